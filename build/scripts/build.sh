@@ -43,6 +43,22 @@ if [ ! -f "$PROJECT_ROOT/build/dist/jenvy.exe" ]; then
     exit 1
 fi
 
+# Step 1.5: Cross-compile the Linux and macOS binaries (no installer: copy to a directory in PATH)
+for TARGET in linux/amd64 linux/arm64 darwin/amd64 darwin/arm64; do
+    TARGET_OS="${TARGET%/*}"
+    TARGET_ARCH="${TARGET#*/}"
+    OUTPUT="build/dist/jenvy-$TARGET_OS-$TARGET_ARCH"
+    echo "► Building $OUTPUT..."
+    if (cd "$PROJECT_ROOT" && CGO_ENABLED=0 GOOS="$TARGET_OS" GOARCH="$TARGET_ARCH" go build \
+        -ldflags "-X main.Version=$VERSION -X main.BuildDate=$BUILD_DATE -X main.GitCommit=$GIT_COMMIT" \
+        -o "$OUTPUT" ./main.go); then
+        echo "✅ $OUTPUT compilato con successo"
+    else
+        echo "❌ Build fallito per $TARGET_OS/$TARGET_ARCH"
+        exit 1
+    fi
+done
+
 # Step 2: Sign jenvy.exe with certificate (optional on Windows)
 SIGNTOOL="/c/Program Files (x86)/Windows Kits/10/bin/x64/signtool.exe"
 if [ -f "$SIGNTOOL" ]; then
@@ -140,6 +156,7 @@ echo ""
 echo "🎉 Build completo! Controlla le cartelle:"
 echo "📦 File generati:"
 echo "   build/dist/jenvy.exe (eseguibile principale)"
+echo "   build/dist/jenvy-{linux,darwin}-{amd64,arm64} (Linux e macOS)"
 echo "   release/jenvy-installer.exe (installer)"
 echo ""
 echo "🚀 Esempi di test:"
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"jenvy/internal/utils"
+)
+
+// ManageAliases gestisce 'jenvy alias': nomi definiti dall'utente per una versione JDK.
+//
+// Gli alias sono salvati in ~/.jenvy/config.json (chiave "aliases") e vengono
+// accettati al posto della versione da 'use', 'remove', 'local', 'which' e
+// 'install' (vedi versionArgument). Un alias verso una major (es. "default" → "21")
+// resta valido anche dopo 'jenvy update', perché indica sempre l'ultima patch
+// installata; un alias verso una patch (es. "proj-x" → "17.0.9") la fissa.
+//
+// Modalità:
+//   - **alias**: Elenca gli alias con la versione associata e lo stato di installazione
+//   - **alias <name>**: Stampa la versione associata (per script)
+//   - **alias <name> <version>**: Crea o sostituisce l'alias
+//   - **alias <name> --delete**: Elimina l'alias
+//
+// Esempio di utilizzo:
+//
+//	jenvy alias default 21
+//	jenvy alias proj-x 17.0.9
+//	jenvy use proj-x
+func ManageAliases() error {
+	positional, flags := utils.SplitArgs(os.Args[2:])
+	remove := false
+	for _, arg := range flags {
+		if arg != "--delete" {
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Unknown option for alias: %s", arg))
+		}
+		remove = true
+	}
+	if len(positional) > 2 || (remove && len(positional) != 1) {
+		printAliasUsage()
+		return utils.ExitWith(utils.ExitGeneric, nil)
+	}
+
+	cfg, err := utils.LoadConfigOrDefault()
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Configuration file parsing error: %v", err))
+	}
+
+	if len(positional) == 0 || positional[0] == "list" || positional[0] == "ls" {
+		showAliases(cfg)
+		return nil
+	}
+
+	name := positional[0]
+	if len(positional) == 1 && !remove {
+		version, ok := cfg.Aliases[name]
+		if !ok {
+			return utils.Fail(utils.ExitNotFound, fmt.Sprintf("Alias not found: %s", name))
+		}
+		fmt.Println(version)
+		return nil
+	}
+
+	if remove {
+		if _, ok := cfg.Aliases[name]; !ok {
+			return utils.Fail(utils.ExitNotFound, fmt.Sprintf("Alias not found: %s", name))
+		}
+		delete(cfg.Aliases, name)
+		if err := utils.SaveConfig(cfg); err != nil {
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to save configuration: %v", err))
+		}
+		utils.PrintSuccess(fmt.Sprintf("Alias %s deleted", name))
+		return nil
+	}
+
+	if err := utils.ValidateAliasName(name); err != nil {
+		return utils.Fail(utils.ExitGeneric, err.Error())
+	}
+	// Il bersaglio è una versione o il nome di un'installazione, mai un altro alias
+	versionsDir, _ := utils.GetJenvyVersionsDirectory()
+	version := positional[1]
+	if !isInstallDirName(version, versionsDir) {
+		if version, err = utils.NormalizeVersionInput(version); err != nil {
+			return utils.Fail(utils.ExitGeneric, err.Error())
+		}
+	}
+
+	if cfg.Aliases == nil {
+		cfg.Aliases = make(map[string]string)
+	}
+	cfg.Aliases[name] = version
+	if err := utils.SaveConfig(cfg); err != nil {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to save configuration: %v", err))
+	}
+	utils.PrintSuccess(fmt.Sprintf("Alias %s → %s", name, version))
+	if !isJDKInstalled(version) {
+		utils.PrintWarning(fmt.Sprintf("JDK %s is not installed: run 'jenvy install %s'", version, version))
+	}
+	return nil
+}
+
+// showAliases elenca gli alias configurati, segnalando quelli senza un JDK installato.
+func showAliases(cfg *utils.Config) {
+	names := utils.SortedAliasNames(cfg)
+	if len(names) == 0 {
+		utils.PrintInfo("No aliases defined")
+		utils.PrintInfo("Create one with 'jenvy alias <name> <version>' (e.g. 'jenvy alias default 21')")
+		return
+	}
+	utils.PrintInfo("Aliases:")
+	for _, name := range names {
+		version := cfg.Aliases[name]
+		status := utils.ColorText("installed", utils.Green)
+		if !isJDKInstalled(version) {
+			status = utils.ColorText("not installed", utils.Yellow)
+		}
+		fmt.Printf("  %s → %-20s %s\n", utils.ColorText(fmt.Sprintf("%-16s", name), utils.Cyan), version, status)
+	}
+}
+
+// printAliasUsage stampa la sintassi del comando 'jenvy alias'.
+func printAliasUsage() {
+	utils.PrintUsage("Usage: jenvy alias [<name> [<version>|--delete]]")
+	utils.PrintInfo("Examples:")
+	fmt.Println("  jenvy alias default 21       # 'jenvy use default' activates the latest JDK 21")
+	fmt.Println("  jenvy alias proj-x 17.0.9    # Pin an exact patch")
+	fmt.Println("  jenvy alias proj-x --delete")
+}
+
+// CompleteAliases stampa i nomi degli alias configurati, uno per riga.
+//
+// Comando nascosto '__complete-aliases' usato dagli script di completamento
+// per suggerire gli alias accanto alle versioni installate.
+func CompleteAliases() {
+	cfg, err := utils.LoadConfigOrDefault()
+	if err != nil {
+		return
+	}
+	for _, name := range utils.SortedAliasNames(cfg) {
+		fmt.Println(name)
+	}
+}
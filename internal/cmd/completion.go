@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"jenvy/internal/utils"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 )
@@ -17,7 +18,7 @@ import (
 // Caratteristiche del completamento generato:
 // - Completamento comandi principali (remote-list, download, use, remove, etc.)
 // - Completamento alias abbreviati (rl, dl, u, rm, etc.)
-// - Completamento provider (adoptium, azul, liberica, private)
+// - Completamento provider (adoptium, azul, liberica, graalvm, private)
 // - Completamento flag (--provider, --all, --latest, etc.)
 // - Completamento versioni JDK installate per comandi 'use' e 'remove'
 // - Completamento intelligente del flag --all per 'remove'
@@ -61,16 +62,17 @@ _jenvy_completion() {
     words=("${COMP_WORDS[@]}")
     cword=$COMP_CWORD
 
-    local commands="remote-list rl download dl extract ex list l use u remove rm init fix-path fp configure-private cp config-show cs config-reset cr completion help --help -h"
-    local providers="adoptium azul liberica private"
-    local flags="--provider --all --latest --major-only --jdk --lts-only --output"
+    local commands="remote-list rl download dl install extract ex list l use u alias default local shell-init current which update upgrade remove rm verify init fix-path fp diagnose-path doctor resolve logs providers self-test uninstall configure-private cp config-show cs config-reset cr config completion help --help -h"
+    local providers="adoptium azul liberica graalvm private"
+    local flags="--provider --all --latest --major-only --jdk --lts-only --since --ea --type --javafx --count --fields --raw --output --refresh"
     
     # Special handling for use and remove commands to complete with installed JDK versions
-    if [[ "$prev" == "use" || "$prev" == "u" || "$prev" == "remove" || "$prev" == "rm" ]]; then
+    if [[ "$prev" == "use" || "$prev" == "u" || "$prev" == "remove" || "$prev" == "rm" || "$prev" == "local" || "$prev" == "default" ]]; then
         # Try to get installed JDK versions using jenvy list
         if command -v jenvy >/dev/null 2>&1; then
             local installed_versions=$(jenvy list 2>/dev/null | grep -E "^\s*JDK-[0-9]" | sed 's/.*JDK-\([^[:space:]]*\).*/\1/' | head -20)
-            if [[ -n "$installed_versions" ]]; then
+            installed_versions="$installed_versions $(jenvy __complete-aliases 2>/dev/null)"
+            if [[ -n "${installed_versions// /}" ]]; then
                 COMPREPLY=($(compgen -W "$installed_versions" -- "$cur"))
                 return 0
             fi
@@ -88,6 +90,8 @@ _jenvy_completion() {
             return 0
             ;;
         --provider)
+            local configured=$(jenvy __complete-providers 2>/dev/null)
+            [[ -n "$configured" ]] && providers="$configured"
             COMPREPLY=($(compgen -W "$providers" -- "$cur"))
             return 0
             ;;
@@ -108,7 +112,12 @@ _jenvy_completion() {
             fi
             return 0
             ;;
-        download|dl)
+        alias)
+            # Complete with configured aliases
+            COMPREPLY=($(compgen -W "$(jenvy __complete-aliases 2>/dev/null)" -- "$cur"))
+            return 0
+            ;;
+        download|dl|install)
             # Complete with common JDK versions
             local versions="8 11 17 21 23 24"
             COMPREPLY=($(compgen -W "$versions" -- "$cur"))
@@ -144,7 +153,16 @@ _jenvy_completion() {
             ;;
         download|dl)
             if [[ "$cur" == --* ]]; then
-                COMPREPLY=($(compgen -W "--provider --output" -- "$cur"))
+                COMPREPLY=($(compgen -W "--provider --output --no-flatten --archive-name --yes --keep-archive --delete-archive --all-lts --manifest --checksum= --checksum-algorithm= --resolve-only --verbose --progress= --extract-to --arch= --os= --resume --stream --ea --type= --javafx" -- "$cur"))
+            else
+                local versions="8 11 17 21 23 24"
+                COMPREPLY=($(compgen -W "$versions" -- "$cur"))
+            fi
+            return 0
+            ;;
+        install)
+            if [[ "$cur" == --* ]]; then
+                COMPREPLY=($(compgen -W "--provider= --use --global --user --local --yes" -- "$cur"))
             else
                 local versions="8 11 17 21 23 24"
                 COMPREPLY=($(compgen -W "$versions" -- "$cur"))
@@ -152,6 +170,10 @@ _jenvy_completion() {
             return 0
             ;;
         extract|ex)
+            if [[ "$cur" == --* ]]; then
+                COMPREPLY=($(compgen -W "--all --yes --jobs= --no-flatten" -- "$cur"))
+                return 0
+            fi
             # Complete with available archive versions from ~/.jenvy/versions
             if command -v jenvy >/dev/null 2>&1; then
                 local available_archives=$(jenvy extract 2>/dev/null | grep -E "^\s*JDK-[0-9]" | sed 's/.*JDK-\([^[:space:]]*\).*/\1/' | head -20)
@@ -167,7 +189,43 @@ _jenvy_completion() {
             COMPREPLY=($(compgen -W "$common_versions" -- "$cur"))
             return 0
             ;;
-        list|l|use|u|remove|rm|init|fix-path|fp|configure-private|cp|config-show|cs|config-reset|cr|completion|help|--help|-h)
+        list|l)
+            COMPREPLY=($(compgen -W "--provider=adoptium --provider=azul --provider=liberica --provider=graalvm --provider=private --provider=unknown --tree --duplicates --check-updates --output=json --output=csv" -- "$cur"))
+            return 0
+            ;;
+        completion)
+            if [[ "$cur" == --* ]]; then
+                COMPREPLY=($(compgen -W "--all --shell=bash --shell=powershell --shell=cmd" -- "$cur"))
+            else
+                COMPREPLY=($(compgen -W "install uninstall status bash powershell cmd" -- "$cur"))
+            fi
+            return 0
+            ;;
+        update|upgrade)
+            COMPREPLY=($(compgen -W "--all --prune --jobs= --yes --no-reactivate --keep-archive --delete-archive" -- "$cur"))
+            return 0
+            ;;
+        which)
+            COMPREPLY=($(compgen -W "--all --with-version" -- "$cur"))
+            return 0
+            ;;
+        logs)
+            COMPREPLY=($(compgen -W "--lines= --clear" -- "$cur"))
+            return 0
+            ;;
+        doctor)
+            COMPREPLY=($(compgen -W "--offline" -- "$cur"))
+            return 0
+            ;;
+        local)
+            COMPREPLY=($(compgen -W "--unset" -- "$cur"))
+            return 0
+            ;;
+        shell-init)
+            COMPREPLY=($(compgen -W "bash zsh powershell" -- "$cur"))
+            return 0
+            ;;
+        use|u|current|remove|rm|verify|init|fix-path|fp|diagnose-path|providers|uninstall|configure-private|cp|config-show|cs|config-reset|cr|config|help|--help|-h)
             # These commands don't take additional arguments or have specific handling above
             return 0
             ;;
@@ -294,7 +352,7 @@ func GenerateCmdCompletion() string {
 //   - use/u: Versioni JDK installate (da 'jenvy list')
 //   - remove/rm: Versioni installate + flag --all
 //   - download/dl: Versioni comuni (8, 11, 17, 21, 23, 24)
-//   - --provider: Lista provider (adoptium, azul, liberica, private)
+//   - --provider: Lista provider (adoptium, azul, liberica, graalvm, private)
 //   - configure-private: Suggerimenti URL comuni
 //
 // Ottimizzazioni implementate:
@@ -329,16 +387,17 @@ _jenvy_completion() {
     words=("${COMP_WORDS[@]}")
     cword=$COMP_CWORD
 
-    local commands="remote-list rl download dl list l use u remove rm init fix-path fp configure-private cp config-show cs config-reset cr completion help --help -h"
-    local providers="adoptium azul liberica private"
-    local flags="--provider --all --latest --major-only --jdk --lts-only --output"
+    local commands="remote-list rl download dl install list l use u alias default local shell-init current which update upgrade remove rm verify init fix-path fp diagnose-path doctor resolve logs providers self-test uninstall configure-private cp config-show cs config-reset cr config completion help --help -h"
+    local providers="adoptium azul liberica graalvm private"
+    local flags="--provider --all --latest --major-only --jdk --lts-only --since --ea --type --javafx --count --fields --raw --output --refresh"
     
     # Special handling for use and remove commands to complete with installed JDK versions
-    if [[ "$prev" == "use" || "$prev" == "u" || "$prev" == "remove" || "$prev" == "rm" ]]; then
+    if [[ "$prev" == "use" || "$prev" == "u" || "$prev" == "remove" || "$prev" == "rm" || "$prev" == "local" || "$prev" == "default" ]]; then
         # Try to get installed JDK versions using jenvy list
         if command -v jenvy >/dev/null 2>&1; then
             local installed_versions=$(jenvy list 2>/dev/null | grep -E "^\s*JDK-[0-9]" | sed 's/.*JDK-\([^[:space:]]*\).*/\1/' | head -20)
-            if [[ -n "$installed_versions" ]]; then
+            installed_versions="$installed_versions $(jenvy __complete-aliases 2>/dev/null)"
+            if [[ -n "${installed_versions// /}" ]]; then
                 COMPREPLY=($(compgen -W "$installed_versions" -- "$cur"))
                 return 0
             fi
@@ -356,6 +415,8 @@ _jenvy_completion() {
             return 0
             ;;
         --provider)
+            local configured=$(jenvy __complete-providers 2>/dev/null)
+            [[ -n "$configured" ]] && providers="$configured"
             COMPREPLY=($(compgen -W "$providers" -- "$cur"))
             return 0
             ;;
@@ -374,7 +435,12 @@ _jenvy_completion() {
             fi
             return 0
             ;;
-        download|dl)
+        alias)
+            # Complete with configured aliases
+            COMPREPLY=($(compgen -W "$(jenvy __complete-aliases 2>/dev/null)" -- "$cur"))
+            return 0
+            ;;
+        download|dl|install)
             local versions="8 11 17 21 23 24"
             COMPREPLY=($(compgen -W "$versions" -- "$cur"))
             return 0
@@ -390,14 +456,59 @@ _jenvy_completion() {
             ;;
         download|dl)
             if [[ "$cur" == --* ]]; then
-                COMPREPLY=($(compgen -W "--provider --output" -- "$cur"))
+                COMPREPLY=($(compgen -W "--provider --output --no-flatten --archive-name --yes --keep-archive --delete-archive --all-lts --manifest --checksum= --checksum-algorithm= --resolve-only --verbose --progress= --extract-to --arch= --os= --resume --stream --ea --type= --javafx" -- "$cur"))
             else
                 local versions="8 11 17 21 23 24"
                 COMPREPLY=($(compgen -W "$versions" -- "$cur"))
             fi
             return 0
             ;;
-        list|l|use|u|remove|rm|init|fix-path|fp|configure-private|cp|config-show|cs|config-reset|cr|completion|help|--help|-h)
+        install)
+            if [[ "$cur" == --* ]]; then
+                COMPREPLY=($(compgen -W "--provider= --use --global --user --local --yes" -- "$cur"))
+            else
+                local versions="8 11 17 21 23 24"
+                COMPREPLY=($(compgen -W "$versions" -- "$cur"))
+            fi
+            return 0
+            ;;
+        list|l)
+            COMPREPLY=($(compgen -W "--provider=adoptium --provider=azul --provider=liberica --provider=graalvm --provider=private --provider=unknown --tree --duplicates --check-updates --output=json --output=csv" -- "$cur"))
+            return 0
+            ;;
+        completion)
+            if [[ "$cur" == --* ]]; then
+                COMPREPLY=($(compgen -W "--all --shell=bash --shell=powershell --shell=cmd" -- "$cur"))
+            else
+                COMPREPLY=($(compgen -W "install uninstall status bash powershell cmd" -- "$cur"))
+            fi
+            return 0
+            ;;
+        update|upgrade)
+            COMPREPLY=($(compgen -W "--all --prune --jobs= --yes --no-reactivate --keep-archive --delete-archive" -- "$cur"))
+            return 0
+            ;;
+        which)
+            COMPREPLY=($(compgen -W "--all --with-version" -- "$cur"))
+            return 0
+            ;;
+        logs)
+            COMPREPLY=($(compgen -W "--lines= --clear" -- "$cur"))
+            return 0
+            ;;
+        doctor)
+            COMPREPLY=($(compgen -W "--offline" -- "$cur"))
+            return 0
+            ;;
+        local)
+            COMPREPLY=($(compgen -W "--unset" -- "$cur"))
+            return 0
+            ;;
+        shell-init)
+            COMPREPLY=($(compgen -W "bash zsh powershell" -- "$cur"))
+            return 0
+            ;;
+        use|u|current|remove|rm|verify|init|fix-path|fp|diagnose-path|providers|uninstall|configure-private|cp|config-show|cs|config-reset|cr|config|help|--help|-h)
             return 0
             ;;
         *)
@@ -462,9 +573,10 @@ func generatePowerShellScript() string {
 Register-ArgumentCompleter -Native -CommandName jenvy -ScriptBlock {
     param($commandName, $wordToComplete, $cursorPosition)
     
-    $commands = @('remote-list', 'rl', 'download', 'dl', 'extract', 'ex', 'list', 'l', 'use', 'u', 'remove', 'rm', 'init', 'fix-path', 'fp', 'configure-private', 'cp', 'config-show', 'cs', 'config-reset', 'cr', 'completion', 'help', '--help', '-h')
-    $providers = @('adoptium', 'azul', 'liberica', 'private')
-    $flags = @('--provider', '--all', '--latest', '--major-only', '--jdk', '--lts-only', '--output')
+    $commands = @('remote-list', 'rl', 'download', 'dl', 'install', 'extract', 'ex', 'list', 'l', 'use', 'u', 'alias', 'default', 'local', 'shell-init', 'current', 'which', 'update', 'upgrade', 'remove', 'rm', 'verify', 'init', 'fix-path', 'fp', 'diagnose-path', 'doctor', 'resolve', 'logs', 'providers', 'self-test', 'uninstall', 'configure-private', 'cp', 'config-show', 'cs', 'config-reset', 'cr', 'config', 'completion', 'help', '--help', '-h')
+    $providers = @(& jenvy __complete-providers 2>$null)
+    if (-not $providers) { $providers = @('adoptium', 'azul', 'liberica', 'graalvm', 'private') }
+    $flags = @('--provider', '--all', '--latest', '--major-only', '--jdk', '--lts-only', '--since', '--ea', '--type', '--javafx', '--count', '--raw', '--output', '--refresh')
     $versions = @('8', '11', '17', '21', '23', '24')
     
     $words = $wordToComplete.Split(' ')
@@ -480,10 +592,11 @@ Register-ArgumentCompleter -Native -CommandName jenvy -ScriptBlock {
         $providers | Where-Object { $_ -like "$lastWord*" }
     }
     # Complete versions for use and remove commands or after --jdk
-    elseif ($secondLastWord -eq 'use' -or $secondLastWord -eq 'u' -or $secondLastWord -eq '--jdk') {
+    elseif ($secondLastWord -eq 'use' -or $secondLastWord -eq 'u' -or $secondLastWord -eq 'local' -or $secondLastWord -eq 'default' -or $secondLastWord -eq '--jdk') {
         # Try to get installed versions first
         try {
-            $installedVersions = & jenvy list 2>$null | Select-String "JDK-(\d+)" | ForEach-Object { $_.Matches[0].Groups[1].Value }
+            $installedVersions = @(& jenvy list 2>$null | Select-String "JDK-(\d+)" | ForEach-Object { $_.Matches[0].Groups[1].Value })
+            $installedVersions += @(& jenvy __complete-aliases 2>$null)
             if ($installedVersions) {
                 $installedVersions | Where-Object { $_ -like "$lastWord*" }
             } else {
@@ -500,7 +613,8 @@ Register-ArgumentCompleter -Native -CommandName jenvy -ScriptBlock {
         } else {
             # Try to get installed versions first
             try {
-                $installedVersions = & jenvy list 2>$null | Select-String "JDK-(\d+)" | ForEach-Object { $_.Matches[0].Groups[1].Value }
+                $installedVersions = @(& jenvy list 2>$null | Select-String "JDK-(\d+)" | ForEach-Object { $_.Matches[0].Groups[1].Value })
+                $installedVersions += @(& jenvy __complete-aliases 2>$null)
                 if ($installedVersions) {
                     ($installedVersions + @('--all')) | Where-Object { $_ -like "$lastWord*" }
                 } else {
@@ -511,6 +625,10 @@ Register-ArgumentCompleter -Native -CommandName jenvy -ScriptBlock {
             }
         }
     }
+    # Complete alias names for alias command
+    elseif ($secondLastWord -eq 'alias') {
+        @(& jenvy __complete-aliases 2>$null) | Where-Object { $_ -like "$lastWord*" }
+    }
     # Complete archive files for extract command
     elseif ($secondLastWord -eq 'extract' -or $secondLastWord -eq 'ex') {
         Get-ChildItem -Path "." -Include "*.zip", "*.tar.gz" -Name | Where-Object { $_ -like "$lastWord*" }
@@ -539,7 +657,7 @@ Register-ArgumentCompleter -Native -CommandName jenvy -ScriptBlock {
 // Contenuto informativo incluso:
 //   - Tutti i comandi principali con alias abbreviati
 //   - Sintassi completa per ogni comando
-//   - Lista provider supportati (adoptium, azul, liberica, private)
+//   - Lista provider supportati (adoptium, azul, liberica, graalvm, private)
 //   - Versioni JDK comuni (8, 11, 17, 21, 23, 24)
 //   - Flag speciali come --all per remove
 //   - Istruzioni per alias DOS (doskey)
@@ -578,20 +696,38 @@ if "%1"=="jenvy" (
     echo Available commands:
     echo   remote-list ^(rl^)     - List available JDK versions from providers
     echo   download ^(dl^)        - Download and install a JDK version
+    echo   install ^<version^> [--use] - Download, extract and optionally activate a JDK
     echo   extract ^(ex^)         - Extract JDK archive to versions directory
     echo   list ^(l^)             - List installed JDK versions
+    echo   local [^<version^>]     - Pin the project JDK in .jenvy-version
+    echo   alias ^<name^> ^<version^> - Name a version for use, remove and local
+    echo   default [^<version^>]   - Show or set the default JDK
+    echo   shell-init bash^|zsh^|powershell - Print a hook that switches JDK on cd
+    echo   current               - Show the active JDK and its scope
+    echo   which ^<version^> ^| --all - Print java launcher paths of installed JDKs
+    echo   update ^<major^>^|--all - Upgrade installed JDKs to the latest patch (--prune removes old ones)
     echo   use ^(u^)              - Set JAVA_HOME system-wide
     echo   remove ^(rm^) ^<version^> - Remove installed JDK version
     echo   remove ^(rm^) --all    - Remove ALL JDK installations
+    echo   verify --all          - Check installed JDKs for modified files
     echo   init                  - Initialize environment and completion
     echo   fix-path ^(fp^)        - Add JDK to PATH
+    echo   diagnose-path         - Show which java on PATH is actually used
+    echo   doctor [--offline]    - Check JAVA_HOME, PATH, registry, installs and providers
+    echo   resolve [--json]      - Explain which JDK applies and what java really runs
+    echo   logs [--clear]        - Show the current diagnostic log or delete all logs
+    echo   providers [--json]    - List built-in and configured providers
+    echo   providers status      - Check provider API reachability
+    echo   self-test             - Download, extract and run a JRE in a temp directory
+    echo   uninstall             - Remove Jenvy footprint from this system
     echo   configure-private ^(cp^) - Configure private repository
     echo   config-show ^(cs^)     - Show current configuration
     echo   config-reset ^(cr^)    - Reset configuration
+    echo   config                - Manage Jenvy settings ^(list/get/set/unset^)
     echo   completion            - Generate completion scripts
     echo   help                  - Show this help
     echo.
-    echo Providers: adoptium, azul, liberica, private
+    echo Providers: adoptium, azul, liberica, graalvm, private
     echo Common versions: 8, 11, 17, 21, 23, 24
 )
 `
@@ -645,51 +781,185 @@ if "%1"=="jenvy" (
 //
 // Restituisce:
 //
-//	Nessuno (void) - Stampa risultati su stdout tramite utils.Print*
+//	error - nil se tutte le shell sono state aggiornate; i risultati sono stampati su stdout tramite utils.Print*
 //
 // Side effects:
 //   - Modifica file di configurazione shell (~/.bashrc, $PROFILE)
 //   - Crea file di aiuto per CMD (~/.jenvy_cmd_help.bat)
 //   - Stampa informazioni di stato e istruzioni utente
-func InstallCompletionForAllShells() {
+func InstallCompletionForAllShells() error {
 	utils.PrintInfo("Installing completion scripts for all available shells...")
+	return installCompletionForShells(completionShells)
+}
 
-	var installed []string
-	var errors []string
+// completionShell descrive una shell per cui jenvy sa installare il completamento.
+type completionShell struct {
+	Key     string                 // Valore accettato da --shell=
+	Name    string                 // Nome mostrato all'utente
+	Install func() (string, error) // Installa o aggiorna il completamento
+	Detect  func() bool            // true se la shell sembra in uso su questa macchina
+}
 
-	// Installa per Bash
-	if err := installBashCompletion(); err != nil {
-		errors = append(errors, fmt.Sprintf("Bash: %v", err))
-	} else {
-		installed = append(installed, "Bash")
+// completionShells elenca le shell supportate da 'jenvy completion install'.
+var completionShells = []completionShell{
+	{"bash", "Bash", installBashCompletion, func() bool {
+		if homeDir, err := utils.UserHomeDir(); err == nil {
+			if _, err := os.Stat(filepath.Join(homeDir, ".bashrc")); err == nil {
+				return true
+			}
+		}
+		return commandAvailable("bash")
+	}},
+	{"powershell", "PowerShell", installPowerShellCompletion, func() bool {
+		return commandAvailable("powershell", "pwsh")
+	}},
+	{"cmd", "CMD", installCmdCompletion, func() bool { // suggerimenti di base
+		return commandAvailable("cmd")
+	}},
+}
+
+// completionShellKey converte una shell rilevata (utils.DetectParentShell) nel valore di --shell=.
+//
+// PowerShell 5 e 7 condividono lo stesso completamento; le shell senza supporto restituiscono "".
+func completionShellKey(shell string) string {
+	switch shell {
+	case "bash", "powershell", "cmd":
+		return shell
+	case "pwsh":
+		return "powershell"
+	default:
+		return ""
 	}
+}
 
-	// Installa per PowerShell
-	if err := installPowerShellCompletion(); err != nil {
-		errors = append(errors, fmt.Sprintf("PowerShell: %v", err))
-	} else {
-		installed = append(installed, "PowerShell")
+// SuggestCompletionVariant indica su stderr lo script adatto quando 'jenvy completion'
+// (che stampa lo script Bash) viene lanciato da PowerShell o CMD.
+//
+// Il suggerimento va su stderr per non finire in 'jenvy completion >> ~/.bashrc'.
+func SuggestCompletionVariant() {
+	if key := completionShellKey(utils.DetectParentShell()); key != "" && key != "bash" {
+		fmt.Fprintf(os.Stderr, "[INFO] Detected %s: use 'jenvy completion %s' or 'jenvy completion install --shell=%s'\n", key, key, key)
+	}
+}
+
+// commandAvailable indica se almeno uno dei comandi è raggiungibile dal PATH.
+func commandAvailable(names ...string) bool {
+	for _, name := range names {
+		if _, err := exec.LookPath(name); err == nil {
+			return true
+		}
 	}
+	return false
+}
 
-	// Installa per CMD (suggerimenti di base)
-	if err := installCmdCompletion(); err != nil {
-		errors = append(errors, fmt.Sprintf("CMD: %v", err))
+// InstallCompletion gestisce 'jenvy completion install' con la scelta delle shell.
+//
+// Modalità:
+//   - **--shell=<nome>**: Installa solo per la shell indicata (bash, powershell, cmd),
+//     senza toccare i profili delle altre
+//   - **--all**: Installa per tutte le shell supportate (comportamento storico,
+//     anche con l'alias --install-all)
+//   - **Nessun selettore**: Mostra le shell rilevate e chiede conferma prima di modificarne i profili
+//
+// Esempio di utilizzo:
+//
+//	jenvy completion install --shell=powershell  # Solo il profilo PowerShell
+//	jenvy completion install --all               # Bash, PowerShell e CMD
+func InstallCompletion() error {
+	all := false
+	shellName := ""
+	positional, flags := utils.SplitArgs(os.Args[2:])
+	if len(positional) > 1 {
+		utils.PrintError(fmt.Sprintf("Unexpected argument: %s", positional[1]))
+		utils.PrintUsage("Usage: jenvy completion install [--shell=bash|powershell|cmd | --all]")
+		return utils.ExitWith(utils.ExitGeneric, fmt.Errorf("unexpected argument: %s", positional[1]))
+	}
+	for _, arg := range flags {
+		if arg == "--all" || arg == "--install-all" {
+			all = true
+		} else if strings.HasPrefix(arg, "--shell=") {
+			shellName = strings.ToLower(strings.TrimPrefix(arg, "--shell="))
+		} else {
+			utils.PrintError(fmt.Sprintf("Unknown option: %s", arg))
+			utils.PrintUsage("Usage: jenvy completion install [--shell=bash|powershell|cmd | --all]")
+			return utils.ExitWith(utils.ExitGeneric, fmt.Errorf("unknown option: %s", arg))
+		}
+	}
+
+	if shellName != "" {
+		for _, shell := range completionShells {
+			if shell.Key == shellName {
+				return installCompletionForShells([]completionShell{shell})
+			}
+		}
+		utils.PrintError(fmt.Sprintf("Completion for '%s' is not supported", shellName))
+		utils.PrintInfo("Supported shells: bash, powershell, cmd")
+		return utils.ExitWith(utils.ExitNotFound, fmt.Errorf("unsupported shell: %s", shellName))
+	}
+
+	if all {
+		return InstallCompletionForAllShells()
+	}
+
+	var detected []completionShell
+	var names []string
+	for _, shell := range completionShells {
+		if shell.Detect() {
+			detected = append(detected, shell)
+			names = append(names, shell.Name)
+		}
+	}
+	if len(detected) == 0 {
+		utils.PrintWarning("No supported shell detected")
+		utils.PrintInfo("Use --shell=bash|powershell|cmd to install for a specific shell")
+		return utils.ExitWith(utils.ExitNotFound, fmt.Errorf("no supported shell detected"))
+	}
+
+	utils.PrintInfo(fmt.Sprintf("Detected shells: %s", strings.Join(names, ", ")))
+	if current := completionShellKey(utils.DetectParentShell()); current != "" {
+		utils.PrintInfo(fmt.Sprintf("Current shell: %s - use --shell=%s to install only for it, or --all for every supported shell", current, current))
 	} else {
-		installed = append(installed, "CMD")
+		utils.PrintInfo("Use --shell=<name> to install for a single shell, or --all for every supported shell")
 	}
+	if !askConfirmation("Install completion for the detected shells? (y/N): ") {
+		utils.PrintInfo("Installation cancelled")
+		return utils.ExitWith(utils.ExitGeneric, fmt.Errorf("installation cancelled by user"))
+	}
+	return installCompletionForShells(detected)
+}
+
+// installCompletionForShells installa il completamento per le shell indicate e riepiloga l'esito.
+//
+// Un errore su una shell non interrompe le altre, ma viene riportato nell'errore restituito.
+func installCompletionForShells(shells []completionShell) error {
+	var changed []string
+	var errors []string
 
-	// Mostra risultati
-	if len(installed) > 0 {
-		utils.PrintSuccess(fmt.Sprintf("Completion installed for: %s", strings.Join(installed, ", ")))
+	for _, shell := range shells {
+		status, err := shell.Install()
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", shell.Name, err))
+			continue
+		}
+		if status == utils.CompletionCurrent {
+			utils.PrintInfo(fmt.Sprintf("%s: completion %s", shell.Name, status))
+		} else {
+			utils.PrintSuccess(fmt.Sprintf("%s: completion %s", shell.Name, status))
+			changed = append(changed, shell.Name)
+		}
 	}
 
 	if len(errors) > 0 {
 		utils.PrintWarning(fmt.Sprintf("Failed to install completion for: %s", strings.Join(errors, "; ")))
 	}
 
-	if len(installed) > 0 {
+	if len(changed) > 0 {
 		utils.PrintInfo("Restart your terminal or source your shell configuration to enable completions")
 	}
+	if len(errors) > 0 {
+		return utils.ExitWith(utils.ExitGeneric, fmt.Errorf("completion failed for %d shell(s)", len(errors)))
+	}
+	return nil
 }
 
 // installBashCompletion installa il completamento Bash nel profilo utente Windows.
@@ -700,20 +970,20 @@ func InstallCompletionForAllShells() {
 // WSL e altri ambienti Bash su Windows.
 //
 // Processo di installazione:
-// 1. **Localizzazione home directory**: Usa os.UserHomeDir() per trovare profilo utente
+// 1. **Localizzazione home directory**: Usa utils.UserHomeDir() per trovare profilo utente
 // 2. **Costruzione percorso**: Identifica ~/.bashrc come target di installazione
-// 3. **Controllo duplicati**: Verifica se completamento già installato
-// 4. **Prevenzione duplicazione**: Evita installazioni multiple dello stesso script
-// 5. **Scrittura sicura**: Appende script al file esistente o crea nuovo file
+// 3. **Controllo blocco esistente**: Cerca il blocco delimitato dalle sentinel jenvy
+// 4. **Confronto**: Confronta il blocco installato con lo script corrente
+// 5. **Scrittura sicura**: Aggiunge o sostituisce solo il blocco jenvy
 //
 // Gestione intelligente duplicati:
 //   - Scansione contenuto ~/.bashrc esistente
-//   - Ricerca signature "_jenvy_completion" per rilevare installazione precedente
-//   - Skip installazione se già presente (evita duplicazione)
-//   - Permette aggiornamenti manuali se necessario
+//   - Blocco identico: nessuna modifica ("already current")
+//   - Blocco obsoleto (anche senza sentinel): sostituito ("updated")
+//   - Nessun blocco: aggiunto in coda ("installed")
 //
 // Struttura script installato:
-//   - Header identificativo per future verifiche
+//   - Sentinel "# >>> jenvy completion >>>" / "# <<< jenvy completion <<<"
 //   - Script Bash completo generato da generateBashScript()
 //   - Registrazione finale con comando 'complete'
 //   - Compatibilità con reload automatico profilo
@@ -730,7 +1000,8 @@ func InstallCompletionForAllShells() {
 //
 // Restituisce:
 //
-//	error - nil se installazione riuscita, errore specifico altrimenti
+//	string - Stato: utils.CompletionInstalled, CompletionUpdated o CompletionCurrent
+//	error  - nil se installazione riuscita, errore specifico altrimenti
 //
 // Errori possibili:
 //   - Impossibile determinare directory home utente
@@ -739,37 +1010,21 @@ func InstallCompletionForAllShells() {
 //   - Corruzione file ~/.bashrc esistente
 //
 // Note di sicurezza:
-//   - Usa os.O_APPEND per evitare sovrascrittura contenuto esistente
+//   - Modifica solo il testo compreso tra le sentinel jenvy
 //   - Crea file con permessi 0644 (sicuri per file di configurazione)
-//   - Non modifica mai contenuto esistente, solo aggiunge
-func installBashCompletion() error {
-	homeDir, err := os.UserHomeDir()
+//   - Non riscrive il file se il blocco è già aggiornato
+func installBashCompletion() (string, error) {
+	homeDir, err := utils.UserHomeDir()
 	if err != nil {
-		return fmt.Errorf("getting home directory: %v", err)
+		return "", fmt.Errorf("getting home directory: %v", err)
 	}
 
 	bashrcPath := filepath.Join(homeDir, ".bashrc")
-
-	// Controlla se il completamento è già installato
-	if content, err := os.ReadFile(bashrcPath); err == nil {
-		if strings.Contains(string(content), "_jenvy_completion") {
-			return nil // Già installato
-		}
-	}
-
-	script := "\n# Jenvy completion\n" + generateBashScript()
-
-	file, err := os.OpenFile(bashrcPath, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+	status, err := installCompletionBlock(bashrcPath, generateBashScript(), bashLegacyCompletionEnd)
 	if err != nil {
-		return fmt.Errorf("opening ~/.bashrc: %v", err)
+		return "", fmt.Errorf("updating ~/.bashrc: %v", err)
 	}
-	defer file.Close()
-
-	if _, err := file.WriteString(script); err != nil {
-		return fmt.Errorf("writing to ~/.bashrc: %v", err)
-	}
-
-	return nil
+	return status, nil
 }
 
 // installPowerShellCompletion installa il completamento nel profilo PowerShell Windows.
@@ -793,10 +1048,10 @@ func installBashCompletion() error {
 //
 // Processo di installazione:
 // 1. **Localizzazione profilo**: Trova o crea percorso profilo appropriato
-// 2. **Controllo duplicati**: Verifica signature "Register-ArgumentCompleter -Native -CommandName jenvy"
-// 3. **Prevenzione sovrascrittura**: Evita installazioni multiple
+// 2. **Controllo blocco esistente**: Cerca il blocco jenvy (sentinel o installazioni precedenti)
+// 3. **Aggiornamento**: Sostituisce il blocco se diverso dallo script corrente
 // 4. **Creazione directory**: os.MkdirAll per struttura directory se necessaria
-// 5. **Installazione script**: Appende script PowerShell completo
+// 5. **Installazione script**: Appende script PowerShell completo se assente
 //
 // Compatibilità PowerShell:
 //   - **Windows PowerShell 5.x**: Versione integrata Windows 10/11
@@ -815,7 +1070,8 @@ func installBashCompletion() error {
 //
 // Restituisce:
 //
-//	error - nil se installazione riuscita, errore specifico altrimenti
+//	string - Stato: utils.CompletionInstalled, CompletionUpdated o CompletionCurrent
+//	error  - nil se installazione riuscita, errore specifico altrimenti
 //
 // Side effects:
 //   - Crea directory profilo PowerShell se non esistente
@@ -823,21 +1079,18 @@ func installBashCompletion() error {
 //   - Registra completion handler nativo PowerShell
 //
 // Note di sicurezza:
-//   - Usa os.O_APPEND per preservare configurazioni esistenti
+//   - Modifica solo il blocco jenvy, preservando configurazioni esistenti
 //   - Crea directory con permessi sicuri (0755)
 //   - Non interferisce con altri moduli PowerShell
-func installPowerShellCompletion() error {
+func installPowerShellCompletion() (string, error) {
 	// Trova il percorso del profilo PowerShell
-	homeDir, err := os.UserHomeDir()
+	homeDir, err := utils.UserHomeDir()
 	if err != nil {
-		return fmt.Errorf("getting home directory: %v", err)
+		return "", fmt.Errorf("getting home directory: %v", err)
 	}
 
 	// Percorsi comuni per il profilo PowerShell
-	possiblePaths := []string{
-		filepath.Join(homeDir, "Documents", "PowerShell", "Microsoft.PowerShell_profile.ps1"),
-		filepath.Join(homeDir, "Documents", "WindowsPowerShell", "Microsoft.PowerShell_profile.ps1"),
-	}
+	possiblePaths := powerShellProfilePaths(homeDir)
 
 	var profilePath string
 	for _, path := range possiblePaths {
@@ -853,30 +1106,15 @@ func installPowerShellCompletion() error {
 	if profilePath == "" {
 		profilePath = possiblePaths[0] // Usa il primo percorso come default
 		if err := os.MkdirAll(filepath.Dir(profilePath), 0755); err != nil {
-			return fmt.Errorf("creating PowerShell profile directory: %v", err)
+			return "", fmt.Errorf("creating PowerShell profile directory: %v", err)
 		}
 	}
 
-	// Controlla se il completamento è già installato
-	if content, err := os.ReadFile(profilePath); err == nil {
-		if strings.Contains(string(content), "Register-ArgumentCompleter -Native -CommandName jenvy") {
-			return nil // Già installato
-		}
-	}
-
-	script := "\n# Jenvy completion\n" + generatePowerShellScript()
-
-	file, err := os.OpenFile(profilePath, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+	status, err := installCompletionBlock(profilePath, generatePowerShellScript(), powerShellLegacyCompletionEnd)
 	if err != nil {
-		return fmt.Errorf("opening PowerShell profile: %v", err)
-	}
-	defer file.Close()
-
-	if _, err := file.WriteString(script); err != nil {
-		return fmt.Errorf("writing to PowerShell profile: %v", err)
+		return "", fmt.Errorf("updating PowerShell profile: %v", err)
 	}
-
-	return nil
+	return status, nil
 }
 
 // installCmdCompletion crea uno script di aiuto per Command Prompt Windows.
@@ -927,7 +1165,8 @@ func installPowerShellCompletion() error {
 //
 // Restituisce:
 //
-//	error - nil se creazione script riuscita, errore specifico altrimenti
+//	string - Stato: utils.CompletionInstalled, CompletionUpdated o CompletionCurrent
+//	error  - nil se creazione script riuscita, errore specifico altrimenti
 //
 // Side effects:
 //   - Crea file ~/.jenvy_cmd_help.bat nella home directory
@@ -938,24 +1177,235 @@ func installPowerShellCompletion() error {
 //   - Tutte le versioni Windows con CMD
 //   - Batch scripting tradizionale Windows
 //   - Ambienti enterprise e legacy Windows
-func installCmdCompletion() error {
+func installCmdCompletion() (string, error) {
 	// Per CMD, creiamo un semplice file di aiuto
-	homeDir, err := os.UserHomeDir()
+	homeDir, err := utils.UserHomeDir()
 	if err != nil {
-		return fmt.Errorf("getting home directory: %v", err)
+		return "", fmt.Errorf("getting home directory: %v", err)
 	}
 
 	cmdHelpPath := filepath.Join(homeDir, ".jenvy_cmd_help.bat")
 
 	script := generateCmdScript()
 
+	status := utils.CompletionInstalled
+	if existing, err := os.ReadFile(cmdHelpPath); err == nil {
+		if string(existing) == script {
+			return utils.CompletionCurrent, nil
+		}
+		status = utils.CompletionUpdated
+	}
+
 	if err := os.WriteFile(cmdHelpPath, []byte(script), 0644); err != nil {
-		return fmt.Errorf("writing CMD help file: %v", err)
+		return "", fmt.Errorf("writing CMD help file: %v", err)
 	}
 
 	// Aggiungi suggerimento per l'alias
 	fmt.Println("� [INFO] For CMD completion, add this alias to your environment:")
 	fmt.Printf("   doskey jenvy-help=%s jenvy $*\n", cmdHelpPath)
 
+	return status, nil
+}
+
+// installCompletionBlock installa o aggiorna il blocco di completamento in un profilo shell.
+//
+// Usa utils.UpdateCompletionBlock per confrontare il blocco esistente con lo script
+// corrente: il file viene riscritto solo se il blocco manca o è obsoleto.
+//
+// Restituisce lo stato (installed, updated, already current) o un errore di I/O.
+func installCompletionBlock(path, script, legacyEnd string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	updated, status := utils.UpdateCompletionBlock(string(content), script, legacyEnd)
+	if status == utils.CompletionCurrent {
+		return status, nil
+	}
+	if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+		return "", err
+	}
+	return status, nil
+}
+
+// Marker usati per riconoscere un completamento jenvy già installato nei profili shell
+const (
+	bashCompletionMarker       = "_jenvy_completion"
+	powerShellCompletionMarker = "Register-ArgumentCompleter -Native -CommandName jenvy"
+
+	// Ultima riga dei blocchi installati prima delle sentinel, usata per rimuoverli
+	bashLegacyCompletionEnd       = "complete -F _jenvy_completion jenvy"
+	powerShellLegacyCompletionEnd = "}"
+)
+
+// powerShellProfilePaths restituisce i percorsi del profilo PowerShell utente,
+// nell'ordine PowerShell 7+ e Windows PowerShell 5.1.
+func powerShellProfilePaths(homeDir string) []string {
+	return []string{
+		filepath.Join(homeDir, "Documents", "PowerShell", "Microsoft.PowerShell_profile.ps1"),
+		filepath.Join(homeDir, "Documents", "WindowsPowerShell", "Microsoft.PowerShell_profile.ps1"),
+	}
+}
+
+// ShowCompletionStatus riporta per ogni shell se il completamento jenvy è installato.
+//
+// Il comando 'jenvy completion status' ispeziona gli stessi file modificati da
+// 'jenvy completion install', così da diagnosticare un completamento che "non
+// funziona" senza dover cercare manualmente nei profili:
+//   - **Bash**: ~/.bashrc contiene la funzione _jenvy_completion
+//   - **PowerShell**: $PROFILE (7+ o 5.1) contiene Register-ArgumentCompleter per jenvy
+//   - **CMD**: esiste ~/.jenvy_cmd_help.bat
+//
+// Per ogni profilo installato mostra anche la data dell'ultima modifica: una shell
+// aperta prima di quell'istante non ha ancora caricato il completamento e deve
+// rileggere il profilo (source ~/.bashrc, . $PROFILE) o essere riavviata.
+func ShowCompletionStatus() error {
+	homeDir, err := utils.UserHomeDir()
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Error getting user directory: %v", err))
+	}
+
+	utils.PrintInfo("Shell completion status:")
+
+	bashrcPath := filepath.Join(homeDir, ".bashrc")
+	printCompletionStatus("Bash", bashrcPath, bashCompletionMarker, "source ~/.bashrc")
+
+	// Mostra il primo profilo PowerShell che contiene il completamento, altrimenti il primo esistente
+	psProfiles := powerShellProfilePaths(homeDir)
+	profilePath := psProfiles[0]
+	for i := len(psProfiles) - 1; i >= 0; i-- {
+		if content, err := os.ReadFile(psProfiles[i]); err == nil {
+			profilePath = psProfiles[i]
+			if strings.Contains(string(content), powerShellCompletionMarker) {
+				break
+			}
+		}
+	}
+	printCompletionStatus("PowerShell", profilePath, powerShellCompletionMarker, ". $PROFILE")
+
+	cmdHelpPath := filepath.Join(homeDir, ".jenvy_cmd_help.bat")
+	printCompletionStatus("CMD", cmdHelpPath, "", fmt.Sprintf("doskey jenvy-help=%s jenvy $*", cmdHelpPath))
+
+	fmt.Println()
+	utils.PrintInfo("Use 'jenvy completion install' to install missing completions")
+	return nil
+}
+
+// printCompletionStatus stampa lo stato del completamento per una singola shell.
+//
+// Con marker vuoto è sufficiente l'esistenza del file. reloadHint è il comando
+// da eseguire nelle shell aperte prima dell'ultima modifica del profilo.
+func printCompletionStatus(shell, path, marker, reloadHint string) {
+	label := utils.ColorText(fmt.Sprintf("%-11s", shell), utils.Blue)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		fmt.Printf("  %s %s (%s)\n", label, utils.ColorText("not installed", utils.Yellow), path)
+		return
+	}
+
+	if marker != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Printf("  %s %s (%v)\n", label, utils.ColorText("unknown", utils.Red), err)
+			return
+		}
+		if !strings.Contains(string(content), marker) {
+			fmt.Printf("  %s %s (%s)\n", label, utils.ColorText("not installed", utils.Yellow), path)
+			return
+		}
+	}
+
+	fmt.Printf("  %s %s (%s)\n", label, utils.ColorText("installed", utils.Green), path)
+	fmt.Printf("  %-11s Last modified %s - shells opened earlier must run: %s\n",
+		"", info.ModTime().Format("2006-01-02 15:04:05"), reloadHint)
+}
+
+// removeCompletionBlock rimuove il blocco di completamento jenvy da un profilo shell.
+//
+// Delega a utils.RemoveCompletionBlock, che riconosce sia il blocco delimitato dalle
+// sentinel sia quello delle installazioni precedenti (terminato da legacyEnd).
+// Il resto del profilo viene lasciato invariato.
+//
+// Restituisce:
+//
+//	bool  - true se un blocco è stato trovato e rimosso
+//	error - errore di lettura/scrittura del profilo
+func removeCompletionBlock(path, legacyEnd string) (bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	cleaned, removed := utils.RemoveCompletionBlock(string(content), legacyEnd)
+	if !removed {
+		return false, nil
+	}
+	return true, os.WriteFile(path, []byte(cleaned), 0644)
+}
+
+// uninstallCompletionFiles rimuove il completamento jenvy da tutti i profili shell.
+//
+// Restituisce l'elenco degli elementi rimossi e degli errori, così che sia
+// 'jenvy completion uninstall' sia 'jenvy uninstall' possano riportarli.
+func uninstallCompletionFiles(homeDir string) (removed []string, failed []string) {
+	bashrcPath := filepath.Join(homeDir, ".bashrc")
+	if ok, err := removeCompletionBlock(bashrcPath, bashLegacyCompletionEnd); err != nil {
+		failed = append(failed, fmt.Sprintf("%s: %v", bashrcPath, err))
+	} else if ok {
+		removed = append(removed, "Bash completion from "+bashrcPath)
+	}
+
+	for _, profilePath := range powerShellProfilePaths(homeDir) {
+		if ok, err := removeCompletionBlock(profilePath, powerShellLegacyCompletionEnd); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", profilePath, err))
+		} else if ok {
+			removed = append(removed, "PowerShell completion from "+profilePath)
+		}
+	}
+
+	cmdHelpPath := filepath.Join(homeDir, ".jenvy_cmd_help.bat")
+	if err := os.Remove(cmdHelpPath); err == nil {
+		removed = append(removed, "CMD help file "+cmdHelpPath)
+	} else if !os.IsNotExist(err) {
+		failed = append(failed, fmt.Sprintf("%s: %v", cmdHelpPath, err))
+	}
+
+	return removed, failed
+}
+
+// UninstallCompletionForAllShells rimuove il completamento jenvy da tutti i profili shell.
+//
+// Operazione inversa di InstallCompletionForAllShells: elimina il blocco tra
+// le sentinel "# >>> jenvy completion >>>" / "# <<< jenvy completion <<<" da
+// ~/.bashrc e dai profili PowerShell e cancella ~/.jenvy_cmd_help.bat.
+// Anche i blocchi scritti prima delle sentinel vengono riconosciuti e rimossi.
+func UninstallCompletionForAllShells() error {
+	homeDir, err := utils.UserHomeDir()
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Error getting user directory: %v", err))
+	}
+
+	removed, failed := uninstallCompletionFiles(homeDir)
+	if len(removed) == 0 && len(failed) == 0 {
+		utils.PrintInfo("No jenvy completion found in shell profiles")
+		return nil
+	}
+	for _, item := range removed {
+		utils.PrintSuccess("Removed " + item)
+	}
+	for _, item := range failed {
+		utils.PrintWarning("Could not remove " + item)
+	}
+	if len(removed) > 0 {
+		utils.PrintInfo("Restart your terminal to apply the changes")
+	}
+	if len(failed) > 0 {
+		return utils.ExitWith(utils.ExitGeneric, fmt.Errorf("%d completion file(s) could not be cleaned", len(failed)))
+	}
 	return nil
 }
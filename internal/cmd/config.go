@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"jenvy/internal/utils"
+)
+
+// ManageConfig gestisce il comando 'jenvy config' per leggere e modificare le impostazioni.
+//
+// Questa funzione espone in modo uniforme le chiavi di ~/.jenvy/config.json gestite
+// da utils.ConfigKeys, validando ogni valore prima del salvataggio così che un
+// errore di battitura non produca una configurazione inutilizzabile.
+//
+// Sottocomandi supportati:
+//
+//	jenvy config list                                    # Mostra tutte le chiavi con valore effettivo
+//	jenvy config get naming-scheme                       # Stampa il valore di una chiave
+//	jenvy config set naming-scheme {provider}-jdk-{version}
+//	jenvy config unset naming-scheme                     # Ripristina il default
+//
+// Gestione configurazione:
+//   - **Merge non distruttivo**: Le chiavi sconosciute nel file vengono preservate
+//   - **Validazione**: Ogni chiave definisce la propria funzione di validazione
+//   - **Default**: 'get' e 'list' mostrano il valore effettivo, incluso il default
+//
+// Side effects:
+//   - Crea ~/.jenvy/config.json se non esiste (solo per set/unset)
+//   - Stampa risultato o errori su stdout
+func ManageConfig() error {
+	// Dopo "--" anche un valore che inizia con "-" è un argomento (es. 'config set <chiave> -- -1')
+	args, _ := utils.SplitArgs(os.Args[2:])
+	if len(args) < 1 {
+		printConfigUsage()
+		return utils.ExitWith(utils.ExitGeneric, nil)
+	}
+
+	cfg, err := utils.LoadConfigOrDefault()
+	if err != nil {
+		utils.PrintError(fmt.Sprintf("Configuration file parsing error: %v", err))
+		utils.PrintInfo("Consider using 'jenvy config-reset' to reset configuration")
+		return utils.ExitWith(utils.ExitGeneric, err)
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		utils.PrintInfo("Jenvy configuration:")
+		for _, key := range utils.ConfigKeys() {
+			value := key.Get(cfg)
+			if value == "" {
+				value = utils.ColorText("(empty)", utils.Yellow)
+			} else if key.Secret {
+				value = utils.ColorText("(configured - hidden for security)", utils.Green)
+			} else {
+				value = utils.ColorText(value, utils.Cyan)
+			}
+			fmt.Printf("  %s %s\n", utils.ColorText(fmt.Sprintf("%-18s", key.Name), utils.Blue), value)
+			fmt.Printf("  %-18s %s\n", "", utils.ColorText(key.Description, utils.BrightBlack))
+		}
+
+	case "get":
+		if len(args) < 2 {
+			utils.PrintUsage("Usage: jenvy config get <key>")
+			return utils.ExitWith(utils.ExitGeneric, nil)
+		}
+		value, err := utils.GetConfigValue(cfg, args[1])
+		if err != nil {
+			utils.PrintError(err.Error())
+			utils.PrintInfo("Use 'jenvy config list' to see available keys")
+			return utils.ExitWith(utils.ExitNotFound, err)
+		}
+		fmt.Println(value)
+
+	case "set":
+		if len(args) < 3 {
+			utils.PrintUsage("Usage: jenvy config set <key> <value>")
+			return utils.ExitWith(utils.ExitGeneric, nil)
+		}
+		key, value := args[1], args[2]
+		if err := utils.SetConfigValue(cfg, key, value); err != nil {
+			utils.PrintError(fmt.Sprintf("Invalid value for '%s': %v", key, err))
+			utils.PrintInfo("Use 'jenvy config list' to see available keys")
+			return utils.ExitWith(utils.ExitGeneric, err)
+		}
+		if err := utils.SaveConfig(cfg); err != nil {
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to save configuration: %v", err))
+		}
+		utils.PrintSuccess(fmt.Sprintf("%s updated", key))
+
+	case "unset":
+		if len(args) < 2 {
+			utils.PrintUsage("Usage: jenvy config unset <key>")
+			return utils.ExitWith(utils.ExitGeneric, nil)
+		}
+		key := args[1]
+		if err := utils.SetConfigValue(cfg, key, ""); err != nil {
+			utils.PrintError(err.Error())
+			utils.PrintInfo("Use 'jenvy config list' to see available keys")
+			return utils.ExitWith(utils.ExitGeneric, err)
+		}
+		if err := utils.SaveConfig(cfg); err != nil {
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to save configuration: %v", err))
+		}
+		utils.PrintSuccess(fmt.Sprintf("%s reset to default", key))
+
+	default:
+		utils.PrintError(fmt.Sprintf("Unknown config subcommand: %s", args[0]))
+		printConfigUsage()
+		return utils.ExitWith(utils.ExitGeneric, fmt.Errorf("unknown config subcommand: %s", args[0]))
+	}
+	return nil
+}
+
+// printConfigUsage stampa la sintassi del comando 'jenvy config'.
+func printConfigUsage() {
+	utils.PrintUsage("Usage: jenvy config <list|get|set|unset> [key] [value]")
+	utils.PrintInfo("Examples:")
+	fmt.Println("  jenvy config list")
+	fmt.Println("  jenvy config set naming-scheme {provider}-jdk-{version}")
+	fmt.Println("  jenvy config unset naming-scheme")
+}
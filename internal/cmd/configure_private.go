@@ -1,10 +1,11 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"jenvy/internal/utils"
 )
 
 // ConfigurePrivateRepo configura un repository privato JDK nel sistema Windows.
@@ -31,7 +32,7 @@ import (
 
 // Gestione sicurezza:
 //   - File creato con permessi utente (0755 per directory)
-//   - Token salvato in chiaro (considerare crittografia per versioni future)
+//   - Token salvato in Windows Credential Manager (DPAPI), nel file solo il riferimento
 //   - Accesso limitato al profilo utente Windows corrente
 //   - Nessuna trasmissione non crittografata del token
 //
@@ -48,7 +49,7 @@ import (
 //
 // Side effects:
 //   - Crea directory ~/.jenvy/ se non esistente
-//   - Sovrascrive completamente config.json esistente (backup non automatico)
+//   - Aggiorna endpoint e token in config.json preservando le altre impostazioni
 //   - Stampa messaggi di stato e risultato su stdout
 //
 // Requisiti:
@@ -66,19 +67,15 @@ import (
 //
 //	ConfigurePrivateRepo("https://nexus.company.com/api/jdk", "abc123token")
 //	// Risultato: File config.json creato in C:\Users\username\.jenvy\config.json
-func ConfigurePrivateRepo(endpoint string, token string) {
-	// Ottiene la directory home dell'utente Windows corrente
-	// Necessaria per localizzare la cartella di configurazione ~/.jenvy
-	home, err := os.UserHomeDir()
+func ConfigurePrivateRepo(endpoint string, token string) error {
+	// Localizza la cartella di configurazione Jenvy
+	// Su Windows: C:\Users\username\.jenvy
+	dir, err := utils.JenvyHome()
 	if err != nil {
 		fmt.Println("[ERROR] Unable to determine user directory:", err)
-		return
+		return utils.ExitWith(utils.ExitGeneric, err)
 	}
 
-	// Costruisce il percorso della directory di configurazione Jenvy
-	// Su Windows: C:\Users\username\.jenvy
-	dir := filepath.Join(home, ".jenvy")
-
 	// Crea ricorsivamente la directory di configurazione se non esiste
 	// Permessi 0755: full access per owner, read+execute per altri
 	os.MkdirAll(dir, 0755)
@@ -87,38 +84,23 @@ func ConfigurePrivateRepo(endpoint string, token string) {
 	// Risultato: C:\Users\username\.jenvy\config.json
 	path := filepath.Join(dir, "config.json")
 
-	// Crea la struttura di configurazione come mappa string-string
-	// Contiene endpoint URL e token di autenticazione per repository privato
-	cfg := map[string]string{
-		"private_endpoint": endpoint,
-		"private_token":    token,
-	}
-
-	// Crea nuovo file di configurazione (sovrascrive se esistente)
-	// ATTENZIONE: questa operazione elimina configurazioni precedenti
-	file, err := os.Create(path)
+	// Carica la configurazione esistente per non perdere le altre impostazioni
+	// (naming scheme, ecc.); un file corrotto viene sostituito
+	cfg, err := utils.LoadConfigOrDefault()
 	if err != nil {
-		fmt.Println("[ERROR] Write error:", err)
-		return
+		cfg = &utils.Config{}
 	}
-	// Assicura chiusura file anche in caso di errore nel resto della funzione
-	defer file.Close()
-
-	// Crea encoder JSON configurato per output leggibile
-	enc := json.NewEncoder(file)
-
-	// Configura indentazione per formattazione JSON human-readable
-	// Usa 2 spazi per indentazione (standard comune)
-	enc.SetIndent("", "  ")
+	cfg.PrivateEndpoint = endpoint
+	cfg.PrivateToken = token
 
-	// Codifica la mappa di configurazione in formato JSON e scrive nel file
-	err = enc.Encode(cfg)
-	if err != nil {
-		fmt.Println("[ERROR] JSON encoding error:", err)
-		return
+	// Salva la configurazione aggiornata in formato JSON indentato
+	if err := utils.SaveConfig(cfg); err != nil {
+		fmt.Println("[ERROR] Write error:", err)
+		return utils.ExitWith(utils.ExitGeneric, err)
 	}
 
 	// Conferma successo operazione con messaggio colorato e percorso file
 	fmt.Println("[SUCCESS] Private repository configured successfully!")
 	fmt.Println("📁 File:", path)
+	return nil
 }
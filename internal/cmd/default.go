@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"jenvy/internal/utils"
+)
+
+// DefaultJDK gestisce 'jenvy default': il JDK predefinito della macchina, come il "default" di sdkman.
+//
+// Il predefinito è l'alias "default" (vedi 'jenvy alias'), quindi vale ovunque
+// sia accettata una versione (es. 'jenvy use default'). Impostarlo lo attiva
+// subito come 'jenvy use', salvo --no-use; 'jenvy init' lo attiva su una
+// macchina appena configurata. Con default-follow-updates=true l'installazione
+// di una patch più recente della sua major ripunta JAVA_HOME (vedi followDefaultJDK).
+//
+// Modalità:
+//   - **default**: Mostra il JDK predefinito e se è quello attivo
+//   - **default <version>**: Imposta il predefinito (deve essere installato) e lo attiva
+//   - **default --unset**: Rimuove il predefinito
+//
+// Opzioni:
+//   - **--global, --user**: Scope dell'attivazione, come in 'jenvy use'
+//   - **--no-use**: Imposta il predefinito senza attivarlo
+//
+// Esempio di utilizzo:
+//
+//	jenvy default 21          # Predefinito = ultima patch di JDK 21, attivato
+//	jenvy default 17.0.9 --user
+func DefaultJDK() error {
+	positional, flags := utils.SplitArgs(os.Args[2:])
+	scope := ""
+	activate, unset := true, false
+	for _, arg := range flags {
+		switch {
+		case arg == "--global" || arg == "--user":
+			if scope != "" && scope != arg {
+				return utils.Fail(utils.ExitGeneric, fmt.Sprintf("%s cannot be combined with %s", arg, scope))
+			}
+			scope = arg
+		case arg == "--no-use":
+			activate = false
+		case arg == "--unset":
+			unset = true
+		default:
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Unknown option for default: %s", arg))
+		}
+	}
+	if len(positional) > 1 || (unset && len(positional) > 0) {
+		utils.PrintUsage("Usage: jenvy default [<version> [--global|--user] [--no-use]] | jenvy default --unset")
+		return utils.ExitWith(utils.ExitGeneric, nil)
+	}
+	if scope != "" && !activate {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("%s cannot be combined with --no-use", scope))
+	}
+
+	cfg, err := utils.LoadConfigOrDefault()
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Configuration file parsing error: %v", err))
+	}
+
+	if unset {
+		if _, ok := cfg.Aliases[utils.DefaultAlias]; !ok {
+			utils.PrintInfo("No default JDK is set")
+			return nil
+		}
+		delete(cfg.Aliases, utils.DefaultAlias)
+		if err := utils.SaveConfig(cfg); err != nil {
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to save configuration: %v", err))
+		}
+		utils.PrintSuccess("Default JDK removed (JAVA_HOME is unchanged)")
+		return nil
+	}
+
+	if len(positional) == 0 {
+		return showDefaultJDK(cfg)
+	}
+
+	versionsDir, _ := utils.GetJenvyVersionsDirectory()
+	version, err := versionArgument(positional[0], versionsDir)
+	if err != nil {
+		return err
+	}
+	if !isJDKInstalled(version) {
+		return utils.Fail(utils.ExitNotFound, fmt.Sprintf("JDK %s is not installed: run 'jenvy install %s' first", version, version))
+	}
+
+	if cfg.Aliases == nil {
+		cfg.Aliases = make(map[string]string)
+	}
+	cfg.Aliases[utils.DefaultAlias] = version
+	if err := utils.SaveConfig(cfg); err != nil {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to save configuration: %v", err))
+	}
+	utils.PrintSuccess(fmt.Sprintf("Default JDK set to %s", version))
+
+	if !activate {
+		utils.PrintInfo("Use 'jenvy use default' to activate it")
+		return nil
+	}
+	fmt.Println()
+	return activateDefaultJDK(scope)
+}
+
+// showDefaultJDK mostra il JDK predefinito e se JAVA_HOME punta a una sua installazione.
+func showDefaultJDK(cfg *utils.Config) error {
+	version, ok := cfg.Aliases[utils.DefaultAlias]
+	if !ok {
+		utils.PrintInfo("No default JDK is set")
+		utils.PrintInfo("Set one with 'jenvy default <version>' (e.g. 'jenvy default 21')")
+		return utils.ExitWith(utils.ExitNotFound, nil)
+	}
+	fmt.Println(version)
+
+	paths, _ := utils.FindJDKInstallationPaths(version)
+	_, activeHome, active := activeJavaHome()
+	switch {
+	case len(paths) == 0:
+		utils.PrintWarning(fmt.Sprintf("JDK %s is not installed: run 'jenvy install %s'", version, version))
+	case active && containsPath(paths, activeHome):
+		utils.PrintInfo("The default JDK is active")
+	default:
+		utils.PrintInfo("The default JDK is not active: run 'jenvy use default'")
+	}
+	return nil
+}
+
+// containsPath indica se home è (o è contenuta in) una delle installazioni indicate.
+func containsPath(installs []string, home string) bool {
+	for _, install := range installs {
+		// Con --no-flatten la root del JDK è una sottodirectory dell'installazione
+		if samePath(install, home) || samePath(install, filepath.Dir(home)) {
+			return true
+		}
+	}
+	return false
+}
+
+// activateDefaultJDK attiva il JDK predefinito come 'jenvy use default [scope]'.
+func activateDefaultJDK(scope string) error {
+	useArgs := []string{os.Args[0], "use", utils.DefaultAlias}
+	if scope != "" {
+		useArgs = append(useArgs, scope)
+	}
+	os.Args = useArgs
+	return UseJDK()
+}
+
+// followDefaultJDK ripunta JAVA_HOME su una nuova installazione della major del JDK predefinito.
+//
+// Viene chiamata dopo un'installazione riuscita ('download', 'extract',
+// 'install'); non fa nulla salvo che:
+//   - default-follow-updates sia true
+//   - il predefinito sia una major (es. "21"): una patch esatta resta fissata
+//   - la nuova installazione sia di quella major
+//   - il JDK attivo sia della stessa major e meno recente
+//
+// Lo scope modificato è quello effettivo (vedi repointJavaHome).
+func followDefaultJDK(installDir string) {
+	cfg, err := utils.LoadConfigOrDefault()
+	if err != nil || !cfg.DefaultFollowUpdates {
+		return
+	}
+	major, err := strconv.Atoi(cfg.Aliases[utils.DefaultAlias])
+	if err != nil {
+		return
+	}
+
+	newHome, ok := utils.ResolveJDKHome(installDir)
+	if !ok {
+		return
+	}
+	newVersion, ok := utils.JDKHomeVersion(newHome)
+	if newMajor, _, _ := utils.ParseVersionNumber(newVersion); !ok || newMajor != major {
+		return
+	}
+
+	active, activeHome, ok := activeJavaHome()
+	if !ok {
+		return
+	}
+	activeVersion, ok := utils.JDKHomeVersion(activeHome)
+	if activeMajor, _, _ := utils.ParseVersionNumber(activeVersion); !ok || activeMajor != major {
+		return
+	}
+	if utils.CompareJavaVersions(newVersion, activeVersion) <= 0 {
+		return
+	}
+
+	fmt.Println()
+	utils.PrintInfo(fmt.Sprintf("JDK %s is newer than the active default JDK %s", newVersion, activeVersion))
+	repointJavaHome(active, activeHome, newHome, newVersion)
+}
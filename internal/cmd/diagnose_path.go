@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"jenvy/internal/utils"
+)
+
+// DiagnosePath gestisce il comando 'jenvy diagnose-path' per individuare problemi di ordinamento del PATH.
+//
+// Elenca, nell'ordine di ricerca, ogni directory del PATH effettivo che contiene
+// java.exe e indica:
+//   - **Quale viene eseguita**: La directory a cui 'java' si risolve (exec.LookPath)
+//   - **Controllo Jenvy**: Se la directory appartiene a un JDK installato da Jenvy
+//   - **JAVA_HOME**: Se la directory è %JAVA_HOME%\bin
+//
+// Se il java eseguito non è quello gestito da Jenvy, spiega quale voce lo oscura.
+// Il controllo usa il PATH del processo corrente: dopo 'jenvy use' occorre aprire
+// un nuovo terminale perché le modifiche al registro siano visibili.
+//
+// Esempio di utilizzo:
+//
+//	jenvy diagnose-path
+func DiagnosePath() error {
+	utils.PrintSection("[PATH] JAVA ON PATH")
+	fmt.Println()
+
+	entries := utils.FindJavaOnPath(os.Getenv("PATH"))
+	if len(entries) == 0 {
+		utils.PrintWarning("No java executable found on PATH")
+		utils.PrintInfo("Run 'jenvy use <version>' and restart the terminal")
+		return utils.ExitWith(utils.ExitNotFound, errors.New("no java executable found on PATH"))
+	}
+
+	versionsDir, _ := utils.GetJenvyVersionsDirectory()
+	javaHome := os.Getenv("JAVA_HOME")
+	javaHomeBin := ""
+	if javaHome != "" {
+		javaHomeBin = filepath.Join(javaHome, "bin")
+	}
+
+	resolvedDir := ""
+	if resolved, err := exec.LookPath("java"); err == nil {
+		resolvedDir = filepath.Dir(resolved)
+	}
+
+	active, firstManaged := printJavaPathEntries(entries, resolvedDir, javaHomeBin, versionsDir)
+	fmt.Println()
+
+	switch {
+	case active == nil:
+		utils.PrintWarning("'java' could not be resolved on PATH")
+	case firstManaged == nil:
+		utils.PrintWarning(fmt.Sprintf("'java' resolves to %s, which is not managed by Jenvy", active.Dir))
+		utils.PrintInfo("Run 'jenvy use <version>' to add " + javaHomeBinEntry + " to PATH")
+	case active != firstManaged:
+		utils.PrintWarning(fmt.Sprintf("'java' resolves to %s, shadowing the Jenvy JDK at position %d", active.Dir, firstManaged.Position))
+		utils.PrintInfo("Move " + javaHomeBinEntry + " before it in PATH or remove the external entry, then restart the terminal")
+	default:
+		utils.PrintSuccess(fmt.Sprintf("'java' resolves to the Jenvy JDK at %s", active.Dir))
+	}
+	return nil
+}
+
+// printJavaPathEntries stampa le directory del PATH che contengono java, marcando quella eseguita.
+//
+// Condivisa da 'jenvy diagnose-path' (PATH reale) e 'jenvy use --dry-run' (PATH ipotetico).
+//
+// Parametri:
+//
+//	entries []utils.JavaPathEntry - Risultato di utils.FindJavaOnPath
+//	resolvedDir string            - Directory a cui 'java' si risolve (vuota se sconosciuta)
+//	javaHomeBin string            - %JAVA_HOME%\bin da etichettare (vuoto se non impostato)
+//	versionsDir string            - Directory delle installazioni Jenvy
+//
+// Restituisce:
+//
+//	active       - Voce eseguita digitando 'java' (nil se non risolta)
+//	firstManaged - Prima voce appartenente a un JDK Jenvy (nil se nessuna)
+func printJavaPathEntries(entries []utils.JavaPathEntry, resolvedDir, javaHomeBin, versionsDir string) (active, firstManaged *utils.JavaPathEntry) {
+	for i := range entries {
+		entry := &entries[i]
+		managed := versionsDir != "" && isPathInside(entry.Dir, versionsDir)
+		isActive := resolvedDir != "" && samePath(entry.Dir, resolvedDir)
+		if isActive && active == nil {
+			active = entry
+		}
+		if managed && firstManaged == nil {
+			firstManaged = entry
+		}
+
+		var tags []string
+		if managed {
+			tags = append(tags, "jenvy")
+		} else {
+			tags = append(tags, "external")
+		}
+		if javaHomeBin != "" && samePath(entry.Dir, javaHomeBin) {
+			tags = append(tags, "JAVA_HOME")
+		}
+
+		marker := "   "
+		line := fmt.Sprintf("[%d] %s (%s)", entry.Position, entry.Dir, strings.Join(tags, ", "))
+		if isActive {
+			marker = "-> "
+			line = utils.ColorText(line, utils.Green)
+		}
+		fmt.Println("  " + marker + line)
+	}
+	return active, firstManaged
+}
+
+// samePath confronta due percorsi come fa Windows (case-insensitive, dopo la pulizia).
+func samePath(a, b string) bool {
+	return strings.EqualFold(filepath.Clean(a), filepath.Clean(b))
+}
@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"jenvy/internal/utils"
+)
+
+// Esiti di un controllo di 'jenvy doctor'.
+const (
+	doctorOK   = "OK"
+	doctorWarn = "WARN"
+	doctorFail = "FAIL"
+)
+
+// doctorFinding è l'esito di un controllo di 'jenvy doctor' con l'eventuale rimedio.
+type doctorFinding struct {
+	Status string
+	Detail string
+	Fixes  []string // Azioni suggerite, una per riga
+}
+
+// doctorCheck è un controllo dell'ambiente eseguito da 'jenvy doctor'.
+type doctorCheck struct {
+	Name string
+	Run  func() []doctorFinding
+}
+
+// Doctor gestisce 'jenvy doctor': verifica l'intero ambiente e suggerisce come correggerlo.
+//
+// Riunisce in un solo report i controlli oggi sparsi tra 'current', 'resolve',
+// 'diagnose-path', 'list' e 'providers status':
+//   - **JAVA_HOME**: I valori utente e di sistema puntano a un JDK valido
+//   - **PATH**: Il primo java del PATH è quello di jenvy, non un'installazione
+//     esterna (Oracle installer, Scoop, MSI...) che lo oscura
+//   - **Registry**: %JAVA_HOME%\bin è nel PATH di sistema e il PATH è REG_EXPAND_SZ
+//   - **Installations**: Directory senza JDK valido, archivi da estrarre o
+//     rimasti dopo l'estrazione, download interrotti (.part)
+//   - **Providers**: Raggiungibilità delle API dei provider (saltata con --offline)
+//
+// Nessuna modifica viene applicata: per ogni problema viene indicato il comando
+// che lo risolve. Esce con utils.ExitGeneric se almeno un controllo è FAIL.
+//
+// Esempio di utilizzo:
+//
+//	jenvy doctor
+//	jenvy doctor --offline
+func Doctor() error {
+	offline := false
+	for _, arg := range os.Args[2:] {
+		if arg != "--offline" {
+			utils.PrintUsage("Usage: jenvy doctor [--offline]")
+			return utils.ExitWith(utils.ExitGeneric, nil)
+		}
+		offline = true
+	}
+
+	checks := []doctorCheck{
+		{"JAVA_HOME", doctorCheckJavaHome},
+		{"PATH", doctorCheckPath},
+		{"Registry", doctorCheckRegistry},
+		{"Installations", doctorCheckInstallations},
+	}
+	if !offline {
+		checks = append(checks, doctorCheck{"Providers", doctorCheckProviders})
+	}
+
+	utils.PrintSection("[DOCTOR] ENVIRONMENT CHECK")
+	fmt.Println()
+	warnings, failures := 0, 0
+	for _, check := range checks {
+		for i, finding := range check.Run() {
+			name := check.Name
+			if i > 0 {
+				name = ""
+			}
+			color := utils.Green
+			switch finding.Status {
+			case doctorWarn:
+				color = utils.Yellow
+				warnings++
+			case doctorFail:
+				color = utils.Red
+				failures++
+			}
+			fmt.Printf("  %-14s %s %s\n", name, utils.ColorText(fmt.Sprintf("%-4s", finding.Status), color), finding.Detail)
+			for _, fix := range finding.Fixes {
+				fmt.Printf("  %-14s      -> %s\n", "", fix)
+			}
+		}
+	}
+	fmt.Println()
+
+	switch {
+	case failures > 0:
+		utils.PrintError(fmt.Sprintf("%s, %s", pluralize(failures, "problem"), pluralize(warnings, "warning")))
+		return utils.ExitWith(utils.ExitGeneric, fmt.Errorf("doctor found %d problem(s)", failures))
+	case warnings > 0:
+		utils.PrintWarning(fmt.Sprintf("No problems, %s", pluralize(warnings, "warning")))
+	default:
+		utils.PrintSuccess("Everything looks good")
+	}
+	return nil
+}
+
+// doctorCheckJavaHome verifica che i JAVA_HOME utente e di sistema puntino a un JDK valido.
+func doctorCheckJavaHome() []doctorFinding {
+	var findings []doctorFinding
+	userHome, hasUser := readUserEnvironmentVariable("JAVA_HOME")
+	systemHome, hasSystem := readSystemEnvironmentVariable("JAVA_HOME")
+	if !hasUser && !hasSystem {
+		return []doctorFinding{{doctorWarn, "JAVA_HOME is not set", []string{"jenvy use <version>"}}}
+	}
+	for _, scope := range []struct {
+		name, value string
+		set         bool
+		fix         string
+	}{
+		{"user", userHome, hasUser, "jenvy use <version> --user"},
+		{"system", systemHome, hasSystem, "jenvy use <version>"},
+	} {
+		if !scope.set {
+			continue
+		}
+		home := utils.ResolveJavaHome(scope.value)
+		if _, err := os.Stat(utils.JavaExecutablePath(home)); err != nil {
+			findings = append(findings, doctorFinding{doctorFail,
+				fmt.Sprintf("%s JAVA_HOME=%s does not contain %s", scope.name, scope.value, filepath.Join("bin", utils.JavaExecutableName())),
+				[]string{scope.fix}})
+			continue
+		}
+		findings = append(findings, doctorFinding{doctorOK, fmt.Sprintf("%s JAVA_HOME=%s (%s)", scope.name, home, managedInstallLabel(home)), nil})
+	}
+	if hasUser && hasSystem && !samePath(utils.ResolveJavaHome(userHome), utils.ResolveJavaHome(systemHome)) {
+		findings = append(findings, doctorFinding{doctorWarn,
+			"the user JAVA_HOME overrides a different system JAVA_HOME",
+			[]string{"Intended if you use 'jenvy use --user'; otherwise delete JAVA_HOME from HKCU\\Environment"}})
+	}
+	return findings
+}
+
+// doctorCheckPath verifica che nessun java esterno preceda quello di jenvy nel PATH del terminale.
+func doctorCheckPath() []doctorFinding {
+	entries := utils.FindJavaOnPath(os.Getenv("PATH"))
+	if len(entries) == 0 {
+		return []doctorFinding{{doctorFail, "no java executable on PATH",
+			[]string{"jenvy use <version>, then open a new terminal"}}}
+	}
+
+	versionsDir, _ := utils.GetJenvyVersionsDirectory()
+	res := resolveEffectiveJava()
+	var findings []doctorFinding
+	for _, entry := range entries {
+		managed := (versionsDir != "" && isPathInside(entry.Dir, versionsDir)) ||
+			(res.IntendedHome != "" && samePath(entry.Dir, filepath.Join(res.IntendedHome, "bin")))
+		if managed {
+			break
+		}
+		origin := utils.JavaPathOrigin(entry.Dir)
+		if origin == "" {
+			origin = "external"
+		}
+		findings = append(findings, doctorFinding{doctorWarn,
+			fmt.Sprintf("PATH entry %d %s (%s) provides java before the Jenvy JDK", entry.Position, entry.Dir, origin),
+			[]string{doctorPathFix(origin), "jenvy diagnose-path"}})
+	}
+	if res.Mismatch != "" && len(findings) == 0 {
+		findings = append(findings, doctorFinding{doctorWarn, res.Mismatch, []string{"jenvy resolve"}})
+	}
+	if len(findings) == 0 {
+		findings = append(findings, doctorFinding{doctorOK, fmt.Sprintf("'java' resolves to %s", res.PathJava), nil})
+	}
+	return findings
+}
+
+// doctorPathFix suggerisce come rimuovere un java esterno in base a chi lo ha installato.
+func doctorPathFix(origin string) string {
+	switch origin {
+	case "Oracle installer":
+		return "Uninstall Oracle Java from Settings > Apps, or remove the javapath entry from the system PATH"
+	case "Scoop":
+		return "scoop reset or scoop uninstall the Java app, or remove its shim from the user PATH"
+	case "Chocolatey":
+		return "choco uninstall the Java package, or remove its entry from the system PATH"
+	default:
+		return "Remove the entry from PATH or move " + javaHomeBinEntry + " before it, then open a new terminal"
+	}
+}
+
+// doctorCheckInstallations cerca directory danneggiate, archivi residui e download interrotti.
+func doctorCheckInstallations() []doctorFinding {
+	versionsDir, err := utils.GetJenvyVersionsDirectory()
+	if err != nil {
+		return []doctorFinding{{doctorFail, err.Error(), nil}}
+	}
+	scans, err := scanInstallations(versionsDir, false)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []doctorFinding{{doctorOK, "no JDK installed yet", nil}}
+		}
+		return []doctorFinding{{doctorFail, fmt.Sprintf("cannot read %s: %v", versionsDir, err), nil}}
+	}
+
+	keepArchives := false
+	if cfg, err := utils.LoadConfigOrDefault(); err == nil && cfg.KeepArchives != nil {
+		keepArchives = *cfg.KeepArchives
+	}
+
+	var findings []doctorFinding
+	valid := 0
+	for _, scan := range scans {
+		partials, _ := filepath.Glob(filepath.Join(scan.Path, "*"+partialDownloadSuffix))
+		switch {
+		case scan.JDKHome != "":
+			valid++
+			if scan.ArchivePath != "" && !keepArchives {
+				findings = append(findings, doctorFinding{doctorWarn,
+					fmt.Sprintf("%s: leftover archive %s (%.1f MB)", scan.Name, filepath.Base(scan.ArchivePath), float64(scan.ArchiveSize)/1024/1024),
+					[]string{fmt.Sprintf("Delete %s, or 'jenvy config set keep-archives true' to keep archives on purpose", scan.ArchivePath)}})
+			}
+		case scan.ArchivePath != "":
+			findings = append(findings, doctorFinding{doctorWarn,
+				fmt.Sprintf("%s: archive not extracted yet", scan.Name),
+				[]string{"jenvy extract --all"}})
+		case len(partials) > 0:
+			findings = append(findings, doctorFinding{doctorWarn,
+				fmt.Sprintf("%s: interrupted download %s", scan.Name, filepath.Base(partials[0])),
+				[]string{"jenvy download <version> --resume"}})
+			continue
+		default:
+			findings = append(findings, doctorFinding{doctorFail,
+				fmt.Sprintf("%s: not a valid JDK (missing %s or lib)", scan.Name, filepath.Join("bin", utils.JavaExecutableName())),
+				[]string{fmt.Sprintf("jenvy remove %s, then download it again", scan.Name)}})
+		}
+		if len(partials) > 0 {
+			findings = append(findings, doctorFinding{doctorWarn,
+				fmt.Sprintf("%s: stale partial download %s", scan.Name, filepath.Base(partials[0])),
+				[]string{fmt.Sprintf("Delete %s", partials[0])}})
+		}
+	}
+	return append([]doctorFinding{{doctorOK, fmt.Sprintf("%s in %s", pluralize(valid, "valid JDK"), versionsDir), nil}}, findings...)
+}
+
+// doctorCheckProviders verifica la raggiungibilità delle API dei provider, come 'providers status'.
+func doctorCheckProviders() []doctorFinding {
+	endpoints := append([]providerEndpoint{}, publicProviderEndpoints...)
+	if cfg, err := utils.LoadConfigOrDefault(); err == nil && cfg.PrivateEndpoint != "" {
+		endpoints = append(endpoints, providerEndpoint{Name: "Private", URL: cfg.PrivateEndpoint, Token: cfg.PrivateToken})
+	}
+	results := make([]utils.ProbeResult, len(endpoints))
+	utils.ForEachParallel(len(endpoints), len(endpoints), func(i int) {
+		results[i] = utils.ProbeEndpoint(endpoints[i].URL, endpoints[i].Token, providerProbeTimeout)
+	})
+
+	var unreachable []string
+	for i, result := range results {
+		if !result.Reachable() {
+			unreachable = append(unreachable, endpoints[i].Name)
+		}
+	}
+	switch {
+	case len(unreachable) == len(endpoints):
+		return []doctorFinding{{doctorFail, "no provider is reachable",
+			[]string{"Check the network connection and the proxy (jenvy config set proxy, or HTTP_PROXY / HTTPS_PROXY)", "jenvy providers status"}}}
+	case len(unreachable) > 0:
+		return []doctorFinding{{doctorWarn, fmt.Sprintf("unreachable: %s", strings.Join(unreachable, ", ")),
+			[]string{"jenvy providers status"}}}
+	default:
+		return []doctorFinding{{doctorOK, fmt.Sprintf("all %d providers reachable", len(endpoints)), nil}}
+	}
+}
@@ -0,0 +1,49 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"jenvy/internal/utils"
+)
+
+// doctorCheckRegistry verifica che gli script di profilo di jenvy antepongano $JAVA_HOME/bin al PATH.
+//
+// Su Linux e macOS il ruolo del registro è svolto da /etc/profile.d/jenvy.sh
+// (sistema) e ~/.jenvy/env.sh (utente); quest'ultimo deve essere richiamato
+// da un profilo della shell.
+func doctorCheckRegistry() []doctorFinding {
+	var findings []doctorFinding
+	if _, hasSystem := readSystemEnvironmentVariable("JAVA_HOME"); hasSystem {
+		if inPath, _ := previewSystemPath(""); !inPath {
+			findings = append(findings, doctorFinding{doctorWarn, systemProfileScript + " does not add $JAVA_HOME/bin to PATH",
+				[]string{"sudo jenvy init"}})
+		}
+	}
+	if _, hasUser := readUserEnvironmentVariable("JAVA_HOME"); hasUser {
+		if !userProfileSourced() {
+			findings = append(findings, doctorFinding{doctorWarn, userEnvironmentSource() + " is not sourced by any shell profile",
+				[]string{"jenvy use <version> --user"}})
+		}
+	}
+	if len(findings) == 0 {
+		findings = append(findings, doctorFinding{doctorOK, "shell profile PATH and JAVA_HOME are consistent", nil})
+	}
+	return findings
+}
+
+// userProfileSourced indica se almeno un profilo della shell richiama ~/.jenvy/env.sh.
+func userProfileSourced() bool {
+	homeDir, err := utils.UserHomeDir()
+	if err != nil {
+		return false
+	}
+	for _, profile := range userShellProfiles(homeDir) {
+		if content, err := os.ReadFile(profile); err == nil && strings.Contains(string(content), profileSourceStart) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// doctorCheckRegistry verifica che il PATH di sistema contenga %JAVA_HOME%\bin e possa espanderlo.
+func doctorCheckRegistry() []doctorFinding {
+	key, err := openSystemEnvironmentKey(registry.QUERY_VALUE)
+	if err != nil {
+		return []doctorFinding{{doctorFail, err.Error(), nil}}
+	}
+	defer key.Close()
+
+	path, valueType, err := key.GetStringValue("Path")
+	if err != nil {
+		return []doctorFinding{{doctorFail, fmt.Sprintf("cannot read the system PATH: %v", err), nil}}
+	}
+
+	var findings []doctorFinding
+	hasJavaHomeBin := false
+	for _, entry := range strings.Split(path, ";") {
+		if strings.EqualFold(strings.TrimSpace(entry), `%JAVA_HOME%\bin`) {
+			hasJavaHomeBin = true
+		}
+	}
+	if _, hasSystem := readSystemEnvironmentVariable("JAVA_HOME"); hasSystem && !hasJavaHomeBin {
+		findings = append(findings, doctorFinding{doctorWarn, "system PATH does not contain %JAVA_HOME%\\bin",
+			[]string{"jenvy init (as Administrator)"}})
+	}
+	if valueType != registry.EXPAND_SZ && strings.Contains(path, "%") {
+		findings = append(findings, doctorFinding{doctorFail, "system PATH is REG_SZ: %JAVA_HOME% and other references are not expanded",
+			[]string{"jenvy fix-path (as Administrator) rewrites it as REG_EXPAND_SZ"}})
+	}
+	if len(findings) == 0 {
+		findings = append(findings, doctorFinding{doctorOK, "system PATH and JAVA_HOME are consistent", nil})
+	}
+	return findings
+}
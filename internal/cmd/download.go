@@ -1,19 +1,20 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
-	"os/user"
+	"os/signal"
 	"path/filepath"
-	"runtime"
-	"strconv"
 	"strings"
 	"time"
 
 	"jenvy/internal/providers/adoptium"
 	"jenvy/internal/providers/azul"
+	"jenvy/internal/providers/graalvm"
 	"jenvy/internal/providers/liberica"
 	"jenvy/internal/providers/private"
 	"jenvy/internal/utils"
@@ -46,12 +47,14 @@ type RuntimeInfo struct {
 // È essenziale per garantire il download del JDK corretto per il sistema Windows.
 //
 // Processo di rilevamento:
-// 1. **Sistema operativo**: Sempre "windows" (tool Windows-only)
-// 2. **Rilevamento architettura**: Usa runtime.GOARCH per identificare l'architettura CPU
+// 1. **Sistema operativo**: utils.TargetOS (--os o, in mancanza, il sistema in uso)
+// 2. **Rilevamento architettura**: Usa utils.TargetArch (--arch o, in mancanza, il sistema e non il processo)
 // 3. **Normalizzazione**: Converte nomi Go in formato standard provider JDK
 // 4. **Ritorno struttura**: Incapsula informazioni in RuntimeInfo
 //
-// Mappature architettura (Go → JDK):
+// Un jenvy a 32 bit su Windows 64 bit sceglie quindi comunque un JDK x64.
+//
+// Mappature architettura (Go → JDK, vedi utils.JDKArch):
 //   - "amd64" → "x64" (Intel/AMD 64-bit, più comune)
 //   - "386" → "x32" (Intel/AMD 32-bit, legacy)
 //   - "arm64" → "aarch64" (ARM 64-bit, Windows ARM)
@@ -80,19 +83,31 @@ type RuntimeInfo struct {
 //	fmt.Printf("Sistema: %s %s", runtime.OS, runtime.Arch)
 //	// Output su Windows 64-bit: "Sistema: windows x64"
 func getRuntimeInfo() RuntimeInfo {
-	archName := runtime.GOARCH
+	return RuntimeInfo{OS: utils.TargetOS(), Arch: utils.TargetArch()}
+}
 
-	// Convert Go runtime names to JDK format
-	switch archName {
-	case "amd64":
-		archName = "x64"
-	case "386":
-		archName = "x32"
-	case "arm64":
-		archName = "aarch64"
+// applyRequestedArch valida --arch e avvisa se il JDK richiesto non è quello nativo.
+//
+// Un JDK x32 su Windows x64 funziona ma è limitato a circa 4 GB di heap; uno non
+// eseguibile dal sistema (es. x64 su un sistema a 32 bit) viene comunque scaricato,
+// ad esempio per un'altra macchina, ma con un avviso più esplicito.
+func applyRequestedArch(value string) error {
+	arch, err := utils.ParseArch(value)
+	if err != nil {
+		return err
 	}
-
-	return RuntimeInfo{OS: "windows", Arch: archName}
+	native := utils.NativeArch()
+	switch {
+	case utils.IsForeignOS(utils.TargetOS()):
+		// L'archivio è per un'altra macchina: il confronto con questo sistema non ha senso
+	case !utils.ArchRunsOn(arch, native):
+		utils.PrintWarning(fmt.Sprintf("Requested architecture %s cannot run on this %s %s installation", arch, native, utils.HostOSName()))
+	case arch != native:
+		utils.PrintWarning(fmt.Sprintf("Requested architecture %s does not match this %s %s installation", arch, native, utils.HostOSName()))
+		utils.PrintInfo(fmt.Sprintf("Omit --arch to download the native %s JDK", native))
+	}
+	utils.SetTargetArch(arch)
+	return nil
 }
 
 // shouldPreferVersion determina quale di due versioni JDK dovrebbe essere preferita.
@@ -105,10 +120,11 @@ func getRuntimeInfo() RuntimeInfo {
 // 1. **Versione major superiore**: 21.x.x > 17.x.x
 // 2. **Versione minor superiore**: 17.1.x > 17.0.x (a parità di major)
 // 3. **Versione patch superiore**: 17.0.5 > 17.0.2 (a parità di major.minor)
+// 4. **Build superiore**: 17.0.9+11 > 17.0.9+9 (ricompilazione della stessa patch)
 //
 // Strategia di confronto:
-//   - Parsing completo di entrambe le versioni con adoptium.ParseVersion()
-//   - Confronto gerarchico: major → minor → patch
+//   - Versioni già scomposte al caricamento dell'elenco (utils.ReleaseVersion)
+//   - Confronto gerarchico: major → minor → patch → build
 //   - Prima differenza significativa determina il risultato
 //   - Versioni identiche: ritorna false (nessuna preferenza)
 //
@@ -120,23 +136,22 @@ func getRuntimeInfo() RuntimeInfo {
 //
 // Esempi di comportamento:
 //
-//	shouldPreferVersion("21.0.2", "17.0.5") → true (major superiore)
-//	shouldPreferVersion("17.1.0", "17.0.8") → true (minor superiore)
-//	shouldPreferVersion("17.0.5", "17.0.2") → true (patch superiore)
-//	shouldPreferVersion("17.0.2", "17.0.2") → false (identiche)
+//	21.0.2 rispetto a 17.0.5 → true (major superiore)
+//	17.1.0 rispetto a 17.0.8 → true (minor superiore)
+//	17.0.5 rispetto a 17.0.2 → true (patch superiore)
+//	17.0.2 rispetto a 17.0.2 → false (identiche)
 //
 // Parametri:
 //
-//	version1 string - Prima versione da confrontare
-//	version2 string - Seconda versione da confrontare
+//	version1 utils.ReleaseVersion - Prima versione da confrontare
+//	version2 utils.ReleaseVersion - Seconda versione da confrontare
 //
 // Restituisce:
 //
 //	bool - true se version1 dovrebbe essere preferita a version2, false altrimenti
 //
 // Note implementative:
-//   - Utilizza adoptium.ParseVersion() per parsing consistente
-//   - Gestisce automaticamente versioni malformate (tramite ParseVersion)
+//   - Nessun parsing: confronta gli interi calcolati dal provider
 //   - Algoritmo deterministico: stesso input produce sempre stesso output
 //   - Performance ottimizzata: stop al primo livello di differenza
 //
@@ -147,25 +162,27 @@ func getRuntimeInfo() RuntimeInfo {
 //
 // Esempio di utilizzo:
 //
-//	if shouldPreferVersion("21.0.1", bestVersion) {
-//	    bestVersion = "21.0.1"
+//	if shouldPreferVersion(release.ReleaseVersion, bestMatch.ReleaseVersion) {
+//	    bestMatch = release
 //	}
-func shouldPreferVersion(version1, version2 string) bool {
-	v1Major, v1Minor, v1Patch := adoptium.ParseVersion(version1)
-	v2Major, v2Minor, v2Patch := adoptium.ParseVersion(version2)
-
+func shouldPreferVersion(version1, version2 utils.ReleaseVersion) bool {
 	// Prefer higher major version
-	if v1Major != v2Major {
-		return v1Major > v2Major
+	if version1.Major != version2.Major {
+		return version1.Major > version2.Major
 	}
 
 	// Prefer higher minor version
-	if v1Minor != v2Minor {
-		return v1Minor > v2Minor
+	if version1.Minor != version2.Minor {
+		return version1.Minor > version2.Minor
 	}
 
 	// Prefer higher patch version
-	return v1Patch > v2Patch
+	if version1.Patch != version2.Patch {
+		return version1.Patch > version2.Patch
+	}
+
+	// Prefer the latest respin of the same patch
+	return version1.Build > version2.Build
 }
 
 // DownloadJDK esegue il download completo e l'installazione di una versione JDK specifica su Windows.
@@ -190,11 +207,27 @@ func shouldPreferVersion(version1, version2 string) bool {
 //	jenvy download 21.0.2                # Versione specifica
 //	jenvy download 17 --provider=azul    # Provider specifico
 //	jenvy download 21 --output=./jdks    # Directory custom
+//	jenvy download 17 --no-flatten       # Preserva la struttura originale dell'archivio
+//	jenvy download 17 --archive-name=temurin-{version}-{os}-{arch}  # Nome archivio personalizzato
+//	jenvy download 17 --yes --delete-archive  # Nessuna domanda, archivio rimosso dopo l'estrazione
+//	jenvy download 17 21 --provider=adoptium      # Più versioni in parallelo (vedi downloadVersions)
+//	jenvy download --all-lts --provider=adoptium  # Tutte le LTS (vedi downloadAllLTS)
+//	jenvy download --manifest team-jdks.json      # JDK fissati da un file (vedi downloadManifest)
+//	jenvy download 17 --checksum-algorithm=sha512  # Forza l'algoritmo di verifica del checksum
+//	jenvy download 17 --resolve-only               # Mostra la release risolta senza scaricarla
+//	jenvy download 17 --checksum=<sha256>          # Verifica l'archivio con un hash noto (vedi verifyDownloadedArchive)
+//	jenvy download 17 --verbose                    # Mostra anche token e quota GitHub
+//	jenvy download 17 --arch=x32                   # JDK per un'architettura diversa da quella nativa
+//	jenvy download 17 --resume                     # Riprende subito i trasferimenti interrotti (vedi downloadFile)
+//	jenvy download 17 --extract-to=D:\team-jdks    # Archivio in --output, JDK estratto altrove
+//	jenvy download 25 --ea                         # Build early-access di una major non ancora rilasciata
+//	jenvy download 21 --type=jre --javafx --provider=liberica  # JRE Full con JavaFX
 //
 // Provider supportati:
 //   - **adoptium**: Eclipse Adoptium (default, più popolare)
 //   - **azul**: Azul Zulu OpenJDK (enterprise-ready)
 //   - **liberica**: BellSoft Liberica JDK
+//   - **graalvm**: GraalVM Community o Oracle GraalVM (graalvm-edition), con native-image
 //   - **private**: Repository aziendali configurati
 //
 // Gestione intelligente versioni:
@@ -211,6 +244,7 @@ func shouldPreferVersion(version1, version2 string) bool {
 //
 // Gestione directory:
 //   - Default: C:\Users\username\.jenvy\versions\JDK-{version}\
+//   - Naming configurabile: 'jenvy config set naming-scheme {provider}-jdk-{version}'
 //   - Struttura: Una directory per versione per isolamento
 //   - Creazione automatica di directory mancanti
 //   - Estrazione con flattening di directory annidate
@@ -250,22 +284,32 @@ func shouldPreferVersion(version1, version2 string) bool {
 //	DownloadJDK("adoptium")
 //	// Con args: ["17", "--provider=azul"]
 //	// Risultato: JDK 17 Azul scaricato in ~/.jenvy/versions/JDK-17.x.y/
-func DownloadJDK(defaultProvider string) {
+func DownloadJDK(defaultProvider string) error {
 
 	// Parse command line arguments
-	args := os.Args[2:] // Skip "download"
-	if len(args) == 0 {
-		utils.PrintError("No JDK version specified")
-		utils.PrintInfo("Usage: jenvy download <version> [options]")
-		utils.PrintInfo("Examples:")
-		fmt.Println("  jenvy download 17          # Download JDK 17")
-		fmt.Println("  jenvy download 21.0.5      # Download specific version")
-		fmt.Println("  jenvy download 17 --provider=azul")
-		return
-	}
-
-	version := args[0]
+	// Più versioni vengono scaricate in parallelo (vedi downloadVersions)
+	versions, args := utils.SplitArgs(os.Args[2:])
+	allLTS := false
+	manifestPath := "" // --manifest: file di JDK fissati da installare (vedi utils.LoadJDKManifest)
 	provider := defaultProvider
+	// Con activation-style=junction si conserva la struttura nativa dell'archivio
+	flatten := utils.ActivationStyle() != utils.ActivationStyleJunction
+	archiveName := ""
+	assumeYes := utils.AssumeYes()
+	var keepArchive *bool // nil = usa keep-archives da config o chiedi
+	checksumAlgorithm := ""
+	expectedChecksum := "" // --checksum: hash noto fuori banda, ha precedenza su quello del provider
+	resolveOnly := false
+	verbose := false
+	progressFlag := utils.ProgressAuto
+	extractTo := ""                 // --extract-to: estrae fuori da --output, che resta la cache degli archivi
+	archFlag := ""                  // --arch: architettura del JDK, se diversa da quella nativa
+	osFlag := ""                    // --os: sistema dell'archivio, per scaricarlo per un'altra macchina
+	resume := false                 // --resume: riprende subito i trasferimenti interrotti
+	stream := false                 // --stream: estrae il tar.gz durante il download, senza archivio su disco
+	earlyAccess := false            // --ea: include le build early-access di Adoptium e Liberica
+	imageType := utils.ImageTypeJDK // --type: jdk oppure jre
+	javafx := false                 // --javafx: pacchetti con JavaFX (Liberica Full, Zulu FX)
 
 	// Get default download directory: ~/.jenvy/versions
 	outputDir, dirErr := getDefaultDownloadDir()
@@ -274,89 +318,324 @@ func DownloadJDK(defaultProvider string) {
 		outputDir = "./downloads" // fallback
 	}
 
-	// Parse optional flags
-	for i := 1; i < len(args); i++ {
+	// Parse version and optional flags
+	for i := 0; i < len(args); i++ {
 		arg := args[i]
-		if strings.HasPrefix(arg, "--provider=") {
+		if arg == "--all-lts" {
+			allLTS = true
+		} else if strings.HasPrefix(arg, "--manifest=") {
+			manifestPath = strings.TrimPrefix(arg, "--manifest=")
+		} else if arg == "--manifest" && i+1 < len(args) {
+			i++
+			manifestPath = args[i]
+		} else if strings.HasPrefix(arg, "--provider=") {
 			provider = strings.TrimPrefix(arg, "--provider=")
 		} else if strings.HasPrefix(arg, "--output=") {
 			outputDir = strings.TrimPrefix(arg, "--output=")
+		} else if strings.HasPrefix(arg, "--extract-to=") {
+			extractTo = strings.TrimPrefix(arg, "--extract-to=")
+		} else if arg == "--extract-to" && i+1 < len(args) {
+			i++
+			extractTo = args[i]
+		} else if arg == "--no-flatten" {
+			flatten = false
+		} else if strings.HasPrefix(arg, "--archive-name=") {
+			archiveName = strings.TrimPrefix(arg, "--archive-name=")
+		} else if arg == "--archive-name" && i+1 < len(args) {
+			i++
+			archiveName = args[i]
+		} else if strings.HasPrefix(arg, "--checksum-algorithm=") {
+			checksumAlgorithm = strings.TrimPrefix(arg, "--checksum-algorithm=")
+		} else if strings.HasPrefix(arg, "--checksum=") {
+			expectedChecksum = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(arg, "--checksum=")))
+		} else if strings.HasPrefix(arg, "--arch=") {
+			archFlag = strings.TrimPrefix(arg, "--arch=")
+		} else if strings.HasPrefix(arg, "--os=") {
+			osFlag = strings.TrimPrefix(arg, "--os=")
+		} else if arg == "--resume" {
+			resume = true
+		} else if arg == "--stream" {
+			stream = true
+		} else if arg == "--ea" {
+			earlyAccess = true
+		} else if strings.HasPrefix(arg, "--type=") {
+			imageType = strings.TrimPrefix(arg, "--type=")
+		} else if arg == "--javafx" {
+			javafx = true
+		} else if arg == "--resolve-only" {
+			resolveOnly = true
+		} else if arg == "--verbose" {
+			verbose = true
+		} else if strings.HasPrefix(arg, "--progress=") {
+			progressFlag = utils.ProgressMode(strings.TrimPrefix(arg, "--progress="))
+		} else if arg == "--yes" || arg == "-y" {
+			assumeYes = true
+		} else if arg == "--keep-archive" || arg == "--delete-archive" {
+			keep := arg == "--keep-archive"
+			keepArchive = &keep
+		} else {
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Unknown option for download: %s", arg))
 		}
 	}
 
-	fmt.Printf("%s Searching for JDK version %s from provider: %s\n",
-		utils.ColorText("[>]", utils.BrightCyan), version, provider)
-	fmt.Printf("%s Download directory: %s\n\n",
-		utils.ColorText("[>]", utils.BrightCyan), outputDir)
+	if len(versions) == 0 && !allLTS && manifestPath == "" {
+		utils.PrintError("No JDK version specified")
+		utils.PrintInfo("Usage: jenvy download <version> [options]")
+		utils.PrintInfo("Examples:")
+		fmt.Println("  jenvy download 17          # Download JDK 17")
+		fmt.Println("  jenvy download 21.0.5      # Download specific version")
+		fmt.Println("  jenvy download 17 21 --provider=adoptium # Download several versions in parallel")
+		fmt.Println("  jenvy download 17 --provider=azul")
+		fmt.Println("  jenvy download 17 --no-flatten # Keep the archive's directory layout")
+		fmt.Println("  jenvy download 17 --archive-name={provider}-{version}-{os}-{arch}")
+		fmt.Println("  jenvy download 17 --yes --delete-archive # Non-interactive, remove archive after extraction")
+		fmt.Println("  jenvy download --all-lts --provider=adoptium # Install the latest patch of every LTS")
+		fmt.Println("  jenvy download --manifest team-jdks.json # Install exactly the JDKs pinned in a manifest")
+		fmt.Println("  jenvy download 17 --provider=private --checksum-algorithm=sha512")
+		fmt.Println("  jenvy download 17 --resolve-only # Show what 17 resolves to, without downloading")
+		fmt.Println("  jenvy download 17 --provider=private --checksum=<sha256> # Verify against a known hash")
+		fmt.Println("  jenvy download 17 --progress=lines # Log-friendly progress (default when output is redirected)")
+		fmt.Println("  jenvy download 17 --extract-to=D:\\team-jdks # Keep the archive in --output, install elsewhere")
+		fmt.Println("  jenvy download 17 --arch=x32 # Download a JDK for another architecture")
+		fmt.Println("  jenvy download 21 --os=linux # Keep a Linux tar.gz, e.g. for a Docker image")
+		fmt.Println("  jenvy download 17 --resume # Resume interrupted transfers instead of failing")
+		fmt.Println("  jenvy download 21 --stream # Extract while downloading, without writing the archive")
+		fmt.Println("  jenvy download 25 --ea # Early-access build of an upcoming JDK (adoptium, liberica)")
+		fmt.Println("  jenvy download 21 --type=jre --javafx --provider=liberica # JRE bundling JavaFX")
+		return utils.ExitWith(utils.ExitGeneric, nil)
+	}
 
-	// Create output directory if it doesn't exist
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		utils.PrintError(fmt.Sprintf("Failed to create output directory: %v", err))
-		return
+	for i, version := range versions {
+		normalized, err := versionArgument(version, "")
+		if err != nil {
+			return err
+		}
+		versions[i] = normalized
+	}
+	version := ""
+	if len(versions) > 0 {
+		version = versions[0]
 	}
 
-	// Get JDK releases from the specified provider and find matching version
-	var downloadURL string
-	var filename string
-	var foundVersion string
+	progress, err := utils.ResolveProgressMode(progressFlag)
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, err.Error())
+	}
 
-	switch provider {
-	case "adoptium":
-		releases, err := adoptium.GetAllJDKs()
+	if osFlag != "" {
+		targetOS, err := utils.ParseOS(osFlag)
 		if err != nil {
-			fmt.Printf("[ERROR] Failed to fetch releases from %s: %v\n", provider, err)
-			return
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Invalid --os: %v", err))
 		}
-		downloadURL, filename, foundVersion = findAdoptiumDownload(releases, version)
+		if utils.IsForeignOS(targetOS) {
+			if allLTS || manifestPath != "" || extractTo != "" || stream || len(versions) > 1 {
+				return utils.Fail(utils.ExitGeneric, "--os cannot be combined with --all-lts, --manifest, --extract-to, --stream or multiple versions")
+			}
+			utils.SetTargetOS(targetOS)
+			if err := utils.CheckTargetOS(provider); err != nil {
+				return utils.Fail(utils.ExitNotFound, err.Error())
+			}
+		}
+	}
 
-	case "azul":
-		releases, err := azul.GetAzulJDKs()
-		if err != nil {
-			fmt.Printf("[ERROR] Failed to fetch releases from %s: %v\n", provider, err)
-			return
+	if archFlag != "" {
+		// Il manifest fissa lo SHA-256 degli archivi: un'altra architettura non potrebbe corrispondere.
+		// Con --all-lts e più versioni l'architettura entra nel nome della directory (utils.VariantInstallDirName)
+		if manifestPath != "" {
+			return utils.Fail(utils.ExitGeneric, "--arch cannot be combined with --manifest: the manifest pins the archives by SHA-256")
 		}
-		downloadURL, filename, foundVersion = findAzulDownload(releases, version)
+		if err := applyRequestedArch(archFlag); err != nil {
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Invalid --arch: %v", err))
+		}
+	}
 
-	case "liberica":
-		releases, err := liberica.GetLibericaJDKs()
-		if err != nil {
-			fmt.Printf("[ERROR] Failed to fetch releases from %s: %v\n", provider, err)
-			return
+	if checksumAlgorithm != "" {
+		if _, err := utils.NewChecksumHash(checksumAlgorithm); err != nil {
+			return utils.Fail(utils.ExitGeneric, err.Error())
 		}
-		downloadURL, filename, foundVersion = findLibericaDownload(releases, version)
+	}
 
-	case "private":
-		releases, err := private.GetPrivateJDKs()
+	kind, err := utils.ParseImageType(imageType)
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, err.Error())
+	}
+	if kind != utils.ImageTypeJDK || javafx {
+		if allLTS || manifestPath != "" {
+			return utils.Fail(utils.ExitGeneric, "--type and --javafx cannot be combined with --all-lts or --manifest")
+		}
+		utils.SetImageVariant(kind, javafx)
+		if err := utils.CheckImageVariant(provider); err != nil {
+			return utils.Fail(utils.ExitNotFound, err.Error())
+		}
+	}
+
+	if earlyAccess {
+		if allLTS || manifestPath != "" {
+			return utils.Fail(utils.ExitGeneric, "--ea cannot be combined with --all-lts or --manifest")
+		}
+		utils.SetIncludeEarlyAccess(true)
+	}
+
+	if stream {
+		if allLTS || manifestPath != "" || len(versions) > 1 {
+			return utils.Fail(utils.ExitGeneric, "--stream applies to a single version")
+		}
+		if resume || archiveName != "" || (keepArchive != nil && *keepArchive) {
+			return utils.Fail(utils.ExitGeneric, "--stream writes no archive: it cannot be combined with --resume, --archive-name or --keep-archive")
+		}
+	}
+
+	if allLTS && resolveOnly {
+		return utils.Fail(utils.ExitGeneric, "--resolve-only cannot be combined with --all-lts")
+	}
+
+	if expectedChecksum != "" {
+		if allLTS {
+			return utils.Fail(utils.ExitGeneric, "--checksum cannot be combined with --all-lts")
+		}
+		if checksumAlgorithm == "" && utils.DetectChecksumAlgorithm(expectedChecksum) == "" {
+			return utils.Fail(utils.ExitGeneric, "Invalid --checksum: expected a SHA-256 (64 hex chars) or SHA-512 (128 hex chars) value")
+		}
+	}
+
+	if extractTo != "" {
+		if allLTS || manifestPath != "" {
+			return utils.Fail(utils.ExitGeneric, "--extract-to cannot be combined with --all-lts or --manifest")
+		}
+		absExtractTo, err := filepath.Abs(extractTo)
 		if err != nil {
-			fmt.Printf("[ERROR] Failed to fetch releases from %s: %v\n", provider, err)
-			return
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Invalid --extract-to: %v", err))
 		}
-		downloadURL, filename, foundVersion = findPrivateDownload(releases, version)
+		extractTo = absExtractTo
+	}
 
-	default:
-		fmt.Printf("[ERROR] Unknown provider: %s\n", provider)
-		fmt.Println("[INFO] Available providers: adoptium, azul, liberica, private")
-		return
+	if manifestPath != "" {
+		if version != "" || allLTS {
+			return utils.Fail(utils.ExitGeneric, "--manifest cannot be combined with a version or --all-lts")
+		}
+		if resolveOnly {
+			return utils.Fail(utils.ExitGeneric, "--resolve-only cannot be combined with --manifest")
+		}
+		if expectedChecksum != "" || checksumAlgorithm != "" {
+			return utils.Fail(utils.ExitGeneric, "--checksum options cannot be combined with --manifest: the manifest pins SHA-256 hashes")
+		}
+		return downloadManifest(manifestPath, outputDir, flatten, assumeYes, keepArchive)
+	}
+
+	if allLTS {
+		return downloadAllLTS(provider, outputDir, flatten, assumeYes, keepArchive, checksumAlgorithm)
+	}
+
+	if len(versions) > 1 {
+		if resolveOnly || expectedChecksum != "" || extractTo != "" || archiveName != "" {
+			return utils.Fail(utils.ExitGeneric, "--resolve-only, --checksum, --extract-to and --archive-name apply to a single version")
+		}
+		return downloadVersions(versions, provider, outputDir, flatten, assumeYes, keepArchive, checksumAlgorithm, progress)
 	}
 
+	fmt.Printf("%s Searching for JDK version %s from provider: %s\n",
+		utils.ColorText("[>]", utils.BrightCyan), version, provider)
+	if !resolveOnly {
+		fmt.Printf("%s Download directory: %s\n\n",
+			utils.ColorText("[>]", utils.BrightCyan), outputDir)
+
+		// Create output directory if it doesn't exist
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to create output directory: %v", err))
+		}
+	}
+
+	// Get JDK releases from the specified provider and find matching version
+	findRelease, err := fetchReleaseFinder(provider)
+	if err != nil {
+		fmt.Printf("[ERROR] %v\n", err)
+		if errors.Is(err, errUnknownProvider) {
+			fmt.Println("[INFO] Available providers: adoptium, azul, liberica, graalvm, private")
+			return utils.ExitWith(utils.ExitNotFound, err)
+		}
+		if errors.Is(err, private.ErrNotConfigured) {
+			fmt.Println("[INFO] Run 'jenvy configure-private <endpoint>' to configure one")
+			return utils.ExitWith(utils.ExitNotFound, err)
+		}
+		printProviderFailureHint(provider, err)
+		return utils.ExitWith(utils.ExitNetwork, err)
+	}
+	release := findRelease(version)
+	if expectedChecksum != "" && release.URL != "" {
+		release.Checksum, release.ChecksumAlgorithm = expectedChecksum, ""
+	}
+	downloadURL, filename, foundVersion := release.URL, release.Filename, release.Version
+
 	if downloadURL == "" {
 		fmt.Printf("[ERROR] JDK version %s not found in %s provider\n", version, provider)
 		fmt.Println("[INFO] Try running 'jenvy remote-list' to see available versions")
-		return
+		if !earlyAccess && earlyAccessProviders[provider] {
+			fmt.Println("[INFO] Upcoming JDKs are available as early-access builds with --ea")
+		}
+		if archFlag == "" && utils.NativeArch() == utils.ArchAArch64 {
+			fmt.Printf("[INFO] Not every JDK has a %s ARM build: --arch=x64 downloads one that runs under emulation\n", utils.HostOSName())
+		}
+		return utils.ExitWith(utils.ExitNotFound, fmt.Errorf("JDK version %s not found", version))
+	}
+	if release.EarlyAccess {
+		utils.PrintWarning(fmt.Sprintf("JDK %s is an early-access build: for testing only, not for production", foundVersion))
 	}
 
 	if filename == "" {
-		filename = fmt.Sprintf("openjdk-%s.tar.gz", version)
+		filename = fmt.Sprintf("openjdk-%s.tar.gz", foundVersion)
 	}
 
-	// Create a version-specific subdirectory
-	versionDir := fmt.Sprintf("JDK-%s", foundVersion)
+	// Rinomina l'archivio secondo il pattern richiesto (--archive-name)
+	if archiveName != "" {
+		runtimeInfo := getRuntimeInfo()
+		customName, err := utils.FormatArchiveName(archiveName, provider, foundVersion, runtimeInfo.OS, runtimeInfo.Arch, filename)
+		if err != nil {
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Invalid --archive-name: %v", err))
+		}
+		filename = customName
+	}
+
+	if verbose && utils.IsGitHubURL(downloadURL) {
+		printGitHubAuthStatus()
+	}
+
+	if resolveOnly {
+		printResolvedRelease(release, provider, filename)
+		return nil
+	}
+
+	// Create a version-specific subdirectory named after the configured scheme, with the variant suffixes
+	versionDir := utils.VariantInstallDirName(utils.InstallNamingScheme(), provider, foundVersion)
 	versionOutputDir := filepath.Join(outputDir, versionDir)
 
-	// Create version-specific directory
+	// Con --stream l'archivio va direttamente nella directory di installazione, se possibile
+	if stream {
+		streamDir := versionOutputDir
+		if extractTo != "" {
+			streamDir = filepath.Join(extractTo, versionDir)
+		}
+		if reason := streamUnsupportedReason(release, filename); reason != "" {
+			utils.PrintInfo(fmt.Sprintf("Streaming is not possible (%s): the archive is downloaded first", reason))
+			stream = false
+		} else {
+			fmt.Printf("%s JDK %s\n", utils.ColorText("[FOUND]", utils.BrightGreen), foundVersion)
+			fmt.Printf("%s Download URL: %s\n", utils.ColorText("[URL]", utils.BrightBlue), downloadURL)
+			fmt.Printf("%s Install directory: %s\n", utils.ColorText("[DIR]", utils.BrightYellow), streamDir)
+			if !assumeYes && !askConfirmation("\n[?] Do you want to proceed with the download? (y/N): ") {
+				utils.PrintInfo("Download cancelled by user")
+				return utils.ExitWith(utils.ExitGeneric, errors.New("download cancelled by user"))
+			}
+			fmt.Println()
+			return installStreamed(release, provider, filename, versionDir, streamDir, flatten, extractTo != "", progress, checksumAlgorithm)
+		}
+	}
+
+	// Create version-specific directory (removed again if the download is interrupted)
+	_, statErr := os.Stat(versionOutputDir)
+	createdDir := os.IsNotExist(statErr)
 	if err := os.MkdirAll(versionOutputDir, 0755); err != nil {
 		fmt.Printf("[ERROR] Failed to create version directory: %v\n", err)
-		return
+		return utils.ExitWith(utils.ExitGeneric, err)
 	}
 
 	outputPath := filepath.Join(versionOutputDir, filename)
@@ -369,25 +648,44 @@ func DownloadJDK(defaultProvider string) {
 	// Check if file already exists
 	if _, err := os.Stat(outputPath); err == nil {
 		utils.PrintWarning(fmt.Sprintf("File already exists: %s", filename))
+	} else if info, err := os.Stat(outputPath + partialDownloadSuffix); err == nil {
+		utils.PrintInfo(fmt.Sprintf("Partial download found (%.2f MB): it will be resumed", float64(info.Size())/1024/1024))
 	}
 
 	// Ask for confirmation
-	fmt.Print("\n[?] Do you want to proceed with the download? (y/N): ")
-	var response string
-	fmt.Scanln(&response)
-
-	response = strings.ToLower(strings.TrimSpace(response))
-	if response != "y" && response != "yes" {
-		utils.PrintInfo("Download cancelled by user")
-		return
+	if !assumeYes {
+		fmt.Print("\n[?] Do you want to proceed with the download? (y/N): ")
+		var response string
+		fmt.Scanln(&response)
+
+		response = strings.ToLower(strings.TrimSpace(response))
+		if response != "y" && response != "yes" {
+			utils.PrintInfo("Download cancelled by user")
+			return utils.ExitWith(utils.ExitGeneric, errors.New("download cancelled by user"))
+		}
 	}
 
 	fmt.Println()
 
-	// Download the file
-	if err := downloadFile(downloadURL, outputPath); err != nil {
-		utils.PrintError(fmt.Sprintf("Download failed: %v", err))
-		return
+	// Download the file: Ctrl+C cancels the transfer instead of killing the process, so nothing partial is left
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err = downloadFile(ctx, downloadURL, outputPath, progress, nil, resume)
+	interrupted := ctx.Err() != nil // Letto prima di stop, che annulla sempre il contesto
+	stop()
+	if err != nil {
+		if interrupted {
+			return discardInterruptedDownload(outputPath, versionOutputDir, createdDir)
+		}
+		notifyCompletion("Download", "JDK "+foundVersion, err)
+		return utils.Fail(utils.ExitNetwork, fmt.Sprintf("Download failed: %v", err))
+	}
+
+	if err := verifyDownloadedArchive(outputPath, release, checksumAlgorithm); err != nil {
+		utils.PrintError(fmt.Sprintf("Integrity check failed: %v", err))
+		os.Remove(outputPath)
+		utils.PrintInfo("The corrupted archive has been deleted, please retry the download")
+		notifyCompletion("Download", "JDK "+foundVersion, err)
+		return utils.ExitWith(utils.ExitExtraction, err)
 	}
 
 	utils.PrintSuccess("Download completed successfully!")
@@ -406,27 +704,70 @@ func DownloadJDK(defaultProvider string) {
 	utils.PrintInfo(fmt.Sprintf("Location: %s", outputPath))
 	fmt.Println()
 
-	// Ask if user wants to extract the archive automatically
-	fmt.Print("[?] Do you want to extract the archive now? (Y/n): ")
+	// Con --extract-to la directory di versione resta solo la cache dell'archivio
+	installDir := versionOutputDir
+	if extractTo != "" {
+		installDir = filepath.Join(extractTo, versionDir)
+		if err := os.MkdirAll(installDir, 0755); err != nil {
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to create extraction directory: %v", err))
+		}
+	}
+
+	// Registra l'origine dell'installazione; l'hash dei file viene aggiunto dopo l'estrazione
+	meta := newDownloadMetadata(provider, release, filename, checksumAlgorithm)
+	if err := utils.SaveInstallMetadata(installDir, meta); err != nil {
+		utils.PrintWarning(fmt.Sprintf("Could not save installation metadata: %v", err))
+	}
+
+	// L'archivio per un altro sistema resta intatto: non si estrae né si attiva su questo sistema
+	if utils.IsForeignOS(utils.TargetOS()) {
+		utils.PrintInfo(fmt.Sprintf("This is a %s archive: it is kept as downloaded and will not be extracted or activated", utils.TargetOS()))
+		utils.PrintInfo(fmt.Sprintf("Copy %s to the target machine or reference it from your Dockerfile", outputPath))
+		notifyCompletion("Download", "JDK "+foundVersion, nil)
+		return nil
+	}
+
+	// Ask if user wants to extract the archive automatically (--extract-to implies it)
 	var extractResponse string
-	fmt.Scanln(&extractResponse)
+	if !assumeYes && extractTo == "" {
+		fmt.Print("[?] Do you want to extract the archive now? (Y/n): ")
+		fmt.Scanln(&extractResponse)
+	}
 
+	var extractErr error
 	extractResponse = strings.ToLower(strings.TrimSpace(extractResponse))
 	if extractResponse == "" || extractResponse == "y" || extractResponse == "yes" {
 		fmt.Println()
 		utils.PrintInfo("Starting extraction...")
 
 		// Extract using the same logic as extract command with intelligent parsing
-		if err := extractJDKArchive(versionDir, versionOutputDir); err != nil {
+		if extractTo != "" {
+			err = extractArchive(outputPath, installDir, flatten, progress)
+		} else {
+			err = extractJDKArchive(versionDir, versionOutputDir, flatten, progress)
+		}
+		if err != nil {
 			utils.PrintError(fmt.Sprintf("Extraction failed: %v", err))
 			utils.PrintInfo("You can manually extract later using:")
 			utils.PrintInfo(fmt.Sprintf("  jenvy extract %s", versionDir))
+			extractErr = utils.ExitWith(extractionExitCode(err), err)
 		} else {
+			recordInstallManifest(installDir)
+			reportNativeImage(installDir)
+			if extractTo != "" {
+				if err := utils.RegisterExternalInstall(installDir); err != nil {
+					utils.PrintWarning(fmt.Sprintf("Could not register %s: 'use' and 'list' will not find it (%v)", installDir, err))
+				}
+			}
+			if !shouldKeepArchive(keepArchive, !assumeYes) {
+				removeExtractedArchive(outputPath)
+			}
 			utils.PrintSuccess("JDK extracted successfully!")
-			utils.PrintInfo(fmt.Sprintf("JDK ready at: %s", versionOutputDir))
+			utils.PrintInfo(fmt.Sprintf("JDK ready at: %s", installDir))
 			fmt.Println()
 			utils.PrintInfo("To activate this JDK, use:")
 			utils.PrintInfo(fmt.Sprintf("  jenvy use %s", versionDir))
+			followDefaultJDK(installDir)
 		}
 	} else {
 		utils.PrintWarning("Archive not extracted. To extract manually, use:")
@@ -438,6 +779,42 @@ func DownloadJDK(defaultProvider string) {
 	utils.PrintInfo("  jenvy extract <archive>        # Extract the downloaded archive")
 	utils.PrintInfo("  jenvy list                     # View installed JDKs")
 	utils.PrintInfo("  jenvy use <version>            # Set JDK as active")
+	notifyCompletion("Download", "JDK "+foundVersion, extractErr)
+	return extractErr
+}
+
+// discardInterruptedDownload rimuove quanto lasciato da un download interrotto con Ctrl+C.
+//
+// A differenza di una connessione caduta, un'interruzione voluta non va ripresa:
+// il file .part viene eliminato, e con esso la directory di versione se è stata
+// creata da questa esecuzione. Restituisce l'errore con utils.ExitInterrupted.
+func discardInterruptedDownload(outputPath, versionOutputDir string, createdDir bool) error {
+	os.Remove(outputPath + partialDownloadSuffix)
+	if createdDir {
+		os.RemoveAll(versionOutputDir)
+	}
+	fmt.Println()
+	utils.PrintWarning("Download interrupted: partial files have been removed")
+	return utils.ExitWith(utils.ExitInterrupted, errors.New("download interrupted"))
+}
+
+// newDownloadMetadata descrive l'origine di un'installazione di 'jenvy download', varianti comprese (--type, --javafx, --arch, --os).
+func newDownloadMetadata(provider string, release downloadRelease, archiveName, checksumAlgorithm string) *utils.InstallMetadata {
+	meta := &utils.InstallMetadata{
+		Provider:    provider,
+		Version:     release.Version,
+		DownloadURL: release.URL,
+		ArchiveName: archiveName,
+		InstalledAt: time.Now().Format(time.RFC3339),
+		ImageType:   utils.ImageType(),
+		JavaFX:      utils.JavaFX(),
+		Arch:        utils.TargetArch(),
+	}
+	meta.Checksum, meta.ChecksumAlgorithm = verifiedChecksum(release, checksumAlgorithm)
+	if utils.IsForeignOS(utils.TargetOS()) {
+		meta.OS = utils.TargetOS()
+	}
+	return meta
 }
 
 // downloadFile scarica un file da URL con indicatore di progresso e gestione robusta degli errori.
@@ -447,30 +824,38 @@ func DownloadJDK(defaultProvider string) {
 // di rete. Progettata per fornire feedback continuo all'utente durante operazioni lunghe.
 //
 // Caratteristiche del download:
-// 1. **Client HTTP configurato**: Timeout di 30 minuti per file grandi
+// 1. **Client HTTP configurato**: Timeout di 30 minuti per file grandi (--timeout lo sostituisce)
 // 2. **Headers appropriati**: User-Agent personalizzato per identificazione
 // 3. **Validazione response**: Verifica status code prima di procedere
 // 4. **Progress tracking**: Indicatore percentuale con velocità in tempo reale
 // 5. **Buffer ottimizzato**: 32KB buffer per performance bilanciata
 // 6. **Gestione errori**: Recovery graceful da interruzioni di rete
 //
-// Indicatore di progresso:
-//   - Percentuale completamento se Content-Length disponibile
-//   - Velocità download in MB/s in tempo reale
-//   - Dimensioni scaricate vs totali
+// Indicatore di progresso (utils.Progress):
+//   - Barra, percentuale e tempo stimato se Content-Length disponibile
+//   - Dimensioni scaricate vs totali e velocità, con unità adattiva
 //   - Fallback a solo dimensione scaricata se lunghezza sconosciuta
-//   - Aggiornamento in tempo reale (refresh continuo della stessa linea)
+//   - utils.ProgressBar: Aggiornamento in tempo reale (refresh continuo della stessa linea)
+//   - utils.ProgressLines: Una riga completa ogni 5 secondi, leggibile nei log CI
 //
 // Esempio output progresso:
 //
-//	[DOWNLOAD] Progress: 45.2% (125.4 MB / 277.8 MB) - Speed: 8.3 MB/s
-//	[DOWNLOAD] Downloaded: 125.4 MB - Speed: 8.3 MB/s (se size sconosciuto)
+//	[DOWNLOAD] [█████████░░░░░░░░░░░░░░░]  45.2%  125.4 MB / 277.8 MB  8.3 MB/s  ETA 0:18
+//	[DOWNLOAD] 125.4 MB  8.3 MB/s                           (se size sconosciuto)
+//	[DOWNLOAD] 45% (125.4 MB / 277.8 MB), 8.3 MB/s, ETA 0:18 (utils.ProgressLines)
 //
 // Gestione timeout e resilienza:
-//   - Timeout download: 30 minuti (appropriato per JDK fino a 300MB)
+//   - Timeout download: utils.DefaultDownloadTimeout (30 minuti) o --timeout
 //   - Timeout connection implicito nel http.Client
-//   - Retry non implementato (operazione one-shot per semplicità)
+//   - Nuovi tentativi con --retries se la richiesta iniziale fallisce (utils.DoWithRetry)
+//   - Il file viene scritto in <file>.part e rinominato solo a download completo: un
+//     .part lasciato da un'interruzione viene ripreso con una richiesta HTTP Range
+//     alla chiamata successiva (vedi downloadPart)
+//   - Con resume=true ('download --resume') un'interruzione durante il trasferimento
+//     viene ripresa subito, fino a maxResumeAttempts volte
 //   - Gestione disconnessioni di rete con errori informativi
+//   - Gli URL dei provider con un mirror configurato (es. adoptium-download-mirror)
+//     vengono riscritti verso il mirror (vedi utils.MirrorURL)
 //
 // Sicurezza:
 //   - User-Agent custom per identificazione legittima
@@ -480,8 +865,13 @@ func DownloadJDK(defaultProvider string) {
 //
 // Parametri:
 //
-//	url string      - URL completo del file da scaricare
-//	filepath string - Percorso locale assoluto dove salvare il file
+//	ctx context.Context         - Annullandolo si interrompe il download (es. Ctrl-C in 'jenvy upgrade --all')
+//	url string                  - URL completo del file da scaricare
+//	filepath string             - Percorso locale assoluto dove salvare il file
+//	progress utils.ProgressMode - Stile del progresso già risolto (utils.ProgressNone per download
+//	                              concorrenti, dove le righe di progresso si sovrapporrebbero)
+//	shared *utils.Progress      - Progresso comune a cui sommare i byte di più download paralleli (nil se nessuno)
+//	resume bool                 - true per riprendere subito i trasferimenti interrotti (--resume)
 //
 // Restituisce:
 //
@@ -489,7 +879,7 @@ func DownloadJDK(defaultProvider string) {
 //
 // Errori possibili:
 //   - Errore creazione request HTTP
-//   - Timeout durante download (30 min)
+//   - Timeout durante download (30 min o --timeout)
 //   - Server response non-200 (file non trovato, accesso negato, etc.)
 //   - Errore creazione file locale (permessi, spazio disco)
 //   - Interruzione connessione durante trasferimento
@@ -503,98 +893,193 @@ func DownloadJDK(defaultProvider string) {
 //
 // Esempio di utilizzo:
 //
-//	err := downloadFile("https://adoptium.net/...jdk-17.zip", "C:/Users/user/.jenvy/versions/JDK-17/jdk.zip")
+//	err := downloadFile(context.Background(), "https://adoptium.net/...jdk-17.zip", "C:/Users/user/.jenvy/versions/JDK-17/jdk.zip", utils.ProgressBar, nil, false)
 //	if err != nil {
 //	    log.Printf("Download failed: %v", err)
 //	}
-func downloadFile(url, filepath string) error {
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: time.Minute * 30, // 30 minutes timeout for large files
+func downloadFile(ctx context.Context, url, filepath string, progress utils.ProgressMode, shared *utils.Progress, resume bool) error {
+	if mirrored := utils.MirrorURL(url); mirrored != url {
+		utils.PrintInfo(fmt.Sprintf("Downloading from mirror: %s", mirrored))
+		url = mirrored
+	}
+	partPath := filepath + partialDownloadSuffix
+	for attempt := 1; ; attempt++ {
+		err := downloadPart(ctx, url, partPath, progress, shared)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, errTransferInterrupted) || ctx.Err() != nil {
+			return err
+		}
+		info, statErr := os.Stat(partPath)
+		if statErr != nil || info.Size() == 0 {
+			return err
+		}
+		if !resume || attempt > maxResumeAttempts {
+			utils.PrintInfo(fmt.Sprintf("Partial download kept (%.2f MB): run the same command again to resume it", float64(info.Size())/1024/1024))
+			return err
+		}
+		utils.PrintWarning(fmt.Sprintf("%v, resuming from %.2f MB (%d/%d)", err, float64(info.Size())/1024/1024, attempt, maxResumeAttempts))
+		select {
+		case <-time.After(resumeDelay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 
-	// Create the request
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return fmt.Errorf("creating request: %w", err)
+	if err := os.Rename(partPath, filepath); err != nil {
+		return fmt.Errorf("finalizing download: %w", err)
 	}
+	return nil
+}
+
+// partialDownloadSuffix è l'estensione del file in corso di download, rinominato solo a trasferimento completo.
+//
+// Non essendo un archivio riconosciuto (utils.IsArchiveFile), un download interrotto
+// non viene mai scambiato per un archivio da estrarre.
+const partialDownloadSuffix = ".part"
 
-	// Set user agent
-	req.Header.Set("User-Agent", "Jenvy-Manager/1.0")
+// maxResumeAttempts è il numero di riprese consecutive tentate da 'download --resume'.
+const maxResumeAttempts = 5
+
+// resumeDelay è l'attesa prima di riprendere un trasferimento interrotto.
+const resumeDelay = 2 * time.Second
+
+// errTransferInterrupted indica una connessione caduta durante il trasferimento del corpo.
+//
+// È l'unico errore per cui ha senso riprendere con una richiesta Range: gli
+// errori HTTP e di connessione iniziale sono già gestiti da utils.DoWithRetry.
+var errTransferInterrupted = errors.New("transfer interrupted")
+
+// downloadPart scarica (o completa) il file parziale partPath.
+//
+// Se partPath esiste già chiede al server solo i byte mancanti (Range:
+// bytes=<dimensione>-). Un server che ignora il Range risponde 200 con il file
+// intero: il parziale viene allora riscritto da capo. Una risposta 416 indica un
+// parziale non coerente con il file remoto, che viene scartato; lo stesso vale
+// per una risposta 206 il cui Content-Range non inizia dalla dimensione del parziale.
+//
+// Se shared non è nil vi somma i byte di questo trasferimento: il suo totale
+// cresce della parte mancante all'avvio e, in caso di errore, ne viene tolta la
+// quota non ricevuta, così la percentuale resta coerente con i download riusciti.
+func downloadPart(ctx context.Context, url, partPath string, progress utils.ProgressMode, shared *utils.Progress) (err error) {
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
+	}
+
+	// Create HTTP client with timeout (--timeout overrides the default)
+	client := utils.NewHTTPClient(utils.DefaultDownloadTimeout)
+	req, err := newDownloadRequest(ctx, url)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
 
-	// Send request
-	resp, err := client.Do(req)
+	// Send request, retrying transient failures (--retries)
+	resp, err := utils.DoWithRetry(client, req)
 	if err != nil {
 		return fmt.Errorf("downloading file: %w", err)
 	}
 	defer resp.Body.Close()
 
-	// Check response status
-	if resp.StatusCode != http.StatusOK {
+	flags := os.O_CREATE | os.O_WRONLY
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		// Un intervallo diverso da quello richiesto corromperebbe il file una volta accodato
+		if start, ok := utils.ContentRangeStart(resp.Header.Get("Content-Range")); !ok || start != offset {
+			os.Remove(partPath)
+			return fmt.Errorf("%w: the server sent range %q instead of bytes %d-: the partial download was discarded", errTransferInterrupted, resp.Header.Get("Content-Range"), offset)
+		}
+		flags |= os.O_APPEND
+		if progress != utils.ProgressNone {
+			utils.PrintInfo(fmt.Sprintf("Resuming partial download from %.2f MB", float64(offset)/1024/1024))
+		}
+	case resp.StatusCode == http.StatusOK:
+		if offset > 0 && progress != utils.ProgressNone {
+			utils.PrintInfo("The server does not support resuming: restarting the download")
+		}
+		flags |= os.O_TRUNC
+		offset = 0
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		os.Remove(partPath)
+		return fmt.Errorf("%w: the partial download does not match the remote file and was discarded", errTransferInterrupted)
+	default:
 		return fmt.Errorf("server returned status %d: %s", resp.StatusCode, resp.Status)
 	}
 
-	// Create the output file
-	out, err := os.Create(filepath)
+	out, err := os.OpenFile(partPath, flags, 0644)
 	if err != nil {
 		return fmt.Errorf("creating file: %w", err)
 	}
 	defer out.Close()
 
-	// Get content length for progress tracking
+	// Get content length for progress tracking (the whole file, also when resuming)
 	contentLength := resp.ContentLength
-	var downloaded int64
+	if contentLength > 0 {
+		contentLength += offset
+	}
+	downloaded := offset
 
 	// Create a buffer for copying
 	buffer := make([]byte, 32*1024) // 32KB buffer
 
-	fmt.Println("[DOWNLOAD] Downloading...")
-	startTime := time.Now()
+	if progress != utils.ProgressNone {
+		fmt.Println("[DOWNLOAD] Downloading...")
+	}
+	bar := utils.NewProgress(progress, "[DOWNLOAD]", contentLength, utils.ProgressBytes)
+	bar.Resume(offset)
+	if resp.ContentLength > 0 {
+		shared.Expect(resp.ContentLength)
+		defer func() {
+			if err != nil && downloaded-offset < resp.ContentLength {
+				shared.Expect(downloaded - offset - resp.ContentLength)
+			}
+		}()
+	}
 
 	for {
 		n, err := resp.Body.Read(buffer)
 		if n > 0 {
 			if _, writeErr := out.Write(buffer[:n]); writeErr != nil {
+				bar.Abort()
 				return fmt.Errorf("writing to file: %w", writeErr)
 			}
 			downloaded += int64(n)
-
-			// Show progress if we know the content length
-			if contentLength > 0 {
-				progress := float64(downloaded) / float64(contentLength) * 100
-				elapsed := time.Since(startTime)
-				speed := float64(downloaded) / elapsed.Seconds() / 1024 / 1024 // MB/s
-
-				fmt.Printf("\r[DOWNLOAD] Progress: %.1f%% (%.2f MB / %.2f MB) - Speed: %.2f MB/s",
-					progress,
-					float64(downloaded)/1024/1024,
-					float64(contentLength)/1024/1024,
-					speed,
-				)
-			} else {
-				// Show downloaded amount without percentage
-				elapsed := time.Since(startTime)
-				speed := float64(downloaded) / elapsed.Seconds() / 1024 / 1024 // MB/s
-
-				fmt.Printf("\r[DOWNLOAD] Downloaded: %.2f MB - Speed: %.2f MB/s",
-					float64(downloaded)/1024/1024,
-					speed,
-				)
-			}
+			bar.Add(int64(n))
+			shared.Add(int64(n))
 		}
 
 		if err != nil {
 			if err == io.EOF {
 				break
 			}
-			return fmt.Errorf("reading response: %w", err)
+			bar.Abort()
+			return fmt.Errorf("%w: %v", errTransferInterrupted, err)
 		}
 	}
 
-	fmt.Println() // New line after progress
+	bar.Finish()
+	if contentLength > 0 && downloaded < contentLength {
+		return fmt.Errorf("%w: received %d of %d bytes", errTransferInterrupted, downloaded, contentLength)
+	}
 	return nil
 }
 
+// newDownloadRequest prepara la richiesta GET di un archivio, con User-Agent e credenziali GitHub o del repository privato.
+func newDownloadRequest(ctx context.Context, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", utils.UserAgent)
+	utils.AuthorizeGitHubRequest(req)
+	utils.AuthorizePrivateRequest(req)
+	return req, nil
+}
+
 // getDefaultDownloadDir determina e restituisce la directory di download predefinita per JDK su Windows.
 //
 // Questa funzione costruisce il percorso standardizzato dove Jenvy organizza tutti i JDK scaricati,
@@ -611,8 +1096,8 @@ func downloadFile(url, filepath string) error {
 //	└── JDK-8.0.392\         # Versione legacy
 //
 // Processo di determinazione:
-// 1. **Rilevamento utente corrente**: Usa user.Current() per ottenere info utente
-// 2. **Costruzione percorso**: Combina HomeDir + ".jenvy" + "versions"
+// 1. **Rilevamento home**: Usa utils.JenvyHome() (user.Current, os.UserHomeDir, USERPROFILE/HOME)
+// 2. **Costruzione percorso**: Combina la directory Jenvy + "versions"
 // 3. **Normalizzazione path**: Usa filepath.Join per compatibilità Windows
 // 4. **Validazione**: Verifica accessibilità directory home
 //
@@ -657,13 +1142,7 @@ func downloadFile(url, filepath string) error {
 //   - Riferimento per comando use nella selezione versioni
 //   - Base per comando remove per identificazione target
 func getDefaultDownloadDir() (string, error) {
-	currentUser, err := user.Current()
-	if err != nil {
-		return "", fmt.Errorf("getting current user: %w", err)
-	}
-
-	jenvyDir := filepath.Join(currentUser.HomeDir, ".jenvy", "versions")
-	return jenvyDir, nil
+	return utils.GetJenvyVersionsDirectory()
 }
 
 // findAdoptiumDownload ricerca e seleziona il miglior download JDK da releases Eclipse Adoptium.
@@ -733,44 +1212,30 @@ func findAdoptiumDownload(releases []adoptium.AdoptiumResponse, version string)
 	runtime := getRuntimeInfo()
 
 	// Parse target version
-	targetMajor, targetMinor, targetPatch := utils.ParseVersionNumber(version)
+	target := utils.ParseVersionRequest(version)
 
 	var bestMatch adoptium.AdoptiumResponse
 	var bestBinary struct {
 		OS      string `json:"os"`
 		Arch    string `json:"architecture"`
 		Package struct {
-			Link string `json:"link"`
+			Link     string `json:"link"`
+			Checksum string `json:"checksum"`
 		} `json:"package"`
 	}
 	var found bool
 
 	// Search for matches with proper version parsing
 	for _, release := range releases {
-		releaseVersion := release.VersionData.OpenJDKVersion
-		major, minor, patch := adoptium.ParseVersion(releaseVersion)
-
-		// Check if this version matches our target
-		isMatch := false
-		if targetMinor == -1 && targetPatch == -1 {
-			// Only major version specified (e.g., "17" -> match any 17.x.y)
-			isMatch = (major == targetMajor)
-		} else if targetPatch == -1 {
-			// Major.minor specified (e.g., "17.0" -> match any 17.0.x)
-			isMatch = (major == targetMajor && minor == targetMinor)
-		} else {
-			// Full version specified (e.g., "17.0.5" -> exact match)
-			isMatch = (major == targetMajor && minor == targetMinor && patch == targetPatch)
-		}
-
-		if !isMatch {
+		// Versione già scomposta al caricamento dell'elenco
+		if !release.Satisfies(target) {
 			continue
 		}
 
 		// Find the best binary for this release (prefer current OS/arch)
 		for _, binary := range release.Binaries {
 			if binary.OS == runtime.OS && binary.Arch == runtime.Arch {
-				if !found || shouldPreferVersion(releaseVersion, bestMatch.VersionData.OpenJDKVersion) {
+				if !found || shouldPreferVersion(release.ReleaseVersion, bestMatch.ReleaseVersion) {
 					bestMatch = release
 					bestBinary = binary
 					found = true
@@ -780,9 +1245,10 @@ func findAdoptiumDownload(releases []adoptium.AdoptiumResponse, version string)
 		}
 
 		// If no perfect match, try any compatible binary
+		// (mai di un'altra architettura: con --arch un JDK diverso sarebbe inutilizzabile)
 		if !found {
 			for _, binary := range release.Binaries {
-				if strings.Contains(binary.Package.Link, ".zip") {
+				if binary.Arch == runtime.Arch && strings.Contains(binary.Package.Link, utils.TargetArchiveExtension()) {
 					bestMatch = release
 					bestBinary = binary
 					found = true
@@ -874,7 +1340,7 @@ func findAzulDownload(releases []azul.AzulPackage, version string) (string, stri
 	runtime := getRuntimeInfo()
 
 	// Parse target version
-	targetMajor, targetMinor, targetPatch := utils.ParseVersionNumber(version)
+	target := utils.ParseVersionRequest(version)
 
 	var bestMatch azul.AzulPackage
 	var found bool
@@ -885,35 +1351,13 @@ func findAzulDownload(releases []azul.AzulPackage, version string) (string, stri
 			continue
 		}
 
-		major := release.JavaVersion[0]
-		minor := 0
-		if len(release.JavaVersion) > 1 {
-			minor = release.JavaVersion[1]
-		}
-		patch := 0
-		if len(release.JavaVersion) > 2 {
-			patch = release.JavaVersion[2]
-		}
-
-		// Check if this version matches our target
-		isMatch := false
-		if targetMinor == -1 && targetPatch == -1 {
-			// Only major version specified (e.g., "17" -> match any 17.x.y)
-			isMatch = (major == targetMajor)
-		} else if targetPatch == -1 {
-			// Major.minor specified (e.g., "17.0" -> match any 17.0.x)
-			isMatch = (major == targetMajor && minor == targetMinor)
-		} else {
-			// Full version specified (e.g., "17.0.5" -> exact match)
-			isMatch = (major == targetMajor && minor == targetMinor && patch == targetPatch)
-		}
-
-		if !isMatch {
+		// Versione già scomposta al caricamento dell'elenco
+		if !release.Satisfies(target) {
 			continue
 		}
 
 		// Check if compatible with our platform or is a zip file
-		if strings.Contains(strings.ToLower(release.Name), runtime.OS) || strings.HasSuffix(release.DownloadURL, ".zip") {
+		if strings.Contains(strings.ToLower(release.Name), runtime.OS) || strings.HasSuffix(release.DownloadURL, utils.TargetArchiveExtension()) {
 			bestMatch = release
 			found = true
 			break // Take the first match for now
@@ -958,7 +1402,7 @@ func findAzulDownload(releases []azul.AzulPackage, version string) (string, stri
 //   - **Supporto ARM**: Eccellente supporto per architetture ARM
 //
 // Algoritmo di ricerca:
-// 1. **Parsing versione**: Usa liberica.ParseLibericaVersion() per formato specifico
+// 1. **Versione**: Usa la versione scomposta al caricamento (utils.ReleaseVersion)
 // 2. **Matching flessibile**: Supporta ricerche parziali standard
 // 3. **Primo match**: Strategia semplificata, primo compatible trovato
 // 4. **URL diretto**: Ritorna DownloadURL senza modifiche
@@ -973,29 +1417,15 @@ func findAzulDownload(releases []azul.AzulPackage, version string) (string, stri
 //	string - URL download, nome file, versione trovata
 func findLibericaDownload(releases []liberica.LibericaRelease, version string) (string, string, string) {
 	// Parse target version
-	targetMajor, targetMinor, targetPatch := utils.ParseVersionNumber(version)
+	target := utils.ParseVersionRequest(version)
 
 	var bestMatch liberica.LibericaRelease
 	var found bool
 
 	// Search for matches with proper version parsing
 	for _, release := range releases {
-		major, minor, patch := liberica.ParseLibericaVersion(release.Version)
-
-		// Check if this version matches our target
-		isMatch := false
-		if targetMinor == -1 && targetPatch == -1 {
-			// Only major version specified (e.g., "17" -> match any 17.x.y)
-			isMatch = (major == targetMajor)
-		} else if targetPatch == -1 {
-			// Major.minor specified (e.g., "17.0" -> match any 17.0.x)
-			isMatch = (major == targetMajor && minor == targetMinor)
-		} else {
-			// Full version specified (e.g., "17.0.5" -> exact match)
-			isMatch = (major == targetMajor && minor == targetMinor && patch == targetPatch)
-		}
-
-		if !isMatch {
+		// Versione già scomposta al caricamento dell'elenco
+		if !release.Satisfies(target) {
 			continue
 		}
 
@@ -1018,6 +1448,45 @@ func findLibericaDownload(releases []liberica.LibericaRelease, version string) (
 	return url, filename, bestMatch.Version
 }
 
+// findGraalVMDownload seleziona il pacchetto GraalVM più recente che soddisfa la versione richiesta.
+//
+// La versione confrontata è quella Java (es. "21.0.2+13"), non quella GraalVM
+// (es. "22.3.3" per le vecchie release CE basate su JDK 17): 'jenvy download 17
+// --provider=graalvm' trova quindi anche le release 22.x.
+//
+// Parametri:
+//
+//	releases []graalvm.GraalVMPackage - Pacchetti dell'edizione configurata
+//	version string                    - Versione target
+//
+// Restituisce:
+//
+//	string - URL download, nome file, versione Java trovata
+func findGraalVMDownload(releases []graalvm.GraalVMPackage, version string) (string, string, string) {
+	target := utils.ParseVersionRequest(version)
+
+	var bestMatch graalvm.GraalVMPackage
+	found := false
+	for _, release := range releases {
+		if !release.Satisfies(target) {
+			continue
+		}
+		if !found || shouldPreferVersion(release.ReleaseVersion, bestMatch.ReleaseVersion) {
+			bestMatch = release
+			found = true
+		}
+	}
+	if !found {
+		return "", "", ""
+	}
+
+	filename := bestMatch.Filename
+	if filename == "" {
+		filename = filepath.Base(bestMatch.DownloadURL())
+	}
+	return bestMatch.DownloadURL(), filename, bestMatch.JavaVersion
+}
+
 // findPrivateDownload ricerca downloads da repository privati configurati dall'utente.
 //
 // Gestisce repository JDK aziendali interni come Nexus, Artifactory o API custom,
@@ -1030,10 +1499,11 @@ func findLibericaDownload(releases []liberica.LibericaRelease, version string) (
 //   - **Controllo accesso**: Limitato a utenti autorizzati
 //
 // Algoritmo semplificato:
-// 1. **Parsing versione**: Split standard per major.minor.patch
+// 1. **Versione**: Usa la versione scomposta al caricamento (utils.ReleaseVersion)
 // 2. **Matching diretto**: Confronto stringhe di versione
-// 3. **Primo match**: Strategia rapida per ambienti controllati
-// 4. **URL validazione**: Cleanup parametri query se presenti
+// 3. **Architettura**: Scarta gli archivi non eseguibili (o diversi da --arch, se indicata)
+// 4. **Primo match**: Strategia rapida per ambienti controllati
+// 5. **URL validazione**: Cleanup parametri query se presenti
 //
 // Parametri:
 //
@@ -1044,56 +1514,29 @@ func findLibericaDownload(releases []liberica.LibericaRelease, version string) (
 //
 //	string - URL download privato, nome file, versione
 func findPrivateDownload(releases []private.PrivateRelease, version string) (string, string, string) {
+	runtime := getRuntimeInfo()
+
 	// Parse target version
-	targetMajor, targetMinor, targetPatch := utils.ParseVersionNumber(version)
+	target := utils.ParseVersionRequest(version)
 
 	var bestMatch private.PrivateRelease
 	var found bool
 
 	// Search for matches with proper version parsing
 	for _, release := range releases {
-		// Simple version parsing for private releases
-		versionParts := strings.Split(release.Version, ".")
-		if len(versionParts) == 0 {
-			continue
-		}
-
-		major, err := strconv.Atoi(versionParts[0])
-		if err != nil {
+		// Versione già scomposta al caricamento dell'elenco
+		if !release.Satisfies(target) {
 			continue
 		}
-
-		minor := 0
-		if len(versionParts) > 1 {
-			if m, err := strconv.Atoi(versionParts[1]); err == nil {
-				minor = m
-			}
-		}
-
-		patch := 0
-		if len(versionParts) > 2 {
-			if p, err := strconv.Atoi(versionParts[2]); err == nil {
-				patch = p
+		// Senza --arch basta un archivio eseguibile dal sistema (es. x64 su Windows ARM);
+		// gli archivi senza architettura dichiarata valgono per qualsiasi sistema
+		if release.Arch != "" {
+			arch, err := utils.ParseArch(release.Arch)
+			if err == nil && arch != runtime.Arch && (utils.ArchRequested() || !utils.ArchRunsOn(arch, runtime.Arch)) {
+				continue
 			}
 		}
 
-		// Check if this version matches our target
-		isMatch := false
-		if targetMinor == -1 && targetPatch == -1 {
-			// Only major version specified (e.g., "17" -> match any 17.x.y)
-			isMatch = (major == targetMajor)
-		} else if targetPatch == -1 {
-			// Major.minor specified (e.g., "17.0" -> match any 17.0.x)
-			isMatch = (major == targetMajor && minor == targetMinor)
-		} else {
-			// Full version specified (e.g., "17.0.5" -> exact match)
-			isMatch = (major == targetMajor && minor == targetMinor && patch == targetPatch)
-		}
-
-		if !isMatch {
-			continue
-		}
-
 		// Take the first match
 		bestMatch = release
 		found = true
@@ -1153,6 +1596,8 @@ func findPrivateDownload(releases []private.PrivateRelease, version string) (str
 //
 //	jdkDirName string - Nome directory JDK (es. "JDK-17.0.8+9")
 //	jdkPath string    - Percorso completo directory JDK
+//	flatten bool      - false per preservare la struttura originale (--no-flatten)
+//	progress utils.ProgressMode - Stile del progresso già risolto (utils.ProgressNone per estrazioni concorrenti)
 //
 // Restituisce:
 //
@@ -1160,8 +1605,8 @@ func findPrivateDownload(releases []private.PrivateRelease, version string) (str
 //
 // Esempio di utilizzo:
 //
-//	err := extractJDKArchive("JDK-17.0.8+9", "/home/user/.jenvy/versions/JDK-17.0.8+9")
-func extractJDKArchive(jdkDirName, jdkPath string) error {
+//	err := extractJDKArchive("JDK-17.0.8+9", "/home/user/.jenvy/versions/JDK-17.0.8+9", true, utils.ProgressBar)
+func extractJDKArchive(jdkDirName, jdkPath string, flatten bool, progress utils.ProgressMode) error {
 	// Find archive in directory
 	archivePath, err := findArchiveInDirectory(jdkPath)
 	if err != nil {
@@ -1169,9 +1614,268 @@ func extractJDKArchive(jdkDirName, jdkPath string) error {
 	}
 
 	// Extract archive
-	if err := extractArchive(archivePath, jdkPath); err != nil {
+	if err := extractArchive(archivePath, jdkPath, flatten, progress); err != nil {
 		return fmt.Errorf("extracting archive: %w", err)
 	}
 
 	return nil
 }
+
+// shouldKeepArchive decide se conservare l'archivio scaricato dopo un'estrazione riuscita.
+//
+// Ordine di precedenza:
+//  1. Flag --keep-archive / --delete-archive della singola invocazione
+//  2. Impostazione keep-archives in configurazione ('jenvy config set keep-archives true|false')
+//  3. Domanda interattiva (default: conserva); senza interazione (--yes) l'archivio è conservato
+//
+// Parametri:
+//
+//	override *bool   - Valore dei flag da riga di comando (nil se non specificato)
+//	interactive bool - false per non porre domande (--yes)
+func shouldKeepArchive(override *bool, interactive bool) bool {
+	if override != nil {
+		return *override
+	}
+	if cfg, err := utils.LoadConfigOrDefault(); err == nil && cfg.KeepArchives != nil {
+		return *cfg.KeepArchives
+	}
+	if !interactive {
+		return true
+	}
+	return !askConfirmation("[?] Delete the archive to free disk space? (y/N): ")
+}
+
+// removeExtractedArchive elimina l'archivio di un JDK già estratto, segnalando eventuali errori.
+func removeExtractedArchive(archivePath string) {
+	if err := os.Remove(archivePath); err != nil {
+		utils.PrintWarning(fmt.Sprintf("Could not remove archive file: %v", err))
+		utils.PrintInfo(fmt.Sprintf("Archive file still present: %s", filepath.Base(archivePath)))
+		return
+	}
+	utils.PrintInfo(fmt.Sprintf("Archive removed: %s", filepath.Base(archivePath)))
+}
+
+// errUnknownProvider indica un provider non supportato da download.
+var errUnknownProvider = errors.New("unknown provider")
+
+// downloadRelease è una release risolta da un provider, pronta per il download.
+type downloadRelease struct {
+	URL               string
+	Filename          string
+	Version           string // Versione trovata (es. "17.0.12+7"); vuota se nessuna corrispondenza
+	Checksum          string // Checksum pubblicato dal provider (vuoto se non disponibile)
+	ChecksumAlgorithm string // Algoritmo indicato dal provider (vuoto = dedotto dal checksum)
+	EarlyAccess       bool   // Build early-access, inclusa solo con --ea
+}
+
+// releaseFinder cerca una versione tra le release già scaricate di un provider.
+type releaseFinder func(version string) downloadRelease
+
+// fetchReleaseFinder scarica una sola volta l'elenco delle release di un provider.
+//
+// Il finder restituito può essere chiamato più volte senza nuove richieste di rete,
+// così che 'download --all-lts' risolva tutte le major LTS con un'unica interrogazione.
+//
+// Parametri:
+//
+//	provider string - adoptium | azul | liberica | graalvm | private
+//
+// Restituisce:
+//
+//	releaseFinder - Funzione di ricerca sulle release del provider
+//	error         - errUnknownProvider o errore di rete del provider
+func fetchReleaseFinder(provider string) (releaseFinder, error) {
+	switch provider {
+	case "adoptium":
+		releases, err := adoptium.GetAllJDKs()
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch releases from %s: %w", provider, err)
+		}
+		earlyAccess := adoptiumEarlyAccessURLs(releases)
+		return func(version string) downloadRelease {
+			release := newDownloadRelease(findAdoptiumDownload(releases, version))
+			release.EarlyAccess = earlyAccess[release.URL]
+			for _, r := range releases {
+				for _, binary := range r.Binaries {
+					if binary.Package.Link == release.URL {
+						release.Checksum, release.ChecksumAlgorithm = binary.Package.Checksum, "sha256"
+					}
+				}
+			}
+			return release
+		}, nil
+
+	case "azul":
+		releases, err := azul.GetAzulJDKs()
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch releases from %s: %w", provider, err)
+		}
+		return func(version string) downloadRelease {
+			return newDownloadRelease(findAzulDownload(releases, version))
+		}, nil
+
+	case "liberica":
+		releases, err := liberica.GetLibericaJDKs()
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch releases from %s: %w", provider, err)
+		}
+		earlyAccess := libericaEarlyAccessURLs(releases)
+		return func(version string) downloadRelease {
+			release := newDownloadRelease(findLibericaDownload(releases, version))
+			release.EarlyAccess = earlyAccess[release.URL]
+			return release
+		}, nil
+
+	case "graalvm":
+		releases, err := graalvm.GetGraalVMJDKs()
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch releases from %s: %w", provider, err)
+		}
+		return func(version string) downloadRelease {
+			return newDownloadRelease(findGraalVMDownload(releases, version))
+		}, nil
+
+	case "private":
+		releases, err := private.GetPrivateJDKs()
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch releases from %s: %w", provider, err)
+		}
+		return func(version string) downloadRelease {
+			release := newDownloadRelease(findPrivateDownload(releases, version))
+			for _, r := range releases {
+				if r.DownloadURL == release.URL {
+					release.Checksum, release.ChecksumAlgorithm = r.Checksum, r.ChecksumAlgorithm
+				}
+			}
+			return release
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownProvider, provider)
+	}
+}
+
+// printProviderFailureHint suggerisce come indagare un errore restituito da un provider.
+//
+// Una risposta valida ma senza release utilizzabili (utils.ErrNoUsableReleases)
+// indica un cambio di schema dell'API, da non confondere con una versione che
+// non esiste: in quel caso rimanda a 'remote-list --raw' per vedere la risposta.
+func printProviderFailureHint(provider string, err error) {
+	switch {
+	case errors.Is(err, utils.ErrNoUsableReleases):
+		utils.PrintWarning(fmt.Sprintf("This is a problem with the %s API response, not a missing version", provider))
+		utils.PrintInfo(fmt.Sprintf("Inspect what the provider sent with 'jenvy remote-list --provider=%s --raw'", provider))
+	case errors.Is(err, private.ErrUnreachable):
+		utils.PrintInfo("Check the endpoint with 'jenvy config-show' and your network or proxy settings")
+	}
+}
+
+// printGitHubAuthStatus mostra, in modalità --verbose, la provenienza del token GitHub e la quota residua.
+//
+// Il token non viene mai stampato: solo da dove è stato letto (JENVY_GITHUB_TOKEN
+// o github-token in configurazione), come per il token del repository privato.
+func printGitHubAuthStatus() {
+	if _, source := utils.GitHubToken(); source != "" {
+		utils.PrintInfo(fmt.Sprintf("GitHub token: %s", source))
+	} else {
+		utils.PrintInfo(fmt.Sprintf("GitHub token: none (set %s or 'jenvy config set github-token <token>')", utils.GitHubTokenEnv))
+	}
+
+	limit, err := utils.FetchGitHubRateLimit(10 * time.Second)
+	if err != nil {
+		utils.PrintWarning(fmt.Sprintf("Could not read GitHub rate limit: %v", err))
+		return
+	}
+	utils.PrintInfo(fmt.Sprintf("GitHub rate limit: %d/%d remaining (resets at %s)",
+		limit.Remaining, limit.Limit, limit.Reset.Format("15:04")))
+}
+
+// printResolvedRelease mostra la release risolta da 'download --resolve-only' senza scaricarla.
+//
+// La dimensione dell'archivio è letta con una richiesta HEAD (Content-Length):
+// se il server non la fornisce viene indicata come sconosciuta.
+//
+// Parametri:
+//
+//	release downloadRelease - Release risolta dal provider
+//	provider string         - Provider interrogato
+//	filename string         - Nome con cui l'archivio verrebbe salvato
+func printResolvedRelease(release downloadRelease, provider, filename string) {
+	size := "unknown"
+	if length, err := utils.RemoteContentLength(release.URL, 30*time.Second); err != nil {
+		utils.PrintWarning(fmt.Sprintf("Could not read archive size: %v", err))
+	} else if length >= 0 {
+		size = utils.FormatBytes(length)
+	}
+
+	fmt.Println()
+	fmt.Printf("  Version:  %s\n", release.Version)
+	fmt.Printf("  Provider: %s\n", provider)
+	fmt.Printf("  URL:      %s\n", release.URL)
+	fmt.Printf("  Archive:  %s\n", filename)
+	fmt.Printf("  Size:     %s\n", size)
+	if release.Checksum != "" {
+		fmt.Printf("  Checksum: %s\n", release.Checksum)
+	}
+	fmt.Println()
+	utils.PrintInfo("Resolve only: nothing was downloaded")
+}
+
+// newDownloadRelease costruisce una downloadRelease dal risultato delle funzioni find*Download.
+func newDownloadRelease(url, filename, version string) downloadRelease {
+	return downloadRelease{URL: url, Filename: filename, Version: version}
+}
+
+// verifiedChecksum restituisce il checksum con cui è stato verificato l'archivio di una release, e il suo algoritmo.
+//
+// L'algoritmo segue la stessa precedenza di verifyDownloadedArchive: --checksum-algorithm,
+// poi quello indicato dal provider, poi quello dedotto dalla lunghezza del checksum.
+// Restituisce stringhe vuote se il provider non pubblica un checksum.
+func verifiedChecksum(release downloadRelease, algorithm string) (string, string) {
+	if release.Checksum == "" {
+		return "", ""
+	}
+	if algorithm == "" {
+		algorithm = release.ChecksumAlgorithm
+	}
+	if algorithm == "" {
+		algorithm = utils.DetectChecksumAlgorithm(release.Checksum)
+	}
+	return strings.ToLower(strings.TrimSpace(release.Checksum)), strings.ToLower(algorithm)
+}
+
+// verifyDownloadedArchive confronta l'archivio scaricato con il checksum pubblicato dal provider.
+//
+// L'algoritmo è scelto in quest'ordine: --checksum-algorithm, quello indicato dal
+// provider, quello dedotto dalla lunghezza del checksum. Se il provider non pubblica
+// checksum la verifica viene saltata (con un avviso se l'algoritmo era stato richiesto).
+// Un checksum passato con --checksum sostituisce quello del provider prima della chiamata.
+//
+// Parametri:
+//
+//	archivePath string       - Archivio appena scaricato
+//	release downloadRelease  - Release risolta con checksum e algoritmo del provider
+//	algorithm string         - Valore di --checksum-algorithm (vuoto = automatico)
+//
+// Restituisce:
+//
+//	error - nil se verificato o non verificabile, errore se il checksum non coincide
+func verifyDownloadedArchive(archivePath string, release downloadRelease, algorithm string) error {
+	if release.Checksum == "" {
+		if algorithm != "" {
+			utils.PrintWarning("Provider does not publish a checksum for this archive: verification skipped")
+		}
+		return nil
+	}
+	if algorithm == "" {
+		algorithm = release.ChecksumAlgorithm
+	}
+	if err := utils.VerifyFileChecksum(archivePath, algorithm, release.Checksum); err != nil {
+		return err
+	}
+	if algorithm == "" {
+		algorithm = utils.DetectChecksumAlgorithm(release.Checksum)
+	}
+	utils.PrintSuccess(fmt.Sprintf("Checksum verified (%s)", strings.ToUpper(algorithm)))
+	return nil
+}
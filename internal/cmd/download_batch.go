@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+
+	"jenvy/internal/utils"
+)
+
+// maxConcurrentDownloads limita i download simultanei delle installazioni batch.
+//
+// Pochi download paralleli bastano a saturare una connessione domestica; oltre
+// si rallenta ogni singolo archivio senza guadagnare tempo complessivo.
+const maxConcurrentDownloads = 2
+
+// batchInstall è una voce di un'installazione batch e il suo esito.
+//
+// Chi prepara il batch risolve Release; le voci con Status già impostato (es.
+// "not found", "duplicate") compaiono solo nel riepilogo.
+type batchInstall struct {
+	Label    string // Prima colonna del riepilogo (es. "17", "JDK 21", "azul")
+	Version  string // Versione mostrata se la release non è stata risolta
+	Provider string
+	Release  downloadRelease
+	Status   string // installed | already installed | duplicate | not found | not offered | failed | cancelled
+	Err      error
+}
+
+// batchDownload descrive un'installazione batch: 'download <v1> <v2>...', '--all-lts' e '--manifest'.
+type batchDownload struct {
+	Title             string             // Intestazione del riepilogo (es. "LTS INSTALLATION")
+	Noun              string             // Oggetto dei messaggi (es. "LTS release(s)", "pinned JDK(s)")
+	Done              string             // Messaggio se tutte le voci sono installate
+	Notification      string             // Titolo della notifica di fine (vedi notifyCompletion)
+	Progress          utils.ProgressMode // Progresso comune a tutti i download (ProgressNone per nessuno)
+	ChecksumAlgorithm string             // Valore di --checksum-algorithm (vuoto = automatico)
+	KeepArchive       *bool              // Override da riga di comando per la pulizia archivi (nil = config)
+	Flatten           bool               // false per --no-flatten
+	AssumeYes         bool               // true per saltare la conferma (--yes)
+	OutputDir         string             // Directory delle installazioni (~/.jenvy/versions)
+	Items             []*batchInstall    // Voci, nell'ordine del riepilogo
+}
+
+// installBatch scarica ed estrae in parallelo le release risolte di un batch.
+//
+// È il passo comune di downloadVersions, downloadAllLTS e downloadManifest:
+//  1. **Selezione**: Le voci già installate (stesso nome di directory, vedi
+//     utils.VariantInstallDirName) vengono saltate
+//  2. **Conferma**: Un'unica domanda per tutto il batch (saltata con --yes)
+//  3. **Download concorrente**: Fino a maxConcurrentDownloads archivi in parallelo,
+//     con un unico progresso che somma i byte di tutti i download
+//  4. **Riepilogo**: Esito per ogni voce, anche in caso di errori parziali
+//
+// Ctrl+C interrompe i download in corso: le installazioni incomplete vengono
+// rimosse (vedi installResolvedRelease) e le voci non ancora avviate risultano "cancelled".
+func installBatch(batch batchDownload) error {
+	scheme := utils.InstallNamingScheme()
+	var pending []*batchInstall
+	for _, item := range batch.Items {
+		if item.Status != "" {
+			continue
+		}
+		if item.Release.Filename == "" {
+			item.Release.Filename = fmt.Sprintf("openjdk-%s.tar.gz", item.Release.Version)
+		}
+		installDir := filepath.Join(batch.OutputDir, utils.VariantInstallDirName(scheme, item.Provider, item.Release.Version))
+		if _, ok := utils.ResolveJDKHome(installDir); ok {
+			item.Status = "already installed"
+			continue
+		}
+		pending = append(pending, item)
+	}
+
+	if len(pending) == 0 {
+		utils.PrintInfo("Nothing to download")
+		return printBatchSummary(batch)
+	}
+
+	fmt.Println()
+	utils.PrintInfo(fmt.Sprintf("The following JDKs will be downloaded and extracted to %s:", batch.OutputDir))
+	for _, item := range pending {
+		fmt.Printf("  - %s: %s\n", item.Label, item.Release.Version)
+	}
+	if !batch.AssumeYes && !askConfirmation("\n[?] Do you want to proceed? (y/N): ") {
+		utils.PrintInfo("Download cancelled by user")
+		return utils.ExitWith(utils.ExitGeneric, errors.New("download cancelled by user"))
+	}
+	fmt.Println()
+
+	// Ctrl-C annulla il contesto invece di terminare il processo, così le
+	// installazioni interrotte vengono ripulite prima di uscire
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Il totale cresce man mano che i download partono (la dimensione è nota solo dalla risposta)
+	shared := utils.NewProgress(batch.Progress, "[DOWNLOAD]", 0, utils.ProgressBytes)
+	var mu sync.Mutex
+	done := 0
+	utils.ForEachParallel(len(pending), maxConcurrentDownloads, func(i int) {
+		item := pending[i]
+		if ctx.Err() != nil {
+			item.Status = "cancelled"
+			return
+		}
+		err := installResolvedRelease(ctx, item.Release, item.Provider, batch.OutputDir, scheme, batch.Flatten, batch.KeepArchive, batch.ChecksumAlgorithm, shared)
+
+		mu.Lock()
+		defer mu.Unlock()
+		done++
+		if err != nil && ctx.Err() != nil {
+			item.Status = "cancelled"
+			return
+		}
+		if err != nil {
+			item.Status, item.Err = "failed", err
+			utils.PrintError(fmt.Sprintf("[%d/%d] JDK %s: %v", done, len(pending), item.Release.Version, err))
+			return
+		}
+		item.Status = "installed"
+		utils.PrintSuccess(fmt.Sprintf("[%d/%d] JDK %s installed", done, len(pending), item.Release.Version))
+	})
+	if ctx.Err() != nil {
+		shared.Abort()
+		fmt.Println()
+		utils.PrintWarning("Download interrupted: incomplete installations have been removed")
+	} else {
+		shared.Finish()
+	}
+
+	summaryErr := printBatchSummary(batch)
+	notifyCompletion(batch.Notification, fmt.Sprintf("%d JDK(s)", len(pending)), summaryErr)
+	return summaryErr
+}
+
+// installResolvedRelease scarica, verifica, estrae e registra una release già risolta.
+//
+// Usata dai comandi batch (installBatch, 'upgrade --all'), che mostrano
+// un riepilogo finale invece di chiedere conferme per ogni versione. Se ctx viene
+// annullato prima della fine dell'estrazione la directory di installazione, creata
+// qui, viene rimossa: nessuna installazione resta a metà. Anche l'estrazione si
+// interrompe con Ctrl+C senza lasciare file parziali (vedi extractStaged). Se shared non è nil, i byte scaricati vi
+// vengono sommati.
+func installResolvedRelease(ctx context.Context, release downloadRelease, provider, outputDir, scheme string, flatten bool, keepArchive *bool, checksumAlgorithm string, shared *utils.Progress) (err error) {
+	installDir := filepath.Join(outputDir, utils.VariantInstallDirName(scheme, provider, release.Version))
+	if err := os.MkdirAll(installDir, 0755); err != nil {
+		return fmt.Errorf("creating version directory: %w", err)
+	}
+	defer func() {
+		if err != nil && ctx.Err() != nil {
+			os.RemoveAll(installDir)
+			err = ctx.Err()
+		}
+	}()
+
+	archivePath := filepath.Join(installDir, release.Filename)
+	utils.PrintDownload(fmt.Sprintf("Downloading JDK %s...", release.Version))
+	if err := downloadFile(ctx, release.URL, archivePath, utils.ProgressNone, shared, false); err != nil {
+		return err
+	}
+	if err := verifyDownloadedArchive(archivePath, release, checksumAlgorithm); err != nil {
+		os.Remove(archivePath)
+		return err
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	meta := newDownloadMetadata(provider, release, release.Filename, checksumAlgorithm)
+	if err := utils.SaveInstallMetadata(installDir, meta); err != nil {
+		utils.PrintWarning(fmt.Sprintf("Could not save installation metadata: %v", err))
+	}
+
+	if err := extractJDKArchive(filepath.Base(installDir), installDir, flatten, utils.ProgressNone); err != nil {
+		return fmt.Errorf("extraction failed (archive kept, run 'jenvy extract %s'): %w", filepath.Base(installDir), err)
+	}
+	recordInstallManifest(installDir)
+	reportNativeImage(installDir)
+	if !shouldKeepArchive(keepArchive, false) {
+		removeExtractedArchive(archivePath)
+	}
+	return nil
+}
+
+// printBatchSummary stampa l'esito di ogni voce al termine di un'installazione batch.
+//
+// Restituisce un errore con utils.ExitNetwork se almeno un'installazione è
+// fallita, con utils.ExitInterrupted se il download è stato interrotto, con
+// utils.ExitNotFound se una versione richiesta non è offerta dal provider.
+// Le major non offerte di '--all-lts' ("not offered") non sono un errore.
+func printBatchSummary(batch batchDownload) error {
+	fmt.Println()
+	utils.PrintSection("[SUMMARY] " + batch.Title)
+	failed, notFound, cancelled := 0, 0, 0
+	for _, item := range batch.Items {
+		color := utils.Green
+		switch item.Status {
+		case "failed":
+			color = utils.Red
+			failed++
+		case "cancelled":
+			color = utils.Red
+			cancelled++
+		case "not found":
+			color = utils.Yellow
+			notFound++
+		case "not offered", "duplicate":
+			color = utils.Yellow
+		}
+
+		version := item.Release.Version
+		if version == "" {
+			version = item.Version
+		}
+		if version == "" {
+			version = "-"
+		}
+		line := fmt.Sprintf("  %-12s %-20s %s", item.Label, version, utils.ColorText(item.Status, color))
+		if item.Err != nil {
+			line += fmt.Sprintf(" (%v)", item.Err)
+		}
+		fmt.Println(line)
+	}
+	fmt.Println()
+
+	switch {
+	case failed > 0:
+		utils.PrintWarning(fmt.Sprintf("%d %s failed to install", failed, batch.Noun))
+		return utils.ExitWith(utils.ExitNetwork, fmt.Errorf("%d %s failed to install", failed, batch.Noun))
+	case cancelled > 0:
+		return utils.ExitWith(utils.ExitInterrupted, errors.New("download interrupted"))
+	case notFound > 0:
+		utils.PrintWarning(fmt.Sprintf("%d version(s) not found: run 'jenvy remote-list' to see the available versions", notFound))
+		return utils.ExitWith(utils.ExitNotFound, fmt.Errorf("%d version(s) not found", notFound))
+	}
+	utils.PrintSuccess(batch.Done)
+	utils.PrintInfo("Use 'jenvy use <version>' to activate one of them")
+	return nil
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"jenvy/internal/utils"
+)
+
+// downloadAllLTS installa l'ultima patch di ogni major LTS offerta da un provider.
+//
+// Pensato per il bootstrap di una macchina nuova ('jenvy download --all-lts'):
+// un'unica interrogazione del provider (fetchReleaseFinder) risolve l'ultima
+// patch di ogni major in utils.LTSMajors, poi installBatch scarica ed estrae le
+// release. Le major non offerte compaiono nel riepilogo senza essere un errore.
+// L'unica domanda è la conferma iniziale (saltata con --yes); la pulizia degli
+// archivi segue --keep-archive/--delete-archive o keep-archives in configurazione,
+// altrimenti gli archivi vengono conservati.
+//
+// Parametri:
+//
+//	provider string          - Provider da cui scaricare
+//	outputDir string         - Directory delle installazioni (~/.jenvy/versions)
+//	flatten bool             - false per --no-flatten
+//	assumeYes bool           - true per saltare la conferma (--yes)
+//	keepArchive *bool        - Override da riga di comando per la pulizia archivi (nil = config)
+//	checksumAlgorithm string - Valore di --checksum-algorithm (vuoto = automatico)
+func downloadAllLTS(provider, outputDir string, flatten, assumeYes bool, keepArchive *bool, checksumAlgorithm string) error {
+	utils.PrintSearch(fmt.Sprintf("Resolving LTS releases from provider: %s", provider))
+	findRelease, err := fetchReleaseFinder(provider)
+	if err != nil {
+		return batchProviderFailure(provider, err)
+	}
+
+	var items []*batchInstall
+	for _, major := range utils.LTSMajors {
+		item := &batchInstall{Label: fmt.Sprintf("JDK %d", major), Provider: provider, Release: findRelease(strconv.Itoa(major))}
+		if item.Release.URL == "" {
+			item.Status = "not offered"
+		}
+		items = append(items, item)
+	}
+
+	return installBatch(batchDownload{
+		Title:             "LTS INSTALLATION",
+		Noun:              "LTS release(s)",
+		Done:              "All available LTS releases are installed",
+		Notification:      "LTS download",
+		Progress:          utils.ProgressNone,
+		ChecksumAlgorithm: checksumAlgorithm,
+		KeepArchive:       keepArchive,
+		Flatten:           flatten,
+		AssumeYes:         assumeYes,
+		OutputDir:         outputDir,
+		Items:             items,
+	})
+}
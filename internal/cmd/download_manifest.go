@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"jenvy/internal/utils"
+)
+
+// downloadManifest installa esattamente i JDK elencati in un file di versioni fissate.
+//
+// È la controparte "lockfile" di 'jenvy download': ogni sviluppatore del team
+// ottiene gli stessi archivi, byte per byte (vedi utils.LoadJDKManifest per lo schema):
+//  1. **Risoluzione**: Ogni provider viene interrogato una sola volta; ogni voce
+//     deve risolversi nella versione fissata (utils.VersionMatchesPin)
+//  2. **Blocco**: Se anche una sola versione non è più offerta non viene scaricato nulla
+//  3. **Installazione**: installBatch scarica ed estrae le release, come per --all-lts
+//  4. **Verifica**: Ogni archivio deve corrispondere allo SHA-256 del manifest,
+//     che ha precedenza su quello pubblicato dal provider
+//
+// Le versioni già installate vengono saltate. Come per --all-lts l'unica domanda
+// è la conferma iniziale (saltata con --yes).
+//
+// Parametri:
+//
+//	manifestPath string - File JSON con le voci {provider, version, sha256}
+//	outputDir string    - Directory delle installazioni (~/.jenvy/versions)
+//	flatten bool        - false per --no-flatten
+//	assumeYes bool      - true per saltare la conferma (--yes)
+//	keepArchive *bool   - Override da riga di comando per la pulizia archivi (nil = config)
+func downloadManifest(manifestPath, outputDir string, flatten, assumeYes bool, keepArchive *bool) error {
+	pins, err := utils.LoadJDKManifest(manifestPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return utils.Fail(utils.ExitNotFound, fmt.Sprintf("Manifest not found: %s", manifestPath))
+		}
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Invalid manifest: %v", err))
+	}
+
+	scheme := utils.InstallNamingScheme()
+	finders := make(map[string]releaseFinder)
+	var items []*batchInstall
+	unavailable, providerFailures := 0, 0
+	for _, pin := range pins {
+		item := &batchInstall{Label: pin.Provider, Version: pin.Version, Provider: pin.Provider}
+		items = append(items, item)
+
+		// Già installata con la versione fissata: nessuna interrogazione del provider
+		installDir := filepath.Join(outputDir, utils.VariantInstallDirName(scheme, pin.Provider, pin.Version))
+		if _, ok := utils.ResolveJDKHome(installDir); ok {
+			item.Status = "already installed"
+			continue
+		}
+
+		findRelease, ok := finders[pin.Provider]
+		if !ok {
+			utils.PrintSearch(fmt.Sprintf("Resolving pinned releases from provider: %s", pin.Provider))
+			finder, err := fetchReleaseFinder(pin.Provider)
+			if err != nil {
+				utils.PrintError(err.Error())
+				printProviderFailureHint(pin.Provider, err)
+			}
+			finders[pin.Provider] = finder
+			findRelease = finder
+		}
+		if findRelease == nil {
+			item.Status, item.Err = "failed", fmt.Errorf("provider %s unavailable", pin.Provider)
+			providerFailures++
+			continue
+		}
+
+		item.Release = findRelease(pin.Version)
+		if item.Release.URL == "" || !utils.VersionMatchesPin(pin.Version, item.Release.Version) {
+			item.Status = "not available"
+			if item.Release.Version != "" {
+				item.Err = fmt.Errorf("provider now offers %s", item.Release.Version)
+			}
+			unavailable++
+			continue
+		}
+		// Lo SHA-256 del manifest ha precedenza su quello pubblicato dal provider
+		item.Release.Checksum, item.Release.ChecksumAlgorithm = pin.SHA256, "sha256"
+	}
+
+	// Un manifest installato solo in parte non è riproducibile: meglio non toccare nulla
+	if unavailable > 0 || providerFailures > 0 {
+		fmt.Println()
+		for _, item := range items {
+			if item.Status != "not available" && item.Status != "failed" {
+				continue
+			}
+			line := fmt.Sprintf("%s JDK %s: %s", item.Provider, item.Version, item.Status)
+			if item.Err != nil {
+				line += fmt.Sprintf(" (%v)", item.Err)
+			}
+			utils.PrintError(line)
+		}
+		utils.PrintError("Some pinned JDKs cannot be resolved: nothing was downloaded")
+		if unavailable > 0 {
+			utils.PrintInfo(fmt.Sprintf("Update %s with versions still offered (see 'jenvy remote-list')", manifestPath))
+			return utils.ExitWith(utils.ExitNotFound, fmt.Errorf("%d pinned JDK(s) no longer available", unavailable))
+		}
+		return utils.ExitWith(utils.ExitNetwork, fmt.Errorf("%d pinned JDK(s) could not be resolved", providerFailures))
+	}
+
+	return installBatch(batchDownload{
+		Title:        "MANIFEST INSTALLATION",
+		Noun:         "pinned JDK(s)",
+		Done:         "All pinned JDKs are installed",
+		Notification: "Manifest download",
+		Progress:     utils.ProgressNone,
+		KeepArchive:  keepArchive,
+		Flatten:      flatten,
+		AssumeYes:    assumeYes,
+		OutputDir:    outputDir,
+		Items:        items,
+	})
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"jenvy/internal/providers/private"
+	"jenvy/internal/utils"
+)
+
+// downloadVersions scarica ed estrae in parallelo più versioni dallo stesso provider.
+//
+// Usata da 'jenvy download 17 21 --provider=adoptium': un'unica interrogazione
+// del provider risolve ogni versione richiesta, poi installBatch scarica ed
+// estrae le release con un unico progresso e mostra l'esito di ciascuna.
+// Le versioni che si risolvono nella stessa release di un'altra richiesta
+// vengono saltate.
+//
+// Parametri:
+//
+//	versions []string           - Versioni richieste, già normalizzate
+//	provider string             - Provider da cui scaricare
+//	outputDir string            - Directory delle installazioni (~/.jenvy/versions)
+//	flatten bool                - false per --no-flatten
+//	assumeYes bool              - true per saltare la conferma (--yes)
+//	keepArchive *bool           - Override da riga di comando per la pulizia archivi (nil = config)
+//	checksumAlgorithm string    - Valore di --checksum-algorithm (vuoto = automatico)
+//	progress utils.ProgressMode - Stile del progresso già risolto
+func downloadVersions(versions []string, provider, outputDir string, flatten, assumeYes bool, keepArchive *bool, checksumAlgorithm string, progress utils.ProgressMode) error {
+	utils.PrintSearch(fmt.Sprintf("Resolving %d JDK versions from provider: %s", len(versions), provider))
+	findRelease, err := fetchReleaseFinder(provider)
+	if err != nil {
+		return batchProviderFailure(provider, err)
+	}
+
+	var items []*batchInstall
+	resolved := make(map[string]bool)
+	for _, query := range versions {
+		item := &batchInstall{Label: query, Provider: provider, Release: findRelease(query)}
+		items = append(items, item)
+		switch {
+		case item.Release.URL == "":
+			item.Status = "not found"
+		case resolved[item.Release.Version]:
+			item.Status = "duplicate"
+		}
+		resolved[item.Release.Version] = true
+	}
+
+	return installBatch(batchDownload{
+		Title:             "DOWNLOAD",
+		Noun:              "JDK(s)",
+		Done:              "All requested JDKs are installed",
+		Notification:      "Download",
+		Progress:          progress,
+		ChecksumAlgorithm: checksumAlgorithm,
+		KeepArchive:       keepArchive,
+		Flatten:           flatten,
+		AssumeYes:         assumeYes,
+		OutputDir:         outputDir,
+		Items:             items,
+	})
+}
+
+// batchProviderFailure segnala un provider che non ha restituito l'elenco delle release per un'installazione batch.
+func batchProviderFailure(provider string, err error) error {
+	if errors.Is(err, errUnknownProvider) || errors.Is(err, private.ErrNotConfigured) {
+		return utils.Fail(utils.ExitNotFound, err.Error())
+	}
+	failure := utils.Fail(utils.ExitNetwork, err.Error())
+	printProviderFailureHint(provider, err)
+	return failure
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"jenvy/internal/utils"
+)
+
+// errStreamIntegrity indica un archivio ricevuto in streaming che non corrisponde al checksum atteso.
+var errStreamIntegrity = errors.New("integrity check failed")
+
+// streamUnsupportedReason indica perché un archivio non può essere estratto durante il download ('download --stream').
+//
+// Lo streaming richiede:
+//   - un tar.gz: un archivio ZIP va letto dalla fine (directory centrale), quindi da disco
+//   - un checksum pubblicato o passato con --checksum: senza un archivio su disco
+//     è l'unico modo di verificare quanto è stato estratto
+//
+// Restituisce "" se lo streaming è possibile.
+func streamUnsupportedReason(release downloadRelease, filename string) string {
+	switch {
+	case !strings.HasSuffix(strings.ToLower(filename), ".tar.gz"):
+		return "only tar.gz archives can be extracted while downloading"
+	case release.Checksum == "":
+		return "the provider publishes no checksum to verify the stream against"
+	}
+	return ""
+}
+
+// installStreamed scarica ed estrae un JDK in un solo passaggio, senza scrivere l'archivio su disco.
+//
+// È il percorso di 'jenvy download <version> --stream': dimezza le scritture su
+// disco e il tempo di installazione rispetto a download + estrazione. Il checksum
+// viene calcolato sul flusso mentre viene estratto (vedi streamJDKArchive); se non
+// coincide, o il trasferimento si interrompe (anche con Ctrl+C), installDir non
+// viene toccata.
+// Non essendoci un archivio, non c'è nulla da riprendere o conservare.
+//
+// Parametri:
+//
+//	release downloadRelease     - Release risolta, con il checksum da verificare
+//	provider string             - Provider della release
+//	filename string             - Nome dell'archivio remoto, registrato nei metadati
+//	versionDir string           - Nome della directory di installazione (es. "JDK-21.0.2")
+//	installDir string           - Directory di installazione (in --output o --extract-to)
+//	flatten bool                - false per --no-flatten
+//	external bool               - true con --extract-to, per registrare l'installazione esterna
+//	progress utils.ProgressMode - Stile del progresso già risolto
+//	checksumAlgorithm string    - Valore di --checksum-algorithm (vuoto = automatico)
+func installStreamed(release downloadRelease, provider, filename, versionDir, installDir string, flatten, external bool, progress utils.ProgressMode, checksumAlgorithm string) error {
+	utils.PrintDownload(fmt.Sprintf("Streaming JDK %s into %s (no archive is written)", release.Version, installDir))
+
+	// Ctrl+C interrompe il trasferimento invece di terminare il processo, così l'estrazione temporanea viene rimossa
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := streamJDKArchive(ctx, release, installDir, flatten, progress, checksumAlgorithm); err != nil {
+		if ctx.Err() != nil {
+			fmt.Println()
+			utils.PrintWarning("Download interrupted: nothing was left on disk")
+			return utils.ExitWith(utils.ExitInterrupted, errors.New("download interrupted"))
+		}
+		utils.PrintError(fmt.Sprintf("Streamed installation failed: %v", err))
+		utils.PrintInfo("Nothing was left on disk: retry, or download without --stream to keep a resumable archive")
+		notifyCompletion("Download", "JDK "+release.Version, err)
+		if errors.Is(err, errStreamIntegrity) {
+			return utils.ExitWith(utils.ExitExtraction, err)
+		}
+		return utils.ExitWith(utils.ExitNetwork, err)
+	}
+
+	if err := utils.SaveInstallMetadata(installDir, newDownloadMetadata(provider, release, filename, checksumAlgorithm)); err != nil {
+		utils.PrintWarning(fmt.Sprintf("Could not save installation metadata: %v", err))
+	}
+	recordInstallManifest(installDir)
+	reportNativeImage(installDir)
+	if external {
+		if err := utils.RegisterExternalInstall(installDir); err != nil {
+			utils.PrintWarning(fmt.Sprintf("Could not register %s: 'use' and 'list' will not find it (%v)", installDir, err))
+		}
+	}
+
+	utils.PrintSuccess("JDK downloaded and extracted successfully!")
+	utils.PrintInfo(fmt.Sprintf("JDK ready at: %s", installDir))
+	fmt.Println()
+	utils.PrintInfo("To activate this JDK, use:")
+	utils.PrintInfo(fmt.Sprintf("  jenvy use %s", versionDir))
+	followDefaultJDK(installDir)
+	notifyCompletion("Download", "JDK "+release.Version, nil)
+	return nil
+}
+
+// streamJDKArchive estrae in installDir il tar.gz della release direttamente dalla risposta HTTP.
+//
+// Il corpo della risposta passa per il calcolo del checksum e il progresso prima
+// di arrivare all'estrazione (extractTarStream); i byte rimasti dopo la fine del
+// tar vengono letti comunque, perché il checksum copre l'intero archivio.
+// L'estrazione avviene in una directory temporanea (vedi extractStaged) e arriva
+// in installDir solo con il checksum verificato. Se il checksum non coincide la
+// directory temporanea viene rimossa e installDir resta com'era, senza essere
+// creata se non esisteva: su disco non resta nulla.
+func streamJDKArchive(ctx context.Context, release downloadRelease, installDir string, flatten bool, progress utils.ProgressMode, checksumAlgorithm string) error {
+	if checksumAlgorithm == "" {
+		checksumAlgorithm = release.ChecksumAlgorithm
+	}
+	verifier, err := utils.NewChecksumVerifier(checksumAlgorithm, release.Checksum)
+	if err != nil {
+		return err
+	}
+
+	url := release.URL
+	if mirrored := utils.MirrorURL(url); mirrored != url {
+		utils.PrintInfo(fmt.Sprintf("Downloading from mirror: %s", mirrored))
+		url = mirrored
+	}
+	client := utils.NewHTTPClient(utils.DefaultDownloadTimeout)
+	req, err := newDownloadRequest(ctx, url)
+	if err != nil {
+		return err
+	}
+	resp, err := utils.DoWithRetry(client, req)
+	if err != nil {
+		return fmt.Errorf("downloading file: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("server returned status %d: %s", resp.StatusCode, resp.Status)
+	}
+
+	bar := utils.NewProgress(progress, "[DOWNLOAD]", resp.ContentLength, utils.ProgressBytes)
+	body := io.TeeReader(io.TeeReader(resp.Body, verifier), bar)
+	err = extractStaged(installDir, release.Filename, flatten, func(ctx context.Context, staging string) error {
+		if err := extractTarStream(ctx, body, staging); err != nil {
+			return fmt.Errorf("extracting %s: %w", filepath.Base(url), err)
+		}
+		if _, err := io.Copy(io.Discard, body); err != nil {
+			return fmt.Errorf("%w: %v", errTransferInterrupted, err)
+		}
+		if err := verifier.Verify(); err != nil {
+			return fmt.Errorf("%w: %v", errStreamIntegrity, err)
+		}
+		return nil
+	})
+	if err != nil {
+		bar.Abort()
+		return err
+	}
+	bar.Finish()
+	utils.PrintSuccess(fmt.Sprintf("Checksum verified (%s)", strings.ToUpper(verifier.Algorithm())))
+	return nil
+}
@@ -0,0 +1,410 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"jenvy/internal/utils"
+)
+
+// Su Linux e macOS non esiste un registro delle variabili d'ambiente: le variabili
+// persistenti di 'jenvy use' sono export in uno script di profilo gestito da jenvy.
+//   - **Sistema**: /etc/profile.d/jenvy.sh, letto dalle shell di login (richiede root)
+//   - **Utente**: ~/.jenvy/env.sh, richiamato dai profili della shell dell'utente
+//
+// Lo script viene riscritto a ogni 'jenvy use'; le righe non riconosciute restano invariate.
+
+// systemProfileScript è lo script di profilo con le variabili di sistema.
+const systemProfileScript = "/etc/profile.d/jenvy.sh"
+
+// javaHomeBinEntry è la voce del PATH che rende attivo il JDK di JAVA_HOME.
+const javaHomeBinEntry = "$JAVA_HOME/bin"
+
+// javaHomePathExport è la riga dello script che antepone $JAVA_HOME/bin al PATH.
+const javaHomePathExport = `export PATH="$JAVA_HOME/bin:$PATH"`
+
+// profileScriptHeader apre ogni script di profilo scritto da jenvy.
+const profileScriptHeader = "# Managed by jenvy ('jenvy use'): manual changes to JAVA_HOME are overwritten"
+
+// Sentinel del blocco che richiama ~/.jenvy/env.sh dai profili della shell.
+const (
+	profileSourceStart = "# >>> jenvy env >>>"
+	profileSourceEnd   = "# <<< jenvy env <<<"
+)
+
+// userProfileScript restituisce lo script con le variabili utente (~/.jenvy/env.sh).
+func userProfileScript() (string, error) {
+	jenvyDir, err := utils.JenvyHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(jenvyDir, "env.sh"), nil
+}
+
+// userEnvironmentSource indica dove sono salvate le variabili utente (origine mostrata da 'jenvy current').
+func userEnvironmentSource() string {
+	if script, err := userProfileScript(); err == nil {
+		return script
+	}
+	return "~/.jenvy/env.sh"
+}
+
+// systemEnvironmentSource indica dove sono salvate le variabili di sistema.
+func systemEnvironmentSource() string {
+	return systemProfileScript
+}
+
+// readProfileScript restituisce le righe di uno script di profilo (nil se non esiste).
+func readProfileScript(script string) ([]string, error) {
+	content, err := os.ReadFile(script)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return strings.Split(strings.TrimRight(string(content), "\n"), "\n"), nil
+}
+
+// writeProfileScript scrive uno script di profilo, creando la directory se serve.
+func writeProfileScript(script string, lines []string) error {
+	if len(lines) == 0 || lines[0] != profileScriptHeader {
+		lines = append([]string{profileScriptHeader}, lines...)
+	}
+	if err := os.MkdirAll(filepath.Dir(script), 0755); err != nil {
+		return describeProfileError("failed to create "+filepath.Dir(script), err)
+	}
+	if err := os.WriteFile(script, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return describeProfileError("failed to write "+script, err)
+	}
+	return nil
+}
+
+// profileExport riconosce una riga "export NOME=valore" e ne restituisce nome e valore.
+func profileExport(line string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(line), "export ")
+	if !ok {
+		return "", "", false
+	}
+	name, value, ok := strings.Cut(rest, "=")
+	if !ok {
+		return "", "", false
+	}
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	} else if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		value = strings.ReplaceAll(value[1:len(value)-1], `'\''`, "'")
+	}
+	return name, value, true
+}
+
+// readProfileVariable legge una variabile esportata da uno script di profilo.
+func readProfileVariable(script, name string) (string, bool) {
+	lines, err := readProfileScript(script)
+	if err != nil {
+		return "", false
+	}
+	for _, line := range lines {
+		if key, value, ok := profileExport(line); ok && key == name && line != javaHomePathExport {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// setProfileVariable scrive (o sostituisce) l'export di una variabile in uno script di profilo.
+//
+// Il valore è racchiuso tra apici singoli, così spazi e $ nei percorsi non
+// vengono interpretati dalla shell.
+func setProfileVariable(script, name, value string) error {
+	lines, err := readProfileScript(script)
+	if err != nil {
+		return describeProfileError("failed to read "+script, err)
+	}
+	export := fmt.Sprintf("export %s='%s'", name, strings.ReplaceAll(value, "'", `'\''`))
+	replaced := false
+	for i, line := range lines {
+		if key, _, ok := profileExport(line); ok && key == name && line != javaHomePathExport {
+			lines[i], replaced = export, true
+		}
+	}
+	if !replaced {
+		// JAVA_HOME va definita prima della riga che la usa nel PATH
+		lines = insertBeforePathExport(lines, export)
+	}
+	return writeProfileScript(script, lines)
+}
+
+// insertBeforePathExport inserisce line prima dell'export del PATH, o in coda se manca.
+func insertBeforePathExport(lines []string, line string) []string {
+	for i, existing := range lines {
+		if existing == javaHomePathExport {
+			return append(lines[:i], append([]string{line}, lines[i:]...)...)
+		}
+	}
+	return append(lines, line)
+}
+
+// removeProfileLine elimina da uno script le righe per cui match è vero.
+//
+// Restituisce true se almeno una riga è stata rimossa.
+func removeProfileLine(script string, match func(line string) bool) (bool, error) {
+	lines, err := readProfileScript(script)
+	if err != nil || lines == nil {
+		return false, err
+	}
+	var kept []string
+	for _, line := range lines {
+		if !match(line) {
+			kept = append(kept, line)
+		}
+	}
+	if len(kept) == len(lines) {
+		return false, nil
+	}
+	return true, writeProfileScript(script, kept)
+}
+
+// ensureProfilePath aggiunge a uno script l'export che antepone $JAVA_HOME/bin al PATH.
+//
+// Restituisce true se la riga è stata aggiunta, false se era già presente.
+func ensureProfilePath(script string) (bool, error) {
+	lines, err := readProfileScript(script)
+	if err != nil {
+		return false, describeProfileError("failed to read "+script, err)
+	}
+	for _, line := range lines {
+		if line == javaHomePathExport {
+			return false, nil
+		}
+	}
+	return true, writeProfileScript(script, append(lines, javaHomePathExport))
+}
+
+// describeProfileError aggiunge un'azione da compiere agli errori di permesso sugli script di profilo.
+//
+// L'errore originale resta disponibile con errors.Is/As.
+func describeProfileError(action string, err error) error {
+	if errors.Is(err, fs.ErrPermission) {
+		return fmt.Errorf("%s: permission denied (run with sudo, or use 'jenvy use <version> --user'): %w", action, err)
+	}
+	return fmt.Errorf("%s: %w", action, err)
+}
+
+// registryExitCode sceglie il codice di uscita per un errore di scrittura dell'ambiente.
+//
+// Come su Windows, il permesso negato diventa utils.ExitPermission.
+func registryExitCode(err error) int {
+	if errors.Is(err, fs.ErrPermission) {
+		return utils.ExitPermission
+	}
+	return utils.ExitGeneric
+}
+
+// readSystemEnvironmentVariable legge una variabile di sistema da /etc/profile.d/jenvy.sh.
+func readSystemEnvironmentVariable(name string) (string, bool) {
+	return readProfileVariable(systemProfileScript, name)
+}
+
+// readUserEnvironmentVariable legge una variabile dell'utente da ~/.jenvy/env.sh.
+func readUserEnvironmentVariable(name string) (string, bool) {
+	script, err := userProfileScript()
+	if err != nil {
+		return "", false
+	}
+	return readProfileVariable(script, name)
+}
+
+// setSystemEnvironmentVariable esporta una variabile per tutti gli utenti da /etc/profile.d/jenvy.sh.
+func setSystemEnvironmentVariable(name, value string) error {
+	return setProfileVariable(systemProfileScript, name, value)
+}
+
+// ensureJavaHomeInPath aggiunge $JAVA_HOME/bin in testa al PATH delle shell di login.
+func ensureJavaHomeInPath() error {
+	added, err := ensureProfilePath(systemProfileScript)
+	if err != nil {
+		return err
+	}
+	if !added {
+		utils.PrintInfo("$JAVA_HOME/bin is already in PATH")
+		return nil
+	}
+	utils.PrintSuccess("Added $JAVA_HOME/bin to PATH in " + systemProfileScript)
+	return nil
+}
+
+// setUserEnvironmentVariable esporta una variabile dell'utente da ~/.jenvy/env.sh.
+//
+// Lo script viene richiamato dai profili della shell (vedi ensureProfileSourced),
+// dove ha precedenza su /etc/profile.d perché letto dopo.
+func setUserEnvironmentVariable(name, value string) error {
+	script, err := userProfileScript()
+	if err != nil {
+		return err
+	}
+	if err := setProfileVariable(script, name, value); err != nil {
+		return err
+	}
+	return ensureProfileSourced(script)
+}
+
+// ensureJavaHomeInUserPath aggiunge $JAVA_HOME/bin in testa al PATH dell'utente, se manca.
+func ensureJavaHomeInUserPath() error {
+	script, err := userProfileScript()
+	if err != nil {
+		return err
+	}
+	added, err := ensureProfilePath(script)
+	if err != nil {
+		return err
+	}
+	if added {
+		utils.PrintSuccess("Added $JAVA_HOME/bin to PATH in " + script)
+	}
+	return nil
+}
+
+// userShellProfiles restituisce i profili della shell che devono richiamare ~/.jenvy/env.sh.
+//
+// Vengono usati i profili già presenti tra ~/.bashrc, ~/.bash_profile, ~/.zshrc
+// e ~/.profile; se non ne esiste nessuno viene creato ~/.profile.
+func userShellProfiles(homeDir string) []string {
+	var profiles []string
+	for _, name := range []string{".bashrc", ".bash_profile", ".zshrc", ".profile"} {
+		path := filepath.Join(homeDir, name)
+		if _, err := os.Stat(path); err == nil {
+			profiles = append(profiles, path)
+		}
+	}
+	if len(profiles) == 0 {
+		profiles = append(profiles, filepath.Join(homeDir, ".profile"))
+	}
+	return profiles
+}
+
+// ensureProfileSourced aggiunge ai profili della shell il blocco che carica script.
+func ensureProfileSourced(script string) error {
+	homeDir, err := utils.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	block := fmt.Sprintf("%s\n[ -f '%s' ] && . '%s'\n%s\n", profileSourceStart, script, script, profileSourceEnd)
+	for _, profile := range userShellProfiles(homeDir) {
+		content, err := os.ReadFile(profile)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		if strings.Contains(string(content), profileSourceStart) {
+			continue
+		}
+		updated := string(content)
+		if updated != "" && !strings.HasSuffix(updated, "\n") {
+			updated += "\n"
+		}
+		if err := os.WriteFile(profile, []byte(updated+"\n"+block), 0644); err != nil {
+			return describeProfileError("failed to update "+profile, err)
+		}
+		utils.PrintInfo(fmt.Sprintf("Added jenvy environment to %s", profile))
+	}
+	return nil
+}
+
+// deleteSystemEnvironmentVariable rimuove l'export di una variabile da /etc/profile.d/jenvy.sh.
+func deleteSystemEnvironmentVariable(name string) error {
+	_, err := removeProfileLine(systemProfileScript, func(line string) bool {
+		key, _, ok := profileExport(line)
+		return ok && key == name && line != javaHomePathExport
+	})
+	if err != nil {
+		return describeProfileError("failed to update "+systemProfileScript, err)
+	}
+	return nil
+}
+
+// removeJavaHomeFromPath rimuove $JAVA_HOME/bin dal PATH di sistema, operazione
+// inversa di ensureJavaHomeInPath. Restituisce true se la voce era presente.
+func removeJavaHomeFromPath() (bool, error) {
+	removed, err := removeProfileLine(systemProfileScript, func(line string) bool {
+		return line == javaHomePathExport
+	})
+	if err != nil {
+		return false, describeProfileError("failed to update "+systemProfileScript, err)
+	}
+	return removed, nil
+}
+
+// previewSystemPath calcola il PATH che riceverebbe una nuova shell di login dopo 'jenvy use' ('use --dry-run').
+//
+// Restituisce anche se /etc/profile.d/jenvy.sh antepone già $JAVA_HOME/bin al PATH.
+func previewSystemPath(jdkPath string) (bool, string) {
+	lines, _ := readProfileScript(systemProfileScript)
+	inPath := false
+	for _, line := range lines {
+		if line == javaHomePathExport {
+			inPath = true
+		}
+	}
+	return inPath, filepath.Join(jdkPath, "bin") + string(os.PathListSeparator) + os.Getenv("PATH")
+}
+
+// isRunningAsAdmin indica se jenvy è eseguito come root, necessario per scrivere in /etc/profile.d.
+func isRunningAsAdmin() bool {
+	return os.Geteuid() == 0
+}
+
+// requestAdminPrivileges riesegue il comando corrente con sudo.
+//
+// A differenza dell'elevazione UAC di Windows il comando elevato viene atteso:
+// al ritorno ha già completato il lavoro, quindi il processo corrente deve solo
+// terminare. Restituisce false se sudo non è disponibile, la password viene
+// rifiutata o il comando elevato fallisce.
+//
+// sudo azzera l'ambiente e la home diventa quella di root: JENVY_HOME viene
+// passata esplicitamente con la directory dati dell'utente, così il comando
+// elevato trova le stesse versioni installate e la stessa configurazione.
+func requestAdminPrivileges() bool {
+	exe, err := os.Executable()
+	if err != nil {
+		utils.PrintError(fmt.Sprintf("Failed to get executable path: %v", err))
+		return false
+	}
+	sudo, err := exec.LookPath("sudo")
+	if err != nil {
+		utils.PrintError("sudo is not available: run the command as root")
+		return false
+	}
+	jenvyHome, err := utils.JenvyHome()
+	if err != nil {
+		utils.PrintError(fmt.Sprintf("Failed to determine the Jenvy home directory: %v", err))
+		return false
+	}
+	args := append([]string{"env", utils.JenvyHomeEnv + "=" + jenvyHome, exe}, elevatedArgs()...)
+	elevated := exec.Command(sudo, args...)
+	elevated.Stdin, elevated.Stdout, elevated.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return elevated.Run() == nil
+}
+
+// setProcessEnvironmentVariable imposta una variabile nell'ambiente del processo corrente.
+func setProcessEnvironmentVariable(name, value string) error {
+	return os.Setenv(name, value)
+}
+
+// executableSuffix è l'estensione degli eseguibili delle shell avviate da 'use --temporary'.
+const executableSuffix = ""
+
+// defaultTemporaryShell restituisce la shell di 'use --temporary' quando quella di origine non è nota.
+func defaultTemporaryShell() string {
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return shell
+	}
+	return "/bin/sh"
+}
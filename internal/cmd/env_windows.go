@@ -0,0 +1,623 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"jenvy/internal/utils"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// javaHomeBinEntry è la voce del PATH che rende attivo il JDK di JAVA_HOME.
+const javaHomeBinEntry = `%JAVA_HOME%\bin`
+
+// userEnvironmentSource indica dove sono salvate le variabili utente (origine mostrata da 'jenvy current').
+func userEnvironmentSource() string {
+	return `HKCU\Environment`
+}
+
+// systemEnvironmentSource indica dove sono salvate le variabili di sistema.
+func systemEnvironmentSource() string {
+	return `HKLM\` + systemEnvironmentKey
+}
+
+// requestAdminPrivileges richiede automaticamente privilegi amministratore tramite UAC Windows.
+//
+// Questa funzione gestisce l'elevazione dei privilegi quando il comando "jenvy use"
+// necessita di modificare le variabili d'ambiente di sistema. Utilizza l'API Windows
+// ShellExecute con il verbo "runas" per attivare il dialogo UAC (User Account Control).
+//
+// Meccanismo elevazione UAC:
+// 1. **Rilevamento eseguibile**: Ottiene il path dell'eseguibile Jenvy corrente
+// 2. **Preparazione argomenti**: Ricostruisce tutti gli argomenti della command line
+// 3. **ShellExecute "runas"**: Invoca Windows Shell con richiesta privilegi admin
+// 4. **Terminazione processo**: Il processo corrente termina, quello elevato continua
+//
+// Processo UAC Windows:
+//   - **Dialogo sicurezza**: Windows mostra prompt UAC per conferma utente
+//   - **Nuovo processo**: Se accettato, viene creato processo con privilegi admin
+//   - **Stesso comando**: Il nuovo processo esegue esattamente gli stessi argomenti
+//   - **Terminazione originale**: Il processo originale termina dopo l'elevazione
+//
+// Gestione argomenti:
+//   - **Preservazione completa**: Tutti gli argomenti originali vengono mantenuti
+//   - **Esclusione program name**: Solo gli argomenti reali (os.Args[1:])
+//   - **Join sicuro**: Concatenazione argomenti con spazi per ShellExecute
+//   - **Unicode support**: Gestione corretta caratteri Unicode in percorsi
+//
+// Codici ritorno ShellExecute:
+//   - **> 32**: Successo, elevazione completata
+//   - **<= 32**: Errore o cancellazione utente
+//   - **Codici comuni**: 2=file not found, 5=access denied, 8=memoria insufficiente
+//
+// Parametri:
+//
+//	Nessuno (legge da os.Args globale)
+//
+// Restituisce:
+//
+//	bool - true se elevazione completata con successo, false se fallita o rifiutata
+//
+// Comportamento trasparente:
+//   - Se successo: Il processo corrente termina, quello elevato prosegue silenziosamente
+//   - Se fallimento: Il processo corrente continua con messaggi di errore appropriati
+//   - Se cancellato: L'utente ha rifiutato l'elevazione nel dialogo UAC
+//
+// Scenari di utilizzo:
+//   - Utente standard che esegue "jenvy use"
+//   - Modifica variabili ambiente sistema richiede privilegi admin
+//   - Alternativa a esecuzione manuale "Run as Administrator"
+//
+// Limitazioni:
+//   - Richiede interazione utente (dialogo UAC)
+//   - Non funziona in contesti automatizzati senza desktop
+//   - Dipende dalle policy UAC del sistema
+//
+// Esempio di utilizzo:
+//
+//	if !isRunningAsAdmin() {
+//	    if requestAdminPrivileges() {
+//	        return // Il nuovo processo gestirà il comando
+//	    }
+//	    // Gestire fallimento elevazione
+//	}
+func requestAdminPrivileges() bool {
+	// Get current executable path
+	exe, err := os.Executable()
+	if err != nil {
+		utils.PrintError(fmt.Sprintf("Failed to get executable path: %v", err))
+		return false
+	}
+
+	// Build command arguments (pass all original arguments, global options included)
+	args := elevatedArgs()
+
+	// Create the command with runas verb to request admin privileges
+	verbPtr, _ := syscall.UTF16PtrFromString("runas")
+	exePtr, _ := syscall.UTF16PtrFromString(exe)
+
+	// Join arguments into a single string
+	argString := strings.Join(args, " ")
+	argPtr, _ := syscall.UTF16PtrFromString(argString)
+
+	// Keep the working directory: 'use' without a version reads project files from it
+	var dirPtr *uint16
+	if cwd, err := os.Getwd(); err == nil {
+		dirPtr, _ = syscall.UTF16PtrFromString(cwd)
+	}
+
+	// Use ShellExecute to run with elevated privileges
+	ret := shellExecute(0, verbPtr, exePtr, argPtr, dirPtr, 1)
+
+	// Return true if ShellExecute succeeded (> 32)
+	return ret > 32
+}
+
+// shellExecute è un wrapper Go per l'API Windows ShellExecuteW per esecuzione programmi con privilegi.
+//
+// Questa funzione incapsula la chiamata diretta all'API Win32 ShellExecuteW utilizzando
+// syscall per eseguire programmi con parametri specifici, inclusa la possibilità di
+// richiedere elevazione privilegi tramite il verbo "runas".
+//
+// API Windows ShellExecuteW:
+//   - **Funzione nativa**: shell32.dll ShellExecuteW per esecuzione avanzata
+//   - **Unicode support**: Versione Wide (W) per supporto caratteri Unicode
+//   - **Verbi azione**: "open", "runas", "print", etc. per diversi comportamenti
+//   - **Controllo finestra**: Parametri per gestione visualizzazione finestra
+//
+// Parametri:
+//
+//	hwnd uintptr     - Handle finestra parent (0 per nessun parent)
+//	verb *uint16     - Verbo azione: nil="open", "runas"=privilegi admin
+//	file *uint16     - Percorso eseguibile da lanciare (UTF-16 pointer)
+//	args *uint16     - Argomenti command line (UTF-16 pointer, può essere nil)
+//	dir *uint16      - Directory lavoro (UTF-16 pointer, può essere nil)
+//	show int         - Modalità visualizzazione finestra (SW_HIDE=0, SW_NORMAL=1, etc.)
+//
+// Restituisce:
+//
+//	uintptr - Codice ritorno ShellExecute (>32=successo, <=32=errore specifico)
+//
+// Codici ritorno comuni:
+//   - **> 32**: Successo, programma avviato correttamente
+//   - **0**: Out of memory or resources
+//   - **2**: File not found (ERROR_FILE_NOT_FOUND)
+//   - **3**: Path not found (ERROR_PATH_NOT_FOUND)
+//   - **5**: Access denied (ERROR_ACCESS_DENIED)
+//   - **8**: Out of memory (ERROR_NOT_ENOUGH_MEMORY)
+//   - **31**: No application associated with file type
+//
+// Utilizzo syscall.NewLazyDLL:
+//   - **Caricamento lazy**: DLL caricata solo quando necessario
+//   - **Performance**: Evita caricamento inutile se funzione non usata
+//   - **Gestione errori**: syscall gestisce automaticamente errori caricamento
+//   - **Pulizia automatica**: Go runtime gestisce cleanup DLL
+//
+// Sicurezza:
+//   - **unsafe.Pointer**: Necessario per compatibilità API C Windows
+//   - **Validazione input**: Chiamante responsabile per validazione parametri
+//   - **Gestione memoria**: Go runtime gestisce stringhe UTF-16
+//
+// Esempio di utilizzo con UAC:
+//
+//	verb, _ := syscall.UTF16PtrFromString("runas")
+//	exe, _ := syscall.UTF16PtrFromString("C:\\app.exe")
+//	args, _ := syscall.UTF16PtrFromString("arg1 arg2")
+//	ret := shellExecute(0, verb, exe, args, nil, 1)
+//	if ret > 32 { /* successo */ }
+func shellExecute(hwnd uintptr, verb, file, args, dir *uint16, show int) uintptr {
+	ret, _, _ := syscall.NewLazyDLL("shell32.dll").NewProc("ShellExecuteW").Call(
+		hwnd,
+		uintptr(unsafe.Pointer(verb)),
+		uintptr(unsafe.Pointer(file)),
+		uintptr(unsafe.Pointer(args)),
+		uintptr(unsafe.Pointer(dir)),
+		uintptr(show))
+	return ret
+}
+
+// isRunningAsAdmin verifica se il processo corrente ha privilegi di amministratore.
+//
+// Questa funzione implementa un controllo affidabile per determinare se l'applicazione
+// è in esecuzione con privilegi elevati, necessari per modificare le variabili
+// d'ambiente di sistema tramite il registro Windows.
+//
+// Metodo di verifica:
+//
+//	Tenta di aprire una chiave del registro che richiede privilegi amministratore
+//	per l'accesso in scrittura. Se l'operazione riesce, il processo ha privilegi admin.
+//
+// Chiave registro utilizzata per test:
+//
+//	HKEY_LOCAL_MACHINE\SYSTEM\CurrentControlSet\Control\Session Manager\Environment
+//	- **Criticità**: Chiave sistema per variabili d'ambiente globali
+//	- **Protezione**: Richiede privilegi elevated per SET_VALUE
+//	- **Affidabilità**: Controllo diretto sui permessi reali necessari
+//
+// Vantaggi di questo approccio:
+//   - **Test reale**: Verifica esattamente i permessi che servono per operazioni Jenvy
+//   - **Affidabile**: Non dipende da API che potrebbero cambiare
+//   - **Specifico**: Testa accesso alla specifica risorsa che useremo
+//   - **Immediato**: Fallisce velocemente se privilegi insufficienti
+//
+// Alternative non utilizzate:
+//   - **Token API**: Più complesso e dipendente da versioni Windows
+//   - **Gruppo Administrators**: Membership non garantisce privilegi attivi
+//   - **UAC API**: Overhead maggiore per controllo semplice
+//
+// Meccanismo:
+// 1. **Tentativo apertura**: Prova ad aprire chiave con permessi SET_VALUE
+// 2. **Gestione errore**: Se fallisce, assenza privilegi amministratore
+// 3. **Pulizia**: Chiude chiave immediatamente se apertura riuscita
+// 4. **Ritorno booleano**: true se privilegi presenti, false altrimenti
+//
+// Parametri:
+//
+//	Nessuno (controlla processo corrente)
+//
+// Restituisce:
+//
+//	bool - true se processo ha privilegi amministratore, false altrimenti
+//
+// Utilizzo tipico:
+//
+//	if !isRunningAsAdmin() {
+//	    // Richiedi elevazione UAC
+//	    requestAdminPrivileges()
+//	} else {
+//	    // Procedi con modifiche sistema
+//	}
+//
+// Scenari di utilizzo:
+//   - Prima di ogni modifica variabili d'ambiente sistema
+//   - Decisione se mostrare prompt UAC o errore
+//   - Validazione prerequisiti per operazioni privilegiate
+//   - Guida utente su come eseguire comando correttamente
+//
+// Limitazioni:
+//   - Non distingue tra diversi livelli di privilegi admin
+//   - Non rileva UAC disabilitato o policy gruppo
+//   - Test specifico per registro, potrebbe non coprire altri privilegi
+func isRunningAsAdmin() bool {
+	// Try to open a registry key that requires admin access
+	key, err := openSystemEnvironmentKey(registry.SET_VALUE)
+	if err != nil {
+		return false
+	}
+	defer key.Close()
+	return true
+}
+
+// setSystemEnvironmentVariable imposta una variabile d'ambiente di sistema nel registro Windows.
+//
+// Questa funzione modifica permanentemente le variabili d'ambiente a livello di sistema
+// attraverso il registro di Windows, rendendo le modifiche persistenti e disponibili
+// per tutti gli utenti e servizi del sistema.
+//
+// Registro Windows utilizzato:
+//
+//	Chiave: HKEY_LOCAL_MACHINE\SYSTEM\CurrentControlSet\Control\Session Manager\Environment
+//	Tipo: REG_SZ (String Value)
+//	Scopo: Variabili d'ambiente sistema globali
+//
+// Processo di modifica:
+// 1. **Apertura chiave registro**: Apre con permessi SET_VALUE per modifica
+// 2. **Impostazione valore**: Scrive la variabile come stringa nel registro
+// 3. **Chiusura chiave**: Cleanup automatico con defer per sicurezza
+// 4. **Broadcasting**: Notifica WM_SETTINGCHANGE (vedi broadcastEnvironmentChange)
+//
+// Requisiti privilegi:
+//   - **Amministratore richiesto**: HKLM richiede privilegi elevated
+//   - **UAC necessario**: Su Windows Vista+ serve elevazione UAC
+//   - **Servizi Windows**: Accesso completo per modifiche sistema
+//
+// Persistenza e scope:
+//   - **Permanente**: Sopravvive a riavvii sistema
+//   - **Globale**: Disponibile per tutti gli utenti
+//   - **Servizi**: Accessibile ai servizi Windows
+//   - **Nuove sessioni**: Automaticamente disponibile in nuovi login
+//
+// Broadcasting:
+//   - **WM_SETTINGCHANGE**: Messaggio Windows per notifica applicazioni
+//   - **HWND_BROADCAST**: Broadcast a tutte le finestre top-level
+//   - **Update live**: Explorer rilegge l'ambiente, i nuovi terminali vedono il valore
+//
+// Parametri:
+//
+//	name string  - Nome variabile d'ambiente (es. "JAVA_HOME")
+//	value string - Valore da assegnare (es. "C:\Program Files\Java\jdk-17")
+//
+// Restituisce:
+//
+//	error - nil se successo, errore specifico se operazione fallisce
+//
+// Errori comuni:
+//   - **Permessi insufficienti**: Processo non eseguito come amministratore
+//   - **Chiave inaccessibile**: Registro corrotto o permessi negati
+//   - **Valore non impostabile**: Problemi scrittura registro o memoria
+//   - **Nome invalido**: Caratteri speciali non supportati nel nome
+//
+// Esempio di utilizzo:
+//
+//	err := setSystemEnvironmentVariable("JAVA_HOME", "C:\\jdk-17")
+//	if err != nil {
+//	    log.Printf("Failed to set JAVA_HOME: %v", err)
+//	}
+//
+// Note di sicurezza:
+//   - Non valida caratteri pericolosi nel nome/valore
+//   - Non previene sovrascrittura variabili sistema critiche
+//   - Responsabilità chiamante per validazione input
+func setSystemEnvironmentVariable(name, value string) error {
+	key, err := openSystemEnvironmentKey(registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer key.Close()
+
+	err = key.SetStringValue(name, value)
+	if err != nil {
+		return describeRegistryError("failed to set registry value", err)
+	}
+
+	// Broadcast WM_SETTINGCHANGE so Explorer and new terminals pick up the change
+	broadcastEnvironmentChange()
+	return nil
+}
+
+// ensureJavaHomeInPath assicura che %JAVA_HOME%\bin sia presente nel PATH di sistema Windows.
+//
+// Questa funzione gestisce l'aggiornamento intelligente della variabile PATH di sistema
+// per includere la directory bin del JDK attivo, permettendo l'esecuzione diretta
+// di comandi Java da qualsiasi posizione nel prompt dei comandi.
+//
+// Processo di aggiornamento PATH:
+// 1. **Lettura PATH corrente**: Recupera valore attuale dal registro sistema
+// 2. **Parsing entries**: Suddivide PATH in singole directory separate da ";"
+// 3. **Controllo esistenza**: Verifica se %JAVA_HOME%\bin è già presente
+// 4. **Aggiunta intelligente**: Se mancante, aggiunge all'inizio del PATH
+// 5. **Scrittura registro**: Salva il nuovo PATH nel registro sistema
+//
+// Registro Windows utilizzato:
+//
+//	Chiave: HKLM\SYSTEM\CurrentControlSet\Control\Session Manager\Environment
+//	Valore: "Path" (REG_EXPAND_SZ o REG_SZ)
+//	Permessi: QUERY_VALUE | SET_VALUE per lettura e modifica
+//
+// Gestione %JAVA_HOME%\bin:
+//   - **Variabile espandibile**: Usa %JAVA_HOME%\bin invece di path assoluto
+//   - **Posizione prioritaria**: Aggiunto all'inizio del PATH per precedenza
+//   - **Case-insensitive**: Confronto ignorando maiuscole/minuscole
+//   - **Trim whitespace**: Rimuove spazi accidentali nelle entries PATH
+//
+// Vantaggi utilizzo %JAVA_HOME%\bin:
+//   - **Dinamico**: Si aggiorna automaticamente quando JAVA_HOME cambia
+//   - **Portable**: Non hard-coded a path specifici
+//   - **Standard**: Convenzione comune per setup Java
+//   - **Manutenibile**: Un solo punto di aggiornamento (JAVA_HOME)
+//
+// Comportamento intelligente:
+//   - **Evita duplicati**: Non aggiunge se già presente nel PATH
+//   - **Priorità elevata**: Inserimento all'inizio per precedenza su altre versioni Java
+//   - **Preservazione PATH**: Mantiene tutte le altre entries esistenti
+//   - **Feedback utente**: Messaggi informativi su operazioni eseguite
+//
+// Parametri:
+//
+//	Nessuno (opera su variabili d'ambiente sistema)
+//
+// Restituisce:
+//
+//	error - nil se successo o già presente, errore se modifica fallisce
+//
+// Messaggi output:
+//   - "[INFO] %JAVA_HOME%\bin is already in PATH" - se già configurato
+//   - "[SUCCESS] Added %JAVA_HOME%\bin to system PATH" - se aggiunto con successo
+//
+// Scenari di errore:
+//   - **Permessi insufficienti**: Richiede privilegi amministratore
+//   - **Registro inaccessibile**: Chiave sistema corrotta o bloccata
+//   - **PATH corrotto**: Valore PATH nel formato non riconosciuto
+//   - **Memoria insufficiente**: PATH troppo lungo per limiti Windows
+//
+// Esempio PATH risultante:
+//
+//	Prima:  "C:\Windows\System32;C:\Windows;C:\Program Files\Git\bin"
+//	Dopo:   "%JAVA_HOME%\bin;C:\Windows\System32;C:\Windows;C:\Program Files\Git\bin"
+func ensureJavaHomeInPath() error {
+	key, err := openSystemEnvironmentKey(registry.QUERY_VALUE | registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer key.Close()
+
+	// Read current PATH
+	currentPath, _, err := key.GetStringValue("Path")
+	if err != nil {
+		return fmt.Errorf("failed to read PATH: %w", err)
+	}
+
+	javaHomeBin := `%JAVA_HOME%\bin`
+
+	// Check if %JAVA_HOME%\\bin is already in PATH
+	pathEntries := strings.Split(currentPath, ";")
+	for _, entry := range pathEntries {
+		if strings.EqualFold(strings.TrimSpace(entry), javaHomeBin) {
+			utils.PrintInfo("%JAVA_HOME%\\bin is already in PATH")
+			return nil
+		}
+	}
+
+	// Add %JAVA_HOME%\\bin to the beginning of PATH
+	newPath := javaHomeBin + ";" + currentPath
+
+	err = key.SetStringValue("Path", newPath)
+	if err != nil {
+		return describeRegistryError("failed to update PATH", err)
+	}
+	broadcastEnvironmentChange()
+
+	utils.PrintSuccess("Added %JAVA_HOME%\\bin to system PATH")
+	return nil
+}
+
+// setUserEnvironmentVariable imposta una variabile d'ambiente dell'utente corrente (HKCU\Environment).
+func setUserEnvironmentVariable(name, value string) error {
+	key, err := registry.OpenKey(registry.CURRENT_USER, `Environment`, registry.SET_VALUE)
+	if err != nil {
+		return describeRegistryError("failed to open user environment", err)
+	}
+	defer key.Close()
+
+	if err := key.SetStringValue(name, value); err != nil {
+		return describeRegistryError("failed to set registry value", err)
+	}
+	broadcastEnvironmentChange()
+	return nil
+}
+
+// ensureJavaHomeInUserPath aggiunge %JAVA_HOME%\bin in testa al PATH utente, se manca.
+//
+// Il PATH utente può non esistere: in quel caso viene creato. Il valore è scritto
+// come REG_EXPAND_SZ, altrimenti %JAVA_HOME% non verrebbe espanso.
+func ensureJavaHomeInUserPath() error {
+	key, err := registry.OpenKey(registry.CURRENT_USER, `Environment`, registry.QUERY_VALUE|registry.SET_VALUE)
+	if err != nil {
+		return describeRegistryError("failed to open user environment", err)
+	}
+	defer key.Close()
+
+	javaHomeBin := `%JAVA_HOME%\bin`
+	currentPath, _, err := key.GetStringValue("Path")
+	if err != nil && err != registry.ErrNotExist {
+		return fmt.Errorf("failed to read user PATH: %w", err)
+	}
+	for _, entry := range strings.Split(currentPath, ";") {
+		if strings.EqualFold(strings.TrimSpace(entry), javaHomeBin) {
+			return nil
+		}
+	}
+
+	newPath := javaHomeBin
+	if currentPath != "" {
+		newPath += ";" + currentPath
+	}
+	if err := key.SetExpandStringValue("Path", newPath); err != nil {
+		return describeRegistryError("failed to update user PATH", err)
+	}
+	broadcastEnvironmentChange()
+	utils.PrintSuccess("Added %JAVA_HOME%\\bin to user PATH")
+	return nil
+}
+
+// readSystemEnvironmentVariable legge una variabile d'ambiente di sistema dal registro.
+func readSystemEnvironmentVariable(name string) (string, bool) {
+	key, err := openSystemEnvironmentKey(registry.QUERY_VALUE)
+	if err != nil {
+		return "", false
+	}
+	defer key.Close()
+
+	value, _, err := key.GetStringValue(name)
+	if err != nil {
+		return "", false
+	}
+	return value, true
+}
+
+// readUserEnvironmentVariable legge una variabile d'ambiente dell'utente corrente dal registro (HKCU\Environment).
+func readUserEnvironmentVariable(name string) (string, bool) {
+	key, err := registry.OpenKey(registry.CURRENT_USER, `Environment`, registry.QUERY_VALUE)
+	if err != nil {
+		return "", false
+	}
+	defer key.Close()
+
+	value, _, err := key.GetStringValue(name)
+	if err != nil {
+		return "", false
+	}
+	return value, true
+}
+
+// deleteSystemEnvironmentVariable rimuove una variabile d'ambiente di sistema dal registro.
+func deleteSystemEnvironmentVariable(name string) error {
+	key, err := openSystemEnvironmentKey(registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer key.Close()
+
+	if err := key.DeleteValue(name); err != nil && err != registry.ErrNotExist {
+		return describeRegistryError("failed to delete registry value", err)
+	}
+	broadcastEnvironmentChange()
+	return nil
+}
+
+// removeJavaHomeFromPath rimuove %JAVA_HOME%\bin dal PATH di sistema, operazione
+// inversa di ensureJavaHomeInPath. Restituisce true se la voce era presente.
+func removeJavaHomeFromPath() (bool, error) {
+	key, err := openSystemEnvironmentKey(registry.QUERY_VALUE | registry.SET_VALUE)
+	if err != nil {
+		return false, err
+	}
+	defer key.Close()
+
+	currentPath, valueType, err := key.GetStringValue("Path")
+	if err != nil {
+		return false, fmt.Errorf("failed to read PATH: %w", err)
+	}
+
+	var kept []string
+	found := false
+	for _, entry := range strings.Split(currentPath, ";") {
+		if strings.EqualFold(strings.TrimSpace(entry), `%JAVA_HOME%\bin`) {
+			found = true
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	if !found {
+		return false, nil
+	}
+
+	newPath := strings.Join(kept, ";")
+	if valueType == registry.EXPAND_SZ {
+		err = key.SetExpandStringValue("Path", newPath)
+	} else {
+		err = key.SetStringValue("Path", newPath)
+	}
+	if err != nil {
+		return false, describeRegistryError("failed to update PATH", err)
+	}
+	broadcastEnvironmentChange()
+	return true, nil
+}
+
+// setProcessEnvironmentVariable imposta una variabile nell'environment block del processo
+// corrente tramite SetEnvironmentVariableW, senza toccare il registro.
+func setProcessEnvironmentVariable(name, value string) error {
+	namePtr, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return err
+	}
+	valuePtr, err := syscall.UTF16PtrFromString(value)
+	if err != nil {
+		return err
+	}
+
+	ret, _, callErr := syscall.NewLazyDLL("kernel32.dll").NewProc("SetEnvironmentVariableW").Call(
+		uintptr(unsafe.Pointer(namePtr)),
+		uintptr(unsafe.Pointer(valuePtr)))
+	if ret == 0 {
+		return callErr
+	}
+	return nil
+}
+
+// previewSystemPath calcola il PATH che riceverebbe un nuovo processo dopo 'jenvy use' ('use --dry-run').
+//
+// Restituisce anche se il PATH di sistema contiene già %JAVA_HOME%\bin; in
+// caso contrario la voce viene aggiunta in testa, come farebbe ensureJavaHomeInPath.
+func previewSystemPath(jdkPath string) (bool, string) {
+	systemPath, _ := readSystemEnvironmentVariable("Path")
+	inPath := false
+	for _, entry := range strings.Split(systemPath, ";") {
+		if strings.EqualFold(strings.TrimSpace(entry), javaHomeBinEntry) {
+			inPath = true
+			break
+		}
+	}
+	if !inPath {
+		systemPath = javaHomeBinEntry + ";" + systemPath
+	}
+
+	// Un nuovo processo riceve il PATH di sistema seguito da quello utente
+	newPath := systemPath
+	if userPath, ok := readUserEnvironmentVariable("Path"); ok && userPath != "" {
+		newPath += ";" + userPath
+	}
+	newPath = utils.ExpandWindowsEnv(newPath, func(name string) (string, bool) {
+		if strings.EqualFold(name, "JAVA_HOME") {
+			return jdkPath, true
+		}
+		return os.LookupEnv(name)
+	})
+	return inPath, newPath
+}
+
+// executableSuffix è l'estensione degli eseguibili delle shell avviate da 'use --temporary'.
+const executableSuffix = ".exe"
+
+// defaultTemporaryShell restituisce la shell di 'use --temporary' quando quella di origine non è nota.
+func defaultTemporaryShell() string {
+	if comspec := os.Getenv("ComSpec"); comspec != "" {
+		return comspec
+	}
+	return "cmd.exe"
+}
@@ -4,10 +4,14 @@ import (
 	"archive/tar"
 	"archive/zip"
 	"compress/gzip"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"jenvy/internal/utils"
@@ -51,6 +55,7 @@ import (
 // - Funziona solo con archivi scaricati tramite 'jenvy download'
 // - Riconosce la struttura ~/.jenvy/versions/JDK-version/archive.zip
 // - Estrae direttamente nella directory JDK appropriata
+// - Riprende un'estrazione interrotta saltando i file già estratti correttamente
 //
 // **Sintassi comando:**
 //
@@ -58,6 +63,8 @@ import (
 //	jenvy extract 17                   # estrae versione 17.x.y più recente
 //	jenvy extract 17.0                 # estrae versione 17.0.x più recente
 //	jenvy extract JDK-17.0.16+8        # estrae versione specifica esatta
+//	jenvy extract 17 --no-flatten      # preserva la directory originale dell'archivio
+//	jenvy extract --all --yes --jobs=4 # estrae ogni archivio in sospeso (vedi extractAllArchives)
 //
 // **Esempi d'uso:**
 //
@@ -66,30 +73,64 @@ import (
 //	jenvy extract JDK-21.0.1+12        # estrae specificamente questa versione
 //
 // La funzione garantisce estrazione sicura e pulizia automatica in caso di errori.
-func ExtractJDK() {
-	// Ottieni directory home dell'utente
-	homeDir, err := os.UserHomeDir()
+func ExtractJDK() error {
+	// Ottieni directory delle versioni installate
+	versionsDir, err := utils.GetJenvyVersionsDirectory()
 	if err != nil {
 		utils.PrintError(fmt.Sprintf("Error getting user directory: %v", err))
 		utils.PrintInfo("Cannot access Windows user profile directory")
-		return
+		return utils.ExitWith(utils.ExitGeneric, err)
 	}
 
-	versionsDir := filepath.Join(homeDir, ".jenvy", "versions")
+	// Separa le opzioni dagli argomenti posizionali
+	// Con activation-style=junction si conserva la struttura nativa dell'archivio
+	flatten := utils.ActivationStyle() != utils.ActivationStyleJunction
+	all, assumeYes := false, utils.AssumeYes()
+	jobs := maxConcurrentDownloads
+	positional, flags := utils.SplitArgs(os.Args[2:])
+	for _, arg := range flags {
+		switch {
+		case arg == "--no-flatten":
+			flatten = false
+		case arg == "--all":
+			all = true
+		case arg == "--yes" || arg == "-y":
+			assumeYes = true
+		case strings.HasPrefix(arg, "--jobs="):
+			n, err := strconv.Atoi(strings.TrimPrefix(arg, "--jobs="))
+			if err != nil || n < 1 {
+				return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Invalid --jobs value '%s': use a positive number", strings.TrimPrefix(arg, "--jobs=")))
+			}
+			jobs = n
+		default:
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Unknown option for extract: %s", arg))
+		}
+	}
+
+	if all {
+		if len(positional) > 0 {
+			return utils.Fail(utils.ExitGeneric, "--all cannot be combined with a version")
+		}
+		return extractAllArchives(versionsDir, flatten, assumeYes, jobs)
+	}
 
 	// Se nessun argomento, mostra archivi disponibili
-	if len(os.Args) < 3 {
+	if len(positional) == 0 {
 		showAvailableArchives(versionsDir)
-		return
+		return nil
 	}
 
-	requestedVersion := os.Args[2]
+	requestedVersion, err := versionArgument(positional[0], versionsDir)
+	if err != nil {
+		return err
+	}
 
-	// Se l'input non inizia con "JDK-", cerca usando parsing intelligente
+	// Se l'input è il nome esatto di una directory (es. "JDK-17.0.8" o "azul-jdk-17.0.8")
+	// usalo direttamente, altrimenti cerca usando parsing intelligente
 	var jdkDir string
 	var actualVersion string
 
-	if strings.HasPrefix(requestedVersion, "JDK-") {
+	if info, err := os.Stat(filepath.Join(versionsDir, requestedVersion)); err == nil && info.IsDir() && requestedVersion != "" {
 		// Input completo, usa direttamente
 		actualVersion = requestedVersion
 		jdkDir = filepath.Join(versionsDir, requestedVersion)
@@ -99,7 +140,7 @@ func ExtractJDK() {
 			utils.PrintError(fmt.Sprintf("JDK version not found: %s", requestedVersion))
 			utils.PrintInfo("Available JDK versions:")
 			showAvailableArchives(versionsDir)
-			return
+			return utils.ExitWith(utils.ExitNotFound, err)
 		}
 	} else {
 		// Input parziale (es. "17"), cerca JDK con archivi disponibili
@@ -108,44 +149,62 @@ func ExtractJDK() {
 			utils.PrintError(fmt.Sprintf("Unable to find JDK with archive for version '%s': %v", requestedVersion, err))
 			utils.PrintInfo("Available JDK versions with archives:")
 			showAvailableArchives(versionsDir)
-			return
+			return utils.ExitWith(utils.ExitNotFound, err)
 		}
 
 		jdkDir = foundPath
 		actualVersion = filepath.Base(foundPath)
 		utils.PrintInfo(fmt.Sprintf("Found JDK version: %s", actualVersion))
 	} // Cerca archivi nella directory JDK
+
+	// Gli archivi scaricati con --os per un altro sistema restano intatti
+	if targetOS, foreign := utils.IsForeignInstall(jdkDir); foreign {
+		err := fmt.Errorf("%s contains a %s archive: it cannot be extracted on %s", actualVersion, targetOS, utils.HostOSName())
+		utils.PrintError(err.Error())
+		utils.PrintInfo(fmt.Sprintf("Copy the archive in %s to a %s machine instead", jdkDir, targetOS))
+		return utils.ExitWith(utils.ExitGeneric, err)
+	}
+
 	archiveFile, err := findArchiveInDirectory(jdkDir)
 	if err != nil {
 		utils.PrintError(fmt.Sprintf("No archive found in %s: %v", actualVersion, err))
 		utils.PrintInfo("This JDK may already be extracted or the archive is missing")
-		return
+		return utils.ExitWith(utils.ExitNotFound, err)
 	}
 
 	utils.PrintInfo(fmt.Sprintf("Found archive: %s", filepath.Base(archiveFile)))
 	utils.PrintInfo(fmt.Sprintf("Extracting to: %s", jdkDir))
+	if staging, err := utils.StagingDir(jdkDir); err == nil && hasPartialExtraction(staging) {
+		utils.PrintInfo("Resuming previous extraction: files already extracted will be skipped")
+	}
 
 	// Estrai l'archivio nella stessa directory
-	if err := extractArchive(archiveFile, jdkDir); err != nil {
-		utils.PrintError(fmt.Sprintf("Extraction failed: %v", err))
-		return
+	progress, _ := utils.ResolveProgressMode(utils.ProgressAuto)
+	if err := extractArchive(archiveFile, jdkDir, flatten, progress); err != nil {
+		notifyCompletion("Extraction", actualVersion, err)
+		return utils.Fail(extractionExitCode(err), fmt.Sprintf("Extraction failed: %v", err))
 	}
 
 	// Verifica che l'estrazione sia avvenuta correttamente
-	if !utils.IsValidJDKDirectory(jdkDir) {
+	if _, ok := utils.ResolveJDKHome(jdkDir); !ok {
 		utils.PrintWarning("Extracted directory does not appear to be a valid JDK")
 		utils.PrintInfo("The archive may be corrupted or in an unexpected format")
 	}
 
-	// Rimuovi l'archivio dopo estrazione riuscita
-	if err := os.Remove(archiveFile); err != nil {
-		utils.PrintWarning(fmt.Sprintf("Could not remove archive file: %v", err))
-		utils.PrintInfo(fmt.Sprintf("Archive file still present: %s", filepath.Base(archiveFile)))
+	// Rimuovi l'archivio dopo estrazione riuscita, salvo keep-archives=true in configurazione
+	if cfg, err := utils.LoadConfigOrDefault(); err != nil || cfg.KeepArchives == nil || !*cfg.KeepArchives {
+		removeExtractedArchive(archiveFile)
 	}
 
+	recordInstallManifest(jdkDir)
+	reportNativeImage(jdkDir)
+
 	utils.PrintSuccess(fmt.Sprintf("JDK extracted successfully: %s", actualVersion))
 	utils.PrintInfo(fmt.Sprintf("Location: %s", jdkDir))
 	utils.PrintInfo("Use 'jenvy use " + actualVersion + "' to activate this JDK")
+	followDefaultJDK(jdkDir)
+	notifyCompletion("Extraction", actualVersion, nil)
+	return nil
 }
 
 // showAvailableArchives mostra la lista di archivi JDK disponibili per l'estrazione.
@@ -155,7 +214,7 @@ func ExtractJDK() {
 // per visualizzare i JDK scaricati e pronti per l'estrazione.
 //
 // **Funzionalità:**
-// - Scansione automatica directory versioni JDK
+// - Scansione parallela directory versioni JDK (scanInstallations, condivisa con 'jenvy list')
 // - Rilevamento archivi .zip e .tar.gz non estratti
 // - Distinzione tra JDK estratti e non estratti
 // - Output colorato per migliore leggibilità
@@ -169,7 +228,7 @@ func ExtractJDK() {
 // Parametri:
 //   - versionsDir: percorso directory contenente le versioni JDK
 func showAvailableArchives(versionsDir string) {
-	entries, err := os.ReadDir(versionsDir)
+	scans, err := scanInstallations(versionsDir, false)
 	if err != nil {
 		utils.PrintError(fmt.Sprintf("Cannot access versions directory: %v", err))
 		utils.PrintInfo("Make sure to download JDKs first using 'jenvy download <version>'")
@@ -179,29 +238,14 @@ func showAvailableArchives(versionsDir string) {
 	var availableArchives []string
 	var extractedJDKs []string
 
-	for _, entry := range entries {
-		if entry.IsDir() {
-			jdkDir := filepath.Join(versionsDir, entry.Name())
-
-			// Controlla se c'è un archivio nella directory
-			archiveFile, err := findArchiveInDirectory(jdkDir)
-			if err == nil {
-				// Ottieni informazioni sul file
-				info, err := os.Stat(archiveFile)
-				if err == nil {
-					size := float64(info.Size()) / (1024 * 1024) // MB
-					availableArchives = append(availableArchives,
-						fmt.Sprintf("  %s (%.1f MB)", entry.Name(), size))
-				} else {
-					availableArchives = append(availableArchives,
-						fmt.Sprintf("  %s", entry.Name()))
-				}
-			} else {
-				// Controlla se è un JDK già estratto
-				if utils.IsValidJDKDirectory(jdkDir) {
-					extractedJDKs = append(extractedJDKs, fmt.Sprintf("  %s (already extracted)", entry.Name()))
-				}
-			}
+	for _, scan := range scans {
+		if scan.ArchivePath != "" {
+			size := float64(scan.ArchiveSize) / (1024 * 1024) // MB
+			availableArchives = append(availableArchives,
+				fmt.Sprintf("  %s (%.1f MB)", scan.Name, size))
+		} else if scan.JDKHome != "" {
+			// JDK già estratto
+			extractedJDKs = append(extractedJDKs, fmt.Sprintf("  %s (already extracted)", scan.Name))
 		}
 	}
 
@@ -248,7 +292,7 @@ func showAvailableArchives(versionsDir string) {
 // perché filtra solo quelli con archivi disponibili.
 //
 // **Algoritmo di ricerca intelligente:**
-// 1. **Exact Match**: Cerca "JDK-{version}" con archivio
+// 1. **Exact Match**: Cerca "JDK-{version}" (o "{provider}-jdk-{version}") con archivio
 // 2. **Partial Match**: Cerca versioni che iniziano con il pattern e hanno archivi
 // 3. **Filtro archivi**: Solo directory con archivi .zip o .tar.gz disponibili
 // 4. **Gestione ambiguità**: Mostra opzioni multiple se trovate
@@ -283,35 +327,40 @@ func showAvailableArchives(versionsDir string) {
 //   - Progettato specificamente per il comando extract
 //   - Non considera JDK già estratti senza archivi
 func findJDKWithArchive(versionsDir, version string) (string, error) {
-	// Cerca prima match esatto
-	exactMatch := filepath.Join(versionsDir, fmt.Sprintf("JDK-%s", version))
-	if _, err := os.Stat(exactMatch); err == nil {
-		if _, err := findArchiveInDirectory(exactMatch); err == nil {
-			return exactMatch, nil
-		}
-	}
-
-	// Cerca match parziali con archivi
 	entries, err := os.ReadDir(versionsDir)
 	if err != nil {
 		return "", fmt.Errorf("failed to read versions directory: %w", err)
 	}
 
+	// Cerca match esatti e parziali con archivi (formati "JDK-" e "{provider}-jdk-")
+	scheme := utils.InstallNamingScheme()
+	var exactMatches []string
 	var matches []string
 	for _, entry := range entries {
-		if entry.IsDir() {
-			name := entry.Name()
-			if strings.HasPrefix(name, "JDK-") {
-				jdkVersion := strings.TrimPrefix(name, "JDK-")
-				if strings.HasPrefix(jdkVersion, version) {
-					fullPath := filepath.Join(versionsDir, name)
-					// Verifica che ci sia un archivio nella directory
-					if _, err := findArchiveInDirectory(fullPath); err == nil {
-						matches = append(matches, fullPath)
-					}
-				}
-			}
+		if !entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		jdkVersion, _, ok := utils.ParseInstallDirName(name, scheme)
+		if !ok || !strings.HasPrefix(jdkVersion, version) {
+			continue
+		}
+		fullPath := filepath.Join(versionsDir, name)
+		// Verifica che ci sia un archivio nella directory (e che sia per Windows, vedi download --os)
+		if _, err := findArchiveInDirectory(fullPath); err != nil {
+			continue
 		}
+		if _, foreign := utils.IsForeignInstall(fullPath); foreign {
+			continue
+		}
+		if jdkVersion == version {
+			exactMatches = append(exactMatches, fullPath)
+		}
+		matches = append(matches, fullPath)
+	}
+
+	if len(exactMatches) == 1 {
+		return exactMatches[0], nil
 	}
 
 	if len(matches) == 0 {
@@ -415,41 +464,188 @@ func findArchiveInDirectory(dirPath string) (string, error) {
 // - Controllo dimensioni file per prevenire zip bombs
 // - Verifica spazio disco disponibile durante estrazione
 // - Pulizia automatica in caso di errori
+// - Estrazione in una directory temporanea: destPath cambia solo se riesce (vedi extractStaged)
 //
 // **Gestione strutture archivio:**
 // - Rimozione directory wrapper se presente (comune in archivi JDK)
 // - Normalizzazione struttura per compatibilità con altri comandi jenvy
 // - Preservazione metadata JDK essenziali
+// - Con flatten=false la struttura nativa viene lasciata intatta (--no-flatten)
 //
 // Parametri:
 //   - archivePath: percorso del file archivio da estrarre
 //   - destPath: directory di destinazione per l'estrazione
+//   - flatten: se true sposta il contenuto della directory wrapper in destPath
+//   - progress: stile del progresso già risolto (utils.ProgressNone per estrazioni concorrenti)
 //
 // Ritorna errore se l'estrazione fallisce per qualsiasi motivo.
-func extractArchive(archivePath, destPath string) error {
+func extractArchive(archivePath, destPath string, flatten bool, progress utils.ProgressMode) error {
 	// Ensure destination directory exists
-	if err := os.MkdirAll(destPath, 0755); err != nil {
+	if err := os.MkdirAll(utils.LongPath(destPath), 0755); err != nil {
 		return fmt.Errorf("failed to create destination directory: %v", err)
 	}
 
+	var extract func(ctx context.Context, staging string) error
 	ext := strings.ToLower(filepath.Ext(archivePath))
 	if ext == ".zip" {
-		if err := extractZip(archivePath, destPath); err != nil {
-			return fmt.Errorf("ZIP extraction failed: %v", err)
+		extract = func(ctx context.Context, staging string) error {
+			if err := extractZip(ctx, archivePath, staging, progress); err != nil {
+				return fmt.Errorf("ZIP extraction failed: %w", err)
+			}
+			return nil
 		}
 	} else if strings.HasSuffix(strings.ToLower(archivePath), ".tar.gz") {
-		if err := extractTarGz(archivePath, destPath); err != nil {
-			return fmt.Errorf("TAR.GZ extraction failed: %v", err)
+		extract = func(ctx context.Context, staging string) error {
+			if err := extractTarGz(ctx, archivePath, staging, progress); err != nil {
+				return fmt.Errorf("TAR.GZ extraction failed: %w", err)
+			}
+			return nil
 		}
 	} else {
 		return fmt.Errorf("unsupported archive format: %s", ext)
 	}
 
+	return extractStaged(destPath, archivePath, flatten, extract)
+}
+
+// errExtractionInterrupted indica un'estrazione interrotta con Ctrl+C, dopo la quale non resta nulla di parziale.
+var errExtractionInterrupted = errors.New("extraction interrupted")
+
+// extractionExitCode restituisce il codice di uscita di un'estrazione fallita:
+// utils.ExitInterrupted se interrotta con Ctrl+C, altrimenti utils.ExitExtraction.
+func extractionExitCode(err error) int {
+	if errors.Is(err, errExtractionInterrupted) {
+		return utils.ExitInterrupted
+	}
+	return utils.ExitExtraction
+}
+
+// extractStaged esegue extract in una directory temporanea e porta il risultato in destPath solo se riesce.
+//
+// La directory temporanea è utils.StagingDir(destPath), sullo stesso volume di
+// destPath. Il JDK viene appiattito lì (flatten) e poi spostato con commitStaging:
+// fino a quel momento destPath non cambia, quindi 'list' non vede mai
+// un'installazione a metà. In caso di errore o di Ctrl+C la directory temporanea
+// viene rimossa; Ctrl+C interrompe extract tramite ctx e restituisce
+// errExtractionInterrupted. Se il processo viene terminato bruscamente, la
+// directory temporanea resta e l'estrazione successiva verso la stessa
+// destinazione la riprende (vedi utils.ExtractedFileMatches).
+func extractStaged(destPath, archivePath string, flatten bool, extract func(ctx context.Context, staging string) error) (err error) {
+	staging, err := utils.StagingDir(destPath)
+	if err != nil {
+		return fmt.Errorf("locating staging directory: %w", err)
+	}
+	if err := os.MkdirAll(utils.LongPath(staging), 0755); err != nil {
+		return fmt.Errorf("creating staging directory: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			os.RemoveAll(utils.LongPath(staging))
+		}
+	}()
+
+	// Ctrl+C annulla il contesto invece di terminare il processo, così la directory temporanea viene rimossa
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := extract(ctx, staging); err != nil {
+		if ctx.Err() != nil {
+			return errExtractionInterrupted
+		}
+		return err
+	}
+	if flatten {
+		flattenExtractedJDK(staging, archivePath)
+	}
+	return commitStaging(staging, destPath)
+}
+
+// commitStaging sostituisce il JDK estratto in destPath con il contenuto di staging.
+//
+// Archivi, download parziali e metadati già presenti in destPath vengono
+// conservati; tutto il resto (es. un'estrazione precedente) viene sostituito.
+// Lo scambio avviene con rename di intere directory, senza mai lasciare un JDK
+// metà vecchio e metà nuovo:
+//  1. **Conservazione**: Le voci di isInstallBookkeeping passano da destPath a staging
+//  2. **Scambio**: destPath viene spostata da parte (staging + "-old"), staging prende il suo posto
+//  3. **Pulizia**: Solo a scambio riuscito la vecchia installazione viene rimossa
+//
+// Se un rename fallisce, destPath e le voci conservate tornano come prima.
+func commitStaging(staging, destPath string) error {
+	existing, err := os.ReadDir(destPath)
+	if os.IsNotExist(err) {
+		if err := os.MkdirAll(utils.LongPath(filepath.Dir(destPath)), 0755); err != nil {
+			return fmt.Errorf("failed to create destination directory: %v", err)
+		}
+		if err := os.Rename(utils.LongPath(staging), utils.LongPath(destPath)); err != nil {
+			return fmt.Errorf("failed to move the JDK to its final location: %v", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var kept []string
+	for _, entry := range existing {
+		if isInstallBookkeeping(entry) {
+			kept = append(kept, entry.Name())
+		}
+	}
+	if err := moveEntries(destPath, staging, kept); err != nil {
+		return fmt.Errorf("failed to keep %v", err)
+	}
+
+	// Un'eventuale directory rimasta da uno scambio interrotto non serve più
+	old := staging + "-old"
+	os.RemoveAll(utils.LongPath(old))
+	if err := os.Rename(utils.LongPath(destPath), utils.LongPath(old)); err != nil {
+		moveEntries(staging, destPath, kept)
+		return fmt.Errorf("failed to move the previous installation aside: %v", err)
+	}
+	if err := os.Rename(utils.LongPath(staging), utils.LongPath(destPath)); err != nil {
+		if rollbackErr := os.Rename(utils.LongPath(old), utils.LongPath(destPath)); rollbackErr != nil {
+			// staging viene rimossa dal chiamante: le voci conservate restano con la vecchia installazione
+			moveEntries(staging, old, kept)
+			return fmt.Errorf("failed to move the JDK to its final location: %v (the previous installation is in %s)", err, old)
+		}
+		moveEntries(staging, destPath, kept)
+		return fmt.Errorf("failed to move the JDK to its final location: %v", err)
+	}
+	if err := os.RemoveAll(utils.LongPath(old)); err != nil {
+		utils.PrintWarning(fmt.Sprintf("Could not remove the previous installation in %s: %v", old, err))
+	}
+	return nil
+}
+
+// moveEntries sposta con un rename le voci indicate da una directory all'altra.
+//
+// Una voce con lo stesso nome in to viene sostituita: le voci conservate da
+// commitStaging prevalgono su quelle dell'archivio. Se un rename fallisce, le
+// voci già spostate tornano in from.
+func moveEntries(from, to string, names []string) error {
+	for i, name := range names {
+		target := utils.LongPath(filepath.Join(to, name))
+		os.RemoveAll(target)
+		if err := os.Rename(utils.LongPath(filepath.Join(from, name)), target); err != nil {
+			// Le voci già spostate tornano indietro: nessuna resta a metà tra le due directory
+			moveEntries(to, from, names[:i])
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// flattenExtractedJDK porta in destPath il contenuto della directory wrapper di un JDK appena estratto.
+//
+// Un fallimento non è un errore: il JDK resta utilizzabile nella struttura
+// originale (risolta da utils.ResolveJDKHome) e viene solo segnalato.
+func flattenExtractedJDK(destPath, archivePath string) {
 	// Try to find and flatten JDK structure if needed
 	jdkRoot, err := findJDKRootDir(destPath)
 	if err != nil {
 		utils.PrintWarning("Could not locate JDK root directory, using extracted structure as-is")
-		return nil
+		return
 	}
 
 	// If JDK is nested, flatten it
@@ -459,8 +655,6 @@ func extractArchive(archivePath, destPath string) error {
 			utils.PrintInfo("JDK extracted successfully but may have nested structure")
 		}
 	}
-
-	return nil
 }
 
 // extractZip estrae un archivio ZIP con protezioni di sicurezza avanzate per Windows.
@@ -473,6 +667,7 @@ func extractArchive(archivePath, destPath string) error {
 //   - **Zip slip protection**: Validazione rigorosa percorsi file
 //   - **Path normalization**: Pulizia e validazione nomi file
 //   - **Directory confinement**: Prevenzione scrittura fuori target
+//   - **Zip bomb**: Limiti su dimensione estratta, numero di voci e rapporto di compressione (vedi utils.ArchiveGuard)
 //   - **Resource management**: Chiusura automatica handle file
 //
 // Gestione file e directory:
@@ -480,33 +675,76 @@ func extractArchive(archivePath, destPath string) error {
 //   - **File extraction**: Preservazione contenuto e metadati
 //   - **Permission handling**: Gestione appropriata permessi Windows
 //   - **Unicode support**: Supporto completo caratteri internazionali
+//   - **Resume**: Salta i file già estratti con stessa dimensione e data (vedi utils.ExtractedFileMatches)
 //
 // Parametri:
 //   - src: percorso archivio ZIP sorgente
 //   - dest: directory destinazione estrazione
+//   - progress: stile del progresso, misurato sulla dimensione non compressa dei file
 //
 // Ritorna errore se l'estrazione fallisce per qualsiasi motivo.
-func extractZip(src, dest string) error {
+func extractZip(ctx context.Context, src, dest string, progress utils.ProgressMode) error {
 	r, err := zip.OpenReader(src)
 	if err != nil {
 		return err
 	}
 	defer r.Close()
 
+	var total int64
 	for _, f := range r.File {
+		total += int64(f.UncompressedSize64)
+	}
+	cfg, _ := utils.LoadConfigOrDefault()
+	guard := utils.NewArchiveGuard(utils.ArchiveExtractLimits(cfg))
+	if info, err := os.Stat(src); err == nil {
+		guard.Compressed(info.Size())
+	}
+	bar := utils.NewProgress(progress, "[EXTRACT]", total, utils.ProgressBytes)
+	if err := extractZipFiles(ctx, r.File, dest, bar, guard); err != nil {
+		bar.Abort()
+		return err
+	}
+	bar.Finish()
+	return nil
+}
+
+// extractZipFiles estrae le voci di un archivio ZIP in dest, aggiornando bar dopo ogni file.
+//
+// Si ferma prima della voce successiva quando ctx viene annullato, e con
+// utils.ErrArchiveLimit se l'archivio supera i limiti di guard.
+func extractZipFiles(ctx context.Context, files []*zip.File, dest string, bar *utils.Progress, guard *utils.ArchiveGuard) error {
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := guard.Entry(); err != nil {
+			return err
+		}
+		bar.Add(int64(f.UncompressedSize64))
+
 		// Clean the file path to prevent zip slip attacks
 		cleanPath := filepath.Join(dest, f.Name)
 		if !strings.HasPrefix(cleanPath, filepath.Clean(dest)+string(os.PathSeparator)) {
 			continue
 		}
+		// Paths longer than 260 characters on Windows without LongPathsEnabled
+		target := utils.LongPath(cleanPath)
 
 		if f.FileInfo().IsDir() {
-			os.MkdirAll(cleanPath, 0755)
+			os.MkdirAll(target, 0755)
+			continue
+		}
+
+		// Already extracted by a previous (interrupted) run
+		if utils.ExtractedFileMatches(target, int64(f.UncompressedSize64), f.Modified) {
+			if err := guard.Extracted(int64(f.UncompressedSize64)); err != nil {
+				return err
+			}
 			continue
 		}
 
 		// Create the directories for file
-		if err := os.MkdirAll(filepath.Dir(cleanPath), 0755); err != nil {
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
 			return err
 		}
 
@@ -516,19 +754,24 @@ func extractZip(src, dest string) error {
 			return err
 		}
 
-		outFile, err := os.OpenFile(cleanPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
+		outFile, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
 		if err != nil {
 			rc.Close()
 			return err
 		}
 
-		_, err = io.Copy(outFile, rc)
+		_, err = io.Copy(io.MultiWriter(outFile, guard), rc)
 		outFile.Close()
 		rc.Close()
 
 		if err != nil {
 			return err
 		}
+
+		// Set the archive timestamp last so that truncated files are never considered complete
+		if err := os.Chtimes(target, f.Modified, f.Modified); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -547,27 +790,52 @@ func extractZip(src, dest string) error {
 // Gestione metadati:
 //   - **File regolari**: Preservazione contenuto e dimensione
 //   - **Directory**: Ricreazione struttura gerarchica
-//   - **Permessi**: Conversione permessi file → Windows
-//   - **Timestamp**: Preservazione dove possibile
+//   - **Permessi**: Preservati, compresi i bit di esecuzione (ignorati da NTFS)
+//   - **Link**: Simbolici e fisici ricreati dopo i file (vedi createTarLinks)
+//   - **Timestamp**: Preservati, e usati per saltare i file già estratti (resume)
 //
 // Sicurezza TAR:
 //   - **Tar slip protection**: Validazione percorsi come ZIP
-//   - **Type validation**: Gestione solo file regolari e directory
+//   - **Limiti**: Dimensione estratta, numero di voci e rapporto di compressione
+//     (extract-max-size, extract-max-files, extract-max-ratio; vedi utils.ArchiveGuard)
+//   - **Type validation**: Solo file regolari, directory e link; i link che
+//     puntano fuori da dest non vengono creati
 //   - **Path cleaning**: Normalizzazione percorsi cross-platform
 //
 // Parametri:
 //   - src: percorso archivio TAR.GZ sorgente
 //   - dest: directory destinazione estrazione
+//   - progress: stile del progresso, misurato sui byte compressi letti dall'archivio
 //
 // Ritorna errore per problemi decompressione/estrazione.
-func extractTarGz(src, dest string) error {
+func extractTarGz(ctx context.Context, src, dest string, progress utils.ProgressMode) error {
 	file, err := os.Open(src)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	gzr, err := gzip.NewReader(file)
+	var total int64
+	if info, err := file.Stat(); err == nil {
+		total = info.Size()
+	}
+	bar := utils.NewProgress(progress, "[EXTRACT]", total, utils.ProgressBytes)
+	if err := extractTarStream(ctx, io.TeeReader(file, bar), dest); err != nil {
+		bar.Abort()
+		return err
+	}
+	bar.Finish()
+	return nil
+}
+
+// extractTarStream estrae in dest un archivio TAR compresso con gzip letto da r.
+//
+// Si ferma prima della voce successiva quando ctx viene annullato, e con
+// utils.ErrArchiveLimit se l'archivio supera i limiti di estrazione configurati.
+func extractTarStream(ctx context.Context, r io.Reader, dest string) error {
+	cfg, _ := utils.LoadConfigOrDefault()
+	guard := utils.NewArchiveGuard(utils.ArchiveExtractLimits(cfg))
+	gzr, err := gzip.NewReader(guard.CompressedReader(r))
 	if err != nil {
 		return err
 	}
@@ -575,7 +843,11 @@ func extractTarGz(src, dest string) error {
 
 	tr := tar.NewReader(gzr)
 
+	var links []tarLink
 	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		header, err := tr.Next()
 		if err == io.EOF {
 			break
@@ -583,45 +855,263 @@ func extractTarGz(src, dest string) error {
 		if err != nil {
 			return err
 		}
+		if err := guard.Entry(); err != nil {
+			return err
+		}
 
 		// Clean the file path to prevent tar slip attacks
 		cleanPath := filepath.Join(dest, header.Name)
 		if !strings.HasPrefix(cleanPath, filepath.Clean(dest)+string(os.PathSeparator)) {
 			continue
 		}
+		// Paths longer than 260 characters on Windows without LongPathsEnabled
+		target := utils.LongPath(cleanPath)
 
 		switch header.Typeflag {
 		case tar.TypeDir:
-			if err := os.MkdirAll(cleanPath, 0755); err != nil {
+			if err := os.MkdirAll(target, 0755); err != nil {
 				return err
 			}
 		case tar.TypeReg:
+			// Already extracted by a previous (interrupted) run
+			if utils.ExtractedFileMatches(target, header.Size, header.ModTime) {
+				if err := guard.Extracted(header.Size); err != nil {
+					return err
+				}
+				continue
+			}
+
 			// Create the directories for file
-			if err := os.MkdirAll(filepath.Dir(cleanPath), 0755); err != nil {
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
 				return err
 			}
 
 			// Extract file
-			outFile, err := os.Create(cleanPath)
+			outFile, err := os.Create(target)
 			if err != nil {
 				return err
 			}
 
-			if _, err := io.Copy(outFile, tr); err != nil {
+			if _, err := io.Copy(io.MultiWriter(outFile, guard), tr); err != nil {
 				outFile.Close()
 				return err
 			}
 
 			outFile.Close()
 
-			// Set file permissions
-			if err := os.Chmod(cleanPath, os.FileMode(header.Mode)); err != nil {
+			// Set file permissions, executable bits included
+			if err := os.Chmod(target, header.FileInfo().Mode().Perm()); err != nil {
 				return err
 			}
+
+			// Set the archive timestamp last so that truncated files are never considered complete
+			if err := os.Chtimes(target, header.ModTime, header.ModTime); err != nil {
+				return err
+			}
+		case tar.TypeSymlink, tar.TypeLink:
+			links = append(links, tarLink{header: header, path: cleanPath})
 		}
 	}
 
-	return nil
+	return createTarLinks(dest, links)
+}
+
+// tarLink è un link simbolico o fisico di un archivio tar, in attesa di essere creato.
+type tarLink struct {
+	header *tar.Header
+	path   string // Percorso del link in dest
+}
+
+// createTarLinks crea in dest i link di un archivio tar, dopo tutti i file regolari.
+//
+// Creandoli per ultimi nessun file dell'archivio viene scritto attraverso un
+// link, e su Windows la destinazione esiste già quando os.Symlink deve capire se
+// è una directory. Alcuni archivi ne dipendono (es. GraalVM, o i JDK Linux per
+// immagini Docker scaricati con --os). I link che non è possibile creare (NTFS
+// senza il privilegio per i link simbolici, destinazione fuori da dest) non
+// bloccano l'estrazione: vengono elencati in utils.ArchiveLinksFile.
+func createTarLinks(dest string, links []tarLink) error {
+	var skipped []utils.ArchiveLink
+	for _, link := range links {
+		if err := createTarLink(dest, link); err != nil {
+			kind := "symlink"
+			if link.header.Typeflag == tar.TypeLink {
+				kind = "hardlink"
+			}
+			skipped = append(skipped, utils.ArchiveLink{Path: link.header.Name, Target: link.header.Linkname, Type: kind, Reason: err.Error()})
+		}
+	}
+	if len(skipped) == 0 {
+		return nil
+	}
+	utils.PrintWarning(fmt.Sprintf("%d link(s) of the archive could not be created on this filesystem, see %s", len(skipped), utils.ArchiveLinksFile))
+	return utils.SaveArchiveLinks(dest, skipped)
+}
+
+// createTarLink crea un singolo link in dest, sostituendo quello lasciato da un'estrazione interrotta.
+//
+// Percorso del link, destinazione del link simbolico e sorgente del link fisico
+// vengono risolti sul disco con resolveInDest, seguendo i link già creati: un
+// archivio non può uscire dall'installazione concatenando link che, presi uno
+// per uno, sembrano interni (es. "s -> ." seguito da "s/x -> .."). Il limite è
+// la directory di primo livello che contiene il link, non dest: l'appiattimento
+// (flattenExtractedJDK) la porta al posto di dest, e un link verso dest
+// finirebbe fuori dall'installazione.
+// Un link fisico che il filesystem non supporta diventa una copia del file.
+func createTarLink(dest string, link tarLink) error {
+	rel, err := filepath.Rel(filepath.Clean(dest), link.path)
+	if err != nil {
+		return err
+	}
+	components := pathComponents(rel)
+	top := dest
+	if len(components) > 1 {
+		top = filepath.Join(dest, components[0])
+		components = components[1:]
+		if err := os.MkdirAll(utils.LongPath(top), 0755); err != nil {
+			return err
+		}
+	}
+	root, err := filepath.EvalSymlinks(top)
+	if err != nil {
+		return err
+	}
+	if realDest, err := filepath.EvalSymlinks(dest); err != nil || !isWithin(realDest, root) {
+		return errOutsideInstallation
+	}
+
+	dirComponents := components[:len(components)-1]
+	parent, err := resolveInDest(root, dirComponents)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(utils.LongPath(parent), 0755); err != nil {
+		return err
+	}
+	target := utils.LongPath(filepath.Join(parent, components[len(components)-1]))
+	if info, err := os.Lstat(target); err == nil {
+		if info.IsDir() {
+			return errors.New("a directory with the same name exists")
+		}
+		if err := os.Remove(target); err != nil {
+			return err
+		}
+	}
+
+	linkname := filepath.FromSlash(link.header.Linkname)
+	if filepath.IsAbs(linkname) {
+		return errOutsideInstallation
+	}
+	if link.header.Typeflag == tar.TypeSymlink {
+		if _, err := resolveInDest(root, append(append([]string{}, dirComponents...), pathComponents(linkname)...)); err != nil {
+			return err
+		}
+		return os.Symlink(linkname, target)
+	}
+
+	// La sorgente di un link fisico è relativa alla radice dell'archivio
+	sourceComponents := pathComponents(linkname)
+	if top != dest {
+		if len(sourceComponents) < 2 || sourceComponents[0] != filepath.Base(top) {
+			return errOutsideInstallation
+		}
+		sourceComponents = sourceComponents[1:]
+	}
+	source, err := resolveInDest(root, sourceComponents)
+	if err != nil {
+		return err
+	}
+	if err := os.Link(utils.LongPath(source), target); err == nil {
+		return nil
+	}
+	return copyExtractedFile(utils.LongPath(source), target, link.header.FileInfo().Mode().Perm())
+}
+
+// errOutsideInstallation indica un link dell'archivio che porterebbe fuori dalla directory di estrazione.
+var errOutsideInstallation = errors.New("the target is outside the installation")
+
+// maxLinkHops limita i link simbolici seguiti da resolveInDest, come ELOOP nei sistemi Unix.
+const maxLinkHops = 40
+
+// resolveInDest risolve un percorso relativo a root seguendo i link simbolici già presenti sul disco.
+//
+// A differenza di filepath.Join, che tratta ".." sul testo del percorso, ogni
+// componente esistente che è un link viene sostituito dalla sua destinazione
+// prima di applicare i componenti successivi, come fa il sistema operativo. I
+// componenti che non esistono ancora vengono accodati così come sono.
+//
+// Parametri:
+//
+//	root string         - Directory entro cui restare, già risolta con filepath.EvalSymlinks
+//	components []string - Componenti del percorso relativo (vedi pathComponents)
+//
+// Restituisce il percorso reale, o errOutsideInstallation se in un qualsiasi
+// punto della risoluzione il percorso esce da root.
+func resolveInDest(root string, components []string) (string, error) {
+	current := root
+	hops := 0
+	for len(components) > 0 {
+		component := components[0]
+		components = components[1:]
+		switch component {
+		case "", ".":
+			continue
+		case "..":
+			current = filepath.Dir(current)
+			if !isWithin(root, current) {
+				return "", errOutsideInstallation
+			}
+			continue
+		}
+
+		next := filepath.Join(current, component)
+		info, err := os.Lstat(utils.LongPath(next))
+		if err != nil || info.Mode()&os.ModeSymlink == 0 {
+			current = next
+			continue
+		}
+		if hops++; hops > maxLinkHops {
+			return "", errors.New("too many levels of symbolic links")
+		}
+		linkname, err := os.Readlink(utils.LongPath(next))
+		if err != nil {
+			return "", err
+		}
+		if filepath.IsAbs(linkname) {
+			return "", errOutsideInstallation
+		}
+		components = append(pathComponents(linkname), components...)
+	}
+	return current, nil
+}
+
+// isWithin indica se path è root o si trova al suo interno.
+func isWithin(root, path string) bool {
+	return path == root || strings.HasPrefix(path, root+string(os.PathSeparator))
+}
+
+// pathComponents divide un percorso nei suoi componenti senza semplificare "..", che va risolto sul disco.
+func pathComponents(path string) []string {
+	return strings.FieldsFunc(filepath.ToSlash(path), func(r rune) bool { return r == '/' })
+}
+
+// copyExtractedFile copia un file già estratto, per i link fisici non supportati dal filesystem.
+func copyExtractedFile(source, target string, mode os.FileMode) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
 }
 
 // findJDKRootDir localizza la directory root effettiva del JDK all'interno dell'estrazione.
@@ -763,3 +1253,29 @@ func flattenJDKDirectory(jdkRootDir, targetDir, archivePath string) error {
 
 	return nil
 }
+
+// hasPartialExtraction indica se la directory contiene già file estratti oltre all'archivio.
+//
+// Applicata alla directory temporanea di un'installazione (utils.StagingDir),
+// indica un'estrazione terminata bruscamente (es. processo ucciso): invece di
+// ripulire la directory, l'estrazione riprende e scrive solo i file mancanti o
+// incompleti.
+func hasPartialExtraction(jdkDir string) bool {
+	entries, err := os.ReadDir(jdkDir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if isInstallBookkeeping(entry) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// isInstallBookkeeping indica se una voce di una directory di installazione è un archivio, un download parziale o i metadati, e non parte del JDK.
+func isInstallBookkeeping(entry os.DirEntry) bool {
+	name := entry.Name()
+	return name == utils.InstallMetadataFile || (!entry.IsDir() && (utils.IsArchiveFile(name) || strings.HasSuffix(name, partialDownloadSuffix)))
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"jenvy/internal/utils"
+)
+
+// pendingExtraction è una directory di versione con un archivio ancora da estrarre.
+type pendingExtraction struct {
+	Name    string // Nome directory (es. "JDK-17.0.9+9")
+	Path    string // Directory di versione, destinazione dell'estrazione
+	Archive string // Archivio trovato da findArchiveInDirectory
+	Status  string // extracted | failed | cancelled
+	Err     error
+}
+
+// findPendingExtractions elenca le directory con un archivio ma senza un JDK estratto.
+//
+// Le installazioni con keep-archives=true conservano l'archivio accanto al JDK
+// già estratto: vengono escluse perché ResolveJDKHome le riconosce come valide.
+// Sono esclusi anche gli archivi per altri sistemi scaricati con 'download --os'.
+func findPendingExtractions(versionsDir string) ([]*pendingExtraction, error) {
+	scans, err := scanInstallations(versionsDir, false)
+	if err != nil {
+		return nil, err
+	}
+	var pending []*pendingExtraction
+	for _, scan := range scans {
+		if scan.ArchivePath == "" || scan.JDKHome != "" {
+			continue
+		}
+		if _, foreign := utils.IsForeignInstall(scan.Path); foreign {
+			continue
+		}
+		pending = append(pending, &pendingExtraction{Name: scan.Name, Path: scan.Path, Archive: scan.ArchivePath})
+	}
+	return pending, nil
+}
+
+// extractAllArchives gestisce 'jenvy extract --all': estrae ogni archivio in sospeso.
+//
+// È la controparte massiva di 'jenvy extract <version>', utile dopo più download
+// senza estrazione o dopo aver copiato archivi in ~/.jenvy/versions. Ogni archivio
+// viene estratto (e appiattito, salvo --no-flatten) nella propria directory, poi
+// rimosso salvo keep-archives=true, come nell'estrazione singola. Fino a jobs
+// estrazioni procedono in parallelo (utils.ForEachParallel).
+//
+// Parametri:
+//
+//	versionsDir string - Directory delle versioni Jenvy
+//	flatten bool       - false con --no-flatten
+//	assumeYes bool     - true con --yes: nessuna conferma
+//	jobs int           - Estrazioni concorrenti (--jobs)
+//
+// Esempio di utilizzo:
+//
+//	jenvy extract --all --yes --jobs=4
+func extractAllArchives(versionsDir string, flatten, assumeYes bool, jobs int) error {
+	pending, err := findPendingExtractions(versionsDir)
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Cannot access versions directory: %v", err))
+	}
+	if len(pending) == 0 {
+		utils.PrintInfo("No archives waiting to be extracted")
+		return nil
+	}
+
+	utils.PrintInfo(fmt.Sprintf("The following archives will be extracted in %s:", versionsDir))
+	for _, item := range pending {
+		fmt.Printf("  - %s (%s)\n", item.Name, filepath.Base(item.Archive))
+	}
+	if !assumeYes && !askConfirmation("\n[?] Do you want to proceed? (y/N): ") {
+		utils.PrintInfo("Extraction cancelled by user")
+		return utils.ExitWith(utils.ExitGeneric, errors.New("extraction cancelled by user"))
+	}
+	fmt.Println()
+
+	keepArchives := false
+	if cfg, err := utils.LoadConfigOrDefault(); err == nil && cfg.KeepArchives != nil {
+		keepArchives = *cfg.KeepArchives
+	}
+
+	// Ctrl-C interrompe le estrazioni in corso (vedi extractStaged) e salta quelle non ancora iniziate
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	utils.ForEachParallel(len(pending), jobs, func(i int) {
+		item := pending[i]
+		if ctx.Err() != nil {
+			item.Status = "cancelled"
+			return
+		}
+		utils.PrintInfo(fmt.Sprintf("[%d/%d] Extracting %s", i+1, len(pending), item.Name))
+		if err := extractArchive(item.Archive, item.Path, flatten, utils.ProgressNone); err != nil {
+			if errors.Is(err, errExtractionInterrupted) {
+				item.Status = "cancelled"
+				return
+			}
+			item.Status, item.Err = "failed", err
+			utils.PrintError(fmt.Sprintf("%s: %v", item.Name, err))
+			return
+		}
+		if _, ok := utils.ResolveJDKHome(item.Path); !ok {
+			item.Status, item.Err = "failed", errors.New("extracted directory is not a valid JDK")
+			utils.PrintError(fmt.Sprintf("%s: %v", item.Name, item.Err))
+			return
+		}
+		if !keepArchives {
+			removeExtractedArchive(item.Archive)
+		}
+		recordInstallManifest(item.Path)
+		item.Status = "extracted"
+		utils.PrintSuccess(fmt.Sprintf("[%d/%d] %s extracted", i+1, len(pending), item.Name))
+	})
+
+	return printExtractSummary(pending)
+}
+
+// printExtractSummary stampa l'esito di ogni estrazione di 'extract --all' e restituisce l'errore complessivo.
+func printExtractSummary(pending []*pendingExtraction) error {
+	fmt.Println()
+	utils.PrintSection("[SUMMARY] JDK EXTRACTION")
+	failed, cancelled := 0, 0
+	for _, item := range pending {
+		color := utils.Green
+		switch item.Status {
+		case "failed":
+			color = utils.Red
+			failed++
+		case "cancelled":
+			color = utils.Red
+			cancelled++
+		}
+		line := fmt.Sprintf("  %-34s %s", item.Name, utils.ColorText(item.Status, color))
+		if item.Err != nil {
+			line += fmt.Sprintf(" (%v)", item.Err)
+		}
+		fmt.Println(line)
+	}
+	fmt.Println()
+	utils.PrintInfo(fmt.Sprintf("%d extracted, %d failed, %d cancelled", len(pending)-failed-cancelled, failed, cancelled))
+
+	summary := fmt.Sprintf("%d archive(s)", len(pending))
+	if failed > 0 {
+		err := fmt.Errorf("%d extraction(s) failed", failed)
+		notifyCompletion("Extraction", summary, err)
+		return utils.ExitWith(utils.ExitExtraction, err)
+	}
+	if cancelled > 0 {
+		err := errors.New("extraction interrupted")
+		notifyCompletion("Extraction", summary, err)
+		return utils.ExitWith(utils.ExitInterrupted, err)
+	}
+	notifyCompletion("Extraction", summary, nil)
+	return nil
+}
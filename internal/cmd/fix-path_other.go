@@ -0,0 +1,20 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"fmt"
+
+	"jenvy/internal/utils"
+)
+
+// FixPath ripulisce il PATH di sistema di Windows; su Linux e macOS non ha effetto.
+//
+// Il PATH di queste piattaforme è costruito dai profili della shell a ogni avvio,
+// non salvato in un registro: jenvy vi aggiunge solo la riga di
+// /etc/profile.d/jenvy.sh, senza duplicati.
+func FixPath() error {
+	utils.PrintInfo("fix-path only applies to the Windows system PATH")
+	utils.PrintInfo(fmt.Sprintf("On this platform jenvy adds %s to PATH from %s", javaHomeBinEntry, systemEnvironmentSource()))
+	return nil
+}
@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
+
+	"jenvy/internal/utils"
 )
 
 // FixPath esegue la pulizia automatica della variabile d'ambiente PATH di sistema Windows.
@@ -40,7 +43,7 @@ import (
 // Esempi di utilizzo:
 //
 //	jenvy fix-path  # Pulisce automaticamente il PATH di sistema
-func FixPath() {
+func FixPath() error {
 
 	fmt.Println("Jenvy PATH REPAIR UTILITY")
 	fmt.Println("==========================")
@@ -55,7 +58,7 @@ func FixPath() {
 	output, err := cmd.Output()
 	if err != nil {
 		fmt.Printf("[ERROR] Error reading system PATH: %v\n", err)
-		return
+		return utils.ExitWith(utils.ExitGeneric, err)
 	}
 
 	// parseRegistryOutput analizza l'output del comando reg.exe per estrarre
@@ -77,7 +80,7 @@ func FixPath() {
 	}
 	if currentPath == "" {
 		fmt.Println("[ERROR] Current PATH is empty or not found")
-		return
+		return utils.ExitWith(utils.ExitNotFound, errors.New("system PATH not found"))
 	}
 
 	fmt.Printf("Current SYSTEM PATH entries: %d\n", len(strings.Split(currentPath, ";")))
@@ -124,7 +127,7 @@ func FixPath() {
 	// non è necessario modificare il registro di sistema.
 	if duplicatesRemoved == 0 && emptyEntriesRemoved == 0 {
 		fmt.Println("[SUCCESS] PATH is already clean, no duplicates found")
-		return
+		return nil
 	}
 
 	// Ricostruisce il PATH pulito utilizzando il separatore ";" di Windows.
@@ -150,13 +153,18 @@ func FixPath() {
 	if err != nil {
 		fmt.Printf("[ERROR] Error updating SYSTEM PATH: %v\n", err)
 		fmt.Printf("[INFO] TIP: You may need to run as Administrator\n")
-		return
+		if !isRunningAsAdmin() {
+			return utils.ExitWith(utils.ExitPermission, err)
+		}
+		return utils.ExitWith(utils.ExitGeneric, err)
 	}
 
+	broadcastEnvironmentChange()
 	fmt.Println("[SUCCESS] SYSTEM PATH cleaned successfully!")
 	fmt.Println()
 	fmt.Println("[INFO] IMPORTANT: Restart your terminal or VS Code to see the changes")
 	fmt.Println("   Or run: refreshenv (if you have Chocolatey installed)")
 	fmt.Println()
 	fmt.Printf("You can verify the changes by running: echo $PATH\n")
+	return nil
 }
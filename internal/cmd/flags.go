@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"jenvy/internal/utils"
+)
+
+// commandAliases associa le forme brevi al nome completo del comando.
+var commandAliases = map[string]string{
+	"rl":      "remote-list",
+	"dl":      "download",
+	"ex":      "extract",
+	"l":       "list",
+	"u":       "use",
+	"rm":      "remove",
+	"upgrade": "update",
+	"fp":      "fix-path",
+	"cp":      "configure-private",
+	"cs":      "config-show",
+	"cr":      "config-reset",
+}
+
+// commandFlags elenca i comandi con le rispettive opzioni (vedi utils.NormalizeArgs).
+//
+// Ogni comando di main.go deve comparire qui: un comando assente non viene
+// riconosciuto quando è preceduto da opzioni globali. Ogni opzione letta dal
+// comando va elencata, con valore o booleana: le altre vengono rifiutate prima
+// del dispatch. Le opzioni globali (utils.GlobalValueFlags, utils.GlobalBoolFlags)
+// valgono per tutti i comandi.
+var commandFlags = map[string]utils.FlagSpec{
+	"remote-list": {
+		Values: []string{"--provider", "--jdk", "--since", "--type", "--fields", "--output"},
+		Bools:  []string{"--all", "--major-only", "--latest", "--lts-only", "--ea", "--javafx", "--count", "--raw"},
+	},
+	"download": {
+		Values: []string{"--provider", "--output", "--extract-to", "--archive-name", "--manifest", "--checksum",
+			"--checksum-algorithm", "--arch", "--os", "--progress", "--type"},
+		Bools: []string{"--all-lts", "--no-flatten", "--resume", "--stream", "--ea", "--javafx", "--resolve-only",
+			"--verbose", "--keep-archive", "--delete-archive"},
+	},
+	"install": {Values: []string{"--provider"}, Bools: []string{"--use", "--global", "--user", "--local"}},
+	"extract": {Values: []string{"--jobs"}, Bools: []string{"--all", "--no-flatten"}},
+	"list":    {Values: []string{"--provider", "--output"}, Bools: []string{"--tree", "--duplicates", "--check-updates"}},
+	"use": {
+		Values: []string{"--shell"},
+		Bools:  []string{"--temporary", "--session", "--global", "--user", "--local", "--from-build", "--dry-run", "--install"},
+	},
+	"local":             {Bools: []string{"--unset"}},
+	"alias":             {Bools: []string{"--delete"}},
+	"default":           {Bools: []string{"--global", "--user", "--no-use", "--unset"}},
+	"current":           {Bools: []string{"--version-only"}},
+	"which":             {Bools: []string{"--all", "--with-version", "--version-only"}},
+	"remove":            {Bools: []string{"--all"}, Shorts: map[byte]string{'a': "--all"}},
+	"update":            {Values: []string{"--jobs"}, Bools: []string{"--all", "--no-reactivate", "--prune", "--keep-archive", "--delete-archive"}},
+	"verify":            {Bools: []string{"--all"}},
+	"completion":        {Values: []string{"--shell"}, Bools: []string{"--all", "--install-all"}},
+	"fix-path":          {},
+	"diagnose-path":     {},
+	"doctor":            {Bools: []string{"--offline"}},
+	"resolve":           {Bools: []string{"--json"}},
+	"logs":              {Values: []string{"--lines"}, Bools: []string{"--clear"}},
+	"providers":         {Bools: []string{"--json"}},
+	"self-test":         {},
+	"shell-init":        {},
+	"init":              {},
+	"uninstall":         {},
+	"configure-private": {},
+	"config-show":       {Bools: []string{"--json", "--reveal"}},
+	"config-reset":      {},
+	"config":            {},
+}
+
+// canonicalCommand restituisce il nome completo di un comando o alias (vuoto se sconosciuto).
+func canonicalCommand(name string) string {
+	if full, ok := commandAliases[name]; ok {
+		return full
+	}
+	if _, ok := commandFlags[name]; ok {
+		return name
+	}
+	return ""
+}
+
+// versionWriters sono i comandi che modificano ~/.jenvy/versions, quindi eseguiti con il lock di ~/.jenvy.
+var versionWriters = map[string]bool{
+	"download": true,
+	"install":  true,
+	"extract":  true,
+	"remove":   true,
+	"update":   true,
+}
+
+// ModifiesVersions indica se la riga di comando, già normalizzata, modifica ~/.jenvy/versions.
+//
+// main acquisisce il lock di ~/.jenvy (vedi utils.AcquireLock) solo per questi
+// comandi, così 'list', 'use' e simili non attendono un download in corso.
+// 'use' acquisisce il lock da sé solo se deve scaricare una versione mancante
+// (vedi installJDKForUse); 'download --resolve-only' non scrive nulla.
+func ModifiesVersions(args []string) bool {
+	if len(args) < 2 {
+		return false
+	}
+	command := canonicalCommand(args[1])
+	_, flags := utils.SplitArgs(args[2:])
+	if command == "download" {
+		return !slices.Contains(flags, "--resolve-only")
+	}
+	return versionWriters[command]
+}
+
+// NormalizeCommandLine porta la riga di comando alla forma letta dai comandi.
+//
+// Il comando viene spostato in args[1] anche se preceduto da opzioni globali
+// (es. 'jenvy --yes download 17'), poi i suoi argomenti vengono normalizzati
+// con utils.NormalizeArgs: opzioni prima o dopo gli argomenti posizionali,
+// "--nome valore" oltre a "--nome=valore", opzioni brevi combinate (es. 'rm -ay').
+// Senza un comando noto (es. 'jenvy --help') e per i comandi interni "__" la
+// riga di comando resta invariata.
+//
+// Parametri:
+//
+//	args []string - Riga di comando completa (os.Args)
+//
+// Restituisce:
+//
+//	[]string - Riga di comando normalizzata; in caso di errore solo programma e
+//	           comando, per mostrarne l'aiuto (vedi ShowCommandHelp)
+//	bool     - true se è stato richiesto l'aiuto del comando ('jenvy <comando> --help')
+//	error    - Opzione sconosciuta per il comando o opzione con valore senza valore
+//
+// Esempio di utilizzo:
+//
+//	args, help, err := NormalizeCommandLine([]string{"jenvy", "--yes", "download", "--provider", "azul", "17"})
+//	// args = ["jenvy", "download", "17", "--provider=azul", "--yes"]
+func NormalizeCommandLine(args []string) ([]string, bool, error) {
+	index := -1
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if canonicalCommand(arg) != "" {
+			index = i
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			return args, false, nil // Comando sconosciuto: lo segnala main
+		}
+		// Il valore di un'opzione globale non è un comando (es. --timeout 90s)
+		for _, name := range utils.GlobalValueFlags {
+			if arg == name {
+				i++
+			}
+		}
+	}
+	if index < 0 {
+		return args, false, nil
+	}
+
+	rest := append(append([]string{}, args[1:index]...), args[index+1:]...)
+	normalized, help, err := utils.NormalizeArgs(rest, commandFlags[canonicalCommand(args[index])])
+	if err != nil {
+		return []string{args[0], args[index]}, false, err
+	}
+	return append([]string{args[0], args[index]}, normalized...), help, nil
+}
+
+// commandLine è la riga di comando normalizzata, prima che main ne tolga le opzioni globali.
+var commandLine []string
+
+// SetCommandLine memorizza la riga di comando normalizzata da NormalizeCommandLine.
+//
+// main toglie da os.Args le opzioni globali (--yes, --timeout, --ca-cert...) dopo
+// averle applicate: il comando rieseguito con privilegi elevati deve riceverle di nuovo.
+func SetCommandLine(args []string) {
+	commandLine = args
+}
+
+// elevatedArgs restituisce gli argomenti con cui rieseguire il comando con privilegi elevati.
+func elevatedArgs() []string {
+	if len(commandLine) > 1 {
+		return commandLine[1:]
+	}
+	return os.Args[1:]
+}
+
+// ShowCommandHelp mostra l'aiuto di un singolo comando ('jenvy <comando> --help', 'jenvy help <comando>').
+//
+// Riporta le righe dell'help generale relative al comando (vedi helpSections),
+// seguite dalle opzioni valide per ogni comando. Un comando sconosciuto
+// restituisce un errore con utils.ExitGeneric.
+func ShowCommandHelp(command string) error {
+	name := canonicalCommand(command)
+	if name == "" {
+		utils.PrintInfo("Use 'jenvy --help' to see all available commands")
+		return utils.Fail(utils.ExitGeneric, "Unknown command: "+command)
+	}
+
+	names := []string{name}
+	for alias, full := range commandAliases {
+		if full == name {
+			names = append(names, alias)
+		}
+	}
+	var usage, global []string
+	for _, section := range helpSections {
+		for _, line := range section.Lines {
+			if strings.HasPrefix(line, "  jenvy <command> ") {
+				global = append(global, line)
+				continue
+			}
+			for _, candidate := range names {
+				if rest, ok := strings.CutPrefix(line, "  jenvy "+candidate); ok && (rest == "" || rest[0] == ' ') {
+					usage = append(usage, line)
+					break
+				}
+			}
+		}
+	}
+
+	fmt.Println(utils.SectionText("jenvy " + name))
+	for _, line := range usage {
+		fmt.Println(line)
+	}
+	if len(usage) == 0 {
+		fmt.Println("  jenvy " + name)
+	}
+	fmt.Println("")
+	fmt.Println(utils.SectionText("Options valid for every command:"))
+	for _, line := range global {
+		fmt.Println(line)
+	}
+	return nil
+}
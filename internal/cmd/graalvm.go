@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"jenvy/internal/utils"
+)
+
+// nativeImageLabel riassume per 'jenvy list' lo stato di native-image di un'installazione.
+//
+// Restituisce una stringa vuota per i JDK che non sono GraalVM.
+func nativeImageLabel(status utils.NativeImageStatus) string {
+	switch {
+	case !status.GraalVM:
+		return ""
+	case status.Bundled:
+		return "bundled"
+	case status.HasGu:
+		return "not installed (gu install native-image)"
+	default:
+		return "not available"
+	}
+}
+
+// reportNativeImage spiega, dopo l'estrazione di un GraalVM, come usare native-image.
+//
+// Le distribuzioni GraalVM for JDK 17+ includono native-image in bin; le release
+// 22.x lo installano con il Graal Updater (gu), per cui viene mostrato il comando
+// esatto da eseguire. Per gli altri JDK non stampa nulla.
+//
+// Parametri:
+//
+//	installDir string - Directory di installazione appena estratta
+func reportNativeImage(installDir string) {
+	home, ok := utils.ResolveJDKHome(installDir)
+	if !ok {
+		return
+	}
+	status := utils.DetectNativeImage(home)
+	switch {
+	case !status.GraalVM:
+		return
+	case status.Bundled:
+		utils.PrintInfo(fmt.Sprintf("GraalVM native-image is bundled: %s", filepath.Join(home, "bin", "native-image.cmd")))
+		utils.PrintInfo("native-image needs the Visual Studio C++ build tools: run it from an 'x64 Native Tools' prompt")
+	case status.HasGu:
+		utils.PrintWarning("This GraalVM release does not bundle native-image")
+		utils.PrintInfo(fmt.Sprintf("Install it with: \"%s\" install native-image", filepath.Join(home, "bin", "gu.cmd")))
+	}
+}
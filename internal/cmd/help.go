@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
+	"unicode/utf8"
 
 	"jenvy/internal/ui"
 	"jenvy/internal/utils"
@@ -41,57 +43,14 @@ func ShowHelp() {
 	fmt.Println(" " + utils.ColorText("[LATEST]", utils.BrightCyan) + " Latest patch version")
 	fmt.Println("")
 
-	fmt.Println(utils.SectionText("[COMMANDS] AVAILABLE COMMANDS:"))
-	fmt.Println("─────────────────────")
-	fmt.Println("  jenvy remote-list (rl)                   # Show recommended versions (default: Adoptium)")
-	fmt.Println("  jenvy remote-list --provider=azul        # Specify provider (adoptium|azul|liberica|private)")
-	fmt.Println("  jenvy remote-list --all                  # Show versions from all providers")
-	fmt.Println("  jenvy remote-list --latest               # Show only the latest version")
-	fmt.Println("  jenvy remote-list --major-only           # Show only major releases (e.g. 17.0.0)")
-	fmt.Println("  jenvy remote-list --jdk=17               # Filter only a specific version")
-	fmt.Println("  jenvy remote-list --lts-only             # Show only LTS versions")
-	fmt.Println("")
-	fmt.Println(utils.SectionText("[DOWNLOAD] JDK DOWNLOAD:"))
-	fmt.Println("────────────────")
-	fmt.Println("  jenvy download (dl) <version>            # Download JDK version to ~/.jenvy/versions")
-	fmt.Println("  jenvy download 17 --provider=adoptium    # Download from specific provider")
-	fmt.Println("  jenvy download 21 --output=./my-jdks     # Download to custom directory")
-	fmt.Println("")
-	fmt.Println(utils.SectionText("[EXTRACT] JDK EXTRACTION:"))
-	fmt.Println("──────────────────")
-	fmt.Println("  jenvy extract (ex)                       # List available archives to extract")
-	fmt.Println("  jenvy extract 17                          # Extract any JDK 17.x.y version")
-	fmt.Println("  jenvy extract 21                          # Extract any JDK 21.x.y version")
-	fmt.Println("  jenvy extract JDK-17.0.16+8              # Extract specific JDK version")
-	fmt.Println("")
-	fmt.Println(utils.SectionText("[MANAGE] JDK MANAGEMENT:"))
-	fmt.Println("─────────────────")
-	fmt.Println("  jenvy list (l)                           # Show installed JDK versions")
-	fmt.Println("  jenvy use (u) <version>                  # Set JDK version as active (JAVA_HOME)")
-	fmt.Println("  jenvy remove (rm) <version>              # Remove installed JDK version")
-	fmt.Println("  jenvy remove (rm) --all                  # Remove ALL JDK installations")
-	fmt.Println("")
-	fmt.Println(utils.SectionText("[SHELL] SHELL COMPLETION:"))
-	fmt.Println("──────────────────")
-	fmt.Println("  jenvy completion                         # Generate bash completion script")
-	fmt.Println("  jenvy completion install                 # Install completion to ~/.bashrc")
-	fmt.Println("")
-	fmt.Println(utils.SectionText("[TOOLS] SYSTEM TOOLS:"))
-	fmt.Println("───────────────")
-	fmt.Println("  jenvy fix-path (fp)                      # Remove duplicate PATH entries")
-	fmt.Println("  jenvy init                               # Initialize Jenvy environment variables")
-	fmt.Println("")
-	fmt.Println(utils.SectionText("[PRIVATE] PRIVATE REPOSITORY CONFIGURATION:"))
-	fmt.Println("───────────────────────────────────")
-	fmt.Println("  jenvy configure-private (cp) <endpoint> [token]  # Configure enterprise repository")
-	fmt.Println("  jenvy config-show (cs)                           # Show current configuration")
-	fmt.Println("  jenvy config-reset (cr)                          # Remove private configuration")
-	fmt.Println("")
-	fmt.Println(utils.SectionText("[HELP] HELP & VERSION:"))
-	fmt.Println("────────────────")
-	fmt.Println("  jenvy --help, -h, help                   # Show this help message")
-	fmt.Println("  jenvy --version, -v, version             # Show version information")
-	fmt.Println("")
+	for _, section := range helpSections {
+		fmt.Println(utils.SectionText(section.Title))
+		fmt.Println(strings.Repeat("─", utf8.RuneCountInString(section.Title)))
+		for _, line := range section.Lines {
+			fmt.Println(line)
+		}
+		fmt.Println("")
+	}
 	fmt.Println(utils.ExamplesText("PRACTICAL EXAMPLES:"))
 	fmt.Println("──────────────────────")
 	fmt.Println("  jenvy rl --provider=azul --jdk=21")
@@ -103,3 +62,168 @@ func ShowHelp() {
 	fmt.Println("  jenvy completion install                 # Enable tab completion")
 	fmt.Println("  jenvy dl 17                              # Then use: jenvy <Tab> to see commands")
 }
+
+// helpSection è una sezione dell'help generale.
+//
+// Le righe "  jenvy <comando> ..." sono anche l'aiuto dei singoli comandi
+// (vedi ShowCommandHelp): ogni nuova opzione va documentata qui.
+type helpSection struct {
+	Title string
+	Lines []string
+}
+
+// helpSections sono le sezioni di 'jenvy --help', nell'ordine di visualizzazione.
+var helpSections = []helpSection{
+	{"[COMMANDS] AVAILABLE COMMANDS:", []string{
+		"  jenvy remote-list (rl)                   # Show recommended versions (default: Adoptium)",
+		"  jenvy remote-list --provider=azul        # Specify provider (adoptium|azul|liberica|graalvm|private)",
+		"  jenvy remote-list --all                  # Show versions from all providers",
+		"  jenvy remote-list --latest               # Show only the latest version",
+		"  jenvy remote-list --major-only           # Show only major releases (e.g. 17.0.0)",
+		"  jenvy remote-list --jdk=17               # Filter only a specific version",
+		"  jenvy remote-list --lts-only             # Show only LTS versions",
+		"  jenvy remote-list --since=90d            # Show only releases from the last 90 days",
+		"  jenvy remote-list --all --ea             # Include early-access builds of upcoming JDKs, marked (EA)",
+		"  jenvy remote-list --type=jre --javafx    # JREs (or --type=jdk) bundling JavaFX: azul, liberica",
+		"  jenvy remote-list --all --count          # Print only the number of matching versions",
+		"  jenvy remote-list --fields=version,lts   # Choose and order columns (version,os,arch,lts,download)",
+		"  jenvy remote-list --provider=azul --raw  # Print the provider's JSON response unmodified",
+		"  jenvy remote-list --all --output=json    # Machine-readable output (json|csv) for scripts and IDEs",
+	}},
+	{"[DOWNLOAD] JDK DOWNLOAD:", []string{
+		"  jenvy download (dl) <version>            # Download JDK version to ~/.jenvy/versions",
+		"  jenvy download 17 --provider=adoptium    # Download from specific provider",
+		"  jenvy download 17 21 --provider=adoptium # Several versions in parallel, one progress and summary",
+		"  jenvy download 21 --provider=graalvm     # GraalVM with native-image (edition: config graalvm-edition)",
+		"  jenvy download 21 --output=./my-jdks     # Download to custom directory",
+		"  jenvy download 21 --extract-to=D:\\jdks   # Keep the archive in --output, extract the JDK here",
+		"  jenvy download 17 --archive-name={provider}-{version}-{os}-{arch}  # Custom archive file name",
+		"  jenvy download 17 --yes --delete-archive # Non-interactive; --keep-archive keeps the archive",
+		"  jenvy download 17 --checksum-algorithm=sha512 # Verify the archive with SHA-512 (default: auto)",
+		"  jenvy download 17 --checksum=<hash>      # Verify the archive against a hash you already know",
+		"  jenvy download 17 --progress=lines       # Newline progress for logs (auto when redirected or CI is set)",
+		"  jenvy download 17 --resume               # Resume a dropped transfer at once (a .part file always resumes)",
+		"  jenvy download 21 --stream               # Extract while downloading, no archive on disk (tar.gz with checksum)",
+		"  jenvy download 25 --ea                   # Early-access build from adoptium or liberica",
+		"  jenvy download 21 --type=jre             # Runtime only; add --javafx for Liberica Full or Zulu FX",
+		"  jenvy download 17 --resolve-only         # Show version, URL and size without downloading",
+		"  jenvy download 17 --verbose              # Also show GitHub token source and rate limit",
+		"  jenvy download 17 --arch=x64             # x64, x86 or aarch64, e.g. x64 on Windows ARM (default: native OS)",
+		"  jenvy download 21 --os=linux             # linux, mac or alpine tar.gz for another machine, kept as is",
+		"  jenvy download --all-lts [--provider=X]  # Install the latest patch of every LTS release",
+		"  jenvy download --manifest team-jdks.json # Install the pinned [{provider, version, sha256}] set",
+		"  jenvy install 21 --use                   # One shot: resolve, download, extract and activate JDK 21",
+		"  jenvy install 17 --provider=azul --yes   # Install without prompts; add --use --user to activate it",
+	}},
+	{"[EXTRACT] JDK EXTRACTION:", []string{
+		"  jenvy extract (ex)                       # List available archives to extract",
+		"  jenvy extract 17                          # Extract any JDK 17.x.y version",
+		"  jenvy extract 21                          # Extract any JDK 21.x.y version",
+		"  jenvy extract JDK-17.0.16+8              # Extract specific JDK version",
+		"  jenvy extract 17 --no-flatten             # Keep the archive's nested directory",
+		"  jenvy extract --all [--yes] [--jobs=N]    # Extract every downloaded archive not yet extracted",
+	}},
+	{"[MANAGE] JDK MANAGEMENT:", []string{
+		"  jenvy list (l)                           # Show installed JDK versions",
+		"  jenvy list --provider=<name>             # Only JDKs from a provider (unknown = no metadata)",
+		"  jenvy list --tree                        # Show ~/.jenvy/versions as a directory tree",
+		"  jenvy list --duplicates                  # Show versions installed more than once",
+		"  jenvy list --check-updates               # Latest patch from each provider, end-of-life majors in red",
+		"  jenvy list --output=json                 # Machine-readable output (json|csv), data only on stdout",
+		"  jenvy use (u) <version>                  # Set JDK version as active (JAVA_HOME)",
+		"  jenvy use <version> --temporary          # Activate only in a new child shell (no admin)",
+		"  jenvy use <version> --session [--shell=] # Print JAVA_HOME/PATH commands for eval (no admin)",
+		"  jenvy use <version> --global             # Set the system JAVA_HOME (default, requires admin)",
+		"  jenvy use <version> --user               # Set the user JAVA_HOME (no admin, overrides system)",
+		"  jenvy use <version> --local              # Pin the JDK for this project (.jenvy-version)",
+		"  jenvy use                                # Activate the JDK pinned by .jenvy-version/.java-version",
+		"  jenvy local <version>                    # Same as 'use <version> --local'",
+		"  jenvy local [--unset]                    # Show or remove the project pin (.jenvy-version/.java-version)",
+		"  jenvy alias <name> <version>             # Name a version for use/remove/local (e.g. alias default 21)",
+		"  jenvy alias [<name>] | <name> --delete   # List aliases, print one, or delete it",
+		"  jenvy default <version> [--no-use]       # Set and activate the default JDK ('init' activates it too)",
+		"  jenvy default [--unset]                  # Show or remove the default JDK",
+		"  jenvy shell-init bash|zsh|powershell     # Print a hook that switches JDK on cd (session only)",
+		"  jenvy use --from-build                   # Activate the JDK required by pom.xml/build.gradle",
+		"  jenvy current                            # Show the active JDK, its scope and the java on PATH",
+		"  jenvy current --version-only             # Print only the active version (for scripts)",
+		"  jenvy which <version>                    # Print the java launcher path of an installed JDK",
+		"  jenvy which --all [--with-version]       # Print every installed java launcher, one per line",
+		"  jenvy which <version> --version-only     # Print the installed version a request resolves to",
+		"                                           # Precedence: project (.jenvy-version) > user > system",
+		"  jenvy use -                              # Switch back to the previously active JDK",
+		"  jenvy use <version> --dry-run            # Preview changes and PATH shadowing, change nothing",
+		"  jenvy use <version> --install [--yes]    # Download the version first if it is not installed",
+		"  jenvy update (upgrade) --all             # Upgrade every installed major to its latest patch",
+		"  jenvy update 21 --prune                  # Only JDK 21; remove the previous patches once upgraded",
+		"  jenvy update --all --jobs=4 --yes        # Parallel downloads, no prompt (--no-reactivate keeps JAVA_HOME)",
+		"  jenvy remove (rm) <version>              # Remove installed JDK version",
+		"  jenvy remove (rm) --all                  # Remove ALL JDK installations",
+		"  jenvy verify <version> | --all           # Check installed JDKs for modified files",
+	}},
+	{"[SHELL] SHELL COMPLETION:", []string{
+		"  jenvy completion                         # Generate bash completion script",
+		"  jenvy completion install                 # Install completion for detected shells",
+		"  jenvy completion install --shell=<name>  # Only bash, powershell or cmd (--all for every shell)",
+		"  jenvy completion uninstall               # Remove completion from shell profiles",
+		"  jenvy completion status                  # Show which shells have completion installed",
+	}},
+	{"[TOOLS] SYSTEM TOOLS:", []string{
+		"  jenvy fix-path (fp)                      # Remove duplicate PATH entries",
+		"  jenvy diagnose-path                      # Show which java on PATH is actually used",
+		"  jenvy doctor [--offline]                 # Check the whole environment and suggest fixes",
+		"  jenvy resolve [--json]                   # Explain which JDK applies and what 'java' really runs",
+		"  jenvy logs [--lines=N]                   # Show the current log file path and its last lines",
+		"  jenvy logs --clear                       # Delete all logs in ~/.jenvy/logs",
+		"  jenvy providers                          # List built-in and configured providers",
+		"  jenvy providers --json                   # Provider list as JSON (for scripts)",
+		"  jenvy providers status                   # Check provider API reachability and latency",
+		"  jenvy self-test                          # Download, extract and run a JRE in a temp JENVY_HOME",
+		"  jenvy init                               # Initialize Jenvy environment variables",
+		"  jenvy uninstall                          # Remove completion, environment changes and ~/.jenvy",
+		"  jenvy <command> --insecure-skip-verify   # UNSAFE: skip TLS verification for this run only",
+		"  jenvy <command> --yes (-y)               # Answer yes to every prompt, for CI (or JENVY_NONINTERACTIVE=1)",
+		"  jenvy <command> --ca-cert=<file|dir>     # Also trust these CA certificates (TLS-inspecting proxies)",
+		"  jenvy <command> --refresh                # Ignore cached provider metadata (~/.jenvy/cache)",
+		"  jenvy <command> --timeout=<90s|5m>       # Timeout of every network request (or JENVY_TIMEOUT)",
+		"  jenvy <command> --retries=<n>            # Retry network errors and 5xx responses (or JENVY_RETRIES)",
+		"  jenvy <command> --no-wait                # Fail at once if another jenvy run is changing ~/.jenvy/versions",
+		"  jenvy <command> --name value             # Options also accept a separate value, before or after arguments",
+		"  jenvy <command> <args> -- <args>         # Unknown options are errors; after -- nothing is an option",
+	}},
+	{"[PRIVATE] PRIVATE REPOSITORY CONFIGURATION:", []string{
+		"  jenvy configure-private (cp) <endpoint> [token]  # Configure enterprise repository",
+		"  jenvy config set private-type artifactory        # Search an Artifactory (or nexus) repository, no index file",
+		"  jenvy config set private-repository jdk-releases # Repository to search; token user:password uses Basic auth",
+		"  jenvy config set private-type s3                 # List a bucket (or 'directory' for an HTTP index)",
+		"  jenvy config set private-pattern <pattern>       # File names, e.g. jdk-{version}-{os}-{arch}.zip",
+		"  jenvy config set private-type github             # Release assets of the endpoint repo, e.g. https://github.com/o/r",
+		"  jenvy config set private-token <token>           # Stored in Windows Credential Manager, not in config.json",
+		"  jenvy config-show (cs)                           # Show current configuration",
+		"  jenvy config-show --json [--reveal]              # Print effective configuration as JSON",
+		"  jenvy config-reset (cr)                          # Remove private configuration",
+		"  jenvy config list                                # Show all Jenvy settings",
+		"  jenvy config set <key> <value>                   # Change a setting (e.g. naming-scheme)",
+		"  jenvy config unset <key>                         # Restore a setting to its default",
+		"  jenvy config set activation-style junction       # use repoints ~/.jenvy/current, no admin after setup",
+		"  jenvy config set notify-on-complete true         # Windows notification when download/extract/upgrade ends",
+		"  jenvy config set default-follow-updates true     # Activate newer patches of the default JDK's major",
+		"  jenvy config set extract-max-size 16GB           # Limit archive extraction (also extract-max-files, extract-max-ratio)",
+		"  jenvy config set lock-timeout 30m                # Wait for other jenvy runs (or JENVY_LOCK_TIMEOUT, default: 10m)",
+		"  jenvy config set log-retention 14                # Keep 14 days of logs in ~/.jenvy/logs (default: 7)",
+		"  jenvy config set proxy http://proxy.corp:8080    # Proxy for APIs and downloads (no-proxy excludes hosts)",
+		"  jenvy config set proxy-auth ntlm                 # With proxy-user DOMAIN\\user and proxy-password",
+		"  jenvy config set ca-cert C:\\certs\\corp.pem       # Trust a corporate CA for every HTTPS request",
+		"  jenvy config set adoptium-api-mirror <url>       # Internal mirror for a provider's API (also <p>-download-mirror)",
+		"  jenvy config set cache-ttl 6h                    # Reuse provider metadata for 6h (default: 1h, 0 disables)",
+	}},
+	{"[HELP] HELP & VERSION:", []string{
+		"  jenvy --help, -h, help                   # Show this help message",
+		"  jenvy <command> --help, help <command>   # Show the help of a single command",
+		"  jenvy --version, -v, version             # Show version information",
+	}},
+	{"[EXIT] EXIT CODES:", []string{
+		"  0 success, 1 generic error, 2 not found, 3 network/download error,",
+		"  4 extraction/verification error, 5 permission/elevation error",
+	}},
+}
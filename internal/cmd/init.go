@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"jenvy/internal/utils"
 )
 
 // Init inizializza completamente l'ambiente Jenvy su Windows.
@@ -130,7 +132,7 @@ func isJenvyInPath() bool {
 //	└── versions\             # Directory per JDK scaricati (creata al primo download)
 //
 // Processo di creazione:
-// 1. Ottiene la directory home dell'utente corrente usando os.UserHomeDir()
+// 1. Ottiene la directory dati Jenvy usando utils.JenvyHome()
 // 2. Costruisce il percorso completo: C:\Users\username\.jenvy
 // 3. Utilizza os.MkdirAll() per creare ricorsivamente tutte le directory necessarie
 // 4. Imposta i permessi a 0755 (equivalente Windows: full control per owner, read per altri)
@@ -158,12 +160,11 @@ func isJenvyInPath() bool {
 //   - Supporta percorsi lunghi Windows (>260 caratteri) se abilitati
 //   - Gestisce caratteri Unicode nei nomi utente Windows
 func createConfigDirectory() error {
-	homeDir, err := os.UserHomeDir()
+	configDir, err := utils.JenvyHome()
 	if err != nil {
 		return err
 	}
 
-	configDir := filepath.Join(homeDir, ".jenvy")
 	return os.MkdirAll(configDir, 0755)
 }
 
@@ -177,7 +178,7 @@ func createConfigDirectory() error {
 //	C:\Users\username\.jenvy\config.json    # File di configurazione principale
 //
 // Processo di verifica:
-// 1. Ottiene la directory home dell'utente Windows usando os.UserHomeDir()
+// 1. Ottiene la directory dati Jenvy usando utils.JenvyHome()
 // 2. Costruisce il percorso completo del file Windows
 // 3. Utilizza os.Stat() per verificare l'esistenza e l'accessibilità del file
 // 4. Interpreta il risultato: nil = file esiste, errore = file non trovato o inaccessibile
@@ -207,12 +208,12 @@ func createConfigDirectory() error {
 //   - Funzione read-only, non modifica il filesystem
 //   - Rispetta le ACL (Access Control List) di Windows per l'accesso ai file
 func hasExistingConfig() bool {
-	homeDir, err := os.UserHomeDir()
+	jenvyDir, err := utils.JenvyHome()
 	if err != nil {
 		return false
 	}
 
-	configPath := filepath.Join(homeDir, ".jenvy", "config.json")
+	configPath := filepath.Join(jenvyDir, "config.json")
 	_, err = os.Stat(configPath)
 	return err == nil
 }
@@ -281,14 +282,14 @@ func createDefaultConfig() error {
   "preferLTS": true
 }`
 
-	// Ottiene la directory home dell'utente Windows corrente
-	homeDir, err := os.UserHomeDir()
+	// Ottiene la directory dati Jenvy dell'utente Windows corrente
+	jenvyDir, err := utils.JenvyHome()
 	if err != nil {
 		return err
 	}
 
 	// Costruisce il percorso completo del file di configurazione Windows
-	configPath := filepath.Join(homeDir, ".jenvy", "config.json")
+	configPath := filepath.Join(jenvyDir, "config.json")
 
 	// Scrive il file di configurazione nel profilo utente Windows
 	// Il file avrà permessi appropriati per l'ambiente Windows
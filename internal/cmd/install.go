@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"jenvy/internal/utils"
+)
+
+// InstallJDK esegue 'jenvy install': risolve, scarica, estrae e, con --use, attiva un JDK.
+//
+// Riunisce in un solo comando il flusso 'download' → 'extract' → 'use'. La
+// versione viene risolta presso il provider come in 'jenvy download' e
+// installata con verifica del checksum, metadati e manifest (vedi
+// installJDKFrom); se una versione corrispondente è già installata il download
+// viene saltato. Con --provider conta solo un'installazione di quel provider
+// (vedi isJDKInstalledFrom), altrimenti una di qualsiasi provider. Con --yes nessuna conferma viene chiesta, né per il download
+// né per l'attivazione. Senza --use una nuova patch della major del JDK
+// predefinito può comunque essere attivata (vedi followDefaultJDK).
+//
+// Opzioni:
+//   - **--provider=<nome>**: Provider da cui scaricare (predefinito: quello configurato)
+//   - **--use**: Attiva il JDK installato, come 'jenvy use <version>'
+//   - **--global, --user, --local**: Scope dell'attivazione (solo con --use)
+//   - **--yes, -y**: Nessuna conferma
+//
+// Esempio di utilizzo:
+//
+//	jenvy install 21 --use            # Scarica JDK 21 (se manca) e lo attiva
+//	jenvy install 17 --provider=azul  # Solo installazione, da Azul
+//	jenvy install 21 --use --user --yes
+func InstallJDK(defaultProvider string) error {
+	provider := defaultProvider
+	providerGiven := false // Senza --provider vale come installata una versione di qualsiasi provider
+	activate := false
+	scope := "" // --global, --user o --local, passato a 'use'
+	assumeYes := utils.AssumeYes()
+	version := ""
+	positional, flags := utils.SplitArgs(os.Args[2:])
+	if len(positional) > 0 {
+		version = positional[0]
+	}
+	for _, arg := range flags {
+		if strings.HasPrefix(arg, "--provider=") {
+			provider = strings.TrimPrefix(arg, "--provider=")
+			providerGiven = true
+		} else if arg == "--use" {
+			activate = true
+		} else if arg == "--global" || arg == "--user" || arg == "--local" {
+			if scope != "" && scope != arg {
+				return utils.Fail(utils.ExitGeneric, fmt.Sprintf("%s cannot be combined with %s", arg, scope))
+			}
+			scope = arg
+		} else if arg == "--yes" || arg == "-y" {
+			assumeYes = true
+		} else {
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Unknown option for install: %s", arg))
+		}
+	}
+
+	if version == "" {
+		utils.PrintUsage("Usage: jenvy install <version> [--provider=<name>] [--use [--global|--user|--local]] [--yes]")
+		utils.PrintInfo("Examples:")
+		fmt.Println("  jenvy install 21 --use            # Download, extract and activate JDK 21")
+		fmt.Println("  jenvy install 17 --provider=azul  # Download and extract only")
+		return utils.ExitWith(utils.ExitGeneric, nil)
+	}
+	if scope != "" && !activate {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("%s requires --use", scope))
+	}
+
+	version, err := versionArgument(version, "")
+	if err != nil {
+		return err
+	}
+
+	home := ""
+	if providerGiven && isJDKInstalledFrom(version, provider) {
+		utils.PrintInfo(fmt.Sprintf("JDK %s from %s is already installed: skipping download", version, provider))
+	} else if !providerGiven && isJDKInstalled(version) {
+		utils.PrintInfo(fmt.Sprintf("JDK %s is already installed: skipping download", version))
+	} else if home, err = installJDKFrom(version, provider, assumeYes); err != nil {
+		return err
+	}
+
+	if !activate {
+		utils.PrintInfo(fmt.Sprintf("Use 'jenvy use %s' to activate it", version))
+		if home != "" {
+			followDefaultJDK(home)
+		}
+		return nil
+	}
+	fmt.Println()
+
+	// L'attivazione è quella di 'jenvy use', con le stesse opzioni di scope
+	useArgs := []string{os.Args[0], "use", version}
+	if scope != "" {
+		useArgs = append(useArgs, scope)
+	}
+	if assumeYes {
+		useArgs = append(useArgs, "--yes")
+	}
+	os.Args = useArgs
+	return UseJDK()
+}
@@ -0,0 +1,29 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+)
+
+// updateJunction crea o ripunta il link simbolico che sostituisce la junction NTFS.
+//
+// Su Linux e macOS i symlink non richiedono privilegi. Una directory reale nello
+// stesso percorso non viene mai cancellata: in quel caso viene restituito un errore.
+func updateJunction(link, target string) error {
+	if info, err := os.Lstat(link); err == nil {
+		if info.Mode()&os.ModeSymlink == 0 {
+			return fmt.Errorf("%s is a regular directory, not a symlink: move it away and retry", link)
+		}
+		// os.Remove elimina solo il collegamento, non il JDK
+		if err := os.Remove(link); err != nil {
+			return fmt.Errorf("removing old symlink: %w", err)
+		}
+	}
+
+	if err := os.Symlink(target, link); err != nil {
+		return fmt.Errorf("creating symlink: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// updateJunction crea o ripunta una directory junction NTFS.
+//
+// Le junction, a differenza dei symlink, non richiedono privilegi amministratore
+// né la Modalità sviluppatore. Una directory reale nello stesso percorso non viene
+// mai cancellata: in quel caso viene restituito un errore.
+func updateJunction(link, target string) error {
+	if info, err := os.Lstat(link); err == nil {
+		if info.IsDir() && info.Mode()&(os.ModeSymlink|os.ModeIrregular) == 0 {
+			return fmt.Errorf("%s is a regular directory, not a junction: move it away and retry", link)
+		}
+		// Su una junction os.Remove elimina solo il collegamento, non il JDK
+		if err := os.Remove(link); err != nil {
+			return fmt.Errorf("removing old junction: %w", err)
+		}
+	}
+
+	output, err := exec.Command("cmd", "/c", "mklink", "/J", link, target).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("mklink /J failed: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -20,21 +20,21 @@ import (
 //  1. **Scansione directory Jenvy**: Accede alla directory ~/.jenvy/versions nel profilo utente Windows
 //     per individuare tutte le installazioni JDK gestite dal tool
 //
-//  2. **Analisi automatica**: Per ogni installazione rilevata esegue:
+//  2. **Analisi automatica**: Per ogni installazione rilevata esegue, in parallelo (scanInstallations):
 //     - Calcolo dimensioni directory tramite filesystem walk
 //     - Rilevamento stato di estrazione (archivio vs. installazione completa)
-//     - Lettura metadati di installazione (data, tipo archivio)
+//     - Lettura metadati di installazione (provider, versione esatta per la colonna LTS)
 //
 //  3. **Ordinamento intelligente**: Ordina le installazioni per numero di versione
 //     con logica di parsing che riconosce pattern di versioning JDK standard
 //
 //  4. **Visualizzazione tabellare**: Presenta i risultati in formato tabella con:
-//     - Versione e stato di installazione
+//     - Versione, provider, LTS e stato di installazione
 //     - Data di installazione e dimensioni formattate
 //     - Percorsi abbreviati per leggibilità
 //
 // **Caratteristiche Windows-specifiche:**
-// - Utilizza os.UserHomeDir() per accedere al profilo utente Windows
+// - Utilizza utils.GetJenvyVersionsDirectory() per localizzare le installazioni
 // - Gestisce percorsi Windows con separatori backslash appropriati
 // - Supporta formati archivio Windows (.zip, .msi, .exe) oltre a formati Unix
 // - Formattazione output ottimizzata per terminali Windows (cmd.exe, PowerShell)
@@ -44,66 +44,227 @@ import (
 // - [ARCHIVE]: Solo file archivio presente, richiede estrazione
 // - [EMPTY]: Directory esistente ma senza contenuto valido
 //
+// **Filtro per provider:**
+// Con --provider=X vengono mostrate solo le installazioni il cui provider registrato
+// nei metadati (utils.InstallMetadata) coincide con X. Le installazioni senza metadati
+// (precedenti alla loro introduzione o copiate a mano) compaiono solo con --provider=unknown.
+//
 // Esempi di utilizzo:
 //
-//	jenvy list  # Mostra tutte le installazioni JDK locali
-func ListInstalledJDKs() {
+//	jenvy list                     # Mostra tutte le installazioni JDK locali
+//	jenvy list --provider=azul     # Solo i JDK Zulu
+//	jenvy list --provider=unknown  # Solo le installazioni senza metadati
+//	jenvy list --tree              # Struttura di ~/.jenvy/versions ad albero (vedi displayJDKTree)
+//	jenvy list --duplicates        # Versioni installate più di una volta (vedi displayDuplicateJDKs)
+//	jenvy list --output=json       # Dati per script e IDE (vedi writeInstallationRecords)
+//	jenvy list --check-updates     # Ultima patch disponibile e major fuori supporto (vedi checkInstallUpdates)
+func ListInstalledJDKs() error {
+	providerFilter := ""
+	tree := false
+	duplicates := false
+	checkUpdates := false
+	output := utils.OutputTable
+	for _, arg := range os.Args[2:] {
+		if strings.HasPrefix(arg, "--provider=") {
+			providerFilter = strings.ToLower(strings.TrimPrefix(arg, "--provider="))
+		} else if strings.HasPrefix(arg, "--output=") {
+			format, err := utils.ParseOutputFormat(strings.TrimPrefix(arg, "--output="))
+			if err != nil {
+				return utils.Fail(utils.ExitGeneric, err.Error())
+			}
+			output = format
+		} else if arg == "--tree" {
+			tree = true
+		} else if arg == "--duplicates" {
+			duplicates = true
+		} else if arg == "--check-updates" {
+			checkUpdates = true
+		} else {
+			utils.PrintError(fmt.Sprintf("Unknown option: %s", arg))
+			utils.PrintUsage("Usage: jenvy list [--provider=<name>|unknown] [--tree|--duplicates|--check-updates|--output=json|csv]")
+			return utils.ExitWith(utils.ExitGeneric, fmt.Errorf("unknown option: %s", arg))
+		}
+	}
+	if tree && duplicates {
+		return utils.Fail(utils.ExitGeneric, "--tree cannot be combined with --duplicates")
+	}
+	if checkUpdates && (tree || duplicates) {
+		return utils.Fail(utils.ExitGeneric, "--check-updates cannot be combined with --tree or --duplicates")
+	}
+	if output != utils.OutputTable {
+		if tree || duplicates || checkUpdates {
+			return utils.Fail(utils.ExitGeneric, "--output cannot be combined with --tree, --duplicates or --check-updates")
+		}
+		return writeInstallationRecords(output, providerFilter)
+	}
+
 	fmt.Println(utils.ColorText("LOCAL JDK INSTALLATIONS", utils.Bold+utils.BrightCyan))
 	fmt.Println()
 
-	// Ottieni directory home utente
-	homeDir, err := os.UserHomeDir()
+	// Ottieni directory delle versioni installate
+	versionsDir, err := utils.GetJenvyVersionsDirectory()
 	if err != nil {
 		fmt.Println(utils.ErrorText(fmt.Sprintf("Error getting home directory: %v", err)))
-		return
+		return utils.ExitWith(utils.ExitGeneric, err)
 	}
 
-	versionsDir := filepath.Join(homeDir, ".jenvy", "versions")
-
 	// Controlla se la directory esiste
-	if _, err := os.Stat(versionsDir); os.IsNotExist(err) {
+	if _, err := os.Stat(versionsDir); os.IsNotExist(err) && len(utils.ExternalInstallDirs()) == 0 {
 		fmt.Println(utils.WarningText("No JDK installations found"))
 		fmt.Printf("[INFO] Directory %s does not exist yet\n", versionsDir)
 		fmt.Println("   Use 'jenvy download <version>' to download a version")
-		return
+		return nil
 	}
 
-	// Leggi contenuto directory
-	entries, err := os.ReadDir(versionsDir)
+	// Analizza tutte le installazioni in parallelo
+	scans, err := scanInstallations(versionsDir, true)
 	if err != nil {
 		fmt.Println(utils.ErrorText(fmt.Sprintf("Error reading directory: %v", err)))
-		return
+		return utils.ExitWith(utils.ExitGeneric, err)
 	}
 
-	if len(entries) == 0 {
+	if len(scans) == 0 {
 		fmt.Println(utils.WarningText("No JDK installations found"))
 		fmt.Printf("[INFO] Directory %s is empty\n", versionsDir)
 		fmt.Println("   Use 'jenvy download <version>' to download a version")
-		return
+		return nil
 	}
 
 	// Raccogli informazioni sui JDK installati
-	var jdks []JDKInstallation
-	for _, entry := range entries {
-		if entry.IsDir() {
-			jdkPath := filepath.Join(versionsDir, entry.Name())
-			installation := analyzeJDKInstallation(entry.Name(), jdkPath)
-			jdks = append(jdks, installation)
+	var selected []installationScan
+	for _, scan := range scans {
+		if providerFilter != "" && !matchesProviderFilter(scan.Details.Provider, providerFilter) {
+			continue
 		}
+		selected = append(selected, scan)
 	}
 
-	if len(jdks) == 0 {
+	if len(selected) == 0 {
+		if providerFilter != "" {
+			fmt.Println(utils.WarningText(fmt.Sprintf("No JDK installations from provider '%s'", providerFilter)))
+			fmt.Println("   Installations without recorded metadata are listed with --provider=unknown")
+			return nil
+		}
 		fmt.Println(utils.WarningText("No valid JDK installations found"))
-		return
+		return nil
 	}
 
 	// Ordina per versione (più recenti per prime)
-	sort.Slice(jdks, func(i, j int) bool {
-		return compareVersions(jdks[i].Version, jdks[j].Version) > 0
+	sort.Slice(selected, func(i, j int) bool {
+		return compareVersions(selected[i].Details.Version, selected[j].Details.Version) > 0
 	})
 
+	if tree {
+		displayJDKTree(versionsDir, selected)
+		return nil
+	}
+	if duplicates {
+		displayDuplicateJDKs(versionsDir, selected)
+		return nil
+	}
+
 	// Mostra installazioni in formato tabella
+	jdks := make([]JDKInstallation, len(selected))
+	for i, scan := range selected {
+		jdks[i] = *scan.Details
+	}
 	displayJDKTable(jdks)
+	if checkUpdates {
+		displayInstallUpdates(checkInstallUpdates(selected))
+	}
+	return nil
+}
+
+// installationRecordHeaders sono i campi di 'jenvy list --output=json|csv'.
+var installationRecordHeaders = []string{"Version", "Provider", "LTS", "Status", "Size", "Installed", "Path", "Native Image"}
+
+// writeInstallationRecords gestisce 'jenvy list --output=json|csv'.
+//
+// Applica lo stesso filtro --provider e lo stesso ordinamento della tabella, ma
+// scrive su stdout solo i dati (utils.WriteRecords): avvisi ed errori vanno su
+// stderr. Senza installazioni produce un elenco vuoto invece di un messaggio.
+func writeInstallationRecords(format, providerFilter string) error {
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = stdout }()
+
+	var selected []installationScan
+	if versionsDir, err := utils.GetJenvyVersionsDirectory(); err == nil {
+		scans, err := scanInstallations(versionsDir, true)
+		if err != nil && !os.IsNotExist(err) {
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Error reading directory: %v", err))
+		}
+		for _, scan := range scans {
+			if providerFilter == "" || matchesProviderFilter(scan.Details.Provider, providerFilter) {
+				selected = append(selected, scan)
+			}
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		return compareVersions(selected[i].Details.Version, selected[j].Details.Version) > 0
+	})
+
+	rows := make([][]string, len(selected))
+	for i, scan := range selected {
+		details := scan.Details
+		status := strings.ToLower(strings.Trim(getStatusIcon(details.IsExtracted, details.ArchiveType), "[]"))
+		rows[i] = []string{details.Version, details.Provider, ltsLabel(details.LTS), status, details.Size, details.InstallDate, details.Path, details.NativeImage}
+	}
+	if err := utils.WriteRecords(stdout, format, installationRecordHeaders, rows); err != nil {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to write output: %v", err))
+	}
+	return nil
+}
+
+// displayJDKTree mostra ~/.jenvy/versions come albero di directory ('jenvy list --tree').
+//
+// Ogni installazione è un ramo con stato e dimensione; per le installazioni non
+// ancora estratte il nome dell'archivio compare come foglia, così si vede subito
+// quale file occupa spazio su disco:
+//
+//	C:\Users\Marco\.jenvy\versions
+//	├── JDK-21.0.5+11   [READY]    312.4 MB
+//	└── JDK-17.0.12+7   [ARCHIVE]  187.9 MB
+//	    └── OpenJDK17U-jdk_x64_windows_hotspot_17.0.12_7.zip
+//
+// Parametri:
+//
+//	versionsDir string         - Radice dell'albero
+//	scans []installationScan   - Installazioni da mostrare, già filtrate e ordinate (con Details)
+func displayJDKTree(versionsDir string, scans []installationScan) {
+	// Le installazioni esterne ('download --extract-to') mostrano il percorso completo
+	labels := make([]string, len(scans))
+	nameWidth := 0
+	for i, scan := range scans {
+		labels[i] = scan.Name
+		if filepath.Dir(scan.Path) != filepath.Clean(versionsDir) {
+			labels[i] = scan.Path
+		}
+		if len(labels[i]) > nameWidth {
+			nameWidth = len(labels[i])
+		}
+	}
+
+	fmt.Println(utils.ColorText(versionsDir, utils.Bold+utils.BrightCyan))
+	for i, scan := range scans {
+		branch, indent := "├── ", "│   "
+		if i == len(scans)-1 {
+			branch, indent = "└── ", "    "
+		}
+
+		details := scan.Details
+		status := getStatusIcon(details.IsExtracted, details.ArchiveType)
+		fmt.Printf("%s%s  %s  %s\n", branch,
+			utils.ColorText(fmt.Sprintf("%-*s", nameWidth, labels[i]), utils.Bold),
+			utils.ColorText(fmt.Sprintf("%-9s", status), getStatusColor(details.IsExtracted, details.ArchiveType)),
+			details.Size)
+
+		if !details.IsExtracted && scan.ArchivePath != "" {
+			fmt.Printf("%s└── %s\n", indent, utils.ColorText(filepath.Base(scan.ArchivePath), utils.BrightBlack))
+		}
+	}
+	fmt.Println()
+	fmt.Printf("%d installation(s)\n", len(scans))
 }
 
 // JDKInstallation rappresenta un'installazione JDK locale
@@ -114,6 +275,19 @@ type JDKInstallation struct {
 	InstallDate string
 	IsExtracted bool
 	ArchiveType string
+	Provider    string // Provider registrato nei metadati (vuoto se sconosciuto)
+	LTS         bool   // true se la versione esatta (metadati o file release) è una major LTS
+	NativeImage string // Stato di native-image per i GraalVM (vedi nativeImageLabel), vuoto per gli altri JDK
+}
+
+// matchesProviderFilter verifica se un'installazione soddisfa il filtro 'list --provider'.
+//
+// Il valore speciale "unknown" seleziona le installazioni senza provider registrato.
+func matchesProviderFilter(provider, filter string) bool {
+	if filter == "unknown" {
+		return provider == ""
+	}
+	return strings.EqualFold(provider, filter)
 }
 
 // analyzeJDKInstallation analizza una directory JDK per estrarre informazioni
@@ -144,7 +318,7 @@ func analyzeJDKInstallation(dirName, jdkPath string) JDKInstallation {
 
 	// Calcola dimensione directory
 	size := calculateDirSize(jdkPath)
-	installation.Size = formatSize(size)
+	installation.Size = utils.FormatBytes(size)
 
 	// Ottieni data di installazione (modificata della directory)
 	if stat, err := os.Stat(jdkPath); err == nil {
@@ -154,6 +328,27 @@ func analyzeJDKInstallation(dirName, jdkPath string) JDKInstallation {
 	// Controlla se contiene file estratti o archivi
 	installation.IsExtracted, installation.ArchiveType = checkExtractionStatus(jdkPath)
 
+	// Provider e versione esatta registrati al momento del download
+	exactVersion := ""
+	if meta, err := utils.LoadInstallMetadata(jdkPath); err == nil {
+		installation.Provider = meta.Provider
+		exactVersion = meta.Version
+	}
+
+	home, hasHome := utils.ResolveJDKHome(jdkPath)
+	if hasHome {
+		installation.NativeImage = nativeImageLabel(utils.DetectNativeImage(home))
+	}
+
+	// Senza metadati la versione viene dal file release del JDK, e solo in ultima istanza dal nome
+	if exactVersion == "" && hasHome {
+		exactVersion, _ = utils.JDKHomeVersion(home)
+	}
+	if exactVersion == "" {
+		exactVersion, _, _ = utils.ParseInstallDirName(dirName, utils.InstallNamingScheme())
+	}
+	installation.LTS = exactVersion != "" && utils.IsLTSVersion(exactVersion)
+
 	return installation
 }
 
@@ -202,49 +397,6 @@ func calculateDirSize(dirPath string) int64 {
 	return size
 }
 
-// formatSize formatta una dimensione in byte in una stringa leggibile
-//
-// Questa funzione converte conteggi di byte grezzi in rappresentazioni di dimensioni
-// user-friendly utilizzando unità binarie standard (basate su 1024) per reportistica
-// precisa dello spazio su disco:
-//
-// **Logica di conversione:**
-// - Utilizza unità binarie: B, KB, MB, GB, TB (basate su 1024)
-// - Mantiene precisione con 1 cifra decimale per unità superiori ai byte
-// - Gestisce casi limite (dimensione zero, file molto grandi)
-//
-// **Regole di formattazione:**
-// - Byte: Nessuna cifra decimale (es. "1024 B")
-// - Unità maggiori: Una cifra decimale (es. "1.5 MB")
-// - Selezione automatica unità basata sulla grandezza
-//
-// Questo fornisce reportistica consistente delle dimensioni attraverso tutte
-// le installazioni JDK per facile confronto e gestione dello spazio su disco.
-//
-// Parametri:
-//   - bytes: Dimensione in byte come int64
-//
-// Returns: Stringa dimensione formattata con unità appropriata
-func formatSize(bytes int64) string {
-	if bytes == 0 {
-		return "0 B"
-	}
-
-	units := []string{"B", "KB", "MB", "GB", "TB"}
-	size := float64(bytes)
-	unitIndex := 0
-
-	for size >= 1024 && unitIndex < len(units)-1 {
-		size /= 1024
-		unitIndex++
-	}
-
-	if unitIndex == 0 {
-		return fmt.Sprintf("%.0f %s", size, units[unitIndex])
-	}
-	return fmt.Sprintf("%.1f %s", size, units[unitIndex])
-}
-
 // checkExtractionStatus verifica se la directory contiene file estratti o solo archivi
 //
 // Questa funzione analizza il contenuto di una directory JDK per determinare
@@ -429,10 +581,10 @@ func extractMainVersion(version string) int {
 func displayJDKTable(jdks []JDKInstallation) {
 	fmt.Printf(utils.ColorText("Found %d JDK installations:\n\n", utils.Bold+utils.BrightCyan), len(jdks))
 
-	// Header della tabella con colori (senza colonna PROVIDER)
-	fmt.Printf(utils.ColorText("%-30s %-10s %-18s %-12s %s\n", utils.Bold+utils.BrightCyan),
-		"VERSION", "STATUS", "INSTALL DATE", "SIZE", "PATH")
-	fmt.Println(utils.ColorText(strings.Repeat("-", 85), utils.Cyan))
+	// Header della tabella con colori
+	fmt.Printf(utils.ColorText("%-30s %-10s %-4s %-10s %-18s %-12s %s\n", utils.Bold+utils.BrightCyan),
+		"VERSION", "PROVIDER", "LTS", "STATUS", "INSTALL DATE", "SIZE", "PATH")
+	fmt.Println(utils.ColorText(strings.Repeat("-", 101), utils.Cyan))
 
 	// Righe della tabella con colori
 	for _, jdk := range jdks {
@@ -453,18 +605,45 @@ func displayJDKTable(jdks []JDKInstallation) {
 
 		// Formatta la riga con padding fisso per l'allineamento
 		version := fmt.Sprintf("%-30s", jdk.Version)
+		provider := jdk.Provider
+		if provider == "" {
+			provider = "-"
+		}
+		provider = fmt.Sprintf("%-10s", provider)
+		lts := fmt.Sprintf("%-4s", ltsLabel(jdk.LTS))
 		statusStr := fmt.Sprintf("%-10s", status)
 		installDate := fmt.Sprintf("%-18s", jdk.InstallDate)
 		size := fmt.Sprintf("%-12s", jdk.Size)
 
-		fmt.Printf("%s %s %s %s %s\n",
+		fmt.Printf("%s %s %s %s %s %s %s\n",
 			utils.ColorText(version, versionColor),
+			provider,
+			lts,
 			utils.ColorText(statusStr, statusColor),
 			installDate,
 			size,
 			utils.ColorText(displayPath, utils.Blue))
 	}
 
+	// Per i GraalVM indica se native-image è già utilizzabile
+	var graalvms []JDKInstallation
+	for _, jdk := range jdks {
+		if jdk.NativeImage != "" {
+			graalvms = append(graalvms, jdk)
+		}
+	}
+	if len(graalvms) > 0 {
+		fmt.Println()
+		fmt.Println(utils.ColorText("GraalVM native-image:", utils.Bold+utils.BrightCyan))
+		for _, jdk := range graalvms {
+			color := utils.BrightGreen
+			if jdk.NativeImage != "bundled" {
+				color = utils.BrightYellow
+			}
+			fmt.Printf("   %-30s %s\n", jdk.Version, utils.ColorText(jdk.NativeImage, color))
+		}
+	}
+
 	fmt.Println()
 	fmt.Println(utils.ColorText("Status Legend:", utils.Bold+utils.BrightCyan))
 	fmt.Printf("   %s - JDK extracted and ready for use\n", utils.ColorText("[READY]", utils.BrightGreen))
@@ -477,6 +656,14 @@ func displayJDKTable(jdks []JDKInstallation) {
 	fmt.Println("   jenvy remove <version>   - Remove a version")
 }
 
+// ltsLabel restituisce il valore della colonna LTS di 'jenvy list', come in 'remote-list'.
+func ltsLabel(lts bool) string {
+	if lts {
+		return "Yes"
+	}
+	return "No"
+}
+
 // getStatusIcon restituisce l'icona di stato appropriata senza emoji
 //
 // Questa funzione determina il testo di stato da visualizzare per ogni installazione JDK
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"jenvy/internal/utils"
+)
+
+// duplicateInstall è un'installazione che condivide la versione con almeno un'altra.
+type duplicateInstall struct {
+	Provider string // Provider registrato o ricavato dal nome ("" se sconosciuto)
+	Scan     installationScan
+}
+
+// groupDuplicateInstalls raggruppa le installazioni per versione risolta (utils.VersionKey).
+//
+// La versione viene letta dai metadati di installazione e, in loro assenza, dal
+// nome della directory; le installazioni senza versione riconoscibile sono
+// ignorate. Restituisce solo i gruppi con più di un'installazione, ordinati per
+// versione decrescente come 'jenvy list'.
+func groupDuplicateInstalls(scans []installationScan) ([]string, map[string][]duplicateInstall) {
+	scheme := utils.InstallNamingScheme()
+	groups := make(map[string][]duplicateInstall)
+	for _, scan := range scans {
+		version, provider, ok := utils.ParseInstallDirName(scan.Name, scheme)
+		if meta, err := utils.LoadInstallMetadata(scan.Path); err == nil && meta.Version != "" {
+			version, provider, ok = meta.Version, meta.Provider, true
+		}
+		if !ok {
+			continue
+		}
+		key, ok := utils.VersionKey(version)
+		if !ok {
+			continue
+		}
+		groups[key] = append(groups[key], duplicateInstall{Provider: provider, Scan: scan})
+	}
+
+	var keys []string
+	for key, installs := range groups {
+		if len(installs) > 1 {
+			keys = append(keys, key)
+		} else {
+			delete(groups, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return compareVersions(keys[i], keys[j]) > 0 })
+	return keys, groups
+}
+
+// displayDuplicateJDKs mostra le versioni installate più di una volta ('jenvy list --duplicates').
+//
+// Una stessa versione installata da provider diversi, o in ~/.jenvy/versions e
+// in una directory di 'download --extract-to', occupa spazio due volte e rende
+// ambiguo 'jenvy use <version>' ("multiple matches"). Per ogni gruppo vengono
+// mostrati provider, dimensione e percorso, così da scegliere cosa rimuovere.
+//
+// Parametri:
+//
+//	versionsDir string       - Directory delle versioni Jenvy
+//	scans []installationScan - Installazioni già filtrate (con Details)
+func displayDuplicateJDKs(versionsDir string, scans []installationScan) {
+	keys, groups := groupDuplicateInstalls(scans)
+	if len(keys) == 0 {
+		utils.PrintSuccess("No JDK version is installed more than once")
+		return
+	}
+
+	utils.PrintSection("[DUPLICATES] SAME VERSION INSTALLED MORE THAN ONCE")
+	for _, key := range keys {
+		installs := groups[key]
+		fmt.Println(utils.ColorText(fmt.Sprintf("JDK %s (%d installs)", key, len(installs)), utils.Bold))
+		for _, install := range installs {
+			provider := install.Provider
+			if provider == "" {
+				provider = "unknown"
+			}
+			fmt.Printf("  %-10s %10s  %s\n", provider, install.Scan.Details.Size, install.Scan.Path)
+		}
+		fmt.Println()
+	}
+
+	utils.PrintInfo(fmt.Sprintf("%s installed more than once", pluralize(len(keys), "version")))
+	utils.PrintInfo("Remove the copies you don't need with 'jenvy remove <directory name>'")
+	for _, key := range keys {
+		for _, install := range groups[key] {
+			if filepath.Dir(install.Scan.Path) != filepath.Clean(versionsDir) {
+				utils.PrintInfo("Installs outside " + versionsDir + " (download --extract-to) must be deleted manually")
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"jenvy/internal/utils"
+)
+
+// installUpdate è l'esito di 'jenvy list --check-updates' per un'installazione.
+type installUpdate struct {
+	Name      string // Nome della directory di installazione
+	Provider  string // Provider registrato o ricavato dal nome ("" se sconosciuto)
+	Installed string // Versione installata
+	Major     int
+	Latest    string // Ultima patch offerta dal provider, vuota se non determinabile
+	Status    string // update available | up to date | not offered | unknown provider | check failed
+	EOL       bool   // La major è fuori supporto (vedi utils.IsEOLMajor)
+}
+
+// checkInstallUpdates confronta ogni installazione con l'ultima patch della sua major presso il provider.
+//
+// Come in 'jenvy update' ogni provider viene interrogato una sola volta. La
+// versione viene letta dai metadati di installazione e, in loro assenza, dal
+// nome della directory; senza provider noto viene indicata solo la fine del
+// supporto della major. Le installazioni senza versione riconoscibile sono ignorate.
+func checkInstallUpdates(scans []installationScan) []installUpdate {
+	scheme := utils.InstallNamingScheme()
+	finders := make(map[string]releaseFinder)
+	now := time.Now()
+
+	var updates []installUpdate
+	for _, scan := range scans {
+		version, provider, ok := utils.ParseInstallDirName(scan.Name, scheme)
+		if meta, err := utils.LoadInstallMetadata(scan.Path); err == nil && meta.Version != "" {
+			version, provider, ok = meta.Version, meta.Provider, true
+		}
+		major, _, _ := utils.ParseVersionNumber(version)
+		if !ok || major <= 0 {
+			continue
+		}
+		update := installUpdate{Name: scan.Name, Provider: provider, Installed: version, Major: major, EOL: utils.IsEOLMajor(major, now)}
+
+		if provider == "" {
+			update.Status = "unknown provider"
+			updates = append(updates, update)
+			continue
+		}
+		findRelease, cached := finders[provider]
+		if !cached {
+			utils.PrintSearch(fmt.Sprintf("Resolving latest releases from provider: %s", provider))
+			finder, err := fetchReleaseFinder(provider)
+			if err != nil {
+				utils.PrintWarning(fmt.Sprintf("Cannot check %s: %v", provider, err))
+			}
+			finders[provider] = finder
+			findRelease = finder
+		}
+
+		switch release := releaseOrEmpty(findRelease, major); {
+		case findRelease == nil:
+			update.Status = "check failed"
+		case release.URL == "":
+			update.Status = "not offered"
+		case utils.CompareJavaVersions(release.Version, version) > 0:
+			update.Latest, update.Status = release.Version, "update available"
+		default:
+			update.Latest, update.Status = release.Version, "up to date"
+		}
+		updates = append(updates, update)
+	}
+	return updates
+}
+
+// releaseOrEmpty risolve l'ultima release di una major, o una release vuota se il provider non ha risposto.
+func releaseOrEmpty(findRelease releaseFinder, major int) downloadRelease {
+	if findRelease == nil {
+		return downloadRelease{}
+	}
+	return findRelease(strconv.Itoa(major))
+}
+
+// displayInstallUpdates mostra l'esito di 'jenvy list --check-updates' sotto la tabella delle installazioni.
+//
+// Le patch disponibili sono in giallo, le major fuori supporto in rosso: per
+// queste l'aggiornamento di patch non basta e conviene passare a una major
+// supportata.
+func displayInstallUpdates(updates []installUpdate) {
+	fmt.Println()
+	fmt.Println(utils.ColorText("Update check:", utils.Bold+utils.BrightCyan))
+	if len(updates) == 0 {
+		fmt.Println("   No installation with a recognizable version")
+		return
+	}
+
+	outdated, eol, current := 0, 0, 0
+	for _, update := range updates {
+		color := utils.BrightGreen
+		status := update.Status
+		switch update.Status {
+		case "update available":
+			color = utils.BrightYellow
+			status = fmt.Sprintf("%s available", update.Latest)
+			outdated++
+		case "up to date":
+			current++
+		default:
+			color = utils.Yellow
+		}
+		line := fmt.Sprintf("   %-30s %-10s %-18s %s", update.Name, providerLabel(update.Provider), update.Installed, utils.ColorText(status, color))
+		if update.EOL {
+			line += " " + utils.ColorText(fmt.Sprintf("[EOL: JDK %d is no longer supported]", update.Major), utils.BrightRed)
+			eol++
+		}
+		fmt.Println(line)
+	}
+
+	fmt.Println()
+	if outdated > 0 {
+		utils.PrintInfo(fmt.Sprintf("%d installation(s) can be updated: run 'jenvy update --all'", outdated))
+	}
+	if eol > 0 {
+		utils.PrintWarning(fmt.Sprintf("%d installation(s) use an end-of-life major: move to a supported LTS (e.g. 'jenvy install %d --use')", eol, utils.LTSMajors[len(utils.LTSMajors)-1]))
+	}
+	if current == len(updates) && eol == 0 {
+		utils.PrintSuccess("All installations are up to date")
+	}
+}
+
+// providerLabel restituisce il provider da mostrare, "unknown" se non registrato.
+func providerLabel(provider string) string {
+	if provider == "" {
+		return "unknown"
+	}
+	return provider
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"jenvy/internal/utils"
+)
+
+// LocalJDK gestisce 'jenvy local': fissa, mostra o rimuove il JDK del progetto.
+//
+// È la scorciatoia di 'jenvy use <version> --local', sul modello di
+// 'nvm'/'sdkman'/'jenv local'. Il file scritto è sempre .jenvy-version nella
+// directory corrente; in lettura vale anche .java-version (vedi
+// utils.FindProjectVersion), cercato risalendo le directory superiori.
+//
+// Modalità:
+//   - **local <version>**: Verifica che il JDK sia installato e scrive .jenvy-version
+//   - **local**: Mostra la versione fissata e il file da cui proviene
+//   - **local --unset**: Elimina .jenvy-version dalla directory corrente
+//
+// Esempio di utilizzo:
+//
+//	jenvy local 21
+//	jenvy local
+//	jenvy local --unset
+func LocalJDK() error {
+	unset := false
+	positional, flags := utils.SplitArgs(os.Args[2:])
+	for _, arg := range flags {
+		if arg != "--unset" {
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Unknown option for local: %s", arg))
+		}
+		unset = true
+	}
+	if len(positional) > 1 || (unset && len(positional) > 0) {
+		utils.PrintUsage("Usage: jenvy local [<version>] | jenvy local --unset")
+		return utils.ExitWith(utils.ExitGeneric, nil)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to determine current directory: %v", err))
+	}
+
+	if unset {
+		return unsetProjectVersion(cwd)
+	}
+	if len(positional) == 0 {
+		return showProjectVersion(cwd)
+	}
+
+	versionsDir, _ := utils.GetJenvyVersionsDirectory()
+	version, err := versionArgument(positional[0], versionsDir)
+	if err != nil {
+		return err
+	}
+	jdkPath, ok := resolveInstalledJDK(version)
+	if !ok {
+		return utils.ExitWith(utils.ExitNotFound, nil)
+	}
+	return useJDKForProject(version, jdkPath)
+}
+
+// showProjectVersion stampa la versione fissata per il progetto di dir e il JDK che la soddisfa.
+func showProjectVersion(dir string) error {
+	project, ok := utils.FindProjectVersion(dir)
+	if !ok {
+		utils.PrintInfo(fmt.Sprintf("No %s or %s found in this directory or its parents", utils.ProjectVersionFile, utils.JavaVersionFile))
+		utils.PrintInfo("Use 'jenvy local <version>' to pin a JDK for this project")
+		return utils.ExitWith(utils.ExitNotFound, nil)
+	}
+	fmt.Println(project.Value)
+	utils.PrintInfo(fmt.Sprintf("Pinned by %s", project.Source))
+	if !isJDKInstalled(project.Value) {
+		utils.PrintWarning(fmt.Sprintf("JDK %s is not installed; run 'jenvy download %s'", project.Value, project.Value))
+	}
+	return nil
+}
+
+// unsetProjectVersion elimina il .jenvy-version della directory corrente.
+//
+// Un .java-version non viene toccato: appartiene di solito ad altri strumenti.
+func unsetProjectVersion(dir string) error {
+	path := filepath.Join(dir, utils.ProjectVersionFile)
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			utils.PrintInfo(fmt.Sprintf("No %s in this directory", utils.ProjectVersionFile))
+			return nil
+		}
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to delete %s: %v", path, err))
+	}
+	utils.PrintSuccess(fmt.Sprintf("Removed %s", path))
+	if project, ok := utils.FindProjectVersion(dir); ok {
+		utils.PrintInfo(fmt.Sprintf("JDK %s still applies from %s", project.Value, project.Source))
+	}
+	return nil
+}
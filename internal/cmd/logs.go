@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"jenvy/internal/utils"
+)
+
+// defaultLogTailLines è il numero di righe del log mostrate da 'jenvy logs'.
+const defaultLogTailLines = 20
+
+// ShowLogs gestisce 'jenvy logs': mostra il log diagnostico corrente o cancella i log.
+//
+// Modalità:
+//   - **logs**: Percorso del log del giorno e sue ultime righe (da allegare a una segnalazione)
+//   - **logs --lines=N**: Ultime N righe invece di 20
+//   - **logs --clear**: Elimina tutti i log di jenvy in ~/.jenvy/logs
+//
+// La conservazione (log-retention, in giorni) viene applicata a ogni avvio di
+// jenvy da utils.StartLogging; 'jenvy logs' non scrive nel log.
+//
+// Esempio di utilizzo:
+//
+//	jenvy logs
+//	jenvy logs --lines=100
+//	jenvy logs --clear
+func ShowLogs() error {
+	clear := false
+	lines := defaultLogTailLines
+	for _, arg := range os.Args[2:] {
+		switch {
+		case arg == "--clear":
+			clear = true
+		case strings.HasPrefix(arg, "--lines="):
+			n, err := strconv.Atoi(strings.TrimPrefix(arg, "--lines="))
+			if err != nil || n < 1 {
+				return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Invalid --lines value: %s", strings.TrimPrefix(arg, "--lines=")))
+			}
+			lines = n
+		default:
+			utils.PrintUsage("Usage: jenvy logs [--lines=N] | jenvy logs --clear")
+			return utils.ExitWith(utils.ExitGeneric, nil)
+		}
+	}
+
+	dir, err := utils.LogsDir()
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to determine log directory: %v", err))
+	}
+
+	if clear {
+		files, err := utils.ListLogFiles(dir)
+		if err != nil {
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to read %s: %v", dir, err))
+		}
+		removed := 0
+		for _, file := range files {
+			if err := os.Remove(file); err != nil {
+				utils.PrintWarning(fmt.Sprintf("Could not delete %s: %v", file, err))
+				continue
+			}
+			removed++
+		}
+		utils.PrintSuccess(fmt.Sprintf("Deleted %s from %s", pluralize(removed, "log file"), dir))
+		return nil
+	}
+
+	current := utils.CurrentLogPath(dir, time.Now())
+	content, err := os.ReadFile(current)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to read %s: %v", current, err))
+		}
+		// Nessun comando eseguito oggi: mostra il log più recente, se esiste
+		files, _ := utils.ListLogFiles(dir)
+		if len(files) == 0 {
+			utils.PrintInfo(fmt.Sprintf("No logs yet in %s", dir))
+			return utils.ExitWith(utils.ExitNotFound, nil)
+		}
+		current = files[len(files)-1]
+		if content, err = os.ReadFile(current); err != nil {
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to read %s: %v", current, err))
+		}
+	}
+
+	cfg, _ := utils.LoadConfigOrDefault()
+	utils.PrintInfo(fmt.Sprintf("Log file: %s", current))
+	utils.PrintInfo(fmt.Sprintf("Keeping %d days of logs (jenvy config set log-retention <days>)", utils.LogRetentionDays(cfg)))
+	fmt.Println()
+
+	all := strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	if len(all) > lines {
+		all = all[len(all)-lines:]
+	}
+	for _, line := range all {
+		fmt.Println(strings.TrimRight(line, "\r"))
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"jenvy/internal/utils"
+)
+
+// toastTimeout limita l'attesa di PowerShell: la notifica non deve mai rallentare il comando.
+const toastTimeout = 10 * time.Second
+
+// toastAppID è l'AppUserModelID di Windows PowerShell, registrato su ogni installazione.
+//
+// Le notifiche toast richiedono un'applicazione registrata nel menu Start; jenvy
+// non ne installa una, quindi la notifica viene mostrata a nome di PowerShell.
+const toastAppID = `{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe`
+
+// toastScript mostra una notifica con le API WinRT ToastNotificationManager.
+//
+// Titolo e testo arrivano dalle variabili d'ambiente JENVY_TOAST_TITLE e
+// JENVY_TOAST_TEXT, così non serve alcun escaping dei valori nello script.
+const toastScript = `
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $template.GetElementsByTagName('text')
+$text.Item(0).AppendChild($template.CreateTextNode($env:JENVY_TOAST_TITLE)) > $null
+$text.Item(1).AppendChild($template.CreateTextNode($env:JENVY_TOAST_TEXT)) > $null
+$toast = [Windows.UI.Notifications.ToastNotification]::new($template)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier($env:JENVY_TOAST_APP).Show($toast)
+`
+
+// notifyCompletion mostra una notifica Windows al termine di un'operazione lunga.
+//
+// Attiva solo con 'jenvy config set notify-on-complete true', così script e CI
+// non ricevono mai notifiche inattese. Qualsiasi errore (PowerShell assente,
+// notifiche disabilitate dai criteri di sistema) viene ignorato: la notifica è
+// un di più e non deve cambiare l'esito del comando.
+//
+// Parametri:
+//
+//	operation string - Operazione conclusa (es. "Download", "Upgrade")
+//	subject string   - Oggetto dell'operazione (es. "JDK 21.0.5+11")
+//	err error        - Esito dell'operazione (nil = successo)
+//
+// Esempio di utilizzo:
+//
+//	notifyCompletion("Download", "JDK 21.0.5+11", nil)
+//	// Toast: "Jenvy: Download completed" / "JDK 21.0.5+11"
+func notifyCompletion(operation, subject string, err error) {
+	cfg, cfgErr := utils.LoadConfigOrDefault()
+	if cfgErr != nil || !cfg.NotifyOnComplete {
+		return
+	}
+
+	title := fmt.Sprintf("Jenvy: %s completed", operation)
+	text := subject
+	if err != nil {
+		title = fmt.Sprintf("Jenvy: %s failed", operation)
+		text = fmt.Sprintf("%s: %v", subject, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), toastTimeout)
+	defer cancel()
+	toast := exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-WindowStyle", "Hidden", "-Command", toastScript)
+	toast.Env = append(os.Environ(),
+		"JENVY_TOAST_TITLE="+title,
+		"JENVY_TOAST_TEXT="+text,
+		"JENVY_TOAST_APP="+toastAppID,
+	)
+	toast.Run()
+}
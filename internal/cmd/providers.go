@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"jenvy/internal/utils"
+)
+
+// providerProbeTimeout è il tempo massimo concesso a ciascun provider per rispondere.
+const providerProbeTimeout = 10 * time.Second
+
+// providerEndpoint associa un provider all'endpoint API usato per il controllo di stato.
+type providerEndpoint struct {
+	Name  string
+	URL   string
+	Token string
+}
+
+// publicProviderEndpoints sono gli endpoint API dei provider pubblici interrogati da remote-list e download.
+var publicProviderEndpoints = []providerEndpoint{
+	{Name: "Adoptium", URL: "https://api.adoptium.net/v3/info/available_releases"},
+	{Name: "Azul", URL: "https://api.azul.com/metadata/v1/zulu/packages?page_size=1"},
+	{Name: "Liberica", URL: "https://api.bell-sw.com/v1/liberica/releases?bitness=64&os=windows&package-type=zip&bundle-type=jdk"},
+	{Name: "GraalVM", URL: "https://api.foojay.io/disco/v3.0/major_versions?maintained=true"},
+}
+
+// providerInfo descrive un provider mostrato da 'jenvy providers'.
+type providerInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	BuiltIn     bool   `json:"built_in"`
+	Default     bool   `json:"default"`
+}
+
+// builtinProviders sono i provider integrati, nell'ordine in cui vengono mostrati.
+//
+// Ogni nuovo provider pubblico va aggiunto qui, così compare in 'jenvy providers'
+// e nel completamento di --provider.
+var builtinProviders = []providerInfo{
+	{Name: "adoptium", Description: "Eclipse Temurin builds from the Adoptium project", BuiltIn: true},
+	{Name: "azul", Description: "Azul Zulu builds of OpenJDK", BuiltIn: true},
+	{Name: "liberica", Description: "BellSoft Liberica JDK builds", BuiltIn: true},
+	{Name: "graalvm", Description: "GraalVM Community or Oracle GraalVM (graalvm-edition), with native-image", BuiltIn: true},
+}
+
+// ManageProviders gestisce il comando 'jenvy providers'.
+//
+// Sottocomandi supportati:
+//
+//	jenvy providers            # Elenca i provider integrati e configurati
+//	jenvy providers --json     # Stesso elenco in formato JSON
+//	jenvy providers status     # Verifica la raggiungibilità delle API dei provider
+func ManageProviders() error {
+	positional, flags := utils.SplitArgs(os.Args[2:])
+	switch strings.Join(append(positional, flags...), " ") {
+	case "":
+		return ListProviders(false)
+	case "--json":
+		return ListProviders(true)
+	case "status":
+		return ShowProvidersStatus()
+	default:
+		utils.PrintUsage("Usage: jenvy providers [--json] | jenvy providers status")
+		return utils.ExitWith(utils.ExitGeneric, nil)
+	}
+}
+
+// availableProviders restituisce i provider integrati seguiti dal repository privato, se configurato.
+//
+// Il provider predefinito (utils.DefaultProvider) viene marcato con Default.
+func availableProviders() []providerInfo {
+	providers := append([]providerInfo{}, builtinProviders...)
+	if cfg, err := utils.LoadConfigOrDefault(); err == nil && cfg.PrivateEndpoint != "" {
+		providers = append(providers, providerInfo{
+			Name:        "private",
+			Description: fmt.Sprintf("Private repository at %s", cfg.PrivateEndpoint),
+		})
+	}
+
+	defaultProvider := utils.DefaultProvider()
+	for i := range providers {
+		providers[i].Default = providers[i].Name == defaultProvider
+	}
+	return providers
+}
+
+// ListProviders elenca i provider utilizzabili con --provider.
+//
+// Mostra i provider integrati con una breve descrizione, il repository privato
+// configurato con 'jenvy configure-private' e quale provider è il predefinito.
+// Non effettua richieste di rete: per la raggiungibilità c'è 'jenvy providers status'.
+//
+// Parametri:
+//
+//	asJSON bool - true per stampare l'elenco in JSON (--json), pensato per script e strumenti
+func ListProviders(asJSON bool) error {
+	providers := availableProviders()
+	if asJSON {
+		out, err := json.MarshalIndent(providers, "", "  ")
+		if err != nil {
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to encode providers: %v", err))
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	utils.PrintSection("[PROVIDERS] AVAILABLE PROVIDERS")
+	for _, provider := range providers {
+		marker := " "
+		if provider.Default {
+			marker = "*"
+		}
+		fmt.Printf("  %s %-10s %s\n", utils.ColorText(marker, utils.Green), provider.Name, provider.Description)
+	}
+	fmt.Println()
+	utils.PrintInfo("* = default provider, used when --provider is omitted")
+	if len(providers) == len(builtinProviders) {
+		utils.PrintInfo("Add a private repository with 'jenvy configure-private <endpoint>'")
+	}
+	return nil
+}
+
+// CompleteProviders stampa i nomi dei provider disponibili, uno per riga.
+//
+// Comando nascosto '__complete-providers' usato dagli script di completamento,
+// così il repository privato viene suggerito solo quando è configurato.
+func CompleteProviders() {
+	for _, provider := range availableProviders() {
+		fmt.Println(provider.Name)
+	}
+}
+
+// ShowProvidersStatus verifica la raggiungibilità delle API di tutti i provider.
+//
+// Quando 'download' o 'remote-list' falliscono, permette di distinguere un problema
+// della rete o del proxy locale (nessun provider raggiungibile) da un singolo
+// provider non disponibile. I controlli usano richieste HEAD leggere eseguite in
+// parallelo tramite il client HTTP condiviso (vedi utils.ProbeEndpoint), quindi
+// rispettano la stessa configurazione proxy dei download.
+//
+// Per ogni provider mostra:
+//   - **Stato**: OK, HTTP <codice> (risponde con errore) o UNREACHABLE
+//   - **Latenza**: Tempo di risposta della richiesta
+//   - **Dettaglio**: Codice HTTP o errore di rete
+//
+// Il repository privato viene incluso solo se configurato, con il relativo token.
+// Se almeno un provider non è raggiungibile restituisce un errore con utils.ExitNetwork.
+func ShowProvidersStatus() error {
+	endpoints := append([]providerEndpoint{}, publicProviderEndpoints...)
+	if cfg, err := utils.LoadConfigOrDefault(); err == nil && cfg.PrivateEndpoint != "" {
+		endpoints = append(endpoints, providerEndpoint{Name: "Private", URL: cfg.PrivateEndpoint, Token: cfg.PrivateToken})
+	}
+
+	utils.PrintFetch(fmt.Sprintf("Checking %d provider endpoints...", len(endpoints)))
+	results := make([]utils.ProbeResult, len(endpoints))
+	utils.ForEachParallel(len(endpoints), len(endpoints), func(i int) {
+		results[i] = utils.ProbeEndpoint(endpoints[i].URL, endpoints[i].Token, providerProbeTimeout)
+	})
+
+	fmt.Println()
+	reachable := 0
+	for i, endpoint := range endpoints {
+		result := results[i]
+		status, color, detail := "UNREACHABLE", utils.Red, ""
+		if result.Reachable() {
+			reachable++
+			status, color = "OK", utils.Green
+			detail = fmt.Sprintf("HTTP %d", result.StatusCode)
+			if result.StatusCode >= http.StatusBadRequest {
+				status, color = fmt.Sprintf("HTTP %d", result.StatusCode), utils.Yellow
+				detail = http.StatusText(result.StatusCode)
+			}
+		} else {
+			detail = result.Err.Error()
+		}
+		fmt.Printf("  %-10s %s %6d ms  %s\n", endpoint.Name,
+			utils.ColorText(fmt.Sprintf("%-12s", status), color), result.Latency.Milliseconds(), detail)
+	}
+	fmt.Println()
+
+	switch {
+	case reachable == 0:
+		utils.PrintError("No provider is reachable: check your network connection or proxy settings")
+		utils.PrintInfo("Proxy is read from 'jenvy config set proxy <url>', or HTTP_PROXY / HTTPS_PROXY / NO_PROXY")
+	case reachable < len(endpoints):
+		utils.PrintWarning("Some providers are unreachable: the problem is likely on the provider side")
+	default:
+		utils.PrintSuccess("All providers are reachable")
+		return nil
+	}
+	return utils.ExitWith(utils.ExitNetwork, fmt.Errorf("%d of %d provider(s) unreachable", len(endpoints)-reachable, len(endpoints)))
+}
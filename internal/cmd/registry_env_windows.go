@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"unsafe"
+
+	"jenvy/internal/utils"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+// systemEnvironmentKey è la chiave HKLM delle variabili d'ambiente di sistema.
+const systemEnvironmentKey = `SYSTEM\CurrentControlSet\Control\Session Manager\Environment`
+
+// Costanti di SendMessageTimeoutW per la notifica del cambio di ambiente.
+const (
+	hwndBroadcast          = 0xFFFF // HWND_BROADCAST: tutte le finestre top-level
+	wmSettingChange        = 0x001A // WM_SETTINGCHANGE
+	smtoAbortIfHung        = 0x0002 // SMTO_ABORTIFHUNG: non attende le finestre bloccate
+	settingChangeTimeoutMs = 5000   // Attesa massima per finestra, in millisecondi
+)
+
+var procSendMessageTimeoutW = windows.NewLazySystemDLL("user32.dll").NewProc("SendMessageTimeoutW")
+
+// broadcastEnvironmentChange notifica a Explorer e alle altre applicazioni che l'ambiente è cambiato.
+//
+// Invia WM_SETTINGCHANGE con lParam "Environment", come fanno il pannello di
+// controllo e setx: Explorer rilegge le variabili dal registro, quindi i terminali
+// aperti da lì dopo 'jenvy use' vedono subito il nuovo JAVA_HOME senza logout.
+// I terminali già aperti mantengono il proprio ambiente. Va chiamata dopo ogni
+// scrittura riuscita delle variabili nel registro; un fallimento viene solo
+// segnalato perché la modifica nel registro è comunque valida.
+func broadcastEnvironmentChange() {
+	param, err := windows.UTF16PtrFromString("Environment")
+	if err != nil {
+		return
+	}
+	var result uintptr
+	ret, _, callErr := procSendMessageTimeoutW.Call(
+		hwndBroadcast,
+		wmSettingChange,
+		0,
+		uintptr(unsafe.Pointer(param)),
+		smtoAbortIfHung,
+		settingChangeTimeoutMs,
+		uintptr(unsafe.Pointer(&result)))
+	if ret == 0 {
+		utils.PrintWarning(fmt.Sprintf("Could not notify running applications of the environment change: %v", callErr))
+		utils.PrintInfo("New terminals may need a logout/login to see the change")
+	}
+}
+
+// registryOpenAttempts e registryRetryDelay regolano i tentativi di apertura della chiave.
+//
+// Antivirus e installer tengono talvolta la chiave occupata per pochi istanti:
+// qualche tentativo ravvicinato evita di far fallire 'jenvy use' per un blocco transitorio.
+const (
+	registryOpenAttempts = 4
+	registryRetryDelay   = 150 * time.Millisecond
+)
+
+// openSystemEnvironmentKey apre la chiave delle variabili di sistema riprovando sugli errori transitori.
+//
+// Tra un tentativo e l'altro l'attesa raddoppia (150ms, 300ms, 600ms). Gli errori
+// non transitori, come l'accesso negato, vengono restituiti subito. L'errore finale
+// è già tradotto da describeRegistryError.
+//
+// Parametri:
+//
+//	access uint32 - Diritti richiesti (es. registry.QUERY_VALUE|registry.SET_VALUE)
+func openSystemEnvironmentKey(access uint32) (registry.Key, error) {
+	delay := registryRetryDelay
+	var err error
+	for attempt := 1; attempt <= registryOpenAttempts; attempt++ {
+		var key registry.Key
+		key, err = registry.OpenKey(registry.LOCAL_MACHINE, systemEnvironmentKey, access)
+		if err == nil {
+			return key, nil
+		}
+		if !isTransientRegistryError(err) || attempt == registryOpenAttempts {
+			break
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+	return 0, describeRegistryError("failed to open registry key", err)
+}
+
+// isTransientRegistryError riconosce gli errori dovuti a un altro processo che usa la chiave.
+func isTransientRegistryError(err error) bool {
+	return errors.Is(err, windows.ERROR_SHARING_VIOLATION) ||
+		errors.Is(err, windows.ERROR_LOCK_VIOLATION) ||
+		errors.Is(err, windows.ERROR_BUSY)
+}
+
+// describeRegistryError traduce gli errori comuni del registro in messaggi con un'azione da compiere.
+//
+// L'errore originale resta disponibile con errors.Is/As.
+//
+// Esempio di utilizzo:
+//
+//	return describeRegistryError("failed to update PATH", err)
+//	// → failed to update PATH: access denied (run as Administrator, or use 'jenvy use <version> --temporary'): Access is denied.
+func describeRegistryError(action string, err error) error {
+	switch {
+	case errors.Is(err, windows.ERROR_ACCESS_DENIED):
+		return fmt.Errorf("%s: access denied (run as Administrator, or use 'jenvy use <version> --temporary'): %w", action, err)
+	case isTransientRegistryError(err):
+		return fmt.Errorf("%s: another process is modifying the environment, retry in a moment: %w", action, err)
+	default:
+		return fmt.Errorf("%s: %w", action, err)
+	}
+}
+
+// registryExitCode sceglie il codice di uscita per un errore del registro.
+//
+// L'accesso negato diventa utils.ExitPermission, così gli script possono
+// distinguerlo da un errore generico e riprovare con --user o come amministratore.
+func registryExitCode(err error) int {
+	if errors.Is(err, windows.ERROR_ACCESS_DENIED) {
+		return utils.ExitPermission
+	}
+	return utils.ExitGeneric
+}
@@ -1,13 +1,18 @@
 package cmd
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"jenvy/internal/providers/adoptium"
 	"jenvy/internal/providers/azul"
+	"jenvy/internal/providers/graalvm"
 	"jenvy/internal/providers/liberica"
 	"jenvy/internal/providers/private"
 	"jenvy/internal/utils"
@@ -30,6 +35,13 @@ import (
 //     - --latest: Visualizza solo l'ultima versione disponibile
 //     - --jdk=XX: Filtra per versione JDK specifica (es. --jdk=17)
 //     - --lts-only: Mostra esclusivamente versioni Long Term Support
+//     - --since=DATA|DURATA: Solo release recenti (es. 2024-06-01, 90d, 6m, 1y)
+//     - --ea: Include le build early-access di Adoptium e Liberica, marcate "(EA)"
+//     - --type=jre|jdk, --javafx: Runtime invece del JDK, pacchetti con JavaFX (Liberica Full, Zulu FX)
+//     - --count: Stampa solo il numero di versioni trovate (utile negli script)
+//     - --fields=a,b: Colonne da mostrare e relativo ordine (es. version,lts)
+//     - --raw: Stampa il JSON inviato dal provider, senza parsing né tabella
+//     - --output=json|csv: Stampa le righe come dati strutturati invece della tabella
 //
 //  3. **Modalità intelligente predefinita**: Quando nessun filtro è specificato,
 //     applica logica di selezione smart che raccomanda le versioni più appropriate
@@ -43,6 +55,9 @@ import (
 //     - Stato LTS (Long Term Support)
 //     - URL di download diretto
 //
+//     Le righe di tutti i provider vengono raccolte prima del rendering, così che
+//     la tabella sia seguita da un riepilogo "N versions from M providers".
+//
 // **Caratteristiche Windows-specifiche:**
 // - Filtra automaticamente solo le versioni Windows-compatibili
 // - Riconosce architetture Windows (x64, arm64)
@@ -56,69 +71,461 @@ import (
 //	jenvy remote-list --all                             # Tutte le versioni di tutti i provider
 //	jenvy remote-list --provider=azul --lts-only        # Solo LTS di Azul
 //	jenvy remote-list --jdk=17 --latest                 # Ultima versione JDK 17
+//	jenvy remote-list --jdk=17 --all --since=6m         # Patch di 17 degli ultimi sei mesi
+//	jenvy remote-list --provider=adoptium --ea          # Anche le build EA della prossima major
+//	jenvy remote-list --all --type=jre --javafx         # JRE con JavaFX dei provider che li pubblicano
+//	jenvy remote-list --all --lts-only --count          # Solo il numero di versioni LTS
+//	jenvy remote-list --all --fields=version,lts        # Tabella compatta senza URL
+//	jenvy remote-list --provider=azul --raw > azul.json # Risposta grezza per il debug
+//	jenvy remote-list --all --output=json               # Versioni per script e IDE (vedi writeRemoteRecords)
 //
 // Parametri:
 //   - defaultProvider: Provider predefinito da utilizzare se non specificato
-func RemoteList(defaultProvider string) {
+func RemoteList(defaultProvider string) error {
+	// Opzioni proprie del comando: un'opzione sconosciuta è un errore, non un'uscita del processo
+	fs := flag.NewFlagSet("remote-list", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
 	// Usa il valore ricevuto da main.go come default
-	provider := flag.String("provider", defaultProvider, "provider: adoptium | azul | liberica | private")
-	all := flag.Bool("all", false, "Show versions from all providers")
-	majorOnly := flag.Bool("major-only", false, "Show only major releases")
-	latestOnly := flag.Bool("latest", false, "Show only the latest version")
-	jdkFilter := flag.Int("jdk", 0, "Filter only one JDK version (e.g. --jdk=17)")
-	ltsOnly := flag.Bool("lts-only", false, "Show only LTS versions")
-	flag.CommandLine.Parse(os.Args[2:])
-
-	defaultMode := !*all && !*majorOnly && !*latestOnly && *jdkFilter == 0 && !*ltsOnly
-
-	if *all && defaultMode {
-		utils.PrintInfo("Smart selection with recommended version for each provider\n")
-		printRecommendedAdoptium()
-		printRecommendedAzul()
-		printRecommendedLiberica()
-		return
-	}
-
-	if defaultMode {
-		utils.PrintInfo(fmt.Sprintf("Smart selection with recommended version for provider: %s\n", *provider))
-		switch strings.ToLower(*provider) {
-		case "adoptium":
-			printRecommendedAdoptium()
-		case "azul":
-			printRecommendedAzul()
-		case "liberica":
-			printRecommendedLiberica()
-		case "private":
-			printRecommendedPrivate()
-		default:
-			utils.PrintError(fmt.Sprintf("Invalid provider '%s'. Use --provider=adoptium | azul | liberica | private", *provider))
-		}
-		return
-	}
-
-	if *all {
-		utils.PrintSearch("Fetching JDKs from all providers...\n")
-		printAdoptium(*majorOnly, *latestOnly, *jdkFilter, *ltsOnly)
-		printAzul(*majorOnly, *latestOnly, *jdkFilter, *ltsOnly)
-		printLiberica(*majorOnly, *latestOnly, *jdkFilter, *ltsOnly)
-		return
-	}
-
-	switch strings.ToLower(*provider) {
-	case "adoptium":
-		printAdoptium(*majorOnly, *latestOnly, *jdkFilter, *ltsOnly)
-	case "azul":
-		printAzul(*majorOnly, *latestOnly, *jdkFilter, *ltsOnly)
-	case "liberica":
-		printLiberica(*majorOnly, *latestOnly, *jdkFilter, *ltsOnly)
-	case "private":
-		printPrivate(*majorOnly, *latestOnly, *jdkFilter, *ltsOnly)
+	provider := fs.String("provider", defaultProvider, "provider: adoptium | azul | liberica | graalvm | private")
+	all := fs.Bool("all", false, "Show versions from all providers")
+	majorOnly := fs.Bool("major-only", false, "Show only major releases")
+	latestOnly := fs.Bool("latest", false, "Show only the latest version")
+	jdkFilter := fs.Int("jdk", 0, "Filter only one JDK version (e.g. --jdk=17)")
+	ltsOnly := fs.Bool("lts-only", false, "Show only LTS versions")
+	sinceFlag := fs.String("since", "", "Show only releases published after a date or within a duration (e.g. 2024-06-01, 90d, 6m)")
+	earlyAccess := fs.Bool("ea", false, "Include early-access builds of upcoming JDKs (adoptium, liberica)")
+	typeFlag := fs.String("type", utils.ImageTypeJDK, "Image type: jdk | jre")
+	javafx := fs.Bool("javafx", false, "Show packages bundling JavaFX (azul, liberica)")
+	countOnly := fs.Bool("count", false, "Print only the number of matching versions")
+	fieldsFlag := fs.String("fields", "", "Comma-separated columns to show, in order (version, os, arch, lts, download)")
+	raw := fs.Bool("raw", false, "Print the provider's JSON response unmodified")
+	outputFlag := fs.String("output", utils.OutputTable, "Output format: table | json | csv")
+	if err := fs.Parse(os.Args[2:]); err != nil {
+		return utils.Fail(utils.ExitGeneric, err.Error())
+	}
+	if fs.NArg() > 0 {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Unexpected argument for remote-list: %s", fs.Arg(0)))
+	}
+
+	output, err := utils.ParseOutputFormat(*outputFlag)
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, err.Error())
+	}
+	machine := output != utils.OutputTable
+	if machine && (*raw || *countOnly) {
+		return utils.Fail(utils.ExitGeneric, "--output cannot be combined with --raw or --count")
+	}
+	// Con --output=json|csv lo stdout contiene solo i dati: ogni altro messaggio va su stderr
+	stdout := os.Stdout
+	if machine {
+		os.Stdout = os.Stderr
+		defer func() { os.Stdout = stdout }()
+	}
+
+	kind, err := utils.ParseImageType(*typeFlag)
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, err.Error())
+	}
+	utils.SetImageVariant(kind, *javafx)
+	if !*all {
+		if err := utils.CheckImageVariant(strings.ToLower(*provider)); err != nil {
+			return utils.Fail(utils.ExitNotFound, err.Error())
+		}
+	}
+
+	if *raw {
+		if *all {
+			return utils.Fail(utils.ExitGeneric, "--raw works with a single --provider, not with --all")
+		}
+		if *majorOnly || *latestOnly || *jdkFilter != 0 || *ltsOnly || *sinceFlag != "" || *countOnly || *fieldsFlag != "" || *earlyAccess {
+			return utils.Fail(utils.ExitGeneric, "--raw prints the provider response as is and cannot be combined with filters, --ea, --count or --fields")
+		}
+		return printRawProviderJSON(*provider)
+	}
+
+	if *earlyAccess {
+		utils.SetIncludeEarlyAccess(true)
+		if !*all && !earlyAccessProviders[strings.ToLower(*provider)] && !*countOnly {
+			utils.PrintWarning(fmt.Sprintf("%s does not publish early-access builds; --ea applies to adoptium and liberica", *provider))
+		}
+	}
+
+	var fields []string
+	if *fieldsFlag != "" {
+		fields = strings.Split(*fieldsFlag, ",")
+		if _, _, err := utils.SelectColumns(nil, remoteListHeaders, fields); err != nil {
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Invalid --fields: %v", err))
+		}
+	}
+
+	var since time.Time
+	if *sinceFlag != "" {
+		parsed, err := utils.ParseSinceFilter(*sinceFlag, time.Now())
+		if err != nil {
+			return utils.Fail(utils.ExitGeneric, err.Error())
+		}
+		since = parsed
+		if !*countOnly {
+			utils.PrintInfo(fmt.Sprintf("Showing releases published since %s", since.Format("2006-01-02")))
+		}
+	}
+
+	defaultMode := !*all && !*majorOnly && !*latestOnly && *jdkFilter == 0 && !*ltsOnly && since.IsZero()
+
+	var sources []remoteSource
+	switch {
+	case *all && defaultMode:
+		sources = []remoteSource{
+			{"Adoptium", fetchRecommendedAdoptium},
+			{"Azul", fetchRecommendedAzul},
+			{"Liberica", fetchRecommendedLiberica},
+			{"GraalVM", fetchRecommendedGraalVM},
+		}
+		if !*countOnly {
+			utils.PrintInfo("Smart selection with recommended version for each provider\n")
+		}
+
+	case *all:
+		filter := func(fetch remoteFilteredFetch) func() ([][]string, error) {
+			return func() ([][]string, error) { return fetch(*majorOnly, *latestOnly, *jdkFilter, *ltsOnly, since) }
+		}
+		sources = []remoteSource{
+			{"Adoptium", filter(fetchAdoptium)},
+			{"Azul", filter(fetchAzul)},
+			{"Liberica", filter(fetchLiberica)},
+			{"GraalVM", filter(fetchGraalVM)},
+		}
+		if !*countOnly {
+			utils.PrintSearch("Fetching JDKs from all providers...\n")
+		}
+
 	default:
-		utils.PrintError(fmt.Sprintf("Invalid provider '%s'. Use --provider=adoptium | azul | liberica | private", *provider))
+		if strings.EqualFold(*provider, "private") && !private.IsConfigured() {
+			return utils.Fail(utils.ExitNotFound, "No private repository configured; run 'jenvy configure-private <endpoint>'")
+		}
+		source, ok := providerRemoteSource(*provider, defaultMode, *majorOnly, *latestOnly, *jdkFilter, *ltsOnly, since)
+		if !ok {
+			return utils.Fail(utils.ExitNotFound, fmt.Sprintf("Invalid provider '%s'. Use --provider=adoptium | azul | liberica | graalvm | private", *provider))
+		}
+		sources = []remoteSource{source}
+		if defaultMode && !*countOnly {
+			utils.PrintInfo(fmt.Sprintf("Smart selection with recommended version for provider: %s\n", *provider))
+		}
+	}
+
+	if *all && (kind != utils.ImageTypeJDK || *javafx) {
+		sources = supportedImageSources(sources, !*countOnly)
+	}
+	if utils.ImageVariantSuffix() != "" && !*countOnly {
+		utils.PrintInfo(fmt.Sprintf("Showing %s packages\n", imageVariantLabel()))
+	}
+
+	tables, failed := collectRemoteTables(sources, !*countOnly)
+	var fetchErr error
+	if failed > 0 {
+		fetchErr = utils.ExitWith(utils.ExitNetwork, fmt.Errorf("%d provider(s) failed", failed))
+	}
+
+	total := 0
+	for _, table := range tables {
+		total += len(table.Rows)
+	}
+	if *countOnly {
+		fmt.Println(total)
+		return fetchErr
+	}
+	if machine {
+		return writeRemoteRecords(stdout, output, tables, fields, fetchErr)
+	}
+
+	for _, table := range tables {
+		rows, headers, _ := utils.SelectColumns(table.Rows, remoteListHeaders, fields)
+		utils.PrintInfo(table.Provider)
+		utils.PrintTable(rows, headers)
+	}
+	utils.PrintInfo(fmt.Sprintf("%s from %s", pluralize(total, "version"), pluralize(len(tables), "provider")))
+	return fetchErr
+}
+
+// writeRemoteRecords scrive le versioni di remote-list come JSON o CSV, con il provider di ogni riga.
+//
+// Le colonne sono quelle della tabella (eventualmente ridotte da --fields)
+// precedute da "provider", così i dati di --all restano distinguibili.
+func writeRemoteRecords(out io.Writer, format string, tables []remoteTable, fields []string, fetchErr error) error {
+	_, selected, _ := utils.SelectColumns(nil, remoteListHeaders, fields)
+	headers := append([]string{"Provider"}, selected...)
+	var records [][]string
+	for _, table := range tables {
+		rows, _, _ := utils.SelectColumns(table.Rows, remoteListHeaders, fields)
+		for _, row := range rows {
+			records = append(records, append([]string{table.Provider}, row...))
+		}
+	}
+	if err := utils.WriteRecords(out, format, headers, records); err != nil {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to write output: %v", err))
+	}
+	return fetchErr
+}
+
+// supportedImageSources esclude da --all i provider che non pubblicano la variante di --type/--javafx.
+func supportedImageSources(sources []remoteSource, verbose bool) []remoteSource {
+	var kept []remoteSource
+	for _, source := range sources {
+		if utils.CheckImageVariant(strings.ToLower(source.Name)) != nil {
+			if verbose {
+				utils.PrintInfo(fmt.Sprintf("Skipping %s: no %s packages", source.Name, imageVariantLabel()))
+			}
+			continue
+		}
+		kept = append(kept, source)
+	}
+	return kept
+}
+
+// imageVariantLabel descrive la variante richiesta (es. "JRE with JavaFX").
+func imageVariantLabel() string {
+	label := strings.ToUpper(utils.ImageType())
+	if utils.JavaFX() {
+		label += " with JavaFX"
+	}
+	return label
+}
+
+// earlyAccessProviders sono i provider che con --ea aggiungono le build early-access.
+var earlyAccessProviders = map[string]bool{"adoptium": true, "liberica": true}
+
+// markEarlyAccess aggiunge utils.EarlyAccessMarker alla versione delle righe che scaricano una build EA.
+//
+// Le righe sono riconosciute dall'URL di download (ultima colonna), l'unico
+// valore che identifica la build anche dopo la selezione raccomandata.
+func markEarlyAccess(rows [][]string, earlyAccessURLs map[string]bool) [][]string {
+	if len(earlyAccessURLs) == 0 {
+		return rows
+	}
+	for _, row := range rows {
+		if earlyAccessURLs[row[len(row)-1]] {
+			row[0] += utils.EarlyAccessMarker
+		}
+	}
+	return rows
+}
+
+// adoptiumEarlyAccessURLs restituisce gli URL degli archivi delle build EA di Adoptium.
+func adoptiumEarlyAccessURLs(list []adoptium.AdoptiumResponse) map[string]bool {
+	urls := make(map[string]bool)
+	for _, release := range list {
+		if release.IsEarlyAccess() {
+			for _, binary := range release.Binaries {
+				urls[binary.Package.Link] = true
+			}
+		}
+	}
+	return urls
+}
+
+// libericaEarlyAccessURLs restituisce gli URL degli archivi delle build EA di Liberica.
+func libericaEarlyAccessURLs(list []liberica.LibericaRelease) map[string]bool {
+	urls := make(map[string]bool)
+	for _, release := range list {
+		if release.EarlyAccess {
+			urls[release.DownloadURL] = true
+		}
+	}
+	return urls
+}
+
+// remoteListHeaders sono le intestazioni della tabella di remote-list, comuni a tutti i provider.
+var remoteListHeaders = []string{"Version", "OS", "Arch", "LTS", "Download"}
+
+// remoteSource associa un provider alla funzione che ne recupera le righe da mostrare.
+type remoteSource struct {
+	Name  string
+	Fetch func() ([][]string, error)
+}
+
+// remoteTable contiene le righe raccolte da un provider, pronte per il rendering.
+type remoteTable struct {
+	Provider string
+	Rows     [][]string
+}
+
+// remoteFilteredFetch è la firma comune delle funzioni fetch* con filtri espliciti.
+type remoteFilteredFetch func(majorOnly, latestOnly bool, jdkFilter int, ltsOnly bool, since time.Time) ([][]string, error)
+
+// errReleaseDatesUnavailable indica che un provider non pubblica le date di rilascio.
+//
+// Senza data non è possibile stabilire se una release rientra nel filtro --since,
+// quindi le sue versioni vengono omesse invece di essere mostrate come recenti.
+var errReleaseDatesUnavailable = errors.New("does not publish release dates: skipped by --since")
+
+// providerRemoteSource restituisce la sorgente per un singolo provider scelto con --provider.
+//
+// In modalità predefinita (nessun filtro) usa la selezione raccomandata del provider,
+// altrimenti l'elenco completo filtrato. Restituisce false se il provider non esiste.
+func providerRemoteSource(provider string, defaultMode, majorOnly, latestOnly bool, jdkFilter int, ltsOnly bool, since time.Time) (remoteSource, bool) {
+	recommended := map[string]remoteSource{
+		"adoptium": {"Adoptium", fetchRecommendedAdoptium},
+		"azul":     {"Azul", fetchRecommendedAzul},
+		"liberica": {"Liberica", fetchRecommendedLiberica},
+		"graalvm":  {"GraalVM", fetchRecommendedGraalVM},
+		"private":  {"Private Repository", fetchRecommendedPrivate},
+	}
+	filtered := map[string]remoteFilteredFetch{
+		"adoptium": fetchAdoptium,
+		"azul":     fetchAzul,
+		"liberica": fetchLiberica,
+		"graalvm":  fetchGraalVM,
+		"private":  fetchPrivate,
+	}
+
+	key := strings.ToLower(provider)
+	source, ok := recommended[key]
+	if !ok || defaultMode {
+		return source, ok
+	}
+	fetch := filtered[key]
+	source.Fetch = func() ([][]string, error) { return fetch(majorOnly, latestOnly, jdkFilter, ltsOnly, since) }
+	return source, true
+}
+
+// printRawProviderJSON stampa su stdout la risposta del provider senza interpretarla.
+//
+// Serve a capire perché una versione che "dovrebbe" esistere non viene trovata:
+// l'output è esattamente ciò che jenvy riceve prima del parsing, quindi può
+// anche essere salvato e rielaborato con strumenti come jq. Per Adoptium, che
+// risponde con un documento per major, vedi adoptium.GetRawReleases.
+//
+// Parametri:
+//
+//	provider string - Provider scelto con --provider (adoptium | azul | liberica | graalvm | private)
+//
+// Restituisce:
+//
+//	error - utils.ExitNotFound per provider sconosciuti o privato non configurato,
+//	        utils.ExitNetwork se la richiesta fallisce
+func printRawProviderJSON(provider string) error {
+	fetchers := map[string]func() ([]byte, error){
+		"adoptium": adoptium.GetRawReleases,
+		"azul":     azul.GetRawPackages,
+		"liberica": liberica.GetRawReleases,
+		"graalvm":  graalvm.GetRawPackages,
+		"private":  private.GetRawReleases,
+	}
+	fetch, ok := fetchers[strings.ToLower(provider)]
+	if !ok {
+		return utils.Fail(utils.ExitNotFound, fmt.Sprintf("Invalid provider '%s'. Use --provider=adoptium | azul | liberica | graalvm | private", provider))
+	}
+
+	body, err := fetch()
+	if errors.Is(err, private.ErrNotConfigured) {
+		return utils.Fail(utils.ExitNotFound, "No private repository configured; run 'jenvy configure-private <endpoint>'")
+	}
+	if err != nil {
+		return utils.Fail(utils.ExitNetwork, fmt.Sprintf("%s error: %v", provider, err))
 	}
+
+	os.Stdout.Write(body)
+	if len(body) > 0 && body[len(body)-1] != '\n' {
+		fmt.Println()
+	}
+	return nil
+}
+
+// remoteFetchTimeout è il tempo massimo concesso a un provider per restituire il proprio elenco.
+//
+// Adoptium esegue una richiesta per ogni major, quindi il limite è più ampio del
+// timeout della singola richiesta; un --timeout più lungo lo estende.
+const remoteFetchTimeout = 90 * time.Second
+
+// remoteFetchResult è l'esito dell'interrogazione di una sorgente.
+type remoteFetchResult struct {
+	rows [][]string
+	err  error
 }
 
-// printRecommendedAdoptium recupera e visualizza le versioni JDK Adoptium raccomandate per Windows.
+// collectRemoteTables interroga le sorgenti in parallelo e raccoglie le righe di ciascun provider.
+//
+// Ogni provider viene interrogato in una propria goroutine con un limite di
+// remoteFetchTimeout, così un'API lenta non blocca l'elenco degli altri. I
+// risultati e gli errori vengono riportati nell'ordine delle sorgenti. I
+// provider che falliscono vengono segnalati e omessi, così che un errore di rete
+// su un provider non impedisca di mostrare gli altri.
+//
+// Parametri:
+//
+//	sources []remoteSource - Provider da interrogare
+//	verbose bool           - Se false non stampa i messaggi di avanzamento (usato da --count)
+//
+// Restituisce:
+//
+//	[]remoteTable - Righe raccolte per ogni provider interrogato con successo
+//	int           - Numero di provider che hanno restituito un errore
+func collectRemoteTables(sources []remoteSource, verbose bool) ([]remoteTable, int) {
+	timeout := remoteFetchTimeout
+	if utils.NetworkTimeout() > timeout {
+		timeout = utils.NetworkTimeout()
+	}
+
+	results := make([]remoteFetchResult, len(sources))
+	if verbose {
+		for _, source := range sources {
+			utils.PrintFetch(fmt.Sprintf("Fetching data from %s...", source.Name))
+		}
+	}
+	utils.ForEachParallel(len(sources), len(sources), func(i int) {
+		rows, err := fetchWithTimeout(sources[i].Fetch, timeout)
+		results[i] = remoteFetchResult{rows: rows, err: err}
+	})
+
+	var tables []remoteTable
+	failed := 0
+	for i, source := range sources {
+		rows, err := results[i].rows, results[i].err
+		if errors.Is(err, errReleaseDatesUnavailable) {
+			if verbose {
+				utils.PrintWarning(fmt.Sprintf("%s %v", source.Name, err))
+			}
+			continue
+		}
+		if err != nil {
+			utils.PrintError(fmt.Sprintf("%s error: %v", source.Name, err))
+			// "Private Repository" -> private, come in --provider
+			printProviderFailureHint(strings.ToLower(strings.Fields(source.Name)[0]), err)
+			failed++
+			continue
+		}
+		tables = append(tables, remoteTable{Provider: source.Name, Rows: rows})
+	}
+	return tables, failed
+}
+
+// fetchWithTimeout esegue fetch restituendo un errore se non termina entro timeout.
+//
+// La goroutine di un provider scaduto non viene interrotta: le sue richieste
+// terminano comunque per il timeout di rete, e il processo esce subito dopo.
+func fetchWithTimeout(fetch func() ([][]string, error), timeout time.Duration) ([][]string, error) {
+	done := make(chan remoteFetchResult, 1)
+	go func() {
+		rows, err := fetch()
+		done <- remoteFetchResult{rows: rows, err: err}
+	}()
+	select {
+	case result := <-done:
+		return result.rows, result.err
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("no response within %s", timeout)
+	}
+}
+
+// pluralize formatta un conteggio con il sostantivo al singolare o plurale (es. "1 version", "3 versions").
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// fetchRecommendedAdoptium recupera le versioni JDK Adoptium raccomandate per Windows.
 //
 // Questa funzione implementa la logica di selezione intelligente per le distribuzioni
 // Eclipse Temurin (Adoptium), focalizzandosi su versioni ottimali per ambienti Windows:
@@ -136,23 +543,20 @@ func RemoteList(defaultProvider string) {
 //
 // La funzione utilizza l'API ufficiale Adoptium per garantire informazioni
 // aggiornate e affidabili sulle release disponibili.
-func printRecommendedAdoptium() {
-	utils.PrintFetch("Fetching data from Adoptium...")
+func fetchRecommendedAdoptium() ([][]string, error) {
 	list, err := adoptium.GetAllJDKs()
 	if err != nil {
-		utils.PrintError(fmt.Sprintf("Adoptium error: %v", err))
-		return
+		return nil, err
 	}
-	utils.PrintInfo("Adoptium")
 	recommended := adoptium.GetRecommendedJDKs(list)
 	var data [][]string
 	for _, j := range recommended {
 		data = append(data, []string{j.Version, j.OS, j.Arch, j.LTS, j.Link})
 	}
-	utils.PrintTable(data, []string{"Version", "OS", "Arch", "LTS", "Download"})
+	return markEarlyAccess(data, adoptiumEarlyAccessURLs(list)), nil
 }
 
-// printRecommendedAzul recupera e visualizza le versioni JDK Azul Zulu raccomandate per Windows.
+// fetchRecommendedAzul recupera le versioni JDK Azul Zulu raccomandate per Windows.
 //
 // Questa funzione gestisce la selezione intelligente delle distribuzioni Azul Zulu,
 // focalizzandosi su versioni certificate e supportate per ecosistemi Windows enterprise:
@@ -171,23 +575,20 @@ func printRecommendedAdoptium() {
 //
 // La funzione accede al repository ufficiale Azul per ottenere
 // informazioni aggiornate su disponibilità e raccomandazioni.
-func printRecommendedAzul() {
-	utils.PrintFetch("Fetching data from Azul...")
+func fetchRecommendedAzul() ([][]string, error) {
 	list, err := azul.GetAzulJDKs()
 	if err != nil {
-		utils.PrintError(fmt.Sprintf("Azul error: %v", err))
-		return
+		return nil, err
 	}
-	utils.PrintInfo("Azul")
 	recommended := azul.GetRecommendedJDKs(list)
 	var data [][]string
 	for _, j := range recommended {
 		data = append(data, []string{j.Version, j.OS, j.Arch, j.LTS, j.DownloadURL})
 	}
-	utils.PrintTable(data, []string{"Version", "OS", "Arch", "LTS", "Download"})
+	return data, nil
 }
 
-// printRecommendedLiberica recupera e visualizza le versioni JDK BellSoft Liberica raccomandate per Windows.
+// fetchRecommendedLiberica recupera le versioni JDK BellSoft Liberica raccomandate per Windows.
 //
 // Questa funzione gestisce la selezione delle distribuzioni BellSoft Liberica,
 // particolarmente vantaggiose per applicazioni Windows che richiedono JavaFX:
@@ -212,23 +613,20 @@ func printRecommendedAzul() {
 //
 // La funzione accede al repository BellSoft per informazioni aggiornate
 // su versioni e componenti disponibili.
-func printRecommendedLiberica() {
-	utils.PrintFetch("Fetching data from Liberica...")
+func fetchRecommendedLiberica() ([][]string, error) {
 	list, err := liberica.GetLibericaJDKs()
 	if err != nil {
-		utils.PrintError(fmt.Sprintf("Liberica error: %v", err))
-		return
+		return nil, err
 	}
-	utils.PrintInfo("Liberica")
 	recommended := liberica.GetRecommendedJDKs(list)
 	var data [][]string
 	for _, j := range recommended {
 		data = append(data, []string{j.Version, j.OS, j.Arch, j.LTS, j.DownloadURL})
 	}
-	utils.PrintTable(data, []string{"Version", "OS", "Arch", "LTS", "Download"})
+	return markEarlyAccess(data, libericaEarlyAccessURLs(list)), nil
 }
 
-// printRecommendedPrivate visualizza le versioni JDK raccomandate da repository privati configurati per l'ambiente Windows.
+// fetchRecommendedPrivate recupera le versioni JDK raccomandate da repository privati configurati per l'ambiente Windows.
 //
 // Questa funzione gestisce distribuzioni JDK personalizzate o enterprise,
 // specificamente configurate per requisiti aziendali Windows:
@@ -253,14 +651,11 @@ func printRecommendedLiberica() {
 //
 // Prerequisito: Il repository privato deve essere configurato tramite
 // il comando 'jenvy configure private <URL>' con credenziali appropriate.
-func printRecommendedPrivate() {
-	utils.PrintFetch("Fetching data from Private repository...")
+func fetchRecommendedPrivate() ([][]string, error) {
 	list, err := private.GetPrivateJDKs()
 	if err != nil {
-		utils.PrintError(fmt.Sprintf("Private repository error: %v", err))
-		return
+		return nil, err
 	}
-	utils.PrintInfo("Private Repository")
 	var data [][]string
 	for _, j := range list {
 		ltsString := "No"
@@ -269,10 +664,10 @@ func printRecommendedPrivate() {
 		}
 		data = append(data, []string{j.Version, j.OS, j.Arch, ltsString, j.DownloadURL})
 	}
-	utils.PrintTable(data, []string{"Version", "OS", "Arch", "LTS", "Download"})
+	return data, nil
 }
 
-// printAdoptium visualizza tutte le versioni JDK Eclipse Adoptium disponibili, ottimizzate per piattaforme Windows.
+// fetchAdoptium recupera tutte le versioni JDK Eclipse Adoptium disponibili, ottimizzate per piattaforme Windows.
 //
 // Questa funzione espone l'inventario completo delle distribuzioni Adoptium,
 // fornendo accesso a tutte le versioni supportate per l'ecosistema Windows:
@@ -299,25 +694,29 @@ func printRecommendedPrivate() {
 //   - latestOnly: limita all'ultima versione disponibile per ciascun major
 //   - jdkFilter: filtra per versione JDK specifica (0 = tutte)
 //   - ltsOnly: mostra solo versioni con supporto a lungo termine
+//   - since: mostra solo release pubblicate da questa data (zero = nessun filtro)
 //
 // Utilizzare questa funzione per esplorare tutte le opzioni disponibili
 // prima di selezionare la versione più adatta all'ambiente Windows target.
-func printAdoptium(majorOnly, latestOnly bool, jdkFilter int, ltsOnly bool) {
-	utils.PrintFetch("Fetching data from Adoptium...")
+func fetchAdoptium(majorOnly, latestOnly bool, jdkFilter int, ltsOnly bool, since time.Time) ([][]string, error) {
 	list, err := adoptium.GetAllJDKs()
 	if err != nil {
-		utils.PrintError(fmt.Sprintf("Adoptium error: %v", err))
-		return
+		return nil, err
 	}
-	utils.PrintInfo("Adoptium")
 	var data [][]string
 	for _, j := range list {
-		data = append(data, []string{j.VersionData.OpenJDKVersion, "windows", "x64", "N/A", j.Binaries[0].Package.Link})
+		if jdkFilter != 0 && j.Major != jdkFilter {
+			continue
+		}
+		if !utils.ReleasedSince(j.Timestamp, since) {
+			continue
+		}
+		data = append(data, []string{j.VersionData.OpenJDKVersion, j.Binaries[0].OS, j.Binaries[0].Arch, "N/A", j.Binaries[0].Package.Link})
 	}
-	utils.PrintTable(data, []string{"Version", "OS", "Arch", "LTS", "Download"})
+	return markEarlyAccess(data, adoptiumEarlyAccessURLs(list)), nil
 }
 
-// printAzul visualizza tutte le versioni JDK Azul Zulu disponibili per l'ecosistema Windows.
+// fetchAzul recupera tutte le versioni JDK Azul Zulu disponibili per l'ecosistema Windows.
 //
 // Questa funzione fornisce accesso completo al catalogo Azul Zulu,
 // una distribuzione OpenJDK enterprise-grade ottimizzata per Windows:
@@ -344,13 +743,17 @@ func printAdoptium(majorOnly, latestOnly bool, jdkFilter int, ltsOnly bool) {
 //   - latestOnly: limita all'ultima versione disponibile per ciascun major
 //   - jdkFilter: filtra per versione JDK specifica (0 = tutte)
 //   - ltsOnly: mostra solo versioni con supporto a lungo termine
+//   - since: mostra solo release pubblicate da questa data (zero = nessun filtro)
 //
 // Ideale per valutare opzioni enterprise complete prima della selezione.
-func printAzul(majorOnly, latestOnly bool, jdkFilter int, ltsOnly bool) {
+func fetchAzul(majorOnly, latestOnly bool, jdkFilter int, ltsOnly bool, since time.Time) ([][]string, error) {
+	if !since.IsZero() {
+		return nil, errReleaseDatesUnavailable
+	}
+
 	list, err := azul.GetAzulJDKs()
 	if err != nil {
-		fmt.Println("Error fetching from Azul:", err)
-		return
+		return nil, err
 	}
 
 	var data [][]string
@@ -365,19 +768,18 @@ func printAzul(majorOnly, latestOnly bool, jdkFilter int, ltsOnly bool) {
 			}
 			data = append(data, []string{j.Version, j.OS, j.Arch, j.LTS, j.DownloadURL})
 		}
-		utils.PrintTable(data, []string{"Version", "OS", "Arch", "LTS", "Download"})
-		return
+		return data, nil
 	}
 
 	for _, j := range list {
-		if majorOnly && len(j.JavaVersion) > 1 && j.JavaVersion[1] != 0 {
+		if majorOnly && j.Minor != 0 {
 			continue
 		}
 		if !strings.HasSuffix(j.DownloadURL, ".zip") {
 			continue
 		}
 
-		major := j.JavaVersion[0]
+		major := j.Major
 		if jdkFilter != 0 && major != jdkFilter {
 			continue
 		}
@@ -397,10 +799,10 @@ func printAzul(majorOnly, latestOnly bool, jdkFilter int, ltsOnly bool) {
 			j.DownloadURL,
 		})
 	}
-	utils.PrintTable(data, []string{"Version", "OS", "Arch", "LTS", "Download"})
+	return data, nil
 }
 
-// printLiberica visualizza tutte le versioni JDK BellSoft Liberica disponibili per l'ecosistema Windows.
+// fetchLiberica recupera tutte le versioni JDK BellSoft Liberica disponibili per l'ecosistema Windows.
 //
 // Questa funzione espone il catalogo completo delle distribuzioni BellSoft Liberica,
 // particolarmente vantaggiose per applicazioni Windows che richiedono JavaFX:
@@ -427,13 +829,17 @@ func printAzul(majorOnly, latestOnly bool, jdkFilter int, ltsOnly bool) {
 //   - latestOnly: limita all'ultima versione disponibile per ciascun major
 //   - jdkFilter: filtra per versione JDK specifica (0 = tutte)
 //   - ltsOnly: mostra solo versioni con supporto a lungo termine
+//   - since: mostra solo release pubblicate da questa data (zero = nessun filtro)
 //
 // Particolarmente indicata per progetti Windows con requisiti grafici avanzati.
-func printLiberica(majorOnly, latestOnly bool, jdkFilter int, ltsOnly bool) {
+func fetchLiberica(majorOnly, latestOnly bool, jdkFilter int, ltsOnly bool, since time.Time) ([][]string, error) {
+	if !since.IsZero() {
+		return nil, errReleaseDatesUnavailable
+	}
+
 	list, err := liberica.GetLibericaJDKs()
 	if err != nil {
-		fmt.Println("Error fetching from Liberica:", err)
-		return
+		return nil, err
 	}
 
 	var data [][]string
@@ -448,16 +854,14 @@ func printLiberica(majorOnly, latestOnly bool, jdkFilter int, ltsOnly bool) {
 			}
 			data = append(data, []string{j.Version, j.OS, j.Arch, j.LTS, j.DownloadURL})
 		}
-		utils.PrintTable(data, []string{"Version", "OS", "Arch", "LTS", "Download"})
-		return
+		return markEarlyAccess(data, libericaEarlyAccessURLs(list)), nil
 	}
 
 	for _, j := range list {
-		major, _, _ := liberica.ParseLibericaVersion(j.Version)
 		if majorOnly && !(strings.Contains(j.Version, ".0.0") || strings.Contains(j.Version, "+")) {
 			continue
 		}
-		if jdkFilter != 0 && major != jdkFilter {
+		if jdkFilter != 0 && j.Major != jdkFilter {
 			continue
 		}
 		isLTS := strings.HasPrefix(j.Version, "17.") || strings.HasPrefix(j.Version, "21.") || strings.Contains(strings.ToLower(j.Version), "lts")
@@ -473,10 +877,85 @@ func printLiberica(majorOnly, latestOnly bool, jdkFilter int, ltsOnly bool) {
 			j.DownloadURL,
 		})
 	}
-	utils.PrintTable(data, []string{"Version", "OS", "Arch", "LTS", "Download"})
+	return markEarlyAccess(data, libericaEarlyAccessURLs(list)), nil
+}
+
+// fetchRecommendedGraalVM restituisce l'ultima release GraalVM di ogni major Java.
+//
+// L'edizione (Community o Oracle GraalVM) è quella scelta con graalvm-edition,
+// la stessa usata da 'jenvy download --provider=graalvm'.
+func fetchRecommendedGraalVM() ([][]string, error) {
+	list, err := graalvm.GetGraalVMJDKs()
+	if err != nil {
+		return nil, err
+	}
+	return graalVMRows(latestGraalVMPerMajor(list)), nil
+}
+
+// fetchGraalVM recupera le release GraalVM dell'edizione configurata applicando i filtri di remote-list.
+//
+// foojay non espone le date di rilascio nei pacchetti, quindi --since non è supportato.
+//
+// Parametri:
+//   - majorOnly: mostra solo versioni major (es. 17.0.0)
+//   - latestOnly: limita all'ultima versione disponibile per ciascun major
+//   - jdkFilter: filtra per versione JDK specifica (0 = tutte)
+//   - ltsOnly: mostra solo versioni con supporto a lungo termine
+//   - since: non supportato (errReleaseDatesUnavailable)
+func fetchGraalVM(majorOnly, latestOnly bool, jdkFilter int, ltsOnly bool, since time.Time) ([][]string, error) {
+	if !since.IsZero() {
+		return nil, errReleaseDatesUnavailable
+	}
+	list, err := graalvm.GetGraalVMJDKs()
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []graalvm.GraalVMPackage
+	for _, j := range list {
+		if majorOnly && (j.Minor != 0 || j.Patch != 0) {
+			continue
+		}
+		if jdkFilter != 0 && j.Major != jdkFilter {
+			continue
+		}
+		if ltsOnly && !j.LTS() {
+			continue
+		}
+		selected = append(selected, j)
+	}
+	if latestOnly {
+		selected = latestGraalVMPerMajor(selected)
+	}
+	return graalVMRows(selected), nil
+}
+
+// latestGraalVMPerMajor tiene la release più recente di ogni major, in ordine di major crescente.
+func latestGraalVMPerMajor(list []graalvm.GraalVMPackage) []graalvm.GraalVMPackage {
+	latest := make(map[int]graalvm.GraalVMPackage)
+	for _, j := range list {
+		if current, ok := latest[j.Major]; !ok || shouldPreferVersion(j.ReleaseVersion, current.ReleaseVersion) {
+			latest[j.Major] = j
+		}
+	}
+	result := make([]graalvm.GraalVMPackage, 0, len(latest))
+	for _, j := range latest {
+		result = append(result, j)
+	}
+	sort.Slice(result, func(i, k int) bool { return result[i].Major < result[k].Major })
+	return result
+}
+
+// graalVMRows converte i pacchetti GraalVM nelle righe della tabella di remote-list.
+func graalVMRows(list []graalvm.GraalVMPackage) [][]string {
+	var data [][]string
+	for _, j := range list {
+		data = append(data, []string{j.JavaVersion, j.OS, j.Arch, utils.IfBool(j.LTS()), j.DownloadURL()})
+	}
+	return data
 }
 
-// printPrivate visualizza tutte le versioni JDK disponibili da repository privati configurati per Windows.
+// fetchPrivate recupera tutte le versioni JDK disponibili da repository privati configurati per Windows.
 //
 // Questa funzione gestisce l'accesso completo a distribuzioni JDK personalizzate
 // e repository enterprise, specificamente ottimizzati per infrastrutture Windows:
@@ -504,13 +983,13 @@ func printLiberica(majorOnly, latestOnly bool, jdkFilter int, ltsOnly bool) {
 //   - latestOnly: limita all'ultima versione disponibile per ciascun major
 //   - jdkFilter: filtra per versione JDK specifica (0 = tutte)
 //   - ltsOnly: mostra solo versioni con supporto a lungo termine
+//   - since: mostra solo release pubblicate da questa data (zero = nessun filtro)
 //
 // Prerequisito: Repository privato configurato tramite 'jenvy configure private <URL>'.
-func printPrivate(majorOnly, latestOnly bool, jdkFilter int, ltsOnly bool) {
+func fetchPrivate(majorOnly, latestOnly bool, jdkFilter int, ltsOnly bool, since time.Time) ([][]string, error) {
 	list, err := private.GetPrivateJDKs()
 	if err != nil {
-		fmt.Println("[ERROR] Private error:", err)
-		return
+		return nil, err
 	}
 
 	// Converti in []RecommendedEntry e poi in []utils.Entry
@@ -531,6 +1010,9 @@ func printPrivate(majorOnly, latestOnly bool, jdkFilter int, ltsOnly bool) {
 			if ltsOnly && !entry.LtsValue() {
 				continue
 			}
+			if !utils.ReleasedSince(entry.(private.RecommendedEntry).ReleaseDate, since) {
+				continue
+			}
 			data = append(data, []string{
 				entry.(private.RecommendedEntry).Version,
 				entry.(private.RecommendedEntry).OS,
@@ -539,9 +1021,7 @@ func printPrivate(majorOnly, latestOnly bool, jdkFilter int, ltsOnly bool) {
 				entry.(private.RecommendedEntry).DownloadURL,
 			})
 		}
-		fmt.Println("[INFO] Private")
-		utils.PrintTable(data, []string{"Version", "OS", "Arch", "LTS", "Download"})
-		return
+		return data, nil
 	}
 
 	for _, entry := range all {
@@ -554,6 +1034,9 @@ func printPrivate(majorOnly, latestOnly bool, jdkFilter int, ltsOnly bool) {
 		if majorOnly && entry.MinorValue() != 0 {
 			continue
 		}
+		if !utils.ReleasedSince(entry.(private.RecommendedEntry).ReleaseDate, since) {
+			continue
+		}
 
 		data = append(data, []string{
 			entry.(private.RecommendedEntry).Version,
@@ -563,7 +1046,5 @@ func printPrivate(majorOnly, latestOnly bool, jdkFilter int, ltsOnly bool) {
 			entry.(private.RecommendedEntry).DownloadURL,
 		})
 	}
-
-	fmt.Println("[INFO] Private")
-	utils.PrintTable(data, []string{"Version", "OS", "Arch", "LTS", "Download"})
+	return data, nil
 }
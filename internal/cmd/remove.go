@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"jenvy/internal/utils"
@@ -38,39 +40,43 @@ import (
 //
 // La funzione è progettata per essere sicura e user-friendly, fornendo
 // feedback dettagliato e opzioni di rollback in caso di problemi.
-func RemoveJDK() {
-	if len(os.Args) < 3 {
+func RemoveJDK() error {
+	positional, flags := utils.SplitArgs(os.Args[2:])
+	all := slices.Contains(flags, "--all")
+	if len(positional) == 0 && !all {
 		utils.PrintUsage("Usage: jenvy remove <version>")
 		utils.PrintUsage("       jenvy remove --all")
 		utils.PrintUsage("Short form: jenvy rm <version>")
 		utils.PrintUsage("           jenvy rm -a")
 		utils.PrintInfo("Available JDKs:")
 		showAvailableJDKsForRemoval()
-		return
+		return utils.ExitWith(utils.ExitGeneric, nil)
 	}
 
 	// Controlla se è stato richiesto di rimuovere tutto
-	if os.Args[2] == "--all" || os.Args[2] == "-a" {
-		removeAllJDKs()
-		return
+	if all {
+		if len(positional) > 0 {
+			return utils.Fail(utils.ExitGeneric, "--all cannot be combined with a version")
+		}
+		return removeAllJDKs()
 	}
 
-	version := os.Args[2]
-
-	// Ottieni directory home dell'utente
-	homeDir, err := os.UserHomeDir()
+	// Ottieni directory delle versioni installate
+	versionsDir, err := utils.GetJenvyVersionsDirectory()
 	if err != nil {
-		utils.PrintError(fmt.Sprintf("Error getting home directory: %v", err))
-		return
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Error getting home directory: %v", err))
 	}
 
-	versionsDir := filepath.Join(homeDir, ".jenvy", "versions")
+	version, err := versionArgument(positional[0], versionsDir)
+	if err != nil {
+		return err
+	}
 
 	// Controlla se la directory esiste
 	if _, err := os.Stat(versionsDir); os.IsNotExist(err) {
 		utils.PrintError("No JDK installations found")
 		utils.PrintInfo("The versions directory doesn't exist yet")
-		return
+		return utils.ExitWith(utils.ExitNotFound, errors.New("no JDK installations found"))
 	}
 
 	// Trova la versione JDK da rimuovere
@@ -78,7 +84,7 @@ func RemoveJDK() {
 	if err != nil {
 		utils.PrintError(fmt.Sprintf("JDK version %s not found: %v", version, err))
 		utils.PrintInfo("Run 'jenvy list' to see installed JDKs")
-		return
+		return utils.ExitWith(utils.ExitNotFound, err)
 	}
 
 	// Verifica se la versione è attualmente in uso
@@ -87,26 +93,18 @@ func RemoveJDK() {
 		utils.PrintInfo("Consider switching to another version before removal:")
 		utils.PrintInfo("  jenvy use <other-version>")
 		utils.PrintInfo("Continuing will unset JAVA_HOME and may affect running Java applications")
-		fmt.Print("\nDo you want to continue anyway? [y/N]: ")
-
-		var response string
-		fmt.Scanln(&response)
-		if strings.ToLower(strings.TrimSpace(response)) != "y" {
+		if !askConfirmation("\nDo you want to continue anyway? [y/N]: ") {
 			utils.PrintInfo("Removal cancelled")
-			return
+			return utils.ExitWith(utils.ExitGeneric, errors.New("removal cancelled by user"))
 		}
 	}
 
 	// Conferma rimozione
 	fmt.Printf("Are you sure you want to remove JDK %s?\n", version)
 	fmt.Printf("   Path: %s\n", jdkPath)
-	fmt.Print("   This action cannot be undone. [y/N]: ")
-
-	var response string
-	fmt.Scanln(&response)
-	if strings.ToLower(strings.TrimSpace(response)) != "y" {
+	if !askConfirmation("   This action cannot be undone. [y/N]: ") {
 		utils.PrintInfo("Removal cancelled")
-		return
+		return utils.ExitWith(utils.ExitGeneric, errors.New("removal cancelled by user"))
 	}
 
 	// Rimuovi la directory JDK
@@ -115,7 +113,10 @@ func RemoveJDK() {
 	if err != nil {
 		utils.PrintError(fmt.Sprintf("Failed to remove JDK: %v", err))
 		utils.PrintInfo("Make sure no applications are using this JDK")
-		return
+		if os.IsPermission(err) {
+			return utils.ExitWith(utils.ExitPermission, err)
+		}
+		return utils.ExitWith(utils.ExitGeneric, err)
 	}
 
 	utils.PrintSuccess(fmt.Sprintf("JDK %s removed successfully", version))
@@ -124,6 +125,7 @@ func RemoveJDK() {
 	fmt.Println()
 	utils.PrintInfo("Remaining JDK installations:")
 	showRemainingJDKs(versionsDir)
+	return nil
 }
 
 // findJDKForRemoval localizza e valida il percorso del JDK da rimuovere nel filesystem Windows.
@@ -166,7 +168,9 @@ func findJDKForRemoval(versionsDir, targetVersion string) (string, error) {
 		return "", fmt.Errorf("failed to read versions directory: %w", err)
 	}
 
-	// Cerca corrispondenza esatta prima
+	// Cerca corrispondenza esatta prima (nome directory o versione estratta).
+	// Con naming scheme per provider la stessa versione può comparire più volte.
+	var exactMatches []string
 	for _, entry := range entries {
 		if !entry.IsDir() {
 			continue
@@ -176,12 +180,23 @@ func findJDKForRemoval(versionsDir, targetVersion string) (string, error) {
 
 		// Estrai la versione dal nome della directory
 		if extractedVersion := extractVersionFromDirName(dirName); extractedVersion != "" {
-			if extractedVersion == targetVersion {
-				return filepath.Join(versionsDir, dirName), nil
+			if dirName == targetVersion || extractedVersion == targetVersion {
+				exactMatches = append(exactMatches, dirName)
 			}
 		}
 	}
 
+	if len(exactMatches) == 1 {
+		return filepath.Join(versionsDir, exactMatches[0]), nil
+	}
+	if len(exactMatches) > 1 {
+		utils.PrintError(fmt.Sprintf("Multiple JDK installations found for version '%s':", targetVersion))
+		for _, match := range exactMatches {
+			fmt.Printf("  - %s\n", match)
+		}
+		return "", fmt.Errorf("please specify the directory name, e.g. 'jenvy remove %s'", exactMatches[0])
+	}
+
 	// Se non trova corrispondenza esatta, cerca corrispondenza parziale
 	var matches []string
 	for _, entry := range entries {
@@ -228,7 +243,7 @@ func findJDKForRemoval(versionsDir, targetVersion string) (string, error) {
 // - Formato generico: "jdk-<version>", "JDK-<version>"
 //
 // **Algoritmo di normalizzazione:**
-// 1. Rimozione prefissi provider-specifici (adoptium-, azul-, liberica-, private-)
+// 1. Rimozione prefissi provider-specifici (adoptium-, azul-, liberica-, graalvm-, private-)
 // 2. Rimozione prefissi standard (jdk-, JDK-)
 // 3. Preservazione formato versione completo (major.minor.patch+build)
 // 4. Gestione case-insensitive per compatibilità Windows
@@ -257,10 +272,14 @@ func extractVersionFromDirName(dirName string) string {
 	// jdk-11.0.21+9
 	// adoptium-jdk-21.0.1+12
 	// azul-jdk-8.0.392+8
+	// ...e lo schema personalizzato configurato con 'jenvy config set naming-scheme'
+	if version, _, ok := utils.ParseInstallDirName(dirName, utils.InstallNamingScheme()); ok {
+		return version
+	}
 
 	// Rimuovi prefissi comuni
 	cleaned := dirName
-	prefixes := []string{"adoptium-", "azul-", "liberica-", "private-"}
+	prefixes := []string{"adoptium-", "azul-", "liberica-", "graalvm-", "private-"}
 	for _, prefix := range prefixes {
 		if strings.HasPrefix(cleaned, prefix) {
 			cleaned = strings.TrimPrefix(cleaned, prefix)
@@ -309,7 +328,7 @@ func extractVersionFromDirName(dirName string) string {
 //
 // Ritorna true se il JDK è attualmente configurato come JAVA_HOME attivo.
 func isJDKCurrentlyInUse(jdkPath string) bool {
-	javaHome := os.Getenv("JAVA_HOME")
+	javaHome := utils.ResolveJavaHome(os.Getenv("JAVA_HOME"))
 	if javaHome == "" {
 		return false
 	}
@@ -353,12 +372,11 @@ func isJDKCurrentlyInUse(jdkPath string) bool {
 // La funzione è progettata per essere chiamata automaticamente quando
 // l'utente invoca il comando remove senza parametri specifici.
 func showAvailableJDKsForRemoval() {
-	homeDir, err := os.UserHomeDir()
+	versionsDir, err := utils.GetJenvyVersionsDirectory()
 	if err != nil {
 		return
 	}
 
-	versionsDir := filepath.Join(homeDir, ".jenvy", "versions")
 	if _, err := os.Stat(versionsDir); os.IsNotExist(err) {
 		utils.PrintInfo("No JDK installations found")
 		return
@@ -482,28 +500,24 @@ func showRemainingJDKs(versionsDir string) {
 // - Logging operazione per audit trail
 //
 // Questa è un'operazione irreversibile che richiede particolare attenzione.
-func removeAllJDKs() {
-	// Ottieni directory home dell'utente
-	homeDir, err := os.UserHomeDir()
+func removeAllJDKs() error {
+	// Ottieni directory delle versioni installate
+	versionsDir, err := utils.GetJenvyVersionsDirectory()
 	if err != nil {
-		utils.PrintError(fmt.Sprintf("Error getting home directory: %v", err))
-		return
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Error getting home directory: %v", err))
 	}
 
-	versionsDir := filepath.Join(homeDir, ".jenvy", "versions")
-
 	// Controlla se la directory esiste
 	if _, err := os.Stat(versionsDir); os.IsNotExist(err) {
 		utils.PrintError("No JDK installations found")
 		utils.PrintInfo("The versions directory doesn't exist yet")
-		return
+		return utils.ExitWith(utils.ExitNotFound, errors.New("no JDK installations found"))
 	}
 
 	// Leggi tutte le installazioni
 	entries, err := os.ReadDir(versionsDir)
 	if err != nil {
-		utils.PrintError(fmt.Sprintf("Failed to read versions directory: %v", err))
-		return
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to read versions directory: %v", err))
 	}
 
 	// Filtra solo le directory
@@ -521,7 +535,7 @@ func removeAllJDKs() {
 
 	if len(jdkDirs) == 0 {
 		utils.PrintInfo("No JDK installations found to remove")
-		return
+		return nil
 	}
 
 	// Mostra quello che verrà rimosso
@@ -532,7 +546,7 @@ func removeAllJDKs() {
 	fmt.Printf("\n   Directory: %s\n", versionsDir)
 
 	// Controlla se qualche JDK è attualmente in uso
-	currentJavaHome := os.Getenv("JAVA_HOME")
+	currentJavaHome := utils.ResolveJavaHome(os.Getenv("JAVA_HOME"))
 	if currentJavaHome != "" {
 		normalizedJavaHome := strings.ToLower(filepath.Clean(currentJavaHome))
 		normalizedVersionsDir := strings.ToLower(filepath.Clean(versionsDir))
@@ -548,10 +562,15 @@ func removeAllJDKs() {
 	fmt.Print("\n   Are you absolutely sure? Type 'yes' to confirm: ")
 
 	var response string
-	fmt.Scanln(&response)
+	if utils.AssumeYes() {
+		fmt.Println("yes (--yes)")
+		response = "yes"
+	} else {
+		fmt.Scanln(&response)
+	}
 	if strings.ToLower(strings.TrimSpace(response)) != "yes" {
 		utils.PrintInfo("Removal cancelled")
-		return
+		return utils.ExitWith(utils.ExitGeneric, errors.New("removal cancelled by user"))
 	}
 
 	// Procedi con la rimozione
@@ -585,14 +604,14 @@ func removeAllJDKs() {
 			fmt.Printf("   - %s\n", version)
 		}
 		utils.PrintInfo("Make sure no applications are using these JDKs")
+		return utils.ExitWith(utils.ExitGeneric, fmt.Errorf("failed to remove %d JDK(s)", len(failedRemovals)))
 	}
 
-	// Se tutti i JDK sono stati rimossi con successo, rimuovi anche la directory versions se vuota
-	if len(failedRemovals) == 0 {
-		if entries, err := os.ReadDir(versionsDir); err == nil && len(entries) == 0 {
-			os.Remove(versionsDir) // Rimuovi la directory vuota (ignora errori)
-		}
-		utils.PrintInfo("All JDK installations have been removed")
-		utils.PrintInfo("Run 'jenvy download <version>' to install a new JDK")
+	// Tutti i JDK sono stati rimossi con successo: rimuovi anche la directory versions se vuota
+	if entries, err := os.ReadDir(versionsDir); err == nil && len(entries) == 0 {
+		os.Remove(versionsDir) // Rimuovi la directory vuota (ignora errori)
 	}
+	utils.PrintInfo("All JDK installations have been removed")
+	utils.PrintInfo("Run 'jenvy download <version>' to install a new JDK")
+	return nil
 }
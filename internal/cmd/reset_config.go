@@ -46,30 +46,29 @@ import (
 //
 // La funzione garantisce operazione sicura anche in presenza di file inesistenti
 // o problemi di accesso, fornendo feedback appropriato all'utente.
-func ResetPrivateConfig() {
-	homeDir, err := os.UserHomeDir()
+func ResetPrivateConfig() error {
+	jenvyDir, err := utils.JenvyHome()
 	if err != nil {
 		utils.PrintError(fmt.Sprintf("Error accessing user directory: %v", err))
 		utils.PrintInfo("Unable to locate Windows user profile directory")
 		utils.PrintInfo("Ensure proper user permissions and try again")
-		return
+		return utils.ExitWith(utils.ExitGeneric, err)
 	}
 
-	configPath := filepath.Join(homeDir, ".jenvy", "config.json")
-	jenvyDir := filepath.Join(homeDir, ".jenvy")
+	configPath := filepath.Join(jenvyDir, "config.json")
 
 	// Verifica esistenza directory .jenvy
 	if _, err := os.Stat(jenvyDir); os.IsNotExist(err) {
 		utils.PrintInfo("Jenvy configuration directory not found")
 		utils.PrintInfo("No private repository configuration exists to reset")
-		return
+		return nil
 	}
 
 	// Verifica esistenza file prima della rimozione
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		utils.PrintInfo("Private repository configuration not found")
 		utils.PrintInfo("No configuration file exists to reset")
-		return
+		return nil
 	}
 
 	// Rimuovi il file di configurazione
@@ -81,10 +80,18 @@ func ResetPrivateConfig() {
 		utils.PrintInfo("  - Insufficient Windows permissions")
 		utils.PrintInfo("  - File is read-only or protected")
 		utils.PrintInfo("Try closing applications and running as administrator")
-		return
+		if os.IsPermission(err) {
+			return utils.ExitWith(utils.ExitPermission, err)
+		}
+		return utils.ExitWith(utils.ExitGeneric, err)
+	}
+
+	if err := utils.DeleteStoredSecrets(); err != nil {
+		utils.PrintWarning(fmt.Sprintf("Unable to remove stored tokens from Windows Credential Manager: %v", err))
 	}
 
 	utils.PrintSuccess("Private repository configuration reset successfully")
 	utils.PrintInfo("All private repository settings have been cleared")
 	utils.PrintInfo("Use 'jenvy configure private <URL>' to set up new repository")
+	return nil
 }
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"jenvy/internal/utils"
+)
+
+// javaResolution è la catena che determina quale java viene eseguito, anello per anello.
+//
+// Riunisce lo stato letto da 'jenvy current' (scope), dal file di progetto e da
+// 'jenvy diagnose-path' (PATH), così che 'jenvy resolve' possa spiegare in un
+// solo punto perché un certo java è quello attivo.
+type javaResolution struct {
+	ProjectFile     string `json:"project_file,omitempty"`       // .jenvy-version trovato risalendo dalla directory corrente
+	ProjectVersion  string `json:"project_version,omitempty"`    // Valore del file, così come scritto
+	ExpandedVersion string `json:"expanded_version,omitempty"`   // Valore normalizzato (es. "1.8" -> "8", "jdk-21" -> "21")
+	ProjectInstall  string `json:"project_install,omitempty"`    // JDK installato a cui il valore corrisponde
+	UserJavaHome    string `json:"user_java_home,omitempty"`     // HKCU\Environment
+	SystemJavaHome  string `json:"system_java_home,omitempty"`   // HKLM\...\Environment
+	Scope           string `json:"scope,omitempty"`              // Scope effettivo (project > user > system)
+	IntendedHome    string `json:"intended_java_home,omitempty"` // JDK che jenvy considera attivo
+	ProcessJavaHome string `json:"process_java_home,omitempty"`  // JAVA_HOME del terminale corrente
+	PathJava        string `json:"path_java,omitempty"`          // Risultato di exec.LookPath("java")
+	Mismatch        string `json:"mismatch,omitempty"`           // Perché PathJava non è il JDK previsto (vuoto = coerente)
+}
+
+// resolveEffectiveJava raccoglie tutti gli anelli della risoluzione del java effettivo.
+//
+// Non modifica nulla: legge il file di progetto, il registro (scope user e
+// system), l'ambiente del processo e il PATH.
+func resolveEffectiveJava() javaResolution {
+	var res javaResolution
+	var values []utils.ScopeValue
+
+	if cwd, err := os.Getwd(); err == nil {
+		if project, ok := utils.FindProjectVersion(cwd); ok {
+			res.ProjectFile, res.ProjectVersion = project.Source, project.Value
+			res.ExpandedVersion = project.Value
+			if normalized, err := utils.NormalizeVersionInput(project.Value); err == nil {
+				res.ExpandedVersion = normalized
+			}
+			if installDir, err := utils.FindSingleJDKInstallation(res.ExpandedVersion); err == nil {
+				if home, ok := utils.ResolveJDKHome(installDir); ok {
+					res.ProjectInstall = home
+				}
+			}
+			values = append(values, utils.ScopeValue{Scope: utils.ScopeProject, Value: project.Value, Source: project.Source})
+		}
+	}
+	if userHome, ok := readUserEnvironmentVariable("JAVA_HOME"); ok {
+		res.UserJavaHome = utils.ResolveJavaHome(userHome)
+		values = append(values, utils.ScopeValue{Scope: utils.ScopeUser, Value: res.UserJavaHome})
+	}
+	if systemHome, ok := readSystemEnvironmentVariable("JAVA_HOME"); ok {
+		res.SystemJavaHome = utils.ResolveJavaHome(systemHome)
+		values = append(values, utils.ScopeValue{Scope: utils.ScopeSystem, Value: res.SystemJavaHome})
+	}
+
+	if effective, ok := utils.EffectiveScope(values...); ok {
+		res.Scope = effective.Scope
+		res.IntendedHome = effective.Value
+		if effective.Scope == utils.ScopeProject {
+			res.IntendedHome = res.ProjectInstall
+		}
+	}
+
+	res.ProcessJavaHome = os.Getenv("JAVA_HOME")
+	if javaPath, err := exec.LookPath("java"); err == nil {
+		if abs, err := filepath.Abs(javaPath); err == nil {
+			javaPath = abs
+		}
+		res.PathJava = javaPath
+	}
+
+	switch {
+	case res.Scope == utils.ScopeProject && res.ProjectInstall == "":
+		res.Mismatch = fmt.Sprintf("JDK %s pinned by %s is not installed", res.ProjectVersion, res.ProjectFile)
+	case res.IntendedHome == "":
+		res.Mismatch = "no JDK is active in any scope"
+	case res.PathJava == "":
+		res.Mismatch = "'java' is not on PATH"
+	case !samePath(filepath.Dir(res.PathJava), filepath.Join(res.IntendedHome, "bin")):
+		res.Mismatch = fmt.Sprintf("'java' resolves to %s, not to %s", res.PathJava, res.IntendedHome)
+	}
+	return res
+}
+
+// ResolveJava gestisce 'jenvy resolve': spiega perché un certo java è quello attivo.
+//
+// Mostra nell'ordine in cui vengono applicati:
+//  1. **Project**: File .jenvy-version trovato e valore che contiene
+//  2. **Expands to**: Valore normalizzato (jenvy non ha alias: "1.8" -> "8", "jdk-21" -> "21")
+//  3. **Install**: JDK installato a cui il valore corrisponde
+//  4. **Scope**: Scope effettivo tra project, user e system (vedi utils.EffectiveScope)
+//  5. **Intended**: JDK che jenvy considera attivo
+//  6. **PATH java**: Eseguibile trovato da exec.LookPath("java")
+//
+// Se il java della shell non appartiene al JDK previsto lo segnala con la causa
+// più probabile. Con --json stampa la stessa catena come oggetto JSON per gli script.
+//
+// Esempio di utilizzo:
+//
+//	jenvy resolve
+//	jenvy resolve --json
+func ResolveJava() error {
+	asJSON := false
+	for _, arg := range os.Args[2:] {
+		if arg != "--json" {
+			utils.PrintUsage("Usage: jenvy resolve [--json]")
+			return utils.ExitWith(utils.ExitGeneric, nil)
+		}
+		asJSON = true
+	}
+
+	res := resolveEffectiveJava()
+	if asJSON {
+		out, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to encode resolution: %v", err))
+		}
+		fmt.Println(string(out))
+		if res.IntendedHome == "" {
+			return utils.ExitWith(utils.ExitNotFound, errors.New(res.Mismatch))
+		}
+		return nil
+	}
+
+	utils.PrintSection("[RESOLVE] EFFECTIVE JAVA")
+	project := "-"
+	if res.ProjectFile != "" {
+		project = fmt.Sprintf("%s (%s)", res.ProjectVersion, res.ProjectFile)
+	}
+	install := "-"
+	switch {
+	case res.ProjectInstall != "":
+		install = res.ProjectInstall
+	case res.ProjectFile != "":
+		install = utils.ColorText("not installed", utils.Yellow)
+	}
+	printResolveStep("1. Project", project)
+	printResolveStep("2. Expands to", valueOrDash(res.ExpandedVersion))
+	printResolveStep("3. Install", install)
+	printResolveStep("4. Scope", valueOrDash(res.Scope))
+	printResolveStep("     user", valueOrDash(res.UserJavaHome))
+	printResolveStep("     system", valueOrDash(res.SystemJavaHome))
+	printResolveStep("5. Intended", valueOrDash(res.IntendedHome))
+	printResolveStep("6. PATH java", valueOrDash(res.PathJava))
+	fmt.Println()
+
+	if res.IntendedHome == "" {
+		utils.PrintWarning(res.Mismatch)
+		if res.ProjectFile != "" {
+			utils.PrintInfo(fmt.Sprintf("Run 'jenvy download %s' to install it", res.ExpandedVersion))
+		} else {
+			utils.PrintInfo("Activate one with 'jenvy use <version>' (system), '--user' or '--local' (project)")
+		}
+		return utils.ExitWith(utils.ExitNotFound, errors.New(res.Mismatch))
+	}
+
+	if res.Mismatch != "" {
+		utils.PrintWarning(res.Mismatch)
+		switch {
+		case res.Scope == utils.ScopeProject:
+			utils.PrintInfo("The project pin is applied by running 'jenvy use' in this directory")
+		case res.ProcessJavaHome != "" && !samePath(utils.ResolveJavaHome(res.ProcessJavaHome), res.IntendedHome):
+			utils.PrintInfo(fmt.Sprintf("This terminal still uses JAVA_HOME=%s: open a new terminal", res.ProcessJavaHome))
+		default:
+			utils.PrintInfo("Run 'jenvy diagnose-path' to see which PATH entry shadows it")
+		}
+		return nil
+	}
+	utils.PrintSuccess(fmt.Sprintf("'java' runs the %s JDK at %s", res.Scope, res.IntendedHome))
+	return nil
+}
+
+// printResolveStep stampa un anello della catena di 'jenvy resolve' in colonne allineate.
+func printResolveStep(label, value string) {
+	fmt.Printf("  %-13s %s\n", label, value)
+}
+
+// valueOrDash restituisce "-" per i valori vuoti, come nelle tabelle di 'jenvy current'.
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"jenvy/internal/utils"
+)
+
+// installationScan contiene il risultato della scansione di una directory di versione.
+type installationScan struct {
+	Name        string // Nome directory (es. "JDK-17.0.9")
+	Path        string // Percorso assoluto della directory
+	ArchivePath string // Archivio non ancora estratto, vuoto se assente
+	ArchiveSize int64  // Dimensione dell'archivio in byte
+	JDKHome     string // Root JDK risolta, vuota se non è un JDK valido
+
+	// Details è valorizzato solo se richiesto (calcolo dimensioni costoso)
+	Details *JDKInstallation
+}
+
+// scanInstallations analizza in parallelo tutte le directory in ~/.jenvy/versions.
+//
+// È l'implementazione condivisa da 'jenvy extract' (senza argomenti) e 'jenvy list':
+// per ogni directory rileva l'eventuale archivio da estrarre e la root JDK, e, se
+// withDetails è true, calcola anche dimensione, data e stato con
+// analyzeJDKInstallation. Le directory vengono elaborate da un pool limitato di
+// worker (utils.ForEachParallel) e i risultati mantengono l'ordine di os.ReadDir,
+// così l'output resta deterministico. In coda vengono aggiunte le installazioni
+// estratte altrove con 'download --extract-to' (utils.ExternalInstallDirs).
+//
+// Parametri:
+//
+//	versionsDir string - Directory delle versioni Jenvy
+//	withDetails bool   - true per includere JDKInstallation (walk ricorsivo)
+//
+// Restituisce:
+//
+//	[]installationScan - Un elemento per ogni sottodirectory, in ordine alfabetico, più le esterne
+//	error              - Errore di lettura della directory versioni (assente è ammessa se ci sono esterne)
+func scanInstallations(versionsDir string, withDetails bool) ([]installationScan, error) {
+	externals := utils.ExternalInstallDirs()
+	entries, err := os.ReadDir(versionsDir)
+	if err != nil && (!os.IsNotExist(err) || len(externals) == 0) {
+		return nil, err
+	}
+
+	var dirs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			dirs = append(dirs, filepath.Join(versionsDir, entry.Name()))
+		}
+	}
+	dirs = append(dirs, externals...)
+
+	results := make([]installationScan, len(dirs))
+	utils.ForEachParallel(len(dirs), 0, func(i int) {
+		scan := installationScan{
+			Name: filepath.Base(dirs[i]),
+			Path: dirs[i],
+		}
+
+		if archive, err := findArchiveInDirectory(scan.Path); err == nil {
+			scan.ArchivePath = archive
+			if info, err := os.Stat(archive); err == nil {
+				scan.ArchiveSize = info.Size()
+			}
+		}
+		if home, ok := utils.ResolveJDKHome(scan.Path); ok {
+			scan.JDKHome = home
+		}
+		if withDetails {
+			installation := analyzeJDKInstallation(scan.Name, scan.Path)
+			scan.Details = &installation
+		}
+
+		results[i] = scan
+	})
+
+	return results, nil
+}
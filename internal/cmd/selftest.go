@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"jenvy/internal/providers/adoptium"
+	"jenvy/internal/utils"
+)
+
+// selfTestJavaTimeout limita l'esecuzione di 'java -version' durante il self-test.
+const selfTestJavaTimeout = 30 * time.Second
+
+// selfTestStage è una fase del self-test con il codice di uscita da usare se fallisce.
+type selfTestStage struct {
+	Name string
+	Code int
+	Run  func() (string, error) // Restituisce il dettaglio da mostrare accanto all'esito
+}
+
+// SelfTest verifica un'installazione di jenvy eseguendo l'intera pipeline su una JRE usa e getta.
+//
+// Pensato come controllo di accettazione dopo l'installazione o da allegare a una
+// segnalazione di bug. Tutto avviene in una directory temporanea indicata come
+// JENVY_HOME, quindi ~/.jenvy, la configurazione e il registro non vengono toccati.
+// Nella directory temporanea viene copiata config.json, così proxy, mirror, token
+// e certificati configurati valgono anche per il self-test:
+//  1. **Resolve**: Cerca la JRE più leggera tra le LTS Adoptium per il sistema e l'architettura in uso
+//  2. **Download**: Scarica l'archivio e ne verifica lo SHA-256
+//  3. **Extract**: Estrae l'archivio e controlla che contenga un runtime valido
+//  4. **Run**: Esegue 'java -version' e verifica che riporti la versione scaricata
+//  5. **Cleanup**: Rimuove la directory temporanea
+//
+// Le fasi successive a un errore vengono segnalate come SKIP; la pulizia avviene
+// sempre, anche con Ctrl+C. Il codice di uscita è quello della prima fase fallita
+// (rete, estrazione), o utils.ExitInterrupted se il self-test è stato interrotto.
+//
+// Esempio di utilizzo:
+//
+//	jenvy self-test
+func SelfTest() error {
+	tempHome, err := os.MkdirTemp("", "jenvy-self-test-")
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to create temporary directory: %v", err))
+	}
+	config, err := os.ReadFile(utils.ConfigPath())
+	if err == nil {
+		err = os.WriteFile(filepath.Join(tempHome, "config.json"), config, 0644)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		utils.PrintWarning(fmt.Sprintf("Failed to copy the configuration, running with defaults: %v", err))
+	}
+	previousHome, hadHome := os.LookupEnv(utils.JenvyHomeEnv)
+	os.Setenv(utils.JenvyHomeEnv, tempHome)
+	defer func() {
+		if hadHome {
+			os.Setenv(utils.JenvyHomeEnv, previousHome)
+		} else {
+			os.Unsetenv(utils.JenvyHomeEnv)
+		}
+	}()
+
+	// Ctrl-C interrompe la fase in corso invece di terminare il processo, così la directory temporanea viene rimossa
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	progress, _ := utils.ResolveProgressMode(utils.ProgressAuto)
+	var asset *adoptium.JREAsset
+	var archivePath, javaHome string
+
+	stages := []selfTestStage{
+		{"Resolve", utils.ExitNetwork, func() (string, error) {
+			found, err := adoptium.GetSmallestJRE(utils.LTSMajors)
+			if err != nil {
+				return "", err
+			}
+			asset = found
+			return fmt.Sprintf("Adoptium JRE %s (%.1f MB)", asset.Version.OpenJDKVersion, float64(asset.Binary.Package.Size)/1024/1024), nil
+		}},
+		{"Download", utils.ExitNetwork, func() (string, error) {
+			versionsDir, err := utils.GetJenvyVersionsDirectory()
+			if err != nil {
+				return "", err
+			}
+			installDir := filepath.Join(versionsDir, utils.FormatInstallDirName(utils.InstallNamingScheme(), "adoptium", asset.Version.OpenJDKVersion))
+			if err := os.MkdirAll(installDir, 0755); err != nil {
+				return "", err
+			}
+			archivePath = filepath.Join(installDir, asset.Binary.Package.Name)
+
+			start := time.Now()
+			if err := downloadFile(ctx, asset.Binary.Package.Link, archivePath, progress, nil, false); err != nil {
+				return "", err
+			}
+			release := downloadRelease{Checksum: asset.Binary.Package.Checksum, ChecksumAlgorithm: "sha256"}
+			if err := verifyDownloadedArchive(archivePath, release, ""); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%s in %s, checksum verified", asset.Binary.Package.Name, time.Since(start).Round(time.Millisecond)), nil
+		}},
+		{"Extract", utils.ExitExtraction, func() (string, error) {
+			installDir := filepath.Dir(archivePath)
+			if err := extractArchive(archivePath, installDir, true, progress); err != nil {
+				return "", err
+			}
+			home, ok := utils.ResolveJDKHome(installDir)
+			if !ok {
+				return "", fmt.Errorf("extracted archive does not contain %s and lib", filepath.Join("bin", utils.JavaExecutableName()))
+			}
+			javaHome = home
+			return home, nil
+		}},
+		{"Run", utils.ExitExtraction, func() (string, error) {
+			runCtx, cancel := context.WithTimeout(ctx, selfTestJavaTimeout)
+			defer cancel()
+			reported, line, err := utils.RunJavaVersion(runCtx, javaHome)
+			if err != nil {
+				return "", fmt.Errorf("java -version failed: %v", err)
+			}
+			if expected := asset.Version.OpenJDKVersion; !utils.JavaVersionMatches(reported, expected) {
+				return "", fmt.Errorf("unexpected output %q (expected version %s)", line, expected)
+			}
+			return line, nil
+		}},
+	}
+
+	utils.PrintSection("[SELF-TEST] DOWNLOAD -> EXTRACT -> RUN")
+	utils.PrintInfo(fmt.Sprintf("Working in temporary %s: %s", utils.JenvyHomeEnv, tempHome))
+	fmt.Println()
+
+	var failure error
+	for _, stage := range stages {
+		if failure == nil && ctx.Err() != nil {
+			failure = utils.ExitWith(utils.ExitInterrupted, errors.New("self-test interrupted"))
+		}
+		if failure != nil {
+			printSelfTestResult(stage.Name, "SKIP", utils.Yellow, "")
+			continue
+		}
+		detail, err := stage.Run()
+		if err != nil {
+			printSelfTestResult(stage.Name, "FAIL", utils.Red, err.Error())
+			code := stage.Code
+			if ctx.Err() != nil {
+				code = utils.ExitInterrupted
+			}
+			failure = utils.ExitWith(code, fmt.Errorf("self-test %s failed: %w", strings.ToLower(stage.Name), err))
+			continue
+		}
+		printSelfTestResult(stage.Name, "PASS", utils.Green, detail)
+	}
+
+	if err := os.RemoveAll(tempHome); err != nil {
+		printSelfTestResult("Cleanup", "FAIL", utils.Red, err.Error())
+		utils.PrintInfo(fmt.Sprintf("Remove %s manually", tempHome))
+	} else {
+		printSelfTestResult("Cleanup", "PASS", utils.Green, "temporary directory removed")
+	}
+	fmt.Println()
+
+	if failure != nil {
+		utils.PrintError("Self-test failed")
+		return failure
+	}
+	utils.PrintSuccess("Self-test passed: download, extraction and execution work")
+	return nil
+}
+
+// printSelfTestResult stampa l'esito di una fase del self-test in colonne allineate.
+func printSelfTestResult(stage, status, color, detail string) {
+	fmt.Printf("  %-9s %s %s\n", stage, utils.ColorText(fmt.Sprintf("%-5s", status), color), detail)
+}
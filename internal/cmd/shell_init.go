@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"jenvy/internal/utils"
+)
+
+// ShellInit gestisce 'jenvy shell-init <shell>': stampa l'hook per il cambio automatico di JDK.
+//
+// L'hook va caricato dal profilo della shell. A ogni cambio di directory chiede
+// a jenvy (comando nascosto '__project-home') il JDK fissato da .jenvy-version o
+// .java-version e imposta JAVA_HOME e PATH solo per la sessione corrente, senza
+// toccare il registro. Uscendo dal progetto ripristina il JAVA_HOME precedente.
+// È l'equivalente degli hook di sdkman/jabba; cmd.exe non ha un evento di
+// cambio directory e non è supportato.
+//
+// Shell supportate:
+//   - **bash**: Linux, macOS e Git Bash/MSYS2, tramite PROMPT_COMMAND
+//   - **zsh**: Shell predefinita di macOS, tramite l'hook chpwd
+//   - **powershell** (o pwsh): Windows PowerShell e PowerShell 7, tramite la funzione prompt
+//
+// Esempio di utilizzo:
+//
+//	eval "$(jenvy shell-init bash)"                            # in ~/.bashrc
+//	eval "$(jenvy shell-init zsh)"                             # in ~/.zshrc
+//	jenvy shell-init powershell | Out-String | Invoke-Expression # in $PROFILE
+func ShellInit() error {
+	args, _ := utils.SplitArgs(os.Args[2:])
+	if len(args) != 1 {
+		printShellInitUsage()
+		return utils.ExitWith(utils.ExitGeneric, nil)
+	}
+	switch strings.ToLower(args[0]) {
+	case "bash":
+		fmt.Print(generateBashShellHook())
+	case "zsh":
+		fmt.Print(generateZshShellHook())
+	case "powershell", "pwsh":
+		fmt.Print(generatePowerShellShellHook())
+	case "cmd":
+		utils.PrintError("cmd.exe cannot run a hook on directory change")
+		utils.PrintInfo("Use 'jenvy use' in the project directory, or switch to PowerShell or Git Bash")
+		return utils.ExitWith(utils.ExitGeneric, nil)
+	default:
+		printShellInitUsage()
+		return utils.ExitWith(utils.ExitGeneric, nil)
+	}
+	return nil
+}
+
+// printShellInitUsage mostra la sintassi di 'jenvy shell-init' e come caricare l'hook.
+func printShellInitUsage() {
+	utils.PrintUsage("Usage: jenvy shell-init bash|zsh|powershell")
+	utils.PrintInfo("Bash (~/.bashrc):           eval \"$(jenvy shell-init bash)\"")
+	utils.PrintInfo("Zsh (~/.zshrc):             eval \"$(jenvy shell-init zsh)\"")
+	utils.PrintInfo("PowerShell ($PROFILE):      jenvy shell-init powershell | Out-String | Invoke-Expression")
+}
+
+// PrintProjectHome stampa la root del JDK fissato per la directory corrente.
+//
+// Comando nascosto '__project-home' usato dagli hook di 'jenvy shell-init'. Non
+// stampa nulla se nessun file di progetto viene trovato, così l'hook ripristina
+// il JAVA_HOME della sessione. Se la versione fissata non è installata lo
+// segnala su stderr, senza interrompere il prompt.
+func PrintProjectHome() {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	project, ok := utils.FindProjectVersion(cwd)
+	if !ok {
+		return
+	}
+	version := project.Value
+	if normalized, err := utils.NormalizeVersionInput(version); err == nil {
+		version = normalized
+	}
+	if installDir, err := utils.FindSingleJDKInstallation(version); err == nil {
+		if home, ok := utils.ResolveJDKHome(installDir); ok {
+			fmt.Println(home)
+			return
+		}
+	}
+	fmt.Fprintf(os.Stderr, "jenvy: JDK %s pinned by %s is not installed (run 'jenvy download %s')\n", project.Value, project.Source, version)
+}
+
+// generateBashShellHook restituisce l'hook di 'jenvy shell-init bash'.
+func generateBashShellHook() string {
+	return unixShellHookFunctions + `
+if [[ ";${PROMPT_COMMAND-};" != *";_jenvy_hook;"* ]]; then
+    PROMPT_COMMAND="_jenvy_hook${PROMPT_COMMAND:+;$PROMPT_COMMAND}"
+fi
+_jenvy_hook
+`
+}
+
+// generateZshShellHook restituisce l'hook di 'jenvy shell-init zsh'.
+//
+// zsh ha un evento di cambio directory (chpwd): l'hook non viene eseguito a ogni prompt.
+func generateZshShellHook() string {
+	return unixShellHookFunctions + `
+autoload -Uz add-zsh-hook
+add-zsh-hook chpwd _jenvy_hook
+_jenvy_hook
+`
+}
+
+// unixShellHookFunctions sono le funzioni comuni agli hook di bash e zsh.
+//
+// In Git Bash il PATH usa percorsi in stile Unix: la directory bin del JDK viene
+// convertita con cygpath, mentre JAVA_HOME resta un percorso Windows.
+const unixShellHookFunctions = `# jenvy shell-init: switch JAVA_HOME when entering a directory with .jenvy-version/.java-version
+_jenvy_remove_path() {
+    [[ -z "${_JENVY_PATH_ENTRY-}" ]] && return
+    PATH=":$PATH:"
+    PATH="${PATH//:$_JENVY_PATH_ENTRY:/:}"
+    PATH="${PATH#:}"
+    PATH="${PATH%:}"
+    unset _JENVY_PATH_ENTRY
+}
+
+_jenvy_hook() {
+    [[ "$PWD" == "${_JENVY_LAST_PWD-}" ]] && return
+    _JENVY_LAST_PWD="$PWD"
+    local home bin
+    home="$(jenvy __project-home)"
+    if [[ -z "$home" ]]; then
+        if [[ -n "${_JENVY_ACTIVE-}" ]]; then
+            _jenvy_remove_path
+            if [[ -n "$_JENVY_ORIGINAL_JAVA_HOME" ]]; then
+                export JAVA_HOME="$_JENVY_ORIGINAL_JAVA_HOME"
+            else
+                unset JAVA_HOME
+            fi
+            unset _JENVY_ACTIVE _JENVY_ORIGINAL_JAVA_HOME
+        fi
+        return
+    fi
+    [[ "$home" == "${JAVA_HOME-}" ]] && return
+    if [[ -z "${_JENVY_ACTIVE-}" ]]; then
+        _JENVY_ACTIVE=1
+        _JENVY_ORIGINAL_JAVA_HOME="${JAVA_HOME-}"
+    fi
+    _jenvy_remove_path
+    bin="$home/bin"
+    command -v cygpath >/dev/null 2>&1 && bin="$(cygpath -u "$bin")"
+    export JAVA_HOME="$home"
+    export PATH="$bin:$PATH"
+    _JENVY_PATH_ENTRY="$bin"
+}
+`
+
+// generatePowerShellShellHook restituisce l'hook di 'jenvy shell-init powershell'.
+//
+// La funzione prompt esistente viene conservata e richiamata dopo l'hook, così
+// i prompt personalizzati (oh-my-posh, starship) continuano a funzionare.
+func generatePowerShellShellHook() string {
+	return `# jenvy shell-init: switch JAVA_HOME when entering a directory with .jenvy-version/.java-version
+function global:Remove-JenvyPathEntry {
+    if (-not $global:JenvyPathEntry) { return }
+    $env:Path = (($env:Path -split ';') | Where-Object { $_ -ne $global:JenvyPathEntry }) -join ';'
+    $global:JenvyPathEntry = $null
+}
+
+function global:Invoke-JenvyHook {
+    if ($PWD.Provider.Name -ne 'FileSystem') { return }
+    if ($PWD.ProviderPath -eq $global:JenvyLastPwd) { return }
+    $global:JenvyLastPwd = $PWD.ProviderPath
+    $jdkHome = & jenvy __project-home | Select-Object -First 1
+    if (-not $jdkHome) {
+        if ($global:JenvyActive) {
+            Remove-JenvyPathEntry
+            if ($global:JenvyOriginalJavaHome) {
+                $env:JAVA_HOME = $global:JenvyOriginalJavaHome
+            } else {
+                Remove-Item Env:JAVA_HOME -ErrorAction SilentlyContinue
+            }
+            $global:JenvyActive = $false
+            $global:JenvyOriginalJavaHome = $null
+        }
+        return
+    }
+    if ($jdkHome -eq $env:JAVA_HOME) { return }
+    if (-not $global:JenvyActive) {
+        $global:JenvyActive = $true
+        $global:JenvyOriginalJavaHome = $env:JAVA_HOME
+    }
+    Remove-JenvyPathEntry
+    $env:JAVA_HOME = $jdkHome
+    $global:JenvyPathEntry = Join-Path $jdkHome 'bin'
+    $env:Path = "$global:JenvyPathEntry;$env:Path"
+}
+
+if (-not $global:JenvyOriginalPrompt) {
+    $global:JenvyOriginalPrompt = $function:prompt
+    function global:prompt {
+        Invoke-JenvyHook
+        & $global:JenvyOriginalPrompt
+    }
+}
+Invoke-JenvyHook
+`
+}
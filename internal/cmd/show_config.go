@@ -58,26 +58,47 @@ import (
 // - Audit configurazioni in ambienti multi-utente Windows
 // - Validazione setup prima di operazioni automatizzate
 //
+// **Output per script (--json):**
+// - Stampa su stdout la configurazione effettiva (default compresi) in formato JSON
+// - Include provider predefinito, directory delle installazioni e file di configurazione
+// - Le credenziali restano mascherate come nella vista testuale, salvo --reveal
+//
+//	jenvy config-show --json              # Verifica della configurazione in CI
+//	jenvy config-show --json --reveal     # Include i token in chiaro
+//
 // La funzione garantisce accesso sicuro alle informazioni di configurazione
 // senza esporre dati sensibili in plain text quando non necessario.
-func ShowCurrentConfig() {
-	homeDir, err := os.UserHomeDir()
+func ShowCurrentConfig() error {
+	asJSON, reveal := false, false
+	for _, arg := range os.Args[2:] {
+		switch arg {
+		case "--json":
+			asJSON = true
+		case "--reveal":
+			reveal = true
+		}
+	}
+
+	if asJSON {
+		return showConfigJSON(reveal)
+	}
+
+	jenvyDir, err := utils.JenvyHome()
 	if err != nil {
 		utils.PrintError(fmt.Sprintf("Unable to access user directory: %v", err))
 		utils.PrintInfo("Cannot locate Windows user profile directory")
 		utils.PrintInfo("Ensure proper user permissions and try again")
-		return
+		return utils.ExitWith(utils.ExitGeneric, err)
 	}
 
-	configPath := filepath.Join(homeDir, ".jenvy", "config.json")
-	jenvyDir := filepath.Join(homeDir, ".jenvy")
+	configPath := filepath.Join(jenvyDir, "config.json")
 
 	// Verifica esistenza directory .jenvy
 	if _, err := os.Stat(jenvyDir); os.IsNotExist(err) {
 		utils.PrintInfo("Jenvy configuration directory not found")
 		utils.PrintInfo("No private repository has been configured yet")
 		utils.PrintInfo("Use 'jenvy configure private <URL>' to set up a repository")
-		return
+		return nil
 	}
 
 	// Verifica esistenza file configurazione
@@ -85,7 +106,7 @@ func ShowCurrentConfig() {
 		utils.PrintInfo("Private repository configuration not found")
 		utils.PrintInfo("No configuration file exists")
 		utils.PrintInfo("Use 'jenvy configure private <URL>' to set up a repository")
-		return
+		return nil
 	}
 
 	// Apri e leggi il file di configurazione
@@ -97,19 +118,22 @@ func ShowCurrentConfig() {
 		utils.PrintInfo("  - Insufficient Windows permissions")
 		utils.PrintInfo("  - File corruption or access restrictions")
 		utils.PrintInfo("Try closing applications or running as administrator")
-		return
+		if os.IsPermission(err) {
+			return utils.ExitWith(utils.ExitPermission, err)
+		}
+		return utils.ExitWith(utils.ExitGeneric, err)
 	}
 	defer file.Close()
 
 	// Parse del contenuto JSON
-	var cfg map[string]string
+	var cfg map[string]interface{}
 	err = json.NewDecoder(file).Decode(&cfg)
 	if err != nil {
 		utils.PrintError(fmt.Sprintf("Configuration file parsing error: %v", err))
 		utils.PrintInfo("The configuration file appears to be corrupted")
 		utils.PrintInfo("Consider using 'jenvy reset-config' to reset configuration")
 		utils.PrintInfo("Then reconfigure with 'jenvy configure private <URL>'")
-		return
+		return utils.ExitWith(utils.ExitGeneric, err)
 	}
 
 	// Verifica che la configurazione non sia vuota
@@ -117,7 +141,7 @@ func ShowCurrentConfig() {
 		utils.PrintInfo("Configuration file is empty")
 		utils.PrintInfo("No private repository settings found")
 		utils.PrintInfo("Use 'jenvy configure private <URL>' to set up a repository")
-		return
+		return nil
 	}
 
 	// Visualizza la configurazione corrente
@@ -132,11 +156,19 @@ func ShowCurrentConfig() {
 	sort.Strings(keys)
 
 	for _, key := range keys {
-		value := cfg[key]
+		value := fmt.Sprint(cfg[key])
+		if cfg[key] == nil {
+			value = ""
+		}
+		if reveal && utils.IsCredentialReference(value) {
+			if secret, err := utils.ResolveCredentialReference(value); err == nil {
+				value = secret
+			}
+		}
 		displayValue := value
 		if value == "" {
 			displayValue = utils.ColorText("(empty)", utils.Yellow)
-		} else if key == "password" || key == "token" || key == "api_key" {
+		} else if utils.IsSecretConfigKey(key) && !reveal {
 			// Maschera valori sensibili per sicurezza
 			displayValue = utils.ColorText("(configured - hidden for security)", utils.Green)
 		} else {
@@ -150,4 +182,46 @@ func ShowCurrentConfig() {
 	fmt.Println()
 	utils.PrintInfo("Use 'jenvy reset-config' to clear configuration")
 	utils.PrintInfo("Use 'jenvy configure private <URL>' to update repository")
+	return nil
+}
+
+// effectiveConfig è il documento stampato da 'jenvy config-show --json'.
+type effectiveConfig struct {
+	ConfigFile      string                 `json:"config_file"`
+	DefaultProvider string                 `json:"default_provider"`
+	VersionsDir     string                 `json:"versions_dir"`
+	Settings        map[string]interface{} `json:"settings"`
+}
+
+// showConfigJSON stampa la configurazione effettiva in formato JSON.
+//
+// Un file di configurazione assente non è un errore: vengono riportati i default,
+// così che la CI possa verificare anche una macchina appena provisionata.
+func showConfigJSON(reveal bool) error {
+	cfg, err := utils.LoadConfigOrDefault()
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Configuration file parsing error: %v", err))
+	}
+
+	var raw map[string]interface{}
+	if data, err := os.ReadFile(utils.ConfigPath()); err == nil {
+		json.Unmarshal(data, &raw) // Già validato da LoadConfigOrDefault
+	}
+
+	versionsDir, err := utils.GetJenvyVersionsDirectory()
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to access Jenvy directory: %v", err))
+	}
+
+	out, err := json.MarshalIndent(effectiveConfig{
+		ConfigFile:      utils.ConfigPath(),
+		DefaultProvider: utils.DefaultProvider(),
+		VersionsDir:     versionsDir,
+		Settings:        utils.EffectiveConfigSettings(raw, cfg, reveal),
+	}, "", "  ")
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to encode configuration: %v", err))
+	}
+	fmt.Println(string(out))
+	return nil
 }
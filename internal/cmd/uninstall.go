@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"jenvy/internal/utils"
+)
+
+// UninstallJenvy rimuove le tracce lasciate da Jenvy nel sistema.
+//
+// Il comando 'jenvy uninstall' è il punto finale del ciclo di vita del tool e
+// annulla, previa conferma, le modifiche fatte da init, completion e use:
+//
+//  1. **Completamento shell**: Rimuove i blocchi jenvy da ~/.bashrc e dai profili PowerShell
+//  2. **Aiuto CMD**: Elimina ~/.jenvy_cmd_help.bat
+//  3. **Variabili di sistema** (opzionale): Rimuove JAVA_HOME se punta a un JDK Jenvy
+//     e %JAVA_HOME%\bin dal PATH di sistema (richiede privilegi amministratore)
+//  4. **Directory ~/.jenvy** (opzionale): Elimina configurazione e TUTTI i JDK installati,
+//     con conferma esplicita digitando 'yes'
+//
+// Al termine stampa l'elenco esatto degli elementi rimossi. L'eseguibile jenvy
+// stesso non viene cancellato (può essere in uso e la sua posizione dipende
+// dall'installer).
+func UninstallJenvy() error {
+	homeDir, err := utils.UserHomeDir()
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Error getting user directory: %v", err))
+	}
+
+	utils.PrintWarning("This will remove Jenvy shell completion and, optionally, its environment changes and data")
+	if !askConfirmation("Continue with uninstall? (y/N): ") {
+		utils.PrintInfo("Uninstall cancelled")
+		return utils.ExitWith(utils.ExitGeneric, errors.New("uninstall cancelled by user"))
+	}
+
+	// 1-2. Blocchi di completamento nei profili shell e file di aiuto CMD
+	removed, failed := uninstallCompletionFiles(homeDir)
+
+	// 3. JAVA_HOME e PATH di sistema, solo se impostati da Jenvy
+	jenvyDir := filepath.Join(homeDir, ".jenvy")
+	javaHome, javaHomeSet := readSystemEnvironmentVariable("JAVA_HOME")
+	if javaHomeSet && isPathInside(javaHome, jenvyDir) {
+		fmt.Printf("\nSystem JAVA_HOME points to a Jenvy JDK: %s\n", javaHome)
+		if askConfirmation("Unset JAVA_HOME and remove " + javaHomeBinEntry + " from system PATH? (y/N): ") {
+			if !isRunningAsAdmin() {
+				utils.PrintWarning("Administrator privileges required to modify system environment variables")
+				utils.PrintInfo("Run 'jenvy uninstall' as Administrator to clean JAVA_HOME and PATH")
+			} else {
+				if err := deleteSystemEnvironmentVariable("JAVA_HOME"); err != nil {
+					failed = append(failed, fmt.Sprintf("JAVA_HOME: %v", err))
+				} else {
+					removed = append(removed, "System JAVA_HOME ("+javaHome+")")
+				}
+				if ok, err := removeJavaHomeFromPath(); err != nil {
+					failed = append(failed, fmt.Sprintf("PATH: %v", err))
+				} else if ok {
+					removed = append(removed, javaHomeBinEntry+" from system PATH")
+				}
+			}
+		}
+	}
+
+	// 4. Directory ~/.jenvy con tutti i JDK
+	if _, err := os.Stat(jenvyDir); err == nil {
+		jdkCount := 0
+		if entries, err := os.ReadDir(filepath.Join(jenvyDir, "versions")); err == nil {
+			for _, entry := range entries {
+				if entry.IsDir() {
+					jdkCount++
+				}
+			}
+		}
+		fmt.Printf("\nWARNING: Removing %s deletes your configuration and ALL %d installed JDK(s)!\n", jenvyDir, jdkCount)
+		fmt.Print("   Type 'yes' to delete it, or press Enter to keep it: ")
+		var response string
+		if utils.AssumeYes() {
+			fmt.Println("(kept, non-interactive)")
+		} else {
+			fmt.Scanln(&response)
+		}
+		if strings.ToLower(strings.TrimSpace(response)) == "yes" {
+			if err := os.RemoveAll(jenvyDir); err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", jenvyDir, err))
+			} else {
+				removed = append(removed, fmt.Sprintf("%s (%d JDK(s))", jenvyDir, jdkCount))
+			}
+			if err := utils.DeleteStoredSecrets(); err != nil {
+				failed = append(failed, fmt.Sprintf("Windows Credential Manager: %v", err))
+			}
+		} else {
+			utils.PrintInfo(fmt.Sprintf("Kept %s", jenvyDir))
+		}
+	}
+
+	// Riepilogo
+	fmt.Println()
+	if len(removed) == 0 {
+		utils.PrintInfo("Nothing was removed")
+	} else {
+		utils.PrintSuccess("Removed:")
+		for _, item := range removed {
+			fmt.Printf("   - %s\n", item)
+		}
+	}
+	if len(failed) > 0 {
+		utils.PrintWarning("Could not remove:")
+		for _, item := range failed {
+			fmt.Printf("   - %s\n", item)
+		}
+	}
+	utils.PrintInfo("Restart your terminal to apply the changes")
+	if len(failed) > 0 {
+		return utils.ExitWith(utils.ExitGeneric, fmt.Errorf("%d item(s) could not be removed", len(failed)))
+	}
+	return nil
+}
+
+// askConfirmation stampa una domanda e restituisce true se l'utente risponde y/yes.
+//
+// In modalità non interattiva (--yes o JENVY_NONINTERACTIVE, vedi utils.AssumeYes)
+// non legge stdin e risponde sì, mostrando comunque la domanda nel log del terminale.
+func askConfirmation(prompt string) bool {
+	fmt.Print(prompt)
+	if utils.AssumeYes() {
+		fmt.Println("y (--yes)")
+		return true
+	}
+	var response string
+	fmt.Scanln(&response)
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
+
+// isPathInside verifica (case-insensitive, come NTFS) se path si trova dentro dir.
+func isPathInside(path, dir string) bool {
+	rel, err := filepath.Rel(strings.ToLower(filepath.Clean(dir)), strings.ToLower(filepath.Clean(path)))
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,550 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"jenvy/internal/utils"
+)
+
+// upgradeVariant è la variante di un'installazione (--type, --javafx, --arch), dai suoi metadati.
+//
+// Un aggiornamento resta nella stessa variante: una JRE x32 viene sostituita da
+// una JRE x32 e non dal JDK nativo della stessa major.
+type upgradeVariant struct {
+	ImageType string
+	JavaFX    bool
+	Arch      string
+}
+
+// installVariant legge la variante dai metadati; quelli scritti prima di --type e
+// --arch indicano un JDK senza JavaFX per l'architettura nativa.
+func installVariant(meta *utils.InstallMetadata) upgradeVariant {
+	variant := upgradeVariant{ImageType: meta.ImageType, JavaFX: meta.JavaFX, Arch: meta.Arch}
+	if variant.ImageType == "" {
+		variant.ImageType = utils.ImageTypeJDK
+	}
+	if variant.Arch == "" {
+		variant.Arch = utils.NativeArch()
+	}
+	return variant
+}
+
+// apply imposta la variante per le interrogazioni ai provider e i nomi delle directory.
+func (v upgradeVariant) apply() {
+	utils.SetImageVariant(v.ImageType, v.JavaFX)
+	utils.SetTargetArch(v.Arch)
+}
+
+// key identifica la variante nei raggruppamenti (es. "jre/fx/x32").
+func (v upgradeVariant) key() string {
+	return fmt.Sprintf("%s/%t/%s", v.ImageType, v.JavaFX, v.Arch)
+}
+
+// label descrive la variante nei messaggi; vuota per un JDK nativo senza JavaFX.
+func (v upgradeVariant) label() string {
+	var parts []string
+	if v.ImageType != utils.ImageTypeJDK {
+		parts = append(parts, strings.ToUpper(v.ImageType))
+	}
+	if v.JavaFX {
+		parts = append(parts, "JavaFX")
+	}
+	if v.Arch != utils.NativeArch() {
+		parts = append(parts, v.Arch)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+// majorUpgrade descrive l'aggiornamento di una major installata e il suo esito.
+type majorUpgrade struct {
+	Provider    string
+	Major       int
+	Variant     upgradeVariant
+	Installed   string // Versione installata più recente della major
+	InstallPath string // Root JDK della versione installata
+	Release     downloadRelease
+	NewPath     string             // Root JDK della nuova installazione, valorizzata dopo l'upgrade
+	Previous    []installationScan // Installazioni della major prima dell'upgrade, rimosse con --prune
+	Status      string             // upgraded | up to date | not offered | failed | cancelled
+	Err         error
+}
+
+// UpgradeJDKs gestisce 'jenvy update' (alias 'upgrade'): porta le major installate all'ultima patch.
+//
+// Comando di manutenzione che compone risoluzione delle release, download
+// concorrenti e riattivazione del JDK:
+//  1. **Inventario**: Per ogni provider, major e variante installati (dai metadati di
+//     'jenvy download': --type, --javafx, --arch) considera la versione più recente
+//  2. **Risoluzione**: Un'interrogazione per provider e variante (fetchReleaseFinder) trova l'ultima patch
+//  3. **Download concorrente**: Fino a --jobs aggiornamenti in parallelo, con una riga
+//     di avanzamento per major
+//  4. **Riepilogo**: Major aggiornate, già aggiornate, non offerte, fallite o annullate
+//  5. **Riattivazione**: Se la major del JDK attivo è stata aggiornata, JAVA_HOME viene
+//     ripuntato sulla nuova versione (saltabile con --no-reactivate)
+//  6. **Pulizia**: Con --prune le versioni precedenti delle major aggiornate vengono
+//     rimosse, tranne quella ancora attiva (vedi pruneUpgradedJDKs)
+//
+// Con una major (es. 'jenvy update 21') viene aggiornata solo quella, per ogni
+// provider da cui è installata; --all le aggiorna tutte. Senza --prune le
+// versioni precedenti non vengono rimosse. Ctrl-C interrompe i download in
+// corso, rimuove le installazioni incomplete e non avvia nuovi aggiornamenti.
+// Le installazioni senza metadati (provider sconosciuto) vengono saltate.
+//
+// Esempio di utilizzo:
+//
+//	jenvy update --all                     # Chiede conferma e aggiorna tutto
+//	jenvy update 21 --prune                # Solo JDK 21, rimuovendo le patch precedenti
+//	jenvy upgrade --all --jobs=4 --yes     # Non interattivo, 4 download in parallelo
+//	jenvy upgrade --all --no-reactivate    # Non modifica JAVA_HOME
+func UpgradeJDKs() error {
+	all := false
+	major := 0 // major indicata come argomento (es. 'jenvy update 21'), 0 = nessuna
+	prune := false
+	assumeYes := utils.AssumeYes()
+	reactivate := true
+	jobs := maxConcurrentDownloads
+	var keepArchive *bool
+	positional, flags := utils.SplitArgs(os.Args[2:])
+	if len(positional) > 1 {
+		utils.PrintError(fmt.Sprintf("Unexpected argument: %s", positional[1]))
+		return utils.ExitWith(utils.ExitGeneric, nil)
+	}
+	if len(positional) == 1 {
+		n, err := strconv.Atoi(positional[0])
+		if err != nil || n < 1 {
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Invalid major version '%s': use a number such as 21", positional[0]))
+		}
+		major = n
+	}
+	for _, arg := range flags {
+		switch {
+		case arg == "--all":
+			all = true
+		case arg == "--yes" || arg == "-y":
+			assumeYes = true
+		case arg == "--no-reactivate":
+			reactivate = false
+		case arg == "--prune":
+			prune = true
+		case arg == "--keep-archive" || arg == "--delete-archive":
+			keep := arg == "--keep-archive"
+			keepArchive = &keep
+		case strings.HasPrefix(arg, "--jobs="):
+			n, err := strconv.Atoi(strings.TrimPrefix(arg, "--jobs="))
+			if err != nil || n < 1 {
+				return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Invalid --jobs value '%s': use a positive number", strings.TrimPrefix(arg, "--jobs=")))
+			}
+			jobs = n
+		default:
+			utils.PrintError(fmt.Sprintf("Unknown option: %s", arg))
+			return utils.ExitWith(utils.ExitGeneric, nil)
+		}
+	}
+	if all == (major != 0) {
+		utils.PrintUsage("Usage: jenvy update <major>|--all [--prune] [--jobs=N] [--yes] [--no-reactivate] [--keep-archive|--delete-archive]")
+		return utils.ExitWith(utils.ExitGeneric, nil)
+	}
+
+	versionsDir, err := utils.GetJenvyVersionsDirectory()
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to access Jenvy directory: %v", err))
+	}
+	upgrades, unknown := collectInstalledMajors(versionsDir)
+	if unknown > 0 {
+		utils.PrintWarning(fmt.Sprintf("Skipping %d installation(s) without metadata: their provider is unknown", unknown))
+	}
+	if major != 0 {
+		upgrades = filterUpgradesByMajor(upgrades, major)
+		if len(upgrades) == 0 {
+			utils.PrintInfo("Run 'jenvy list' to see installed JDKs")
+			return utils.Fail(utils.ExitNotFound, fmt.Sprintf("No upgradable JDK %d installation found", major))
+		}
+	}
+	if len(upgrades) == 0 {
+		utils.PrintInfo("No upgradable JDK installations found")
+		return nil
+	}
+
+	pending := resolveUpgrades(upgrades)
+	if len(pending) == 0 {
+		return printUpgradeSummary(upgrades)
+	}
+
+	fmt.Println()
+	utils.PrintInfo(fmt.Sprintf("The following JDKs will be upgraded in %s:", versionsDir))
+	for _, upgrade := range pending {
+		fmt.Printf("  - %s JDK %d%s: %s -> %s\n", upgrade.Provider, upgrade.Major, upgrade.Variant.label(), upgrade.Installed, upgrade.Release.Version)
+	}
+	if prune {
+		utils.PrintInfo("Previous versions of these majors will be removed (--prune)")
+	}
+	if !assumeYes && !askConfirmation("\n[?] Do you want to proceed? (y/N): ") {
+		utils.PrintInfo("Upgrade cancelled by user")
+		return utils.ExitWith(utils.ExitGeneric, errors.New("upgrade cancelled by user"))
+	}
+	fmt.Println()
+
+	// Ctrl-C annulla il contesto invece di terminare il processo, così le
+	// installazioni interrotte vengono ripulite prima di uscire
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	scheme := utils.InstallNamingScheme()
+	flatten := utils.ActivationStyle() != utils.ActivationStyleJunction
+	// La variante è un'impostazione globale (vedi upgradeVariant.apply): i download
+	// concorrenti avvengono una variante alla volta
+	done := 0
+	for _, group := range groupUpgradesByVariant(pending) {
+		group[0].Variant.apply()
+		offset := done
+		utils.ForEachParallel(len(group), jobs, func(i int) {
+			upgrade, n := group[i], offset+i+1
+			if ctx.Err() != nil {
+				upgrade.Status = "cancelled"
+				return
+			}
+			utils.PrintInfo(fmt.Sprintf("[%d/%d] %s JDK %d%s: upgrading to %s", n, len(pending), upgrade.Provider, upgrade.Major, upgrade.Variant.label(), upgrade.Release.Version))
+			if err := installResolvedRelease(ctx, upgrade.Release, upgrade.Provider, versionsDir, scheme, flatten, keepArchive, "", nil); err != nil {
+				if ctx.Err() != nil {
+					upgrade.Status = "cancelled"
+					return
+				}
+				upgrade.Status, upgrade.Err = "failed", err
+				utils.PrintError(fmt.Sprintf("JDK %s: %v", upgrade.Release.Version, err))
+				return
+			}
+			upgrade.Status = "upgraded"
+			upgrade.NewPath, _ = utils.ResolveJDKHome(filepath.Join(versionsDir, utils.VariantInstallDirName(scheme, upgrade.Provider, upgrade.Release.Version)))
+			utils.PrintSuccess(fmt.Sprintf("[%d/%d] JDK %s installed", n, len(pending), upgrade.Release.Version))
+		})
+		done += len(group)
+	}
+	utils.SetImageVariant(utils.ImageTypeJDK, false)
+	utils.SetTargetArch("")
+
+	if ctx.Err() != nil {
+		fmt.Println()
+		utils.PrintWarning("Upgrade interrupted: incomplete installations have been removed")
+	}
+	summaryErr := printUpgradeSummary(upgrades)
+	notifyCompletion("Upgrade", fmt.Sprintf("%d JDK(s) to upgrade", len(pending)), summaryErr)
+
+	if reactivate && ctx.Err() == nil {
+		if err := reactivateUpgradedJDK(upgrades); err != nil && summaryErr == nil {
+			summaryErr = err
+		}
+	}
+	if prune && ctx.Err() == nil {
+		if err := pruneUpgradedJDKs(upgrades); err != nil && summaryErr == nil {
+			summaryErr = err
+		}
+	}
+	return summaryErr
+}
+
+// filterUpgradesByMajor tiene solo le installazioni della major indicata, di qualsiasi provider.
+func filterUpgradesByMajor(upgrades []*majorUpgrade, major int) []*majorUpgrade {
+	var filtered []*majorUpgrade
+	for _, upgrade := range upgrades {
+		if upgrade.Major == major {
+			filtered = append(filtered, upgrade)
+		}
+	}
+	return filtered
+}
+
+// collectInstalledMajors raggruppa le installazioni per provider, major e variante, tenendo la versione più recente.
+//
+// JDK, JRE, pacchetti con JavaFX e architetture diverse della stessa major
+// restano gruppi separati: ognuno viene aggiornato nella propria variante e
+// --prune non rimuove mai le installazioni di un'altra.
+//
+// Restituisce le major ordinate per provider, numero e variante, e quante
+// installazioni sono state saltate perché prive di metadati.
+func collectInstalledMajors(versionsDir string) ([]*majorUpgrade, int) {
+	scans, err := scanInstallations(versionsDir, false)
+	if err != nil {
+		return nil, 0
+	}
+
+	latest := make(map[string]*majorUpgrade)
+	unknown := 0
+	for _, scan := range scans {
+		if scan.JDKHome == "" {
+			continue
+		}
+		meta, err := utils.LoadInstallMetadata(scan.Path)
+		if err != nil || meta.Provider == "" || meta.Version == "" {
+			unknown++
+			continue
+		}
+		major, _, _ := utils.ParseVersionNumber(meta.Version)
+		if major <= 0 {
+			unknown++
+			continue
+		}
+		variant := installVariant(meta)
+		key := fmt.Sprintf("%s/%d/%s", meta.Provider, major, variant.key())
+		current, ok := latest[key]
+		if !ok {
+			current = &majorUpgrade{Provider: meta.Provider, Major: major, Variant: variant}
+			latest[key] = current
+		}
+		current.Previous = append(current.Previous, scan)
+		if current.Installed != "" && utils.CompareJavaVersions(meta.Version, current.Installed) <= 0 {
+			continue
+		}
+		current.Installed, current.InstallPath = meta.Version, scan.JDKHome
+	}
+
+	upgrades := make([]*majorUpgrade, 0, len(latest))
+	for _, upgrade := range latest {
+		upgrades = append(upgrades, upgrade)
+	}
+	sort.Slice(upgrades, func(i, j int) bool {
+		if upgrades[i].Provider != upgrades[j].Provider {
+			return upgrades[i].Provider < upgrades[j].Provider
+		}
+		if upgrades[i].Major != upgrades[j].Major {
+			return upgrades[i].Major < upgrades[j].Major
+		}
+		return upgrades[i].Variant.key() < upgrades[j].Variant.key()
+	})
+	return upgrades, unknown
+}
+
+// groupUpgradesByVariant divide gli aggiornamenti per variante, nell'ordine in cui compaiono.
+func groupUpgradesByVariant(upgrades []*majorUpgrade) [][]*majorUpgrade {
+	var groups [][]*majorUpgrade
+	index := make(map[string]int)
+	for _, upgrade := range upgrades {
+		key := upgrade.Variant.key()
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], upgrade)
+	}
+	return groups
+}
+
+// resolveUpgrades trova l'ultima patch di ogni major e restituisce quelle da aggiornare.
+//
+// Ogni provider viene interrogato una sola volta per variante, con le stesse
+// impostazioni di --type, --javafx e --arch dell'installazione; se non risponde,
+// tutte le sue major di quella variante vengono segnate come fallite.
+func resolveUpgrades(upgrades []*majorUpgrade) []*majorUpgrade {
+	defer utils.SetTargetArch("")
+	defer utils.SetImageVariant(utils.ImageTypeJDK, false)
+
+	finders := make(map[string]releaseFinder)
+	var pending []*majorUpgrade
+	for _, upgrade := range upgrades {
+		upgrade.Variant.apply()
+		key := upgrade.Provider + "/" + upgrade.Variant.key()
+		findRelease, ok := finders[key]
+		if !ok {
+			utils.PrintSearch(fmt.Sprintf("Resolving latest releases from provider: %s%s", upgrade.Provider, upgrade.Variant.label()))
+			finder, err := fetchReleaseFinder(upgrade.Provider)
+			if err != nil {
+				utils.PrintError(err.Error())
+				printProviderFailureHint(upgrade.Provider, err)
+			}
+			finders[key] = finder
+			findRelease = finder
+		}
+		if findRelease == nil {
+			upgrade.Status, upgrade.Err = "failed", fmt.Errorf("provider %s unavailable", upgrade.Provider)
+			continue
+		}
+
+		upgrade.Release = findRelease(strconv.Itoa(upgrade.Major))
+		switch {
+		case upgrade.Release.URL == "":
+			upgrade.Status = "not offered"
+		case utils.CompareJavaVersions(upgrade.Release.Version, upgrade.Installed) <= 0:
+			upgrade.Status = "up to date"
+		default:
+			if upgrade.Release.Filename == "" {
+				upgrade.Release.Filename = fmt.Sprintf("openjdk-%s.tar.gz", upgrade.Release.Version)
+			}
+			pending = append(pending, upgrade)
+		}
+	}
+	return pending
+}
+
+// printUpgradeSummary stampa l'esito di ogni major al termine di 'upgrade --all'.
+//
+// Restituisce un errore con utils.ExitNetwork se almeno un aggiornamento è fallito,
+// o con utils.ExitInterrupted se l'operazione è stata interrotta.
+func printUpgradeSummary(upgrades []*majorUpgrade) error {
+	fmt.Println()
+	utils.PrintSection("[SUMMARY] JDK UPGRADE")
+	counts := make(map[string]int)
+	for _, upgrade := range upgrades {
+		counts[upgrade.Status]++
+		color := utils.Green
+		switch upgrade.Status {
+		case "failed", "cancelled":
+			color = utils.Red
+		case "not offered":
+			color = utils.Yellow
+		}
+
+		target := upgrade.Installed
+		if upgrade.Status == "upgraded" {
+			target = upgrade.Installed + " -> " + upgrade.Release.Version
+		}
+		line := fmt.Sprintf("  %-10s JDK %-3d %-34s %s", upgrade.Provider, upgrade.Major, target+upgrade.Variant.label(), utils.ColorText(upgrade.Status, color))
+		if upgrade.Err != nil {
+			line += fmt.Sprintf(" (%v)", upgrade.Err)
+		}
+		fmt.Println(line)
+	}
+	fmt.Println()
+	utils.PrintInfo(fmt.Sprintf("%d upgraded, %d up to date, %d failed, %d cancelled",
+		counts["upgraded"], counts["up to date"], counts["failed"], counts["cancelled"]))
+
+	switch {
+	case counts["failed"] > 0:
+		return utils.ExitWith(utils.ExitNetwork, fmt.Errorf("%d upgrade(s) failed", counts["failed"]))
+	case counts["cancelled"] > 0:
+		return utils.ExitWith(utils.ExitInterrupted, errors.New("upgrade interrupted"))
+	}
+	return nil
+}
+
+// reactivateUpgradedJDK ripunta JAVA_HOME se il JDK attivo appartiene a una major aggiornata.
+//
+// Lo scope modificato è quello effettivo (vedi repointJavaHome).
+func reactivateUpgradedJDK(upgrades []*majorUpgrade) error {
+	active, activeHome, ok := activeJavaHome()
+	if !ok {
+		return nil
+	}
+
+	for _, upgrade := range upgrades {
+		if upgrade.Status != "upgraded" || upgrade.NewPath == "" || !samePath(activeHome, upgrade.InstallPath) {
+			continue
+		}
+		fmt.Println()
+		return repointJavaHome(active, activeHome, upgrade.NewPath, upgrade.Release.Version)
+	}
+	return nil
+}
+
+// activeJavaHome restituisce lo scope effettivo di JAVA_HOME (vedi 'jenvy current') e il JDK a cui punta.
+func activeJavaHome() (utils.ScopeValue, string, bool) {
+	userHome, _ := readUserEnvironmentVariable("JAVA_HOME")
+	systemHome, _ := readSystemEnvironmentVariable("JAVA_HOME")
+	active, ok := utils.EffectiveScope(
+		utils.ScopeValue{Scope: utils.ScopeUser, Value: userHome},
+		utils.ScopeValue{Scope: utils.ScopeSystem, Value: systemHome},
+	)
+	if !ok {
+		return active, "", false
+	}
+	return active, utils.ResolveJavaHome(active.Value), true
+}
+
+// repointJavaHome sposta JAVA_HOME dal JDK attivo a newPath, nello scope effettivo.
+//
+// Usata da 'jenvy update' e dal JDK predefinito (vedi followDefaultJDK):
+//   - **Junction**: La junction ~/.jenvy/current viene ripuntata, senza privilegi
+//   - **User**: La JAVA_HOME utente viene aggiornata, senza privilegi
+//   - **System**: Aggiornata solo se il processo è già amministratore, altrimenti
+//     viene suggerito il comando 'jenvy use' da eseguire
+func repointJavaHome(active utils.ScopeValue, activeHome, newPath, version string) error {
+	link, _ := utils.CurrentJDKLink()
+	switch {
+	case link != "" && samePath(active.Value, link):
+		if err := updateJunction(link, newPath); err != nil {
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to update %s: %v", link, err))
+		}
+	case active.Scope == utils.ScopeUser:
+		if err := setUserEnvironmentVariable("JAVA_HOME", newPath); err != nil {
+			utils.PrintError(fmt.Sprintf("Failed to set user JAVA_HOME: %v", err))
+			return utils.ExitWith(registryExitCode(err), err)
+		}
+	case isRunningAsAdmin():
+		if err := setSystemEnvironmentVariable("JAVA_HOME", newPath); err != nil {
+			utils.PrintError(fmt.Sprintf("Failed to set JAVA_HOME: %v", err))
+			return utils.ExitWith(registryExitCode(err), err)
+		}
+	default:
+		utils.PrintInfo(fmt.Sprintf("The active JDK has a newer version: run 'jenvy use %s' to switch JAVA_HOME to it", version))
+		return nil
+	}
+	rememberPreviousJavaHome(activeHome, newPath)
+	utils.PrintSuccess(fmt.Sprintf("Re-activated JDK %s (%s JAVA_HOME)", version, active.Scope))
+	return nil
+}
+
+// pruneUpgradedJDKs rimuove le versioni precedenti delle major aggiornate ('update --prune').
+//
+// Viene eseguita dopo la riattivazione, così il JDK attivo è già quello nuovo.
+// Un'installazione ancora usata come JAVA_HOME (di sistema, utente, del processo
+// o tramite la junction ~/.jenvy/current) non viene mai rimossa: succede se la
+// riattivazione è stata saltata con --no-reactivate o richiede privilegi.
+//
+// Restituisce un errore con utils.ExitPermission o utils.ExitGeneric se almeno
+// una rimozione è fallita.
+func pruneUpgradedJDKs(upgrades []*majorUpgrade) error {
+	userHome, _ := readUserEnvironmentVariable("JAVA_HOME")
+	systemHome, _ := readSystemEnvironmentVariable("JAVA_HOME")
+	active := []string{
+		utils.ResolveJavaHome(userHome),
+		utils.ResolveJavaHome(systemHome),
+		utils.ResolveJavaHome(os.Getenv("JAVA_HOME")),
+	}
+	if link, err := utils.CurrentJDKLink(); err == nil && link != "" {
+		active = append(active, utils.ResolveJavaHome(link))
+	}
+
+	var failed error
+	for _, upgrade := range upgrades {
+		if upgrade.Status != "upgraded" {
+			continue
+		}
+		for _, previous := range upgrade.Previous {
+			if samePath(previous.JDKHome, upgrade.NewPath) {
+				continue
+			}
+			inUse := false
+			for _, home := range active {
+				if home != "" && samePath(home, previous.JDKHome) {
+					inUse = true
+				}
+			}
+			if inUse {
+				utils.PrintWarning(fmt.Sprintf("Keeping %s: it is still the active JDK", previous.Name))
+				continue
+			}
+			if err := os.RemoveAll(previous.Path); err != nil {
+				utils.PrintError(fmt.Sprintf("Failed to remove %s: %v", previous.Name, err))
+				code := utils.ExitGeneric
+				if os.IsPermission(err) {
+					code = utils.ExitPermission
+				}
+				failed = utils.ExitWith(code, err)
+				continue
+			}
+			utils.PrintSuccess(fmt.Sprintf("Removed %s (replaced by JDK %s)", previous.Name, upgrade.Release.Version))
+		}
+	}
+	return failed
+}
@@ -1,16 +1,14 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
-	"syscall"
-	"unsafe"
 
 	"jenvy/internal/utils"
-
-	"golang.org/x/sys/windows/registry"
 )
 
 // UseJDK attiva una versione specifica di JDK come JAVA_HOME di sistema su Windows.
@@ -49,7 +47,7 @@ import (
 //
 // Parametri:
 //
-//	Legge da os.Args[2] la versione JDK da attivare
+//	Legge da os.Args[2:] la versione JDK da attivare e le opzioni --temporary/--shell=
 //
 // Comportamenti speciali:
 //   - Se mancano argomenti: Mostra usage e lista JDK disponibili
@@ -62,6 +60,26 @@ import (
 //	jenvy use 17        → Attiva JDK 17 (cerca JDK-17.x.x)
 //	jenvy use 17.0.5    → Attiva JDK 17.0.5 specifico
 //	jenvy u 21          → Forma breve per attivare JDK 21
+//	jenvy use 17 --temporary → JDK 17 solo in una shell figlia (vedi useTemporaryJDK)
+//	jenvy use 17 --session --shell=powershell → Comandi per Invoke-Expression (vedi useSessionJDK)
+//	jenvy use -         → Torna al JDK attivo prima dell'ultimo 'jenvy use'
+//	jenvy use 17 --dry-run → Mostra le modifiche e l'effetto sul PATH senza applicarle
+//	jenvy use 17 --user    → JAVA_HOME utente (HKCU), senza privilegi amministratore
+//	jenvy use 17 --local   → Scrive .jenvy-version nella directory corrente
+//	jenvy use              → Attiva il JDK indicato dal .jenvy-version del progetto
+//	jenvy use --from-build → Attiva il JDK richiesto da pom.xml o build.gradle(.kts)
+//	jenvy use 21 --install --yes → Scarica JDK 21 dal provider predefinito se manca, poi lo attiva
+//
+// Scope e precedenza:
+//   - **--global** (predefinito): JAVA_HOME di sistema (HKLM), richiede privilegi amministratore
+//   - **--user**: JAVA_HOME utente (HKCU), nasconde quello di sistema
+//   - **--local**: File .jenvy-version, il più specifico
+//
+// In risoluzione vale project > user > system; 'jenvy current' mostra quale
+// scope è effettivo e perché (vedi utils.EffectiveScope).
+//
+// Con activation-style=junction JAVA_HOME resta fisso su ~/.jenvy/current e
+// 'jenvy use' ripunta soltanto la junction (vedi useJDKViaJunction).
 //
 // Output tipico:
 //
@@ -73,82 +91,144 @@ import (
 //
 // Scenari di errore:
 //   - Privilegi insufficienti: Guida per esecuzione come amministratore
-//   - JDK non trovato: Suggerisce "jenvy list" per vedere JDK disponibili
+//   - JDK non trovato: Suggerisce "jenvy list" per vedere JDK disponibili; con --install
+//     o install-on-use lo scarica invece (vedi installJDKForUse)
 //   - Directory JDK corrotta: Messaggio di errore con path problematico
 //   - Errori registro: Consigli troubleshooting per problemi Windows
-func UseJDK() {
-	if len(os.Args) < 3 {
-		utils.PrintUsage("Usage: jenvy use <version>")
+func UseJDK() error {
+	// Separa le opzioni dalla versione richiesta
+	temporary := false
+	session := false // --session: stampa i comandi per eval invece di modificare l'ambiente
+	dryRun := false
+	shell := ""
+	scope := "" // --global, --user o --local; vuoto = system (comportamento storico)
+	fromBuild := false
+	install := false // --install: scarica la versione se non è installata (vedi install-on-use)
+	assumeYes := utils.AssumeYes()
+	positional, flags := utils.SplitArgs(os.Args[2:])
+	for _, arg := range flags {
+		if arg == "--temporary" {
+			temporary = true
+		} else if arg == "--session" {
+			session = true
+		} else if arg == "--global" || arg == "--user" || arg == "--local" {
+			if scope != "" && scope != arg {
+				return utils.Fail(utils.ExitGeneric, fmt.Sprintf("%s cannot be combined with %s", arg, scope))
+			}
+			scope = arg
+		} else if arg == "--from-build" {
+			fromBuild = true
+		} else if arg == "--dry-run" {
+			dryRun = true
+		} else if arg == "--install" {
+			install = true
+		} else if arg == "--yes" || arg == "-y" {
+			assumeYes = true
+		} else if strings.HasPrefix(arg, "--shell=") {
+			shell = strings.TrimPrefix(arg, "--shell=")
+		} else {
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Unknown option for use: %s", arg))
+		}
+	}
+
+	if scope != "" && temporary {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("%s cannot be combined with --temporary", scope))
+	}
+	if session && (scope != "" || temporary || dryRun) {
+		return utils.Fail(utils.ExitGeneric, "--session cannot be combined with --global, --user, --local, --temporary or --dry-run")
+	}
+
+	// Con --session lo stdout contiene solo i comandi da valutare: ogni altro messaggio va su stderr
+	stdout := os.Stdout
+	if session {
+		os.Stdout = os.Stderr
+		defer func() { os.Stdout = stdout }()
+	}
+
+	// --from-build sostituisce la versione esplicita con quella dei file di build
+	if fromBuild {
+		if len(positional) > 0 {
+			return utils.Fail(utils.ExitGeneric, "--from-build cannot be combined with an explicit version")
+		}
+		version, ok := resolveBuildVersion()
+		if !ok {
+			return utils.ExitWith(utils.ExitNotFound, nil)
+		}
+		positional = append(positional, version)
+	}
+
+	// Senza versione vale il file .jenvy-version del progetto, se presente
+	if len(positional) == 0 && scope != "--local" {
+		if version, ok := resolveScopedVersion(); ok {
+			positional = append(positional, version)
+		}
+	}
+
+	if len(positional) == 0 {
+		utils.PrintUsage("Usage: jenvy use <version>|-|--from-build [--global|--user|--local] [--install [--yes]] [--dry-run] [--temporary|--session [--shell=cmd|powershell|pwsh|bash]]")
 		utils.PrintUsage("Short form: jenvy u <version>")
 		utils.PrintInfo("Available JDKs:")
 		showAvailableJDKs()
-		return
+		return utils.ExitWith(utils.ExitGeneric, nil)
 	}
 
-	version := os.Args[2]
+	version := positional[0]
+	if version == "-" && scope == "--local" {
+		return utils.Fail(utils.ExitGeneric, "'jenvy use -' cannot be combined with --local")
+	}
+	if version != "-" {
+		versionsDir, _ := utils.GetJenvyVersionsDirectory()
+		normalized, err := versionArgument(version, versionsDir)
+		if err != nil {
+			return err
+		}
+		version = normalized
+	}
 
-	// Prima di tutto, verifichiamo se ci sono JDK installati
-	versionsDir, err := utils.GetJenvyVersionsDirectory()
-	if err != nil {
-		utils.PrintError(fmt.Sprintf("Failed to access Jenvy directory: %v", err))
-		return
+	var jdkPath string
+	var ok bool
+	if version == "-" {
+		jdkPath, version, ok = resolvePreviousJDK()
+	} else if !dryRun && !isJDKInstalled(version) && installOnUseEnabled(install) {
+		installed, err := installJDKForUse(version, assumeYes)
+		if err != nil {
+			return err
+		}
+		jdkPath, ok = installed, true
+	} else {
+		jdkPath, ok = resolveInstalledJDK(version)
+	}
+	if !ok {
+		return utils.ExitWith(utils.ExitNotFound, nil)
 	}
 
-	// Controlla se la directory versions esiste e contiene JDK
-	entries, err := os.ReadDir(versionsDir)
-	if err != nil {
-		utils.PrintError("Jenvy versions directory not found or inaccessible")
-		utils.PrintInfo("No JDKs appear to be installed yet")
-		utils.PrintInfo(fmt.Sprintf("Use 'jenvy download %s' to download your first JDK", version))
-		return
+	// Anteprima: nessuna modifica al registro, quindi nessun privilegio richiesto
+	if dryRun {
+		previewUseJDK(version, jdkPath)
+		return nil
 	}
 
-	// Verifica se ci sono JDK validi installati
-	jdkCount := 0
-	for _, entry := range entries {
-		if entry.IsDir() && strings.HasPrefix(entry.Name(), "JDK-") {
-			jdkPath := filepath.Join(versionsDir, entry.Name())
-			if utils.IsValidJDKDirectory(jdkPath) {
-				jdkCount++
-			}
-		}
+	// Modalità sessione: solo output per eval, nessun privilegio richiesto
+	if session {
+		return useSessionJDK(stdout, version, jdkPath, shell)
 	}
 
-	if jdkCount == 0 {
-		utils.PrintError("No valid JDK installations found")
-		utils.PrintInfo("The .jenvy/versions directory exists but contains no valid JDK installations")
-		utils.PrintInfo(fmt.Sprintf("Use 'jenvy download %s' to download a JDK", version))
-		return
+	// Modalità temporanea: nessuna modifica al registro, quindi nessun privilegio richiesto
+	if temporary {
+		return useTemporaryJDK(version, jdkPath, shell)
 	}
 
-	// CONTROLLO IMPORTANTE: Verifica se la versione richiesta esiste PRIMA di richiedere privilegi admin
-	jdkPath, err := utils.FindSingleJDKInstallation(version)
-	if err != nil {
-		// Fornire messaggi di errore più specifici e utili
-		if strings.Contains(err.Error(), "no JDK found matching version") {
-			utils.PrintError(fmt.Sprintf("JDK version %s not found", version))
-			utils.PrintInfo("Available JDK versions:")
-			showAvailableJDKs()
-			utils.PrintInfo(fmt.Sprintf("Use 'jenvy download %s' to download this version", version))
-		} else if strings.Contains(err.Error(), "multiple matches found") {
-			utils.PrintError(fmt.Sprintf("Multiple JDK versions match '%s'", version))
-			utils.PrintInfo("Please be more specific with the version number")
-			utils.PrintInfo("Example: use 'jenvy use 17.0.5' instead of 'jenvy use 17'")
-		} else if strings.Contains(err.Error(), "failed to get Jenvy directory") {
-			utils.PrintError("Unable to access Jenvy installation directory")
-			utils.PrintInfo("Make sure you have proper permissions and the .jenvy directory exists")
-		} else {
-			utils.PrintError(fmt.Sprintf("Failed to locate JDK version %s: %v", version, err))
-		}
-		return
+	// Scope utente e progetto: nessun privilegio amministratore richiesto
+	switch scope {
+	case "--user":
+		return useJDKForUser(version, jdkPath)
+	case "--local":
+		return useJDKForProject(version, jdkPath)
 	}
 
-	// Verify it's a valid JDK directory PRIMA di richiedere privilegi admin
-	if !utils.IsValidJDKDirectory(jdkPath) {
-		utils.PrintError(fmt.Sprintf("Invalid or corrupted JDK directory: %s", jdkPath))
-		utils.PrintInfo("This JDK installation appears to be incomplete or damaged")
-		utils.PrintInfo(fmt.Sprintf("Try downloading it again with: jenvy download %s", version))
-		return
+	// Stile junction: il registro viene toccato solo alla prima attivazione
+	if utils.ActivationStyle() == utils.ActivationStyleJunction {
+		return useJDKViaJunction(version, jdkPath)
 	}
 
 	// Check if running as administrator
@@ -157,27 +237,31 @@ func UseJDK() {
 		utils.PrintInfo("Requesting administrator privileges...")
 
 		if requestAdminPrivileges() {
-			return // Exit current process, admin process will handle the command
-		} else {
-			utils.PrintError("Failed to obtain administrator privileges")
-			utils.PrintInfo("You can run manually as Administrator or use user-level installation")
-			return
+			return nil // Exit current process, admin process will handle the command
 		}
+		utils.PrintError("Failed to obtain administrator privileges")
+		utils.PrintInfo("You can run manually as Administrator or use user-level installation")
+		return utils.ExitWith(utils.ExitPermission, errors.New("failed to obtain administrator privileges"))
 	}
 
+	// Read the JDK being replaced before overwriting it, for 'jenvy use -'
+	previousJavaHome, _ := readSystemEnvironmentVariable("JAVA_HOME")
+	previousJavaHome = utils.ResolveJavaHome(previousJavaHome)
+
 	// Set JAVA_HOME in system environment
-	err = setSystemEnvironmentVariable("JAVA_HOME", jdkPath)
+	err := setSystemEnvironmentVariable("JAVA_HOME", jdkPath)
 	if err != nil {
 		utils.PrintError(fmt.Sprintf("Failed to set JAVA_HOME: %v", err))
 		utils.PrintInfo("Try running as Administrator")
-		return
+		return utils.ExitWith(registryExitCode(err), err)
 	}
+	rememberPreviousJavaHome(previousJavaHome, jdkPath)
 
 	// Ensure %JAVA_HOME%\\bin is in PATH
 	err = ensureJavaHomeInPath()
 	if err != nil {
 		utils.PrintWarning(fmt.Sprintf("Failed to update PATH: %v", err))
-		utils.PrintInfo("You may need to add %JAVA_HOME%\\bin to your PATH manually")
+		utils.PrintInfo("You may need to add " + javaHomeBinEntry + " to your PATH manually")
 	}
 
 	utils.PrintSuccess(fmt.Sprintf("Set JAVA_HOME to JDK %s", version))
@@ -188,333 +272,214 @@ func UseJDK() {
 	fmt.Println()
 	utils.PrintInfo("Testing Java installation:")
 	testJavaInstallation(jdkPath)
+	return nil
 }
 
-// requestAdminPrivileges richiede automaticamente privilegi amministratore tramite UAC Windows.
-//
-// Questa funzione gestisce l'elevazione dei privilegi quando il comando "jenvy use"
-// necessita di modificare le variabili d'ambiente di sistema. Utilizza l'API Windows
-// ShellExecute con il verbo "runas" per attivare il dialogo UAC (User Account Control).
+// resolveInstalledJDK individua e valida l'installazione Jenvy richiesta da 'jenvy use'.
 //
-// Meccanismo elevazione UAC:
-// 1. **Rilevamento eseguibile**: Ottiene il path dell'eseguibile Jenvy corrente
-// 2. **Preparazione argomenti**: Ricostruisce tutti gli argomenti della command line
-// 3. **ShellExecute "runas"**: Invoca Windows Shell con richiesta privilegi admin
-// 4. **Terminazione processo**: Il processo corrente termina, quello elevato continua
-//
-// Processo UAC Windows:
-//   - **Dialogo sicurezza**: Windows mostra prompt UAC per conferma utente
-//   - **Nuovo processo**: Se accettato, viene creato processo con privilegi admin
-//   - **Stesso comando**: Il nuovo processo esegue esattamente gli stessi argomenti
-//   - **Terminazione originale**: Il processo originale termina dopo l'elevazione
-//
-// Gestione argomenti:
-//   - **Preservazione completa**: Tutti gli argomenti originali vengono mantenuti
-//   - **Esclusione program name**: Solo gli argomenti reali (os.Args[1:])
-//   - **Join sicuro**: Concatenazione argomenti con spazi per ShellExecute
-//   - **Unicode support**: Gestione corretta caratteri Unicode in percorsi
-//
-// Codici ritorno ShellExecute:
-//   - **> 32**: Successo, elevazione completata
-//   - **<= 32**: Errore o cancellazione utente
-//   - **Codici comuni**: 2=file not found, 5=access denied, 8=memoria insufficiente
+// Tutte le verifiche avvengono PRIMA di richiedere privilegi amministratore, così
+// che un errore di battitura non apra inutilmente il prompt UAC.
 //
 // Parametri:
 //
-//	Nessuno (legge da os.Args globale)
+//	version string - Versione richiesta (es. "17", "17.0.5", "JDK-17.0.5+8")
 //
 // Restituisce:
 //
-//	bool - true se elevazione completata con successo, false se fallita o rifiutata
-//
-// Comportamento trasparente:
-//   - Se successo: Il processo corrente termina, quello elevato prosegue silenziosamente
-//   - Se fallimento: Il processo corrente continua con messaggi di errore appropriati
-//   - Se cancellato: L'utente ha rifiutato l'elevazione nel dialogo UAC
-//
-// Scenari di utilizzo:
-//   - Utente standard che esegue "jenvy use"
-//   - Modifica variabili ambiente sistema richiede privilegi admin
-//   - Alternativa a esecuzione manuale "Run as Administrator"
-//
-// Limitazioni:
-//   - Richiede interazione utente (dialogo UAC)
-//   - Non funziona in contesti automatizzati senza desktop
-//   - Dipende dalle policy UAC del sistema
-//
-// Esempio di utilizzo:
-//
-//	if !isRunningAsAdmin() {
-//	    if requestAdminPrivileges() {
-//	        return // Il nuovo processo gestirà il comando
-//	    }
-//	    // Gestire fallimento elevazione
-//	}
-func requestAdminPrivileges() bool {
-	// Get current executable path
-	exe, err := os.Executable()
+//	string - Root del JDK da usare come JAVA_HOME
+//	bool   - false se il JDK non è stato trovato (errore già mostrato all'utente)
+func resolveInstalledJDK(version string) (string, bool) {
+	// Prima di tutto, verifichiamo se ci sono JDK installati
+	versionsDir, err := utils.GetJenvyVersionsDirectory()
 	if err != nil {
-		utils.PrintError(fmt.Sprintf("Failed to get executable path: %v", err))
-		return false
+		utils.PrintError(fmt.Sprintf("Failed to access Jenvy directory: %v", err))
+		return "", false
 	}
 
-	// Build command arguments (pass all original arguments)
-	args := os.Args[1:] // Skip the program name
+	// Controlla se la directory versions esiste e contiene JDK
+	externals := utils.ExternalInstallDirs()
+	entries, err := os.ReadDir(versionsDir)
+	if err != nil && len(externals) == 0 {
+		utils.PrintError("Jenvy versions directory not found or inaccessible")
+		utils.PrintInfo("No JDKs appear to be installed yet")
+		utils.PrintInfo(fmt.Sprintf("Use 'jenvy download %s' to download your first JDK", version))
+		return "", false
+	}
+	var candidates []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			candidates = append(candidates, filepath.Join(versionsDir, entry.Name()))
+		}
+	}
+	candidates = append(candidates, externals...)
 
-	// Create the command with runas verb to request admin privileges
-	verbPtr, _ := syscall.UTF16PtrFromString("runas")
-	exePtr, _ := syscall.UTF16PtrFromString(exe)
+	// Verifica se ci sono JDK validi installati (formato "JDK-" o "{provider}-jdk-")
+	scheme := utils.InstallNamingScheme()
+	jdkCount := 0
+	for _, jdkPath := range candidates {
+		if _, _, ok := utils.ParseInstallDirName(filepath.Base(jdkPath), scheme); ok {
+			if _, ok := utils.ResolveJDKHome(jdkPath); ok {
+				jdkCount++
+			}
+		}
+	}
 
-	// Join arguments into a single string
-	argString := strings.Join(args, " ")
-	argPtr, _ := syscall.UTF16PtrFromString(argString)
+	if jdkCount == 0 {
+		utils.PrintError("No valid JDK installations found")
+		utils.PrintInfo("The .jenvy/versions directory exists but contains no valid JDK installations")
+		utils.PrintInfo(fmt.Sprintf("Use 'jenvy download %s' to download a JDK", version))
+		return "", false
+	}
 
-	// Use ShellExecute to run with elevated privileges
-	ret := shellExecute(0, verbPtr, exePtr, argPtr, nil, 1)
+	// CONTROLLO IMPORTANTE: Verifica se la versione richiesta esiste PRIMA di richiedere privilegi admin
+	jdkPath, err := utils.FindSingleJDKInstallation(version)
+	if err != nil {
+		// Fornire messaggi di errore più specifici e utili
+		if strings.Contains(err.Error(), "no JDK found matching version") {
+			utils.PrintError(fmt.Sprintf("JDK version %s not found", version))
+			utils.PrintInfo("Available JDK versions:")
+			showAvailableJDKs()
+			utils.PrintInfo(fmt.Sprintf("Use 'jenvy download %s' to download this version", version))
+		} else if strings.Contains(err.Error(), "multiple matches found") {
+			utils.PrintError(fmt.Sprintf("Multiple JDK versions match '%s'", version))
+			utils.PrintInfo("Please be more specific with the version number")
+			utils.PrintInfo("Example: use 'jenvy use 17.0.5' instead of 'jenvy use 17'")
+		} else if strings.Contains(err.Error(), "failed to get Jenvy directory") {
+			utils.PrintError("Unable to access Jenvy installation directory")
+			utils.PrintInfo("Make sure you have proper permissions and the .jenvy directory exists")
+		} else {
+			utils.PrintError(fmt.Sprintf("Failed to locate JDK version %s: %v", version, err))
+		}
+		return "", false
+	}
 
-	// Return true if ShellExecute succeeded (> 32)
-	return ret > 32
+	// Verify it's a valid JDK directory PRIMA di richiedere privilegi admin.
+	// Le installazioni estratte con --no-flatten hanno la root JDK in una sottodirectory.
+	jdkHome, ok := utils.ResolveJDKHome(jdkPath)
+	if !ok {
+		utils.PrintError(fmt.Sprintf("Invalid or corrupted JDK directory: %s", jdkPath))
+		utils.PrintInfo("This JDK installation appears to be incomplete or damaged")
+		utils.PrintInfo(fmt.Sprintf("Try downloading it again with: jenvy download %s", version))
+		return "", false
+	}
+	return jdkHome, true
 }
 
-// shellExecute è un wrapper Go per l'API Windows ShellExecuteW per esecuzione programmi con privilegi.
-//
-// Questa funzione incapsula la chiamata diretta all'API Win32 ShellExecuteW utilizzando
-// syscall per eseguire programmi con parametri specifici, inclusa la possibilità di
-// richiedere elevazione privilegi tramite il verbo "runas".
+// resolvePreviousJDK restituisce il JAVA_HOME attivo prima dell'ultimo 'jenvy use'.
 //
-// API Windows ShellExecuteW:
-//   - **Funzione nativa**: shell32.dll ShellExecuteW per esecuzione avanzata
-//   - **Unicode support**: Versione Wide (W) per supporto caratteri Unicode
-//   - **Verbi azione**: "open", "runas", "print", etc. per diversi comportamenti
-//   - **Controllo finestra**: Parametri per gestione visualizzazione finestra
-//
-// Parametri:
-//
-//	hwnd uintptr     - Handle finestra parent (0 per nessun parent)
-//	verb *uint16     - Verbo azione: nil="open", "runas"=privilegi admin
-//	file *uint16     - Percorso eseguibile da lanciare (UTF-16 pointer)
-//	args *uint16     - Argomenti command line (UTF-16 pointer, può essere nil)
-//	dir *uint16      - Directory lavoro (UTF-16 pointer, può essere nil)
-//	show int         - Modalità visualizzazione finestra (SW_HIDE=0, SW_NORMAL=1, etc.)
+// Il valore viene ripristinato così com'è, anche se punta a un JDK non gestito da
+// Jenvy (es. installato con un MSI): in quel caso viene solo segnalato se la
+// directory non sembra più un JDK valido.
 //
 // Restituisce:
 //
-//	uintptr - Codice ritorno ShellExecute (>32=successo, <=32=errore specifico)
-//
-// Codici ritorno comuni:
-//   - **> 32**: Successo, programma avviato correttamente
-//   - **0**: Out of memory or resources
-//   - **2**: File not found (ERROR_FILE_NOT_FOUND)
-//   - **3**: Path not found (ERROR_PATH_NOT_FOUND)
-//   - **5**: Access denied (ERROR_ACCESS_DENIED)
-//   - **8**: Out of memory (ERROR_NOT_ENOUGH_MEMORY)
-//   - **31**: No application associated with file type
-//
-// Utilizzo syscall.NewLazyDLL:
-//   - **Caricamento lazy**: DLL caricata solo quando necessario
-//   - **Performance**: Evita caricamento inutile se funzione non usata
-//   - **Gestione errori**: syscall gestisce automaticamente errori caricamento
-//   - **Pulizia automatica**: Go runtime gestisce cleanup DLL
-//
-// Sicurezza:
-//   - **unsafe.Pointer**: Necessario per compatibilità API C Windows
-//   - **Validazione input**: Chiamante responsabile per validazione parametri
-//   - **Gestione memoria**: Go runtime gestisce stringhe UTF-16
-//
-// Esempio di utilizzo con UAC:
-//
-//	verb, _ := syscall.UTF16PtrFromString("runas")
-//	exe, _ := syscall.UTF16PtrFromString("C:\\app.exe")
-//	args, _ := syscall.UTF16PtrFromString("arg1 arg2")
-//	ret := shellExecute(0, verb, exe, args, nil, 1)
-//	if ret > 32 { /* successo */ }
-func shellExecute(hwnd uintptr, verb, file, args, dir *uint16, show int) uintptr {
-	ret, _, _ := syscall.NewLazyDLL("shell32.dll").NewProc("ShellExecuteW").Call(
-		hwnd,
-		uintptr(unsafe.Pointer(verb)),
-		uintptr(unsafe.Pointer(file)),
-		uintptr(unsafe.Pointer(args)),
-		uintptr(unsafe.Pointer(dir)),
-		uintptr(show))
-	return ret
+//	string - JAVA_HOME precedente
+//	string - Nome da mostrare nei messaggi
+//	bool   - false se non esiste un JDK precedente (errore già mostrato all'utente)
+func resolvePreviousJDK() (string, string, bool) {
+	cfg, err := utils.LoadConfigOrDefault()
+	if err != nil {
+		utils.PrintError(fmt.Sprintf("Configuration file parsing error: %v", err))
+		return "", "", false
+	}
+	if cfg.PreviousJavaHome == "" {
+		utils.PrintError("No previous JDK recorded")
+		utils.PrintInfo("'jenvy use -' becomes available after switching JDK with 'jenvy use <version>'")
+		return "", "", false
+	}
+
+	previous := cfg.PreviousJavaHome
+	if versionsDir, err := utils.GetJenvyVersionsDirectory(); err == nil && isPathInside(previous, versionsDir) {
+		if !utils.IsValidJDKDirectory(previous) {
+			utils.PrintError(fmt.Sprintf("Previous JDK is no longer installed: %s", previous))
+			return "", "", false
+		}
+	} else if !utils.IsValidJDKDirectory(previous) {
+		utils.PrintWarning(fmt.Sprintf("Previous JAVA_HOME does not look like a valid JDK: %s", previous))
+		utils.PrintInfo("Restoring it anyway")
+	}
+	return previous, filepath.Base(previous), true
 }
 
-// setSystemEnvironmentVariable imposta una variabile d'ambiente di sistema nel registro Windows.
-//
-// Questa funzione modifica permanentemente le variabili d'ambiente a livello di sistema
-// attraverso il registro di Windows, rendendo le modifiche persistenti e disponibili
-// per tutti gli utenti e servizi del sistema.
-//
-// Registro Windows utilizzato:
-//
-//	Chiave: HKEY_LOCAL_MACHINE\SYSTEM\CurrentControlSet\Control\Session Manager\Environment
-//	Tipo: REG_SZ (String Value)
-//	Scopo: Variabili d'ambiente sistema globali
-//
-// Processo di modifica:
-// 1. **Apertura chiave registro**: Apre con permessi SET_VALUE per modifica
-// 2. **Impostazione valore**: Scrive la variabile come stringa nel registro
-// 3. **Chiusura chiave**: Cleanup automatico con defer per sicurezza
-// 4. **Broadcasting**: Notifica sistema del cambiamento (implementazione futura)
-//
-// Requisiti privilegi:
-//   - **Amministratore richiesto**: HKLM richiede privilegi elevated
-//   - **UAC necessario**: Su Windows Vista+ serve elevazione UAC
-//   - **Servizi Windows**: Accesso completo per modifiche sistema
-//
-// Persistenza e scope:
-//   - **Permanente**: Sopravvive a riavvii sistema
-//   - **Globale**: Disponibile per tutti gli utenti
-//   - **Servizi**: Accessibile ai servizi Windows
-//   - **Nuove sessioni**: Automaticamente disponibile in nuovi login
-//
-// Broadcasting (da implementare):
-//   - **WM_SETTINGCHANGE**: Messaggio Windows per notifica applicazioni
-//   - **HWND_BROADCAST**: Broadcast a tutte le finestre top-level
-//   - **Update live**: Alcune applicazioni aggiornano senza riavvio
-//
-// Parametri:
-//
-//	name string  - Nome variabile d'ambiente (es. "JAVA_HOME")
-//	value string - Valore da assegnare (es. "C:\Program Files\Java\jdk-17")
+// rememberPreviousJavaHome salva in configurazione il JAVA_HOME appena sostituito.
 //
-// Restituisce:
-//
-//	error - nil se successo, errore specifico se operazione fallisce
-//
-// Errori comuni:
-//   - **Permessi insufficienti**: Processo non eseguito come amministratore
-//   - **Chiave inaccessibile**: Registro corrotto o permessi negati
-//   - **Valore non impostabile**: Problemi scrittura registro o memoria
-//   - **Nome invalido**: Caratteri speciali non supportati nel nome
-//
-// Esempio di utilizzo:
-//
-//	err := setSystemEnvironmentVariable("JAVA_HOME", "C:\\jdk-17")
-//	if err != nil {
-//	    log.Printf("Failed to set JAVA_HOME: %v", err)
-//	}
-//
-// Note di sicurezza:
-//   - Non valida caratteri pericolosi nel nome/valore
-//   - Non previene sovrascrittura variabili sistema critiche
-//   - Responsabilità chiamante per validazione input
-func setSystemEnvironmentVariable(name, value string) error {
-	key, err := registry.OpenKey(registry.LOCAL_MACHINE,
-		`SYSTEM\CurrentControlSet\Control\Session Manager\Environment`,
-		registry.SET_VALUE)
-	if err != nil {
-		return fmt.Errorf("failed to open registry key: %w", err)
+// Chiamata dopo ogni 'jenvy use' riuscito: 'jenvy use -' scambia quindi i due JDK,
+// come 'cd -' con le directory. Un errore di salvataggio non annulla il cambio di JDK.
+func rememberPreviousJavaHome(previous, current string) {
+	if previous == "" || samePath(previous, current) {
+		return
 	}
-	defer key.Close()
-
-	err = key.SetStringValue(name, value)
+	cfg, err := utils.LoadConfigOrDefault()
 	if err != nil {
-		return fmt.Errorf("failed to set registry value: %w", err)
+		utils.PrintWarning(fmt.Sprintf("Could not record previous JDK: %v", err))
+		return
+	}
+	cfg.PreviousJavaHome = previous
+	if err := utils.SaveConfig(cfg); err != nil {
+		utils.PrintWarning(fmt.Sprintf("Could not record previous JDK: %v", err))
 	}
-
-	// Broadcast WM_SETTINGCHANGE message to notify applications
-	// This helps some applications pick up the new environment variable
-	utils.PrintInfo("Broadcasting environment change...")
-
-	return nil
 }
 
-// ensureJavaHomeInPath assicura che %JAVA_HOME%\bin sia presente nel PATH di sistema Windows.
-//
-// Questa funzione gestisce l'aggiornamento intelligente della variabile PATH di sistema
-// per includere la directory bin del JDK attivo, permettendo l'esecuzione diretta
-// di comandi Java da qualsiasi posizione nel prompt dei comandi.
-//
-// Processo di aggiornamento PATH:
-// 1. **Lettura PATH corrente**: Recupera valore attuale dal registro sistema
-// 2. **Parsing entries**: Suddivide PATH in singole directory separate da ";"
-// 3. **Controllo esistenza**: Verifica se %JAVA_HOME%\bin è già presente
-// 4. **Aggiunta intelligente**: Se mancante, aggiunge all'inizio del PATH
-// 5. **Scrittura registro**: Salva il nuovo PATH nel registro sistema
+// previewUseJDK mostra cosa farebbe 'jenvy use' senza modificare il registro ('use --dry-run').
 //
-// Registro Windows utilizzato:
-//
-//	Chiave: HKLM\SYSTEM\CurrentControlSet\Control\Session Manager\Environment
-//	Valore: "Path" (REG_EXPAND_SZ o REG_SZ)
-//	Permessi: QUERY_VALUE | SET_VALUE per lettura e modifica
-//
-// Gestione %JAVA_HOME%\bin:
-//   - **Variabile espandibile**: Usa %JAVA_HOME%\bin invece di path assoluto
-//   - **Posizione prioritaria**: Aggiunto all'inizio del PATH per precedenza
-//   - **Case-insensitive**: Confronto ignorando maiuscole/minuscole
-//   - **Trim whitespace**: Rimuove spazi accidentali nelle entries PATH
-//
-// Vantaggi utilizzo %JAVA_HOME%\bin:
-//   - **Dinamico**: Si aggiorna automaticamente quando JAVA_HOME cambia
-//   - **Portable**: Non hard-coded a path specifici
-//   - **Standard**: Convenzione comune per setup Java
-//   - **Manutenibile**: Un solo punto di aggiornamento (JAVA_HOME)
-//
-// Comportamento intelligente:
-//   - **Evita duplicati**: Non aggiunge se già presente nel PATH
-//   - **Priorità elevata**: Inserimento all'inizio per precedenza su altre versioni Java
-//   - **Preservazione PATH**: Mantiene tutte le altre entries esistenti
-//   - **Feedback utente**: Messaggi informativi su operazioni eseguite
+// Oltre alle modifiche previste (JAVA_HOME e %JAVA_HOME%\bin nel PATH di sistema),
+// ricostruisce il PATH che vedrà un nuovo terminale (PATH di sistema seguito da
+// quello utente, con il nuovo JAVA_HOME espanso) ed esegue su di esso l'analisi di
+// 'jenvy diagnose-path'. Se un altro java.exe precede il JDK scelto (es. la voce
+// Oracle javapath), avvisa che 'java -version' non cambierà finché il PATH non
+// viene corretto.
 //
 // Parametri:
 //
-//	Nessuno (opera su variabili d'ambiente sistema)
-//
-// Restituisce:
-//
-//	error - nil se successo o già presente, errore se modifica fallisce
-//
-// Messaggi output:
-//   - "[INFO] %JAVA_HOME%\bin is already in PATH" - se già configurato
-//   - "[SUCCESS] Added %JAVA_HOME%\bin to system PATH" - se aggiunto con successo
-//
-// Scenari di errore:
-//   - **Permessi insufficienti**: Richiede privilegi amministratore
-//   - **Registro inaccessibile**: Chiave sistema corrotta o bloccata
-//   - **PATH corrotto**: Valore PATH nel formato non riconosciuto
-//   - **Memoria insufficiente**: PATH troppo lungo per limiti Windows
-//
-// Esempio PATH risultante:
-//
-//	Prima:  "C:\Windows\System32;C:\Windows;C:\Program Files\Git\bin"
-//	Dopo:   "%JAVA_HOME%\bin;C:\Windows\System32;C:\Windows;C:\Program Files\Git\bin"
-func ensureJavaHomeInPath() error {
-	key, err := registry.OpenKey(registry.LOCAL_MACHINE,
-		`SYSTEM\CurrentControlSet\Control\Session Manager\Environment`,
-		registry.QUERY_VALUE|registry.SET_VALUE)
-	if err != nil {
-		return fmt.Errorf("failed to open registry key: %w", err)
-	}
-	defer key.Close()
-
-	// Read current PATH
-	currentPath, _, err := key.GetStringValue("Path")
-	if err != nil {
-		return fmt.Errorf("failed to read PATH: %w", err)
-	}
-
-	javaHomeBin := `%JAVA_HOME%\bin`
+//	version string - Nome del JDK da mostrare nei messaggi
+//	jdkPath string - Root del JDK che diventerebbe JAVA_HOME
+func previewUseJDK(version, jdkPath string) {
+	utils.PrintSection("[DRY RUN] JENVY USE")
+	fmt.Println()
 
-	// Check if %JAVA_HOME%\\bin is already in PATH
-	pathEntries := strings.Split(currentPath, ";")
-	for _, entry := range pathEntries {
-		if strings.EqualFold(strings.TrimSpace(entry), javaHomeBin) {
-			utils.PrintInfo("%JAVA_HOME%\\bin is already in PATH")
-			return nil
+	currentJavaHome, _ := readSystemEnvironmentVariable("JAVA_HOME")
+	newJavaHome := jdkPath
+	if utils.ActivationStyle() == utils.ActivationStyleJunction {
+		if link, err := utils.CurrentJDKLink(); err == nil {
+			newJavaHome = link
+			fmt.Printf("  Junction:  %s -> %s\n", link, jdkPath)
 		}
 	}
+	if currentJavaHome == "" {
+		currentJavaHome = "(not set)"
+	}
+	if samePath(currentJavaHome, newJavaHome) {
+		fmt.Printf("  JAVA_HOME: %s, unchanged\n", currentJavaHome)
+	} else {
+		fmt.Printf("  JAVA_HOME: %s -> %s\n", currentJavaHome, newJavaHome)
+	}
 
-	// Add %JAVA_HOME%\\bin to the beginning of PATH
-	newPath := javaHomeBin + ";" + currentPath
+	inPath, newPath := previewSystemPath(jdkPath)
+	if inPath {
+		fmt.Printf("  PATH:      %s already present, unchanged\n", javaHomeBinEntry)
+	} else {
+		fmt.Printf("  PATH:      %s would be added at the beginning of the system PATH\n", javaHomeBinEntry)
+	}
+	fmt.Println()
 
-	err = key.SetStringValue("Path", newPath)
-	if err != nil {
-		return fmt.Errorf("failed to update PATH: %w", err)
+	utils.PrintInfo("java on PATH after the change (new terminals):")
+	entries := utils.FindJavaOnPath(newPath)
+	if len(entries) == 0 {
+		utils.PrintWarning(fmt.Sprintf("No java executable would be found on PATH, check %s", filepath.Join(jdkPath, "bin")))
+		return
 	}
+	versionsDir, _ := utils.GetJenvyVersionsDirectory()
+	jdkBin := filepath.Join(jdkPath, "bin")
+	active, _ := printJavaPathEntries(entries, entries[0].Dir, jdkBin, versionsDir)
+	fmt.Println()
 
-	utils.PrintSuccess("Added %JAVA_HOME%\\bin to system PATH")
-	return nil
+	if samePath(active.Dir, jdkBin) {
+		utils.PrintSuccess(fmt.Sprintf("'java' would resolve to JDK %s", version))
+	} else {
+		utils.PrintWarning(fmt.Sprintf("'java' would still resolve to %s, shadowing JDK %s", active.Dir, version))
+		utils.PrintInfo("Setting JAVA_HOME won't change 'java -version' until that entry is moved after " + javaHomeBinEntry + " or removed from PATH")
+	}
+	utils.PrintInfo("Dry run: no changes were made")
 }
 
 // showAvailableJDKs mostra una lista delle installazioni JDK disponibili nel sistema.
@@ -591,14 +556,22 @@ func showAvailableJDKs() {
 		return
 	}
 
+	scheme := utils.InstallNamingScheme()
 	var jdks []string
 	for _, entry := range entries {
-		if entry.IsDir() && strings.HasPrefix(entry.Name(), "JDK-") {
-			version := strings.TrimPrefix(entry.Name(), "JDK-")
-			jdkPath := filepath.Join(versionsDir, entry.Name())
-			if utils.IsValidJDKDirectory(jdkPath) {
-				jdks = append(jdks, version)
+		if !entry.IsDir() {
+			continue
+		}
+		version, provider, ok := utils.ParseInstallDirName(entry.Name(), scheme)
+		if !ok {
+			continue
+		}
+		jdkPath := filepath.Join(versionsDir, entry.Name())
+		if _, ok := utils.ResolveJDKHome(jdkPath); ok {
+			if provider != "" {
+				version = fmt.Sprintf("%s (%s)", version, provider)
 			}
+			jdks = append(jdks, version)
 		}
 	}
 
@@ -613,34 +586,17 @@ func showAvailableJDKs() {
 	}
 }
 
-// testJavaInstallation testa se un'installazione Java è funzionante e accessibile.
+// testJavaInstallation esegue 'java -version' nel JDK appena attivato e ne controlla la versione.
 //
-// Questa funzione esegue controlli basilari per verificare che un'installazione JDK
-// sia configurata correttamente e l'eseguibile Java sia accessibile. Fornisce
-// feedback immediato all'utente sulla funzionalità dell'installazione appena attivata.
+// Fornisce un riscontro immediato sull'installazione attivata, invece di
+// scoprire il problema alla prima compilazione:
+// 1. **Esecuzione**: Lancia "java -version" con timeout (utils.JavaVersionTimeout)
+// 2. **Parsing**: Estrae la versione riportata (utils.ParseJavaVersionOutput)
+// 3. **Confronto**: Verifica che corrisponda alla versione nel nome della directory
 //
-// Controlli eseguiti:
-// 1. **Costruzione path**: Costruisce percorso completo a java.exe
-// 2. **Verifica esistenza**: Controlla che java.exe esista fisicamente
-// 3. **Test accessibilità**: Verifica che il file sia accessibile e leggibile
-// 4. **Feedback utente**: Informa sui risultati dei controlli
-//
-// Path testato:
-//
-//	{jdkPath}\bin\java.exe
-//	Esempio: "C:\Users\user\.jenvy\versions\JDK-17\bin\java.exe"
-//
-// Controlli limitati (per semplicità):
-//   - **Solo esistenza file**: Non esegue java.exe per test funzionalità
-//   - **No version check**: Non verifica che versione Java corrisponda
-//   - **No execution test**: Non testa "java -version" command
-//   - **No permissions**: Non verifica permessi esecuzione
-//
-// Razionale controlli limitati:
-//   - **Performance**: Evita overhead esecuzione processi
-//   - **Sicurezza**: Non esegue codice esterno automaticamente
-//   - **Semplicità**: Controllo base sufficiente per validazione immediata
-//   - **Reliability**: Meno dipendenze = meno punti di fallimento
+// Un JDK per un'altra architettura, un archivio estratto a metà o una directory
+// che contiene un JDK diverso da quello indicato dal nome vengono segnalati con
+// un errore o un avviso; l'attivazione resta comunque valida.
 //
 // Parametri:
 //
@@ -648,43 +604,58 @@ func showAvailableJDKs() {
 //
 // Output tipico successo:
 //
-//	Testing: C:\Users\user\.jenvy\versions\JDK-17\bin\java.exe -version
-//	[SUCCESS] Java executable found and accessible
-//	Java location: C:\Users\user\.jenvy\versions\JDK-17\bin\java.exe
+//	Testing: C:\Users\user\.jenvy\versions\JDK-17.0.9+9\bin\java.exe -version
+//	openjdk version "17.0.9" 2023-10-17
+//	[SUCCESS] Java 17.0.9 runs correctly
+//	Java location: C:\Users\user\.jenvy\versions\JDK-17.0.9+9\bin\java.exe
 //
 // Output tipico errore:
 //
-//	Testing: C:\Users\user\.jenvy\versions\JDK-17\bin\java.exe -version
-//	[ERROR] Java executable not found
-//
-// Miglioramenti futuri possibili:
-//   - Esecuzione effettiva "java -version" con cattura output
-//   - Parsing versione e verifica corrispondenza
-//   - Test compilazione semplice per validare javac
-//   - Controllo permessi esecuzione
-//   - Timeout per prevenire hang su installazioni corrotte
-//
-// Integrazione nel workflow:
-//   - Chiamata automatica dopo UseJDK() per feedback immediato
-//   - Validazione finale prima di considerare operazione completata
-//   - Troubleshooting helper per identificare problemi installazione
+//	Testing: C:\Users\user\.jenvy\versions\JDK-17.0.9+9\bin\java.exe -version
+//	[ERROR] java -version failed: ...
 func testJavaInstallation(jdkPath string) {
-	javaExe := filepath.Join(jdkPath, "bin", "java.exe")
-
-	// Test java -version command
+	javaExe := utils.JavaExecutablePath(jdkPath)
 	fmt.Printf("Testing: %s -version\n", javaExe)
 
-	// We can't easily run the command and capture output here without additional complexity
-	// Instead, we'll just verify the executable exists and is accessible
 	if _, err := os.Stat(javaExe); err != nil {
 		utils.PrintError("Java executable not found")
 		return
 	}
 
-	utils.PrintSuccess("Java executable found and accessible")
+	ctx, cancel := context.WithTimeout(context.Background(), utils.JavaVersionTimeout)
+	defer cancel()
+	reported, line, err := utils.RunJavaVersion(ctx, jdkPath)
+	if err != nil {
+		utils.PrintError(fmt.Sprintf("java -version failed: %v", err))
+		utils.PrintInfo("The JDK may be corrupted or built for another architecture: check it with 'jenvy verify', or download it again")
+		return
+	}
+	fmt.Println(line)
+
+	if expected, ok := expectedJDKVersion(jdkPath); ok && !utils.JavaVersionMatches(reported, expected) {
+		utils.PrintWarning(fmt.Sprintf("java -version reports %s, but the installation is named after %s", reported, expected))
+		utils.PrintInfo("The directory may contain a different JDK: download the intended version again with 'jenvy download'")
+	} else {
+		utils.PrintSuccess(fmt.Sprintf("Java %s runs correctly", reported))
+	}
 	fmt.Printf("Java location: %s\n", javaExe)
 }
 
+// expectedJDKVersion ricava dal nome della directory di installazione la versione attesa di un JDK.
+//
+// Con --no-flatten la root del JDK è annidata nella directory di installazione,
+// quindi se il nome di jdkPath non è riconosciuto viene provato quello superiore.
+// Senza un nome riconosciuto si ripiega su utils.JDKHomeVersion.
+func expectedJDKVersion(jdkPath string) (string, bool) {
+	scheme := utils.InstallNamingScheme()
+	for _, dir := range []string{jdkPath, filepath.Dir(jdkPath)} {
+		if version, _, ok := utils.ParseInstallDirName(filepath.Base(dir), scheme); ok {
+			return version, true
+		}
+	}
+	return utils.JDKHomeVersion(jdkPath)
+}
+
 // InitializeJenvyEnvironment configura l'ambiente iniziale per Jenvy durante l'installazione.
 //
 // Questa funzione gestisce il setup iniziale dell'ambiente Jenvy quando il tool viene
@@ -743,8 +714,10 @@ func testJavaInstallation(jdkPath string) {
 // Note per sviluppatori:
 //   - Non richiede JDK già installati per funzionare
 //   - Prepara solo l'ambiente, non installa JDK
+//   - Se è impostato un JDK predefinito installato ('jenvy default') lo attiva:
+//     JAVA_HOME di sistema da amministratore, altrimenti utente
 //   - Idempotente: sicuro chiamare multiple volte
-func InitializeJenvyEnvironment() {
+func InitializeJenvyEnvironment() error {
 	fmt.Println("🔧 Setting up Jenvy environment variables...")
 
 	// Check if running as administrator
@@ -756,85 +729,23 @@ func InitializeJenvyEnvironment() {
 		err := ensureJavaHomeInPath()
 		if err != nil {
 			utils.PrintError(fmt.Sprintf("Failed to initialize PATH: %v", err))
-			utils.PrintInfo("You may need to manually add %JAVA_HOME%\\bin to your PATH")
-		} else {
-			utils.PrintSuccess("Jenvy environment initialized")
+			utils.PrintInfo("You may need to manually add " + javaHomeBinEntry + " to your PATH")
+			return utils.ExitWith(registryExitCode(err), err)
 		}
+		utils.PrintSuccess("Jenvy environment initialized")
 	}
 
-	utils.PrintInfo("Use 'jenvy use <version>' to set your active JDK")
-}
-
-// isRunningAsAdmin verifica se il processo corrente ha privilegi di amministratore.
-//
-// Questa funzione implementa un controllo affidabile per determinare se l'applicazione
-// è in esecuzione con privilegi elevati, necessari per modificare le variabili
-// d'ambiente di sistema tramite il registro Windows.
-//
-// Metodo di verifica:
-//
-//	Tenta di aprire una chiave del registro che richiede privilegi amministratore
-//	per l'accesso in scrittura. Se l'operazione riesce, il processo ha privilegi admin.
-//
-// Chiave registro utilizzata per test:
-//
-//	HKEY_LOCAL_MACHINE\SYSTEM\CurrentControlSet\Control\Session Manager\Environment
-//	- **Criticità**: Chiave sistema per variabili d'ambiente globali
-//	- **Protezione**: Richiede privilegi elevated per SET_VALUE
-//	- **Affidabilità**: Controllo diretto sui permessi reali necessari
-//
-// Vantaggi di questo approccio:
-//   - **Test reale**: Verifica esattamente i permessi che servono per operazioni Jenvy
-//   - **Affidabile**: Non dipende da API che potrebbero cambiare
-//   - **Specifico**: Testa accesso alla specifica risorsa che useremo
-//   - **Immediato**: Fallisce velocemente se privilegi insufficienti
-//
-// Alternative non utilizzate:
-//   - **Token API**: Più complesso e dipendente da versioni Windows
-//   - **Gruppo Administrators**: Membership non garantisce privilegi attivi
-//   - **UAC API**: Overhead maggiore per controllo semplice
-//
-// Meccanismo:
-// 1. **Tentativo apertura**: Prova ad aprire chiave con permessi SET_VALUE
-// 2. **Gestione errore**: Se fallisce, assenza privilegi amministratore
-// 3. **Pulizia**: Chiude chiave immediatamente se apertura riuscita
-// 4. **Ritorno booleano**: true se privilegi presenti, false altrimenti
-//
-// Parametri:
-//
-//	Nessuno (controlla processo corrente)
-//
-// Restituisce:
-//
-//	bool - true se processo ha privilegi amministratore, false altrimenti
-//
-// Utilizzo tipico:
-//
-//	if !isRunningAsAdmin() {
-//	    // Richiedi elevazione UAC
-//	    requestAdminPrivileges()
-//	} else {
-//	    // Procedi con modifiche sistema
-//	}
-//
-// Scenari di utilizzo:
-//   - Prima di ogni modifica variabili d'ambiente sistema
-//   - Decisione se mostrare prompt UAC o errore
-//   - Validazione prerequisiti per operazioni privilegiate
-//   - Guida utente su come eseguire comando correttamente
-//
-// Limitazioni:
-//   - Non distingue tra diversi livelli di privilegi admin
-//   - Non rileva UAC disabilitato o policy gruppo
-//   - Test specifico per registro, potrebbe non coprire altri privilegi
-func isRunningAsAdmin() bool {
-	// Try to open a registry key that requires admin access
-	key, err := registry.OpenKey(registry.LOCAL_MACHINE,
-		`SYSTEM\CurrentControlSet\Control\Session Manager\Environment`,
-		registry.SET_VALUE)
-	if err != nil {
-		return false
+	// Su una macchina appena configurata il JDK predefinito ('jenvy default') diventa quello attivo
+	if target, ok := utils.ResolveAlias(utils.DefaultAlias); ok && isJDKInstalled(target) {
+		fmt.Println()
+		utils.PrintInfo(fmt.Sprintf("Activating the default JDK %s", target))
+		scope := "--global"
+		if !isRunningAsAdmin() {
+			scope = "--user"
+		}
+		return activateDefaultJDK(scope)
 	}
-	defer key.Close()
-	return true
+
+	utils.PrintInfo("Use 'jenvy use <version>' to set your active JDK")
+	return nil
 }
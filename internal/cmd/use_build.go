@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+
+	"jenvy/internal/utils"
+)
+
+// resolveBuildVersion sceglie il JDK installato richiesto dai file di build del progetto ('use --from-build').
+//
+// La versione major viene letta da pom.xml o build.gradle(.kts) nella directory
+// corrente (vedi utils.DetectBuildRequirement). Tra le installazioni di quella
+// major viene scelta la patch più recente. Se nessuna è installata, propone di
+// scaricarla con 'jenvy download <major>' e riprova.
+//
+// Restituisce:
+//
+//	string - Nome della directory di installazione scelta, utilizzabile da resolveInstalledJDK
+//	bool   - false se non è stato trovato alcun requisito o JDK (errore già mostrato)
+func resolveBuildVersion() (string, bool) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		utils.PrintError(fmt.Sprintf("Failed to determine current directory: %v", err))
+		return "", false
+	}
+	requirement, ok := utils.DetectBuildRequirement(cwd)
+	if !ok {
+		utils.PrintError("No Java version requirement found in pom.xml, build.gradle.kts or build.gradle")
+		utils.PrintInfo("Pass a version explicitly: jenvy use <version>")
+		return "", false
+	}
+	utils.PrintInfo(fmt.Sprintf("%s requires Java %d (%s)", filepath.Base(requirement.Source), requirement.Major, requirement.Field))
+
+	if installed, ok := newestInstalledMajor(requirement.Major); ok {
+		return installed, true
+	}
+
+	utils.PrintWarning(fmt.Sprintf("JDK %d is not installed", requirement.Major))
+	if !askConfirmation(fmt.Sprintf("[?] Download JDK %d now? (y/N): ", requirement.Major)) {
+		utils.PrintInfo(fmt.Sprintf("Install it later with 'jenvy download %d'", requirement.Major))
+		return "", false
+	}
+	if err := runJenvy("download", strconv.Itoa(requirement.Major)); err != nil {
+		utils.PrintError(fmt.Sprintf("Download failed: %v", err))
+		return "", false
+	}
+
+	installed, ok := newestInstalledMajor(requirement.Major)
+	if !ok {
+		utils.PrintError(fmt.Sprintf("JDK %d is still not installed", requirement.Major))
+	}
+	return installed, ok
+}
+
+// newestInstalledMajor restituisce la directory dell'installazione più recente di una major.
+func newestInstalledMajor(major int) (string, bool) {
+	paths, err := utils.FindJDKInstallationPaths(strconv.Itoa(major))
+	if err != nil {
+		return "", false
+	}
+
+	scheme := utils.InstallNamingScheme()
+	newest, newestVersion := "", ""
+	for _, path := range paths {
+		version, _, ok := utils.ParseInstallDirName(filepath.Base(path), scheme)
+		if installedMajor, _, _ := utils.ParseVersionNumber(version); !ok || installedMajor != major {
+			continue
+		}
+		if newest == "" || utils.CompareJavaVersions(version, newestVersion) > 0 {
+			newest, newestVersion = filepath.Base(path), version
+		}
+	}
+	return newest, newest != ""
+}
+
+// runJenvy esegue un altro comando jenvy nello stesso terminale e ne attende la fine.
+//
+// Rilanciare l'eseguibile, come fa requestAdminPrivileges, riusa il comando
+// completo (messaggi, conferme e flag) senza duplicarne la logica.
+func runJenvy(args ...string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locating jenvy executable: %w", err)
+	}
+	command := exec.Command(exe, args...)
+	command.Stdin, command.Stdout, command.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return command.Run()
+}
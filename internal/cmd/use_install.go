@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"jenvy/internal/utils"
+)
+
+// installOnUseEnabled indica se 'jenvy use' deve scaricare le versioni mancanti.
+//
+// L'opzione --install vale per la singola esecuzione; install-on-use in
+// configurazione la rende il comportamento predefinito.
+func installOnUseEnabled(flag bool) bool {
+	if flag {
+		return true
+	}
+	cfg, err := utils.LoadConfigOrDefault()
+	return err == nil && cfg.InstallOnUse
+}
+
+// isJDKInstalled indica, senza stampare nulla, se almeno un'installazione corrisponde alla versione.
+func isJDKInstalled(version string) bool {
+	matches, _ := utils.FindJDKInstallationPaths(version)
+	return len(matches) > 0
+}
+
+// isJDKInstalledFrom indica se la versione è installata da provider, nella variante richiesta.
+//
+// Il provider è quello dei metadati di installazione: con lo schema predefinito
+// "JDK-{version}" il nome della directory non lo riporta. Il nome deve inoltre
+// essere quello che 'download' darebbe alla release (vedi utils.VariantInstallDirName),
+// così un JRE o un JDK per un'altra architettura non valgono come il JDK richiesto.
+func isJDKInstalledFrom(version, provider string) bool {
+	matches, _ := utils.FindJDKInstallationPaths(version)
+	scheme := utils.InstallNamingScheme()
+	for _, path := range matches {
+		meta, err := utils.LoadInstallMetadata(path)
+		if err != nil || meta.Version == "" || !strings.EqualFold(meta.Provider, provider) {
+			continue
+		}
+		if filepath.Base(path) == utils.VariantInstallDirName(scheme, meta.Provider, meta.Version) {
+			return true
+		}
+	}
+	return false
+}
+
+// installJDKForUse scarica ed estrae dal provider predefinito una versione non installata.
+//
+// Chiude il ciclo tra 'use' e 'download' (vedi installJDKFrom). Senza assumeYes
+// chiede conferma. main non acquisisce il lock di ~/.jenvy per 'use': viene
+// acquisito qui, solo per il download e l'estrazione, così l'attivazione di una
+// versione già installata e l'attesa di sudo o UAC non bloccano gli altri processi.
+// Se nel frattempo un altro processo ha installato la versione, viene usata quella.
+//
+// Esempio di utilizzo:
+//
+//	jdkPath, err := installJDKForUse("21", false)
+func installJDKForUse(version string, assumeYes bool) (string, error) {
+	lock, err := utils.AcquireLock("use " + version)
+	if err != nil {
+		return "", utils.Fail(utils.ExitGeneric, err.Error())
+	}
+	defer lock.Release()
+
+	if isJDKInstalled(version) {
+		jdkPath, ok := resolveInstalledJDK(version)
+		if !ok {
+			return "", utils.ExitWith(utils.ExitNotFound, nil)
+		}
+		return jdkPath, nil
+	}
+	utils.PrintInfo(fmt.Sprintf("JDK %s is not installed", version))
+	return installJDKFrom(version, utils.DefaultProvider(), assumeYes)
+}
+
+// installJDKFrom risolve una versione presso un provider, quindi la scarica e la estrae.
+//
+// La release viene risolta come in 'jenvy download <version>' e installata con
+// installResolvedRelease, quindi con verifica del checksum, metadati e manifest.
+// Senza assumeYes chiede conferma. Usata da 'use --install' e 'jenvy install'.
+//
+// Parametri:
+//
+//	version string  - Versione richiesta, già normalizzata (es. "21", "17.0.9+9")
+//	provider string - Provider da cui scaricare (es. "adoptium")
+//	assumeYes bool  - true con --yes: nessuna conferma
+//
+// Restituisce:
+//
+//	string - JAVA_HOME della nuova installazione
+//	error  - Errore già mostrato, con il codice di uscita appropriato
+func installJDKFrom(version, provider string, assumeYes bool) (string, error) {
+	utils.PrintSearch(fmt.Sprintf("Resolving JDK %s from provider: %s", version, provider))
+
+	findRelease, err := fetchReleaseFinder(provider)
+	if err != nil {
+		failure := utils.Fail(utils.ExitNetwork, err.Error())
+		printProviderFailureHint(provider, err)
+		return "", failure
+	}
+	release := findRelease(version)
+	if release.URL == "" {
+		utils.PrintInfo(fmt.Sprintf("Use 'jenvy remote-list --provider=%s' to see the available versions", provider))
+		return "", utils.Fail(utils.ExitNotFound, fmt.Sprintf("JDK %s is not offered by %s", version, provider))
+	}
+	if release.Filename == "" {
+		release.Filename = fmt.Sprintf("openjdk-%s.tar.gz", release.Version)
+	}
+
+	if !assumeYes && !askConfirmation(fmt.Sprintf("[?] Download and install JDK %s from %s now? (y/N): ", release.Version, provider)) {
+		utils.PrintInfo("Download cancelled by user")
+		utils.PrintInfo(fmt.Sprintf("Use 'jenvy download %s' to install it later", version))
+		return "", utils.ExitWith(utils.ExitNotFound, fmt.Errorf("JDK %s not installed", version))
+	}
+
+	outputDir, err := getDefaultDownloadDir()
+	if err != nil {
+		return "", utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to determine download directory: %v", err))
+	}
+	scheme := utils.InstallNamingScheme()
+	// Con activation-style=junction si conserva la struttura nativa dell'archivio, come in 'download'
+	flatten := utils.ActivationStyle() != utils.ActivationStyleJunction
+	// Ctrl-C annulla il download invece di terminare il processo, così l'installazione a metà viene rimossa
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := installResolvedRelease(ctx, release, provider, outputDir, scheme, flatten, nil, "", nil); err != nil {
+		if ctx.Err() != nil {
+			fmt.Println()
+			utils.PrintWarning("Download interrupted: the incomplete installation has been removed")
+			return "", utils.ExitWith(utils.ExitInterrupted, errors.New("download interrupted"))
+		}
+		return "", utils.Fail(utils.ExitNetwork, fmt.Sprintf("JDK %s: %v", release.Version, err))
+	}
+
+	installDir := filepath.Join(outputDir, utils.VariantInstallDirName(scheme, provider, release.Version))
+	home, ok := utils.ResolveJDKHome(installDir)
+	if !ok {
+		return "", utils.Fail(utils.ExitExtraction, fmt.Sprintf("%s does not contain a valid JDK after extraction", installDir))
+	}
+	utils.PrintSuccess(fmt.Sprintf("JDK %s installed", release.Version))
+	return home, nil
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"jenvy/internal/utils"
+)
+
+// useJDKViaJunction attiva un JDK ripuntando la junction ~/.jenvy/current (activation-style=junction).
+//
+// JAVA_HOME resta fisso sulla junction, quindi cambiare JDK non richiede di
+// modificare il registro né privilegi amministratore:
+//  1. **Prima attivazione**: Se JAVA_HOME non punta ancora alla junction, richiede
+//     l'elevazione una sola volta per impostarlo e aggiungere %JAVA_HOME%\bin al PATH
+//  2. **Cambio JDK**: Sostituisce la junction con una nuova che punta al JDK scelto
+//  3. **Effetto immediato**: Anche i terminali già aperti vedono il nuovo JDK,
+//     perché JAVA_HOME non cambia
+//
+// Parametri:
+//
+//	version string - Nome del JDK da mostrare nei messaggi
+//	jdkPath string - Root del JDK a cui far puntare la junction
+func useJDKViaJunction(version, jdkPath string) error {
+	link, err := utils.CurrentJDKLink()
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Error getting user directory: %v", err))
+	}
+
+	javaHome, _ := readSystemEnvironmentVariable("JAVA_HOME")
+	needsRegistry := !samePath(javaHome, link)
+	if needsRegistry && !isRunningAsAdmin() {
+		utils.PrintInfo("Administrator privileges required once to point JAVA_HOME to " + link)
+		utils.PrintInfo("Requesting administrator privileges...")
+		if !requestAdminPrivileges() {
+			utils.PrintError("Failed to obtain administrator privileges")
+			utils.PrintInfo("You can run manually as Administrator")
+			return utils.ExitWith(utils.ExitPermission, errors.New("failed to obtain administrator privileges"))
+		}
+		return nil
+	}
+
+	// Il JDK sostituito è la destinazione della junction, oppure il JAVA_HOME
+	// impostato prima di passare allo stile junction
+	previous := utils.ResolveJavaHome(javaHome)
+	if target, err := os.Readlink(link); err == nil {
+		previous = target
+	}
+
+	if err := updateJunction(link, jdkPath); err != nil {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to update %s: %v", link, err))
+	}
+	rememberPreviousJavaHome(previous, jdkPath)
+
+	if needsRegistry {
+		if err := setSystemEnvironmentVariable("JAVA_HOME", link); err != nil {
+			utils.PrintError(fmt.Sprintf("Failed to set JAVA_HOME: %v", err))
+			utils.PrintInfo("Try running as Administrator")
+			return utils.ExitWith(registryExitCode(err), err)
+		}
+		if err := ensureJavaHomeInPath(); err != nil {
+			utils.PrintWarning(fmt.Sprintf("Failed to update PATH: %v", err))
+			utils.PrintInfo("You may need to add " + javaHomeBinEntry + " to your PATH manually")
+		}
+		utils.PrintSuccess(fmt.Sprintf("Set JAVA_HOME to %s", link))
+		utils.PrintInfo("Restart your terminal/IDE once to see the changes; later switches need no restart or admin rights")
+	}
+
+	utils.PrintSuccess(fmt.Sprintf("Switched %s to JDK %s", link, version))
+	utils.PrintInfo(fmt.Sprintf("%s -> %s", link, jdkPath))
+
+	fmt.Println()
+	utils.PrintInfo("Testing Java installation:")
+	testJavaInstallation(jdkPath)
+	return nil
+}
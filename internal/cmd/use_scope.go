@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"jenvy/internal/utils"
+)
+
+// useJDKForUser attiva un JDK impostando JAVA_HOME nell'ambiente dell'utente (HKCU).
+//
+// È lo scope --user di 'jenvy use': non richiede privilegi amministratore e, come
+// previsto da Windows, la JAVA_HOME utente nasconde quella di sistema per i nuovi
+// processi. %JAVA_HOME%\bin viene aggiunto anche al PATH utente, perché il PATH di
+// sistema può contenere un altro java.exe che lo precede (vedi 'jenvy diagnose-path').
+//
+// Parametri:
+//
+//	version string - Nome del JDK da mostrare nei messaggi
+//	jdkPath string - Root del JDK da usare come JAVA_HOME
+func useJDKForUser(version, jdkPath string) error {
+	previousJavaHome, _ := readUserEnvironmentVariable("JAVA_HOME")
+
+	if err := setUserEnvironmentVariable("JAVA_HOME", jdkPath); err != nil {
+		utils.PrintError(fmt.Sprintf("Failed to set user JAVA_HOME: %v", err))
+		return utils.ExitWith(registryExitCode(err), err)
+	}
+	rememberPreviousJavaHome(utils.ResolveJavaHome(previousJavaHome), jdkPath)
+
+	if err := ensureJavaHomeInUserPath(); err != nil {
+		utils.PrintWarning(fmt.Sprintf("Failed to update user PATH: %v", err))
+		utils.PrintInfo("You may need to add " + javaHomeBinEntry + " to your PATH manually")
+	}
+
+	utils.PrintSuccess(fmt.Sprintf("Set user JAVA_HOME to JDK %s", version))
+	utils.PrintInfo(fmt.Sprintf("JAVA_HOME = %s", jdkPath))
+	utils.PrintInfo("The user value overrides the system JAVA_HOME; restart your terminal/IDE to see the changes")
+
+	fmt.Println()
+	utils.PrintInfo("Testing Java installation:")
+	testJavaInstallation(jdkPath)
+	return nil
+}
+
+// useJDKForProject fissa il JDK del progetto scrivendo .jenvy-version nella directory corrente.
+//
+// È lo scope --local di 'jenvy use': non modifica il registro. Il file può essere
+// versionato con il progetto; 'jenvy use' senza versione e 'jenvy current' lo
+// cercano nella directory corrente e in quelle superiori.
+//
+// Parametri:
+//
+//	version string - Versione richiesta, salvata così come l'ha scritta l'utente
+//	jdkPath string - Root del JDK risolto (solo per i messaggi)
+func useJDKForProject(version, jdkPath string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to determine current directory: %v", err))
+	}
+	path, err := utils.WriteProjectVersionFile(cwd, version)
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, err.Error())
+	}
+	utils.PrintSuccess(fmt.Sprintf("Pinned JDK %s for this project", version))
+	utils.PrintInfo(fmt.Sprintf("%s -> %s", path, jdkPath))
+	utils.PrintInfo("Run 'jenvy use' without a version in this directory to activate it")
+	return nil
+}
+
+// resolveScopedVersion risolve il JDK di 'jenvy use' invocato senza versione.
+//
+// Applica la stessa precedenza di 'jenvy current' limitata allo scope che può
+// fornire una versione: il file .jenvy-version del progetto.
+//
+// Restituisce:
+//
+//	string - Versione letta dal file di progetto
+//	bool   - false se nessun file è stato trovato
+func resolveScopedVersion() (string, bool) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", false
+	}
+	project, ok := utils.FindProjectVersion(cwd)
+	if !ok {
+		return "", false
+	}
+	utils.PrintInfo(fmt.Sprintf("Using JDK %s from %s", project.Value, project.Source))
+	return project.Value, true
+}
+
+// ShowCurrent gestisce 'jenvy current': mostra quale JDK è attivo e quale scope lo determina.
+//
+// Gli scope vengono letti tutti e valutati con utils.EffectiveScope
+// (project > user > system). Per ogni scope viene mostrato il valore, così da
+// capire anche quali impostazioni sono nascoste da uno scope più specifico.
+// Se il JAVA_HOME del processo corrente differisce (es. shell di
+// 'jenvy use --temporary' o terminale aperto prima dell'ultimo 'jenvy use'), viene segnalato.
+// Seguono la versione del JDK effettivo, l'installazione gestita da jenvy a cui
+// corrisponde e il java.exe trovato per primo nel PATH, con un avviso se
+// quest'ultimo non appartiene al JDK effettivo (vedi resolveEffectiveJava).
+//
+// Output tipico:
+//
+//	  project  17                                  C:\work\app\.jenvy-version
+//	* user     C:\Users\Marco\.jenvy\versions\JDK-21  HKCU\Environment
+//	  system   C:\Users\Marco\.jenvy\versions\JDK-17  HKLM\...\Environment
+//
+//	  Version       21.0.5
+//	  Managed       JDK-21 (adoptium)
+//	  PATH java     C:\Users\Marco\.jenvy\versions\JDK-21\bin\java.exe
+//
+// Con --version-only stampa solo la versione del JDK effettivo (es. "17.0.9"),
+// senza colori né messaggi, e senza output esce con utils.ExitNotFound se nessun
+// JDK è attivo: pensato per gli script (vedi printEffectiveVersion).
+func ShowCurrent() error {
+	versionOnly := false
+	for _, arg := range os.Args[2:] {
+		if arg != "--version-only" {
+			utils.PrintUsage("Usage: jenvy current [--version-only]")
+			return utils.ExitWith(utils.ExitGeneric, nil)
+		}
+		versionOnly = true
+	}
+
+	var values []utils.ScopeValue
+	if cwd, err := os.Getwd(); err == nil {
+		if project, ok := utils.FindProjectVersion(cwd); ok {
+			values = append(values, project)
+		}
+	}
+	if userHome, ok := readUserEnvironmentVariable("JAVA_HOME"); ok {
+		values = append(values, utils.ScopeValue{Scope: utils.ScopeUser, Value: utils.ResolveJavaHome(userHome), Source: userEnvironmentSource()})
+	}
+	if systemHome, ok := readSystemEnvironmentVariable("JAVA_HOME"); ok {
+		values = append(values, utils.ScopeValue{Scope: utils.ScopeSystem, Value: utils.ResolveJavaHome(systemHome), Source: systemEnvironmentSource()})
+	}
+
+	effective, ok := utils.EffectiveScope(values...)
+	if versionOnly {
+		return printEffectiveVersion(effective, ok)
+	}
+	if !ok {
+		utils.PrintWarning("No JDK is active in any scope")
+		utils.PrintInfo("Activate one with 'jenvy use <version>' (system), '--user' or '--local' (project)")
+		return utils.ExitWith(utils.ExitNotFound, errors.New("no active JDK"))
+	}
+
+	utils.PrintSection("[CURRENT] ACTIVE JDK")
+	for _, scope := range []string{utils.ScopeProject, utils.ScopeUser, utils.ScopeSystem} {
+		value, source := "-", ""
+		for _, v := range values {
+			if v.Scope == scope && v.Value != "" {
+				value, source = v.Value, v.Source
+			}
+		}
+		marker := " "
+		if scope == effective.Scope {
+			marker = utils.ColorText("*", utils.Green)
+		}
+		fmt.Printf("%s %-8s %-45s %s\n", marker, scope, value, source)
+	}
+	fmt.Println()
+
+	switch effective.Scope {
+	case utils.ScopeProject:
+		utils.PrintInfo(fmt.Sprintf("Effective: JDK %s, pinned by %s", effective.Value, effective.Source))
+		utils.PrintInfo("Run 'jenvy use' in this directory to activate it")
+	case utils.ScopeUser:
+		utils.PrintInfo(fmt.Sprintf("Effective: %s (user JAVA_HOME overrides the system value)", effective.Value))
+	default:
+		utils.PrintInfo(fmt.Sprintf("Effective: %s (system JAVA_HOME, no user or project override)", effective.Value))
+	}
+
+	// Versione, installazione gestita e java effettivo sul PATH (stessa catena di 'jenvy resolve')
+	res := resolveEffectiveJava()
+	if res.IntendedHome != "" {
+		version, _ := utils.JDKHomeVersion(res.IntendedHome)
+		printResolveStep("Version", valueOrDash(version))
+		printResolveStep("Managed", managedInstallLabel(res.IntendedHome))
+	}
+	printResolveStep("PATH java", valueOrDash(res.PathJava))
+	fmt.Println()
+
+	staleTerminal := false
+	if processHome := os.Getenv("JAVA_HOME"); processHome != "" && effective.Scope != utils.ScopeProject &&
+		!samePath(utils.ResolveJavaHome(processHome), effective.Value) {
+		utils.PrintWarning(fmt.Sprintf("This terminal still uses JAVA_HOME=%s: open a new terminal to pick up the change", processHome))
+		staleTerminal = true
+	}
+	if res.Mismatch != "" && !staleTerminal {
+		utils.PrintWarning(res.Mismatch)
+		if res.Scope != utils.ScopeProject {
+			utils.PrintInfo("Run 'jenvy diagnose-path' to see which PATH entry shadows it")
+		}
+	}
+	return nil
+}
+
+// managedInstallLabel descrive l'installazione gestita da jenvy a cui appartiene un JAVA_HOME.
+//
+// Riconosce sia le directory in ~/.jenvy/versions (anche con la root del JDK
+// annidata, per gli archivi non appiattiti) sia quelle registrate con
+// external-dirs. Restituisce "no (not installed by jenvy)" per gli altri JDK.
+func managedInstallLabel(home string) string {
+	if versionsDir, err := utils.GetJenvyVersionsDirectory(); err == nil && isPathInside(home, versionsDir) {
+		if rel, err := filepath.Rel(versionsDir, home); err == nil && rel != "." {
+			name := strings.Split(rel, string(filepath.Separator))[0]
+			if meta, err := utils.LoadInstallMetadata(filepath.Join(versionsDir, name)); err == nil && meta.Provider != "" {
+				return fmt.Sprintf("%s (%s)", name, meta.Provider)
+			}
+			return name
+		}
+	}
+	for _, dir := range utils.ExternalInstallDirs() {
+		if isPathInside(home, dir) {
+			return fmt.Sprintf("%s (external)", filepath.Base(dir))
+		}
+	}
+	return "no (not installed by jenvy)"
+}
+
+// printEffectiveVersion stampa solo la versione del JDK effettivo ('jenvy current --version-only').
+//
+// Il valore dello scope project è una versione da risolvere tra quelle
+// installate; quelli user e system sono già un JAVA_HOME. In caso di errore non
+// stampa nulla, così che $(jenvy current --version-only) resti vuoto.
+//
+// Restituisce:
+//
+//	error - utils.ExitNotFound se nessun JDK è attivo o quello fissato dal progetto non è installato
+func printEffectiveVersion(effective utils.ScopeValue, ok bool) error {
+	if !ok {
+		return utils.ExitWith(utils.ExitNotFound, errors.New("no active JDK"))
+	}
+
+	home := effective.Value
+	if effective.Scope == utils.ScopeProject {
+		version, err := utils.NormalizeVersionInput(effective.Value)
+		if err != nil {
+			return utils.ExitWith(utils.ExitNotFound, err)
+		}
+		matches, _ := utils.FindJDKInstallationPaths(version)
+		if len(matches) != 1 {
+			return utils.ExitWith(utils.ExitNotFound, fmt.Errorf("JDK %s pinned by %s is not installed or ambiguous", effective.Value, effective.Source))
+		}
+		resolved, valid := utils.ResolveJDKHome(matches[0])
+		if !valid {
+			return utils.ExitWith(utils.ExitNotFound, fmt.Errorf("%s is not a valid JDK", matches[0]))
+		}
+		home = resolved
+	}
+
+	version, found := utils.JDKHomeVersion(home)
+	if !found {
+		return utils.ExitWith(utils.ExitNotFound, fmt.Errorf("cannot determine the version of %s", home))
+	}
+	fmt.Println(version)
+	return nil
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"jenvy/internal/utils"
+)
+
+// useSessionJDK stampa i comandi che attivano un JDK solo nella shell che li esegue.
+//
+// È la modalità --session di 'jenvy use': a differenza di --temporary non avvia
+// una shell figlia, ma lascia che sia la shell corrente (o uno script di CI) a
+// valutare l'output. Nessuna modifica al registro, nessun privilegio richiesto.
+// Su out vengono scritti soltanto i comandi: UseJDK dirotta su stderr tutti gli
+// altri messaggi, così un errore non finisce mai dentro eval.
+//
+// Parametri:
+//
+//	out io.Writer   - Stdout originale del processo
+//	version string  - Versione richiesta (solo per i messaggi)
+//	jdkPath string  - Root del JDK da attivare
+//	shell string    - Valore di --shell; vuoto = shell da cui è stato lanciato jenvy, altrimenti cmd
+//
+// Esempio di utilizzo:
+//
+//	jenvy use 21 --session --shell=powershell | Out-String | Invoke-Expression
+//	eval "$(jenvy use 21 --session --shell=bash)"
+//	for /f "delims=" %i in ('jenvy use 21 --session --shell=cmd') do %i
+func useSessionJDK(out io.Writer, version, jdkPath, shell string) error {
+	if shell == "" {
+		shell = utils.DetectParentShell()
+		if shell == "" {
+			shell = "cmd"
+		}
+	}
+	lines, err := utils.SessionEnvStatements(shell, jdkPath, os.Getenv("PATH"))
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, err.Error())
+	}
+	for _, line := range lines {
+		fmt.Fprintln(out, line)
+	}
+	utils.PrintInfo(fmt.Sprintf("JDK %s for this %s session only (evaluate the output to apply it)", version, shell))
+	return nil
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"jenvy/internal/utils"
+)
+
+// useTemporaryJDK attiva un JDK solo per il processo corrente e per una shell figlia.
+//
+// Windows non dispone di variabili d'ambiente con scope "sessione di login": le uniche
+// persistenti sono quelle di sistema (HKLM) e utente (HKCU). L'alternativa più vicina è
+// modificare l'environment block del processo tramite SetEnvironmentVariableW; ogni
+// processo avviato da qui lo eredita. Per rendere la modalità utile da terminale,
+// 'jenvy use --temporary' avvia quindi una nuova shell interattiva con JAVA_HOME e PATH
+// aggiornati: il JDK resta attivo finché non si esce da quella shell ('exit').
+//
+// Semantica:
+//   - **Nessun privilegio richiesto**: Il registro non viene modificato
+//   - **Nessuna persistenza**: Chiusa la shell figlia, l'ambiente originale è ripristinato
+//   - **Ereditarietà**: Tutti i processi avviati dalla shell figlia vedono il JDK
+//   - **Shell padre invariata**: Il terminale da cui si lancia il comando non cambia
+//
+// Parametri:
+//
+//	version string  - Versione richiesta dall'utente (solo per i messaggi)
+//	jdkPath string  - Root del JDK da attivare
+//	shell string    - Shell da avviare ("cmd", "powershell", "pwsh", "bash" o percorso);
+//	                  vuoto = la shell da cui è stato lanciato jenvy, altrimenti %ComSpec% ($SHELL su Linux e macOS)
+//
+// Esempio di utilizzo:
+//
+//	jenvy use 17 --temporary                   # Nuova shell dello stesso tipo con JDK 17
+//	jenvy use 21 --temporary --shell=pwsh      # Nuova PowerShell 7 con JDK 21
+func useTemporaryJDK(version, jdkPath, shell string) error {
+	binDir := filepath.Join(jdkPath, "bin")
+	path := binDir + string(os.PathListSeparator) + os.Getenv("PATH")
+
+	if err := setProcessEnvironmentVariable("JAVA_HOME", jdkPath); err != nil {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to set JAVA_HOME for this session: %v", err))
+	}
+	if err := setProcessEnvironmentVariable("PATH", path); err != nil {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to update PATH for this session: %v", err))
+	}
+
+	shellExe := resolveTemporaryShell(shell)
+	utils.PrintSuccess(fmt.Sprintf("JDK %s active for this session only (no permanent changes)", version))
+	utils.PrintInfo(fmt.Sprintf("JAVA_HOME = %s", jdkPath))
+	utils.PrintInfo(fmt.Sprintf("Starting %s - type 'exit' to return to the previous environment", filepath.Base(shellExe)))
+	fmt.Println()
+
+	// Il processo figlio eredita l'environment block appena modificato
+	child := exec.Command(shellExe)
+	child.Stdin = os.Stdin
+	child.Stdout = os.Stdout
+	child.Stderr = os.Stderr
+	if err := child.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to start shell '%s': %v", shellExe, err))
+		}
+	}
+
+	fmt.Println()
+	utils.PrintInfo(fmt.Sprintf("Left temporary JDK %s session", version))
+	return nil
+}
+
+// resolveTemporaryShell determina l'eseguibile della shell da avviare per --temporary.
+func resolveTemporaryShell(shell string) string {
+	switch strings.ToLower(shell) {
+	case "":
+		// Di default riapre la stessa shell da cui è stato lanciato jenvy
+		if detected := utils.DetectParentShell(); detected != "" && detected != "cmd" {
+			return resolveTemporaryShell(detected)
+		}
+		return defaultTemporaryShell()
+	case "cmd", "bash", "zsh", "powershell", "pwsh":
+		return strings.ToLower(shell) + executableSuffix
+	default:
+		return shell
+	}
+}
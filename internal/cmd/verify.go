@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"jenvy/internal/utils"
+)
+
+// recordInstallManifest calcola e salva l'hash del manifest di un'installazione appena estratta.
+//
+// Aggiorna .jenvy-meta.json preservando i dati di origine scritti da 'jenvy download';
+// se il file non esiste (archivio copiato a mano) ne crea uno con la sola data e hash.
+// Un errore non blocca l'installazione: il JDK risulterà "unverifiable" in 'jenvy verify'.
+func recordInstallManifest(installDir string) {
+	meta, err := utils.LoadInstallMetadata(installDir)
+	if err != nil {
+		meta = &utils.InstallMetadata{}
+	}
+	if meta.InstalledAt == "" {
+		meta.InstalledAt = time.Now().Format(time.RFC3339)
+	}
+
+	hash, err := utils.ComputeManifestHash(installDir)
+	if err != nil {
+		utils.PrintWarning(fmt.Sprintf("Could not compute installation manifest: %v", err))
+		return
+	}
+	meta.ManifestHash = hash
+
+	if err := utils.SaveInstallMetadata(installDir, meta); err != nil {
+		utils.PrintWarning(fmt.Sprintf("Could not save installation metadata: %v", err))
+	}
+}
+
+// VerifyInstallations gestisce il comando 'jenvy verify' per controllare l'integrità dei JDK.
+//
+// Per ogni installazione ricalcola l'hash del manifest dei file (vedi
+// utils.ComputeManifestHash) e lo confronta con quello registrato in
+// .jenvy-meta.json al momento dell'estrazione.
+//
+// Esiti possibili:
+//   - **OK**: I file coincidono con quelli installati
+//   - **MODIFIED**: Almeno un file è stato aggiunto, rimosso o modificato
+//   - **UNVERIFIABLE**: Nessun manifest registrato (JDK installato con versioni precedenti)
+//   - **NOT EXTRACTED**: È presente solo l'archivio
+//
+// Sintassi:
+//
+//	jenvy verify --all        # Verifica tutte le installazioni
+//	jenvy verify 17           # Verifica una singola installazione
+func VerifyInstallations() error {
+	positional, flags := utils.SplitArgs(os.Args[2:])
+	all := slices.Contains(flags, "--all")
+	if len(positional) == 0 && !all {
+		utils.PrintUsage("Usage: jenvy verify <version> | --all")
+		return utils.ExitWith(utils.ExitGeneric, nil)
+	}
+
+	versionsDir, err := utils.GetJenvyVersionsDirectory()
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to access Jenvy directory: %v", err))
+	}
+
+	var targets []installationScan
+	if all {
+		if len(positional) > 0 {
+			return utils.Fail(utils.ExitGeneric, "--all cannot be combined with a version")
+		}
+		scans, err := scanInstallations(versionsDir, false)
+		if err != nil {
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to read versions directory: %v", err))
+		}
+		targets = scans
+	} else {
+		installDir, err := utils.FindSingleJDKInstallation(positional[0])
+		if err != nil {
+			return utils.Fail(utils.ExitNotFound, fmt.Sprintf("JDK version %s not found: %v", positional[0], err))
+		}
+		scan := installationScan{Name: filepath.Base(installDir), Path: installDir}
+		scan.JDKHome, _ = utils.ResolveJDKHome(installDir)
+		targets = append(targets, scan)
+	}
+
+	if len(targets) == 0 {
+		utils.PrintInfo("No JDK installations found")
+		return nil
+	}
+
+	utils.PrintInfo(fmt.Sprintf("Verifying %d installation(s)...", len(targets)))
+	counts := make(map[string]int)
+	for _, target := range targets {
+		status, detail := verifyInstallation(target)
+		counts[status]++
+
+		color := utils.Green
+		switch status {
+		case "MODIFIED":
+			color = utils.Red
+		case "UNVERIFIABLE", "NOT EXTRACTED":
+			color = utils.Yellow
+		}
+		fmt.Printf("  %s %s", utils.ColorText(fmt.Sprintf("%-14s", status), color), target.Name)
+		if detail != "" {
+			fmt.Printf(" (%s)", detail)
+		}
+		fmt.Println()
+	}
+
+	fmt.Println()
+	summary := fmt.Sprintf("%d ok, %d modified, %d unverifiable", counts["OK"], counts["MODIFIED"], counts["UNVERIFIABLE"])
+	if counts["MODIFIED"] > 0 {
+		utils.PrintWarning(summary)
+		utils.PrintInfo("Modified JDKs may have been tampered with: remove and download them again")
+		return utils.ExitWith(utils.ExitExtraction, fmt.Errorf("%d installation(s) modified", counts["MODIFIED"]))
+	}
+	utils.PrintSuccess(summary)
+	return nil
+}
+
+// verifyInstallation confronta l'hash attuale di un'installazione con quello registrato.
+func verifyInstallation(target installationScan) (status, detail string) {
+	if target.JDKHome == "" {
+		return "NOT EXTRACTED", "run 'jenvy extract " + target.Name + "'"
+	}
+
+	meta, err := utils.LoadInstallMetadata(target.Path)
+	if err != nil || meta.ManifestHash == "" {
+		return "UNVERIFIABLE", "no manifest recorded at install time"
+	}
+
+	hash, err := utils.ComputeManifestHash(target.Path)
+	if err != nil {
+		return "MODIFIED", fmt.Sprintf("cannot read files: %v", err)
+	}
+	if hash != meta.ManifestHash {
+		return "MODIFIED", "files changed since installation"
+	}
+	return "OK", ""
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"jenvy/internal/utils"
+)
+
+// versionArgument valida la versione passata da riga di comando (utils.NormalizeVersionInput).
+//
+// Il nome esatto di una directory in versionsDir (es. "azul-jdk-17.0.8") resta
+// invariato, così 'use', 'remove' ed 'extract' continuano ad accettarlo anche
+// quando non è una versione. Con versionsDir vuoto ('download') si valida sempre.
+// Un alias definito con 'jenvy alias' viene sostituito dalla sua versione.
+//
+// Parametri:
+//
+//	arg string         - Argomento digitato dall'utente
+//	versionsDir string - Directory delle installazioni ("" per non accettare nomi di directory)
+//
+// Restituisce:
+//
+//	string - Versione normalizzata o nome di directory
+//	error  - utils.ExitGeneric se l'input non è valido (messaggio già mostrato)
+func versionArgument(arg, versionsDir string) (string, error) {
+	if isInstallDirName(arg, versionsDir) {
+		return arg, nil
+	}
+	if target, ok := utils.ResolveAlias(arg); ok {
+		if isInstallDirName(target, versionsDir) {
+			return target, nil
+		}
+		arg = target
+	}
+
+	version, err := utils.NormalizeVersionInput(arg)
+	if err != nil {
+		return "", utils.Fail(utils.ExitGeneric, err.Error())
+	}
+	return version, nil
+}
+
+// isInstallDirName indica se name è il nome esatto di una directory in versionsDir (falso con versionsDir vuoto).
+func isInstallDirName(name, versionsDir string) bool {
+	if versionsDir == "" {
+		return false
+	}
+	dir := filepath.Join(versionsDir, name)
+	info, err := os.Stat(dir)
+	return err == nil && info.IsDir() && filepath.Dir(dir) == filepath.Clean(versionsDir)
+}
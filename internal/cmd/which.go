@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"jenvy/internal/utils"
+)
+
+// WhichJDK stampa il percorso del launcher java dei JDK installati.
+//
+// Pensato come primitiva per script: l'output contiene solo percorsi, uno per
+// riga, senza colori né messaggi informativi (gli errori restano su stdout come
+// negli altri comandi, ma il codice di uscita li segnala).
+//
+// Modalità:
+//   - **which <version>**: Launcher del JDK risolto come in 'jenvy use <version>'
+//   - **which --all**: Launcher di ogni installazione valida, in ordine di versione crescente
+//   - **--with-version**: Con --all, antepone a ogni percorso la versione e un tab
+//   - **which <version> --version-only**: Versione del JDK risolto (es. "17.0.9") invece del percorso
+//
+// Esempio di utilizzo:
+//
+//	jenvy which 17
+//	jenvy which 17 --version-only
+//	jenvy which --all --with-version
+//	for /f "tokens=*" %j in ('jenvy which --all') do "%j" -version
+func WhichJDK() error {
+	all, withVersion, versionOnly := false, false, false
+	positional, flags := utils.SplitArgs(os.Args[2:])
+	for _, arg := range flags {
+		switch arg {
+		case "--all":
+			all = true
+		case "--with-version":
+			withVersion = true
+		case "--version-only":
+			versionOnly = true
+		default:
+			return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Unknown option for which: %s", arg))
+		}
+	}
+
+	validArgs := len(positional) == 1 && !all && !withVersion
+	if all {
+		validArgs = len(positional) == 0 && !versionOnly
+	}
+	if !validArgs {
+		utils.PrintUsage("Usage: jenvy which <version> [--version-only] | jenvy which --all [--with-version]")
+		return utils.ExitWith(utils.ExitGeneric, nil)
+	}
+
+	if !all {
+		versionsDir, _ := utils.GetJenvyVersionsDirectory()
+		version, err := versionArgument(positional[0], versionsDir)
+		if err != nil {
+			return err
+		}
+		jdkPath, ok := resolveInstalledJDK(version)
+		if !ok {
+			return utils.ExitWith(utils.ExitNotFound, nil)
+		}
+		if versionOnly {
+			installed, found := utils.JDKHomeVersion(jdkPath)
+			if !found {
+				return utils.Fail(utils.ExitNotFound, fmt.Sprintf("Cannot determine the version of %s", jdkPath))
+			}
+			fmt.Println(installed)
+			return nil
+		}
+		fmt.Println(utils.JavaExecutablePath(jdkPath))
+		return nil
+	}
+
+	versionsDir, err := utils.GetJenvyVersionsDirectory()
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to access Jenvy directory: %v", err))
+	}
+	scans, err := scanInstallations(versionsDir, false)
+	if err != nil && !os.IsNotExist(err) {
+		return utils.Fail(utils.ExitGeneric, fmt.Sprintf("Failed to read versions directory: %v", err))
+	}
+
+	type launcher struct{ Version, Path string }
+	var launchers []launcher
+	for _, scan := range scans {
+		if scan.JDKHome == "" {
+			continue
+		}
+		javaExe := utils.JavaExecutablePath(scan.JDKHome)
+		if _, err := os.Stat(javaExe); err != nil {
+			continue
+		}
+		version := extractVersionFromDirName(scan.Name)
+		if version == "" {
+			version = scan.Name
+		}
+		launchers = append(launchers, launcher{Version: version, Path: javaExe})
+	}
+	if len(launchers) == 0 {
+		return utils.Fail(utils.ExitNotFound, "No valid JDK installations found")
+	}
+
+	sort.SliceStable(launchers, func(i, j int) bool {
+		return utils.CompareJavaVersions(launchers[i].Version, launchers[j].Version) < 0
+	})
+	for _, l := range launchers {
+		if withVersion {
+			fmt.Printf("%s\t%s\n", l.Version, l.Path)
+		} else {
+			fmt.Println(l.Path)
+		}
+	}
+	return nil
+}
@@ -1,10 +1,12 @@
 package adoptium
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+
+	"jenvy/internal/utils"
 )
 
 type AdoptiumResponse struct {
@@ -12,30 +14,44 @@ type AdoptiumResponse struct {
         OS      string `json:"os"`
         Arch    string `json:"architecture"`
         Package struct {
-            Link string `json:"link"`
+            Link     string `json:"link"`
+            Checksum string `json:"checksum"` // SHA-256 dell'archivio
         } `json:"package"`
     } `json:"binaries"`
 
     VersionData struct {
         OpenJDKVersion string `json:"openjdk_version"`
     } `json:"version_data"`
+
+    // Data di pubblicazione della release (RFC3339), usata da remote-list --since
+    Timestamp string `json:"timestamp"`
+
+    // Tipo di release: ga oppure ea per le build early-access (--ea)
+    ReleaseType string `json:"release_type"`
+
+    // Versione già scomposta, calcolata al caricamento dell'elenco
+    utils.ReleaseVersion `json:"-"`
 }
 
 
+// IsEarlyAccess indica se la release è una build early-access.
+func (r AdoptiumResponse) IsEarlyAccess() bool {
+    return r.ReleaseType == "ea"
+}
+
 func GetJDKList() ([]AdoptiumResponse, error) {
-    url := "https://api.adoptium.net/v3/assets/feature_releases/21/ga?architecture=x64&os=windows&image_type=jdk"
+    url := "https://api.adoptium.net/v3/assets/feature_releases/21/ga?architecture=" + utils.TargetArch() + "&os=" + apiOS() + "&image_type=" + utils.ImageType()
 
-    resp, err := http.Get(url)
+    body, _, err := utils.CachedGet(url)
     if err != nil {
         return nil, err
     }
-    defer resp.Body.Close()
 
-    body, _ := io.ReadAll(resp.Body)
     var data []AdoptiumResponse
     if err := json.Unmarshal(body, &data); err != nil {
         return nil, err
     }
+    parseReleaseVersions(data)
 
     return data, nil
 }
@@ -46,20 +62,129 @@ func GetAllJDKs() ([]AdoptiumResponse, error) {
     }
 
     var all []AdoptiumResponse
+    var lastErr error
+    fetched := 0
     for _, v := range versions {
-        url := fmt.Sprintf("https://api.adoptium.net/v3/assets/feature_releases/%s/ga?architecture=x64&os=windows&image_type=jdk", v)
-        resp, err := http.Get(url)
+        body, err := fetchFeatureReleases(v)
         if err != nil {
+            lastErr = err
             continue
         }
-        body, _ := io.ReadAll(resp.Body)
-        resp.Body.Close()
+        fetched++
 
         var data []AdoptiumResponse
         if err := json.Unmarshal(body, &data); err == nil {
             all = append(all, data...)
         }
     }
+    // Nessuna risposta: è un problema di rete, non di schema
+    if fetched == 0 && lastErr != nil {
+        return nil, lastErr
+    }
+    if utils.IncludeEarlyAccess() {
+        ea, err := getEarlyAccessJDKs()
+        if err != nil {
+            return nil, fmt.Errorf("early-access builds: %w", err)
+        }
+        all = append(all, ea...)
+    }
+    parseReleaseVersions(all)
+
+    usable := 0
+    for _, release := range all {
+        if release.Major > 0 && len(release.Binaries) > 0 && release.Binaries[0].Package.Link != "" {
+            usable++
+        }
+    }
+    if err := utils.CheckUsableReleases(len(all), usable); err != nil {
+        return nil, err
+    }
+    return all, nil
+}
+
+// apiOS converte il sistema richiesto (--os) nel nome usato dall'API Adoptium.
+func apiOS() string {
+    if utils.TargetOS() == utils.OSAlpine {
+        return "alpine-linux"
+    }
+    return utils.TargetOS()
+}
+
+// fetchFeatureReleases scarica il JSON delle release GA di una major (JDK o JRE, vedi --type, --arch e --os).
+func fetchFeatureReleases(version string) ([]byte, error) {
+    url := fmt.Sprintf("https://api.adoptium.net/v3/assets/feature_releases/%s/ga?architecture=%s&os=%s&image_type=%s", version, utils.TargetArch(), apiOS(), utils.ImageType())
+    body, _, err := utils.CachedGet(url)
+    return body, err
+}
+
+// getEarlyAccessJDKs scarica le build EA (nightly) delle major non ancora rilasciate.
+//
+// Adoptium restituisce per ogni major le build più recenti; le major senza
+// build Windows pubblicate vengono ignorate.
+func getEarlyAccessJDKs() ([]AdoptiumResponse, error) {
+    versions, err := GetEarlyAccessVersions()
+    if err != nil {
+        return nil, err
+    }
+    var all []AdoptiumResponse
+    for _, v := range versions {
+        url := fmt.Sprintf("https://api.adoptium.net/v3/assets/feature_releases/%s/ea?architecture=%s&os=%s&image_type=%s", v, utils.TargetArch(), apiOS(), utils.ImageType())
+        body, status, err := utils.CachedGet(url)
+        if err != nil {
+            return nil, err
+        }
+        if status != http.StatusOK {
+            continue // 404: nessuna build EA per questa major
+        }
+        var data []AdoptiumResponse
+        if err := json.Unmarshal(body, &data); err != nil {
+            return nil, fmt.Errorf("JDK %s EA: %w", v, err)
+        }
+        for i := range data {
+            data[i].ReleaseType = "ea"
+        }
+        all = append(all, data...)
+    }
     return all, nil
 }
 
+// GetRawReleases restituisce le risposte dell'API Adoptium così come inviate ('remote-list --raw').
+//
+// Adoptium risponde con un documento per ogni major, quindi il risultato è un
+// oggetto JSON che associa ogni major alla relativa risposta, non modificata.
+// Una risposta che non è JSON valido (es. una pagina di errore) viene inclusa
+// come stringa.
+func GetRawReleases() ([]byte, error) {
+    versions, err := GetAvailableVersions()
+    if err != nil {
+        return nil, err
+    }
+
+    // Composto a mano: json.Marshal riformatterebbe le risposte
+    var raw bytes.Buffer
+    raw.WriteString("{")
+    for i, v := range versions {
+        body, err := fetchFeatureReleases(v)
+        if err != nil {
+            return nil, fmt.Errorf("JDK %s: %w", v, err)
+        }
+        if !json.Valid(body) {
+            body, _ = json.Marshal(string(body))
+        }
+        if i > 0 {
+            raw.WriteString(",")
+        }
+        fmt.Fprintf(&raw, "\n%q: ", v)
+        raw.Write(bytes.TrimSpace(body))
+    }
+    raw.WriteString("\n}")
+    return raw.Bytes(), nil
+}
+
+// parseReleaseVersions scompone una sola volta la versione di ogni release caricata.
+func parseReleaseVersions(releases []AdoptiumResponse) {
+    for i := range releases {
+        releases[i].ReleaseVersion = utils.NewReleaseVersion(releases[i].VersionData.OpenJDKVersion)
+    }
+}
+
@@ -3,24 +3,30 @@ package adoptium
 import (
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
+
+	"jenvy/internal/utils"
 )
 
 type Available struct {
-    AvailableReleases []int `json:"available_releases"`
+    AvailableReleases        []int `json:"available_releases"`
+    MostRecentFeatureRelease int   `json:"most_recent_feature_release"` // Ultima major GA
+    TipVersion               int   `json:"tip_version"`                 // Major in sviluppo (solo EA)
 }
 
-func GetAvailableVersions() ([]string, error) {
-    resp, err := http.Get("https://api.adoptium.net/v3/info/available_releases")
+// fetchAvailable legge l'elenco delle major pubblicate da Adoptium.
+func fetchAvailable() (Available, error) {
+    var info Available
+    body, _, err := utils.CachedGet("https://api.adoptium.net/v3/info/available_releases")
     if err != nil {
-        return nil, err
+        return info, err
     }
-    defer resp.Body.Close()
+    err = json.Unmarshal(body, &info)
+    return info, err
+}
 
-    body, _ := io.ReadAll(resp.Body)
-    var info Available
-    if err := json.Unmarshal(body, &info); err != nil {
+func GetAvailableVersions() ([]string, error) {
+    info, err := fetchAvailable()
+    if err != nil {
         return nil, err
     }
 
@@ -30,3 +36,19 @@ func GetAvailableVersions() ([]string, error) {
     }
     return versions, nil
 }
+
+// GetEarlyAccessVersions restituisce le major non ancora rilasciate, per cui esistono solo build EA.
+//
+// Sono le major successive all'ultima GA fino alla tip_version di Adoptium
+// (es. 25 e 26 quando l'ultima GA è 24).
+func GetEarlyAccessVersions() ([]string, error) {
+    info, err := fetchAvailable()
+    if err != nil {
+        return nil, err
+    }
+    var versions []string
+    for v := info.MostRecentFeatureRelease + 1; v <= info.TipVersion; v++ {
+        versions = append(versions, fmt.Sprintf("%d", v))
+    }
+    return versions, nil
+}
@@ -0,0 +1,88 @@
+package adoptium
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"jenvy/internal/utils"
+)
+
+// JREAsset è l'ultima JRE per il sistema e l'architettura in uso di una major, come pubblicata dall'API Adoptium.
+//
+// Usata da 'jenvy self-test', che ha bisogno del pacchetto più piccolo possibile
+// e non di un JDK completo.
+type JREAsset struct {
+	Binary struct {
+		Package struct {
+			Name     string `json:"name"`
+			Link     string `json:"link"`
+			Checksum string `json:"checksum"` // SHA-256 dell'archivio
+			Size     int64  `json:"size"`
+		} `json:"package"`
+	} `json:"binary"`
+	Version struct {
+		OpenJDKVersion string `json:"openjdk_version"`
+	} `json:"version"`
+}
+
+// GetSmallestJRE restituisce la JRE più leggera tra le ultime release delle major indicate.
+//
+// Viene cercata per il sistema in uso (utils.HostOS) e per l'architettura nativa,
+// così da poterla estrarre ed eseguire sulla macchina stessa.
+//
+// Le major che non rispondono o non pubblicano una JRE vengono ignorate; viene
+// restituito un errore solo se nessuna major ha prodotto un pacchetto.
+//
+// Parametri:
+//
+//	majors []int - Major da confrontare (es. utils.LTSMajors)
+func GetSmallestJRE(majors []int) (*JREAsset, error) {
+	client := utils.NewHTTPClient(30 * time.Second)
+	var smallest *JREAsset
+	var lastErr error
+	hostOS := utils.HostOS()
+	if hostOS == utils.OSAlpine {
+		hostOS = "alpine-linux"
+	}
+	for _, major := range majors {
+		url := fmt.Sprintf("https://api.adoptium.net/v3/assets/latest/%d/hotspot?architecture=%s&image_type=jre&os=%s&vendor=eclipse", major, utils.NativeArch(), hostOS)
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("User-Agent", utils.UserAgent)
+
+		resp, err := utils.DoWithRetry(client, req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		var assets []JREAsset
+		err = json.NewDecoder(resp.Body).Decode(&assets)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = fmt.Errorf("JRE %d: %w", major, err)
+			continue
+		}
+
+		for i := range assets {
+			asset := &assets[i]
+			if asset.Binary.Package.Link == "" {
+				continue
+			}
+			if smallest == nil || asset.Binary.Package.Size < smallest.Binary.Package.Size {
+				smallest = asset
+			}
+		}
+	}
+
+	if smallest == nil {
+		if lastErr == nil {
+			lastErr = fmt.Errorf("no %s %s JRE published for majors %v", utils.HostOSName(), utils.NativeArch(), majors)
+		}
+		return nil, lastErr
+	}
+	return smallest, nil
+}
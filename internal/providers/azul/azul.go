@@ -3,9 +3,9 @@ package azul
 import (
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"strings"
+
+	"jenvy/internal/utils"
 )
 
 type AzulPackage struct {
@@ -13,23 +13,74 @@ type AzulPackage struct {
     JavaVersion []int    `json:"java_version"`
     DownloadURL string   `json:"download_url"`
     Latest      bool     `json:"latest"`
+    BuildNumber int      `json:"openjdk_build_number"`
+
+    // Versione già scomposta, calcolata al caricamento dell'elenco
+    utils.ReleaseVersion `json:"-"`
 }
 
 
-func GetAzulJDKs() ([]AzulPackage, error) {
-    url := "https://api.azul.com/metadata/v1/zulu/packages?java_package_type=jdk&os=windows&arch=x86_64&availability_types=CA&release_status=ga&page_size=100"
+// packagesURL restituisce l'endpoint dei metadati Azul con i pacchetti GA in formato archivio.
+//
+// Tipo di pacchetto (jdk | jre), presenza di JavaFX (Zulu FX), architettura e
+// sistema seguono --type, --javafx, --arch e --os; il formato è zip per
+// Windows e tar.gz per gli altri sistemi.
+func packagesURL() string {
+    return fmt.Sprintf("https://api.azul.com/metadata/v1/zulu/packages?java_package_type=%s&javafx_bundled=%t&os=%s&arch=%s&archive_type=%s&availability_types=CA&release_status=ga&page_size=100",
+        utils.ImageType(), utils.JavaFX(), azulOS(), azulArch(), strings.TrimPrefix(utils.TargetArchiveExtension(), "."))
+}
+
+// azulOS converte il sistema richiesto nel nome usato dall'API Azul.
+func azulOS() string {
+    switch utils.TargetOS() {
+    case utils.OSMac:
+        return "macos"
+    case utils.OSAlpine:
+        return "linux_musl"
+    default:
+        return utils.TargetOS()
+    }
+}
 
-    resp, err := http.Get(url)
+// azulArch converte l'architettura richiesta nel nome usato dall'API Azul.
+func azulArch() string {
+    switch utils.TargetArch() {
+    case utils.ArchX32:
+        return "i686"
+    case utils.ArchAArch64:
+        return "aarch64"
+    default:
+        return "x86_64"
+    }
+}
+
+// GetRawPackages restituisce il JSON dei pacchetti così come inviato da Azul ('remote-list --raw').
+func GetRawPackages() ([]byte, error) {
+    body, _, err := utils.CachedGet(packagesURL())
+    return body, err
+}
+
+func GetAzulJDKs() ([]AzulPackage, error) {
+    body, err := GetRawPackages()
     if err != nil {
         return nil, err
     }
-    defer resp.Body.Close()
-
-    body, _ := io.ReadAll(resp.Body)
     var data []AzulPackage
     if err := json.Unmarshal(body, &data); err != nil {
         return nil, err
     }
+    // java_version è già numerico: le componenti assenti valgono 0
+    usable := 0
+    for i := range data {
+        parts := append(append([]int{}, data[i].JavaVersion...), 0, 0, 0)
+        data[i].ReleaseVersion = utils.ReleaseVersion{Major: parts[0], Minor: parts[1], Patch: parts[2], Build: data[i].BuildNumber}
+        if data[i].Major > 0 && data[i].DownloadURL != "" {
+            usable++
+        }
+    }
+    if err := utils.CheckUsableReleases(len(data), usable); err != nil {
+        return nil, err
+    }
 
     return data, nil
 }
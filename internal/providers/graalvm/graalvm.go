@@ -0,0 +1,123 @@
+package graalvm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"jenvy/internal/utils"
+)
+
+// GraalVMPackage è un pacchetto GraalVM come descritto dalla Disco API di foojay.
+//
+// GraalVM non ha un'API di release propria per entrambe le edizioni: foojay
+// indicizza sia GraalVM Community (GitHub graalvm-ce-builds) sia Oracle GraalVM
+// (download.oracle.com) con lo stesso schema.
+type GraalVMPackage struct {
+	Distribution        string `json:"distribution"`         // graalvm_community, graalvm_ce17, graalvm (Oracle)...
+	JavaVersion         string `json:"java_version"`         // Versione Java (es. "21.0.2+13")
+	DistributionVersion string `json:"distribution_version"` // Versione GraalVM (es. "21.0.2", "22.3.3")
+	OS                  string `json:"operating_system"`
+	Arch                string `json:"architecture"`
+	Filename            string `json:"filename"`
+	DirectDownloadURI   string `json:"direct_download_uri"`
+	TermOfSupport       string `json:"term_of_support"` // lts | mts | sts
+	Links               struct {
+		PkgDownloadRedirect string `json:"pkg_download_redirect"`
+	} `json:"links"`
+
+	// Versione già scomposta, calcolata al caricamento dell'elenco
+	utils.ReleaseVersion `json:"-"`
+}
+
+// DownloadURL restituisce l'URL dell'archivio, preferendo il link diretto al redirect di foojay.
+func (p GraalVMPackage) DownloadURL() string {
+	if p.DirectDownloadURI != "" {
+		return p.DirectDownloadURI
+	}
+	return p.Links.PkgDownloadRedirect
+}
+
+// LTS indica se la release Java sottostante è LTS.
+func (p GraalVMPackage) LTS() bool {
+	return strings.EqualFold(p.TermOfSupport, "lts")
+}
+
+// packagesURL restituisce l'endpoint foojay con i pacchetti JDK GA per architettura e sistema richiesti.
+//
+// foojay chiama x86 l'architettura a 32 bit e macos il sistema Apple; il
+// formato è zip per Windows e tar.gz per gli altri sistemi.
+func packagesURL() string {
+	arch := utils.TargetArch()
+	if arch == utils.ArchX32 {
+		arch = "x86"
+	}
+	os := utils.TargetOS()
+	if os == utils.OSMac {
+		os = "macos"
+	}
+	archive := strings.TrimPrefix(utils.TargetArchiveExtension(), ".")
+	return "https://api.foojay.io/disco/v3.0/packages?operating_system=" + os + "&architecture=" + arch + "&archive_type=" + archive + "&package_type=jdk&release_status=ga"
+}
+
+// editionDistributions sono le distribuzioni foojay che compongono ciascuna edizione.
+//
+// Le release GraalVM CE 22.x (graalvm_ce11, graalvm_ce17) precedono il nome
+// "GraalVM Community" e non includono native-image, che va installato con gu.
+var editionDistributions = map[string][]string{
+	utils.GraalVMEditionCommunity: {"graalvm_community", "graalvm_ce17", "graalvm_ce11"},
+	utils.GraalVMEditionOracle:    {"graalvm"},
+}
+
+// editionURL restituisce l'URL dei pacchetti di un'edizione (community | oracle).
+func editionURL(edition string) (string, error) {
+	distributions, ok := editionDistributions[edition]
+	if !ok {
+		return "", utils.ValidateGraalVMEdition(edition)
+	}
+	query := url.Values{"distribution": distributions}
+	return packagesURL() + "&" + query.Encode(), nil
+}
+
+// GetRawPackages restituisce il JSON dei pacchetti così come inviato da foojay ('remote-list --raw').
+func GetRawPackages() ([]byte, error) {
+	endpoint, err := editionURL(utils.GraalVMEdition())
+	if err != nil {
+		return nil, err
+	}
+	body, status, err := utils.CachedGet(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("foojay API returned status %d", status)
+	}
+	return body, nil
+}
+
+// GetGraalVMJDKs restituisce i pacchetti GraalVM dell'edizione configurata (graalvm-edition).
+func GetGraalVMJDKs() ([]GraalVMPackage, error) {
+	body, err := GetRawPackages()
+	if err != nil {
+		return nil, err
+	}
+	var data struct {
+		Result []GraalVMPackage `json:"result"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	usable := 0
+	for i := range data.Result {
+		data.Result[i].ReleaseVersion = utils.NewReleaseVersion(data.Result[i].JavaVersion)
+		if data.Result[i].Major > 0 && data.Result[i].DownloadURL() != "" {
+			usable++
+		}
+	}
+	if err := utils.CheckUsableReleases(len(data.Result), usable); err != nil {
+		return nil, err
+	}
+	return data.Result, nil
+}
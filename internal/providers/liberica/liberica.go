@@ -2,8 +2,11 @@ package liberica
 
 import (
 	"encoding/json"
-	"io"
+	"fmt"
 	"net/http"
+	"strings"
+
+	"jenvy/internal/utils"
 )
 
 type LibericaRelease struct {
@@ -12,22 +15,103 @@ type LibericaRelease struct {
     OS          string `json:"os"`
     Arch        string `json:"architecture"`
     Bitness     int    `json:"bitness"`
+
+    // EarlyAccess indica una build EA di una major non ancora rilasciata (--ea)
+    EarlyAccess bool `json:"-"`
+
+    // Versione già scomposta, calcolata al caricamento dell'elenco
+    utils.ReleaseVersion `json:"-"`
 }
 
-func GetLibericaJDKs() ([]LibericaRelease, error) {
-    url := "https://api.bell-sw.com/v1/liberica/releases?bitness=64&os=windows&arch=x86&package-type=zip&bundle-type=jdk"
+// releasesURL restituisce l'endpoint BellSoft con le release in formato archivio (zip per Windows, tar.gz altrove).
+//
+// Il bundle segue --type e --javafx: jdk, jre oppure le edizioni Full con
+// JavaFX (jdk-full, jre-full). BellSoft indica l'architettura come famiglia
+// (x86, arm) più bitness: x64 è x86 a 64 bit, aarch64 è arm a 64 bit.
+func releasesURL() string {
+    bundle := utils.ImageType()
+    if utils.JavaFX() {
+        bundle += "-full"
+    }
+    bitness, arch := "64", "x86"
+    switch utils.TargetArch() {
+    case utils.ArchX32:
+        bitness = "32"
+    case utils.ArchAArch64:
+        arch = "arm"
+    }
+    os := utils.TargetOS()
+    switch os {
+    case utils.OSMac:
+        os = "macos"
+    case utils.OSAlpine:
+        os = "linux-musl"
+    }
+    packageType := strings.TrimPrefix(utils.TargetArchiveExtension(), ".")
+    return "https://api.bell-sw.com/v1/liberica/releases?bitness=" + bitness + "&os=" + os + "&arch=" + arch + "&package-type=" + packageType + "&bundle-type=" + bundle
+}
 
-    resp, err := http.Get(url)
+// GetRawReleases restituisce il JSON delle release così come inviato da BellSoft ('remote-list --raw').
+func GetRawReleases() ([]byte, error) {
+    body, _, err := utils.CachedGet(releasesURL())
+    return body, err
+}
+
+func GetLibericaJDKs() ([]LibericaRelease, error) {
+    body, err := GetRawReleases()
     if err != nil {
         return nil, err
     }
-    defer resp.Body.Close()
-
-    body, _ := io.ReadAll(resp.Body)
     var data []LibericaRelease
     if err := json.Unmarshal(body, &data); err != nil {
         return nil, err
     }
+    usable := 0
+    for i := range data {
+        data[i].ReleaseVersion = utils.NewReleaseVersion(data[i].Version)
+        if data[i].Major > 0 && data[i].DownloadURL != "" {
+            usable++
+        }
+    }
+    if err := utils.CheckUsableReleases(len(data), usable); err != nil {
+        return nil, err
+    }
 
+    if utils.IncludeEarlyAccess() {
+        ea, err := getEarlyAccessJDKs(data)
+        if err != nil {
+            return nil, fmt.Errorf("early-access builds: %w", err)
+        }
+        data = append(data, ea...)
+    }
     return data, nil
 }
+
+// getEarlyAccessJDKs restituisce le build EA delle major successive all'ultima GA di ga.
+func getEarlyAccessJDKs(ga []LibericaRelease) ([]LibericaRelease, error) {
+    latestGA := 0
+    for _, release := range ga {
+        latestGA = max(latestGA, release.Major)
+    }
+
+    body, status, err := utils.CachedGet(releasesURL() + "&release-type=ea")
+    if err != nil {
+        return nil, err
+    }
+    if status != http.StatusOK {
+        return nil, fmt.Errorf("BellSoft API returned status %d", status)
+    }
+    var data []LibericaRelease
+    if err := json.Unmarshal(body, &data); err != nil {
+        return nil, err
+    }
+    var ea []LibericaRelease
+    for _, release := range data {
+        release.ReleaseVersion = utils.NewReleaseVersion(release.Version)
+        if release.Major > latestGA && release.DownloadURL != "" {
+            release.EarlyAccess = true
+            ea = append(ea, release)
+        }
+    }
+    return ea, nil
+}
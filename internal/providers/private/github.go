@@ -0,0 +1,168 @@
+package private
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"jenvy/internal/utils"
+)
+
+// githubMaxPages limita le pagine di release lette per repository (100 release per pagina).
+//
+// I repository come temurin21-binaries accumulano centinaia di release: le più
+// recenti bastano, e ogni pagina consuma una richiesta del rate limit GitHub.
+const githubMaxPages = 5
+
+// githubRelease è una release restituita dall'API REST di GitHub.
+type githubRelease struct {
+	TagName     string `json:"tag_name"`
+	Draft       bool   `json:"draft"`
+	Prerelease  bool   `json:"prerelease"`
+	PublishedAt string `json:"published_at"`
+	Assets      []struct {
+		Name               string `json:"name"`
+		URL                string `json:"url"`
+		BrowserDownloadURL string `json:"browser_download_url"`
+		Digest             string `json:"digest"` // "sha256:<hex>", assente nelle release più vecchie
+	} `json:"assets"`
+}
+
+// nextPageLink estrae l'URL rel="next" dall'header Link della paginazione GitHub.
+var nextPageLink = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// listGitHub elenca gli archivi JDK allegati alle release di uno o più repository GitHub.
+//
+// endpoint è l'URL del repository (https://github.com/adoptium/temurin21-binaries)
+// oppure, con private-repository impostato a un elenco "owner/repo" separato da
+// virgole, l'host (https://github.com o un GitHub Enterprise). Bozze e
+// pre-release vengono ignorate. Il token è private-token o, per github.com, il
+// token GitHub (JENVY_GITHUB_TOKEN o github-token): con un token i download
+// passano dall'API degli asset, l'unica via per i repository privati. La
+// risposta grezza è l'array delle release di tutti i repository.
+func listGitHub(cfg *utils.Config) ([]PrivateRelease, []byte, error) {
+	pattern, err := artifactPattern(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	apiBase, repos, err := githubRepositories(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	token := cfg.PrivateToken
+	if token == "" && apiBase == utils.GitHubAPIBase {
+		token, _ = utils.GitHubToken()
+	}
+
+	var all []githubRelease
+	for _, repo := range repos {
+		next := fmt.Sprintf("%s/repos/%s/releases?per_page=100", apiBase, repo)
+		for page := 0; next != "" && page < githubMaxPages; page++ {
+			var releases []githubRelease
+			body, link, err := githubRequest(next, token)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", repo, err)
+			}
+			if err := json.Unmarshal(body, &releases); err != nil {
+				return nil, body, fmt.Errorf("JSON parsing error: %v", err)
+			}
+			all = append(all, releases...)
+			next = link
+		}
+	}
+	raw, _ := json.Marshal(all)
+
+	var result []PrivateRelease
+	for _, release := range all {
+		if release.Draft || release.Prerelease {
+			continue
+		}
+		for _, asset := range release.Assets {
+			jdk, ok := releaseFromFile(asset.Name, pattern)
+			if !ok {
+				continue
+			}
+			jdk.DownloadURL = asset.BrowserDownloadURL
+			if token != "" && asset.URL != "" {
+				jdk.DownloadURL = asset.URL
+			}
+			jdk.ReleaseDate = release.PublishedAt
+			if algorithm, sum, ok := strings.Cut(asset.Digest, ":"); ok && algorithm == "sha256" {
+				jdk.Checksum, jdk.ChecksumAlgorithm = sum, algorithm
+			}
+			result = append(result, jdk)
+		}
+	}
+	return result, raw, nil
+}
+
+// githubRepositories ricava dalla configurazione la base API e i repository "owner/repo" da elencare.
+func githubRepositories(cfg *utils.Config) (string, []string, error) {
+	endpoint, err := url.Parse(cfg.PrivateEndpoint)
+	if err != nil || endpoint.Host == "" {
+		return "", nil, fmt.Errorf("invalid private endpoint '%s' (expected e.g. https://github.com/adoptium/temurin21-binaries)", cfg.PrivateEndpoint)
+	}
+	apiBase := utils.GitHubAPIBaseFor(endpoint)
+
+	var repos []string
+	for _, repo := range strings.Split(cfg.PrivateRepository, ",") {
+		if repo = strings.Trim(strings.TrimSpace(repo), "/"); repo != "" {
+			repos = append(repos, repo)
+		}
+	}
+	if len(repos) == 0 {
+		repo := strings.TrimSuffix(strings.Trim(endpoint.Path, "/"), "/releases")
+		repo = strings.TrimSuffix(repo, ".git")
+		if strings.Count(repo, "/") != 1 {
+			return "", nil, fmt.Errorf("private endpoint '%s' is not a GitHub repository URL (or set private-repository to owner/repo)", cfg.PrivateEndpoint)
+		}
+		repos = append(repos, repo)
+	}
+	for _, repo := range repos {
+		if strings.Count(repo, "/") != 1 {
+			return "", nil, fmt.Errorf("invalid GitHub repository '%s' (expected owner/repo)", repo)
+		}
+	}
+	return apiBase, repos, nil
+}
+
+// githubRequest legge una pagina dell'API GitHub e restituisce il link alla pagina successiva.
+func githubRequest(endpoint, token string) ([]byte, string, error) {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("User-Agent", utils.UserAgent)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := utils.NewHTTPClient(utils.DefaultRequestTimeout)
+	resp, err := utils.DoWithRetry(client, req)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w (%s): %v", ErrUnreachable, endpoint, err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0":
+		return nil, "", fmt.Errorf("GitHub API rate limit exceeded (set %s or 'jenvy config set github-token <token>')", utils.GitHubTokenEnv)
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, "", fmt.Errorf("repository not found (private repositories need a token with read access)")
+	case resp.StatusCode != http.StatusOK:
+		return nil, "", fmt.Errorf("❌ Server responded with status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	next := ""
+	if match := nextPageLink.FindStringSubmatch(resp.Header.Get("Link")); match != nil {
+		next = match[1]
+	}
+	return body, next, nil
+}
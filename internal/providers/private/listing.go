@@ -0,0 +1,189 @@
+package private
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"jenvy/internal/utils"
+)
+
+// s3ListResult è una pagina della risposta ListObjectsV2 di S3.
+type s3ListResult struct {
+	Contents []struct {
+		Key          string `xml:"Key"`
+		LastModified string `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// listS3 elenca gli archivi JDK di un bucket S3 con ListObjectsV2.
+//
+// endpoint è l'URL del bucket, in stile virtual-hosted
+// (https://bucket.s3.eu-west-1.amazonaws.com) o path-style
+// (https://minio.corp/bucket); private-repository, se impostato, è il prefisso
+// delle chiavi. La richiesta non è firmata: il bucket deve consentire
+// s3:ListBucket e s3:GetObject alla rete aziendale (bucket policy o VPC
+// endpoint). Un token configurato viene comunque inviato, per i gateway
+// S3-compatibili che lo richiedono. La risposta grezza riunisce le pagine XML.
+func listS3(cfg *utils.Config) ([]PrivateRelease, []byte, error) {
+	pattern, err := artifactPattern(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	base := strings.TrimRight(cfg.PrivateEndpoint, "/")
+
+	var raw bytes.Buffer
+	var releases []PrivateRelease
+	continuation := ""
+	for {
+		query := url.Values{"list-type": {"2"}}
+		if cfg.PrivateRepository != "" {
+			query.Set("prefix", cfg.PrivateRepository)
+		}
+		if continuation != "" {
+			query.Set("continuation-token", continuation)
+		}
+		body, err := privateRequest(http.MethodGet, base+"/?"+query.Encode(), cfg.PrivateToken, "", nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		raw.Write(body)
+		var page s3ListResult
+		if err := xml.Unmarshal(body, &page); err != nil {
+			return nil, raw.Bytes(), fmt.Errorf("XML parsing error: %v", err)
+		}
+		for _, object := range page.Contents {
+			release, ok := releaseFromFile(object.Key, pattern)
+			if !ok {
+				continue
+			}
+			release.DownloadURL = base + "/" + escapeKey(object.Key)
+			release.ReleaseDate = object.LastModified
+			releases = append(releases, release)
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			break
+		}
+		continuation = page.NextContinuationToken
+		raw.WriteByte('\n')
+	}
+	return releases, raw.Bytes(), nil
+}
+
+// escapeKey codifica ogni segmento di una chiave S3 per l'uso in un URL.
+//
+// Anche "+" (frequente nelle versioni, es. 17.0.9+9) viene codificato: nei
+// percorsi S3 lo interpreta come uno spazio.
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(url.PathEscape(segment), "+", "%2B")
+	}
+	return strings.Join(segments, "/")
+}
+
+// hrefPattern estrae le destinazioni dei link da un indice HTML.
+var hrefPattern = regexp.MustCompile(`(?i)href\s*=\s*["']([^"']+)["']`)
+
+// listDirectory elenca gli archivi JDK di un indice di directory HTTP.
+//
+// endpoint è l'URL della directory (es. https://files.corp/jdk/) servita con
+// l'autoindex di Apache, nginx o IIS: vengono considerati i link ai file .zip
+// della pagina, risolti rispetto all'endpoint. Le sottodirectory non vengono
+// visitate. La risposta grezza è la pagina HTML.
+func listDirectory(cfg *utils.Config) ([]PrivateRelease, []byte, error) {
+	pattern, err := artifactPattern(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	endpoint := cfg.PrivateEndpoint
+	if !strings.HasSuffix(endpoint, "/") {
+		endpoint += "/" // I link relativi vanno risolti dentro la directory
+	}
+	base, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid private endpoint '%s': %v", cfg.PrivateEndpoint, err)
+	}
+
+	body, err := privateRequest(http.MethodGet, endpoint, cfg.PrivateToken, "", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var releases []PrivateRelease
+	seen := make(map[string]bool)
+	for _, match := range hrefPattern.FindAllSubmatch(body, -1) {
+		link, err := base.Parse(string(match[1]))
+		if err != nil || seen[link.String()] {
+			continue
+		}
+		seen[link.String()] = true
+		name, err := url.PathUnescape(path.Base(link.Path))
+		if err != nil {
+			continue
+		}
+		release, ok := releaseFromFile(name, pattern)
+		if !ok {
+			continue
+		}
+		release.DownloadURL = link.String()
+		releases = append(releases, release)
+	}
+	return releases, body, nil
+}
+
+// artifactPattern compila private-pattern (nil se non impostato).
+func artifactPattern(cfg *utils.Config) (*regexp.Regexp, error) {
+	if cfg.PrivatePattern == "" {
+		return nil, nil
+	}
+	return utils.CompileArtifactPattern(cfg.PrivatePattern)
+}
+
+// releaseFromFile deduce la release dal nome di un archivio con private-pattern o, senza pattern, con ReleaseFromArtifact.
+//
+// Con un pattern, {os} deve indicare Windows (windows o win) se presente e
+// {arch} viene normalizzata con utils.ParseArch; senza {arch} vale x64.
+func releaseFromFile(artifactPath string, pattern *regexp.Regexp) (PrivateRelease, bool) {
+	if pattern == nil {
+		return ReleaseFromArtifact(artifactPath)
+	}
+	match := pattern.FindStringSubmatch(path.Base(artifactPath))
+	if match == nil {
+		return PrivateRelease{}, false
+	}
+	fields := make(map[string]string)
+	for i, name := range pattern.SubexpNames() {
+		if name != "" && match[i] != "" {
+			fields[name] = match[i]
+		}
+	}
+	if osName, ok := fields["os"]; ok && !strings.EqualFold(osName, "windows") && !strings.EqualFold(osName, "win") {
+		return PrivateRelease{}, false
+	}
+	arch := utils.ArchX64
+	if value, ok := fields["arch"]; ok {
+		parsed, err := utils.ParseArch(value)
+		if err != nil {
+			return PrivateRelease{}, false
+		}
+		arch = parsed
+	}
+
+	version := strings.Replace(fields["version"], "_", "+", 1)
+	release := PrivateRelease{
+		Version: version,
+		OS:      "windows",
+		Arch:    arch,
+		LTS:     utils.IsLTSVersion(version),
+	}
+	release.ReleaseVersion = utils.NewReleaseVersion(version)
+	return release, release.Major > 0
+}
@@ -17,6 +17,14 @@ type PrivateRelease struct {
 	OS          string `json:"os"`
 	Arch        string `json:"arch"`
 	LTS         bool   `json:"lts"`
+	ReleaseDate string `json:"release_date,omitempty"` // YYYY-MM-DD o RFC3339, opzionale
+
+	// Checksum dell'archivio, opzionale; l'algoritmo è dedotto dalla lunghezza se omesso
+	Checksum          string `json:"checksum,omitempty"`
+	ChecksumAlgorithm string `json:"checksum_algorithm,omitempty"` // sha256 | sha512
+
+	// Versione già scomposta, calcolata al caricamento dell'elenco
+	utils.ReleaseVersion `json:"-"`
 }
 
 type RecommendedEntry struct {
@@ -25,34 +33,55 @@ type RecommendedEntry struct {
 	OS          string
 	Arch        string
 	LTS         string
+	ReleaseDate string
 	Major       int
 	Minor       int
 	Patch       int
 }
 
-// ✔️ Fetch remoto da endpoint privato con token opzionale
-func GetPrivateJDKs() ([]PrivateRelease, error) {
+// ErrNotConfigured indica che in ~/.jenvy/config.json non è impostato alcun endpoint privato.
+var ErrNotConfigured = errors.New("no private repository configured")
+
+// ErrUnreachable indica che l'endpoint configurato non risponde (DNS, rete, proxy o TLS).
+//
+// È distinto dalle risposte HTTP di errore: in quel caso il server è raggiungibile
+// ma rifiuta la richiesta (es. token scaduto).
+var ErrUnreachable = errors.New("private repository unreachable")
+
+// IsConfigured indica se è configurato un endpoint per il repository privato.
+func IsConfigured() bool {
+	cfg, err := utils.LoadConfig()
+	return err == nil && cfg.PrivateEndpoint != ""
+}
+
+// GetRawReleases restituisce il JSON così come inviato dall'endpoint privato ('remote-list --raw').
+//
+// Restituisce ErrNotConfigured senza endpoint, ErrUnreachable se la richiesta
+// non ottiene risposta e un errore con il codice HTTP per le risposte diverse da 200.
+//
+// Per gli altri tipi di repository (private-type) restituisce la risposta
+// della ricerca o dell'elenco dei file.
+func GetRawReleases() ([]byte, error) {
 	cfg, err := utils.LoadConfig()
 	if err != nil || cfg.PrivateEndpoint == "" {
-		return nil, errors.New("⚠️ Private endpoint not configured. Check ~/.jenvy/config.json")
+		return nil, ErrNotConfigured
+	}
+	if utils.PrivateType(cfg) != utils.PrivateTypeJSON {
+		_, raw, err := searchRepository(cfg)
+		return raw, err
 	}
 
 	endpoint := cfg.PrivateEndpoint
 	token := cfg.PrivateToken
 
-	if endpoint == "" {
-		return nil, errors.New("⚠️ Jenvy_PRIVATE_ENDPOINT environment variable not set")
-	}
-
 	req, _ := http.NewRequest("GET", endpoint, nil)
-	if token != "" {
-		req.Header.Set("Authorization", "Bearer "+token)
-	}
+	req.Header.Set("User-Agent", utils.UserAgent)
+	utils.SetPrivateAuthorization(req, token)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	client := utils.NewHTTPClient(utils.DefaultRequestTimeout)
+	resp, err := utils.DoWithRetry(client, req)
 	if err != nil {
-		return nil, fmt.Errorf("Network error: %v", err)
+		return nil, fmt.Errorf("%w (%s): %v", ErrUnreachable, endpoint, err)
 	}
 	defer resp.Body.Close()
 
@@ -60,7 +89,37 @@ func GetPrivateJDKs() ([]PrivateRelease, error) {
 		return nil, fmt.Errorf("❌ Server responded with status %d", resp.StatusCode)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	return io.ReadAll(resp.Body)
+}
+
+// searchRepository elenca gli archivi di un repository Artifactory, Nexus, S3, di una directory HTTP o delle release GitHub secondo private-type.
+func searchRepository(cfg *utils.Config) ([]PrivateRelease, []byte, error) {
+	switch utils.PrivateType(cfg) {
+	case utils.PrivateTypeNexus:
+		return searchNexus(cfg)
+	case utils.PrivateTypeS3:
+		return listS3(cfg)
+	case utils.PrivateTypeDirectory:
+		return listDirectory(cfg)
+	case utils.PrivateTypeGitHub:
+		return listGitHub(cfg)
+	default:
+		return searchArtifactory(cfg)
+	}
+}
+
+// ✔️ Fetch remoto da endpoint privato con token opzionale
+//
+// Con private-type artifactory, nexus, s3, directory o github gli archivi vengono
+// elencati dal repository stesso, senza bisogno di un file indice; versione e
+// architettura si ricavano dal nome dei file (private-pattern).
+func GetPrivateJDKs() ([]PrivateRelease, error) {
+	if cfg, err := utils.LoadConfig(); err == nil && cfg.PrivateEndpoint != "" && utils.PrivateType(cfg) != utils.PrivateTypeJSON {
+		releases, _, err := searchRepository(cfg)
+		return releases, err
+	}
+
+	body, err := GetRawReleases()
 	if err != nil {
 		return nil, err
 	}
@@ -70,6 +129,19 @@ func GetPrivateJDKs() ([]PrivateRelease, error) {
 	if err != nil {
 		return nil, fmt.Errorf("JSON parsing error: %v", err)
 	}
+	usable := 0
+	for i := range list {
+		list[i].ReleaseVersion = utils.NewReleaseVersion(list[i].Version)
+		if list[i].Major > 0 && list[i].DownloadURL != "" {
+			usable++
+		}
+	}
+	// Un repository vuoto è legittimo; voci presenti ma inutilizzabili indicano un formato diverso
+	if len(list) > 0 {
+		if err := utils.CheckUsableReleases(len(list), usable); err != nil {
+			return nil, err
+		}
+	}
 	return list, nil
 }
 
@@ -86,6 +158,7 @@ func GetRecommendedJDKs(list []PrivateRelease) []RecommendedEntry {
 			OS:          strings.ToLower(j.OS),
 			Arch:        strings.ToLower(j.Arch),
 			LTS:         utils.IfBool(j.LTS),
+			ReleaseDate: j.ReleaseDate,
 			Major:       major,
 			Minor:       minor,
 			Patch:       patch,
@@ -125,6 +198,7 @@ func ConvertToRecommended(list []PrivateRelease) []RecommendedEntry {
 			OS:          strings.ToLower(j.OS),
 			Arch:        strings.ToLower(j.Arch),
 			LTS:         utils.IfBool(j.LTS),
+			ReleaseDate: j.ReleaseDate,
 			Major:       major,
 			Minor:       minor,
 			Patch:       patch,
@@ -0,0 +1,227 @@
+package private
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"jenvy/internal/utils"
+)
+
+// artifactoryItem è un risultato della ricerca AQL di Artifactory.
+type artifactoryItem struct {
+	Repo    string `json:"repo"`
+	Path    string `json:"path"`
+	Name    string `json:"name"`
+	Created string `json:"created"`
+	SHA256  string `json:"sha256"`
+}
+
+// nexusAsset è un asset restituito dalla ricerca REST di Nexus 3.
+type nexusAsset struct {
+	DownloadURL  string `json:"downloadUrl"`
+	Path         string `json:"path"`
+	LastModified string `json:"lastModified"`
+	Checksum     struct {
+		SHA256 string `json:"sha256"`
+	} `json:"checksum"`
+}
+
+// searchArtifactory elenca gli archivi JDK di un repository Artifactory con una query AQL.
+//
+// endpoint è la base di Artifactory (es. https://repo.corp/artifactory): la
+// query va a <endpoint>/api/search/aql e i download a <endpoint>/<repo>/<path>/<nome>.
+// Restituisce anche la risposta grezza, per 'remote-list --raw'.
+func searchArtifactory(cfg *utils.Config) ([]PrivateRelease, []byte, error) {
+	if cfg.PrivateRepository == "" {
+		return nil, nil, fmt.Errorf("private-repository is required for Artifactory (jenvy config set private-repository <repo>)")
+	}
+	pattern, err := artifactPattern(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	base := strings.TrimRight(cfg.PrivateEndpoint, "/")
+	repo, _ := json.Marshal(cfg.PrivateRepository)
+	query := fmt.Sprintf(`items.find({"repo":%s,"type":"file","name":{"$match":"*.zip"}}).include("repo","path","name","created","sha256")`, repo)
+
+	body, err := privateRequest(http.MethodPost, base+"/api/search/aql", cfg.PrivateToken, "text/plain", []byte(query))
+	if err != nil {
+		return nil, nil, err
+	}
+	var data struct {
+		Results []artifactoryItem `json:"results"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, body, fmt.Errorf("JSON parsing error: %v", err)
+	}
+
+	var releases []PrivateRelease
+	for _, item := range data.Results {
+		artifactPath := path.Join(item.Path, item.Name)
+		release, ok := releaseFromFile(artifactPath, pattern)
+		if !ok {
+			continue
+		}
+		release.DownloadURL = base + "/" + item.Repo + "/" + strings.TrimPrefix(artifactPath, "./")
+		release.ReleaseDate = item.Created
+		release.Checksum = item.SHA256
+		releases = append(releases, release)
+	}
+	return releases, body, nil
+}
+
+// searchNexus elenca gli archivi JDK di un repository Nexus 3 con la ricerca REST degli asset.
+//
+// endpoint è la base di Nexus (es. https://nexus.corp): la ricerca va a
+// <endpoint>/service/rest/v1/search/assets e segue le pagine tramite
+// continuationToken. La risposta grezza riunisce gli asset di tutte le pagine.
+func searchNexus(cfg *utils.Config) ([]PrivateRelease, []byte, error) {
+	if cfg.PrivateRepository == "" {
+		return nil, nil, fmt.Errorf("private-repository is required for Nexus (jenvy config set private-repository <repo>)")
+	}
+	pattern, err := artifactPattern(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	base := strings.TrimRight(cfg.PrivateEndpoint, "/")
+
+	var assets []json.RawMessage
+	continuation := ""
+	for {
+		query := url.Values{"repository": {cfg.PrivateRepository}}
+		if continuation != "" {
+			query.Set("continuationToken", continuation)
+		}
+		body, err := privateRequest(http.MethodGet, base+"/service/rest/v1/search/assets?"+query.Encode(), cfg.PrivateToken, "", nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		var page struct {
+			Items             []json.RawMessage `json:"items"`
+			ContinuationToken string            `json:"continuationToken"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, body, fmt.Errorf("JSON parsing error: %v", err)
+		}
+		assets = append(assets, page.Items...)
+		if page.ContinuationToken == "" {
+			break
+		}
+		continuation = page.ContinuationToken
+	}
+	raw, _ := json.Marshal(map[string][]json.RawMessage{"items": assets})
+
+	var releases []PrivateRelease
+	for _, item := range assets {
+		var asset nexusAsset
+		if json.Unmarshal(item, &asset) != nil || asset.DownloadURL == "" {
+			continue
+		}
+		release, ok := releaseFromFile(asset.Path, pattern)
+		if !ok {
+			continue
+		}
+		release.DownloadURL = asset.DownloadURL
+		release.ReleaseDate = asset.LastModified
+		release.Checksum = asset.Checksum.SHA256
+		releases = append(releases, release)
+	}
+	return releases, raw, nil
+}
+
+// privateRequest esegue una richiesta autenticata verso il repository privato.
+func privateRequest(method, endpoint, token, contentType string, payload []byte) ([]byte, error) {
+	req, err := http.NewRequest(method, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", utils.UserAgent)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	utils.SetPrivateAuthorization(req, token)
+
+	client := utils.NewHTTPClient(utils.DefaultRequestTimeout)
+	resp, err := utils.DoWithRetry(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("%w (%s): %v", ErrUnreachable, endpoint, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("❌ Server responded with status %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+var (
+	// Directory di versione del layout Maven (gruppo/artefatto/<versione>/file)
+	mavenVersionDir = regexp.MustCompile(`^\d+(\.\d+)*([+_-][0-9A-Za-z.]+)?$`)
+	// Versione preceduta da "jdk" nel nome (es. jdk-17.0.9, jdk17.0.8.1)
+	jdkVersionInName = regexp.MustCompile(`(?i)jdk[-_]?(\d+(?:\.\d+)+(?:[+_]\d+)?)`)
+	// Prima versione puntata nel nome (es. OpenJDK17U-jdk_x64_windows_hotspot_17.0.9_9)
+	dottedVersion = regexp.MustCompile(`\d+(?:\.\d+)+(?:[+_]\d+)?`)
+	// Sistemi operativi diversi da Windows, i cui archivi vengono ignorati
+	otherOSMarker = regexp.MustCompile(`(?i)linux|mac|osx|darwin|aix|solaris|alpine`)
+	// Archivi pubblicati accanto al JDK che non sono un JDK (es. OpenJDK21U-jre_x64_windows...)
+	nonJDKMarker = regexp.MustCompile(`(?i)(^|[-_.])(jre|debugimage|testimage|static-libs|sources)([-_.]|$)`)
+	// Separatori delle parti del nome, tra cui l'architettura (es. windows-amd64)
+	nameSeparators = regexp.MustCompile(`[-_.]`)
+)
+
+// ReleaseFromArtifact deduce versione, sistema e architettura dal percorso di un archivio JDK.
+//
+// La versione viene cercata nel nome del file, come nelle distribuzioni
+// ufficiali caricate in un repository raw; in mancanza vale la directory che
+// contiene il file, come nel layout Maven (gruppo/artefatto/versione/file).
+// Vengono scartati i file che non sono archivi .zip, quelli senza una versione
+// riconoscibile, quelli per altri sistemi operativi e gli archivi accessori
+// (JRE, immagini di debug e di test) pubblicati accanto al JDK.
+//
+// Esempio di utilizzo:
+//
+//	release, ok := ReleaseFromArtifact("temurin/OpenJDK17U-jdk_x64_windows_hotspot_17.0.9_9.zip")
+//	// release.Version = "17.0.9+9", release.Arch = "x64", ok = true
+func ReleaseFromArtifact(artifactPath string) (PrivateRelease, bool) {
+	artifactPath = strings.Trim(artifactPath, "/")
+	name := path.Base(artifactPath)
+	if !strings.EqualFold(path.Ext(name), ".zip") || otherOSMarker.MatchString(name) || nonJDKMarker.MatchString(name) {
+		return PrivateRelease{}, false
+	}
+
+	version := dottedVersion.FindString(name)
+	if match := jdkVersionInName.FindStringSubmatch(name); match != nil {
+		version = match[1]
+	}
+	if dir := path.Base(path.Dir(artifactPath)); version == "" && dir != "." && mavenVersionDir.MatchString(dir) {
+		version = dir
+	}
+	if version == "" {
+		return PrivateRelease{}, false
+	}
+	version = strings.Replace(version, "_", "+", 1)
+
+	arch := utils.ArchX64
+	if lower := strings.ToLower(name); !strings.Contains(lower, "x86_64") {
+		for _, token := range nameSeparators.Split(lower, -1) {
+			if parsed, err := utils.ParseArch(token); err == nil {
+				arch = parsed
+				break
+			}
+		}
+	}
+
+	release := PrivateRelease{
+		Version: version,
+		OS:      "windows",
+		Arch:    arch,
+		LTS:     utils.IsLTSVersion(version),
+	}
+	release.ReleaseVersion = utils.NewReleaseVersion(version)
+	return release, release.Major > 0
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ActivationStyleRegistry è lo stile predefinito: 'jenvy use' scrive il JDK scelto in JAVA_HOME.
+const ActivationStyleRegistry = "registry"
+
+// ActivationStyleJunction mantiene JAVA_HOME fisso su ~/.jenvy/current, una directory
+// junction che 'jenvy use' ripunta al JDK scelto senza toccare il registro.
+const ActivationStyleJunction = "junction"
+
+// ValidateActivationStyle verifica che lo stile di attivazione sia supportato.
+func ValidateActivationStyle(style string) error {
+	switch style {
+	case ActivationStyleRegistry, ActivationStyleJunction:
+		return nil
+	default:
+		return fmt.Errorf("invalid activation style '%s' (use %s or %s)", style, ActivationStyleRegistry, ActivationStyleJunction)
+	}
+}
+
+// ActivationStyle restituisce lo stile di attivazione configurato (activation-style).
+//
+// Un file di configurazione assente, illeggibile o con un valore non valido
+// ricade su ActivationStyleRegistry, il comportamento storico di 'jenvy use'.
+func ActivationStyle() string {
+	cfg, err := LoadConfigOrDefault()
+	if err != nil || ValidateActivationStyle(cfg.ActivationStyle) != nil {
+		return ActivationStyleRegistry
+	}
+	return cfg.ActivationStyle
+}
+
+// CurrentJDKLink restituisce il percorso della junction del JDK attivo (~/.jenvy/current).
+func CurrentJDKLink() (string, error) {
+	jenvyDir, err := JenvyHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(jenvyDir, "current"), nil
+}
+
+// ResolveJavaHome risolve un JAVA_HOME che punta alla junction ~/.jenvy/current.
+//
+// Con activation-style=junction JAVA_HOME non cambia mai: per sapere quale JDK è
+// attivo occorre seguire la junction. Qualsiasi altro percorso viene restituito invariato.
+//
+// Esempio di utilizzo:
+//
+//	ResolveJavaHome(`C:\Users\Marco\.jenvy\current`)
+//	// → C:\Users\Marco\.jenvy\versions\JDK-17.0.12+7
+func ResolveJavaHome(javaHome string) string {
+	link, err := CurrentJDKLink()
+	if err != nil || javaHome == "" || !strings.EqualFold(filepath.Clean(javaHome), filepath.Clean(link)) {
+		return javaHome
+	}
+	target, err := os.Readlink(link)
+	if err != nil {
+		return javaHome
+	}
+	return target
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// DefaultAlias è l'alias del JDK predefinito, impostato da 'jenvy default' e attivato da 'jenvy init'.
+const DefaultAlias = "default"
+
+// aliasNamePattern descrive un nome valido per 'jenvy alias': lettere, cifre, ".", "_" e "-", iniziando con una lettera.
+var aliasNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9._-]*$`)
+
+// ValidateAliasName controlla che name possa essere usato come alias di una versione.
+//
+// Un alias non deve poter essere confuso con una versione (es. "jdk17", che
+// NormalizeVersionInput accetta) né con un sottocomando di 'jenvy alias'.
+//
+// Esempio di utilizzo:
+//
+//	err := ValidateAliasName("proj-x") // nil
+//	err := ValidateAliasName("jdk21")  // errore: è una versione
+func ValidateAliasName(name string) error {
+	if !aliasNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid alias name '%s' (use letters, digits, '.', '_' and '-', starting with a letter)", name)
+	}
+	if _, err := NormalizeVersionInput(name); err == nil {
+		return fmt.Errorf("alias name '%s' is a version", name)
+	}
+	switch name {
+	case "list", "ls":
+		return fmt.Errorf("alias name '%s' is reserved", name)
+	}
+	return nil
+}
+
+// ResolveAlias restituisce la versione associata a un alias definito con 'jenvy alias'.
+//
+// Una configurazione assente o illeggibile equivale a nessun alias.
+//
+// Restituisce:
+//
+//	string - Versione associata (es. "17.0.9")
+//	bool   - false se name non è un alias
+func ResolveAlias(name string) (string, bool) {
+	cfg, err := LoadConfigOrDefault()
+	if err != nil {
+		return "", false
+	}
+	version, ok := cfg.Aliases[name]
+	return version, ok && version != ""
+}
+
+// SortedAliasNames restituisce i nomi degli alias configurati in ordine alfabetico.
+func SortedAliasNames(cfg *Config) []string {
+	names := make([]string, 0, len(cfg.Aliases))
+	for name := range cfg.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Architetture nel formato usato dai provider JDK.
+const (
+	ArchX64     = "x64"
+	ArchX32     = "x32"
+	ArchAArch64 = "aarch64"
+)
+
+// JDKArch converte un'architettura Go (runtime.GOARCH) nel formato dei provider JDK.
+//
+// I valori non riconosciuti vengono restituiti invariati.
+func JDKArch(goarch string) string {
+	switch goarch {
+	case "amd64":
+		return ArchX64
+	case "386":
+		return ArchX32
+	case "arm64":
+		return ArchAArch64
+	}
+	return goarch
+}
+
+// NativeArch restituisce l'architettura del sistema operativo, non quella del processo.
+//
+// Un jenvy a 32 bit su Windows 64 bit gira sotto WOW64: runtime.GOARCH vale
+// "386" ma il sistema può eseguire un JDK x64, che è quello da scaricare. Su
+// Windows l'architettura viene letta con IsWow64Process2 (o GetNativeSystemInfo
+// sulle versioni precedenti a Windows 10 1511); se la rilevazione fallisce si
+// ricade su runtime.GOARCH.
+//
+// Restituisce:
+//
+//	string - "x64", "x32" o "aarch64"
+func NativeArch() string {
+	if arch, ok := nativeMachineArch(); ok {
+		return arch
+	}
+	return JDKArch(runtime.GOARCH)
+}
+
+// ParseArch normalizza l'architettura indicata dall'utente (es. --arch=amd64) nel formato JDK.
+//
+// Accetta anche gli alias più comuni: amd64/x86_64, x86/386/i386, arm64.
+func ParseArch(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "x64", "amd64", "x86_64", "x86-64":
+		return ArchX64, nil
+	case "x32", "x86", "386", "i386", "i686":
+		return ArchX32, nil
+	case "aarch64", "arm64":
+		return ArchAArch64, nil
+	}
+	return "", fmt.Errorf("unsupported architecture '%s' (expected x64, x86 or aarch64)", value)
+}
+
+// targetArch è l'architettura indicata con 'jenvy download --arch' (vuota = nativa).
+var targetArch string
+
+// SetTargetArch sceglie l'architettura dei JDK richiesti ai provider.
+//
+// Come --type vale per l'intera esecuzione: i provider leggono TargetArch nel
+// costruire gli URL delle API, così la cache resta separata per architettura.
+// Una stringa vuota ripristina l'architettura nativa.
+func SetTargetArch(arch string) {
+	targetArch = arch
+}
+
+// TargetArch restituisce l'architettura richiesta con --arch o, in mancanza, NativeArch.
+func TargetArch() string {
+	if targetArch != "" {
+		return targetArch
+	}
+	return NativeArch()
+}
+
+// ArchRequested indica se l'architettura è stata scelta esplicitamente con --arch.
+func ArchRequested() bool {
+	return targetArch != ""
+}
+
+// ArchSuffix restituisce il suffisso della directory di installazione per un'architettura non nativa.
+//
+// Vuoto senza --arch o quando coincide con il sistema; altrimenti "-x64",
+// "-x32" o "-aarch64", così un JDK x64 scaricato su Windows ARM (da eseguire
+// in emulazione o da copiare su un'altra macchina) non occupa la directory di
+// quello nativo della stessa versione.
+func ArchSuffix() string {
+	if targetArch == "" || targetArch == NativeArch() {
+		return ""
+	}
+	return "-" + targetArch
+}
+
+// ArchRunsOn indica se un JDK per arch può essere eseguito da un sistema native.
+//
+// Windows x64 esegue anche codice x32 (WOW64); Windows ARM64 emula sia x64 sia x32,
+// con prestazioni ridotte. Un sistema x32 esegue solo JDK x32.
+func ArchRunsOn(arch, native string) bool {
+	switch native {
+	case ArchX64:
+		return arch == ArchX64 || arch == ArchX32
+	case ArchAArch64:
+		return true
+	}
+	return arch == native
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package utils
+
+// nativeMachineArch non rileva nulla fuori da Windows: NativeArch usa runtime.GOARCH.
+func nativeMachineArch() (string, bool) {
+	return "", false
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"debug/pe"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// procGetNativeSystemInfo è GetNativeSystemInfo di kernel32, non esposta da x/sys/windows.
+var procGetNativeSystemInfo = windows.NewLazySystemDLL("kernel32.dll").NewProc("GetNativeSystemInfo")
+
+// systemInfo ricalca la struttura SYSTEM_INFO di Win32.
+type systemInfo struct {
+	ProcessorArchitecture     uint16
+	Reserved                  uint16
+	PageSize                  uint32
+	MinimumApplicationAddress uintptr
+	MaximumApplicationAddress uintptr
+	ActiveProcessorMask       uintptr
+	NumberOfProcessors        uint32
+	ProcessorType             uint32
+	AllocationGranularity     uint32
+	ProcessorLevel            uint16
+	ProcessorRevision         uint16
+}
+
+// Valori di SYSTEM_INFO.wProcessorArchitecture.
+const (
+	processorArchitectureIntel = 0
+	processorArchitectureAMD64 = 9
+	processorArchitectureARM64 = 12
+)
+
+// nativeMachineArch legge l'architettura nativa di Windows.
+//
+// IsWow64Process2 (Windows 10 1511+) riconosce anche un processo x64 emulato su
+// ARM64; GetNativeSystemInfo copre i sistemi precedenti.
+func nativeMachineArch() (string, bool) {
+	var processMachine, nativeMachine uint16
+	if err := windows.IsWow64Process2(windows.CurrentProcess(), &processMachine, &nativeMachine); err == nil {
+		switch nativeMachine {
+		case pe.IMAGE_FILE_MACHINE_AMD64:
+			return ArchX64, true
+		case pe.IMAGE_FILE_MACHINE_I386:
+			return ArchX32, true
+		case pe.IMAGE_FILE_MACHINE_ARM64:
+			return ArchAArch64, true
+		}
+	}
+
+	if procGetNativeSystemInfo.Find() != nil {
+		return "", false
+	}
+	var info systemInfo
+	procGetNativeSystemInfo.Call(uintptr(unsafe.Pointer(&info)))
+	switch info.ProcessorArchitecture {
+	case processorArchitectureAMD64:
+		return ArchX64, true
+	case processorArchitectureIntel:
+		return ArchX32, true
+	case processorArchitectureARM64:
+		return ArchAArch64, true
+	}
+	return "", false
+}
@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// GlobalValueFlags sono le opzioni globali con un valore, accettate da ogni comando.
+var GlobalValueFlags = []string{"--timeout", "--retries", "--ca-cert"}
+
+// GlobalBoolFlags sono le opzioni globali senza valore, tolte da main prima del dispatch.
+var GlobalBoolFlags = []string{"--yes", "--insecure-skip-verify", "--no-verify-tls", "--no-wait", "--refresh"}
+
+// FlagSpec descrive le opzioni di un sottocomando per NormalizeArgs.
+type FlagSpec struct {
+	Values []string        // Opzioni con valore: "--provider azul" diventa "--provider=azul"
+	Bools  []string        // Opzioni senza valore (es. "--all")
+	Shorts map[byte]string // Opzioni brevi combinabili: con {'a': "--all"} "-ay" diventa "--all --yes"
+}
+
+// defaultShorts sono le opzioni brevi valide per ogni comando.
+var defaultShorts = map[byte]string{'y': "--yes", 'h': "--help"}
+
+// takesValue indica se name è un'opzione con valore del comando o globale.
+func (spec FlagSpec) takesValue(name string) bool {
+	for _, value := range spec.Values {
+		if value == name {
+			return true
+		}
+	}
+	for _, value := range GlobalValueFlags {
+		if value == name {
+			return true
+		}
+	}
+	return false
+}
+
+// known indica se name è un'opzione del comando o globale.
+func (spec FlagSpec) known(name string) bool {
+	return spec.takesValue(name) || slices.Contains(spec.Bools, name) || slices.Contains(GlobalBoolFlags, name)
+}
+
+// expandShorts espande un gruppo di opzioni brevi (es. "-ay"), se tutte note.
+func (spec FlagSpec) expandShorts(arg string) ([]string, bool) {
+	if len(arg) < 2 || arg[0] != '-' || arg[1] == '-' {
+		return nil, false
+	}
+	var expanded []string
+	for i := 1; i < len(arg); i++ {
+		long, ok := spec.Shorts[arg[i]]
+		if !ok {
+			long, ok = defaultShorts[arg[i]]
+		}
+		if !ok {
+			return nil, false
+		}
+		expanded = append(expanded, long)
+	}
+	return expanded, true
+}
+
+// NormalizeArgs porta gli argomenti di un sottocomando a una forma canonica.
+//
+// I comandi leggono gli argomenti in modi diversi (os.Args[2] come versione,
+// solo la forma --nome=valore, il package flag che si ferma al primo argomento
+// posizionale): la forma canonica li rende indipendenti dalla posizione delle
+// opzioni. In particolare:
+//   - **Ordine**: Prima gli argomenti posizionali, nell'ordine originale, poi le opzioni
+//   - **Valori**: "--provider azul" diventa "--provider=azul" per le opzioni in spec.Values
+//     e per le GlobalValueFlags
+//   - **Opzioni brevi**: I gruppi come "-ay" vengono espansi nelle forme lunghe
+//   - **Opzioni sconosciute**: Un'opzione assente da spec e dalle opzioni globali, o
+//     un gruppo breve con una lettera sconosciuta, è un errore
+//   - **Fine opzioni**: Dopo "--" tutto è posizionale; "-" da solo è sempre posizionale.
+//     Se uno di questi argomenti inizia con "-", la forma canonica mantiene "--" dopo
+//     le opzioni (vedi SplitArgs), altrimenti si unisce agli altri posizionali
+//
+// Parametri:
+//
+//	args []string - Argomenti successivi al nome del comando
+//	spec FlagSpec - Opzioni del comando
+//
+// Restituisce:
+//
+//	[]string - Argomenti in forma canonica, senza --help
+//	bool     - true se è stato richiesto l'aiuto del comando (--help o -h)
+//	error    - Opzione sconosciuta o opzione con valore senza valore
+//
+// Esempio di utilizzo:
+//
+//	args, help, err := NormalizeArgs([]string{"--provider", "azul", "17", "-y"}, FlagSpec{Values: []string{"--provider"}})
+//	// args = ["17", "--provider=azul", "--yes"], help = false
+//	args, _, err = NormalizeArgs([]string{"set", "--yes", "--", "-1"}, FlagSpec{})
+//	// args = ["set", "--yes", "--", "-1"]
+func NormalizeArgs(args []string, spec FlagSpec) ([]string, bool, error) {
+	var positional, flags, literal []string
+	help := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			literal = args[i+1:]
+			i = len(args)
+		case arg == "-" || !strings.HasPrefix(arg, "-"):
+			positional = append(positional, arg)
+		case arg == "--help":
+			help = true
+		case strings.HasPrefix(arg, "--"):
+			name, _, hasValue := strings.Cut(arg, "=")
+			if !spec.known(name) {
+				return nil, false, fmt.Errorf("unknown option: %s", name)
+			}
+			if !hasValue && spec.takesValue(arg) {
+				if i+1 >= len(args) || strings.HasPrefix(args[i+1], "--") {
+					return nil, false, fmt.Errorf("%s requires a value", arg)
+				}
+				i++
+				arg += "=" + args[i]
+			}
+			flags = append(flags, arg)
+		default:
+			expanded, ok := spec.expandShorts(arg)
+			if !ok {
+				return nil, false, fmt.Errorf("unknown option: %s", arg)
+			}
+			for _, long := range expanded {
+				if long == "--help" {
+					help = true
+					continue
+				}
+				flags = append(flags, long)
+			}
+		}
+	}
+
+	for _, arg := range literal {
+		if strings.HasPrefix(arg, "-") && arg != "-" {
+			return append(append(append(positional, flags...), "--"), literal...), help, nil
+		}
+	}
+	return append(append(positional, literal...), flags...), help, nil
+}
+
+// SplitArgs divide gli argomenti di un comando, in forma canonica, in posizionali e opzioni.
+//
+// Gli argomenti che iniziano con "-" sono opzioni, tranne "-" da solo e quelli
+// dopo "--", che restano posizionali (es. 'jenvy config set <chiave> -- -1').
+// I comandi leggono così gli argomenti invece di scorrere os.Args.
+//
+// Esempio di utilizzo:
+//
+//	positional, flags := SplitArgs([]string{"17", "--yes", "--", "-x"})
+//	// positional = ["17", "-x"], flags = ["--yes"]
+func SplitArgs(args []string) (positional, flags []string) {
+	options, rest := cutOptions(args)
+	for _, arg := range options {
+		if arg != "-" && strings.HasPrefix(arg, "-") {
+			flags = append(flags, arg)
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+	if len(rest) > 0 {
+		positional = append(positional, rest[1:]...)
+	}
+	return positional, flags
+}
+
+// cutOptions separa gli argomenti che possono contenere opzioni da "--" e da quelli che lo seguono.
+//
+// Le funzioni che tolgono le opzioni globali (es. StripYesFlag) esaminano solo
+// la prima parte: un "--yes" dopo "--" è un argomento del comando.
+func cutOptions(args []string) (options, rest []string) {
+	if i := slices.Index(args, "--"); i != -1 {
+		return args[:i], args[i:]
+	}
+	return args, nil
+}
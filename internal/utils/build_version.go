@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// BuildRequirement è la versione Java richiesta da un file di build.
+type BuildRequirement struct {
+	Major  int    // Versione major richiesta (es. 17)
+	Source string // File di build da cui è stata letta
+	Field  string // Proprietà o espressione che l'ha fornita (es. "maven.compiler.release")
+}
+
+// pomVersionProperties sono le proprietà Maven lette, in ordine di priorità.
+//
+// maven.compiler.release vince su source/target perché è quella effettivamente
+// usata da javac; java.version è la convenzione di Spring Boot.
+var pomVersionProperties = []string{"maven.compiler.release", "maven.compiler.source", "maven.compiler.target", "java.version"}
+
+// pomReleaseTag cattura <release> nella configurazione del maven-compiler-plugin.
+var pomReleaseTag = regexp.MustCompile(`<release>\s*([^<\s]+)\s*</release>`)
+
+// gradleVersionPatterns riconoscono le dichiarazioni più comuni in build.gradle(.kts), in ordine di priorità.
+var gradleVersionPatterns = []struct {
+	Field   string
+	Pattern *regexp.Regexp
+}{
+	{"JavaLanguageVersion.of", regexp.MustCompile(`JavaLanguageVersion\.of\(\s*["']?(\d+)["']?\s*\)`)},
+	{"sourceCompatibility", regexp.MustCompile(`sourceCompatibility\s*=\s*(?:JavaVersion\.VERSION_([\d_]+)|["']?([\d.]+)["']?)`)},
+	{"targetCompatibility", regexp.MustCompile(`targetCompatibility\s*=\s*(?:JavaVersion\.VERSION_([\d_]+)|["']?([\d.]+)["']?)`)},
+}
+
+// DetectBuildRequirement legge la versione Java richiesta dai file di build presenti in dir.
+//
+// Il parsing è euristico (nessuna valutazione di Maven o Gradle), per questo
+// 'jenvy use' lo attiva solo con --from-build. File esaminati, in ordine:
+//  1. **pom.xml**: maven.compiler.release/source/target, java.version o <release>
+//     del compiler plugin; i riferimenti ${proprietà} vengono risolti una volta
+//  2. **build.gradle.kts / build.gradle**: JavaLanguageVersion.of(N) del toolchain,
+//     sourceCompatibility o targetCompatibility
+//
+// Le versioni legacy "1.8" vengono normalizzate a 8.
+//
+// Esempio di utilizzo:
+//
+//	if req, ok := utils.DetectBuildRequirement("."); ok {
+//	    fmt.Printf("%s requires Java %d (%s)\n", req.Source, req.Major, req.Field)
+//	}
+func DetectBuildRequirement(dir string) (BuildRequirement, bool) {
+	pomPath := filepath.Join(dir, "pom.xml")
+	if data, err := os.ReadFile(pomPath); err == nil {
+		if major, field, ok := parsePomRequirement(string(data)); ok {
+			return BuildRequirement{Major: major, Source: pomPath, Field: field}, true
+		}
+	}
+	for _, name := range []string{"build.gradle.kts", "build.gradle"} {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if major, field, ok := parseGradleRequirement(string(data)); ok {
+			return BuildRequirement{Major: major, Source: path, Field: field}, true
+		}
+	}
+	return BuildRequirement{}, false
+}
+
+// parsePomRequirement estrae la versione Java dal contenuto di un pom.xml.
+func parsePomRequirement(pom string) (int, string, bool) {
+	properties := make(map[string]string)
+	for _, name := range pomVersionProperties {
+		pattern := regexp.MustCompile(`<` + regexp.QuoteMeta(name) + `>\s*([^<\s]+)\s*</` + regexp.QuoteMeta(name) + `>`)
+		if match := pattern.FindStringSubmatch(pom); match != nil {
+			properties[name] = match[1]
+		}
+	}
+
+	resolve := func(value string) string {
+		if strings.HasPrefix(value, "${") && strings.HasSuffix(value, "}") {
+			return properties[strings.TrimSuffix(strings.TrimPrefix(value, "${"), "}")]
+		}
+		return value
+	}
+
+	for _, name := range pomVersionProperties {
+		if major, ok := parseRequiredMajor(resolve(properties[name])); ok {
+			return major, name, true
+		}
+	}
+	if match := pomReleaseTag.FindStringSubmatch(pom); match != nil {
+		if major, ok := parseRequiredMajor(resolve(match[1])); ok {
+			return major, "maven-compiler-plugin <release>", true
+		}
+	}
+	return 0, "", false
+}
+
+// parseGradleRequirement estrae la versione Java dal contenuto di un build.gradle(.kts).
+func parseGradleRequirement(script string) (int, string, bool) {
+	for _, candidate := range gradleVersionPatterns {
+		match := candidate.Pattern.FindStringSubmatch(script)
+		if match == nil {
+			continue
+		}
+		for _, group := range match[1:] {
+			if major, ok := parseRequiredMajor(strings.ReplaceAll(group, "_", ".")); ok {
+				return major, candidate.Field, true
+			}
+		}
+	}
+	return 0, "", false
+}
+
+// parseRequiredMajor converte "17", "1.8" o "11.0" nella versione major.
+func parseRequiredMajor(value string) (int, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	value = strings.TrimPrefix(value, "1.")
+	if idx := strings.Index(value, "."); idx != -1 {
+		value = value[:idx]
+	}
+	major, err := strconv.Atoi(value)
+	if err != nil || major <= 0 {
+		return 0, false
+	}
+	return major, true
+}
@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultCacheTTL è la validità dei metadati dei provider in cache se cache-ttl non è impostata.
+const DefaultCacheTTL = time.Hour
+
+// refreshCache è impostata da --refresh: i metadati vengono riscaricati ignorando la cache.
+var refreshCache bool
+
+// CacheDir restituisce la directory della cache dei metadati dei provider (~/.jenvy/cache).
+func CacheDir() (string, error) {
+	jenvyDir, err := JenvyHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(jenvyDir, "cache"), nil
+}
+
+// ParseCacheTTL interpreta la validità della cache come durata Go ("30m", "6h"); "0" disattiva la cache.
+func ParseCacheTTL(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "0" {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl < 0 {
+		return 0, fmt.Errorf("invalid cache TTL '%s' (expected e.g. 30m, 6h or 0 to disable)", value)
+	}
+	return ttl, nil
+}
+
+// CacheTTL restituisce la validità della cache secondo la configurazione (0 = cache disattivata).
+func CacheTTL(cfg *Config) time.Duration {
+	if cfg == nil || cfg.CacheTTL == "" {
+		return DefaultCacheTTL
+	}
+	ttl, err := ParseCacheTTL(cfg.CacheTTL)
+	if err != nil {
+		return DefaultCacheTTL
+	}
+	return ttl
+}
+
+// StripRefreshFlag rimuove l'opzione globale --refresh dagli argomenti.
+//
+// Come --yes viene tolta prima del dispatch, così vale per ogni comando che
+// interroga i provider (remote-list, download, upgrade, completamento).
+//
+// Restituisce:
+//
+//	[]string - Argomenti senza l'opzione
+//	bool     - true se l'opzione era presente
+func StripRefreshFlag(args []string) ([]string, bool) {
+	options, rest := cutOptions(args)
+	kept := make([]string, 0, len(args))
+	found := false
+	for i, arg := range options {
+		if i > 0 && arg == "--refresh" {
+			found = true
+			continue
+		}
+		kept = append(kept, arg)
+	}
+	return append(kept, rest...), found
+}
+
+// SetRefreshCache fa ignorare la cache dei metadati per l'esecuzione corrente.
+func SetRefreshCache(enabled bool) {
+	refreshCache = enabled
+}
+
+// cachePath restituisce il file di cache associato a un URL.
+func cachePath(dir, url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// CachedGet scarica i metadati di un provider passando per la cache di ~/.jenvy/cache.
+//
+// Una risposta salvata da meno di cache-ttl (predefinito DefaultCacheTTL) viene
+// servita dal disco senza accedere alla rete; altrimenti la GET passa da
+// HTTPGet e il corpo viene salvato solo se lo stato è 200, così una pagina di
+// errore non resta in cache. Con --refresh la cache viene ignorata ma
+// aggiornata. Un errore nella scrittura della cache non è mai fatale.
+//
+// Parametri:
+//
+//	url string - Endpoint dei metadati (il file di cache è lo SHA-256 dell'URL, dopo MirrorURL)
+//
+// Restituisce:
+//
+//	[]byte - Corpo della risposta
+//	int    - Codice HTTP (200 se servito dalla cache)
+//	error  - Errore di rete o di lettura
+//
+// Esempio di utilizzo:
+//
+//	body, status, err := utils.CachedGet(packagesURL)
+func CachedGet(url string) ([]byte, int, error) {
+	cfg, _ := LoadConfigOrDefault()
+	ttl := CacheTTL(cfg)
+	dir, dirErr := CacheDir()
+	useCache := ttl > 0 && dirErr == nil
+
+	if useCache && !refreshCache {
+		path := cachePath(dir, MirrorURL(url))
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) < ttl {
+			if body, err := os.ReadFile(path); err == nil {
+				return body, http.StatusOK, nil
+			}
+		}
+	}
+
+	resp, err := HTTPGet(url)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+	if useCache && resp.StatusCode == http.StatusOK {
+		writeCacheFile(dir, cachePath(dir, MirrorURL(url)), body)
+	}
+	return body, resp.StatusCode, nil
+}
+
+// writeCacheFile salva body in path passando da un file temporaneo, per non lasciare cache troncate.
+func writeCacheFile(dir, path string, body []byte) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(dir, "*.tmp")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(body)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil || os.Rename(tmp.Name(), path) != nil {
+		os.Remove(tmp.Name())
+	}
+}
@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+)
+
+// checksumAlgorithms associa i nomi accettati da --checksum-algorithm al costruttore dell'hash.
+var checksumAlgorithms = map[string]func() hash.Hash{
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// NewChecksumHash restituisce l'hash.Hash corrispondente a un algoritmo ("sha256", "sha512").
+//
+// Il nome non distingue maiuscole/minuscole e accetta il trattino ("SHA-512").
+func NewChecksumHash(algorithm string) (hash.Hash, error) {
+	name := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(algorithm)), "-", "")
+	newHash, ok := checksumAlgorithms[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported checksum algorithm '%s' (use sha256 or sha512)", algorithm)
+	}
+	return newHash(), nil
+}
+
+// DetectChecksumAlgorithm deduce l'algoritmo dalla lunghezza di un checksum esadecimale.
+//
+// Usato quando il provider pubblica il checksum senza indicarne l'algoritmo:
+// 64 caratteri corrispondono a SHA-256, 128 a SHA-512. Restituisce "" se la
+// lunghezza non corrisponde a nessun algoritmo supportato.
+func DetectChecksumAlgorithm(checksum string) string {
+	switch len(strings.TrimSpace(checksum)) {
+	case sha256.Size * 2:
+		return "sha256"
+	case sha512.Size * 2:
+		return "sha512"
+	default:
+		return ""
+	}
+}
+
+// FileChecksum calcola il checksum esadecimale di un file con l'algoritmo indicato.
+func FileChecksum(path, algorithm string) (string, error) {
+	h, err := NewChecksumHash(algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(h, file); err != nil {
+		return "", fmt.Errorf("reading %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// VerifyFileChecksum verifica che un file corrisponda al checksum pubblicato dal provider.
+//
+// Parametri:
+//
+//	path string      - File da verificare (es. archivio JDK scaricato)
+//	algorithm string - "sha256" o "sha512"; vuoto per dedurlo dalla lunghezza di expected
+//	expected string  - Checksum esadecimale atteso
+//
+// Restituisce:
+//
+//	error - nil se il checksum coincide, errore di lettura, algoritmo o mismatch altrimenti
+func VerifyFileChecksum(path, algorithm, expected string) error {
+	verifier, err := NewChecksumVerifier(algorithm, expected)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(verifier, file); err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	return verifier.Verify()
+}
+
+// ChecksumVerifier calcola il checksum di un flusso mentre viene letto, per
+// verificarlo senza rileggere un file (es. con io.TeeReader durante un download).
+type ChecksumVerifier struct {
+	hash.Hash
+	algorithm string
+	expected  string
+}
+
+// NewChecksumVerifier prepara la verifica di un checksum esadecimale atteso.
+//
+// Con algorithm vuoto l'algoritmo è dedotto dalla lunghezza di expected
+// (vedi DetectChecksumAlgorithm).
+//
+// Esempio di utilizzo:
+//
+//	verifier, err := NewChecksumVerifier("", release.Checksum)
+//	_, err = io.Copy(out, io.TeeReader(resp.Body, verifier))
+//	err = verifier.Verify()
+func NewChecksumVerifier(algorithm, expected string) (*ChecksumVerifier, error) {
+	expected = strings.ToLower(strings.TrimSpace(expected))
+	if algorithm == "" {
+		algorithm = DetectChecksumAlgorithm(expected)
+		if algorithm == "" {
+			return nil, fmt.Errorf("cannot determine checksum algorithm for '%s'", expected)
+		}
+	}
+	h, err := NewChecksumHash(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	return &ChecksumVerifier{Hash: h, algorithm: algorithm, expected: expected}, nil
+}
+
+// Algorithm restituisce l'algoritmo usato, anche se dedotto dalla lunghezza del checksum.
+func (v *ChecksumVerifier) Algorithm() string {
+	return v.algorithm
+}
+
+// Verify confronta il checksum dei dati letti finora con quello atteso.
+func (v *ChecksumVerifier) Verify() error {
+	actual := hex.EncodeToString(v.Sum(nil))
+	if actual != v.expected {
+		return fmt.Errorf("%s checksum mismatch: expected %s, got %s", strings.ToUpper(v.algorithm), v.expected, actual)
+	}
+	return nil
+}
@@ -1,7 +1,6 @@
 package utils
 
 import (
-	"fmt"
 	"runtime"
 )
 
@@ -99,42 +98,46 @@ func supportsColor() bool {
 }
 
 // Print colored text functions
+// PrintError e PrintWarning vengono registrati anche nel log diagnostico (vedi StartLogging).
+// Con una barra di avanzamento attiva il testo compare sopra la barra (vedi printLine).
 func PrintError(text string) {
-	fmt.Println(ErrorText(text))
+	printLine(ErrorText(text))
+	logMessage("ERROR", text)
 }
 
 func PrintSuccess(text string) {
-	fmt.Println(SuccessText(text))
+	printLine(SuccessText(text))
 }
 
 func PrintInfo(text string) {
-	fmt.Println(InfoText(text))
+	printLine(InfoText(text))
 }
 
 func PrintWarning(text string) {
-	fmt.Println(WarningText(text))
+	printLine(WarningText(text))
+	logMessage("WARN", text)
 }
 
 func PrintFetch(text string) {
-	fmt.Println(FetchText(text))
+	printLine(FetchText(text))
 }
 
 func PrintSearch(text string) {
-	fmt.Println(SearchText(text))
+	printLine(SearchText(text))
 }
 
 func PrintDownload(text string) {
-	fmt.Println(DownloadText(text))
+	printLine(DownloadText(text))
 }
 
 func PrintReady(text string) {
-	fmt.Println(ReadyText(text))
+	printLine(ReadyText(text))
 }
 
 func PrintUsage(text string) {
-	fmt.Println(UsageText(text))
+	printLine(UsageText(text))
 }
 
 func PrintSection(text string) {
-	fmt.Println(SectionText(text))
+	printLine(SectionText(text))
 }
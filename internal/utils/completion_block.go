@@ -0,0 +1,114 @@
+package utils
+
+import "strings"
+
+// Sentinel che delimitano il blocco di completamento jenvy nei profili shell.
+//
+// Il blocco è scritto da 'jenvy completion install' e rimosso da
+// 'jenvy completion uninstall': tutto ciò che sta tra le due righe appartiene a
+// Jenvy e può essere sostituito senza toccare il resto del profilo utente.
+const (
+	CompletionBlockBegin = "# >>> jenvy completion >>>"
+	CompletionBlockEnd   = "# <<< jenvy completion <<<"
+
+	// legacyCompletionHeader è l'intestazione usata prima dell'introduzione delle sentinel
+	legacyCompletionHeader = "# Jenvy completion"
+)
+
+// WrapCompletionBlock racchiude uno script di completamento tra le sentinel jenvy.
+func WrapCompletionBlock(script string) string {
+	return CompletionBlockBegin + "\n" + strings.TrimRight(script, "\n") + "\n" + CompletionBlockEnd + "\n"
+}
+
+// RemoveCompletionBlock elimina i blocchi di completamento jenvy dal contenuto di un profilo.
+//
+// Riconosce sia i blocchi delimitati dalle sentinel sia quelli scritti dalle
+// versioni precedenti, che iniziano con "# Jenvy completion" e terminano con la
+// prima riga uguale a legacyEnd (es. "complete -F _jenvy_completion jenvy" per
+// Bash o "}" per PowerShell). La riga vuota che precede il blocco viene rimossa
+// insieme ad esso.
+//
+// Parametri:
+//
+//	content string   - Contenuto attuale del profilo
+//	legacyEnd string - Ultima riga dei blocchi senza sentinel
+//
+// Restituisce:
+//
+//	string - Contenuto senza blocchi jenvy
+//	bool   - true se almeno un blocco è stato rimosso
+func RemoveCompletionBlock(content, legacyEnd string) (string, bool) {
+	lines := strings.Split(content, "\n")
+	var kept []string
+	removed := false
+	endLine := ""
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if endLine == "" {
+			switch trimmed {
+			case CompletionBlockBegin:
+				endLine = CompletionBlockEnd
+			case legacyCompletionHeader:
+				endLine = legacyEnd
+			default:
+				kept = append(kept, line)
+				continue
+			}
+			removed = true
+			if len(kept) > 0 && strings.TrimSpace(kept[len(kept)-1]) == "" {
+				kept = kept[:len(kept)-1]
+			}
+			continue
+		}
+		if trimmed == endLine {
+			endLine = ""
+		}
+	}
+
+	if !removed {
+		return content, false
+	}
+	return strings.Join(kept, "\n"), true
+}
+
+// Esito dell'installazione di un blocco di completamento in un profilo shell
+const (
+	CompletionInstalled = "installed"       // Blocco aggiunto per la prima volta
+	CompletionUpdated   = "updated"         // Blocco esistente sostituito con la versione corrente
+	CompletionCurrent   = "already current" // Blocco già identico allo script generato
+)
+
+// UpdateCompletionBlock calcola il nuovo contenuto di un profilo con il blocco di completamento aggiornato.
+//
+// Se il profilo contiene già un blocco identico allo script corrente non cambia nulla;
+// se contiene un blocco diverso (con sentinel o delle versioni precedenti) lo
+// sostituisce; altrimenti aggiunge il blocco in coda. In questo modo il completamento
+// segue l'evoluzione di Jenvy invece di restare fermo alla prima installazione.
+//
+// Parametri:
+//
+//	content string   - Contenuto attuale del profilo (vuoto se il file non esiste)
+//	script string    - Script di completamento generato dalla versione corrente
+//	legacyEnd string - Ultima riga dei blocchi senza sentinel (vedi RemoveCompletionBlock)
+//
+// Restituisce:
+//
+//	string - Nuovo contenuto del profilo
+//	string - CompletionInstalled, CompletionUpdated o CompletionCurrent
+func UpdateCompletionBlock(content, script, legacyEnd string) (string, string) {
+	block := WrapCompletionBlock(script)
+	if strings.Contains(content, "\n"+block) || strings.HasPrefix(content, block) {
+		return content, CompletionCurrent
+	}
+
+	status := CompletionInstalled
+	if cleaned, removed := RemoveCompletionBlock(content, legacyEnd); removed {
+		content = cleaned
+		status = CompletionUpdated
+	}
+
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content + "\n" + block, status
+}
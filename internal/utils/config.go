@@ -2,25 +2,776 @@ package utils
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 type Config struct {
-    PrivateEndpoint string `json:"private_endpoint"`
-    PrivateToken    string `json:"private_token"`
+	PrivateEndpoint string `json:"private_endpoint"`
+	PrivateToken    string `json:"private_token"`
+	GitHubToken     string `json:"github_token,omitempty"`
+	NamingScheme    string `json:"naming_scheme,omitempty"`
+
+	// PrivateType è il tipo di repository privato (json | artifactory | nexus | s3 | directory | github, vuoto = json);
+	// PrivateRepository il repository interrogato con artifactory e nexus
+	PrivateType       string `json:"private_type,omitempty"`
+	PrivateRepository string `json:"private_repository,omitempty"`
+
+	// PrivatePattern descrive il nome degli archivi nei repository senza indice (vedi CompileArtifactPattern)
+	PrivatePattern string `json:"private_pattern,omitempty"`
+
+	// KeepArchives decide se conservare l'archivio dopo l'estrazione (nil = chiedi all'utente)
+	KeepArchives *bool `json:"keep_archives,omitempty"`
+
+	// ActivationStyle sceglie come 'jenvy use' attiva un JDK (registry | junction, vuoto = registry)
+	ActivationStyle string `json:"activation_style,omitempty"`
+
+	// InstallOnUse fa scaricare a 'jenvy use' le versioni non installate (come 'use --install')
+	InstallOnUse bool `json:"install_on_use,omitempty"`
+
+	// NotifyOnComplete mostra una notifica Windows al termine di download, estrazioni e upgrade
+	NotifyOnComplete bool `json:"notify_on_complete,omitempty"`
+
+	// PreviousJavaHome è il JAVA_HOME attivo prima dell'ultimo 'jenvy use' (per 'jenvy use -')
+	PreviousJavaHome string `json:"previous_java_home,omitempty"`
+
+	// ExternalInstalls sono i JDK estratti fuori da ~/.jenvy/versions con 'download --extract-to'
+	ExternalInstalls []string `json:"external_installs,omitempty"`
+
+	// Aliases associa i nomi definiti con 'jenvy alias' a una versione (es. "default" → "21")
+	Aliases map[string]string `json:"aliases,omitempty"`
+
+	// DefaultFollowUpdates ripunta JAVA_HOME quando la major del JDK predefinito riceve una patch più recente
+	DefaultFollowUpdates bool `json:"default_follow_updates,omitempty"`
+
+	// NetworkTimeout e NetworkRetries sono i default di --timeout e --retries (vedi ApplyNetworkFlags)
+	NetworkTimeout string `json:"network_timeout,omitempty"`
+	NetworkRetries int    `json:"network_retries,omitempty"`
+
+	// LockTimeout è l'attesa massima del lock di ~/.jenvy (vedi AcquireLock); "0" per non attendere
+	LockTimeout string `json:"lock_timeout,omitempty"`
+
+	// ExtractMaxSize, ExtractMaxFiles e ExtractMaxRatio limitano l'estrazione degli archivi (vedi ArchiveGuard)
+	ExtractMaxSize  string `json:"extract_max_size,omitempty"`
+	ExtractMaxFiles int    `json:"extract_max_files,omitempty"`
+	ExtractMaxRatio int    `json:"extract_max_ratio,omitempty"`
+
+	// GraalVMEdition sceglie l'edizione servita dal provider graalvm (community | oracle, vuoto = community)
+	GraalVMEdition string `json:"graalvm_edition,omitempty"`
+
+	// LogRetention è il numero di giorni di log conservati in ~/.jenvy/logs (0 = DefaultLogRetention)
+	LogRetention int `json:"log_retention,omitempty"`
+
+	// CacheTTL è la validità dei metadati dei provider in ~/.jenvy/cache (vuoto = DefaultCacheTTL, "0" = disattivata)
+	CacheTTL string `json:"cache_ttl,omitempty"`
+
+	// Proxy per tutte le richieste HTTP (vuoto = HTTP_PROXY/HTTPS_PROXY), vedi LoadProxySettings
+	Proxy         string `json:"proxy,omitempty"`
+	NoProxy       string `json:"no_proxy,omitempty"`
+	ProxyAuth     string `json:"proxy_auth,omitempty"`
+	ProxyUser     string `json:"proxy_user,omitempty"`
+	ProxyPassword string `json:"proxy_password,omitempty"`
+
+	// CACert è un file o una directory di certificati CA aggiuntivi (vedi ApplyCACertFlag)
+	CACert string `json:"ca_cert,omitempty"`
+
+	// Mirror dei provider pubblici: basi che sostituiscono API e download originali (vedi MirrorURL)
+	AdoptiumAPIMirror      string `json:"adoptium_api_mirror,omitempty"`
+	AdoptiumDownloadMirror string `json:"adoptium_download_mirror,omitempty"`
+	AzulAPIMirror          string `json:"azul_api_mirror,omitempty"`
+	AzulDownloadMirror     string `json:"azul_download_mirror,omitempty"`
+	LibericaAPIMirror      string `json:"liberica_api_mirror,omitempty"`
+	LibericaDownloadMirror string `json:"liberica_download_mirror,omitempty"`
+	GraalVMAPIMirror       string `json:"graalvm_api_mirror,omitempty"`
+	GraalVMDownloadMirror  string `json:"graalvm_download_mirror,omitempty"`
 }
 
+// LoadConfig carica ~/.jenvy/config.json risolvendo i segreti salvati in Credential Manager.
+//
+// Alla prima lettura di un file con token o password in chiaro, questi vengono
+// spostati in Credential Manager (vedi migrateConfigSecrets).
 func LoadConfig() (*Config, error) {
-    home, _ := os.UserHomeDir()
-    path := filepath.Join(home, ".jenvy", "config.json")
-    file, err := os.Open(path)
-    if err != nil {
-        return nil, err
-    }
-    defer file.Close()
-
-    var cfg Config
-    err = json.NewDecoder(file).Decode(&cfg)
-    return &cfg, err
+	file, err := os.Open(ConfigPath())
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var cfg Config
+	if err := json.NewDecoder(file).Decode(&cfg); err != nil {
+		return &cfg, err
+	}
+	if resolveConfigSecrets(&cfg) {
+		migrateConfigSecrets(&cfg)
+	}
+	return &cfg, nil
+}
+
+// ConfigPath restituisce il percorso del file di configurazione Jenvy (~/.jenvy/config.json).
+func ConfigPath() string {
+	jenvyDir, _ := JenvyHome()
+	return filepath.Join(jenvyDir, "config.json")
+}
+
+// LoadConfigOrDefault carica la configurazione utente, restituendo una configurazione
+// vuota quando il file non esiste ancora.
+//
+// A differenza di LoadConfig, un file mancante non è considerato un errore: i comandi
+// che leggono un'opzione opzionale (es. naming scheme) possono così applicare i default
+// senza dover distinguere tra "non configurato" e "file assente".
+//
+// Restituisce:
+//
+//	*Config - Configurazione caricata (mai nil)
+//	error   - nil se file assente o letto correttamente, errore se JSON corrotto
+func LoadConfigOrDefault() (*Config, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Config{}, nil
+		}
+		return &Config{}, err
+	}
+	return cfg, nil
+}
+
+// SaveConfig salva la configurazione in ~/.jenvy/config.json preservando le chiavi sconosciute.
+//
+// Il file può contenere chiavi scritte da altre versioni di Jenvy (es. la configurazione
+// di default creata da 'jenvy init'): queste vengono lette come JSON generico e
+// reinserite nel file finale, così che la scrittura di un singolo campo non
+// cancelli impostazioni non gestite da questa struttura. Su Windows i segreti
+// (token e password) vanno in Credential Manager e nel file resta solo il
+// riferimento (vedi storeConfigSecrets).
+//
+// Parametri:
+//
+//	cfg *Config - Configurazione da salvare
+//
+// Restituisce:
+//
+//	error - nil se salvataggio completato, errore di I/O o encoding altrimenti
+func SaveConfig(cfg *Config) error {
+	path := ConfigPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+
+	merged := make(map[string]interface{})
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &merged) // Best-effort: un file corrotto viene sovrascritto
+	}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("encoding config: %w", err)
+	}
+	var known map[string]interface{}
+	if err := json.Unmarshal(data, &known); err != nil {
+		return fmt.Errorf("encoding config: %w", err)
+	}
+	storeConfigSecrets(known, merged)
+	for _, key := range configJSONKeys() {
+		delete(merged, key)
+	}
+	for key, value := range known {
+		merged[key] = value
+	}
+
+	out, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding config: %w", err)
+	}
+	return os.WriteFile(path, append(out, '\n'), 0644)
+}
+
+// ConfigKey descrive un'impostazione modificabile tramite 'jenvy config set'.
+//
+// Name è il nome usato da riga di comando (kebab-case), JSONKey la chiave
+// corrispondente in config.json (snake_case). Get e Set convertono il valore
+// da/verso la rappresentazione testuale validandolo. Secret indica che il valore
+// è una credenziale e va mascherato nell'output.
+type ConfigKey struct {
+	Name        string
+	JSONKey     string
+	Description string
+	Secret      bool
+	Get         func(cfg *Config) string
+	Set         func(cfg *Config, value string) error
+}
+
+// configKeys contiene tutte le impostazioni gestite da 'jenvy config'.
+//
+// Le chiavi dei mirror dei provider sono generate da mirrorConfigKeys.
+var configKeys = append([]ConfigKey{
+	{
+		Name:        "private-endpoint",
+		JSONKey:     "private_endpoint",
+		Description: "Private repository endpoint URL",
+		Get:         func(cfg *Config) string { return cfg.PrivateEndpoint },
+		Set: func(cfg *Config, value string) error {
+			cfg.PrivateEndpoint = value
+			return nil
+		},
+	},
+	{
+		Name:        "private-token",
+		JSONKey:     "private_token",
+		Description: "Private repository authentication token",
+		Secret:      true,
+		Get:         func(cfg *Config) string { return cfg.PrivateToken },
+		Set: func(cfg *Config, value string) error {
+			cfg.PrivateToken = value
+			return nil
+		},
+	},
+	{
+		Name:        "private-type",
+		JSONKey:     "private_type",
+		Description: "Private repository type: json index, Artifactory/Nexus search, S3 bucket, HTTP directory or GitHub releases (json|artifactory|nexus|s3|directory|github)",
+		Get:         func(cfg *Config) string { return PrivateType(cfg) },
+		Set: func(cfg *Config, value string) error {
+			value = strings.ToLower(strings.TrimSpace(value))
+			if value == "" {
+				cfg.PrivateType = ""
+				return nil
+			}
+			if err := ValidatePrivateType(value); err != nil {
+				return err
+			}
+			cfg.PrivateType = value
+			return nil
+		},
+	},
+	{
+		Name:        "private-repository",
+		JSONKey:     "private_repository",
+		Description: "Repository searched when private-type is artifactory or nexus (key prefix for s3, owner/repo list for github)",
+		Get:         func(cfg *Config) string { return cfg.PrivateRepository },
+		Set: func(cfg *Config, value string) error {
+			cfg.PrivateRepository = strings.TrimSpace(value)
+			return nil
+		},
+	},
+	{
+		Name:        "private-pattern",
+		JSONKey:     "private_pattern",
+		Description: "Archive file name pattern, e.g. jdk-{version}-{os}-{arch}.zip (empty = guess from the name)",
+		Get:         func(cfg *Config) string { return cfg.PrivatePattern },
+		Set: func(cfg *Config, value string) error {
+			value = strings.TrimSpace(value)
+			if value != "" {
+				if _, err := CompileArtifactPattern(value); err != nil {
+					return err
+				}
+			}
+			cfg.PrivatePattern = value
+			return nil
+		},
+	},
+	{
+		Name:        "github-token",
+		JSONKey:     "github_token",
+		Description: "GitHub token for release downloads (overridden by JENVY_GITHUB_TOKEN)",
+		Secret:      true,
+		Get:         func(cfg *Config) string { return cfg.GitHubToken },
+		Set: func(cfg *Config, value string) error {
+			cfg.GitHubToken = strings.TrimSpace(value)
+			return nil
+		},
+	},
+	{
+		Name:        "naming-scheme",
+		JSONKey:     "naming_scheme",
+		Description: "Install directory naming scheme (placeholders: {provider}, {version})",
+		Get: func(cfg *Config) string {
+			if cfg.NamingScheme == "" {
+				return DefaultNamingScheme
+			}
+			return cfg.NamingScheme
+		},
+		Set: func(cfg *Config, value string) error {
+			if value == "" {
+				cfg.NamingScheme = ""
+				return nil
+			}
+			if err := ValidateNamingScheme(value); err != nil {
+				return err
+			}
+			cfg.NamingScheme = value
+			return nil
+		},
+	},
+	{
+		Name:        "keep-archives",
+		JSONKey:     "keep_archives",
+		Description: "Keep downloaded archives after extraction (true|false, unset = ask)",
+		Get: func(cfg *Config) string {
+			if cfg.KeepArchives == nil {
+				return ""
+			}
+			return strconv.FormatBool(*cfg.KeepArchives)
+		},
+		Set: func(cfg *Config, value string) error {
+			if value == "" {
+				cfg.KeepArchives = nil
+				return nil
+			}
+			keep, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("expected true or false, got '%s'", value)
+			}
+			cfg.KeepArchives = &keep
+			return nil
+		},
+	},
+	{
+		Name:        "notify-on-complete",
+		JSONKey:     "notify_on_complete",
+		Description: "Show a Windows notification when download, extract or upgrade finishes (true|false)",
+		Get:         func(cfg *Config) string { return strconv.FormatBool(cfg.NotifyOnComplete) },
+		Set: func(cfg *Config, value string) error {
+			if value == "" {
+				cfg.NotifyOnComplete = false
+				return nil
+			}
+			notify, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("expected true or false, got '%s'", value)
+			}
+			cfg.NotifyOnComplete = notify
+			return nil
+		},
+	},
+	{
+		Name:        "network-timeout",
+		JSONKey:     "network_timeout",
+		Description: "Timeout of every network request, e.g. 90s or 5m (overridden by --timeout and JENVY_TIMEOUT)",
+		Get:         func(cfg *Config) string { return cfg.NetworkTimeout },
+		Set: func(cfg *Config, value string) error {
+			if value == "" {
+				cfg.NetworkTimeout = ""
+				return nil
+			}
+			if _, err := ParseNetworkTimeout(value); err != nil {
+				return err
+			}
+			cfg.NetworkTimeout = value
+			return nil
+		},
+	},
+	{
+		Name:        "network-retries",
+		JSONKey:     "network_retries",
+		Description: "Retries after network errors or 5xx responses (overridden by --retries and JENVY_RETRIES)",
+		Get:         func(cfg *Config) string { return strconv.Itoa(cfg.NetworkRetries) },
+		Set: func(cfg *Config, value string) error {
+			if value == "" {
+				cfg.NetworkRetries = 0
+				return nil
+			}
+			retries, err := ParseNetworkRetries(value)
+			if err != nil {
+				return err
+			}
+			cfg.NetworkRetries = retries
+			return nil
+		},
+	},
+	{
+		Name:        "lock-timeout",
+		JSONKey:     "lock_timeout",
+		Description: "How long to wait for another jenvy process using ~/.jenvy, e.g. 30m or 0 to fail at once (default: 10m)",
+		Get:         func(cfg *Config) string { return cfg.LockTimeout },
+		Set: func(cfg *Config, value string) error {
+			if value == "" {
+				cfg.LockTimeout = ""
+				return nil
+			}
+			if _, err := ParseLockTimeout(value); err != nil {
+				return err
+			}
+			cfg.LockTimeout = value
+			return nil
+		},
+	},
+	{
+		Name:        "extract-max-size",
+		JSONKey:     "extract_max_size",
+		Description: "Largest size an archive may extract to, e.g. 16GB (default: 8GB)",
+		Get:         func(cfg *Config) string { return FormatBytes(ArchiveExtractLimits(cfg).MaxSize) },
+		Set: func(cfg *Config, value string) error {
+			if value == "" {
+				cfg.ExtractMaxSize = ""
+				return nil
+			}
+			if _, err := ParseByteSize(value); err != nil {
+				return err
+			}
+			cfg.ExtractMaxSize = strings.TrimSpace(value)
+			return nil
+		},
+	},
+	{
+		Name:        "extract-max-files",
+		JSONKey:     "extract_max_files",
+		Description: "Most entries an archive may contain (default: 100000)",
+		Get:         func(cfg *Config) string { return strconv.Itoa(ArchiveExtractLimits(cfg).MaxFiles) },
+		Set: func(cfg *Config, value string) error {
+			if value == "" {
+				cfg.ExtractMaxFiles = 0
+				return nil
+			}
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 1 {
+				return fmt.Errorf("expected a number >= 1, got '%s'", value)
+			}
+			cfg.ExtractMaxFiles = n
+			return nil
+		},
+	},
+	{
+		Name:        "extract-max-ratio",
+		JSONKey:     "extract_max_ratio",
+		Description: "Highest compression ratio accepted while extracting, in extracted bytes per archive byte (default: 100)",
+		Get:         func(cfg *Config) string { return strconv.Itoa(ArchiveExtractLimits(cfg).MaxRatio) },
+		Set: func(cfg *Config, value string) error {
+			if value == "" {
+				cfg.ExtractMaxRatio = 0
+				return nil
+			}
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 1 {
+				return fmt.Errorf("expected a number >= 1, got '%s'", value)
+			}
+			cfg.ExtractMaxRatio = n
+			return nil
+		},
+	},
+	{
+		Name:        "log-retention",
+		JSONKey:     "log_retention",
+		Description: "Days of diagnostic logs kept in ~/.jenvy/logs (older files are deleted)",
+		Get:         func(cfg *Config) string { return strconv.Itoa(LogRetentionDays(cfg)) },
+		Set: func(cfg *Config, value string) error {
+			if value == "" {
+				cfg.LogRetention = 0
+				return nil
+			}
+			days, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || days < 1 {
+				return fmt.Errorf("expected a number of days >= 1, got '%s'", value)
+			}
+			cfg.LogRetention = days
+			return nil
+		},
+	},
+	{
+		Name:        "cache-ttl",
+		JSONKey:     "cache_ttl",
+		Description: "How long provider metadata stays in ~/.jenvy/cache, e.g. 30m or 6h (0 disables; --refresh bypasses)",
+		Get:         func(cfg *Config) string { return CacheTTL(cfg).String() },
+		Set: func(cfg *Config, value string) error {
+			if value == "" {
+				cfg.CacheTTL = ""
+				return nil
+			}
+			if _, err := ParseCacheTTL(value); err != nil {
+				return err
+			}
+			cfg.CacheTTL = strings.TrimSpace(value)
+			return nil
+		},
+	},
+	{
+		Name:        "proxy",
+		JSONKey:     "proxy",
+		Description: "HTTP(S) proxy for provider APIs and downloads, e.g. http://proxy.corp:8080 (overrides HTTP_PROXY/HTTPS_PROXY)",
+		Get: func(cfg *Config) string {
+			if proxyURL, err := ParseProxyURL(cfg.Proxy); err == nil && cfg.Proxy != "" {
+				return proxyURL.Redacted()
+			}
+			return cfg.Proxy
+		},
+		Set: func(cfg *Config, value string) error {
+			if value == "" {
+				cfg.Proxy = ""
+				return nil
+			}
+			if _, err := ParseProxyURL(value); err != nil {
+				return err
+			}
+			cfg.Proxy = strings.TrimSpace(value)
+			return nil
+		},
+	},
+	{
+		Name:        "no-proxy",
+		JSONKey:     "no_proxy",
+		Description: "Comma-separated hosts, domains or CIDRs reached without proxy (overrides NO_PROXY)",
+		Get:         func(cfg *Config) string { return cfg.NoProxy },
+		Set: func(cfg *Config, value string) error {
+			cfg.NoProxy = strings.TrimSpace(value)
+			return nil
+		},
+	},
+	{
+		Name:        "proxy-auth",
+		JSONKey:     "proxy_auth",
+		Description: "Proxy authentication scheme used with proxy-user (basic|ntlm)",
+		Get: func(cfg *Config) string {
+			if cfg.ProxyAuth == "" {
+				return ProxyAuthBasic
+			}
+			return cfg.ProxyAuth
+		},
+		Set: func(cfg *Config, value string) error {
+			value = strings.ToLower(strings.TrimSpace(value))
+			if value == "" {
+				cfg.ProxyAuth = ""
+				return nil
+			}
+			if err := ValidateProxyAuth(value); err != nil {
+				return err
+			}
+			cfg.ProxyAuth = value
+			return nil
+		},
+	},
+	{
+		Name:        "proxy-user",
+		JSONKey:     "proxy_user",
+		Description: "Proxy user name (DOMAIN\\user for NTLM)",
+		Get:         func(cfg *Config) string { return cfg.ProxyUser },
+		Set: func(cfg *Config, value string) error {
+			cfg.ProxyUser = strings.TrimSpace(value)
+			return nil
+		},
+	},
+	{
+		Name:        "proxy-password",
+		JSONKey:     "proxy_password",
+		Description: "Proxy password",
+		Secret:      true,
+		Get:         func(cfg *Config) string { return cfg.ProxyPassword },
+		Set: func(cfg *Config, value string) error {
+			cfg.ProxyPassword = value
+			return nil
+		},
+	},
+	{
+		Name:        "ca-cert",
+		JSONKey:     "ca_cert",
+		Description: "Extra CA certificate file or directory trusted for HTTPS, e.g. a TLS-inspecting proxy's root (overridden by --ca-cert)",
+		Get:         func(cfg *Config) string { return cfg.CACert },
+		Set: func(cfg *Config, value string) error {
+			if value == "" {
+				cfg.CACert = ""
+				return nil
+			}
+			path, err := filepath.Abs(strings.TrimSpace(value))
+			if err != nil {
+				return err
+			}
+			if _, err := LoadCACertPool(path); err != nil {
+				return err
+			}
+			cfg.CACert = path
+			return nil
+		},
+	},
+	{
+		Name:        "install-on-use",
+		JSONKey:     "install_on_use",
+		Description: "Let 'use' download a version that is not installed, from the default provider (true|false)",
+		Get:         func(cfg *Config) string { return strconv.FormatBool(cfg.InstallOnUse) },
+		Set: func(cfg *Config, value string) error {
+			if value == "" {
+				cfg.InstallOnUse = false
+				return nil
+			}
+			install, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("expected true or false, got '%s'", value)
+			}
+			cfg.InstallOnUse = install
+			return nil
+		},
+	},
+	{
+		Name:        "default-follow-updates",
+		JSONKey:     "default_follow_updates",
+		Description: "Re-point JAVA_HOME when a newer patch of the default JDK's major is installed (true|false)",
+		Get:         func(cfg *Config) string { return strconv.FormatBool(cfg.DefaultFollowUpdates) },
+		Set: func(cfg *Config, value string) error {
+			if value == "" {
+				cfg.DefaultFollowUpdates = false
+				return nil
+			}
+			follow, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("expected true or false, got '%s'", value)
+			}
+			cfg.DefaultFollowUpdates = follow
+			return nil
+		},
+	},
+	{
+		Name:        "graalvm-edition",
+		JSONKey:     "graalvm_edition",
+		Description: "GraalVM edition used by --provider=graalvm (community = GraalVM CE, oracle = Oracle GraalVM under GFTC)",
+		Get: func(cfg *Config) string {
+			if cfg.GraalVMEdition == "" {
+				return GraalVMEditionCommunity
+			}
+			return cfg.GraalVMEdition
+		},
+		Set: func(cfg *Config, value string) error {
+			value = strings.ToLower(value)
+			if value == "" {
+				cfg.GraalVMEdition = ""
+				return nil
+			}
+			if err := ValidateGraalVMEdition(value); err != nil {
+				return err
+			}
+			cfg.GraalVMEdition = value
+			return nil
+		},
+	},
+	{
+		Name:        "activation-style",
+		JSONKey:     "activation_style",
+		Description: "How 'use' activates a JDK (registry = set JAVA_HOME, junction = repoint ~/.jenvy/current)",
+		Get: func(cfg *Config) string {
+			if cfg.ActivationStyle == "" {
+				return ActivationStyleRegistry
+			}
+			return cfg.ActivationStyle
+		},
+		Set: func(cfg *Config, value string) error {
+			value = strings.ToLower(value)
+			if value == "" {
+				cfg.ActivationStyle = ""
+				return nil
+			}
+			if err := ValidateActivationStyle(value); err != nil {
+				return err
+			}
+			cfg.ActivationStyle = value
+			return nil
+		},
+	},
+}, mirrorConfigKeys()...)
+
+// ConfigKeys restituisce le impostazioni configurabili ordinate per nome.
+func ConfigKeys() []ConfigKey {
+	keys := make([]ConfigKey, len(configKeys))
+	copy(keys, configKeys)
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+	return keys
+}
+
+// FindConfigKey cerca un'impostazione per nome CLI (es. "naming-scheme") o chiave JSON.
+func FindConfigKey(name string) (ConfigKey, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, key := range configKeys {
+		if key.Name == name || key.JSONKey == name {
+			return key, true
+		}
+	}
+	return ConfigKey{}, false
+}
+
+// SetConfigValue imposta il valore di una chiave di configurazione validandolo.
+//
+// Parametri:
+//
+//	cfg *Config  - Configurazione da modificare
+//	name string  - Nome della chiave (es. "naming-scheme")
+//	value string - Valore testuale da impostare
+//
+// Restituisce:
+//
+//	error - nil se impostato, errore se chiave sconosciuta o valore non valido
+func SetConfigValue(cfg *Config, name, value string) error {
+	key, ok := FindConfigKey(name)
+	if !ok {
+		return fmt.Errorf("unknown configuration key '%s'", name)
+	}
+	return key.Set(cfg, value)
+}
+
+// GetConfigValue restituisce il valore effettivo di una chiave di configurazione.
+func GetConfigValue(cfg *Config, name string) (string, error) {
+	key, ok := FindConfigKey(name)
+	if !ok {
+		return "", fmt.Errorf("unknown configuration key '%s'", name)
+	}
+	return key.Get(cfg), nil
+}
+
+// configJSONKeys restituisce le chiavi JSON gestite dalla struttura Config.
+func configJSONKeys() []string {
+	var keys []string
+	for _, key := range configKeys {
+		keys = append(keys, key.JSONKey)
+	}
+	return keys
+}
+
+// MaskedConfigValue sostituisce i valori segreti nell'output di configurazione.
+const MaskedConfigValue = "********"
+
+// IsSecretConfigKey indica se il valore di una chiave va mascherato nell'output.
+//
+// Riconosce le chiavi gestite marcate come Secret (per nome CLI o chiave JSON) e
+// le chiavi di credenziali scritte da versioni precedenti (password, token, api_key).
+func IsSecretConfigKey(name string) bool {
+	if key, ok := FindConfigKey(name); ok {
+		return key.Secret
+	}
+	switch strings.ToLower(name) {
+	case "password", "token", "api_key":
+		return true
+	}
+	return false
+}
+
+// EffectiveConfigSettings restituisce le impostazioni effettive da esportare come JSON.
+//
+// Parte dal contenuto grezzo di config.json (incluse le chiavi non gestite da Config),
+// sovrascrive le chiavi gestite con il loro valore effettivo (default compresi) e
+// maschera le credenziali con MaskedConfigValue, salvo che reveal sia true.
+//
+// Parametri:
+//
+//	raw map[string]interface{} - Contenuto del file (nil se assente)
+//	cfg *Config                - Configurazione caricata
+//	reveal bool                - Se true mostra le credenziali in chiaro (--reveal)
+//
+// Restituisce:
+//
+//	map[string]interface{} - Impostazioni indicizzate per chiave JSON
+func EffectiveConfigSettings(raw map[string]interface{}, cfg *Config, reveal bool) map[string]interface{} {
+	settings := make(map[string]interface{}, len(raw)+len(configKeys))
+	for key, value := range raw {
+		settings[key] = value
+	}
+	for _, key := range configKeys {
+		settings[key.JSONKey] = key.Get(cfg)
+	}
+
+	if !reveal {
+		for key, value := range settings {
+			if value != nil && value != "" && IsSecretConfigKey(key) {
+				settings[key] = MaskedConfigValue
+			}
+		}
+	}
+	return settings
 }
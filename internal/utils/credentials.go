@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// CredentialReferencePrefix precede in config.json i segreti salvati in Windows Credential Manager.
+//
+// Il file conserva solo il riferimento (es. "credman:jenvy/private_token"); il
+// valore è la credenziale generica con target "jenvy/private_token".
+const CredentialReferencePrefix = "credman:"
+
+// errCredentialNotFound indica un riferimento a una credenziale non presente in Credential Manager.
+var errCredentialNotFound = errors.New("credential not found in Windows Credential Manager")
+
+// migrateSecretsOnce limita la migrazione dei segreti in chiaro a un tentativo per processo.
+var migrateSecretsOnce sync.Once
+
+// credentialTarget restituisce il nome della credenziale di una chiave segreta.
+func credentialTarget(jsonKey string) string {
+	return "jenvy/" + jsonKey
+}
+
+// IsCredentialReference indica se un valore di config.json è un riferimento a Credential Manager.
+func IsCredentialReference(value string) bool {
+	return strings.HasPrefix(value, CredentialReferencePrefix)
+}
+
+// ResolveCredentialReference legge da Credential Manager il segreto indicato da un riferimento.
+//
+// I valori che non sono riferimenti vengono restituiti così come sono.
+func ResolveCredentialReference(value string) (string, error) {
+	if !IsCredentialReference(value) {
+		return value, nil
+	}
+	target := strings.TrimPrefix(value, CredentialReferencePrefix)
+	secret, err := readCredential(target)
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", target, err)
+	}
+	return secret, nil
+}
+
+// resolveConfigSecrets sostituisce i riferimenti delle chiavi segrete con i valori di Credential Manager.
+//
+// Un riferimento non risolvibile (es. config.json copiato da un altro computer)
+// lascia il valore vuoto, come se il segreto non fosse configurato.
+//
+// Restituisce:
+//
+//	bool - true se il file contiene segreti in chiaro da migrare
+func resolveConfigSecrets(cfg *Config) bool {
+	plaintext := false
+	for _, key := range configKeys {
+		if !key.Secret {
+			continue
+		}
+		value := key.Get(cfg)
+		if !IsCredentialReference(value) {
+			plaintext = plaintext || value != ""
+			continue
+		}
+		secret, _ := ResolveCredentialReference(value)
+		key.Set(cfg, secret)
+	}
+	return plaintext
+}
+
+// storeConfigSecrets salva in Credential Manager i segreti da scrivere in config.json.
+//
+// Ogni segreto non vuoto in known viene sostituito dal suo riferimento; per i
+// segreti svuotati viene eliminata la credenziale a cui puntava il file
+// precedente (previous). Se Credential Manager non è disponibile il valore
+// resta in chiaro, come nelle versioni precedenti.
+func storeConfigSecrets(known, previous map[string]interface{}) {
+	for _, key := range configKeys {
+		if !key.Secret {
+			continue
+		}
+		value, _ := known[key.JSONKey].(string)
+		target := credentialTarget(key.JSONKey)
+		if value == "" {
+			if old, _ := previous[key.JSONKey].(string); IsCredentialReference(old) {
+				deleteCredential(strings.TrimPrefix(old, CredentialReferencePrefix))
+			}
+			continue
+		}
+		if IsCredentialReference(value) || !credentialStoreAvailable() {
+			continue
+		}
+		if err := writeCredential(target, value); err != nil {
+			fmt.Fprintln(os.Stderr, WarningText(fmt.Sprintf("Could not store %s in Windows Credential Manager, keeping it in config.json: %v", key.Name, err)))
+			continue
+		}
+		known[key.JSONKey] = CredentialReferencePrefix + target
+	}
+}
+
+// migrateConfigSecrets sposta in Credential Manager i segreti in chiaro di un config.json esistente.
+//
+// La migrazione è best-effort e avviene al primo caricamento della
+// configurazione: se non riesce i segreti restano leggibili dal file. Gli
+// avvisi vanno su stderr per non alterare l'output di --json o di shell-init.
+func migrateConfigSecrets(cfg *Config) {
+	if !credentialStoreAvailable() {
+		return
+	}
+	migrateSecretsOnce.Do(func() {
+		if err := SaveConfig(cfg); err != nil {
+			fmt.Fprintln(os.Stderr, WarningText(fmt.Sprintf("Could not move secrets to Windows Credential Manager: %v", err)))
+			return
+		}
+		fmt.Fprintln(os.Stderr, InfoText("Moved tokens and passwords from config.json to Windows Credential Manager"))
+	})
+}
+
+// DeleteStoredSecrets elimina da Credential Manager tutti i segreti di Jenvy.
+//
+// Va chiamata quando config.json viene rimosso (config-reset, uninstall), così
+// che nessuna credenziale resti orfana. Fuori da Windows non fa nulla.
+//
+// Restituisce:
+//
+//	error - Primo errore di eliminazione, nil se tutte le credenziali sono state rimosse
+func DeleteStoredSecrets() error {
+	if !credentialStoreAvailable() {
+		return nil
+	}
+	var firstErr error
+	for _, key := range configKeys {
+		if !key.Secret {
+			continue
+		}
+		if err := deleteCredential(credentialTarget(key.JSONKey)); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("deleting %s: %w", credentialTarget(key.JSONKey), err)
+		}
+	}
+	return firstErr
+}
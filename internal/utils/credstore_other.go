@@ -0,0 +1,25 @@
+//go:build !windows
+
+package utils
+
+import "errors"
+
+// errCredentialStoreUnavailable è restituito fuori da Windows, dove Credential Manager non esiste.
+var errCredentialStoreUnavailable = errors.New("Windows Credential Manager is not available")
+
+// credentialStoreAvailable è sempre false fuori da Windows: i segreti restano in config.json.
+func credentialStoreAvailable() bool {
+	return false
+}
+
+func writeCredential(target, secret string) error {
+	return errCredentialStoreUnavailable
+}
+
+func readCredential(target string) (string, error) {
+	return "", errCredentialStoreUnavailable
+}
+
+func deleteCredential(target string) error {
+	return errCredentialStoreUnavailable
+}
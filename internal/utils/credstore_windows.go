@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"errors"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// Funzioni di Credential Manager (advapi32), non esposte da x/sys/windows.
+var (
+	advapi32       = windows.NewLazySystemDLL("advapi32.dll")
+	procCredWrite  = advapi32.NewProc("CredWriteW")
+	procCredRead   = advapi32.NewProc("CredReadW")
+	procCredDelete = advapi32.NewProc("CredDeleteW")
+	procCredFree   = advapi32.NewProc("CredFree")
+)
+
+// Valori di CREDENTIAL.Type e CREDENTIAL.Persist.
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+)
+
+// credential ricalca la struttura CREDENTIALW di Win32.
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        windows.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// credentialStoreAvailable indica se Credential Manager è utilizzabile.
+func credentialStoreAvailable() bool {
+	return procCredWrite.Find() == nil && procCredRead.Find() == nil &&
+		procCredDelete.Find() == nil && procCredFree.Find() == nil
+}
+
+// writeCredential salva secret come credenziale generica dell'utente corrente.
+//
+// Windows cifra il valore con DPAPI; CRED_PERSIST_LOCAL_MACHINE lo lega a
+// questo computer, senza propagarlo con i profili roaming.
+func writeCredential(target, secret string) error {
+	targetPtr, err := windows.UTF16PtrFromString(target)
+	if err != nil {
+		return err
+	}
+	userPtr, _ := windows.UTF16PtrFromString("jenvy")
+	blob := []byte(secret)
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         targetPtr,
+		CredentialBlobSize: uint32(len(blob)),
+		Persist:            credPersistLocalMachine,
+		UserName:           userPtr,
+	}
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+	if ok, _, err := procCredWrite.Call(uintptr(unsafe.Pointer(&cred)), 0); ok == 0 {
+		return err
+	}
+	return nil
+}
+
+// readCredential legge una credenziale scritta da writeCredential.
+//
+// Restituisce errCredentialNotFound se la credenziale non esiste.
+func readCredential(target string) (string, error) {
+	targetPtr, err := windows.UTF16PtrFromString(target)
+	if err != nil {
+		return "", err
+	}
+	var cred *credential
+	if ok, _, err := procCredRead.Call(uintptr(unsafe.Pointer(targetPtr)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred))); ok == 0 {
+		if errors.Is(err, windows.ERROR_NOT_FOUND) {
+			return "", errCredentialNotFound
+		}
+		return "", err
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+	if cred.CredentialBlobSize == 0 {
+		return "", nil
+	}
+	return string(unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)), nil
+}
+
+// deleteCredential elimina una credenziale; una credenziale assente non è un errore.
+func deleteCredential(target string) error {
+	targetPtr, err := windows.UTF16PtrFromString(target)
+	if err != nil {
+		return err
+	}
+	if ok, _, err := procCredDelete.Call(uintptr(unsafe.Pointer(targetPtr)), credTypeGeneric, 0); ok == 0 && !errors.Is(err, windows.ERROR_NOT_FOUND) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseSinceFilter interpreta il valore di --since come data assoluta o durata relativa.
+//
+// Formati accettati:
+//   - Data assoluta: "2024-06-01" oppure RFC3339 ("2024-06-01T00:00:00Z")
+//   - Durata relativa rispetto a now: "90d" (giorni), "12w" (settimane),
+//     "6m" (mesi), "1y" (anni), oppure una durata Go come "72h"
+//
+// Parametri:
+//
+//	value string  - Valore passato dall'utente
+//	now time.Time - Istante di riferimento per le durate relative
+//
+// Restituisce:
+//
+//	time.Time - Data minima di rilascio da mostrare
+//	error     - Errore se il formato non è riconosciuto
+//
+// Esempio di utilizzo:
+//
+//	since, err := ParseSinceFilter("6m", time.Now())  // ultimi sei mesi
+func ParseSinceFilter(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, fmt.Errorf("empty date")
+	}
+
+	if date, ok := ParseReleaseDate(value); ok {
+		return date, nil
+	}
+
+	if len(value) > 1 {
+		amount, err := strconv.Atoi(value[:len(value)-1])
+		if err == nil && amount >= 0 {
+			switch strings.ToLower(value[len(value)-1:]) {
+			case "d":
+				return now.AddDate(0, 0, -amount), nil
+			case "w":
+				return now.AddDate(0, 0, -7*amount), nil
+			case "m":
+				return now.AddDate(0, -amount, 0), nil
+			case "y":
+				return now.AddDate(-amount, 0, 0), nil
+			}
+		}
+	}
+
+	if duration, err := time.ParseDuration(value); err == nil && duration >= 0 {
+		return now.Add(-duration), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date or duration '%s' (use YYYY-MM-DD or e.g. 90d, 12w, 6m, 1y)", value)
+}
+
+// ParseReleaseDate converte la data di rilascio restituita dai provider.
+//
+// Accetta RFC3339 (Adoptium: "2023-10-17T12:34:56Z") e date semplici "YYYY-MM-DD"
+// (repository privati). Restituisce false per stringhe vuote o non riconosciute.
+func ParseReleaseDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// ReleasedSince indica se una release con data releaseDate rientra nel filtro --since.
+//
+// Un filtro zero (--since non specificato) accetta tutto; una data non disponibile
+// o non interpretabile viene esclusa, perché non è possibile dimostrare che la
+// release sia recente.
+func ReleasedSince(releaseDate string, since time.Time) bool {
+	if since.IsZero() {
+		return true
+	}
+	date, ok := ParseReleaseDate(releaseDate)
+	return ok && !date.Before(since)
+}
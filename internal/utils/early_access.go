@@ -0,0 +1,21 @@
+package utils
+
+// includeEarlyAccess è impostata da --ea: Adoptium e Liberica aggiungono le build early-access.
+var includeEarlyAccess bool
+
+// EarlyAccessMarker segue la versione delle build early-access nelle tabelle di remote-list.
+const EarlyAccessMarker = " (EA)"
+
+// SetIncludeEarlyAccess include le build early-access nei risultati dei provider (--ea).
+//
+// Le build EA considerate sono quelle delle major non ancora rilasciate (es.
+// JDK 25 mentre l'ultima GA è 24): servono a provare le prossime versioni, non
+// a sostituire gli aggiornamenti delle major già pubblicate.
+func SetIncludeEarlyAccess(enabled bool) {
+	includeEarlyAccess = enabled
+}
+
+// IncludeEarlyAccess indica se l'esecuzione corrente include le build early-access.
+func IncludeEarlyAccess() bool {
+	return includeEarlyAccess
+}
@@ -0,0 +1,60 @@
+package utils
+
+import "time"
+
+// ltsSupportEnd è la fine del supporto gratuito delle major LTS (Eclipse Temurin).
+//
+// Le date seguono la roadmap di Adoptium, che gli altri provider OpenJDK
+// rispettano o superano; vanno aggiornate a ogni nuova LTS.
+var ltsSupportEnd = map[int]time.Time{
+	8:  time.Date(2030, time.December, 1, 0, 0, 0, 0, time.UTC),
+	11: time.Date(2027, time.October, 1, 0, 0, 0, 0, time.UTC),
+	17: time.Date(2029, time.October, 1, 0, 0, 0, 0, time.UTC),
+	21: time.Date(2029, time.December, 1, 0, 0, 0, 0, time.UTC),
+	25: time.Date(2031, time.September, 1, 0, 0, 0, 0, time.UTC),
+}
+
+// majorReleaseDate calcola la data di rilascio di una major con la cadenza semestrale (da JDK 10, marzo 2018).
+func majorReleaseDate(major int) time.Time {
+	return time.Date(2018, time.March, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 6*(major-10), 0)
+}
+
+// MajorSupportEnd restituisce la data di fine supporto di una major JDK.
+//
+// Le LTS usano la tabella ltsSupportEnd; le altre major dalla 9 in poi sono
+// supportate solo fino all'uscita della successiva, sei mesi dopo. Le major
+// precedenti alla 8 sono sempre fuori supporto.
+//
+// Restituisce:
+//
+//	time.Time - Fine del supporto
+//	bool      - false per le LTS future non ancora in tabella (data ignota)
+//
+// Esempio di utilizzo:
+//
+//	end, ok := MajorSupportEnd(22) // → settembre 2024 (uscita di JDK 23), true
+func MajorSupportEnd(major int) (time.Time, bool) {
+	if end, ok := ltsSupportEnd[major]; ok {
+		return end, true
+	}
+	switch {
+	case major < 8:
+		return time.Time{}, true
+	case major > 25 && (major-25)%4 == 0:
+		// LTS successive alla 25 (una ogni due anni): data non ancora pubblicata
+		return time.Time{}, false
+	}
+	return majorReleaseDate(major + 1), true
+}
+
+// IsEOLMajor indica se una major JDK è fuori supporto alla data indicata.
+//
+// Esempio di utilizzo:
+//
+//	if IsEOLMajor(19, time.Now()) {
+//	    PrintWarning("JDK 19 is end-of-life")
+//	}
+func IsEOLMajor(major int, now time.Time) bool {
+	end, ok := MajorSupportEnd(major)
+	return ok && !now.Before(end)
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Codici di uscita di jenvy: il contratto su cui possono contare script e pipeline CI.
+const (
+	ExitOK         = 0 // Comando completato
+	ExitGeneric    = 1 // Errore generico o uso errato del comando
+	ExitNotFound   = 2 // Versione, installazione o risorsa richiesta non trovata
+	ExitNetwork    = 3 // Errore di rete, del provider o del download
+	ExitExtraction = 4 // Errore di estrazione o verifica dell'archivio
+	ExitPermission = 5 // Permessi insufficienti o elevazione negata
+
+	ExitInterrupted = 130 // Interrotto con Ctrl+C (128 + SIGINT, come le shell), file parziali già rimossi
+)
+
+// CommandError è l'errore restituito dai comandi a main, con il codice di uscita da usare.
+//
+// I comandi mostrano da sé messaggi e suggerimenti nel punto in cui l'errore si
+// verifica: main si limita a convertire l'errore in codice con ExitCode, senza
+// stamparlo di nuovo.
+type CommandError struct {
+	Code int
+	Err  error
+}
+
+func (e *CommandError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// Fail mostra un messaggio di errore e restituisce il CommandError corrispondente.
+//
+// È la forma abbreviata del caso più comune in un comando:
+//
+//	utils.PrintError("No JDK found")
+//	return utils.ExitWith(utils.ExitNotFound, errors.New("No JDK found"))
+//
+// che diventa:
+//
+//	return utils.Fail(utils.ExitNotFound, "No JDK found")
+func Fail(code int, message string) error {
+	PrintError(message)
+	return &CommandError{Code: code, Err: errors.New(message)}
+}
+
+// ExitWith associa un codice di uscita a un errore già mostrato all'utente.
+//
+// Da usare quando il messaggio (ed eventuali suggerimenti) è già stato stampato,
+// oppure per propagare l'esito di un helper che stampa da sé i propri errori.
+// err può essere nil se non c'è altro da aggiungere.
+func ExitWith(code int, err error) error {
+	return &CommandError{Code: code, Err: err}
+}
+
+// ExitCode converte l'errore restituito da un comando nel codice di uscita del processo.
+//
+// nil vale ExitOK; un errore senza CommandError nella catena vale ExitGeneric.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var commandErr *CommandError
+	if errors.As(err, &commandErr) {
+		return commandErr.Code
+	}
+	return ExitGeneric
+}
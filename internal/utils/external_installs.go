@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RegisterExternalInstall ricorda un JDK estratto fuori da ~/.jenvy/versions.
+//
+// 'jenvy download --extract-to' separa la cache degli archivi dalla posizione
+// di installazione (es. una directory condivisa dal team): il percorso viene
+// salvato in config.json così che 'use', 'list' e 'which' lo trovino insieme
+// alle installazioni locali. Registrare due volte lo stesso percorso non ha effetto.
+//
+// Parametri:
+//
+//	installDir string - Directory dell'installazione (es. "D:\team-jdks\JDK-21.0.5+11")
+//
+// Restituisce:
+//
+//	error - Errore di lettura o scrittura della configurazione
+func RegisterExternalInstall(installDir string) error {
+	absDir, err := filepath.Abs(installDir)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := LoadConfigOrDefault()
+	if err != nil {
+		return err
+	}
+	for _, dir := range cfg.ExternalInstalls {
+		if strings.EqualFold(filepath.Clean(dir), absDir) {
+			return nil
+		}
+	}
+	cfg.ExternalInstalls = append(cfg.ExternalInstalls, absDir)
+	return SaveConfig(cfg)
+}
+
+// ExternalInstallDirs restituisce le installazioni esterne registrate che esistono ancora.
+//
+// Le directory rimosse o non raggiungibili (es. share di rete scollegata) vengono
+// ignorate senza modificare la configurazione: possono tornare disponibili.
+func ExternalInstallDirs() []string {
+	cfg, err := LoadConfigOrDefault()
+	if err != nil {
+		return nil
+	}
+
+	var dirs []string
+	for _, dir := range cfg.ExternalInstalls {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
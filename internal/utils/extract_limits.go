@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Limiti predefiniti dell'estrazione, se extract-max-size, extract-max-files o extract-max-ratio non sono impostati.
+//
+// Sono ampi rispetto a qualsiasi JDK reale (un JDK estratto occupa meno di 1 GB
+// in poche migliaia di file, con un rapporto di compressione inferiore a 5) ma
+// fermano un archivio costruito per esaurire il disco (zip bomb).
+const (
+	DefaultExtractMaxSize  int64 = 8 << 30 // 8 GB estratti in totale
+	DefaultExtractMaxFiles       = 100000  // Voci dell'archivio (file, directory e link)
+	DefaultExtractMaxRatio       = 100     // Byte estratti per byte compresso
+)
+
+// extractRatioThreshold è la quantità estratta oltre la quale si controlla il rapporto di compressione.
+//
+// Sotto questa soglia anche un archivio legittimo può avere un rapporto molto
+// alto (es. pochi file di testo ripetitivi), e non può comunque riempire il disco.
+const extractRatioThreshold = 64 << 20
+
+// ErrArchiveLimit indica un archivio che supera i limiti di estrazione (vedi ArchiveGuard).
+var ErrArchiveLimit = errors.New("archive exceeds the extraction limits")
+
+// ExtractLimits sono i limiti applicati all'estrazione di un archivio.
+type ExtractLimits struct {
+	MaxSize  int64 // Byte estratti in totale
+	MaxFiles int   // Voci dell'archivio
+	MaxRatio int   // Rapporto massimo tra byte estratti e byte compressi letti
+}
+
+// ArchiveExtractLimits restituisce i limiti di estrazione della configurazione, con i predefiniti per quelli non impostati.
+func ArchiveExtractLimits(cfg *Config) ExtractLimits {
+	limits := ExtractLimits{MaxSize: DefaultExtractMaxSize, MaxFiles: DefaultExtractMaxFiles, MaxRatio: DefaultExtractMaxRatio}
+	if cfg == nil {
+		return limits
+	}
+	if size, err := ParseByteSize(cfg.ExtractMaxSize); err == nil {
+		limits.MaxSize = size
+	}
+	if cfg.ExtractMaxFiles > 0 {
+		limits.MaxFiles = cfg.ExtractMaxFiles
+	}
+	if cfg.ExtractMaxRatio > 0 {
+		limits.MaxRatio = cfg.ExtractMaxRatio
+	}
+	return limits
+}
+
+// ParseByteSize interpreta una dimensione in byte, con unità opzionale in base 1024 (es. "512MB", "8GB", "1048576").
+func ParseByteSize(size string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(size))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(value, unit.suffix) {
+			value, multiplier = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix)), unit.size
+			break
+		}
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("invalid size '%s' (expected e.g. 512MB or 8GB)", size)
+	}
+	return int64(number * float64(multiplier)), nil
+}
+
+// ArchiveGuard conta quanto scrive un'estrazione e la interrompe oltre i limiti (ExtractLimits).
+//
+// Protegge da archivi malevoli o corrotti, ad esempio serviti da un endpoint
+// privato non affidabile: tanti file, molti GB estratti o un rapporto di
+// compressione anomalo fanno fallire l'estrazione con ErrArchiveLimit prima
+// di esaurire il disco. Chi estrae registra ogni voce con Entry, i byte
+// compressi letti con Compressed (o CompressedReader) e quelli scritti con
+// Write, da collegare a io.Copy con io.MultiWriter.
+//
+// Esempio di utilizzo:
+//
+//	cfg, _ := utils.LoadConfigOrDefault()
+//	guard := utils.NewArchiveGuard(utils.ArchiveExtractLimits(cfg))
+//	gzr, err := gzip.NewReader(guard.CompressedReader(file))
+//	...
+//	if err := guard.Entry(); err != nil {
+//	    return err
+//	}
+//	_, err = io.Copy(io.MultiWriter(outFile, guard), tr)
+type ArchiveGuard struct {
+	limits     ExtractLimits
+	entries    int
+	extracted  int64
+	compressed int64
+}
+
+// NewArchiveGuard crea un ArchiveGuard per una singola estrazione.
+func NewArchiveGuard(limits ExtractLimits) *ArchiveGuard {
+	return &ArchiveGuard{limits: limits}
+}
+
+// Entry registra una voce dell'archivio.
+func (g *ArchiveGuard) Entry() error {
+	g.entries++
+	if g.entries > g.limits.MaxFiles {
+		return fmt.Errorf("%w: more than %d entries (if the archive is trusted, raise extract-max-files)", ErrArchiveLimit, g.limits.MaxFiles)
+	}
+	return nil
+}
+
+// Compressed registra n byte compressi letti dall'archivio, per il rapporto di compressione.
+func (g *ArchiveGuard) Compressed(n int64) {
+	g.compressed += n
+}
+
+// CompressedReader restituisce r contando i byte letti come compressi.
+func (g *ArchiveGuard) CompressedReader(r io.Reader) io.Reader {
+	return &guardReader{r: r, guard: g}
+}
+
+// Extracted registra n byte estratti e controlla dimensione totale e rapporto di compressione.
+func (g *ArchiveGuard) Extracted(n int64) error {
+	g.extracted += n
+	if g.extracted > g.limits.MaxSize {
+		return fmt.Errorf("%w: more than %s extracted (if the archive is trusted, raise extract-max-size)", ErrArchiveLimit, FormatBytes(g.limits.MaxSize))
+	}
+	if g.extracted > extractRatioThreshold && g.compressed > 0 && g.extracted/g.compressed > int64(g.limits.MaxRatio) {
+		return fmt.Errorf("%w: compression ratio above %d:1 (if the archive is trusted, raise extract-max-ratio)", ErrArchiveLimit, g.limits.MaxRatio)
+	}
+	return nil
+}
+
+// Write implementa io.Writer registrando i byte come estratti (vedi Extracted).
+func (g *ArchiveGuard) Write(b []byte) (int, error) {
+	if err := g.Extracted(int64(len(b))); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// guardReader conta i byte compressi letti da un archivio per un ArchiveGuard.
+type guardReader struct {
+	r     io.Reader
+	guard *ArchiveGuard
+}
+
+func (r *guardReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.guard.Compressed(int64(n))
+	return n, err
+}
@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// GitHubTokenEnv è la variabile d'ambiente con il token GitHub (ha precedenza sulla configurazione).
+const GitHubTokenEnv = "JENVY_GITHUB_TOKEN"
+
+// GitHubAPIBase è la base dell'API REST di github.com.
+const GitHubAPIBase = "https://api.github.com"
+
+// gitHubRateLimitURL restituisce lo stato del rate limit; interrogarlo non consuma richieste.
+const gitHubRateLimitURL = "https://api.github.com/rate_limit"
+
+// GitHubToken restituisce il token GitHub opzionale e la sua provenienza.
+//
+// Le richieste anonime a GitHub sono limitate per indirizzo IP: in CI che
+// elencano o scaricano spesso il limite si esaurisce rapidamente. L'ordine è:
+//  1. **JENVY_GITHUB_TOKEN**: Variabile d'ambiente, comoda nelle pipeline CI
+//  2. **github-token**: Impostazione salvata con 'jenvy config set github-token <token>'
+//
+// Restituisce:
+//
+//	string - Token (vuoto se non configurato)
+//	string - Provenienza da mostrare all'utente (mai il token stesso)
+func GitHubToken() (string, string) {
+	if token := strings.TrimSpace(os.Getenv(GitHubTokenEnv)); token != "" {
+		return token, GitHubTokenEnv
+	}
+	if cfg, err := LoadConfig(); err == nil && cfg.GitHubToken != "" {
+		return cfg.GitHubToken, "config github-token"
+	}
+	return "", ""
+}
+
+// IsGitHubURL indica se un URL è servito da GitHub (sito, API o contenuti delle release).
+func IsGitHubURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(parsed.Hostname())
+	return host == "github.com" || host == "api.github.com" || strings.HasSuffix(host, ".githubusercontent.com")
+}
+
+// GitHubAPIBaseFor restituisce la base dell'API REST dell'host di un repository GitHub.
+//
+// github.com usa api.github.com; un GitHub Enterprise Server espone l'API
+// sullo stesso host, sotto /api/v3.
+//
+// Esempio di utilizzo:
+//
+//	repo, _ := url.Parse("https://ghe.corp/platform/jdk-builds")
+//	utils.GitHubAPIBaseFor(repo) // "https://ghe.corp/api/v3"
+func GitHubAPIBaseFor(repository *url.URL) string {
+	switch strings.ToLower(repository.Hostname()) {
+	case "github.com", "www.github.com", "api.github.com":
+		return GitHubAPIBase
+	}
+	return repository.Scheme + "://" + repository.Host + "/api/v3"
+}
+
+// AuthorizeGitHubRequest aggiunge il token GitHub a una richiesta diretta a GitHub.
+//
+// Le richieste verso altri host non vengono modificate, così il token non esce mai
+// da GitHub. Anche sui redirect verso altri domini (es. il CDN delle release) il
+// client HTTP di Go rimuove l'header Authorization.
+func AuthorizeGitHubRequest(req *http.Request) {
+	if !IsGitHubURL(req.URL.String()) {
+		return
+	}
+	if token, _ := GitHubToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// GitHubRateLimit riassume la quota di richieste API GitHub disponibile.
+type GitHubRateLimit struct {
+	Limit     int       // Richieste consentite per finestra (60 anonime, 5000 con token)
+	Remaining int       // Richieste ancora disponibili
+	Reset     time.Time // Inizio della prossima finestra
+}
+
+// FetchGitHubRateLimit interroga GitHub sulla quota residua, con il token se configurato.
+//
+// Esempio di utilizzo:
+//
+//	if limit, err := utils.FetchGitHubRateLimit(10 * time.Second); err == nil {
+//	    fmt.Printf("%d/%d requests left\n", limit.Remaining, limit.Limit)
+//	}
+func FetchGitHubRateLimit(timeout time.Duration) (GitHubRateLimit, error) {
+	req, err := http.NewRequest(http.MethodGet, gitHubRateLimitURL, nil)
+	if err != nil {
+		return GitHubRateLimit{}, err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token, _ := GitHubToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := DoWithRetry(NewHTTPClient(timeout), req)
+	if err != nil {
+		return GitHubRateLimit{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return GitHubRateLimit{}, fmt.Errorf("GitHub returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Rate struct {
+			Limit     int   `json:"limit"`
+			Remaining int   `json:"remaining"`
+			Reset     int64 `json:"reset"`
+		} `json:"rate"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return GitHubRateLimit{}, fmt.Errorf("parsing GitHub rate limit: %w", err)
+	}
+	return GitHubRateLimit{
+		Limit:     body.Rate.Limit,
+		Remaining: body.Rate.Remaining,
+		Reset:     time.Unix(body.Rate.Reset, 0),
+	}, nil
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Edizioni GraalVM servite dal provider graalvm (chiave graalvm-edition).
+const (
+	GraalVMEditionCommunity = "community" // GraalVM Community Edition, licenza GPLv2+CE
+	GraalVMEditionOracle    = "oracle"    // Oracle GraalVM, licenza GFTC
+)
+
+// ValidateGraalVMEdition verifica che l'edizione GraalVM sia supportata.
+func ValidateGraalVMEdition(edition string) error {
+	switch edition {
+	case GraalVMEditionCommunity, GraalVMEditionOracle:
+		return nil
+	default:
+		return fmt.Errorf("invalid GraalVM edition '%s' (use %s or %s)", edition, GraalVMEditionCommunity, GraalVMEditionOracle)
+	}
+}
+
+// GraalVMEdition restituisce l'edizione GraalVM configurata (graalvm-edition).
+//
+// Un file di configurazione assente o un valore non valido ricadono su
+// GraalVMEditionCommunity, l'unica con licenza open source.
+func GraalVMEdition() string {
+	cfg, err := LoadConfigOrDefault()
+	if err != nil || ValidateGraalVMEdition(cfg.GraalVMEdition) != nil {
+		return GraalVMEditionCommunity
+	}
+	return cfg.GraalVMEdition
+}
+
+// NativeImageStatus descrive la disponibilità di native-image in una installazione GraalVM.
+type NativeImageStatus struct {
+	GraalVM bool // Il file release dichiara GRAALVM_VERSION
+	Bundled bool // bin\native-image è presente
+	HasGu   bool // bin\gu è presente: native-image si installa con 'gu install native-image'
+}
+
+// DetectNativeImage analizza il layout di una root JDK per riconoscere GraalVM e native-image.
+//
+// Da GraalVM for JDK 17 (23.0) native-image è incluso nella distribuzione; le
+// release 22.x e precedenti lo installano a parte con il Graal Updater (gu).
+//
+// Parametri:
+//
+//	home string - Root JDK (contiene bin e release)
+//
+// Esempio di utilizzo:
+//
+//	status := utils.DetectNativeImage(`C:\Users\Marco\.jenvy\versions\graalvm-jdk-21.0.2`)
+//	// status.GraalVM = true, status.Bundled = true
+func DetectNativeImage(home string) NativeImageStatus {
+	var status NativeImageStatus
+	if file, err := os.Open(filepath.Join(home, "release")); err == nil {
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			if strings.HasPrefix(scanner.Text(), "GRAALVM_VERSION=") {
+				status.GraalVM = true
+				break
+			}
+		}
+		file.Close()
+	}
+	status.Bundled = launcherExists(home, "native-image")
+	status.HasGu = launcherExists(home, "gu")
+	if status.Bundled || status.HasGu {
+		status.GraalVM = true
+	}
+	return status
+}
+
+// launcherExists indica se bin contiene il launcher indicato in una delle forme usate su Windows.
+func launcherExists(home, name string) bool {
+	for _, ext := range []string{".cmd", ".exe", ""} {
+		if info, err := os.Stat(filepath.Join(home, "bin", name+ext)); err == nil && !info.IsDir() {
+			return true
+		}
+	}
+	return false
+}
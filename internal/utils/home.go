@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+)
+
+// UserHomeDir determina la directory home dell'utente con una catena di fallback.
+//
+// user.Current() può fallire in alcuni container CI o sotto account di servizio
+// Windows (profilo non caricato, utente senza voce nel database locale); in quei
+// casi jenvy diventava inutilizzabile. L'ordine dei tentativi è:
+//  1. **user.Current()**: HomeDir dell'utente corrente
+//  2. **os.UserHomeDir()**: Variabile standard della piattaforma
+//  3. **USERPROFILE / HOME**: Lette direttamente dall'ambiente
+//
+// Restituisce:
+//
+//	string - Directory home assoluta
+//	error  - nil se trovata, errore se nessun tentativo ha prodotto un percorso
+func UserHomeDir() (string, error) {
+	if currentUser, err := user.Current(); err == nil && currentUser.HomeDir != "" {
+		return currentUser.HomeDir, nil
+	}
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		return home, nil
+	}
+	for _, name := range []string{"USERPROFILE", "HOME"} {
+		if home := os.Getenv(name); home != "" {
+			return home, nil
+		}
+	}
+	return "", errors.New("unable to determine the user home directory (set USERPROFILE or HOME)")
+}
+
+// JenvyHomeEnv sposta la directory dei dati Jenvy (es. un'installazione usa e getta).
+const JenvyHomeEnv = "JENVY_HOME"
+
+// JenvyHome restituisce la directory dei dati Jenvy (~/.jenvy, o JENVY_HOME se impostata).
+//
+// È il punto unico da cui derivano configurazione, versioni installate e cache:
+// i comandi non devono ricostruire il percorso partendo dalla home. JENVY_HOME
+// permette di lavorare su una directory separata senza toccare quella reale,
+// come fa 'jenvy self-test'.
+//
+// Esempio di utilizzo:
+//
+//	jenvyDir, err := utils.JenvyHome()
+//	// jenvyDir = "C:\Users\Marco\.jenvy"
+func JenvyHome() (string, error) {
+	if dir := os.Getenv(JenvyHomeEnv); dir != "" {
+		return filepath.Abs(dir)
+	}
+	home, err := UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".jenvy"), nil
+}
+
+// StagingDir restituisce la directory temporanea in cui estrarre un JDK destinato a dest.
+//
+// Le estrazioni avvengono qui e solo a estrazione riuscita il contenuto viene
+// spostato in dest, così un'interruzione non lascia mai un'installazione a metà.
+// Per le destinazioni dentro JenvyHome la directory è ~/.jenvy/tmp/<id>; per le
+// altre (es. --extract-to su un altro disco) è accanto a dest, perché lo
+// spostamento finale sia un semplice rename sullo stesso volume. L'id dipende
+// solo da dest: un'estrazione terminata bruscamente (es. processo ucciso)
+// riprende dalla stessa directory.
+//
+// Esempio di utilizzo:
+//
+//	staging, err := utils.StagingDir(`C:\Users\Marco\.jenvy\versions\JDK-21.0.2`)
+//	// staging = "C:\Users\Marco\.jenvy\tmp\JDK-21.0.2-1a2b3c4d"
+func StagingDir(dest string) (string, error) {
+	dest, err := filepath.Abs(dest)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(strings.ToLower(dest)))
+	id := filepath.Base(dest) + "-" + hex.EncodeToString(sum[:4])
+
+	if home, err := JenvyHome(); err == nil {
+		if rel, err := filepath.Rel(home, dest); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.Join(home, "tmp", id), nil
+		}
+	}
+	return filepath.Join(filepath.Dir(dest), ".jenvy-tmp-"+id), nil
+}
@@ -0,0 +1,206 @@
+package utils
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UserAgent identifica Jenvy nelle richieste HTTP verso provider e repository privati.
+const UserAgent = "Jenvy-Manager/1.0"
+
+// NewHTTPClient crea il client HTTP condiviso dai comandi che accedono alla rete.
+//
+// Il client usa un clone del transport di default con il proxy di config.json
+// (proxy, no-proxy, credenziali basic o NTLM) o, in sua assenza, quello standard
+// (HTTP_PROXY, HTTPS_PROXY, NO_PROXY) come il resto del sistema; vedi
+// LoadProxySettings. I certificati di --ca-cert o ca-cert si aggiungono alle
+// radici di sistema (vedi ApplyCACertFlag). Il timeout globale (--timeout, vedi ApplyNetworkFlags) sostituisce quello indicato.
+//
+// Parametri:
+//
+//	timeout time.Duration - Durata massima di una richiesta (0 = nessun limite)
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	if networkTimeout > 0 {
+		timeout = networkTimeout
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	cfg, _ := LoadConfigOrDefault()
+	configureProxy(transport, LoadProxySettings(cfg))
+	applyRootCAs(transport)
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// insecureFlags sono le opzioni globali che disattivano la verifica TLS per una singola esecuzione.
+var insecureFlags = []string{"--insecure-skip-verify", "--no-verify-tls"}
+
+// StripInsecureFlag rimuove --insecure-skip-verify (o --no-verify-tls) dagli argomenti.
+//
+// L'opzione è globale: viene tolta prima del dispatch, così i comandi che
+// rifiutano opzioni sconosciute non devono conoscerla.
+//
+// Restituisce:
+//
+//	[]string - Argomenti senza l'opzione
+//	bool     - true se l'opzione era presente
+func StripInsecureFlag(args []string) ([]string, bool) {
+	options, rest := cutOptions(args)
+	kept := make([]string, 0, len(args))
+	found := false
+	for _, arg := range options {
+		isInsecure := false
+		for _, flag := range insecureFlags {
+			if arg == flag {
+				isInsecure = true
+			}
+		}
+		if isInsecure {
+			found = true
+			continue
+		}
+		kept = append(kept, arg)
+	}
+	return append(kept, rest...), found
+}
+
+// DisableTLSVerification disattiva la verifica dei certificati TLS per il processo corrente.
+//
+// Serve solo a sbloccarsi dietro proxy con ispezione TLS che presentano un
+// certificato non attendibile. Non viene mai salvata in configurazione: vale
+// per l'esecuzione in corso e stampa un avviso ben visibile. La soluzione
+// corretta è importare il certificato del proxy nell'archivio certificati di
+// Windows, che Go usa per verificare le connessioni.
+//
+// Modifica http.DefaultTransport, quindi vale per NewHTTPClient (che lo clona)
+// e per tutto ciò che lo usa, come HTTPGet nei provider.
+func DisableTLSVerification() {
+	transport := http.DefaultTransport.(*http.Transport)
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.InsecureSkipVerify = true
+
+	PrintWarning("TLS certificate verification is DISABLED for this command (--insecure-skip-verify)")
+	PrintWarning("Downloads can be tampered with by anyone on the network path; checksums are the only remaining protection")
+	PrintInfo("Prefer trusting your proxy's CA certificate: --ca-cert=<file>, 'jenvy config set ca-cert <file>' or the Windows certificate store")
+}
+
+// ProbeResult è l'esito di un controllo di raggiungibilità di un endpoint.
+type ProbeResult struct {
+	StatusCode int           // Codice HTTP della risposta (0 se nessuna risposta)
+	Latency    time.Duration // Tempo fino alla ricezione degli header
+	Err        error         // Errore di rete (DNS, connessione, timeout, proxy)
+}
+
+// Reachable indica se l'endpoint ha risposto, indipendentemente dal codice HTTP.
+func (r ProbeResult) Reachable() bool {
+	return r.Err == nil
+}
+
+// ProbeEndpoint verifica la raggiungibilità di un endpoint con una richiesta leggera.
+//
+// Usa una richiesta HEAD; se il server non la supporta (405/501) ripete con GET
+// senza leggere il corpo. Distingue così un problema di rete o proxy locale
+// (Err valorizzato) da un provider raggiungibile ma in errore (StatusCode >= 500).
+//
+// Parametri:
+//
+//	url string            - Endpoint da verificare
+//	token string          - Token opzionale dei repository privati (vedi SetPrivateAuthorization)
+//	timeout time.Duration - Timeout della singola richiesta
+//
+// Esempio di utilizzo:
+//
+//	result := ProbeEndpoint("https://api.adoptium.net/v3/info/available_releases", "", 10*time.Second)
+//	if !result.Reachable() {
+//	    fmt.Println("Network or proxy problem:", result.Err)
+//	}
+func ProbeEndpoint(url, token string, timeout time.Duration) ProbeResult {
+	client := NewHTTPClient(timeout)
+	url = MirrorURL(url)
+	result := probe(client, http.MethodHead, url, token)
+	if result.StatusCode == http.StatusMethodNotAllowed || result.StatusCode == http.StatusNotImplemented {
+		result = probe(client, http.MethodGet, url, token)
+	}
+	return result
+}
+
+// probe esegue una singola richiesta e ne misura la latenza.
+func probe(client *http.Client, method, url, token string) ProbeResult {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return ProbeResult{Err: err}
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	SetPrivateAuthorization(req, token)
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		return ProbeResult{Latency: latency, Err: err}
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	resp.Body.Close()
+	return ProbeResult{StatusCode: resp.StatusCode, Latency: latency}
+}
+
+// RemoteContentLength legge la dimensione di un file remoto con una richiesta HEAD.
+//
+// I redirect (es. GitHub Releases → CDN) vengono seguiti. Restituisce -1 se il
+// server non indica Content-Length.
+//
+// Parametri:
+//
+//	url string            - URL del file (es. archivio JDK)
+//	timeout time.Duration - Timeout della richiesta
+//
+// Restituisce:
+//
+//	int64 - Dimensione in byte, -1 se sconosciuta
+//	error - Errore di rete o risposta HTTP >= 400
+func RemoteContentLength(url string, timeout time.Duration) (int64, error) {
+	req, err := http.NewRequest(http.MethodHead, MirrorURL(url), nil)
+	if err != nil {
+		return -1, err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	AuthorizeGitHubRequest(req)
+	AuthorizePrivateRequest(req)
+
+	resp, err := DoWithRetry(NewHTTPClient(timeout), req)
+	if err != nil {
+		return -1, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return -1, fmt.Errorf("server returned status %d", resp.StatusCode)
+	}
+	return resp.ContentLength, nil
+}
+
+// ContentRangeStart restituisce il primo byte indicato da un header Content-Range.
+//
+// Serve a verificare che una risposta 206 riprenda dal byte richiesto prima di
+// accodarla a un download parziale.
+//
+// Esempio di utilizzo:
+//
+//	ContentRangeStart("bytes 100-199/200") // 100, true
+//	ContentRangeStart("bytes */200")       // 0, false
+func ContentRangeStart(header string) (int64, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(header), "bytes ")
+	if !ok {
+		return 0, false
+	}
+	first, _, ok := strings.Cut(rest, "-")
+	if !ok {
+		return 0, false
+	}
+	start, err := strconv.ParseInt(strings.TrimSpace(first), 10, 64)
+	return start, err == nil && start >= 0
+}
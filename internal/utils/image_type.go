@@ -0,0 +1,83 @@
+package utils
+
+import "fmt"
+
+// Tipi di immagine selezionabili con --type.
+const (
+	ImageTypeJDK = "jdk" // Kit di sviluppo completo (predefinito)
+	ImageTypeJRE = "jre" // Solo runtime, per distribuire applicazioni
+)
+
+// imageType e includeJavaFX sono impostate da --type e --javafx per l'esecuzione corrente.
+var (
+	imageType     = ImageTypeJDK
+	includeJavaFX bool
+)
+
+// imageVariantSupport indica per ogni provider se pubblica JRE e pacchetti con JavaFX.
+//
+// Adoptium non distribuisce JavaFX; GraalVM e i repository privati elencano solo JDK.
+var imageVariantSupport = map[string]struct{ JRE, JavaFX bool }{
+	"adoptium": {JRE: true},
+	"azul":     {JRE: true, JavaFX: true},
+	"liberica": {JRE: true, JavaFX: true},
+}
+
+// ParseImageType valida il valore di --type (jdk | jre).
+func ParseImageType(value string) (string, error) {
+	switch value {
+	case ImageTypeJDK, ImageTypeJRE:
+		return value, nil
+	default:
+		return "", fmt.Errorf("invalid --type '%s' (expected jdk or jre)", value)
+	}
+}
+
+// SetImageVariant sceglie il tipo di immagine e l'inclusione di JavaFX richiesti ai provider.
+//
+// Come --ea vale per l'intera esecuzione: i provider leggono ImageType e
+// JavaFX nel costruire gli URL delle API, così anche la cache resta separata
+// per ogni variante.
+func SetImageVariant(kind string, javafx bool) {
+	imageType, includeJavaFX = kind, javafx
+}
+
+// ImageType restituisce il tipo di immagine richiesto (ImageTypeJDK se non indicato).
+func ImageType() string {
+	return imageType
+}
+
+// JavaFX indica se sono richiesti i pacchetti con JavaFX incluso (Liberica Full, Zulu FX).
+func JavaFX() bool {
+	return includeJavaFX
+}
+
+// CheckImageVariant verifica che un provider pubblichi la variante richiesta.
+//
+// Restituisce nil per la variante predefinita (JDK senza JavaFX), che tutti i
+// provider supportano.
+func CheckImageVariant(provider string) error {
+	support := imageVariantSupport[provider]
+	if imageType == ImageTypeJRE && !support.JRE {
+		return fmt.Errorf("%s does not publish JRE builds (try --provider=adoptium, azul or liberica)", provider)
+	}
+	if includeJavaFX && !support.JavaFX {
+		return fmt.Errorf("%s does not publish JavaFX bundles (try --provider=azul or liberica)", provider)
+	}
+	return nil
+}
+
+// ImageVariantSuffix restituisce il suffisso della directory di installazione per la variante richiesta.
+//
+// Vuoto per un JDK senza JavaFX; altrimenti "-jre", "-fx" o "-jre-fx", così
+// JRE e JDK della stessa versione non condividono la directory.
+func ImageVariantSuffix() string {
+	suffix := ""
+	if imageType == ImageTypeJRE {
+		suffix += "-jre"
+	}
+	if includeJavaFX {
+		suffix += "-fx"
+	}
+	return suffix
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"os"
+	"strings"
+)
+
+// NonInteractiveEnv è la variabile d'ambiente che equivale a --yes per ogni esecuzione (es. nelle pipeline CI).
+const NonInteractiveEnv = "JENVY_NONINTERACTIVE"
+
+// assumeYes è impostata da --yes/-y per l'esecuzione corrente (vedi SetAssumeYes).
+var assumeYes bool
+
+// StripYesFlag rimuove le opzioni globali --yes e -y dagli argomenti.
+//
+// Come --insecure-skip-verify, l'opzione viene tolta prima del dispatch così
+// che valga per qualsiasi comando, anche per quelli che rifiutano opzioni
+// sconosciute; i comandi leggono poi AssumeYes.
+//
+// Restituisce:
+//
+//	[]string - Argomenti senza l'opzione
+//	bool     - true se l'opzione era presente
+func StripYesFlag(args []string) ([]string, bool) {
+	options, rest := cutOptions(args)
+	kept := make([]string, 0, len(args))
+	found := false
+	for i, arg := range options {
+		if i > 0 && (arg == "--yes" || arg == "-y") {
+			found = true
+			continue
+		}
+		kept = append(kept, arg)
+	}
+	return append(kept, rest...), found
+}
+
+// SetAssumeYes attiva la modalità non interattiva per l'esecuzione corrente.
+func SetAssumeYes(enabled bool) {
+	assumeYes = enabled
+}
+
+// AssumeYes indica se jenvy deve procedere senza domande.
+//
+// Vale con --yes/-y o con JENVY_NONINTERACTIVE impostata a 1, true o yes. In
+// questa modalità le conferme dell'operazione richiesta si considerano accettate,
+// mentre le domande accessorie (conservare l'archivio, eliminare ~/.jenvy durante
+// 'uninstall') prendono la risposta predefinita, quella meno distruttiva.
+func AssumeYes() bool {
+	if assumeYes {
+		return true
+	}
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(NonInteractiveEnv))) {
+	case "1", "true", "yes":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// JavaVersionTimeout limita l'esecuzione di 'java -version' dopo l'attivazione di un JDK.
+//
+// Un JDK sano risponde in meno di un secondo; un'installazione corrotta può
+// invece bloccarsi, e non deve bloccare anche 'jenvy use'.
+const JavaVersionTimeout = 15 * time.Second
+
+// RunJavaVersion esegue 'java -version' nel JDK indicato e restituisce la versione riportata.
+//
+// Parametri:
+//
+//	ctx context.Context - Contesto dell'esecuzione (il timeout va applicato dal chiamante)
+//	home string         - Root del JDK (JAVA_HOME)
+//
+// Restituisce:
+//
+//	string - Versione riportata (es. "21.0.5", "1.8.0_432")
+//	string - Prima riga dell'output, da mostrare all'utente
+//	error  - Se java non parte, termina con errore o non riporta una versione
+func RunJavaVersion(ctx context.Context, home string) (string, string, error) {
+	cmd := exec.CommandContext(ctx, JavaExecutablePath(home), "-version")
+	// Senza WaitDelay un processo figlio rimasto appeso all'output bloccherebbe oltre il timeout
+	cmd.WaitDelay = time.Second
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", "", errors.New("no answer before the timeout")
+		}
+		return "", "", ctx.Err()
+	}
+	if err != nil {
+		if detail := strings.TrimSpace(string(output)); detail != "" {
+			return "", "", fmt.Errorf("%v: %s", err, strings.SplitN(detail, "\n", 2)[0])
+		}
+		return "", "", err
+	}
+	version, line, ok := ParseJavaVersionOutput(string(output))
+	if !ok {
+		return "", "", fmt.Errorf("unexpected output %q", strings.TrimSpace(string(output)))
+	}
+	return version, line, nil
+}
+
+// ParseJavaVersionOutput estrae la versione dall'output di 'java -version'.
+//
+// La versione è la stringa tra virgolette della riga "version" (es.
+// `openjdk version "21.0.5" 2024-10-15`); le righe che la precedono, come
+// "Picked up JAVA_TOOL_OPTIONS", vengono ignorate.
+//
+// Esempio di utilizzo:
+//
+//	ParseJavaVersionOutput(`openjdk version "21.0.5" 2024-10-15`) // "21.0.5", riga, true
+//	ParseJavaVersionOutput(`java version "1.8.0_432"`)            // "1.8.0_432", riga, true
+func ParseJavaVersionOutput(output string) (version, line string, ok bool) {
+	for _, raw := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(raw)
+		_, rest, found := strings.Cut(line, ` version "`)
+		if !found {
+			continue
+		}
+		if version, _, found := strings.Cut(rest, `"`); found && version != "" {
+			return version, line, true
+		}
+	}
+	return "", "", false
+}
+
+// JavaVersionMatches indica se la versione riportata da 'java -version' corrisponde a quella attesa.
+//
+// Vengono confrontate solo le parti indicate in expected: "17" accetta qualsiasi
+// 17.x, "17.0.9+9" richiede 17.0.9 (il build non compare sempre nell'output).
+// Le forme di Java 8 ("1.8.0_432", "8u432", "8.0.432") sono equivalenti.
+// Una versione attesa non riconosciuta corrisponde sempre: non c'è nulla da confrontare.
+func JavaVersionMatches(reported, expected string) bool {
+	eMajor, eMinor, ePatch := ParseVersionNumber(expected)
+	if eMajor <= 0 {
+		return true
+	}
+	rMajor, rMinor, rPatch := ParseVersionNumber(reported)
+	if rMajor != eMajor {
+		return false
+	}
+
+	core := expected
+	if idx := strings.IndexAny(core, "+-"); idx != -1 {
+		core = core[:idx]
+	}
+	parts := strings.Count(core, ".") + 1
+	switch {
+	case strings.HasPrefix(core, "1.8.0_") || strings.HasPrefix(core, "8u"):
+		parts = 3
+	case strings.HasPrefix(core, "1.8"):
+		parts = 1 // "1.8.0" senza update indica solo la major
+	}
+	if parts >= 2 && rMinor != eMinor {
+		return false
+	}
+	return parts < 3 || rPatch == ePatch
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// PinnedJDK è una voce del file usato da 'jenvy download --manifest'.
+//
+// Il file è un array JSON di voci, pensato per essere versionato insieme al
+// progetto così che ogni sviluppatore installi esattamente gli stessi archivi:
+//
+//	[
+//	  {"provider": "adoptium", "version": "21.0.5+11", "sha256": "3c654d98..."},
+//	  {"provider": "azul", "version": "17.0.13", "sha256": "9a1b7c..."}
+//	]
+type PinnedJDK struct {
+	Provider string `json:"provider"`
+	Version  string `json:"version"`
+	SHA256   string `json:"sha256"`
+}
+
+// LoadJDKManifest legge e valida un file di JDK fissati.
+//
+// Ogni voce deve indicare provider, versione e uno SHA-256 esadecimale di 64
+// caratteri; le voci duplicate (stesso provider e versione) sono un errore.
+// I checksum vengono normalizzati in minuscolo.
+//
+// Parametri:
+//
+//	path string - Percorso del file JSON
+//
+// Restituisce:
+//
+//	[]PinnedJDK - Voci nell'ordine del file
+//	error       - Errore di lettura, di parsing o della prima voce non valida
+func LoadJDKManifest(path string) ([]PinnedJDK, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []PinnedJDK
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%s contains no JDKs", path)
+	}
+
+	seen := make(map[string]bool)
+	for i := range entries {
+		entry := &entries[i]
+		entry.Provider = strings.ToLower(strings.TrimSpace(entry.Provider))
+		entry.Version = strings.TrimSpace(entry.Version)
+		entry.SHA256 = strings.ToLower(strings.TrimSpace(entry.SHA256))
+
+		switch {
+		case entry.Provider == "":
+			return nil, fmt.Errorf("entry %d: missing provider", i+1)
+		case entry.Version == "":
+			return nil, fmt.Errorf("entry %d: missing version", i+1)
+		case DetectChecksumAlgorithm(entry.SHA256) != "sha256":
+			return nil, fmt.Errorf("entry %d: sha256 must be 64 hex characters", i+1)
+		}
+		if _, err := hex.DecodeString(entry.SHA256); err != nil {
+			return nil, fmt.Errorf("entry %d: sha256 is not a hex value", i+1)
+		}
+
+		key := entry.Provider + "/" + entry.Version
+		if seen[key] {
+			return nil, fmt.Errorf("entry %d: %s %s is listed more than once", i+1, entry.Provider, entry.Version)
+		}
+		seen[key] = true
+	}
+	return entries, nil
+}
+
+// VersionMatchesPin indica se la versione risolta da un provider è quella fissata.
+//
+// Major, minor e patch devono coincidere; il numero di build viene confrontato
+// solo se la versione fissata lo indica (es. "21.0.5+11" non accetta "21.0.5+12",
+// mentre "21.0.5" accetta qualsiasi build della 21.0.5).
+func VersionMatchesPin(pinned, resolved string) bool {
+	if javaBuildNumber(pinned) != 0 {
+		return CompareJavaVersions(pinned, resolved) == 0
+	}
+	pMajor, pMinor, pPatch := ParseVersionNumber(pinned)
+	rMajor, rMinor, rPatch := ParseVersionNumber(resolved)
+	return pMajor == rMajor && pMinor == rMinor && pPatch == rPatch
+}
@@ -3,10 +3,12 @@ package utils
 import (
 	"fmt"
 	"os"
-	"os/user"
 	"path/filepath"
+	"regexp"
+	"runtime"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // ParseVersionNumber analizza e decompone una stringa di versione JDK in componenti numerici.
@@ -136,6 +138,196 @@ func ParseVersionNumber(version string) (major, minor, patch int) {
 	return major, minor, patch
 }
 
+// ReleaseVersion è la versione di una release del provider già scomposta in numeri.
+//
+// I provider la calcolano una sola volta quando caricano l'elenco delle release,
+// così la ricerca di 'download' e i filtri di 'remote-list' confrontano interi
+// invece di rianalizzare la stessa stringa per ogni release a ogni passaggio.
+type ReleaseVersion struct {
+	Major int
+	Minor int
+	Patch int
+	Build int // Numero di build dopo '+' o "-b" (es. 9 in "17.0.9+9"), 0 se assente
+}
+
+// NewReleaseVersion analizza una volta la versione di una release con ParseVersionNumber.
+func NewReleaseVersion(version string) ReleaseVersion {
+	major, minor, patch := ParseVersionNumber(version)
+	return ReleaseVersion{Major: major, Minor: minor, Patch: patch, Build: javaBuildNumber(version)}
+}
+
+// VersionKey riduce una versione alla forma "major.minor.patch", senza build.
+//
+// Rende confrontabili le versioni scritte dai diversi provider per la stessa
+// release (es. "17.0.9+9" di Temurin, "17.0.9" di Zulu), come fa 'list --duplicates'.
+//
+// Esempio di utilizzo:
+//
+//	VersionKey("17.0.9+9")       // "17.0.9", true
+//	VersionKey("1.8.0_452-b09")  // "8.0.452", true
+//	VersionKey("latest")         // "", false
+func VersionKey(version string) (string, bool) {
+	major, minor, patch := ParseVersionNumber(version)
+	if major <= 0 {
+		return "", false
+	}
+	return fmt.Sprintf("%d.%d.%d", major, minor, patch), true
+}
+
+// VersionRequest è una versione richiesta dall'utente, scomposta per la ricerca tra le release.
+//
+// Minor e Patch a -1 accettano qualsiasi valore (come in Matches); Build a -1
+// accetta qualsiasi build. Il build viene richiesto solo scrivendolo dopo '+'
+// (es. "17.0.9+9"), per scegliere tra due build della stessa patch quando un
+// provider pubblica una ricompilazione.
+type VersionRequest struct {
+	Major int
+	Minor int
+	Patch int
+	Build int
+}
+
+// ParseVersionRequest scompone la versione richiesta, conservando il build se indicato con '+'.
+//
+// Esempio di utilizzo:
+//
+//	ParseVersionRequest("17.0.9+9") // {17, 0, 9, 9}
+//	ParseVersionRequest("17.0.9")   // {17, 0, 9, -1}
+func ParseVersionRequest(version string) VersionRequest {
+	major, minor, patch := ParseVersionNumber(version)
+	build := -1
+	if strings.Contains(version, "+") {
+		build = javaBuildNumber(version)
+	}
+	return VersionRequest{Major: major, Minor: minor, Patch: patch, Build: build}
+}
+
+// Satisfies indica se la release corrisponde alla richiesta, build compreso se indicato.
+func (v ReleaseVersion) Satisfies(req VersionRequest) bool {
+	if !v.Matches(req.Major, req.Minor, req.Patch) {
+		return false
+	}
+	return req.Build == -1 || v.Build == req.Build
+}
+
+// Matches indica se la release soddisfa una versione richiesta dall'utente.
+//
+// La richiesta è già scomposta con ParseVersionNumber; minor o patch a -1
+// accettano qualsiasi valore (es. "17" accetta ogni 17.x.y).
+//
+// Esempio di utilizzo:
+//
+//	targetMajor, targetMinor, targetPatch := ParseVersionNumber("21.0.5")
+//	NewReleaseVersion("21.0.5+11").Matches(targetMajor, targetMinor, targetPatch) // true
+func (v ReleaseVersion) Matches(major, minor, patch int) bool {
+	switch {
+	case minor == -1 && patch == -1:
+		return v.Major == major
+	case patch == -1:
+		return v.Major == major && v.Minor == minor
+	default:
+		return v.Major == major && v.Minor == minor && v.Patch == patch
+	}
+}
+
+// versionInputPattern accetta versioni numeriche con suffisso di build opzionale
+// (es. "17", "21.0.5+11", "1.8.0_452-b09") e il formato Liberica "8u352".
+var versionInputPattern = regexp.MustCompile(`^(\d+(\.\d+)*([+_-][0-9A-Za-z.+_-]+)?|8u\d+)$`)
+
+// legacyVersionPattern riconosce la numerazione storica "1.x" fino a Java 8 (es. "1.8", "1.8.0").
+var legacyVersionPattern = regexp.MustCompile(`^1\.([1-8])(\.0)?$`)
+
+// NormalizeVersionInput valida e normalizza una versione digitata dall'utente.
+//
+// È il punto di ingresso comune di 'download', 'use', 'remove' ed 'extract', così
+// che un errore di battitura venga segnalato subito invece di diventare un
+// generico "not found":
+//   - Spazi iniziali e finali vengono rimossi
+//   - Il prefisso "jdk" o "JDK-" viene scartato ("jdk17" → "17")
+//   - La numerazione storica diventa la major ("1.8" → "8")
+//   - Input malformati (es. "17.", "abc") producono un errore con un esempio valido
+//
+// Parametri:
+//
+//	input string - Versione così come digitata
+//
+// Restituisce:
+//
+//	string - Versione normalizzata
+//	error  - Messaggio leggibile se l'input non è una versione
+//
+// Esempio di utilizzo:
+//
+//	version, err := NormalizeVersionInput(" JDK-21.0.5+11 ") // "21.0.5+11", nil
+//	version, err := NormalizeVersionInput("17.")            // "", invalid version "17."...
+func NormalizeVersionInput(input string) (string, error) {
+	version := strings.TrimSpace(input)
+	lower := strings.ToLower(version)
+	for _, prefix := range []string{"jdk-", "jdk"} {
+		if strings.HasPrefix(lower, prefix) {
+			version = strings.TrimSpace(version[len(prefix):])
+			break
+		}
+	}
+
+	if !versionInputPattern.MatchString(version) {
+		if trimmed := strings.TrimRight(version, "."); trimmed != version && versionInputPattern.MatchString(trimmed) {
+			return "", fmt.Errorf("invalid version %q: remove the trailing '.' (e.g. %s)", input, trimmed)
+		}
+		return "", fmt.Errorf("invalid version %q: expected a version like 17, 21.0.5 or 21.0.5+11", input)
+	}
+
+	if match := legacyVersionPattern.FindStringSubmatch(version); match != nil {
+		return match[1], nil
+	}
+	return version, nil
+}
+
+// CompareJavaVersions confronta due versioni Java nei formati supportati da ParseVersionNumber.
+//
+// A parità di major, minor e patch decide il numero di build dopo '+' o "-b"
+// (es. 17.0.12+7 < 17.0.12+9), che i provider incrementano per le ricompilazioni.
+//
+// Restituisce:
+//
+//	int - -1 se a < b, 0 se equivalenti, 1 se a > b
+//
+// Esempio di utilizzo:
+//
+//	CompareJavaVersions("17.0.12+7", "17.0.13+11") // -1
+//	CompareJavaVersions("1.8.0_452-b09", "8u442")  // 1
+func CompareJavaVersions(a, b string) int {
+	aMajor, aMinor, aPatch := ParseVersionNumber(a)
+	bMajor, bMinor, bPatch := ParseVersionNumber(b)
+	for _, pair := range [][2]int{{aMajor, bMajor}, {aMinor, bMinor}, {aPatch, bPatch}, {javaBuildNumber(a), javaBuildNumber(b)}} {
+		if pair[0] != pair[1] {
+			if pair[0] > pair[1] {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
+
+// javaBuildNumber estrae il numero di build da una versione ("17.0.12+7" → 7, "1.8.0_452-b09" → 9).
+func javaBuildNumber(version string) int {
+	build := ""
+	if idx := strings.LastIndex(version, "+"); idx != -1 {
+		build = version[idx+1:]
+	} else if idx := strings.LastIndex(version, "-b"); idx != -1 {
+		build = version[idx+2:]
+	}
+	if end := strings.IndexFunc(build, func(r rune) bool { return r < '0' || r > '9' }); end != -1 {
+		build = build[:end]
+	}
+	n, _ := strconv.Atoi(build)
+	return n
+}
+
+// LTSMajors elenca le versioni major LTS note (Oracle LTS roadmap), in ordine crescente.
+var LTSMajors = []int{8, 11, 17, 21}
+
 // IsLTSVersion determina se una versione JDK è Long Term Support (LTS).
 //
 // Questa funzione centralizza la logica per identificare versioni LTS, utilizzando
@@ -177,10 +369,7 @@ func ParseVersionNumber(version string) (major, minor, patch int) {
 func IsLTSVersion(version string) bool {
 	major, _, _ := ParseVersionNumber(version)
 
-	// Versioni LTS note (Oracle LTS roadmap)
-	ltsVersions := []int{8, 11, 17, 21}
-
-	for _, lts := range ltsVersions {
+	for _, lts := range LTSMajors {
 		if major == lts {
 			return true
 		}
@@ -259,15 +448,127 @@ func IsValidJDKDirectory(path string) bool {
 		}
 	}
 
-	// Check for java executable (Windows-specific)
-	javaExe := "java.exe"
-	if _, err := os.Stat(filepath.Join(path, "bin", javaExe)); err != nil {
+	// Check for java executable (java.exe on Windows, java elsewhere)
+	if _, err := os.Stat(JavaExecutablePath(path)); err != nil {
 		return false
 	}
 
 	return true
 }
 
+// JavaExecutablePath restituisce il percorso del launcher java di un JDK.
+//
+// Il nome dell'eseguibile dipende dal sistema operativo: bin\java.exe su Windows,
+// bin/java altrove. Tutti i comandi che devono localizzare o eseguire java
+// dovrebbero passare da qui invece di costruire il percorso a mano.
+//
+// Parametri:
+//
+//	jdkPath string - Root del JDK (la directory usata come JAVA_HOME)
+//
+// Esempio di utilizzo:
+//
+//	JavaExecutablePath("C:\\Users\\user\\.jenvy\\versions\\JDK-17")
+//	// → "C:\\Users\\user\\.jenvy\\versions\\JDK-17\\bin\\java.exe"
+func JavaExecutablePath(jdkPath string) string {
+	return filepath.Join(jdkPath, "bin", JavaExecutableName())
+}
+
+// JavaExecutableName restituisce il nome del launcher java per il sistema operativo corrente.
+func JavaExecutableName() string {
+	if runtime.GOOS == "windows" {
+		return "java.exe"
+	}
+	return "java"
+}
+
+// ResolveJDKHome restituisce la directory JDK effettiva di un'installazione Jenvy.
+//
+// Normalmente l'archivio viene appiattito durante l'estrazione e la directory di
+// installazione è già la root del JDK. Con 'jenvy extract --no-flatten' invece la
+// struttura originale dell'archivio viene preservata (es. JDK-17/jdk-17.0.9+9/bin):
+// in questo caso la root del JDK è l'unica sottodirectory valida.
+//
+// Parametri:
+//
+//	installDir string - Directory di installazione (es. ~/.jenvy/versions/JDK-17.0.9)
+//
+// Restituisce:
+//
+//	string - Percorso da usare come JAVA_HOME
+//	bool   - false se né la directory né una sua sottodirectory contengono un JDK valido
+func ResolveJDKHome(installDir string) (string, bool) {
+	if IsValidJDKDirectory(installDir) {
+		return installDir, true
+	}
+
+	entries, err := os.ReadDir(installDir)
+	if err != nil {
+		return installDir, false
+	}
+
+	var nested []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		candidate := filepath.Join(installDir, entry.Name())
+		if IsValidJDKDirectory(candidate) {
+			nested = append(nested, candidate)
+		}
+	}
+
+	// Con più candidati la struttura è ambigua: meglio non indovinare
+	if len(nested) != 1 {
+		return installDir, false
+	}
+	return nested[0], true
+}
+
+// JDKHomeVersion restituisce la versione del JDK in una directory JAVA_HOME.
+//
+// Fonti, in ordine di affidabilità:
+// 1. **File release**: JAVA_VERSION scritto dal JDK stesso (es. "17.0.9")
+// 2. **Metadati Jenvy**: Versione registrata da 'download' nella directory di installazione
+// 3. **Nome directory**: Versione ricavata con ParseInstallDirName
+//
+// Il numero di build viene scartato ("17.0.9+9" → "17.0.9"), così il risultato
+// coincide con quello del file release e può essere confrontato dagli script.
+//
+// Parametri:
+//
+//	home string - Root del JDK (es. valore di JAVA_HOME)
+//
+// Restituisce:
+//
+//	string - Versione (es. "17.0.9", "1.8.0_452")
+//	bool   - false se nessuna fonte indica una versione
+func JDKHomeVersion(home string) (string, bool) {
+	if data, err := os.ReadFile(filepath.Join(home, "release")); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if value, found := strings.CutPrefix(strings.TrimSpace(line), "JAVA_VERSION="); found {
+				if version := strings.Trim(value, `"`); version != "" {
+					return version, true
+				}
+			}
+		}
+	}
+
+	// Con --no-flatten i metadati sono nella directory di installazione, sopra la root del JDK
+	for _, dir := range []string{home, filepath.Dir(home)} {
+		if meta, err := LoadInstallMetadata(dir); err == nil && meta.Version != "" {
+			version, _, _ := strings.Cut(meta.Version, "+")
+			return version, true
+		}
+	}
+
+	if version, _, ok := ParseInstallDirName(filepath.Base(home), InstallNamingScheme()); ok {
+		version, _, _ = strings.Cut(version, "+")
+		return version, true
+	}
+	return "", false
+}
+
 // GetJenvyVersionsDirectory ritorna il percorso della directory standard per le versioni Jenvy.
 //
 // Questa funzione centralizza la logica per determinare dove Jenvy installa e gestisce
@@ -282,8 +583,8 @@ func IsValidJDKDirectory(path string) bool {
 //	└── JDK-8.0.392\         # Versione legacy
 //
 // Processo di determinazione:
-// 1. **Directory utente**: Usa user.Current() per ottenere home directory
-// 2. **Costruzione path**: Combina home + ".jenvy" + "versions"
+// 1. **Directory Jenvy**: Usa JenvyHome() (con fallback se user.Current() fallisce)
+// 2. **Costruzione path**: Combina la directory Jenvy + "versions"
 // 3. **Path assoluto**: Ritorna percorso completo e normalizzato
 //
 // Parametri:
@@ -303,15 +604,276 @@ func IsValidJDKDirectory(path string) bool {
 //	}
 //	// versionsDir = "C:\Users\Marco\.jenvy\versions"
 func GetJenvyVersionsDirectory() (string, error) {
-	homeDir, err := user.Current()
+	jenvyDir, err := JenvyHome()
 	if err != nil {
-		return "", fmt.Errorf("failed to get current user: %w", err)
+		return "", err
 	}
 
-	versionsDir := filepath.Join(homeDir.HomeDir, ".jenvy", "versions")
+	versionsDir := filepath.Join(jenvyDir, "versions")
 	return versionsDir, nil
 }
 
+// DefaultNamingScheme è lo schema storico dei nomi directory di installazione.
+const DefaultNamingScheme = "JDK-{version}"
+
+// ProviderNamingScheme è lo schema che include il provider, utile quando più
+// vendor distribuiscono la stessa stringa di versione.
+const ProviderNamingScheme = "{provider}-jdk-{version}"
+
+// ValidateNamingScheme verifica che uno schema di naming produca nomi directory sicuri.
+//
+// Regole di validazione:
+//   - **Placeholder obbligatorio**: Deve contenere {version}
+//   - **Placeholder ammessi**: Solo {provider} e {version}
+//   - **Caratteri sicuri**: Nessun separatore di percorso o carattere riservato Windows
+//
+// Parametri:
+//
+//	scheme string - Schema da validare (es. "{provider}-jdk-{version}")
+//
+// Restituisce:
+//
+//	error - nil se valido, errore descrittivo altrimenti
+func ValidateNamingScheme(scheme string) error {
+	if !strings.Contains(scheme, "{version}") {
+		return fmt.Errorf("naming scheme must contain the {version} placeholder")
+	}
+
+	rest := strings.NewReplacer("{version}", "", "{provider}", "").Replace(scheme)
+	if strings.ContainsAny(rest, "{}") {
+		return fmt.Errorf("naming scheme supports only {provider} and {version} placeholders")
+	}
+	if strings.ContainsAny(rest, `/\:*?"<>|`) || strings.Contains(rest, "..") {
+		return fmt.Errorf("naming scheme contains characters not allowed in directory names")
+	}
+	return nil
+}
+
+// InstallNamingScheme restituisce lo schema di naming configurato o quello di default.
+func InstallNamingScheme() string {
+	cfg, err := LoadConfigOrDefault()
+	if err != nil || cfg.NamingScheme == "" {
+		return DefaultNamingScheme
+	}
+	if ValidateNamingScheme(cfg.NamingScheme) != nil {
+		return DefaultNamingScheme
+	}
+	return cfg.NamingScheme
+}
+
+// FormatInstallDirName costruisce il nome della directory di installazione per una versione.
+//
+// Esempi:
+//
+//	FormatInstallDirName("JDK-{version}", "azul", "17.0.9")              // "JDK-17.0.9"
+//	FormatInstallDirName("{provider}-jdk-{version}", "azul", "17.0.9")   // "azul-jdk-17.0.9"
+//
+// Parametri:
+//
+//	scheme string   - Schema di naming ("" per il default)
+//	provider string - Provider del JDK ("" se sconosciuto)
+//	version string  - Versione esatta del JDK
+//
+// Restituisce:
+//
+//	string - Nome directory; se il provider è sconosciuto e lo schema lo richiede
+//	         viene usato lo schema di default per evitare nomi come "-jdk-17"
+func FormatInstallDirName(scheme, provider, version string) string {
+	if scheme == "" {
+		scheme = DefaultNamingScheme
+	}
+	if provider == "" && strings.Contains(scheme, "{provider}") {
+		scheme = DefaultNamingScheme
+	}
+	return strings.NewReplacer("{provider}", strings.ToLower(provider), "{version}", version).Replace(scheme)
+}
+
+// VariantInstallDirName costruisce il nome della directory di installazione per la variante richiesta.
+//
+// JRE, pacchetti JavaFX, altri sistemi e architetture non native (--type,
+// --javafx, --os, --arch) hanno una directory propria, distinta dal JDK della
+// stessa versione: al nome di FormatInstallDirName vengono aggiunti
+// ImageVariantSuffix, OSSuffix e ArchSuffix. Tutti i percorsi di 'download'
+// (singolo, più versioni, --all-lts, --manifest) devono usarla, così che una
+// variante non venga scambiata per il JDK già installato.
+//
+// Esempio di utilizzo:
+//
+//	// jenvy download 21 --type=jre --arch=x32
+//	VariantInstallDirName("JDK-{version}", "adoptium", "21.0.5+11") // "JDK-21.0.5+11-jre-x32"
+func VariantInstallDirName(scheme, provider, version string) string {
+	return FormatInstallDirName(scheme, provider, version+ImageVariantSuffix()+OSSuffix()+ArchSuffix())
+}
+
+// ParseInstallDirName estrae versione e provider dal nome di una directory di installazione.
+//
+// Forme riconosciute (in ordine):
+// 1. **Schema configurato**: Lo schema passato come parametro, convertito in pattern
+// 2. **Formato storico**: "JDK-{version}" (provider sconosciuto)
+// 3. **Formato per provider**: "{provider}-jdk-{version}" (es. "adoptium-jdk-17.0.9+9")
+//
+// Parametri:
+//
+//	dirName string - Nome della directory (non il percorso completo)
+//	scheme string  - Schema configurato ("" per considerare solo le forme standard)
+//
+// Restituisce:
+//
+//	version string  - Versione estratta (es. "17.0.9+9")
+//	provider string - Provider estratto ("" se non presente nel nome)
+//	ok bool         - true se il nome corrisponde a una forma riconosciuta
+func ParseInstallDirName(dirName, scheme string) (version, provider string, ok bool) {
+	if scheme != "" && scheme != DefaultNamingScheme {
+		if v, p, matched := matchNamingScheme(dirName, scheme); matched {
+			return v, p, true
+		}
+	}
+
+	if len(dirName) > 4 && strings.EqualFold(dirName[:4], "JDK-") {
+		return dirName[4:], "", true
+	}
+
+	if idx := strings.Index(strings.ToLower(dirName), "-jdk-"); idx > 0 {
+		v := dirName[idx+len("-jdk-"):]
+		if v != "" {
+			return v, strings.ToLower(dirName[:idx]), true
+		}
+	}
+
+	return "", "", false
+}
+
+// matchNamingScheme confronta un nome directory con uno schema personalizzato.
+func matchNamingScheme(dirName, scheme string) (version, provider string, ok bool) {
+	pattern := regexp.QuoteMeta(scheme)
+	pattern = strings.Replace(pattern, regexp.QuoteMeta("{provider}"), `(?P<provider>[A-Za-z0-9_.]+)`, 1)
+	pattern = strings.Replace(pattern, regexp.QuoteMeta("{version}"), `(?P<version>[0-9].*)`, 1)
+
+	re, err := regexp.Compile("^(?i)" + pattern + "$")
+	if err != nil {
+		return "", "", false
+	}
+	match := re.FindStringSubmatch(dirName)
+	if match == nil {
+		return "", "", false
+	}
+	for i, name := range re.SubexpNames() {
+		switch name {
+		case "version":
+			version = match[i]
+		case "provider":
+			provider = strings.ToLower(match[i])
+		}
+	}
+	return version, provider, version != ""
+}
+
+// archiveExtensions elenca i formati di archivio JDK riconosciuti, dal più specifico.
+var archiveExtensions = []string{".tar.gz", ".tar.xz", ".tgz", ".zip", ".msi"}
+
+// IsArchiveFile indica se un nome file corrisponde a un archivio JDK.
+func IsArchiveFile(filename string) bool {
+	lower := strings.ToLower(filename)
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// ArchiveExtension restituisce l'estensione di un archivio JDK (".zip", ".tar.gz", ...).
+//
+// A differenza di filepath.Ext riconosce le estensioni doppie come ".tar.gz",
+// così che un nome personalizzato mantenga il formato corretto per l'estrazione.
+func ArchiveExtension(filename string) string {
+	lower := strings.ToLower(filename)
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(lower, ext) {
+			return filename[len(filename)-len(ext):]
+		}
+	}
+	return filepath.Ext(filename)
+}
+
+// ExtractedFileMatches indica se un file estratto in precedenza corrisponde a una voce dell'archivio.
+//
+// Usato per riprendere un'estrazione interrotta: le voci già presenti con la stessa
+// dimensione e data di modifica vengono saltate. Gli estrattori impostano la data
+// solo dopo aver scritto l'intero file, quindi un file troncato da un'interruzione
+// non risulta mai corrispondente. Il confronto è al secondo per tollerare la
+// precisione dei timestamp dei diversi filesystem.
+//
+// Parametri:
+//
+//	path string        - Percorso di destinazione della voce
+//	size int64         - Dimensione dichiarata nell'archivio
+//	modTime time.Time  - Data di modifica dichiarata nell'archivio
+func ExtractedFileMatches(path string, size int64, modTime time.Time) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	return info.Size() == size && info.ModTime().Truncate(time.Second).Equal(modTime.Truncate(time.Second))
+}
+
+// FormatArchiveName costruisce il nome del file archivio a partire da un pattern.
+//
+// Usato da 'jenvy download --archive-name' per ottenere nomi prevedibili (es. per
+// mirror interni) al posto di filepath.Base(url), che per alcuni provider è poco
+// leggibile o collide tra vendor diversi.
+//
+// Placeholder supportati: {provider}, {version}, {os}, {arch}. Se il risultato non
+// termina con l'estensione dell'archivio originale, questa viene aggiunta
+// automaticamente per non compromettere il rilevamento del formato in estrazione.
+//
+// Parametri:
+//
+//	pattern string      - Pattern del nome (es. "temurin-{version}-{os}-{arch}")
+//	provider string     - Provider JDK (es. "adoptium")
+//	version string      - Versione risolta (es. "17.0.9+9")
+//	osName, arch string - Piattaforma di destinazione (es. "windows", "x64")
+//	original string     - Nome file originale, usato per l'estensione
+//
+// Restituisce:
+//
+//	string - Nome file sicuro
+//	error  - Errore se il pattern usa placeholder sconosciuti o produce un nome non valido
+//
+// Esempio di utilizzo:
+//
+//	name, _ := FormatArchiveName("temurin-{version}-{os}-{arch}", "adoptium", "17.0.9", "windows", "x64", "OpenJDK17U.zip")
+//	// name = "temurin-17.0.9-windows-x64.zip"
+func FormatArchiveName(pattern, provider, version, osName, arch, original string) (string, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return "", fmt.Errorf("archive name pattern is empty")
+	}
+
+	name := strings.NewReplacer(
+		"{provider}", strings.ToLower(provider),
+		"{version}", version,
+		"{os}", osName,
+		"{arch}", arch,
+	).Replace(pattern)
+
+	if strings.ContainsAny(name, "{}") {
+		return "", fmt.Errorf("archive name supports only {provider}, {version}, {os} and {arch} placeholders")
+	}
+	if strings.ContainsAny(name, `/\:*?"<>|`) || strings.Contains(name, "..") {
+		return "", fmt.Errorf("archive name '%s' contains characters not allowed in file names", name)
+	}
+	name = strings.TrimSpace(name)
+	if name == "" || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("archive name '%s' is not a valid file name", name)
+	}
+
+	ext := ArchiveExtension(original)
+	if ext != "" && !strings.HasSuffix(strings.ToLower(name), strings.ToLower(ext)) {
+		name += ext
+	}
+	return name, nil
+}
+
 // FindJDKInstallationPaths localizza tutti i percorsi di installazione per una versione JDK specifica.
 //
 // Questa funzione implementa un algoritmo di ricerca intelligente per trovare
@@ -319,19 +881,21 @@ func GetJenvyVersionsDirectory() (string, error) {
 // corrispondenze esatte che parziali nella directory delle versioni Jenvy.
 //
 // Algoritmo di ricerca a due fasi:
-// 1. **Exact Match**: Cerca corrispondenza esatta "JDK-{version}"
-//   - Input "17" → cerca "JDK-17"
+// 1. **Exact Match**: Cerca corrispondenza esatta del nome directory o della versione
+//   - Input "17" → cerca "JDK-17" (o "adoptium-jdk-17" con naming scheme per provider)
 //   - Input "17.0.5" → cerca "JDK-17.0.5"
+//   - Input "azul-jdk-17.0.5" → cerca esattamente quella directory
 //
 // 2. **Partial Match**: Se exact match non trova, cerca prefissi
 //   - Input "17" → trova "JDK-17.0.5", "JDK-17.0.8", etc.
 //   - Input "17.0" → trova tutte le patch versions di 17.0.x
 //
 // Comportamento ricerca:
-//   - **Case sensitive**: Match esatto su formato "JDK-{version}"
+//   - **Case sensitive**: Match sulla versione estratta con ParseInstallDirName
+//   - **Naming scheme**: Riconosce sia "JDK-{version}" che "{provider}-jdk-{version}"
 //   - **Prefix matching**: Versioni che iniziano con il pattern richiesto
 //   - **Directory filtering**: Solo directory valide (non file)
-//   - **Validazione JDK**: Ogni match viene verificato con IsValidJDKDirectory
+//   - **Validazione JDK**: Ogni match viene verificato con ResolveJDKHome (anche non appiattito)
 //
 // Parametri:
 //
@@ -363,37 +927,45 @@ func FindJDKInstallationPaths(version string) ([]string, error) {
 		return nil, fmt.Errorf("failed to get Jenvy directory: %w", err)
 	}
 
-	// Look for exact match first
-	exactMatch := filepath.Join(versionsDir, fmt.Sprintf("JDK-%s", version))
-	if _, err := os.Stat(exactMatch); err == nil {
-		if IsValidJDKDirectory(exactMatch) {
-			return []string{exactMatch}, nil
-		}
-	}
-
-	// Look for partial matches
+	// Installazioni locali seguite da quelle estratte altrove con 'download --extract-to'
+	externals := ExternalInstallDirs()
 	entries, err := os.ReadDir(versionsDir)
-	if err != nil {
+	if err != nil && (!os.IsNotExist(err) || len(externals) == 0) {
 		return nil, fmt.Errorf("failed to read versions directory: %w", err)
 	}
-
-	var matches []string
+	var candidates []string
 	for _, entry := range entries {
 		if entry.IsDir() {
-			name := entry.Name()
-			if strings.HasPrefix(name, "JDK-") {
-				jdkVersion := strings.TrimPrefix(name, "JDK-")
-				if strings.HasPrefix(jdkVersion, version) {
-					fullPath := filepath.Join(versionsDir, name)
-					if IsValidJDKDirectory(fullPath) {
-						matches = append(matches, fullPath)
-					}
-				}
-			}
+			candidates = append(candidates, filepath.Join(versionsDir, entry.Name()))
 		}
 	}
+	candidates = append(candidates, externals...)
 
-	return matches, nil
+	scheme := InstallNamingScheme()
+
+	// Look for exact matches first (directory name or parsed version)
+	var exact []string
+	var partial []string
+	for _, fullPath := range candidates {
+		name := filepath.Base(fullPath)
+		jdkVersion, _, ok := ParseInstallDirName(name, scheme)
+		if !ok {
+			continue
+		}
+		if _, ok := ResolveJDKHome(fullPath); !ok {
+			continue
+		}
+		if name == version || jdkVersion == version {
+			exact = append(exact, fullPath)
+		} else if strings.HasPrefix(jdkVersion, version) || strings.HasPrefix(name, version) {
+			partial = append(partial, fullPath)
+		}
+	}
+
+	if len(exact) > 0 {
+		return exact, nil
+	}
+	return partial, nil
 }
 
 // FindSingleJDKInstallation localizza un singolo percorso JDK, gestendo disambiguazione automatica.
@@ -0,0 +1,200 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LockFileName è il file di ~/.jenvy usato come lock tra processi jenvy concorrenti.
+const LockFileName = "lock"
+
+// LockTimeoutEnv è la variabile d'ambiente con l'attesa massima del lock (es. "5m", "0" per non attendere).
+const LockTimeoutEnv = "JENVY_LOCK_TIMEOUT"
+
+// DefaultLockTimeout è l'attesa massima del lock se né ambiente né configurazione la indicano.
+//
+// Basta per un download lento di un altro processo; oltre, è più probabile un
+// processo bloccato che vale la pena segnalare.
+const DefaultLockTimeout = 10 * time.Minute
+
+// lockPollInterval è l'intervallo tra due tentativi di acquisire un lock occupato.
+const lockPollInterval = 500 * time.Millisecond
+
+// errLockHeld indica un lock già acquisito da un altro processo (vedi tryLockFile).
+var errLockHeld = errors.New("lock held by another process")
+
+// noWait è impostata da --no-wait per l'esecuzione corrente (vedi SetNoWait).
+var noWait bool
+
+// StripNoWaitFlag rimuove l'opzione globale --no-wait dagli argomenti.
+//
+// Come --yes l'opzione viene tolta prima del dispatch, così vale per ogni
+// comando che acquisisce il lock di ~/.jenvy (vedi AcquireLock).
+//
+// Restituisce:
+//
+//	[]string - Argomenti senza l'opzione
+//	bool     - true se l'opzione era presente
+func StripNoWaitFlag(args []string) ([]string, bool) {
+	options, rest := cutOptions(args)
+	kept := make([]string, 0, len(args))
+	found := false
+	for i, arg := range options {
+		if i > 0 && arg == "--no-wait" {
+			found = true
+			continue
+		}
+		kept = append(kept, arg)
+	}
+	return append(kept, rest...), found
+}
+
+// SetNoWait fa fallire subito AcquireLock se il lock è occupato, per l'esecuzione corrente.
+func SetNoWait(enabled bool) {
+	noWait = enabled
+}
+
+// ParseLockTimeout interpreta l'attesa massima del lock: una durata (es. "90s", "5m"), secondi, o "0" per non attendere.
+func ParseLockTimeout(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "0" {
+		return 0, nil
+	}
+	timeout, err := ParseNetworkTimeout(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid lock timeout '%s' (expected e.g. 90s, 5m, seconds or 0)", value)
+	}
+	return timeout, nil
+}
+
+// LockTimeout restituisce l'attesa massima del lock per l'esecuzione corrente.
+//
+// Vale la precedenza --no-wait (nessuna attesa) > JENVY_LOCK_TIMEOUT >
+// configurazione (lock-timeout) > DefaultLockTimeout.
+func LockTimeout() (time.Duration, error) {
+	if noWait {
+		return 0, nil
+	}
+	if value := os.Getenv(LockTimeoutEnv); value != "" {
+		timeout, err := ParseLockTimeout(value)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", LockTimeoutEnv, err)
+		}
+		return timeout, nil
+	}
+	if cfg, err := LoadConfigOrDefault(); err == nil && cfg.LockTimeout != "" {
+		timeout, err := ParseLockTimeout(cfg.LockTimeout)
+		if err != nil {
+			return 0, fmt.Errorf("config lock-timeout: %w", err)
+		}
+		return timeout, nil
+	}
+	return DefaultLockTimeout, nil
+}
+
+// Lock è il lock esclusivo di ~/.jenvy acquisito da AcquireLock.
+type Lock struct {
+	file *os.File
+}
+
+// AcquireLock acquisisce il lock di ~/.jenvy/lock, attendendo se un altro processo jenvy lo possiede.
+//
+// Serve ai comandi che modificano ~/.jenvy/versions (download, estrazione,
+// rimozione, aggiornamento): due esecuzioni concorrenti, ad esempio job CI
+// paralleli sullo stesso agent, altrimenti potrebbero scrivere nella stessa
+// directory di versione. Il lock è un lock di sistema sul file (flock, o
+// LockFileEx su Windows), quindi viene rilasciato anche se il processo termina
+// bruscamente; il file contiene PID e comando del possessore, mostrati a chi attende.
+//
+// L'attesa massima è LockTimeout(); con attesa zero (--no-wait) un lock occupato
+// restituisce subito un errore.
+//
+// Parametri:
+//
+//	operation string - Comando in esecuzione, registrato nel file (es. "download 21")
+//
+// Esempio di utilizzo:
+//
+//	lock, err := utils.AcquireLock("download 21")
+//	if err != nil {
+//	    return err
+//	}
+//	defer lock.Release()
+func AcquireLock(operation string) (*Lock, error) {
+	timeout, err := LockTimeout()
+	if err != nil {
+		return nil, err
+	}
+	home, err := JenvyHome()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(home, 0755); err != nil {
+		return nil, fmt.Errorf("creating %s: %w", home, err)
+	}
+	path := filepath.Join(home, LockFileName)
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("opening lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	waiting := false
+	for {
+		err := tryLockFile(file)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, errLockHeld) {
+			file.Close()
+			return nil, fmt.Errorf("locking %s: %w", path, err)
+		}
+		holder := lockHolder(path)
+		if timeout == 0 {
+			file.Close()
+			return nil, fmt.Errorf("another jenvy process is using %s (%s): retry later, or omit --no-wait to wait for it", home, holder)
+		}
+		if time.Now().After(deadline) {
+			file.Close()
+			return nil, fmt.Errorf("gave up after %s waiting for another jenvy process (%s): raise %s or lock-timeout to wait longer", timeout, holder, LockTimeoutEnv)
+		}
+		if !waiting {
+			PrintInfo(fmt.Sprintf("Waiting for another jenvy process to finish (%s)...", holder))
+			waiting = true
+		}
+		time.Sleep(lockPollInterval)
+	}
+
+	// Il possessore è solo informativo: un errore di scrittura non invalida il lock
+	if err := file.Truncate(0); err == nil {
+		file.WriteAt([]byte(fmt.Sprintf("PID %d: jenvy %s\n", os.Getpid(), operation)), 0)
+	}
+	return &Lock{file: file}, nil
+}
+
+// Release rilascia il lock; il file resta, per non creare corse con chi lo sta aprendo.
+//
+// Il possessore viene cancellato prima di sbloccare, così chi attende non mostra
+// il PID di un processo già terminato.
+func (l *Lock) Release() {
+	if l == nil || l.file == nil {
+		return
+	}
+	l.file.Truncate(0)
+	unlockFile(l.file)
+	l.file.Close()
+	l.file = nil
+}
+
+// lockHolder descrive il processo che possiede il lock, letto dal file.
+func lockHolder(path string) string {
+	data, err := os.ReadFile(path)
+	if holder := strings.TrimSpace(string(data)); err == nil && holder != "" {
+		return holder
+	}
+	return "unknown process"
+}
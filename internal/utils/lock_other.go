@@ -0,0 +1,24 @@
+//go:build !windows
+
+package utils
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// tryLockFile acquisisce senza attendere un lock esclusivo (flock) sull'intero file.
+func tryLockFile(file *os.File) error {
+	err := unix.Flock(int(file.Fd()), unix.LOCK_EX|unix.LOCK_NB)
+	if errors.Is(err, unix.EWOULDBLOCK) {
+		return errLockHeld
+	}
+	return err
+}
+
+// unlockFile rilascia il lock acquisito con tryLockFile.
+func unlockFile(file *os.File) {
+	unix.Flock(int(file.Fd()), unix.LOCK_UN)
+}
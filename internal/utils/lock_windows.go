@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockRegionOffset è l'offset del byte bloccato con LockFileEx.
+//
+// Su Windows il lock impedisce la lettura dell'area bloccata anche agli altri
+// processi: bloccando un byte oltre la fine del file, chi attende può leggere
+// il possessore scritto all'inizio (vedi lockHolder).
+const lockRegionOffset = 1 << 30
+
+// tryLockFile acquisisce senza attendere un lock esclusivo (LockFileEx) sul file.
+func tryLockFile(file *os.File) error {
+	overlapped := windows.Overlapped{Offset: lockRegionOffset}
+	err := windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, &overlapped)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return errLockHeld
+	}
+	return err
+}
+
+// unlockFile rilascia il lock acquisito con tryLockFile.
+func unlockFile(file *os.File) {
+	overlapped := windows.Overlapped{Offset: lockRegionOffset}
+	windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, &overlapped)
+}
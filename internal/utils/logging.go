@@ -0,0 +1,229 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultLogRetention è il numero di giorni di log conservati se log-retention non è impostata.
+const DefaultLogRetention = 7
+
+// MaxLogSize è la dimensione oltre la quale il log del giorno viene ruotato in jenvy-<data>.N.log.
+const MaxLogSize = 5 * 1024 * 1024
+
+// logDateLayout è il formato della data nei nomi dei file di log (jenvy-2025-01-31.log).
+const logDateLayout = "2006-01-02"
+
+// logFile è il log aperto da StartLogging per l'esecuzione corrente (nil = logging inattivo).
+var logFile *os.File
+
+// LogsDir restituisce la directory dei log diagnostici (~/.jenvy/logs).
+func LogsDir() (string, error) {
+	jenvyDir, err := JenvyHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(jenvyDir, "logs"), nil
+}
+
+// LogRetentionDays restituisce i giorni di log da conservare secondo la configurazione.
+func LogRetentionDays(cfg *Config) int {
+	if cfg == nil || cfg.LogRetention < 1 {
+		return DefaultLogRetention
+	}
+	return cfg.LogRetention
+}
+
+// CurrentLogPath restituisce il file di log del giorno indicato.
+func CurrentLogPath(dir string, now time.Time) string {
+	return filepath.Join(dir, "jenvy-"+now.Format(logDateLayout)+".log")
+}
+
+// logFileDate estrae la data dal nome di un file di log di jenvy.
+//
+// Riconosce sia jenvy-<data>.log sia i file ruotati per dimensione jenvy-<data>.N.log.
+func logFileDate(name string) (time.Time, bool) {
+	if !strings.HasPrefix(name, "jenvy-") || !strings.HasSuffix(name, ".log") {
+		return time.Time{}, false
+	}
+	datePart := strings.TrimPrefix(name, "jenvy-")
+	if len(datePart) < len(logDateLayout) {
+		return time.Time{}, false
+	}
+	date, err := time.ParseInLocation(logDateLayout, datePart[:len(logDateLayout)], time.Local)
+	return date, err == nil
+}
+
+// ListLogFiles restituisce i file di log di jenvy presenti in dir, dal più vecchio al più recente.
+//
+// I file che non seguono lo schema jenvy-<data>[.N].log vengono ignorati.
+func ListLogFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if _, ok := logFileDate(entry.Name()); ok {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// RotateLogs applica la politica di conservazione ai log di jenvy.
+//
+// Elimina i file più vecchi di retentionDays giorni (oggi compreso) e, se il log
+// del giorno supera MaxLogSize, lo rinomina in jenvy-<data>.N.log così che
+// l'esecuzione successiva ne inizi uno nuovo. La data nel nome fa da confine di
+// rotazione: ogni giorno ha il proprio file.
+//
+// Parametri:
+//
+//	dir string          - Directory dei log (vedi LogsDir)
+//	retentionDays int   - Giorni da conservare (vedi LogRetentionDays)
+//	now time.Time       - Istante di riferimento
+//
+// Restituisce:
+//
+//	int   - Numero di file eliminati
+//	error - Primo errore incontrato
+//
+// Esempio di utilizzo:
+//
+//	removed, err := utils.RotateLogs(dir, 7, time.Now())
+func RotateLogs(dir string, retentionDays int, now time.Time) (int, error) {
+	files, err := ListLogFiles(dir)
+	if err != nil {
+		return 0, err
+	}
+	if retentionDays < 1 {
+		retentionDays = DefaultLogRetention
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	oldest := today.AddDate(0, 0, -(retentionDays - 1))
+
+	removed := 0
+	var firstErr error
+	for _, file := range files {
+		date, _ := logFileDate(filepath.Base(file))
+		if !date.Before(oldest) {
+			continue
+		}
+		if err := os.Remove(file); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		removed++
+	}
+
+	current := CurrentLogPath(dir, now)
+	if info, err := os.Stat(current); err == nil && info.Size() > MaxLogSize {
+		base := strings.TrimSuffix(current, ".log")
+		for n := 1; ; n++ {
+			rotated := fmt.Sprintf("%s.%d.log", base, n)
+			if _, err := os.Stat(rotated); os.IsNotExist(err) {
+				if err := os.Rename(current, rotated); err != nil && firstErr == nil {
+					firstErr = err
+				}
+				break
+			}
+		}
+	}
+	return removed, firstErr
+}
+
+// StartLogging apre il log del giorno e vi registra il comando in esecuzione.
+//
+// È best effort: se la directory non è scrivibile jenvy funziona comunque senza
+// log. Prima di aprire il file applica RotateLogs con la conservazione
+// configurata (log-retention). Le credenziali negli argomenti vengono mascherate.
+//
+// Parametri:
+//
+//	args []string - Argomenti del processo (os.Args)
+func StartLogging(args []string) {
+	dir, err := LogsDir()
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return
+	}
+	cfg, _ := LoadConfigOrDefault()
+	now := time.Now()
+	RotateLogs(dir, LogRetentionDays(cfg), now)
+
+	file, err := os.OpenFile(CurrentLogPath(dir, now), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	logFile = file
+	logMessage("START", strings.Join(RedactLogArgs(args[1:]), " "))
+}
+
+// StopLogging registra l'esito del comando e chiude il log.
+func StopLogging(err error) {
+	if logFile == nil {
+		return
+	}
+	result := fmt.Sprintf("exit %d", ExitCode(err))
+	if err != nil {
+		result += ": " + err.Error()
+	}
+	logMessage("END", result)
+	logFile.Close()
+	logFile = nil
+}
+
+// logMessage aggiunge una riga al log corrente, se il logging è attivo.
+func logMessage(level, text string) {
+	if logFile == nil {
+		return
+	}
+	fmt.Fprintf(logFile, "%s [%s] %s\n", time.Now().Format("2006-01-02 15:04:05"), level, text)
+}
+
+// RedactLogArgs maschera le credenziali negli argomenti prima di scriverli nel log.
+//
+// Copre il token di 'configure-private <endpoint> <token>', il valore di
+// 'config set' per le chiavi segrete e le opzioni --token.
+func RedactLogArgs(args []string) []string {
+	redacted := append([]string(nil), args...)
+	for i, arg := range redacted {
+		if name, _, ok := strings.Cut(arg, "="); ok && strings.Contains(strings.ToLower(name), "token") {
+			redacted[i] = name + "=" + MaskedConfigValue
+		} else if arg == "--token" && i+1 < len(redacted) {
+			redacted[i+1] = MaskedConfigValue
+		}
+	}
+	if len(redacted) == 0 {
+		return redacted
+	}
+	switch redacted[0] {
+	case "configure-private", "cp":
+		for i := 2; i < len(redacted); i++ {
+			redacted[i] = MaskedConfigValue
+		}
+	case "config":
+		if len(redacted) >= 4 && redacted[1] == "set" && IsSecretConfigKey(redacted[2]) {
+			for i := 3; i < len(redacted); i++ {
+				redacted[i] = MaskedConfigValue
+			}
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package utils
+
+// LongPath restituisce path invariato: il limite MAX_PATH esiste solo su Windows.
+func LongPath(path string) string {
+	return path
+}
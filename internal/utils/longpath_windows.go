@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// LongPath restituisce path nella forma estesa di Windows (\\?\C:\...), non soggetta al limite MAX_PATH di 260 caratteri.
+//
+// Alcuni archivi JDK contengono percorsi molto profondi (es. legal/, jmods/):
+// estratti sotto ~/.jenvy/versions superano i 260 caratteri, e senza
+// LongPathsEnabled nel registro le API Win32 li rifiutano. La forma estesa non
+// interpreta ".", ".." né "/", quindi il percorso viene prima reso assoluto e
+// pulito; i percorsi di rete diventano \\?\UNC\server\share\...
+//
+// Restituisce path invariato se è già in forma estesa o non può essere reso assoluto.
+//
+// Esempio di utilizzo:
+//
+//	LongPath(`C:\Users\me\.jenvy\versions\JDK-21\legal\java.base\LICENSE`)
+//	// `\\?\C:\Users\me\.jenvy\versions\JDK-21\legal\java.base\LICENSE`
+func LongPath(path string) string {
+	if path == "" || strings.HasPrefix(path, `\\?\`) {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	if strings.HasPrefix(abs, `\\`) {
+		return `\\?\UNC\` + abs[2:]
+	}
+	return `\\?\` + abs
+}
@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// InstallMetadataFile è il nome del file di metadati salvato in ogni directory di installazione.
+const InstallMetadataFile = ".jenvy-meta.json"
+
+// InstallMetadata descrive l'origine e lo stato di un'installazione JDK gestita da Jenvy.
+//
+// Il file viene scritto da 'jenvy download' (origine dell'archivio) e aggiornato
+// da 'jenvy extract' con l'hash del manifest dei file estratti, usato poi da
+// 'jenvy verify' per rilevare modifiche successive all'installazione. È la fonte
+// di provider e versione esatta per 'jenvy list', più affidabile del nome della
+// directory (rinominabile, o scelto con --archive-name e naming-scheme).
+type InstallMetadata struct {
+	Provider          string `json:"provider,omitempty"`
+	Version           string `json:"version,omitempty"`
+	DownloadURL       string `json:"download_url,omitempty"`
+	ArchiveName       string `json:"archive_name,omitempty"`
+	Checksum          string `json:"checksum,omitempty"`           // Checksum dell'archivio verificato al download
+	ChecksumAlgorithm string `json:"checksum_algorithm,omitempty"` // Algoritmo del checksum (sha256, sha512)
+	InstalledAt       string `json:"installed_at,omitempty"`       // RFC3339
+	ManifestHash      string `json:"manifest_hash,omitempty"`      // sha256 del manifest dei file
+	ImageType         string `json:"image_type,omitempty"`         // jdk o jre (--type); vuoto nelle installazioni precedenti
+	JavaFX            bool   `json:"javafx,omitempty"`             // true per i pacchetti con JavaFX (--javafx)
+	Arch              string `json:"arch,omitempty"`               // Architettura dell'archivio (x64, x32, aarch64; vedi --arch)
+	OS                string `json:"os,omitempty"`                 // Sistema dell'archivio, solo se diverso da quello in uso (--os)
+}
+
+// LoadInstallMetadata legge i metadati di un'installazione.
+//
+// Restituisce un errore os.IsNotExist se la directory non contiene metadati
+// (es. JDK installati con versioni precedenti di Jenvy).
+func LoadInstallMetadata(installDir string) (*InstallMetadata, error) {
+	data, err := os.ReadFile(filepath.Join(installDir, InstallMetadataFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var meta InstallMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", InstallMetadataFile, err)
+	}
+	return &meta, nil
+}
+
+// SaveInstallMetadata scrive i metadati nella directory di installazione.
+func SaveInstallMetadata(installDir string, meta *InstallMetadata) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding metadata: %w", err)
+	}
+	return os.WriteFile(filepath.Join(installDir, InstallMetadataFile), append(data, '\n'), 0644)
+}
+
+// ArchiveLinksFile è il file che elenca, nella directory di installazione, i link dell'archivio non creati.
+const ArchiveLinksFile = ".jenvy-links.json"
+
+// ArchiveLink è un link simbolico o fisico di un archivio tar che non è stato possibile creare.
+//
+// Succede ad esempio su NTFS senza il privilegio per i link simbolici, o per i
+// link che puntano fuori dall'installazione. Path e Target sono quelli registrati
+// nell'archivio, quindi comprendono l'eventuale directory wrapper (es. "jdk-21/").
+type ArchiveLink struct {
+	Path   string `json:"path"`
+	Target string `json:"target"`
+	Type   string `json:"type"`   // symlink | hardlink
+	Reason string `json:"reason"` // Errore che ha impedito di creare il link
+}
+
+// SaveArchiveLinks scrive in dir l'elenco dei link non creati (ArchiveLinksFile).
+func SaveArchiveLinks(dir string, links []ArchiveLink) error {
+	data, err := json.MarshalIndent(links, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding archive links: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dir, ArchiveLinksFile), append(data, '\n'), 0644)
+}
+
+// ComputeManifestHash calcola un hash che identifica il contenuto di un'installazione JDK.
+//
+// Il manifest è l'elenco ordinato di tutti i file con percorso relativo e hash
+// SHA-256 del contenuto; l'hash finale è lo SHA-256 del manifest. In questo modo
+// qualsiasi file aggiunto, rimosso, rinominato o modificato cambia il risultato.
+// Per i link simbolici conta la destinazione, non il file a cui puntano.
+//
+// Esclusioni:
+//   - Il file di metadati stesso (.jenvy-meta.json)
+//   - Gli archivi scaricati nella root dell'installazione (.zip, .tar.gz, ...)
+//
+// Parametri:
+//
+//	installDir string - Directory di installazione da analizzare
+//
+// Restituisce:
+//
+//	string - Hash esadecimale del manifest
+//	error  - Errore di lettura di file o directory
+func ComputeManifestHash(installDir string) (string, error) {
+	var files []string
+	err := filepath.WalkDir(installDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(installDir, path)
+		if err != nil {
+			return err
+		}
+		if rel == InstallMetadataFile {
+			return nil
+		}
+		if !strings.ContainsRune(rel, filepath.Separator) && IsArchiveFile(rel) {
+			return nil // archivio scaricato nella root dell'installazione
+		}
+		files = append(files, rel)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sort.Strings(files)
+	manifest := sha256.New()
+	for _, rel := range files {
+		fileHash, err := hashManifestEntry(filepath.Join(installDir, rel))
+		if err != nil {
+			return "", err
+		}
+		// Separatore uniforme così l'hash non dipende dal sistema operativo
+		fmt.Fprintf(manifest, "%s\x00%s\n", filepath.ToSlash(rel), fileHash)
+	}
+	return hex.EncodeToString(manifest.Sum(nil)), nil
+}
+
+// hashManifestEntry restituisce l'hash di una voce del manifest: il contenuto per un file, la destinazione per un link simbolico.
+func hashManifestEntry(path string) (string, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.Mode()&fs.ModeSymlink == 0 {
+		return hashFile(path)
+	}
+	target, err := os.Readlink(path)
+	if err != nil {
+		return "", err
+	}
+	return "symlink:" + filepath.ToSlash(target), nil
+}
+
+// hashFile restituisce lo SHA-256 esadecimale del contenuto di un file.
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
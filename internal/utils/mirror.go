@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// providerMirror descrive gli URL pubblici di un provider che un mirror può sostituire.
+type providerMirror struct {
+	Provider      string
+	APIBase       string   // Base delle API dei metadati
+	DownloadBases []string // Basi degli URL degli archivi restituiti dalle API
+	api           func(cfg *Config) *string
+	download      func(cfg *Config) *string
+}
+
+// providerMirrors elenca i provider pubblici con le basi sostituibili da un mirror.
+var providerMirrors = []providerMirror{
+	{"adoptium", "https://api.adoptium.net", []string{"https://github.com/adoptium"},
+		func(cfg *Config) *string { return &cfg.AdoptiumAPIMirror }, func(cfg *Config) *string { return &cfg.AdoptiumDownloadMirror }},
+	{"azul", "https://api.azul.com", []string{"https://cdn.azul.com"},
+		func(cfg *Config) *string { return &cfg.AzulAPIMirror }, func(cfg *Config) *string { return &cfg.AzulDownloadMirror }},
+	{"liberica", "https://api.bell-sw.com", []string{"https://download.bell-sw.com", "https://github.com/bell-sw"},
+		func(cfg *Config) *string { return &cfg.LibericaAPIMirror }, func(cfg *Config) *string { return &cfg.LibericaDownloadMirror }},
+	{"graalvm", "https://api.foojay.io", []string{"https://github.com/graalvm", "https://download.oracle.com"},
+		func(cfg *Config) *string { return &cfg.GraalVMAPIMirror }, func(cfg *Config) *string { return &cfg.GraalVMDownloadMirror }},
+}
+
+// ValidateMirrorURL verifica la base di un mirror (URL http o https assoluto).
+func ValidateMirrorURL(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid mirror URL '%s' (expected e.g. https://artifactory.corp/adoptium-api)", value)
+	}
+	return nil
+}
+
+// MirrorURL riscrive un URL di un provider pubblico verso il mirror configurato.
+//
+// Se rawURL inizia con la base API o di download di un provider per cui
+// config.json indica un mirror (es. adoptium-api-mirror), la base viene
+// sostituita e il resto del percorso resta invariato: il mirror deve quindi
+// replicare la struttura dell'originale, come fa un remote repository di
+// Artifactory o Nexus. Gli URL senza mirror vengono restituiti così come sono.
+//
+// Esempio di utilizzo:
+//
+//	// con adoptium-api-mirror = https://artifactory.corp/api/adoptium
+//	MirrorURL("https://api.adoptium.net/v3/info/available_releases")
+//	// "https://artifactory.corp/api/adoptium/v3/info/available_releases"
+func MirrorURL(rawURL string) string {
+	cfg, err := LoadConfigOrDefault()
+	if err != nil {
+		return rawURL
+	}
+	for _, mirror := range providerMirrors {
+		if replaced, ok := replaceBase(rawURL, mirror.APIBase, *mirror.api(cfg)); ok {
+			return replaced
+		}
+		for _, base := range mirror.DownloadBases {
+			if replaced, ok := replaceBase(rawURL, base, *mirror.download(cfg)); ok {
+				return replaced
+			}
+		}
+	}
+	return rawURL
+}
+
+// replaceBase sostituisce base con target in rawURL se rawURL inizia con base (a confine di percorso).
+func replaceBase(rawURL, base, target string) (string, bool) {
+	if target == "" || !strings.HasPrefix(rawURL, base) {
+		return rawURL, false
+	}
+	rest := rawURL[len(base):]
+	if rest != "" && rest[0] != '/' && rest[0] != '?' {
+		return rawURL, false
+	}
+	return strings.TrimRight(target, "/") + rest, true
+}
+
+// mirrorConfigKeys genera le chiavi di configurazione <provider>-api-mirror e <provider>-download-mirror.
+func mirrorConfigKeys() []ConfigKey {
+	var keys []ConfigKey
+	for _, mirror := range providerMirrors {
+		kinds := []struct {
+			kind, description string
+			field             func(cfg *Config) *string
+		}{
+			{"api", fmt.Sprintf("Mirror replacing %s for %s metadata", mirror.APIBase, mirror.Provider), mirror.api},
+			{"download", fmt.Sprintf("Mirror replacing %s for %s archives", strings.Join(mirror.DownloadBases, " and "), mirror.Provider), mirror.download},
+		}
+		for _, kind := range kinds {
+			field := kind.field
+			keys = append(keys, ConfigKey{
+				Name:        fmt.Sprintf("%s-%s-mirror", mirror.Provider, kind.kind),
+				JSONKey:     fmt.Sprintf("%s_%s_mirror", mirror.Provider, kind.kind),
+				Description: kind.description,
+				Get:         func(cfg *Config) string { return *field(cfg) },
+				Set: func(cfg *Config, value string) error {
+					value = strings.TrimSpace(value)
+					if value != "" {
+						if err := ValidateMirrorURL(value); err != nil {
+							return err
+						}
+					}
+					*field(cfg) = strings.TrimRight(value, "/")
+					return nil
+				},
+			})
+		}
+	}
+	return keys
+}
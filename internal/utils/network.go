@@ -0,0 +1,197 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Variabili d'ambiente equivalenti alle opzioni globali --timeout e --retries.
+const (
+	TimeoutEnv = "JENVY_TIMEOUT"
+	RetriesEnv = "JENVY_RETRIES"
+)
+
+// Timeout usati quando né --timeout, né JENVY_TIMEOUT, né la configurazione ne indicano uno.
+const (
+	DefaultRequestTimeout  = 60 * time.Second // Chiamate API (elenchi provider, repository privati)
+	DefaultDownloadTimeout = 30 * time.Minute // Download completo di un archivio JDK
+)
+
+// retryBaseDelay è l'attesa prima del primo nuovo tentativo; raddoppia a ogni tentativo.
+const retryBaseDelay = time.Second
+
+var (
+	networkTimeout time.Duration // 0 = timeout predefinito di ciascuna operazione
+	networkRetries int           // Nuovi tentativi dopo un errore di rete o una risposta 5xx/429
+)
+
+// NetworkTimeout restituisce il timeout globale impostato (0 se non impostato).
+func NetworkTimeout() time.Duration {
+	return networkTimeout
+}
+
+// NetworkRetries restituisce il numero di nuovi tentativi per le richieste HTTP.
+func NetworkRetries() int {
+	return networkRetries
+}
+
+// ParseNetworkTimeout interpreta un timeout come durata Go ("90s", "2m") o secondi ("90").
+func ParseNetworkTimeout(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, convErr := strconv.Atoi(value)
+		if convErr != nil {
+			return 0, fmt.Errorf("invalid timeout '%s' (expected e.g. 90s, 5m or seconds)", value)
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("timeout must be positive, got '%s'", value)
+	}
+	return timeout, nil
+}
+
+// ParseNetworkRetries interpreta il numero di nuovi tentativi (intero >= 0).
+func ParseNetworkRetries(value string) (int, error) {
+	retries, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || retries < 0 {
+		return 0, fmt.Errorf("invalid retries '%s' (expected a number >= 0)", value)
+	}
+	return retries, nil
+}
+
+// ApplyNetworkFlags rimuove --timeout e --retries dagli argomenti e li applica al processo.
+//
+// Come --insecure-skip-verify le opzioni sono globali e vengono tolte prima del
+// dispatch. Accettano sia "--timeout=2m" sia "--timeout 2m". Per ogni valore
+// vale la precedenza opzione > variabile d'ambiente (JENVY_TIMEOUT,
+// JENVY_RETRIES) > configurazione (network-timeout, network-retries).
+//
+// Parametri:
+//
+//	args []string - Argomenti del processo (os.Args)
+//
+// Restituisce:
+//
+//	[]string - Argomenti senza le opzioni di rete
+//	error    - Valore non valido in opzione, ambiente o configurazione
+//
+// Esempio di utilizzo:
+//
+//	args, err := utils.ApplyNetworkFlags(os.Args) // jenvy rl --timeout=2m --retries 3
+func ApplyNetworkFlags(args []string) ([]string, error) {
+	timeoutValue, retriesValue := os.Getenv(TimeoutEnv), os.Getenv(RetriesEnv)
+	timeoutSource, retriesSource := TimeoutEnv, RetriesEnv
+	if timeoutValue == "" || retriesValue == "" {
+		cfg, _ := LoadConfigOrDefault()
+		if timeoutValue == "" && cfg.NetworkTimeout != "" {
+			timeoutValue, timeoutSource = cfg.NetworkTimeout, "config network-timeout"
+		}
+		if retriesValue == "" && cfg.NetworkRetries > 0 {
+			retriesValue, retriesSource = strconv.Itoa(cfg.NetworkRetries), "config network-retries"
+		}
+	}
+
+	options, rest := cutOptions(args)
+	kept := make([]string, 0, len(args))
+	for i := 0; i < len(options); i++ {
+		arg := options[i]
+		name, value, hasValue := strings.Cut(arg, "=")
+		if name != "--timeout" && name != "--retries" {
+			kept = append(kept, arg)
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(options) {
+				return nil, fmt.Errorf("%s requires a value", name)
+			}
+			i++
+			value = options[i]
+		}
+		if name == "--timeout" {
+			timeoutValue, timeoutSource = value, name
+		} else {
+			retriesValue, retriesSource = value, name
+		}
+	}
+	kept = append(kept, rest...)
+
+	if timeoutValue != "" {
+		timeout, err := ParseNetworkTimeout(timeoutValue)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", timeoutSource, err)
+		}
+		networkTimeout = timeout
+	}
+	if retriesValue != "" {
+		retries, err := ParseNetworkRetries(retriesValue)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", retriesSource, err)
+		}
+		networkRetries = retries
+	}
+	return kept, nil
+}
+
+// DoWithRetry esegue una richiesta HTTP ripetendola dopo errori temporanei.
+//
+// Viene ripetuta fino a NetworkRetries() volte, con attesa esponenziale (1s, 2s,
+// 4s...), se la connessione fallisce o il server risponde 5xx o 429. Le altre
+// risposte (anche 4xx) vengono restituite subito al chiamante. La richiesta non
+// deve avere un corpo: jenvy usa solo GET e HEAD.
+//
+// Parametri:
+//
+//	client *http.Client - Client da usare (vedi NewHTTPClient)
+//	req *http.Request   - Richiesta senza corpo; il suo context interrompe anche le attese
+//
+// Restituisce:
+//
+//	*http.Response - Ultima risposta ricevuta
+//	error          - Ultimo errore di rete, o errore del context se annullato
+func DoWithRetry(client *http.Client, req *http.Request) (*http.Response, error) {
+	delay := retryBaseDelay
+	for attempt := 0; ; attempt++ {
+		resp, err := client.Do(req)
+		retryable := err != nil || resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+		if !retryable || attempt >= networkRetries || errors.Is(err, context.Canceled) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		reason := "network error"
+		if err == nil {
+			reason = fmt.Sprintf("status %d", resp.StatusCode)
+		}
+		PrintWarning(fmt.Sprintf("%s %s failed (%s), retrying in %s (%d/%d)", req.Method, req.URL.Host, reason, delay, attempt+1, networkRetries))
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		delay *= 2
+	}
+}
+
+// HTTPGet esegue una GET con il timeout e i tentativi globali.
+//
+// Sostituisce http.Get nei provider, che altrimenti userebbero http.DefaultClient
+// senza timeout né nuovi tentativi. Gli URL dei provider con un mirror
+// configurato vengono riscritti (vedi MirrorURL).
+func HTTPGet(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, MirrorURL(url), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	return DoWithRetry(NewHTTPClient(DefaultRequestTimeout), req)
+}
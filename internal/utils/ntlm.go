@@ -0,0 +1,187 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// Flag NTLM (MS-NLMP 2.2.2.5) richiesti nel messaggio NEGOTIATE.
+const (
+	ntlmNegotiateUnicode          = 0x00000001
+	ntlmRequestTarget             = 0x00000004
+	ntlmNegotiateNTLM             = 0x00000200
+	ntlmNegotiateAlwaysSign       = 0x00008000
+	ntlmNegotiateExtendedSecurity = 0x00080000
+	ntlmNegotiateTargetInfo       = 0x00800000
+	ntlmNegotiate128              = 0x20000000
+	ntlmNegotiate56               = 0x80000000
+
+	ntlmNegotiateFlags = ntlmNegotiateUnicode | ntlmRequestTarget | ntlmNegotiateNTLM | ntlmNegotiateAlwaysSign |
+		ntlmNegotiateExtendedSecurity | ntlmNegotiateTargetInfo | ntlmNegotiate128 | ntlmNegotiate56
+)
+
+// ntlmSignature apre ogni messaggio NTLM.
+var ntlmSignature = []byte("NTLMSSP\x00")
+
+// ntlmNegotiateMessage costruisce il messaggio NEGOTIATE (tipo 1), senza dominio né workstation.
+func ntlmNegotiateMessage() []byte {
+	msg := make([]byte, 32)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 1)
+	binary.LittleEndian.PutUint32(msg[12:], ntlmNegotiateFlags)
+	return msg
+}
+
+// ntlmAuthenticateMessage risponde al messaggio CHALLENGE (tipo 2) con un AUTHENTICATE (tipo 3) NTLMv2.
+//
+// Parametri:
+//
+//	challenge []byte - Messaggio CHALLENGE decodificato da Proxy-Authenticate
+//	user string      - Utente, senza dominio
+//	password string  - Password in chiaro
+//	domain string    - Dominio Windows (può essere vuoto)
+func ntlmAuthenticateMessage(challenge []byte, user, password, domain string) ([]byte, error) {
+	if len(challenge) < 32 || !bytes.Equal(challenge[:8], ntlmSignature) || binary.LittleEndian.Uint32(challenge[8:]) != 2 {
+		return nil, errors.New("invalid NTLM challenge from proxy")
+	}
+	flags := binary.LittleEndian.Uint32(challenge[20:])
+	serverChallenge := challenge[24:32]
+	var targetInfo []byte
+	if len(challenge) >= 48 {
+		length := int(binary.LittleEndian.Uint16(challenge[40:]))
+		offset := int(binary.LittleEndian.Uint32(challenge[44:]))
+		if offset+length <= len(challenge) {
+			targetInfo = challenge[offset : offset+length]
+		}
+	}
+
+	clientChallenge := make([]byte, 8)
+	if _, err := rand.Read(clientChallenge); err != nil {
+		return nil, err
+	}
+	lmResponse, ntResponse := ntlmV2Responses(user, password, domain, serverChallenge, clientChallenge, ntlmTimestamp(time.Now()), targetInfo)
+
+	fields := [][]byte{lmResponse, ntResponse, utf16LE(domain), utf16LE(user), nil, nil}
+	msg := make([]byte, 64)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 3)
+	for i, field := range fields {
+		header := msg[12+8*i:]
+		binary.LittleEndian.PutUint16(header, uint16(len(field)))
+		binary.LittleEndian.PutUint16(header[2:], uint16(len(field)))
+		binary.LittleEndian.PutUint32(header[4:], uint32(len(msg)))
+		msg = append(msg, field...)
+	}
+	binary.LittleEndian.PutUint32(msg[60:], flags&ntlmNegotiateFlags|ntlmNegotiateUnicode)
+	return msg, nil
+}
+
+// ntlmV2Responses calcola le risposte LMv2 e NTLMv2 (MS-NLMP 3.3.2).
+func ntlmV2Responses(user, password, domain string, serverChallenge, clientChallenge, timestamp, targetInfo []byte) ([]byte, []byte) {
+	responseKey := ntowfV2(user, password, domain)
+
+	blob := []byte{1, 1, 0, 0, 0, 0, 0, 0}
+	blob = append(blob, timestamp...)
+	blob = append(blob, clientChallenge...)
+	blob = append(blob, 0, 0, 0, 0)
+	blob = append(blob, targetInfo...)
+	blob = append(blob, 0, 0, 0, 0)
+
+	ntProof := hmacMD5(responseKey, serverChallenge, blob)
+	lmResponse := append(hmacMD5(responseKey, serverChallenge, clientChallenge), clientChallenge...)
+	return lmResponse, append(ntProof, blob...)
+}
+
+// ntowfV2 deriva la chiave NTLMv2 da utente, password e dominio.
+func ntowfV2(user, password, domain string) []byte {
+	ntHash := md4Sum(utf16LE(password))
+	return hmacMD5(ntHash[:], utf16LE(strings.ToUpper(user)+domain))
+}
+
+// ntlmTimestamp converte t in FILETIME (intervalli di 100ns dal 1601), little-endian.
+func ntlmTimestamp(t time.Time) []byte {
+	const epochDelta = 116444736000000000
+	stamp := make([]byte, 8)
+	binary.LittleEndian.PutUint64(stamp, uint64(t.UnixNano()/100+epochDelta))
+	return stamp
+}
+
+func hmacMD5(key []byte, data ...[]byte) []byte {
+	mac := hmac.New(md5.New, key)
+	for _, d := range data {
+		mac.Write(d)
+	}
+	return mac.Sum(nil)
+}
+
+func utf16LE(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	out := make([]byte, 2*len(units))
+	for i, unit := range units {
+		binary.LittleEndian.PutUint16(out[2*i:], unit)
+	}
+	return out
+}
+
+// md4Sum calcola l'hash MD4 (RFC 1320), richiesto dall'hash NT e assente dalla libreria standard.
+func md4Sum(data []byte) [16]byte {
+	msg := append([]byte{}, data...)
+	msg = append(msg, 0x80)
+	for len(msg)%64 != 56 {
+		msg = append(msg, 0)
+	}
+	msg = binary.LittleEndian.AppendUint64(msg, uint64(len(data))*8)
+
+	a, b, c, d := uint32(0x67452301), uint32(0xefcdab89), uint32(0x98badcfe), uint32(0x10325476)
+	var x [16]uint32
+	for block := 0; block < len(msg); block += 64 {
+		for i := range x {
+			x[i] = binary.LittleEndian.Uint32(msg[block+4*i:])
+		}
+		aa, bb, cc, dd := a, b, c, d
+
+		round1 := func(a, b, c, d, k uint32, s int) uint32 {
+			return bits.RotateLeft32(a+(b&c|^b&d)+x[k], s)
+		}
+		round2 := func(a, b, c, d, k uint32, s int) uint32 {
+			return bits.RotateLeft32(a+(b&c|b&d|c&d)+x[k]+0x5a827999, s)
+		}
+		round3 := func(a, b, c, d, k uint32, s int) uint32 {
+			return bits.RotateLeft32(a+(b^c^d)+x[k]+0x6ed9eba1, s)
+		}
+		for _, k := range []uint32{0, 4, 8, 12} {
+			a = round1(a, b, c, d, k, 3)
+			d = round1(d, a, b, c, k+1, 7)
+			c = round1(c, d, a, b, k+2, 11)
+			b = round1(b, c, d, a, k+3, 19)
+		}
+		for _, k := range []uint32{0, 1, 2, 3} {
+			a = round2(a, b, c, d, k, 3)
+			d = round2(d, a, b, c, k+4, 5)
+			c = round2(c, d, a, b, k+8, 9)
+			b = round2(b, c, d, a, k+12, 13)
+		}
+		for _, k := range []uint32{0, 2, 1, 3} {
+			a = round3(a, b, c, d, k, 3)
+			d = round3(d, a, b, c, k+8, 9)
+			c = round3(c, d, a, b, k+4, 11)
+			b = round3(b, c, d, a, k+12, 15)
+		}
+		a, b, c, d = a+aa, b+bb, c+cc, d+dd
+	}
+
+	var sum [16]byte
+	binary.LittleEndian.PutUint32(sum[0:], a)
+	binary.LittleEndian.PutUint32(sum[4:], b)
+	binary.LittleEndian.PutUint32(sum[8:], c)
+	binary.LittleEndian.PutUint32(sum[12:], d)
+	return sum
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Formati accettati da --output per 'list' e 'remote-list'.
+const (
+	OutputTable = "table" // Tabella colorata per il terminale (predefinito)
+	OutputJSON  = "json"  // Array di oggetti, una chiave per colonna
+	OutputCSV   = "csv"   // Intestazione più una riga per elemento
+)
+
+// ParseOutputFormat valida il valore di --output; vuoto equivale a OutputTable.
+func ParseOutputFormat(value string) (string, error) {
+	switch format := strings.ToLower(strings.TrimSpace(value)); format {
+	case "", OutputTable:
+		return OutputTable, nil
+	case OutputJSON, OutputCSV:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid --output '%s' (use table, json or csv)", value)
+	}
+}
+
+// RecordKey converte un'intestazione di tabella nella chiave usata in JSON e CSV (es. "Install Date" → "install_date").
+func RecordKey(header string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(header)), " ", "_")
+}
+
+// WriteRecords scrive righe tabellari in un formato leggibile dai programmi.
+//
+// È la controparte di PrintTable per IDE, script di build e dashboard: niente
+// colori né messaggi, solo i dati. Le chiavi derivano dalle intestazioni con
+// RecordKey, così JSON e CSV espongono gli stessi campi di --fields.
+//
+// Parametri:
+//
+//	w io.Writer       - Destinazione (di norma stdout)
+//	format string     - OutputJSON o OutputCSV
+//	headers []string  - Intestazioni delle colonne
+//	rows [][]string   - Righe, con lo stesso numero di colonne delle intestazioni
+//
+// Esempio di utilizzo:
+//
+//	utils.WriteRecords(os.Stdout, utils.OutputJSON, []string{"Version", "LTS"}, [][]string{{"21.0.5+11", "Yes"}})
+//	// [{"lts": "Yes", "version": "21.0.5+11"}]
+func WriteRecords(w io.Writer, format string, headers []string, rows [][]string) error {
+	keys := make([]string, len(headers))
+	for i, header := range headers {
+		keys[i] = RecordKey(header)
+	}
+
+	switch format {
+	case OutputJSON:
+		records := make([]map[string]string, 0, len(rows))
+		for _, row := range rows {
+			record := make(map[string]string, len(keys))
+			for i, key := range keys {
+				if i < len(row) {
+					record[key] = row[i]
+				}
+			}
+			records = append(records, record)
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	case OutputCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(keys); err != nil {
+			return err
+		}
+		if err := writer.WriteAll(rows); err != nil {
+			return err
+		}
+		return writer.Error()
+	default:
+		return fmt.Errorf("unsupported output format '%s'", format)
+	}
+}
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"runtime"
+	"sync"
+)
+
+// MaxScanWorkers limita il numero di goroutine usate per le scansioni del filesystem.
+//
+// Oltre questa soglia un disco lento non beneficia di ulteriore parallelismo e si
+// rischia solo di saturare gli handle aperti.
+const MaxScanWorkers = 8
+
+// ForEachParallel esegue fn(i) per ogni indice in [0, n) con un pool limitato di worker.
+//
+// Ogni chiamata riceve un indice diverso: scrivendo il risultato in una slice
+// pre-allocata alla posizione i, il chiamante ottiene un output nello stesso ordine
+// dell'input indipendentemente dall'ordine di completamento dei worker.
+//
+// Parametri:
+//
+//	n int            - Numero di elementi da elaborare
+//	workers int      - Numero massimo di goroutine (<= 0 usa min(NumCPU, MaxScanWorkers))
+//	fn func(i int)   - Funzione da eseguire per l'elemento i
+//
+// Esempio di utilizzo:
+//
+//	results := make([]int64, len(dirs))
+//	ForEachParallel(len(dirs), 0, func(i int) {
+//	    results[i] = dirSize(dirs[i])
+//	})
+func ForEachParallel(n, workers int, fn func(i int)) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+		if workers > MaxScanWorkers {
+			workers = MaxScanWorkers
+		}
+	}
+	if workers > n {
+		workers = n
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// JavaPathEntry descrive una directory del PATH che contiene un launcher java.
+type JavaPathEntry struct {
+	Position int    // Posizione (1-based) della directory nel PATH
+	Dir      string // Directory così come appare nel PATH
+}
+
+// FindJavaOnPath elenca, nell'ordine di ricerca, le directory del PATH che contengono java.
+//
+// Il primo elemento è quello che la shell esegue digitando 'java'; tutti i
+// successivi sono "oscurati". È il controllo alla base di 'jenvy diagnose-path':
+// la causa più comune di "ho eseguito use ma java -version mostra la vecchia
+// versione" è un'altra installazione Java che precede %JAVA_HOME%\bin nel PATH.
+//
+// Parametri:
+//
+//	pathValue string - Valore della variabile PATH (separatore del sistema operativo)
+//
+// Restituisce:
+//
+//	[]JavaPathEntry - Directory con java, nell'ordine del PATH (vuoto se nessuna)
+//
+// Esempio di utilizzo:
+//
+//	entries := FindJavaOnPath(os.Getenv("PATH"))
+//	// → [{3 C:\Program Files\Common Files\Oracle\Java\javapath} {7 C:\Users\user\.jenvy\versions\JDK-17\bin}]
+func FindJavaOnPath(pathValue string) []JavaPathEntry {
+	var entries []JavaPathEntry
+	for i, dir := range filepath.SplitList(pathValue) {
+		dir = strings.TrimSpace(dir)
+		if dir == "" {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(dir, JavaExecutableName()))
+		if err != nil || info.IsDir() {
+			continue
+		}
+		entries = append(entries, JavaPathEntry{Position: i + 1, Dir: dir})
+	}
+	return entries
+}
+
+// ExpandWindowsEnv espande i riferimenti %NOME% come fa Windows con i valori REG_EXPAND_SZ.
+//
+// Serve a valutare un PATH letto dal registro (es. "%JAVA_HOME%\bin;%SystemRoot%")
+// con valori ipotetici, come il nuovo JAVA_HOME di 'jenvy use --dry-run'.
+// I riferimenti a variabili sconosciute restano invariati, come in cmd.exe.
+//
+// Parametri:
+//
+//	value string                       - Testo con riferimenti %NOME%
+//	lookup func(string) (string, bool) - Risolve il nome di una variabile (es. os.LookupEnv)
+//
+// Esempio di utilizzo:
+//
+//	ExpandWindowsEnv(`%JAVA_HOME%\bin`, os.LookupEnv)
+//	// → C:\Users\user\.jenvy\versions\JDK-17\bin
+func ExpandWindowsEnv(value string, lookup func(string) (string, bool)) string {
+	var result strings.Builder
+	for {
+		start := strings.Index(value, "%")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(value[start+1:], "%")
+		if end < 0 {
+			break
+		}
+		end += start + 1
+
+		result.WriteString(value[:start])
+		name := value[start+1 : end]
+		if expanded, ok := lookup(name); ok && name != "" {
+			result.WriteString(expanded)
+			value = value[end+1:]
+		} else {
+			// Lascia il primo % invariato e riprova dal secondo, che può aprire un riferimento valido
+			result.WriteString(value[start:end])
+			value = value[end:]
+		}
+	}
+	result.WriteString(value)
+	return result.String()
+}
+
+// JavaPathOrigin riconosce lo strumento che ha aggiunto al PATH una directory con java.
+//
+// Serve a 'jenvy doctor' per suggerire come rimuovere un java esterno che
+// oscura il JDK gestito da jenvy. Il confronto ignora maiuscole e separatori.
+//
+// Restituisce:
+//
+//	string - Origine riconosciuta (es. "Oracle installer", "Scoop"); vuoto se sconosciuta
+//
+// Esempio di utilizzo:
+//
+//	JavaPathOrigin(`C:\Program Files\Common Files\Oracle\Java\javapath`) // → "Oracle installer"
+func JavaPathOrigin(dir string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(dir, "/", `\`))
+	switch {
+	case strings.Contains(normalized, `\oracle\java\javapath`):
+		return "Oracle installer"
+	case strings.Contains(normalized, `\scoop\`):
+		return "Scoop"
+	case strings.Contains(normalized, `\chocolatey\`):
+		return "Chocolatey"
+	case strings.Contains(normalized, `\.sdkman\`):
+		return "SDKMAN"
+	case strings.Contains(normalized, `\program files\eclipse adoptium\`),
+		strings.Contains(normalized, `\program files\microsoft\jdk-`),
+		strings.Contains(normalized, `\program files\java\`),
+		strings.Contains(normalized, `\program files\zulu\`),
+		strings.Contains(normalized, `\program files\bellsoft\`),
+		strings.Contains(normalized, `\program files\amazon corretto\`):
+		return "MSI installer"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Tipi di repository privato (impostazione private-type).
+const (
+	PrivateTypeJSON        = "json"        // Endpoint che restituisce l'elenco JSON delle release (predefinito)
+	PrivateTypeArtifactory = "artifactory" // Ricerca AQL in un repository Artifactory
+	PrivateTypeNexus       = "nexus"       // Ricerca REST in un repository Nexus 3
+	PrivateTypeS3          = "s3"          // Elenco degli oggetti di un bucket S3 (o compatibile, es. MinIO)
+	PrivateTypeDirectory   = "directory"   // Indice HTML di una directory HTTP (autoindex di Apache/nginx)
+	PrivateTypeGitHub      = "github"      // Asset delle release di repository GitHub o GitHub Enterprise
+)
+
+// ValidatePrivateType verifica il tipo di repository privato.
+func ValidatePrivateType(value string) error {
+	switch value {
+	case PrivateTypeJSON, PrivateTypeArtifactory, PrivateTypeNexus, PrivateTypeS3, PrivateTypeDirectory, PrivateTypeGitHub:
+		return nil
+	default:
+		return fmt.Errorf("invalid private repository type '%s' (expected json, artifactory, nexus, s3, directory or github)", value)
+	}
+}
+
+// PrivateType restituisce il tipo di repository privato configurato (json se non impostato).
+func PrivateType(cfg *Config) string {
+	if cfg == nil || cfg.PrivateType == "" {
+		return PrivateTypeJSON
+	}
+	return cfg.PrivateType
+}
+
+// SetPrivateAuthorization aggiunge le credenziali del repository privato a una richiesta.
+//
+// Un token nella forma "utente:password" viene inviato come Basic, il formato
+// abituale di Nexus e delle API key di Artifactory; gli altri come Bearer.
+func SetPrivateAuthorization(req *http.Request, token string) {
+	if token == "" {
+		return
+	}
+	if user, password, ok := strings.Cut(token, ":"); ok {
+		req.SetBasicAuth(user, password)
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+}
+
+// AuthorizePrivateRequest aggiunge il token privato alle richieste dirette all'host dell'endpoint privato.
+//
+// Serve ai download degli artefatti di Artifactory e Nexus, che di norma
+// richiedono le stesse credenziali della ricerca. Come per il token GitHub, le
+// richieste verso altri host non vengono modificate. Con private-type github
+// l'host è quello dell'API (vedi authorizeGitHubAsset).
+func AuthorizePrivateRequest(req *http.Request) {
+	cfg, err := LoadConfigOrDefault()
+	if err != nil || cfg.PrivateEndpoint == "" {
+		return
+	}
+	endpoint, err := url.Parse(cfg.PrivateEndpoint)
+	if err != nil {
+		return
+	}
+	if PrivateType(cfg) == PrivateTypeGitHub {
+		authorizeGitHubAsset(req, endpoint, cfg.PrivateToken)
+		return
+	}
+	if cfg.PrivateToken == "" || !strings.EqualFold(endpoint.Host, req.URL.Host) || endpoint.Scheme != req.URL.Scheme {
+		return
+	}
+	SetPrivateAuthorization(req, cfg.PrivateToken)
+}
+
+// githubAssetPath riconosce l'URL API di un asset di release (/repos/<owner>/<repo>/releases/assets/<id>).
+var githubAssetPath = regexp.MustCompile(`/repos/[^/]+/[^/]+/releases/assets/\d+$`)
+
+// authorizeGitHubAsset prepara il download di un asset tramite l'API GitHub.
+//
+// L'API restituisce il file solo con "Accept: application/octet-stream",
+// altrimenti risponde con i metadati JSON dell'asset. Il token privato, se
+// impostato, ha precedenza su quello GitHub già aggiunto da AuthorizeGitHubRequest.
+func authorizeGitHubAsset(req *http.Request, endpoint *url.URL, token string) {
+	api, err := url.Parse(GitHubAPIBaseFor(endpoint))
+	if err != nil || !strings.EqualFold(api.Host, req.URL.Host) || api.Scheme != req.URL.Scheme {
+		return
+	}
+	if githubAssetPath.MatchString(req.URL.Path) {
+		req.Header.Set("Accept", "application/octet-stream")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// artifactPlaceholders sono i segnaposto di private-pattern con l'espressione che li riconosce.
+var artifactPlaceholders = map[string]string{
+	"{version}": `(?P<version>\d+(?:[.+_]\d+)*)`,
+	"{os}":      `(?P<os>[A-Za-z0-9]+)`,
+	"{arch}":    `(?P<arch>[A-Za-z0-9]+(?:_64)?)`,
+}
+
+// CompileArtifactPattern converte private-pattern in un'espressione regolare sul nome del file.
+//
+// Il pattern descrive il nome degli archivi con i segnaposto {version}
+// (obbligatorio), {os} e {arch}; "*" corrisponde a qualsiasi testo. Il resto
+// viene confrontato alla lettera, senza distinzione tra maiuscole e minuscole.
+//
+// Esempio di utilizzo:
+//
+//	re, err := CompileArtifactPattern("jdk-{version}-{os}-{arch}.zip")
+//	// riconosce "jdk-21.0.1+12-windows-x64.zip"
+func CompileArtifactPattern(pattern string) (*regexp.Regexp, error) {
+	if !strings.Contains(pattern, "{version}") {
+		return nil, fmt.Errorf("invalid artifact pattern '%s': {version} is required", pattern)
+	}
+	var expr strings.Builder
+	expr.WriteString("(?i)^")
+	for rest := pattern; rest != ""; {
+		if rest[0] == '*' {
+			expr.WriteString(".*")
+			rest = rest[1:]
+			continue
+		}
+		matched := false
+		for placeholder, group := range artifactPlaceholders {
+			if strings.HasPrefix(rest, placeholder) {
+				expr.WriteString(group)
+				rest = rest[len(placeholder):]
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			expr.WriteString(regexp.QuoteMeta(rest[:1]))
+			rest = rest[1:]
+		}
+	}
+	expr.WriteString("$")
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid artifact pattern '%s': %v", pattern, err)
+	}
+	return re, nil
+}
@@ -0,0 +1,329 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProgressMode indica come viene mostrato l'avanzamento di un'operazione lunga (download, estrazione).
+type ProgressMode string
+
+const (
+	ProgressAuto  ProgressMode = "auto"  // bar su terminale, lines se l'output è rediretto o in CI
+	ProgressBar   ProgressMode = "bar"   // Riga aggiornata in place con '\r'
+	ProgressLines ProgressMode = "lines" // Una riga completa ogni progressLineInterval
+	ProgressNone  ProgressMode = ""      // Nessun progresso (operazioni concorrenti)
+)
+
+// ProgressUnit indica cosa misura un Progress.
+type ProgressUnit int
+
+const (
+	ProgressBytes ProgressUnit = iota // Byte, mostrati con unità adattiva (KB, MB, GB) e velocità
+	ProgressItems                     // Elementi (es. JDK di un'operazione batch)
+)
+
+const (
+	progressLineInterval = 5 * time.Second        // Intervallo tra due righe in modalità ProgressLines
+	progressBarInterval  = 100 * time.Millisecond // Aggiornamento massimo della barra
+	progressBarWidth     = 24                     // Caratteri della barra
+)
+
+// ResolveProgressMode traduce il valore di --progress nello stile effettivo.
+//
+// Con ProgressAuto (predefinito) usa la barra animata solo se stdout è un
+// terminale e la variabile CI non è impostata: rediretto su un file di log,
+// l'aggiornamento con '\r' produrrebbe migliaia di righe parziali.
+//
+// Esempio di utilizzo:
+//
+//	mode, err := ResolveProgressMode(ProgressMode("lines")) // ProgressLines, nil
+func ResolveProgressMode(mode ProgressMode) (ProgressMode, error) {
+	switch mode {
+	case ProgressAuto:
+		if IsTerminal(os.Stdout) && os.Getenv("CI") == "" {
+			return ProgressBar, nil
+		}
+		return ProgressLines, nil
+	case ProgressBar, ProgressLines:
+		return mode, nil
+	default:
+		return ProgressNone, fmt.Errorf("invalid --progress value '%s' (use auto, bar or lines)", mode)
+	}
+}
+
+// Progress mostra l'avanzamento di un'operazione: barra, percentuale, quantità, velocità e tempo stimato.
+//
+// È il componente comune di download, estrazioni e operazioni batch: chi lo usa
+// segnala solo l'avanzamento (Add, o Write per collegarlo a un io.Copy) e
+// chiude con Finish. La resa dipende dalla modalità già risolta con
+// ResolveProgressMode; con ProgressNone (o un *Progress nil) tutti i metodi non
+// fanno nulla, quindi non servono controlli nel chiamante.
+//
+// I metodi sono sicuri per l'uso concorrente: più download paralleli possono
+// sommare i propri byte nello stesso Progress (vedi Expect). Mentre una barra
+// è attiva, i messaggi di PrintInfo, PrintError e simili vengono stampati sopra
+// di essa invece di spezzarla.
+//
+// Esempio di output:
+//
+//	[DOWNLOAD] [█████████░░░░░░░░░░░░░░░]  38.2%  106.2 MB / 277.8 MB  8.3 MB/s  ETA 0:21   (ProgressBar)
+//	[DOWNLOAD] 38% (106.2 MB / 277.8 MB), 8.3 MB/s, ETA 0:21                              (ProgressLines)
+//
+// Esempio di utilizzo:
+//
+//	progress := NewProgress(ProgressBar, "[DOWNLOAD]", resp.ContentLength, ProgressBytes)
+//	_, err := io.Copy(io.MultiWriter(file, progress), resp.Body)
+//	progress.Finish()
+type Progress struct {
+	mode    ProgressMode
+	label   string
+	unit    ProgressUnit
+	total   int64 // <= 0 se sconosciuto
+	current int64
+	resumed int64 // Quantità già presente all'avvio, esclusa dalla velocità
+	start   time.Time
+	last    time.Time // Ultima resa (barra o riga)
+	width   int       // Lunghezza dell'ultima barra, per cancellarne i residui
+	out     io.Writer
+}
+
+var (
+	progressMu sync.Mutex // Protegge ogni Progress e activeBar
+	activeBar  *Progress  // Barra attualmente in fondo al terminale, nil se nessuna
+)
+
+// NewProgress crea un indicatore di avanzamento; total <= 0 indica un totale sconosciuto.
+func NewProgress(mode ProgressMode, label string, total int64, unit ProgressUnit) *Progress {
+	now := time.Now()
+	p := &Progress{mode: mode, label: label, unit: unit, total: total, start: now, last: now, out: os.Stdout}
+	if mode == ProgressBar {
+		progressMu.Lock()
+		activeBar = p
+		progressMu.Unlock()
+	}
+	return p
+}
+
+// Resume indica la quantità già completata prima di questa esecuzione (es. download ripreso).
+func (p *Progress) Resume(done int64) {
+	if p == nil {
+		return
+	}
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	p.current, p.resumed = done, done
+}
+
+// Expect aggiunge n unità al totale, per un Progress condiviso tra più
+// trasferimenti il cui totale è noto solo all'avvio di ciascuno.
+// Un valore negativo toglie la parte di un trasferimento fallito.
+func (p *Progress) Expect(n int64) {
+	if p == nil {
+		return
+	}
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	p.total += n
+}
+
+// Add registra n unità completate e aggiorna la resa quando è il momento.
+func (p *Progress) Add(n int64) {
+	if p == nil {
+		return
+	}
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	p.current += n
+	switch p.mode {
+	case ProgressBar:
+		if time.Since(p.last) >= progressBarInterval {
+			p.renderBar()
+		}
+	case ProgressLines:
+		if time.Since(p.last) >= progressLineInterval {
+			p.renderLine()
+		}
+	}
+}
+
+// Write implementa io.Writer contando i byte, per usare Progress con io.Copy e io.TeeReader.
+func (p *Progress) Write(b []byte) (int, error) {
+	p.Add(int64(len(b)))
+	return len(b), nil
+}
+
+// Finish mostra lo stato finale e chiude la riga della barra.
+func (p *Progress) Finish() {
+	if p == nil {
+		return
+	}
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	p.release()
+	switch p.mode {
+	case ProgressBar:
+		p.renderBar()
+		fmt.Fprintln(p.out)
+	case ProgressLines:
+		p.renderLine()
+	}
+}
+
+// Abort chiude la riga della barra senza altro output, quando l'operazione fallisce.
+func (p *Progress) Abort() {
+	if p == nil {
+		return
+	}
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	p.release()
+	if p.mode == ProgressBar {
+		fmt.Fprintln(p.out)
+	}
+}
+
+// release smette di considerare p la barra attiva; va chiamata con progressMu acquisito.
+func (p *Progress) release() {
+	if activeBar == p {
+		activeBar = nil
+	}
+}
+
+// printLine stampa una riga di output sopra l'eventuale barra attiva, poi la ridisegna.
+//
+// È il punto di passaggio delle funzioni Print*: senza, un messaggio stampato
+// durante un download (es. da un altro download parallelo) finirebbe in coda
+// alla riga della barra.
+func printLine(text string) {
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	if activeBar == nil {
+		fmt.Println(text)
+		return
+	}
+	fmt.Fprintf(activeBar.out, "\r%s\r", strings.Repeat(" ", activeBar.width))
+	fmt.Fprintln(activeBar.out, text)
+	activeBar.renderBar()
+}
+
+// renderBar riscrive in place la riga della barra; va chiamata con progressMu acquisito.
+func (p *Progress) renderBar() {
+	p.last = time.Now()
+	var line string
+	if p.total > 0 {
+		filled := int(float64(progressBarWidth) * p.fraction())
+		bar := strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled)
+		line = fmt.Sprintf("%s [%s] %5.1f%%  %s", p.label, bar, p.fraction()*100, p.amount())
+	} else {
+		line = fmt.Sprintf("%s %s", p.label, p.amount())
+	}
+	if rate := p.rate(); rate != "" {
+		line += "  " + rate
+	}
+	if eta := p.eta(); eta != "" {
+		line += "  ETA " + eta
+	}
+
+	padding := ""
+	if n := len([]rune(line)); n < p.width {
+		padding = strings.Repeat(" ", p.width-n)
+	} else {
+		p.width = n
+	}
+	fmt.Fprintf(p.out, "\r%s%s", line, padding)
+}
+
+// renderLine stampa una riga di avanzamento completa, adatta ai log.
+func (p *Progress) renderLine() {
+	p.last = time.Now()
+	line := fmt.Sprintf("%s %s", p.label, p.amount())
+	if p.total > 0 {
+		line = fmt.Sprintf("%s %d%% (%s)", p.label, int(p.fraction()*100), p.amount())
+	}
+	if rate := p.rate(); rate != "" {
+		line += ", " + rate
+	}
+	if eta := p.eta(); eta != "" {
+		line += ", ETA " + eta
+	}
+	fmt.Fprintln(p.out, line)
+}
+
+// fraction restituisce la frazione completata, limitata a 1.
+func (p *Progress) fraction() float64 {
+	if p.total <= 0 {
+		return 0
+	}
+	if p.current >= p.total {
+		return 1
+	}
+	return float64(p.current) / float64(p.total)
+}
+
+// amount descrive la quantità completata e, se noto, il totale (es. "106.2 MB / 277.8 MB", "3/5").
+func (p *Progress) amount() string {
+	if p.unit == ProgressItems {
+		if p.total > 0 {
+			return fmt.Sprintf("%d/%d", p.current, p.total)
+		}
+		return fmt.Sprintf("%d", p.current)
+	}
+	if p.total > 0 {
+		return FormatBytes(p.current) + " / " + FormatBytes(p.total)
+	}
+	return FormatBytes(p.current)
+}
+
+// rate restituisce la velocità per i byte (es. "8.3 MB/s"), vuota nel primo istante.
+func (p *Progress) rate() string {
+	elapsed := time.Since(p.start).Seconds()
+	if p.unit != ProgressBytes || elapsed < 0.5 {
+		return ""
+	}
+	return FormatBytes(int64(float64(p.current-p.resumed)/elapsed)) + "/s"
+}
+
+// eta stima il tempo rimanente dalla velocità media, vuoto se il totale è sconosciuto o già raggiunto.
+func (p *Progress) eta() string {
+	elapsed := time.Since(p.start)
+	done := p.current - p.resumed
+	if p.total <= 0 || done <= 0 || p.current >= p.total || elapsed < time.Second {
+		return ""
+	}
+	remaining := time.Duration(float64(elapsed) * float64(p.total-p.current) / float64(done))
+	return FormatETA(remaining)
+}
+
+// FormatBytes formatta una dimensione con l'unità più adatta (es. "512 B", "1.5 MB", "2.1 GB").
+func FormatBytes(bytes int64) string {
+	if bytes == 0 {
+		return "0 B"
+	}
+
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	size := float64(bytes)
+	unitIndex := 0
+	for size >= 1024 && unitIndex < len(units)-1 {
+		size /= 1024
+		unitIndex++
+	}
+
+	if unitIndex == 0 {
+		return fmt.Sprintf("%.0f %s", size, units[unitIndex])
+	}
+	return fmt.Sprintf("%.1f %s", size, units[unitIndex])
+}
+
+// FormatETA formatta un tempo stimato come m:ss, o h:mm:ss oltre l'ora (es. "0:42", "1:05:09").
+func FormatETA(d time.Duration) string {
+	seconds := int(d.Round(time.Second).Seconds())
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
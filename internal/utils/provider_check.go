@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoUsableReleases indica che il provider ha risposto con JSON valido ma senza release utilizzabili.
+//
+// Quasi sempre significa che lo schema dell'API è cambiato: i campi attesi non
+// esistono più e il parsing produce elenchi vuoti o versioni a zero. Va distinto
+// da "versione non trovata", che invece riguarda una singola richiesta.
+var ErrNoUsableReleases = errors.New("provider response parsed but contained no usable releases, the API may have changed")
+
+// CheckUsableReleases segnala un elenco di release sospetto dopo un parsing riuscito.
+//
+// Una release è utilizzabile se ha una major valida e un link di download; i
+// provider la valutano sui propri campi e passano qui solo i conteggi.
+//
+// Parametri:
+//
+//	total int  - Elementi letti dalla risposta
+//	usable int - Elementi con versione e link di download
+//
+// Restituisce:
+//
+//	error - ErrNoUsableReleases (con i conteggi) se nessuna release è utilizzabile, nil altrimenti
+//
+// Esempio di utilizzo:
+//
+//	if err := utils.CheckUsableReleases(len(data), usable); err != nil {
+//	    return nil, err
+//	}
+func CheckUsableReleases(total, usable int) error {
+	if usable > 0 {
+		return nil
+	}
+	if total == 0 {
+		return fmt.Errorf("%w (empty release list)", ErrNoUsableReleases)
+	}
+	return fmt.Errorf("%w (%d entries, none with a version and download link)", ErrNoUsableReleases, total)
+}
@@ -0,0 +1,277 @@
+package utils
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Schemi di autenticazione verso il proxy (impostazione proxy-auth).
+const (
+	ProxyAuthBasic = "basic"
+	ProxyAuthNTLM  = "ntlm"
+)
+
+// ProxySettings è la configurazione proxy effettiva di un'esecuzione.
+type ProxySettings struct {
+	URL      *url.URL // Proxy configurato (nil = HTTP_PROXY/HTTPS_PROXY)
+	NoProxy  string   // Host esclusi, separati da virgola (config no-proxy o NO_PROXY)
+	Auth     string   // basic | ntlm
+	User     string   // Utente, anche nella forma DOMINIO\utente per NTLM
+	Password string
+}
+
+// ParseProxyURL valida l'URL di un proxy (http, https o socks5); "host:porta" implica http://.
+func ParseProxyURL(value string) (*url.URL, error) {
+	value = strings.TrimSpace(value)
+	if !strings.Contains(value, "://") {
+		value = "http://" + value
+	}
+	proxyURL, err := url.Parse(value)
+	if err != nil || proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL '%s' (expected e.g. http://proxy.corp:8080)", value)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+		return proxyURL, nil
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme '%s' (use http, https or socks5)", proxyURL.Scheme)
+	}
+}
+
+// ValidateProxyAuth verifica lo schema di autenticazione del proxy.
+func ValidateProxyAuth(value string) error {
+	switch value {
+	case ProxyAuthBasic, ProxyAuthNTLM:
+		return nil
+	default:
+		return fmt.Errorf("invalid proxy auth '%s' (expected basic or ntlm)", value)
+	}
+}
+
+// LoadProxySettings combina la configurazione con le variabili d'ambiente standard.
+//
+// Il proxy di config.json (chiave proxy) prevale su HTTP_PROXY/HTTPS_PROXY e vale
+// per ogni schema; no-proxy prevale su NO_PROXY. Le credenziali proxy-user e
+// proxy-password si applicano anche al proxy preso dall'ambiente; quelle
+// incluse nell'URL del proxy valgono se proxy-user non è impostato.
+func LoadProxySettings(cfg *Config) ProxySettings {
+	settings := ProxySettings{Auth: ProxyAuthBasic, NoProxy: os.Getenv("NO_PROXY")}
+	if settings.NoProxy == "" {
+		settings.NoProxy = os.Getenv("no_proxy")
+	}
+	if cfg == nil {
+		return settings
+	}
+	if cfg.Proxy != "" {
+		if proxyURL, err := ParseProxyURL(cfg.Proxy); err == nil {
+			settings.URL = proxyURL
+		}
+	}
+	if cfg.NoProxy != "" {
+		settings.NoProxy = cfg.NoProxy
+	}
+	if cfg.ProxyAuth != "" {
+		settings.Auth = cfg.ProxyAuth
+	}
+	settings.User, settings.Password = cfg.ProxyUser, cfg.ProxyPassword
+	return settings
+}
+
+// ProxyFor restituisce il proxy da usare per target (nil = connessione diretta).
+//
+// Le credenziali configurate vengono inserite nell'URL del proxy: per basic è
+// il modo in cui net/http invia Proxy-Authorization, anche nelle CONNECT.
+// no-proxy vale anche per il proxy preso da HTTP_PROXY/HTTPS_PROXY, che
+// net/http confronterebbe solo con NO_PROXY.
+func (s ProxySettings) ProxyFor(target *url.URL) (*url.URL, error) {
+	if ProxyBypassed(target.Host, s.NoProxy) {
+		return nil, nil
+	}
+	proxyURL := s.URL
+	if proxyURL == nil {
+		envURL, err := http.ProxyFromEnvironment(&http.Request{URL: target})
+		if err != nil || envURL == nil {
+			return envURL, err
+		}
+		proxyURL = envURL
+	}
+	if s.User != "" {
+		withUser := *proxyURL
+		withUser.User = url.UserPassword(s.User, s.Password)
+		proxyURL = &withUser
+	}
+	return proxyURL, nil
+}
+
+// ProxyBypassed indica se host (con porta opzionale) è escluso dal proxy secondo noProxy.
+//
+// Segue la sintassi di NO_PROXY: "*" esclude tutto, "corp.local" e ".corp.local"
+// escludono il dominio e i sottodomini, "10.0.0.0/8" una rete e "host:8080"
+// solo quella porta.
+//
+// Esempio di utilizzo:
+//
+//	ProxyBypassed("repo.corp.local:443", "localhost,.corp.local") // true
+func ProxyBypassed(host, noProxy string) bool {
+	hostname, port := host, ""
+	if h, p, err := net.SplitHostPort(host); err == nil {
+		hostname, port = h, p
+	}
+	hostname = strings.ToLower(hostname)
+	ip := net.ParseIP(hostname)
+
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			if p != port {
+				continue
+			}
+			entry = h
+		}
+		entry = strings.TrimPrefix(strings.TrimPrefix(entry, "*"), ".")
+		if hostname == entry || strings.HasSuffix(hostname, "."+entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// configureProxy applica le impostazioni proxy al transport di un client.
+//
+// Con basic basta la funzione Proxy di net/http. NTLM richiede invece un
+// handshake sulla stessa connessione, che net/http non gestisce: il transport
+// apre il tunnel CONNECT da sé (dialNTLMTunnel) e il proxy non viene più
+// indicato a net/http. NTLM vale quindi per i proxy http:// e per il traffico
+// tunnellizzato, cioè per tutte le API e i download dei provider (HTTPS).
+func configureProxy(transport *http.Transport, settings ProxySettings) {
+	if settings.Auth != ProxyAuthNTLM {
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return settings.ProxyFor(req.URL)
+		}
+		return
+	}
+	dialer := &net.Dialer{Timeout: DefaultRequestTimeout, KeepAlive: DefaultRequestTimeout}
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		target := &url.URL{Scheme: "https", Host: addr}
+		if _, port, _ := net.SplitHostPort(addr); port == "80" {
+			target.Scheme = "http"
+		}
+		proxyURL, err := settings.ProxyFor(target)
+		if err != nil {
+			return nil, err
+		}
+		if proxyURL == nil {
+			return dialer.DialContext(ctx, network, addr)
+		}
+		return dialNTLMTunnel(ctx, dialer, proxyURL, addr)
+	}
+}
+
+// dialNTLMTunnel apre un tunnel CONNECT verso addr autenticandosi al proxy con NTLMv2.
+func dialNTLMTunnel(ctx context.Context, dialer *net.Dialer, proxyURL *url.URL, addr string) (net.Conn, error) {
+	if proxyURL.Scheme != "http" {
+		return nil, fmt.Errorf("NTLM proxy authentication requires an http:// proxy, got %s", proxyURL.Scheme)
+	}
+	user := proxyURL.User.Username()
+	password, _ := proxyURL.User.Password()
+	domain := ""
+	if d, u, ok := strings.Cut(user, `\`); ok {
+		domain, user = d, u
+	}
+
+	proxyAddr := proxyURL.Host
+	if proxyURL.Port() == "" {
+		proxyAddr = net.JoinHostPort(proxyURL.Hostname(), "80")
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	reader := bufio.NewReader(conn)
+
+	resp, err := sendConnect(conn, reader, addr, "NTLM "+base64.StdEncoding.EncodeToString(ntlmNegotiateMessage()))
+	if err == nil && resp.StatusCode == http.StatusProxyAuthRequired {
+		var challenge []byte
+		challenge, err = ntlmChallengeFromHeader(resp.Header.Values("Proxy-Authenticate"))
+		if err == nil {
+			var authenticate []byte
+			authenticate, err = ntlmAuthenticateMessage(challenge, user, password, domain)
+			if err == nil {
+				resp, err = sendConnect(conn, reader, addr, "NTLM "+base64.StdEncoding.EncodeToString(authenticate))
+			}
+		}
+	}
+	if err == nil && resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("proxy CONNECT to %s failed: %s", addr, resp.Status)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return &bufferedConn{Conn: conn, reader: reader}, nil
+}
+
+// sendConnect invia una richiesta CONNECT e legge la risposta del proxy, scartandone il corpo.
+func sendConnect(conn net.Conn, reader *bufio.Reader, addr, authorization string) (*http.Response, error) {
+	request := fmt.Sprintf("CONNECT %s HTTP/1.1\r\nHost: %s\r\nProxy-Authorization: %s\r\nProxy-Connection: Keep-Alive\r\nUser-Agent: %s\r\n\r\n",
+		addr, addr, authorization, UserAgent)
+	if _, err := conn.Write([]byte(request)); err != nil {
+		return nil, err
+	}
+	resp, err := http.ReadResponse(reader, &http.Request{Method: http.MethodConnect})
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// ntlmChallengeFromHeader estrae il messaggio CHALLENGE dagli header Proxy-Authenticate.
+func ntlmChallengeFromHeader(values []string) ([]byte, error) {
+	for _, value := range values {
+		scheme, token, _ := strings.Cut(strings.TrimSpace(value), " ")
+		if strings.EqualFold(scheme, "NTLM") && token != "" {
+			return base64.StdEncoding.DecodeString(strings.TrimSpace(token))
+		}
+	}
+	return nil, fmt.Errorf("proxy did not offer NTLM authentication (Proxy-Authenticate: %s)", strings.Join(values, ", "))
+}
+
+// bufferedConn conserva i byte già letti dal proxy durante l'handshake.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
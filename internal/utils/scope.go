@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProjectVersionFile è il file che fissa il JDK di un progetto ('jenvy use <version> --local').
+const ProjectVersionFile = ".jenvy-version"
+
+// JavaVersionFile è il file di versione usato da jenv e da altri version manager.
+//
+// Viene letto come ripiego, così i progetti che lo versionano già non devono
+// duplicarlo; jenvy scrive sempre ProjectVersionFile.
+const JavaVersionFile = ".java-version"
+
+// ProjectVersionFiles elenca i file di progetto riconosciuti, in ordine di precedenza
+// all'interno della stessa directory.
+var ProjectVersionFiles = []string{ProjectVersionFile, JavaVersionFile}
+
+// Scope in cui può essere attivato un JDK, dal più specifico al più generale.
+const (
+	ScopeProject = "project" // File .jenvy-version nella directory corrente o in una superiore
+	ScopeUser    = "user"    // JAVA_HOME utente (HKCU\Environment)
+	ScopeSystem  = "system"  // JAVA_HOME di sistema (HKLM), il comportamento storico di 'jenvy use'
+)
+
+// ScopeValue è il JDK richiesto da uno scope e da dove proviene.
+type ScopeValue struct {
+	Scope  string // ScopeProject, ScopeUser o ScopeSystem
+	Value  string // Versione (scope project) oppure JAVA_HOME (scope user/system)
+	Source string // File o chiave di registro da cui è stato letto il valore
+}
+
+// EffectiveScope applica la precedenza degli scope: project > user > system.
+//
+// Il modello segue quello di Windows, dove la JAVA_HOME utente nasconde quella
+// di sistema, con in più il file di progetto che vince su entrambe perché è il
+// più specifico. Gli scope senza valore vengono ignorati.
+//
+// Parametri:
+//
+//	values ...ScopeValue - Valori letti per ciascuno scope, in qualsiasi ordine
+//
+// Restituisce:
+//
+//	ScopeValue - Lo scope effettivo
+//	bool       - false se nessuno scope ha un valore
+//
+// Esempio di utilizzo:
+//
+//	effective, ok := utils.EffectiveScope(
+//	    utils.ScopeValue{Scope: utils.ScopeSystem, Value: `C:\jdk-17`},
+//	    utils.ScopeValue{Scope: utils.ScopeUser, Value: `C:\jdk-21`},
+//	)
+//	// effective.Scope = "user"
+func EffectiveScope(values ...ScopeValue) (ScopeValue, bool) {
+	for _, scope := range []string{ScopeProject, ScopeUser, ScopeSystem} {
+		for _, value := range values {
+			if value.Scope == scope && value.Value != "" {
+				return value, true
+			}
+		}
+	}
+	return ScopeValue{}, false
+}
+
+// FindProjectVersion cerca un file .jenvy-version partendo da dir e risalendo le directory superiori.
+//
+// In ogni directory viene provato anche .java-version (vedi ProjectVersionFiles):
+// vince il primo file trovato risalendo, quindi un .java-version nel progetto ha
+// la precedenza su un .jenvy-version in una directory superiore.
+//
+// Restituisce:
+//
+//	ScopeValue - Versione e percorso del file trovato (Scope = ScopeProject)
+//	bool       - false se nessun file è stato trovato fino alla radice
+func FindProjectVersion(dir string) (ScopeValue, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ScopeValue{}, false
+	}
+	for {
+		for _, name := range ProjectVersionFiles {
+			path := filepath.Join(dir, name)
+			if version, err := ReadProjectVersionFile(path); err == nil {
+				return ScopeValue{Scope: ScopeProject, Value: version, Source: path}, true
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ScopeValue{}, false
+		}
+		dir = parent
+	}
+}
+
+// ReadProjectVersionFile legge la versione da un file .jenvy-version.
+//
+// Conta solo la prima riga non vuota; le righe che iniziano con '#' sono commenti.
+func ReadProjectVersionFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return line, nil
+		}
+	}
+	return "", fmt.Errorf("%s is empty", path)
+}
+
+// WriteProjectVersionFile scrive la versione nel file .jenvy-version di dir.
+//
+// Restituisce il percorso del file scritto.
+func WriteProjectVersionFile(dir, version string) (string, error) {
+	version = strings.TrimSpace(version)
+	if version == "" || strings.ContainsAny(version, "\r\n") {
+		return "", errors.New("invalid version for " + ProjectVersionFile)
+	}
+	path := filepath.Join(dir, ProjectVersionFile)
+	if err := os.WriteFile(path, []byte(version+"\n"), 0644); err != nil {
+		return "", fmt.Errorf("writing %s: %w", path, err)
+	}
+	return path, nil
+}
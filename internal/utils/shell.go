@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxShellLookupDepth limita la risalita dell'albero dei processi in DetectParentShell.
+//
+// jenvy può essere avviato tramite wrapper (shim .cmd, winpty, script): qualche livello
+// basta a raggiungere la shell interattiva senza risalire fino a explorer.exe o init.
+const maxShellLookupDepth = 4
+
+// ShellFromProcessName riconosce una shell dal nome del suo eseguibile.
+//
+// Accetta percorsi completi, estensione .exe e il prefisso "-" delle shell di login.
+//
+// Restituisce:
+//
+//	string - "bash", "zsh", "fish", "cmd", "powershell" o "pwsh"; vuoto se non è una shell nota
+//
+// Esempio di utilizzo:
+//
+//	ShellFromProcessName(`C:\Program Files\Git\usr\bin\bash.exe`) // → "bash"
+//	ShellFromProcessName("-zsh")                                 // → "zsh"
+func ShellFromProcessName(name string) string {
+	name = strings.ToLower(filepath.Base(strings.ReplaceAll(name, `\`, "/")))
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "-"), ".exe")
+	switch name {
+	case "bash", "zsh", "fish", "cmd", "powershell", "pwsh":
+		return name
+	default:
+		return ""
+	}
+}
+
+// DetectParentShell individua la shell da cui è stato lanciato jenvy.
+//
+// Risale i processi antenati (API Toolhelp su Windows, /proc sui sistemi Unix)
+// finché non trova una shell nota, fino a maxShellLookupDepth livelli. Serve ai
+// comandi di integrazione con la shell per scegliere un default sensato al posto
+// di chiedere --shell all'utente.
+//
+// Restituisce:
+//
+//	string - Shell riconosciuta (vedi ShellFromProcessName), vuoto se non determinabile
+//
+// Esempio di utilizzo:
+//
+//	if DetectParentShell() == "pwsh" {
+//	    fmt.Println("Use 'jenvy completion powershell'")
+//	}
+func DetectParentShell() string {
+	pid := os.Getppid()
+	for depth := 0; depth < maxShellLookupDepth && pid > 0; depth++ {
+		parent, name, ok := processInfo(pid)
+		if !ok {
+			return ""
+		}
+		if shell := ShellFromProcessName(name); shell != "" {
+			return shell
+		}
+		pid = parent
+	}
+	return ""
+}
+
+// SessionEnvStatements genera i comandi che attivano un JDK nella shell indicata.
+//
+// È l'output di 'jenvy use <version> --session', pensato per eval/Invoke-Expression:
+// JAVA_HOME e PATH cambiano solo nella sessione che esegue i comandi, senza registro
+// né privilegi. Per cmd.exe il PATH viene scritto per intero (currentPath) perché
+// in 'for /f ... do %i' un %PATH% letterale non verrebbe espanso; per bash e zsh
+// la directory bin è convertita in stile MSYS (/c/...).
+//
+// Parametri:
+//
+//	shell string       - "cmd", "powershell", "pwsh", "bash" o "zsh"
+//	javaHome string    - Root del JDK da attivare
+//	currentPath string - PATH attuale della shell (usato solo per cmd)
+//
+// Restituisce:
+//
+//	[]string - Un comando per riga
+//	error    - Se la shell non è supportata
+//
+// Esempio di utilizzo:
+//
+//	lines, _ := utils.SessionEnvStatements("powershell", `C:\jdk-21`, "")
+//	// $env:JAVA_HOME = 'C:\jdk-21'
+//	// $env:Path = 'C:\jdk-21\bin;' + $env:Path
+func SessionEnvStatements(shell, javaHome, currentPath string) ([]string, error) {
+	bin := strings.TrimRight(javaHome, `\/`) + `\bin`
+	switch strings.ToLower(shell) {
+	case "cmd":
+		path := bin
+		if currentPath != "" {
+			path += ";" + currentPath
+		}
+		return []string{
+			fmt.Sprintf(`set "JAVA_HOME=%s"`, javaHome),
+			fmt.Sprintf(`set "PATH=%s"`, path),
+		}, nil
+	case "powershell", "pwsh":
+		return []string{
+			fmt.Sprintf("$env:JAVA_HOME = %s", powerShellQuote(javaHome)),
+			fmt.Sprintf("$env:Path = %s + $env:Path", powerShellQuote(bin+";")),
+		}, nil
+	case "bash", "zsh":
+		return []string{
+			fmt.Sprintf("export JAVA_HOME=%s", posixQuote(javaHome)),
+			fmt.Sprintf(`export PATH=%s:"$PATH"`, posixQuote(MSYSPath(bin))),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported shell '%s' (use cmd, powershell, pwsh, bash or zsh)", shell)
+	}
+}
+
+// MSYSPath converte un percorso Windows nella forma usata da Git Bash/MSYS2.
+//
+// Esempio di utilizzo:
+//
+//	MSYSPath(`C:\Users\me\.jenvy\versions\JDK-21\bin`) // → "/c/Users/me/.jenvy/versions/JDK-21/bin"
+func MSYSPath(path string) string {
+	path = strings.ReplaceAll(path, `\`, "/")
+	if len(path) >= 2 && path[1] == ':' {
+		path = "/" + strings.ToLower(path[:1]) + path[2:]
+	}
+	return path
+}
+
+// powerShellQuote racchiude un valore in apici singoli PowerShell.
+func powerShellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// posixQuote racchiude un valore in apici singoli per bash/zsh.
+func posixQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,36 @@
+//go:build !windows
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// processInfo legge PID del padre e nome del comando di un processo da /proc.
+//
+// Sui sistemi senza /proc (es. macOS) restituisce ok=false e DetectParentShell
+// non riconosce alcuna shell.
+func processInfo(pid int) (int, string, bool) {
+	comm, err := os.ReadFile(fmt.Sprintf("/proc/%d/comm", pid))
+	if err != nil {
+		return 0, "", false
+	}
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, "", false
+	}
+
+	// Il formato è "pid (comm) state ppid ...": comm può contenere spazi e parentesi
+	fields := strings.Fields(string(stat[strings.LastIndex(string(stat), ")")+1:]))
+	if len(fields) < 2 {
+		return 0, "", false
+	}
+	parent, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, "", false
+	}
+	return parent, strings.TrimSpace(string(comm)), true
+}
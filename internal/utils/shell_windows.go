@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// processInfo legge PID del padre e nome dell'eseguibile di un processo tramite
+// uno snapshot Toolhelp (CreateToolhelp32Snapshot).
+func processInfo(pid int) (int, string, bool) {
+	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return 0, "", false
+	}
+	defer windows.CloseHandle(snapshot)
+
+	var entry windows.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+	for err = windows.Process32First(snapshot, &entry); err == nil; err = windows.Process32Next(snapshot, &entry) {
+		if int(entry.ProcessID) == pid {
+			return int(entry.ParentProcessID), windows.UTF16ToString(entry.ExeFile[:]), true
+		}
+	}
+	return 0, "", false
+}
@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// Sistemi operativi selezionabili con 'jenvy download --os'.
+const (
+	OSWindows = "windows"
+	OSLinux   = "linux"
+	OSMac     = "mac"
+	OSAlpine  = "alpine" // Linux con musl libc, per le immagini Docker basate su Alpine
+)
+
+// targetOS è il sistema indicato con 'jenvy download --os' (vuoto = HostOS).
+var targetOS string
+
+// osSupport indica per ogni provider i sistemi per cui può essere interrogato con --os.
+//
+// GraalVM non distribuisce build musl; i repository privati non distinguono
+// i sistemi e valgono solo per quello in uso.
+var osSupport = map[string][]string{
+	"adoptium": {OSWindows, OSLinux, OSMac, OSAlpine},
+	"azul":     {OSWindows, OSLinux, OSMac, OSAlpine},
+	"liberica": {OSWindows, OSLinux, OSMac, OSAlpine},
+	"graalvm":  {OSWindows, OSLinux, OSMac},
+}
+
+// ParseOS normalizza il sistema indicato con --os.
+//
+// Accetta anche gli alias più comuni: win, macos/osx/darwin, alpine-linux/musl.
+func ParseOS(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "windows", "win":
+		return OSWindows, nil
+	case "linux":
+		return OSLinux, nil
+	case "mac", "macos", "osx", "darwin":
+		return OSMac, nil
+	case "alpine", "alpine-linux", "musl":
+		return OSAlpine, nil
+	}
+	return "", fmt.Errorf("unsupported operating system '%s' (expected windows, linux, mac or alpine)", value)
+}
+
+// SetTargetOS sceglie il sistema operativo degli archivi richiesti ai provider.
+//
+// Come --arch vale per l'intera esecuzione: i provider leggono TargetOS nel
+// costruire gli URL delle API. Una stringa vuota ripristina il sistema in uso (HostOS).
+func SetTargetOS(os string) {
+	targetOS = os
+}
+
+// TargetOS restituisce il sistema richiesto con --os (HostOS se non indicato).
+func TargetOS() string {
+	if targetOS != "" {
+		return targetOS
+	}
+	return HostOS()
+}
+
+// alpineReleaseFile identifica le distribuzioni Alpine, che usano musl invece di glibc.
+const alpineReleaseFile = "/etc/alpine-release"
+
+// HostOS restituisce il sistema su cui è in esecuzione jenvy, nel formato di --os.
+//
+// Su Linux con musl (Alpine) restituisce OSAlpine: i JDK per glibc non vi si avviano.
+func HostOS() string {
+	switch runtime.GOOS {
+	case "linux":
+		if _, err := os.Stat(alpineReleaseFile); err == nil {
+			return OSAlpine
+		}
+		return OSLinux
+	case "darwin":
+		return OSMac
+	}
+	return OSWindows
+}
+
+// HostOSName restituisce il nome del sistema su cui è in esecuzione jenvy, per i messaggi.
+func HostOSName() string {
+	switch runtime.GOOS {
+	case "windows":
+		return "Windows"
+	case "linux":
+		return "Linux"
+	case "darwin":
+		return "macOS"
+	}
+	return runtime.GOOS
+}
+
+// IsForeignOS indica se il sistema richiesto non è quello su cui gira jenvy (HostOS).
+//
+// Gli archivi per altri sistemi vengono scaricati e verificati ma non estratti
+// né attivati: servono ad altre macchine (es. un'immagine Docker Linux).
+func IsForeignOS(os string) bool {
+	return os != "" && os != HostOS()
+}
+
+// TargetArchiveExtension restituisce il formato degli archivi per il sistema richiesto.
+//
+// Windows usa .zip; Linux e macOS .tar.gz, che conserva permessi e link simbolici.
+func TargetArchiveExtension() string {
+	if TargetOS() == OSWindows {
+		return ".zip"
+	}
+	return ".tar.gz"
+}
+
+// CheckTargetOS verifica che un provider pubblichi archivi per il sistema richiesto.
+//
+// Il sistema in uso è sempre accettato: è quello per cui ogni provider viene
+// interrogato senza --os.
+func CheckTargetOS(provider string) error {
+	target := TargetOS()
+	if target == HostOS() {
+		return nil
+	}
+	for _, supported := range osSupport[provider] {
+		if supported == target {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s does not publish %s builds (try --provider=adoptium, azul or liberica)", provider, target)
+}
+
+// OSSuffix restituisce il suffisso della directory di installazione per un sistema diverso da quello in uso.
+//
+// Vuoto per il sistema in uso; altrimenti "-windows", "-linux", "-mac" o
+// "-alpine", così l'archivio per un'altra piattaforma non occupa la directory
+// del JDK nativo della stessa versione.
+func OSSuffix() string {
+	if !IsForeignOS(TargetOS()) {
+		return ""
+	}
+	return "-" + TargetOS()
+}
+
+// IsForeignInstall indica se una directory di versione contiene un archivio per un altro sistema.
+//
+// Si basa sui metadati scritti da 'jenvy download --os'; le directory senza
+// metadati sono considerate installazioni per il sistema in uso.
+func IsForeignInstall(installDir string) (string, bool) {
+	meta, err := LoadInstallMetadata(installDir)
+	if err != nil || !IsForeignOS(meta.OS) {
+		return "", false
+	}
+	return meta.OS, true
+}
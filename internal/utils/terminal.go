@@ -0,0 +1,15 @@
+package utils
+
+import "os"
+
+// IsTerminal indica se il file è collegato a un terminale interattivo.
+//
+// Restituisce false per file, pipe e redirezioni (es. 'jenvy download 17 > build.log'),
+// dove le animazioni basate su '\r' producono migliaia di righe parziali.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// extraRootCAs sono le radici di sistema più i certificati di --ca-cert o ca-cert (nil = solo sistema).
+var extraRootCAs *x509.CertPool
+
+// ApplyCACertFlag rimuove --ca-cert dagli argomenti e carica i certificati indicati.
+//
+// Come --timeout l'opzione è globale e accetta sia "--ca-cert=<path>" sia
+// "--ca-cert <path>"; senza opzione vale la chiave ca-cert della
+// configurazione. I certificati si aggiungono a quelli di Windows, quindi
+// servono per i proxy con ispezione TLS senza rinunciare alla verifica
+// (l'alternativa sicura a --insecure-skip-verify).
+//
+// Parametri:
+//
+//	args []string - Argomenti del processo (os.Args)
+//
+// Restituisce:
+//
+//	[]string - Argomenti senza l'opzione
+//	error    - Valore mancante o file/directory senza certificati validi
+//
+// Esempio di utilizzo:
+//
+//	args, err := utils.ApplyCACertFlag(os.Args) // jenvy dl 21 --ca-cert C:\certs\corp-root.pem
+func ApplyCACertFlag(args []string) ([]string, error) {
+	path, source := "", "config ca-cert"
+	if cfg, err := LoadConfigOrDefault(); err == nil {
+		path = cfg.CACert
+	}
+
+	options, rest := cutOptions(args)
+	kept := make([]string, 0, len(args))
+	for i := 0; i < len(options); i++ {
+		name, value, hasValue := strings.Cut(options[i], "=")
+		if name != "--ca-cert" {
+			kept = append(kept, options[i])
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(options) {
+				return nil, fmt.Errorf("%s requires a value", name)
+			}
+			i++
+			value = options[i]
+		}
+		path, source = value, name
+	}
+	kept = append(kept, rest...)
+
+	if path == "" {
+		extraRootCAs = nil
+		return kept, nil
+	}
+	pool, err := LoadCACertPool(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	extraRootCAs = pool
+	return kept, nil
+}
+
+// LoadCACertPool restituisce le radici di sistema con in più i certificati di path.
+//
+// path può essere un file o una directory; delle directory vengono letti i
+// file .pem, .crt e .cer. Ogni file può contenere più certificati PEM oppure
+// un singolo certificato DER, il formato dell'esportazione .cer di Windows.
+func LoadCACertPool(path string) (*x509.CertPool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read CA certificates: %w", err)
+	}
+	files := []string{path}
+	if info.IsDir() {
+		files = nil
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read CA certificates: %w", err)
+		}
+		for _, entry := range entries {
+			switch strings.ToLower(filepath.Ext(entry.Name())) {
+			case ".pem", ".crt", ".cer":
+				if !entry.IsDir() {
+					files = append(files, filepath.Join(path, entry.Name()))
+				}
+			}
+		}
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	added := 0
+	for _, file := range files {
+		certs, err := readCertificates(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, cert := range certs {
+			pool.AddCert(cert)
+		}
+		added += len(certs)
+	}
+	if added == 0 {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+// readCertificates legge i certificati PEM o DER di un file.
+func readCertificates(file string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read CA certificates: %w", err)
+	}
+	var certs []*x509.Certificate
+	for rest := data; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		if cert, err := x509.ParseCertificate(data); err == nil {
+			certs = append(certs, cert)
+		}
+	}
+	return certs, nil
+}
+
+// applyRootCAs inserisce nel transport le radici caricate da ApplyCACertFlag.
+func applyRootCAs(transport *http.Transport) {
+	if extraRootCAs == nil {
+		return
+	}
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.RootCAs = extraRootCAs
+}
@@ -9,28 +9,123 @@ import (
 	"github.com/fatih/color"
 )
 
+// tableColumnWidths conserva le larghezze storiche delle colonne di remote-list.
+//
+// Le colonne non elencate si adattano al contenuto. L'ultima colonna non viene
+// mai riempita di spazi: la sua larghezza serve solo per la riga di separazione.
+var tableColumnWidths = map[string]int{"Version": 18, "OS": 10, "Arch": 8, "LTS": 6, "Download": 61}
+
 // Stampa intestazione e tabella con evidenziazione delle righe LTS
+//
+// Accetta qualsiasi sottoinsieme di colonne (vedi SelectColumns): le righe sono
+// evidenziate solo se la colonna "LTS" è presente.
 func PrintTable(data [][]string, headers []string) {
+	widths := make([]int, len(headers))
+	ltsColumn := -1
+	for i, header := range headers {
+		if header == "LTS" {
+			ltsColumn = i
+		}
+		if width, ok := tableColumnWidths[header]; ok {
+			widths[i] = width
+			continue
+		}
+		widths[i] = len(header)
+		for _, row := range data {
+			if i < len(row) && len(row[i]) > widths[i] {
+				widths[i] = len(row[i])
+			}
+		}
+	}
+
+	formatRow := func(cells []string) string {
+		parts := make([]string, len(headers))
+		for i := range headers {
+			cell := ""
+			if i < len(cells) {
+				cell = cells[i]
+			}
+			if i < len(headers)-1 {
+				cell = fmt.Sprintf("%-*s", widths[i], cell)
+			}
+			parts[i] = cell
+		}
+		return strings.Join(parts, " ") + "\n"
+	}
+
 	// Intestazione
-	color.New(color.FgHiYellow, color.Bold).Printf(
-		"%-18s %-10s %-8s %-6s %s\n",
-		headers[0], headers[1], headers[2], headers[3], headers[4])
+	color.New(color.FgHiYellow, color.Bold).Print(formatRow(headers))
 
-	color.New(color.FgHiWhite).Printf(
-		"%-18s %-10s %-8s %-6s %s\n",
-		"──────────────────", "──────────", "────────", "──────", "─────────────────────────────────────────────────────────────")
+	separators := make([]string, len(headers))
+	for i, width := range widths {
+		separators[i] = strings.Repeat("─", width)
+	}
+	color.New(color.FgHiWhite).Print(formatRow(separators))
 
 	// Righe dati
 	for _, row := range data {
-		if row[3] == "YES" {
+		if ltsColumn >= 0 && ltsColumn < len(row) && row[ltsColumn] == "YES" {
 			// Riga evidenziata (versione LTS)
-			color.New(color.FgHiGreen).Printf(
-				"%-18s %-10s %-8s %-6s %s\n", row[0], row[1], row[2], row[3], row[4])
+			color.New(color.FgHiGreen).Print(formatRow(row))
 		} else {
 			// Riga normale
-			fmt.Printf("%-18s %-10s %-8s %-6s %s\n", row[0], row[1], row[2], row[3], row[4])
+			fmt.Print(formatRow(row))
+		}
+	}
+}
+
+// SelectColumns restringe e riordina le colonne di una tabella secondo un elenco di campi.
+//
+// I nomi dei campi non distinguono maiuscole/minuscole e corrispondono alle
+// intestazioni (es. "version,lts" con intestazioni Version/OS/Arch/LTS/Download).
+//
+// Parametri:
+//
+//	rows [][]string  - Righe complete
+//	headers []string - Intestazioni di rows
+//	fields []string  - Campi da mostrare, nell'ordine desiderato (vuoto = tutti)
+//
+// Restituisce:
+//
+//	[][]string - Righe con le sole colonne selezionate
+//	[]string   - Intestazioni corrispondenti
+//	error      - Campo sconosciuto, con l'elenco dei campi disponibili
+func SelectColumns(rows [][]string, headers []string, fields []string) ([][]string, []string, error) {
+	if len(fields) == 0 {
+		return rows, headers, nil
+	}
+
+	indexes := make([]int, 0, len(fields))
+	selected := make([]string, 0, len(fields))
+	for _, field := range fields {
+		index := -1
+		for i, header := range headers {
+			if strings.EqualFold(strings.TrimSpace(field), header) {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			available := make([]string, len(headers))
+			for i, header := range headers {
+				available[i] = strings.ToLower(header)
+			}
+			return nil, nil, fmt.Errorf("unknown field '%s' (available: %s)", field, strings.Join(available, ", "))
+		}
+		indexes = append(indexes, index)
+		selected = append(selected, headers[index])
+	}
+
+	projected := make([][]string, len(rows))
+	for r, row := range rows {
+		projected[r] = make([]string, len(indexes))
+		for c, index := range indexes {
+			if index < len(row) {
+				projected[r][c] = row[index]
+			}
 		}
 	}
+	return projected, selected, nil
 }
 
 // Stampa una singola riga LTS colorata (usata in versioni precedenti)
@@ -62,6 +157,10 @@ func InferPlatform(name string) (string, string) {
 	switch {
 	case strings.Contains(name, "win_x64"):
 		return "windows", "x64"
+	case strings.Contains(name, "win_aarch64"):
+		return "windows", "aarch64"
+	case strings.Contains(name, "win_i686"):
+		return "windows", "x32"
 	case strings.Contains(name, "linux_x64"):
 		return "linux", "x64"
 	case strings.Contains(name, "macos_x64"):
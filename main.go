@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 
 	"jenvy/internal/cmd"
 	"jenvy/internal/utils"
@@ -16,9 +18,88 @@ var (
 )
 
 func main() {
+	err := run()
+	utils.StopLogging(err)
+	os.Exit(utils.ExitCode(err))
+}
+
+// run esegue il comando richiesto e ne restituisce l'esito.
+//
+// main converte l'errore nel codice di uscita (vedi utils.ExitCode): i comandi
+// hanno già mostrato i propri messaggi, quindi qui non viene stampato nulla.
+func run() error {
+	// Comando e opzioni in forma canonica: opzioni in qualsiasi posizione, "--nome valore", forme brevi combinate
+	args, help, err := cmd.NormalizeCommandLine(os.Args)
+	if err != nil {
+		// Un'opzione sconosciuta non viene ignorata: il comando non parte e se ne mostra l'aiuto
+		utils.PrintError(err.Error())
+		fmt.Println()
+		cmd.ShowCommandHelp(args[1])
+		return utils.ExitWith(utils.ExitGeneric, err)
+	}
+	os.Args = args
+	cmd.SetCommandLine(args)
+
+	// --insecure-skip-verify vale per qualsiasi comando, solo per questa esecuzione
+	if args, insecure := utils.StripInsecureFlag(os.Args); insecure {
+		os.Args = args
+		utils.DisableTLSVerification()
+	}
+
+	// --yes/-y (o JENVY_NONINTERACTIVE) risponde alle conferme al posto dell'utente, per script e CI
+	if args, yes := utils.StripYesFlag(os.Args); yes {
+		os.Args = args
+		utils.SetAssumeYes(true)
+	}
+
+	// --no-wait fa fallire subito i comandi che trovano ~/.jenvy in uso da un altro processo jenvy
+	if args, wait := utils.StripNoWaitFlag(os.Args); wait {
+		os.Args = args
+		utils.SetNoWait(true)
+	}
+
+	// --refresh ignora la cache dei metadati dei provider in ~/.jenvy/cache
+	if args, refresh := utils.StripRefreshFlag(os.Args); refresh {
+		os.Args = args
+		utils.SetRefreshCache(true)
+	}
+
+	// --timeout e --retries regolano ogni accesso alla rete (anche via JENVY_TIMEOUT, JENVY_RETRIES)
+	args, err = utils.ApplyNetworkFlags(os.Args)
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, err.Error())
+	}
+	os.Args = args
+
+	// --ca-cert (o ca-cert in configurazione) aggiunge certificati CA attendibili, es. per proxy con ispezione TLS
+	args, err = utils.ApplyCACertFlag(os.Args)
+	if err != nil {
+		return utils.Fail(utils.ExitGeneric, err.Error())
+	}
+	os.Args = args
+
 	if len(os.Args) < 2 {
 		cmd.ShowHelp()
-		return
+		return nil
+	}
+
+	// 'jenvy <comando> --help' mostra solo l'aiuto del comando, senza eseguirlo
+	if help {
+		return cmd.ShowCommandHelp(os.Args[1])
+	}
+
+	// Log diagnostico in ~/.jenvy/logs, escluso 'logs' (che lo legge o lo cancella) e i comandi interni
+	if os.Args[1] != "logs" && !strings.HasPrefix(os.Args[1], "__") {
+		utils.StartLogging(os.Args)
+	}
+
+	// Un solo processo jenvy alla volta modifica ~/.jenvy/versions (es. job CI paralleli sullo stesso agent)
+	if cmd.ModifiesVersions(os.Args) {
+		lock, err := utils.AcquireLock(strings.Join(os.Args[1:], " "))
+		if err != nil {
+			return utils.Fail(utils.ExitGeneric, err.Error())
+		}
+		defer lock.Release()
 	}
 
 	// Provider predefinito centralizzato
@@ -26,28 +107,65 @@ func main() {
 
 	switch os.Args[1] {
 	case "remote-list", "rl":
-		cmd.RemoteList(provider)
+		return cmd.RemoteList(provider)
 
 	case "download", "dl":
-		cmd.DownloadJDK(provider)
+		return cmd.DownloadJDK(provider)
+
+	case "install":
+		return cmd.InstallJDK(provider)
 
 	case "extract", "ex":
-		cmd.ExtractJDK()
+		return cmd.ExtractJDK()
 
 	case "list", "l":
-		cmd.ListInstalledJDKs()
+		return cmd.ListInstalledJDKs()
 
 	case "use", "u":
-		cmd.UseJDK()
+		return cmd.UseJDK()
+
+	case "alias":
+		return cmd.ManageAliases()
+
+	case "default":
+		return cmd.DefaultJDK()
+
+	case "local":
+		return cmd.LocalJDK()
+
+	case "current":
+		return cmd.ShowCurrent()
+
+	case "which":
+		return cmd.WhichJDK()
 
 	case "remove", "rm":
-		cmd.RemoveJDK()
+		return cmd.RemoveJDK()
+
+	case "update", "upgrade":
+		return cmd.UpgradeJDKs()
+
+	case "verify":
+		return cmd.VerifyInstallations()
 
 	case "completion":
-		if len(os.Args) > 2 {
-			switch os.Args[2] {
-			case "install", "--install-all":
-				cmd.InstallCompletionForAllShells()
+		// 'completion --install-all' è la forma storica di 'completion install --all'
+		positional, flags := utils.SplitArgs(os.Args[2:])
+		if len(positional) == 0 && slices.Contains(flags, "--install-all") {
+			positional = []string{"install"}
+		}
+		if len(positional) > 0 || len(flags) > 0 {
+			subcommand := ""
+			if len(positional) > 0 {
+				subcommand = positional[0]
+			}
+			switch subcommand {
+			case "install":
+				return cmd.InstallCompletion()
+			case "status":
+				return cmd.ShowCompletionStatus()
+			case "uninstall":
+				return cmd.UninstallCompletionForAllShells()
 			case "bash":
 				cmd.GenerateCompletion()
 			case "powershell":
@@ -55,42 +173,86 @@ func main() {
 			case "cmd":
 				fmt.Print(cmd.GenerateCmdCompletion())
 			default:
-				fmt.Println("Usage: jenvy completion [install|bash|powershell|cmd]")
-				fmt.Println("  install     - Install completion for all available shells")
+				fmt.Println("Usage: jenvy completion [install|uninstall|status|bash|powershell|cmd]")
+				fmt.Println("  install     - Install completion for detected shells (--shell=<name> or --all)")
+				fmt.Println("  uninstall   - Remove completion from all shell profiles")
+				fmt.Println("  status      - Show where completion is installed")
 				fmt.Println("  bash        - Generate Bash completion script")
 				fmt.Println("  powershell  - Generate PowerShell completion script")
 				fmt.Println("  cmd         - Generate CMD completion script")
+				return utils.ExitWith(utils.ExitGeneric, nil)
 			}
 		} else {
+			cmd.SuggestCompletionVariant()
 			cmd.GenerateCompletion() // Default: Bash
 		}
 
 	case "fix-path", "fp":
-		cmd.FixPath()
+		return cmd.FixPath()
+
+	case "diagnose-path":
+		return cmd.DiagnosePath()
+
+	case "doctor":
+		return cmd.Doctor()
+
+	case "resolve":
+		return cmd.ResolveJava()
+
+	case "logs":
+		return cmd.ShowLogs()
+
+	case "providers":
+		return cmd.ManageProviders()
+
+	case "self-test":
+		return cmd.SelfTest()
+
+	case "__complete-providers":
+		cmd.CompleteProviders()
+
+	case "__complete-aliases":
+		cmd.CompleteAliases()
+
+	case "shell-init":
+		return cmd.ShellInit()
+
+	case "__project-home":
+		cmd.PrintProjectHome()
 
 	case "init":
-		cmd.InitializeJenvyEnvironment()
+		return cmd.InitializeJenvyEnvironment()
+
+	case "uninstall":
+		return cmd.UninstallJenvy()
 
 	case "configure-private", "cp":
-		if len(os.Args) < 3 {
+		args, _ := utils.SplitArgs(os.Args[2:])
+		if len(args) < 1 {
 			utils.PrintUsage("Usage: jenvy configure-private <endpoint> [token]")
 			utils.PrintUsage("Short form: jenvy cp <endpoint> [token]")
-			return
+			return utils.ExitWith(utils.ExitGeneric, nil)
 		}
-		endpoint := os.Args[2]
+		endpoint := args[0]
 		token := ""
-		if len(os.Args) > 3 {
-			token = os.Args[3]
+		if len(args) > 1 {
+			token = args[1]
 		}
-		cmd.ConfigurePrivateRepo(endpoint, token)
+		return cmd.ConfigurePrivateRepo(endpoint, token)
 
 	case "config-show", "cs":
-		cmd.ShowCurrentConfig()
+		return cmd.ShowCurrentConfig()
 
 	case "config-reset", "cr":
-		cmd.ResetPrivateConfig()
+		return cmd.ResetPrivateConfig()
+
+	case "config":
+		return cmd.ManageConfig()
 
 	case "--help", "-h", "help":
+		if len(os.Args) > 2 {
+			return cmd.ShowCommandHelp(os.Args[2])
+		}
 		cmd.ShowHelp()
 
 	case "--version", "-v", "version":
@@ -99,5 +261,7 @@ func main() {
 	default:
 		utils.PrintError(fmt.Sprintf("Unknown command: %s", os.Args[1]))
 		utils.PrintInfo("Use 'jenvy --help' to see all available commands")
+		return utils.ExitWith(utils.ExitGeneric, fmt.Errorf("unknown command: %s", os.Args[1]))
 	}
+	return nil
 }
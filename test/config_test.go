@@ -5,7 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
+
+	"jenvy/internal/utils"
 )
 
 // TestConfigurationHandling testa la gestione della configurazione
@@ -414,3 +419,254 @@ func BenchmarkConfigurationOperations(b *testing.B) {
 		}
 	})
 }
+
+// TestConfigKeys verifica lettura e scrittura delle chiavi gestite da 'jenvy config'
+func TestConfigKeys(t *testing.T) {
+	cfg := &utils.Config{}
+
+	value, err := utils.GetConfigValue(cfg, "naming-scheme")
+	if err != nil || value != utils.DefaultNamingScheme {
+		t.Errorf("Expected default naming scheme, got %q (err: %v)", value, err)
+	}
+
+	if err := utils.SetConfigValue(cfg, "naming-scheme", utils.ProviderNamingScheme); err != nil {
+		t.Fatalf("SetConfigValue() failed: %v", err)
+	}
+	if cfg.NamingScheme != utils.ProviderNamingScheme {
+		t.Errorf("NamingScheme = %q, want %q", cfg.NamingScheme, utils.ProviderNamingScheme)
+	}
+
+	if err := utils.SetConfigValue(cfg, "naming-scheme", "jdk-{provider}"); err == nil {
+		t.Error("Expected error for scheme without {version}")
+	}
+	if cfg.NamingScheme != utils.ProviderNamingScheme {
+		t.Error("Invalid value should not overwrite the current scheme")
+	}
+
+	if err := utils.SetConfigValue(cfg, "naming-scheme", ""); err != nil || cfg.NamingScheme != "" {
+		t.Errorf("Unset should restore the default, got %q (err: %v)", cfg.NamingScheme, err)
+	}
+
+	if _, err := utils.GetConfigValue(cfg, "unknown-key"); err == nil {
+		t.Error("Expected error for unknown configuration key")
+	}
+}
+
+// TestEffectiveConfigSettings verifica l'export JSON della configurazione e il mascheramento
+func TestEffectiveConfigSettings(t *testing.T) {
+	raw := map[string]interface{}{
+		"private_endpoint": "https://repo.example.com",
+		"private_token":    "secret",
+		"password":         "legacy",
+		"custom":           "kept",
+	}
+	cfg := &utils.Config{PrivateEndpoint: "https://repo.example.com", PrivateToken: "secret"}
+
+	masked := utils.EffectiveConfigSettings(raw, cfg, false)
+	if masked["private_token"] != utils.MaskedConfigValue || masked["password"] != utils.MaskedConfigValue {
+		t.Errorf("Secrets should be masked, got token=%v password=%v", masked["private_token"], masked["password"])
+	}
+	if masked["naming_scheme"] != utils.DefaultNamingScheme {
+		t.Errorf("naming_scheme = %v, want effective default %q", masked["naming_scheme"], utils.DefaultNamingScheme)
+	}
+	if masked["custom"] != "kept" {
+		t.Errorf("Unknown keys should be preserved, got %v", masked["custom"])
+	}
+
+	revealed := utils.EffectiveConfigSettings(raw, cfg, true)
+	if revealed["private_token"] != "secret" {
+		t.Errorf("--reveal should show the token, got %v", revealed["private_token"])
+	}
+}
+
+// TestKeepArchivesConfigKey verifica l'impostazione keep-archives (true/false/non impostata)
+func TestKeepArchivesConfigKey(t *testing.T) {
+	cfg := &utils.Config{}
+	if value, _ := utils.GetConfigValue(cfg, "keep-archives"); value != "" {
+		t.Errorf("Unset keep-archives = %q, want empty (ask)", value)
+	}
+
+	if err := utils.SetConfigValue(cfg, "keep-archives", "false"); err != nil {
+		t.Fatalf("SetConfigValue() failed: %v", err)
+	}
+	if cfg.KeepArchives == nil || *cfg.KeepArchives {
+		t.Error("keep-archives=false should be stored as explicit false")
+	}
+
+	if err := utils.SetConfigValue(cfg, "keep-archives", "maybe"); err == nil {
+		t.Error("Expected error for non-boolean value")
+	}
+
+	if err := utils.SetConfigValue(cfg, "keep-archives", ""); err != nil || cfg.KeepArchives != nil {
+		t.Errorf("Unset should restore the interactive default, got %v (err: %v)", cfg.KeepArchives, err)
+	}
+}
+
+// TestActivationStyleConfigKey verifica l'impostazione activation-style (registry/junction)
+func TestActivationStyleConfigKey(t *testing.T) {
+	cfg := &utils.Config{}
+	if value, _ := utils.GetConfigValue(cfg, "activation-style"); value != utils.ActivationStyleRegistry {
+		t.Errorf("Default activation-style = %q, want %q", value, utils.ActivationStyleRegistry)
+	}
+
+	if err := utils.SetConfigValue(cfg, "activation-style", "Junction"); err != nil {
+		t.Fatalf("SetConfigValue() failed: %v", err)
+	}
+	if cfg.ActivationStyle != utils.ActivationStyleJunction {
+		t.Errorf("ActivationStyle = %q, want %q", cfg.ActivationStyle, utils.ActivationStyleJunction)
+	}
+
+	if err := utils.SetConfigValue(cfg, "activation-style", "symlink"); err == nil {
+		t.Error("Expected error for unsupported activation style")
+	}
+
+	if err := utils.SetConfigValue(cfg, "activation-style", ""); err != nil || cfg.ActivationStyle != "" {
+		t.Errorf("Unset should restore the registry default, got %q (err: %v)", cfg.ActivationStyle, err)
+	}
+}
+
+// TestNotifyOnCompleteConfigKey verifica l'impostazione notify-on-complete (disattivata di default)
+func TestNotifyOnCompleteConfigKey(t *testing.T) {
+	cfg := &utils.Config{}
+	if value, _ := utils.GetConfigValue(cfg, "notify-on-complete"); value != "false" {
+		t.Errorf("Default notify-on-complete = %q, want false", value)
+	}
+
+	if err := utils.SetConfigValue(cfg, "notify-on-complete", "true"); err != nil || !cfg.NotifyOnComplete {
+		t.Errorf("SetConfigValue(true) = %v, NotifyOnComplete = %v", err, cfg.NotifyOnComplete)
+	}
+
+	if err := utils.SetConfigValue(cfg, "notify-on-complete", "sometimes"); err == nil {
+		t.Error("Expected error for non-boolean value")
+	}
+
+	if err := utils.SetConfigValue(cfg, "notify-on-complete", ""); err != nil || cfg.NotifyOnComplete {
+		t.Errorf("Unset should disable notifications, got %v (err: %v)", cfg.NotifyOnComplete, err)
+	}
+}
+
+// TestInstallOnUseConfigKey verifica l'impostazione install-on-use (disattivata di default)
+func TestInstallOnUseConfigKey(t *testing.T) {
+	cfg := &utils.Config{}
+	if value, _ := utils.GetConfigValue(cfg, "install-on-use"); value != "false" {
+		t.Errorf("Default install-on-use = %q, want false", value)
+	}
+
+	if err := utils.SetConfigValue(cfg, "install-on-use", "true"); err != nil || !cfg.InstallOnUse {
+		t.Errorf("SetConfigValue(true) = %v, InstallOnUse = %v", err, cfg.InstallOnUse)
+	}
+
+	if err := utils.SetConfigValue(cfg, "install-on-use", "auto"); err == nil {
+		t.Error("Expected error for non-boolean value")
+	}
+}
+
+// TestRotateLogs verifica la conservazione per giorni dei log di ~/.jenvy/logs
+func TestRotateLogs(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.Local)
+	names := []string{
+		"jenvy-2025-03-10.log",
+		"jenvy-2025-03-04.log",
+		"jenvy-2025-03-03.log",
+		"jenvy-2025-03-03.1.log",
+		"notes.txt",
+	}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := utils.RotateLogs(dir, 7, now)
+	if err != nil || removed != 2 {
+		t.Fatalf("RotateLogs() = %d, %v; want 2, nil", removed, err)
+	}
+	files, _ := utils.ListLogFiles(dir)
+	if len(files) != 2 || filepath.Base(files[0]) != "jenvy-2025-03-04.log" {
+		t.Errorf("Remaining logs = %v, want 2025-03-04 and 2025-03-10", files)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.txt")); err != nil {
+		t.Error("Files that are not jenvy logs must not be deleted")
+	}
+
+	cfg := &utils.Config{}
+	if err := utils.SetConfigValue(cfg, "log-retention", "0"); err == nil {
+		t.Error("Expected error for log-retention 0")
+	}
+	if days := utils.LogRetentionDays(cfg); days != utils.DefaultLogRetention {
+		t.Errorf("Default log retention = %d, want %d", days, utils.DefaultLogRetention)
+	}
+}
+
+// TestRedactLogArgs verifica che le credenziali non finiscano nei log
+func TestRedactLogArgs(t *testing.T) {
+	got := utils.RedactLogArgs([]string{"cp", "https://repo.example.com", "secret-token"})
+	if got[1] != "https://repo.example.com" || got[2] != utils.MaskedConfigValue {
+		t.Errorf("RedactLogArgs(cp) = %v", got)
+	}
+	got = utils.RedactLogArgs([]string{"download", "17", "--github-token=abc"})
+	if got[2] != "--github-token="+utils.MaskedConfigValue {
+		t.Errorf("RedactLogArgs(--github-token) = %v", got)
+	}
+}
+
+// TestStripYesFlag verifica che --yes/-y valga per qualsiasi comando e che JENVY_NONINTERACTIVE lo sostituisca
+func TestStripYesFlag(t *testing.T) {
+	args, found := utils.StripYesFlag([]string{"jenvy", "remove", "17", "-y"})
+	if !found || strings.Join(args, " ") != "jenvy remove 17" {
+		t.Errorf("StripYesFlag() = %v, %v; want [jenvy remove 17], true", args, found)
+	}
+	if _, found := utils.StripYesFlag([]string{"jenvy", "list"}); found {
+		t.Error("StripYesFlag() should not report a missing flag")
+	}
+
+	t.Setenv(utils.NonInteractiveEnv, "")
+	if utils.AssumeYes() {
+		t.Error("AssumeYes() should be false without --yes or " + utils.NonInteractiveEnv)
+	}
+	t.Setenv(utils.NonInteractiveEnv, "true")
+	if !utils.AssumeYes() {
+		t.Error("AssumeYes() should honour " + utils.NonInteractiveEnv + "=true")
+	}
+}
+
+// TestCredentialReferences verifica il riconoscimento dei riferimenti a Credential Manager in config.json
+func TestCredentialReferences(t *testing.T) {
+	if !utils.IsCredentialReference(utils.CredentialReferencePrefix + "jenvy/private_token") {
+		t.Error("credman: value should be a credential reference")
+	}
+	if utils.IsCredentialReference("ghp_example") {
+		t.Error("plain token should not be a credential reference")
+	}
+	if value, err := utils.ResolveCredentialReference("plain-token"); err != nil || value != "plain-token" {
+		t.Errorf("ResolveCredentialReference(plain) = %q, %v", value, err)
+	}
+
+	// Fuori da Windows Credential Manager non esiste: i segreti restano nel file
+	// e un riferimento non risolvibile equivale a un segreto non configurato.
+	// Su Windows la verifica scriverebbe nel Credential Manager dell'utente.
+	if runtime.GOOS == "windows" {
+		return
+	}
+	home := t.TempDir()
+	t.Setenv(utils.JenvyHomeEnv, home)
+	config := `{"private_endpoint":"https://repo.corp/jdk","private_token":"credman:jenvy/private_token","github_token":"ghp_example"}`
+	if err := os.WriteFile(filepath.Join(home, "config.json"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := utils.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.GitHubToken != "ghp_example" || cfg.PrivateToken != "" {
+		t.Errorf("tokens = %q, %q; want ghp_example and empty", cfg.GitHubToken, cfg.PrivateToken)
+	}
+	if err := utils.SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(filepath.Join(home, "config.json"))
+	if !strings.Contains(string(data), `"github_token": "ghp_example"`) {
+		t.Errorf("without Credential Manager the token should stay in config.json:\n%s", data)
+	}
+}
@@ -0,0 +1,237 @@
+package test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/rand"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"jenvy/internal/cmd"
+	"jenvy/internal/utils"
+)
+
+// tarEntry è una voce dell'archivio costruito da writeTestTarGz.
+type tarEntry struct {
+	Name     string
+	Type     byte
+	Linkname string
+	Content  []byte
+}
+
+// writeTestTarGz scrive in path un tar.gz con le voci indicate, nell'ordine dato.
+func writeTestTarGz(t *testing.T, path string, entries []tarEntry) {
+	t.Helper()
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gzw := gzip.NewWriter(file)
+	tw := tar.NewWriter(gzw)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.Name, Typeflag: entry.Type, Linkname: entry.Linkname, Mode: 0755, Size: int64(len(entry.Content))}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(entry.Content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// extractInstalledArchive esegue 'jenvy extract <dirName> --yes' su un JENVY_HOME temporaneo.
+func extractInstalledArchive(t *testing.T, dirName string) error {
+	t.Helper()
+	args := os.Args
+	defer func() { os.Args = args }()
+	os.Args = []string{"jenvy", "extract", dirName, "--yes"}
+	return cmd.ExtractJDK()
+}
+
+// TestExtractRejectsChainedSymlinkEscape verifica che link concatenati non portino file esterni nel JDK.
+//
+// Ogni link, preso da solo, punta dentro la directory di estrazione; seguiti
+// sul disco, "s -> ." e i successivi ".." risalgono fino a JENVY_HOME, e il
+// link fisico finale copierebbe secret.txt nell'installazione.
+func TestExtractRejectsChainedSymlinkEscape(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symbolic links requires a privilege on Windows")
+	}
+	home := t.TempDir()
+	t.Setenv(utils.JenvyHomeEnv, home)
+	const secret = "host secret"
+	if err := os.WriteFile(filepath.Join(home, "secret.txt"), []byte(secret), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	installDir := filepath.Join(home, "versions", "JDK-17.0.1")
+	if err := os.MkdirAll(installDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Oltre 1 MB, perché l'archivio venga riconosciuto come JDK da estrarre
+	padding := make([]byte, 2<<20)
+	rand.Read(padding)
+	writeTestTarGz(t, filepath.Join(installDir, "jdk-17.0.1.tar.gz"), []tarEntry{
+		{Name: "jdk/bin/java", Type: tar.TypeReg, Content: []byte("#!/bin/sh\n")},
+		{Name: "jdk/lib/modules", Type: tar.TypeReg, Content: padding},
+		{Name: "jdk/lib/server/libjvm.so", Type: tar.TypeReg, Content: []byte("ELF")},
+		{Name: "jdk/lib/libjvm.so", Type: tar.TypeSymlink, Linkname: "server/libjvm.so"},
+		{Name: "jdk/bin/javac", Type: tar.TypeLink, Linkname: "jdk/bin/java"},
+		{Name: "jdk/s", Type: tar.TypeSymlink, Linkname: "."},
+		{Name: "jdk/s/x", Type: tar.TypeSymlink, Linkname: ".."},
+		{Name: "jdk/s/x/y", Type: tar.TypeSymlink, Linkname: ".."},
+		{Name: "jdk/s/x/y/z", Type: tar.TypeSymlink, Linkname: ".."},
+		{Name: "jdk/h", Type: tar.TypeLink, Linkname: "jdk/s/x/y/z/secret.txt"},
+	})
+
+	if err := extractInstalledArchive(t, "JDK-17.0.1"); err != nil {
+		t.Fatalf("Extraction failed: %v", err)
+	}
+
+	// I link interni all'installazione vengono creati normalmente
+	if data, err := os.ReadFile(filepath.Join(installDir, "lib", "libjvm.so")); err != nil || string(data) != "ELF" {
+		t.Errorf("Internal symlink lib/libjvm.so not created: %q, %v", data, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(installDir, "bin", "javac")); err != nil || string(data) != "#!/bin/sh\n" {
+		t.Errorf("Internal hard link bin/javac not created: %q, %v", data, err)
+	}
+
+	root, err := filepath.EvalSymlinks(installDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filepath.WalkDir(installDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			if resolved, err := filepath.EvalSymlinks(path); err == nil && resolved != root && !strings.HasPrefix(resolved, root+string(os.PathSeparator)) {
+				t.Errorf("Symlink %s points outside the installation: %s", path, resolved)
+			}
+			return nil
+		}
+		if data, err := os.ReadFile(path); err == nil && string(data) == secret {
+			t.Errorf("A file outside the installation was copied into %s", path)
+		}
+		return nil
+	})
+}
+
+// TestExtractReplacesPreviousInstallation verifica che una nuova estrazione sostituisca per intero quella precedente.
+//
+// I file del JDK precedente spariscono, mentre archivio e metadati restano
+// nella directory di installazione; nessuna directory temporanea rimane.
+func TestExtractReplacesPreviousInstallation(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(utils.JenvyHomeEnv, home)
+	// Senza keep-archives l'archivio verrebbe eliminato dopo l'estrazione
+	cfg, err := utils.LoadConfigOrDefault()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keep := true
+	cfg.KeepArchives = &keep
+	if err := utils.SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	installDir := filepath.Join(home, "versions", "JDK-17.0.1")
+	if err := os.MkdirAll(filepath.Join(installDir, "lib"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(installDir, "lib", "stale.jar"), []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	meta := &utils.InstallMetadata{Provider: "adoptium", Version: "17.0.1"}
+	if err := utils.SaveInstallMetadata(installDir, meta); err != nil {
+		t.Fatal(err)
+	}
+	padding := make([]byte, 2<<20)
+	rand.Read(padding)
+	archive := filepath.Join(installDir, "jdk-17.0.1.tar.gz")
+	writeTestTarGz(t, archive, []tarEntry{
+		{Name: "jdk/bin/java", Type: tar.TypeReg, Content: []byte("#!/bin/sh\n")},
+		{Name: "jdk/lib/modules", Type: tar.TypeReg, Content: padding},
+	})
+
+	if err := extractInstalledArchive(t, "JDK-17.0.1"); err != nil {
+		t.Fatalf("Extraction failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(installDir, "lib", "stale.jar")); !os.IsNotExist(err) {
+		t.Errorf("A file of the previous installation survived the extraction: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(installDir, "bin", "java")); err != nil {
+		t.Errorf("The new JDK is missing: %v", err)
+	}
+	if _, err := os.Stat(archive); err != nil {
+		t.Errorf("The archive was not kept: %v", err)
+	}
+	if got, err := utils.LoadInstallMetadata(installDir); err != nil || got.Provider != "adoptium" {
+		t.Errorf("The metadata were not kept: %+v, %v", got, err)
+	}
+	if leftovers, _ := os.ReadDir(filepath.Join(home, "tmp")); len(leftovers) > 0 {
+		t.Errorf("Temporary directories left behind: %v", leftovers)
+	}
+}
+
+// TestStreamChecksumMismatchLeavesNothing verifica che 'download --stream' con un checksum errato non lasci file.
+//
+// L'archivio viene estratto per intero prima che il checksum possa essere
+// confrontato: né la directory temporanea né quella di installazione devono restare.
+func TestStreamChecksumMismatchLeavesNothing(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(utils.JenvyHomeEnv, home)
+
+	archive := filepath.Join(t.TempDir(), "jdk-17.0.1.tar.gz")
+	writeTestTarGz(t, archive, []tarEntry{
+		{Name: "jdk/bin/java", Type: tar.TypeReg, Content: []byte("#!/bin/sh\n")},
+		{Name: "jdk/lib/modules", Type: tar.TypeReg, Content: []byte("modules")},
+	})
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc("/releases.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `[{"version": "17.0.1", "download": "%s/jdk-17.0.1.tar.gz", "checksum": "%s"}]`, server.URL, strings.Repeat("0", 64))
+	})
+	mux.HandleFunc("/jdk-17.0.1.tar.gz", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, archive)
+	})
+
+	cfg, err := utils.LoadConfigOrDefault()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := utils.SetConfigValue(cfg, "private-endpoint", server.URL+"/releases.json"); err != nil {
+		t.Fatal(err)
+	}
+	if err := utils.SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	args := os.Args
+	defer func() { os.Args = args }()
+	os.Args = []string{"jenvy", "download", "17.0.1", "--provider=private", "--stream", "--yes"}
+	if err := cmd.DownloadJDK("private"); err == nil {
+		t.Fatal("DownloadJDK should fail on a checksum mismatch")
+	}
+
+	for _, dir := range []string{filepath.Join(home, "versions"), filepath.Join(home, "tmp")} {
+		if entries, _ := os.ReadDir(dir); len(entries) > 0 {
+			t.Errorf("%s is not empty after a checksum mismatch: %v", dir, entries)
+		}
+	}
+}
@@ -1,9 +1,20 @@
 package test
 
 import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"jenvy/internal/providers/adoptium"
+	"jenvy/internal/providers/liberica"
+	"jenvy/internal/providers/private"
+	"jenvy/internal/utils"
 )
 
 // TestAdoptiumVersionParsing testa il parsing delle versioni Adoptium
@@ -45,14 +56,16 @@ func TestAdoptiumResponse(t *testing.T) {
 			OS      string `json:"os"`
 			Arch    string `json:"architecture"`
 			Package struct {
-				Link string `json:"link"`
+				Link     string `json:"link"`
+				Checksum string `json:"checksum"`
 			} `json:"package"`
 		}{
 			{
 				OS:   "windows",
 				Arch: "x64",
 				Package: struct {
-					Link string `json:"link"`
+					Link     string `json:"link"`
+					Checksum string `json:"checksum"`
 				}{
 					Link: "https://github.com/adoptium/temurin17-binaries/releases/download/jdk-17.0.8.1%2B1/OpenJDK17U-jdk_x64_windows_hotspot_17.0.8.1_1.zip",
 				},
@@ -123,14 +136,16 @@ func MockAdoptiumResponse(version string, isLTS bool) adoptium.AdoptiumResponse
 			OS      string `json:"os"`
 			Arch    string `json:"architecture"`
 			Package struct {
-				Link string `json:"link"`
+				Link     string `json:"link"`
+				Checksum string `json:"checksum"`
 			} `json:"package"`
 		}{
 			{
 				OS:   "windows",
 				Arch: "x64",
 				Package: struct {
-					Link string `json:"link"`
+					Link     string `json:"link"`
+					Checksum string `json:"checksum"`
 				}{
 					Link: "https://mock.adoptium.net/" + version + ".zip",
 				},
@@ -253,3 +268,529 @@ func TestAdoptiumErrorHandling(t *testing.T) {
 		})
 	}
 }
+
+// TestProbeEndpoint verifica il controllo di raggiungibilità usato da 'jenvy providers status'
+func TestProbeEndpoint(t *testing.T) {
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed) // Forza il fallback a GET
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	result := utils.ProbeEndpoint(server.URL, "secret", 5*time.Second)
+	if !result.Reachable() || result.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("ProbeEndpoint() = %+v, want reachable with status 503", result)
+	}
+	if gotAuth != "Bearer secret" {
+		t.Errorf("Authorization header = %q, want Bearer token", gotAuth)
+	}
+
+	server.Close()
+	if result := utils.ProbeEndpoint(server.URL, "", 5*time.Second); result.Reachable() {
+		t.Error("Closed server should be unreachable")
+	}
+}
+
+// TestRemoteContentLength verifica la lettura della dimensione di un archivio con HEAD, seguendo i redirect
+func TestRemoteContentLength(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/release.zip":
+			http.Redirect(w, r, "/cdn/release.zip", http.StatusFound)
+		case "/cdn/release.zip":
+			if r.Method != http.MethodHead {
+				t.Errorf("Method = %s, want HEAD", r.Method)
+			}
+			w.Header().Set("Content-Length", "195000000")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	length, err := utils.RemoteContentLength(server.URL+"/release.zip", 5*time.Second)
+	if err != nil || length != 195000000 {
+		t.Errorf("RemoteContentLength() = %d, %v; want 195000000", length, err)
+	}
+
+	if _, err := utils.RemoteContentLength(server.URL+"/missing.zip", 5*time.Second); err == nil {
+		t.Error("Expected error for 404 response")
+	}
+}
+
+// TestGitHubTokenAuthorization verifica che il token GitHub venga inviato solo agli host GitHub
+func TestGitHubTokenAuthorization(t *testing.T) {
+	t.Setenv(utils.GitHubTokenEnv, "ghp_example")
+
+	token, source := utils.GitHubToken()
+	if token != "ghp_example" || source != utils.GitHubTokenEnv {
+		t.Errorf("GitHubToken() = %q, %q; want token from %s", token, source, utils.GitHubTokenEnv)
+	}
+
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{"https://github.com/adoptium/temurin17-binaries/releases/download/jdk-17/OpenJDK17.zip", "Bearer ghp_example"},
+		{"https://objects.githubusercontent.com/github-production-release-asset/123", "Bearer ghp_example"},
+		{"https://api.github.com/repos/graalvm/graalvm-ce-builds/releases", "Bearer ghp_example"},
+		{"https://api.adoptium.net/v3/info/available_releases", ""},
+		{"https://github.com.evil.example/asset.zip", ""},
+	}
+
+	for _, tc := range tests {
+		req, err := http.NewRequest(http.MethodGet, tc.url, nil)
+		if err != nil {
+			t.Fatalf("NewRequest(%q) failed: %v", tc.url, err)
+		}
+		utils.AuthorizeGitHubRequest(req)
+		if got := req.Header.Get("Authorization"); got != tc.expected {
+			t.Errorf("Authorization for %s = %q, want %q", tc.url, got, tc.expected)
+		}
+	}
+
+	if !utils.IsSecretConfigKey("github-token") {
+		t.Error("github-token should be masked like private-token")
+	}
+}
+
+// TestCheckUsableReleases verifica che un elenco vuoto o senza versioni venga segnalato come cambio di schema
+func TestCheckUsableReleases(t *testing.T) {
+	if err := utils.CheckUsableReleases(12, 3); err != nil {
+		t.Errorf("Usable releases should not be reported, got %v", err)
+	}
+	for _, counts := range [][2]int{{0, 0}, {25, 0}} {
+		if err := utils.CheckUsableReleases(counts[0], counts[1]); !errors.Is(err, utils.ErrNoUsableReleases) {
+			t.Errorf("CheckUsableReleases(%d, %d) = %v, want ErrNoUsableReleases", counts[0], counts[1], err)
+		}
+	}
+}
+
+// TestCachedGet verifica che i metadati dei provider vengano serviti da ~/.jenvy/cache entro il TTL
+func TestCachedGet(t *testing.T) {
+	t.Setenv(utils.JenvyHomeEnv, t.TempDir())
+	t.Cleanup(func() { utils.SetRefreshCache(false) })
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/error" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		w.Write([]byte(`{"result":[]}`))
+	}))
+	defer server.Close()
+
+	for i := 0; i < 2; i++ {
+		body, status, err := utils.CachedGet(server.URL + "/packages")
+		if err != nil || status != http.StatusOK || string(body) != `{"result":[]}` {
+			t.Fatalf("CachedGet() = %q, %d, %v", body, status, err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Second call should be served from the cache, got %d requests", requests)
+	}
+
+	utils.SetRefreshCache(true)
+	utils.CachedGet(server.URL + "/packages")
+	if requests != 2 {
+		t.Errorf("--refresh should bypass the cache, got %d requests", requests)
+	}
+	utils.SetRefreshCache(false)
+
+	for i := 0; i < 2; i++ {
+		if _, status, _ := utils.CachedGet(server.URL + "/error"); status != http.StatusServiceUnavailable {
+			t.Errorf("Status = %d, want 503", status)
+		}
+	}
+	if requests != 4 {
+		t.Errorf("Error responses must not be cached, got %d requests", requests)
+	}
+
+	for _, value := range []string{"0", "30m", "6h"} {
+		if _, err := utils.ParseCacheTTL(value); err != nil {
+			t.Errorf("ParseCacheTTL(%q) error: %v", value, err)
+		}
+	}
+	for _, value := range []string{"1h-", "-5m", "soon"} {
+		if _, err := utils.ParseCacheTTL(value); err == nil {
+			t.Errorf("ParseCacheTTL(%q) should fail", value)
+		}
+	}
+}
+
+// TestApplyCACertFlag verifica che --ca-cert renda attendibile il certificato di un server HTTPS
+func TestApplyCACertFlag(t *testing.T) {
+	t.Setenv(utils.JenvyHomeEnv, t.TempDir())
+	t.Cleanup(func() { utils.ApplyCACertFlag([]string{"jenvy"}) })
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	if _, err := utils.NewHTTPClient(5 * time.Second).Get(server.URL); err == nil {
+		t.Fatal("Self-signed certificate should not be trusted by default")
+	}
+
+	// La directory contiene il certificato in DER, come un export .cer di Windows
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "proxy-root.cer"), server.Certificate().Raw, 0644); err != nil {
+		t.Fatal(err)
+	}
+	args, err := utils.ApplyCACertFlag([]string{"jenvy", "rl", "--ca-cert", dir, "--all"})
+	if err != nil {
+		t.Fatalf("ApplyCACertFlag() error: %v", err)
+	}
+	if strings.Join(args, " ") != "jenvy rl --all" {
+		t.Errorf("--ca-cert should be stripped, got %v", args)
+	}
+	resp, err := utils.NewHTTPClient(5 * time.Second).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Certificate from --ca-cert should be trusted: %v", err)
+	}
+	resp.Body.Close()
+
+	empty := filepath.Join(dir, "empty.pem")
+	os.WriteFile(empty, []byte("not a certificate"), 0644)
+	if _, err := utils.ApplyCACertFlag([]string{"jenvy", "--ca-cert=" + empty}); err == nil {
+		t.Error("A file without certificates should be rejected")
+	}
+}
+
+// TestPrivateRepositorySearch verifica l'elenco dei JDK da Artifactory (AQL) e Nexus 3 (REST)
+func TestPrivateRepositorySearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/artifactory/api/search/aql":
+			if user, password, ok := r.BasicAuth(); !ok || user != "ci" || password != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"results":[
+				{"repo":"jdk","path":"temurin","name":"OpenJDK17U-jdk_x64_windows_hotspot_17.0.9_9.zip","created":"2023-10-20T10:00:00.000Z","sha256":"abc"},
+				{"repo":"jdk","path":"temurin","name":"OpenJDK17U-jdk_x64_linux_hotspot_17.0.9_9.zip"},
+				{"repo":"jdk","path":"com/corp/jdk/21.0.1","name":"jdk-windows-aarch64.zip"}]}`))
+		case r.URL.Path == "/service/rest/v1/search/assets" && r.URL.Query().Get("continuationToken") == "":
+			w.Write([]byte(`{"items":[{"downloadUrl":"` + "http://" + r.Host + `/repository/jdk/bellsoft-jdk21.0.1+12-windows-amd64.zip","path":"bellsoft-jdk21.0.1+12-windows-amd64.zip","checksum":{"sha256":"def"}}],"continuationToken":"next"}`))
+		case r.URL.Path == "/service/rest/v1/search/assets":
+			w.Write([]byte(`{"items":[{"downloadUrl":"x","path":"bellsoft-jdk21.0.1+12-windows-amd64.zip.sha256"}],"continuationToken":null}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv(utils.JenvyHomeEnv, t.TempDir())
+	cfg := &utils.Config{PrivateEndpoint: server.URL + "/artifactory/", PrivateToken: "ci:secret", PrivateType: utils.PrivateTypeArtifactory, PrivateRepository: "jdk"}
+	if err := utils.SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	releases, err := private.GetPrivateJDKs()
+	if err != nil {
+		t.Fatalf("Artifactory search error: %v", err)
+	}
+	if len(releases) != 2 {
+		t.Fatalf("Got %d releases, want 2 (Linux archive skipped): %+v", len(releases), releases)
+	}
+	if releases[0].Version != "17.0.9+9" || releases[0].Checksum != "abc" || releases[0].DownloadURL != server.URL+"/artifactory/jdk/temurin/OpenJDK17U-jdk_x64_windows_hotspot_17.0.9_9.zip" {
+		t.Errorf("Unexpected raw layout release: %+v", releases[0])
+	}
+	if releases[1].Version != "21.0.1" || releases[1].Arch != utils.ArchAArch64 || !releases[1].LTS {
+		t.Errorf("Maven layout release should take the version from its directory: %+v", releases[1])
+	}
+
+	cfg.PrivateEndpoint, cfg.PrivateType = server.URL, utils.PrivateTypeNexus
+	if err := utils.SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	releases, err = private.GetPrivateJDKs()
+	if err != nil {
+		t.Fatalf("Nexus search error: %v", err)
+	}
+	if len(releases) != 1 || releases[0].Version != "21.0.1+12" || releases[0].Checksum != "def" {
+		t.Errorf("Unexpected Nexus releases: %+v", releases)
+	}
+}
+
+// TestPrivateListingBackends verifica l'elenco dei JDK da un bucket S3 e da un indice di directory HTTP
+func TestPrivateListingBackends(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/bucket/" && r.URL.Query().Get("continuation-token") == "":
+			if r.URL.Query().Get("prefix") != "jdk/" {
+				t.Errorf("prefix = %q, want jdk/", r.URL.Query().Get("prefix"))
+			}
+			w.Write([]byte(`<ListBucketResult><Contents><Key>jdk/corp-jdk-17.0.9+9-windows-x64.zip</Key><LastModified>2023-10-20T10:00:00.000Z</LastModified></Contents>
+				<Contents><Key>jdk/corp-jdk-17.0.9+9-linux-x64.zip</Key></Contents><IsTruncated>true</IsTruncated><NextContinuationToken>p2</NextContinuationToken></ListBucketResult>`))
+		case r.URL.Path == "/bucket/":
+			w.Write([]byte(`<ListBucketResult><Contents><Key>jdk/corp-jdk-21.0.1-windows-aarch64.zip</Key></Contents><IsTruncated>false</IsTruncated></ListBucketResult>`))
+		case r.URL.Path == "/files/jdk/":
+			w.Write([]byte(`<html><body><a href="../">../</a><a href="corp-jdk-21.0.1%2B12-windows-x64.zip">corp-jdk-21.0.1+12-windows-x64.zip</a>
+				<a href="corp-jdk-21.0.1%2B12-windows-x64.zip.sha256">checksum</a><a href='/mirror/corp-jdk-11.0.21-win-x86.zip'>11</a></body></html>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv(utils.JenvyHomeEnv, t.TempDir())
+	cfg := &utils.Config{PrivateEndpoint: server.URL + "/bucket", PrivateType: utils.PrivateTypeS3, PrivateRepository: "jdk/", PrivatePattern: "corp-jdk-{version}-{os}-{arch}.zip"}
+	if err := utils.SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	releases, err := private.GetPrivateJDKs()
+	if err != nil {
+		t.Fatalf("S3 listing error: %v", err)
+	}
+	if len(releases) != 2 || releases[0].Version != "17.0.9+9" || releases[1].Arch != utils.ArchAArch64 {
+		t.Fatalf("Unexpected S3 releases: %+v", releases)
+	}
+	if releases[0].DownloadURL != server.URL+"/bucket/jdk/corp-jdk-17.0.9%2B9-windows-x64.zip" {
+		t.Errorf("DownloadURL = %q", releases[0].DownloadURL)
+	}
+
+	cfg.PrivateEndpoint, cfg.PrivateType = server.URL+"/files/jdk", utils.PrivateTypeDirectory
+	if err := utils.SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	releases, err = private.GetPrivateJDKs()
+	if err != nil {
+		t.Fatalf("Directory listing error: %v", err)
+	}
+	if len(releases) != 2 || releases[0].Version != "21.0.1+12" || releases[1].Arch != utils.ArchX32 {
+		t.Fatalf("Unexpected directory releases: %+v", releases)
+	}
+	if releases[1].DownloadURL != server.URL+"/mirror/corp-jdk-11.0.21-win-x86.zip" {
+		t.Errorf("Absolute links should be resolved against the server, got %q", releases[1].DownloadURL)
+	}
+
+	if err := utils.SetConfigValue(cfg, "private-pattern", "jdk-{os}.zip"); err == nil {
+		t.Error("A pattern without {version} should be rejected")
+	}
+}
+
+// TestPrivateGitHubReleases verifica l'elenco degli asset delle release GitHub (private-type github)
+func TestPrivateGitHubReleases(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer ghe-token" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		switch {
+		case r.URL.Path == "/api/v3/repos/platform/jdk-builds/releases" && r.URL.Query().Get("page") == "":
+			w.Header().Set("Link", `<`+server.URL+`/api/v3/repos/platform/jdk-builds/releases?per_page=100&page=2>; rel="next"`)
+			w.Write([]byte(`[{"tag_name":"jdk-21.0.1+12","published_at":"2023-10-20T10:00:00Z","assets":[
+				{"name":"OpenJDK21U-jdk_x64_windows_hotspot_21.0.1_12.zip","url":"` + server.URL + `/api/v3/repos/platform/jdk-builds/releases/assets/1","digest":"sha256:abc"},
+				{"name":"OpenJDK21U-jre_x64_windows_hotspot_21.0.1_12.zip","url":"` + server.URL + `/api/v3/repos/platform/jdk-builds/releases/assets/2"},
+				{"name":"OpenJDK21U-jdk_x64_linux_hotspot_21.0.1_12.tar.gz","url":"` + server.URL + `/api/v3/repos/platform/jdk-builds/releases/assets/3"}]},
+				{"tag_name":"jdk-22+36-ea","prerelease":true,"assets":[{"name":"OpenJDK22U-jdk_x64_windows_hotspot_ea.zip"}]}]`))
+		case r.URL.Path == "/api/v3/repos/platform/jdk-builds/releases":
+			w.Write([]byte(`[{"tag_name":"jdk-17.0.9+9","assets":[{"name":"OpenJDK17U-jdk_x64_windows_hotspot_17.0.9_9.zip","url":"` + server.URL + `/api/v3/repos/platform/jdk-builds/releases/assets/4"}]}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv(utils.JenvyHomeEnv, t.TempDir())
+	cfg := &utils.Config{PrivateEndpoint: server.URL + "/platform/jdk-builds", PrivateToken: "ghe-token", PrivateType: utils.PrivateTypeGitHub}
+	if err := utils.SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	releases, err := private.GetPrivateJDKs()
+	if err != nil {
+		t.Fatalf("GitHub listing error: %v", err)
+	}
+	if len(releases) != 2 || releases[0].Version != "21.0.1+12" || releases[1].Version != "17.0.9+9" {
+		t.Fatalf("Unexpected GitHub releases: %+v", releases)
+	}
+	if releases[0].Checksum != "abc" || releases[0].DownloadURL != server.URL+"/api/v3/repos/platform/jdk-builds/releases/assets/1" {
+		t.Errorf("Unexpected asset: %+v", releases[0])
+	}
+
+	// I download degli asset API richiedono il contenuto binario, non i metadati
+	req := httptest.NewRequest(http.MethodGet, releases[0].DownloadURL, nil)
+	utils.AuthorizePrivateRequest(req)
+	if req.Header.Get("Accept") != "application/octet-stream" || req.Header.Get("Authorization") != "Bearer ghe-token" {
+		t.Errorf("Asset request headers = %v", req.Header)
+	}
+
+	repo, _ := url.Parse("https://github.com/adoptium/temurin21-binaries")
+	if base := utils.GitHubAPIBaseFor(repo); base != utils.GitHubAPIBase {
+		t.Errorf("GitHubAPIBaseFor(github.com) = %q", base)
+	}
+}
+
+// TestAdoptiumEarlyAccess verifica che --ea aggiunga le build EA delle major non ancora rilasciate
+func TestAdoptiumEarlyAccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/info/available_releases":
+			w.Write([]byte(`{"available_releases":[24],"most_recent_feature_release":24,"tip_version":26}`))
+		case "/v3/assets/feature_releases/24/ga":
+			w.Write([]byte(`[{"binaries":[{"os":"windows","architecture":"x64","package":{"link":"https://example.com/24.zip"}}],"version_data":{"openjdk_version":"24.0.1+9"},"release_type":"ga"}]`))
+		case "/v3/assets/feature_releases/25/ea":
+			w.Write([]byte(`[{"binaries":[{"os":"windows","architecture":"x64","package":{"link":"https://example.com/25-ea.zip"}}],"version_data":{"openjdk_version":"25-beta+27-202506110009"}}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound) // Nessuna build EA per 26
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv(utils.JenvyHomeEnv, t.TempDir())
+	if err := utils.SaveConfig(&utils.Config{AdoptiumAPIMirror: server.URL}); err != nil {
+		t.Fatal(err)
+	}
+
+	releases, err := adoptium.GetAllJDKs()
+	if err != nil {
+		t.Fatalf("GetAllJDKs error: %v", err)
+	}
+	if len(releases) != 1 {
+		t.Fatalf("Without --ea only GA releases expected, got %d", len(releases))
+	}
+
+	utils.SetIncludeEarlyAccess(true)
+	defer utils.SetIncludeEarlyAccess(false)
+	releases, err = adoptium.GetAllJDKs()
+	if err != nil {
+		t.Fatalf("GetAllJDKs --ea error: %v", err)
+	}
+	if len(releases) != 2 || releases[0].IsEarlyAccess() || !releases[1].IsEarlyAccess() || releases[1].Major != 25 {
+		t.Fatalf("Unexpected releases with --ea: %+v", releases)
+	}
+}
+
+// TestImageVariant verifica la scelta di JRE e pacchetti JavaFX (--type, --javafx)
+func TestImageVariant(t *testing.T) {
+	defer utils.SetImageVariant(utils.ImageTypeJDK, false)
+
+	if _, err := utils.ParseImageType("jdk-full"); err == nil {
+		t.Error("--type accepts only jdk or jre")
+	}
+	if utils.CheckImageVariant("graalvm") != nil || utils.ImageVariantSuffix() != "" {
+		t.Error("The default JDK variant should be supported by every provider")
+	}
+
+	utils.SetImageVariant(utils.ImageTypeJRE, true)
+	if utils.CheckImageVariant("adoptium") == nil {
+		t.Error("Adoptium does not publish JavaFX bundles")
+	}
+	if utils.CheckImageVariant("liberica") != nil || utils.ImageVariantSuffix() != "-jre-fx" {
+		t.Errorf("Liberica should support JRE Full, suffix = %q", utils.ImageVariantSuffix())
+	}
+	// Ogni percorso di 'download' installa la variante in una directory propria
+	if name := utils.VariantInstallDirName("JDK-{version}", "liberica", "21.0.5+11"); name != "JDK-21.0.5+11-jre-fx" {
+		t.Errorf("VariantInstallDirName = %q, want JDK-21.0.5+11-jre-fx", name)
+	}
+
+	bundle := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bundle = r.URL.Query().Get("bundle-type")
+		w.Write([]byte(`[{"version":"21.0.5+11","downloadUrl":"https://example.com/bellsoft-jre21.0.5+11-windows-amd64-full.zip"}]`))
+	}))
+	defer server.Close()
+	t.Setenv(utils.JenvyHomeEnv, t.TempDir())
+	if err := utils.SaveConfig(&utils.Config{LibericaAPIMirror: server.URL}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := liberica.GetLibericaJDKs(); err != nil {
+		t.Fatalf("GetLibericaJDKs error: %v", err)
+	}
+	if bundle != "jre-full" {
+		t.Errorf("bundle-type = %q, want jre-full", bundle)
+	}
+}
+
+// TestTargetArch verifica che --arch venga passata alle API dei provider e separi la directory di installazione
+func TestTargetArch(t *testing.T) {
+	defer utils.SetTargetArch("")
+
+	if utils.ArchRequested() || utils.TargetArch() != utils.NativeArch() || utils.ArchSuffix() != "" {
+		t.Error("Without --arch the native architecture should be used")
+	}
+
+	utils.SetTargetArch(utils.ArchAArch64)
+	if utils.NativeArch() != utils.ArchAArch64 && utils.ArchSuffix() != "-aarch64" {
+		t.Errorf("ArchSuffix() = %q, want -aarch64", utils.ArchSuffix())
+	}
+
+	var bitness, arch string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bitness, arch = r.URL.Query().Get("bitness"), r.URL.Query().Get("arch")
+		w.Write([]byte(`[{"version":"21.0.5+11","downloadUrl":"https://example.com/bellsoft-jdk21.0.5+11-windows-aarch64.zip"}]`))
+	}))
+	defer server.Close()
+	t.Setenv(utils.JenvyHomeEnv, t.TempDir())
+	if err := utils.SaveConfig(&utils.Config{LibericaAPIMirror: server.URL}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := liberica.GetLibericaJDKs(); err != nil {
+		t.Fatalf("GetLibericaJDKs error: %v", err)
+	}
+	if bitness != "64" || arch != "arm" {
+		t.Errorf("bitness = %q, arch = %q; want 64 and arm", bitness, arch)
+	}
+}
+
+// TestTargetOS verifica che --os scelga sistema e formato richiesti ai provider e marchi l'installazione
+func TestTargetOS(t *testing.T) {
+	defer utils.SetTargetOS("")
+
+	if _, err := utils.ParseOS("solaris"); err == nil {
+		t.Error("--os accepts only windows, linux, mac or alpine")
+	}
+	if parsed, _ := utils.ParseOS("darwin"); parsed != utils.OSMac {
+		t.Errorf("ParseOS(darwin) = %q, want mac", parsed)
+	}
+	wantExtension := ".tar.gz"
+	if utils.HostOS() == utils.OSWindows {
+		wantExtension = ".zip"
+	}
+	if utils.TargetOS() != utils.HostOS() || utils.TargetArchiveExtension() != wantExtension || utils.OSSuffix() != "" {
+		t.Errorf("Without --os the archives for this system should be used, got %s%s", utils.TargetOS(), utils.TargetArchiveExtension())
+	}
+	if utils.IsForeignOS(utils.HostOS()) || utils.CheckTargetOS("private") != nil {
+		t.Error("--os naming this system should not be treated as foreign")
+	}
+	if utils.HostOS() == utils.OSAlpine {
+		t.Skip("Alpine is not a foreign system on this host")
+	}
+
+	utils.SetTargetOS(utils.OSAlpine)
+	if utils.CheckTargetOS("graalvm") == nil || utils.CheckTargetOS("private") == nil {
+		t.Error("GraalVM and private repositories do not publish Alpine builds")
+	}
+	if utils.CheckTargetOS("liberica") != nil || utils.OSSuffix() != "-alpine" {
+		t.Errorf("Liberica should publish Alpine builds, suffix = %q", utils.OSSuffix())
+	}
+
+	var osName, packageType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		osName, packageType = r.URL.Query().Get("os"), r.URL.Query().Get("package-type")
+		w.Write([]byte(`[{"version":"21.0.5+11","downloadUrl":"https://example.com/bellsoft-jdk21.0.5+11-linux-x64-musl.tar.gz"}]`))
+	}))
+	defer server.Close()
+	t.Setenv(utils.JenvyHomeEnv, t.TempDir())
+	if err := utils.SaveConfig(&utils.Config{LibericaAPIMirror: server.URL}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := liberica.GetLibericaJDKs(); err != nil {
+		t.Fatalf("GetLibericaJDKs error: %v", err)
+	}
+	if osName != "linux-musl" || packageType != "tar.gz" {
+		t.Errorf("os = %q, package-type = %q; want linux-musl and tar.gz", osName, packageType)
+	}
+
+	installDir := t.TempDir()
+	if _, foreign := utils.IsForeignInstall(installDir); foreign {
+		t.Error("A directory without metadata should be an installation for this system")
+	}
+	if err := utils.SaveInstallMetadata(installDir, &utils.InstallMetadata{Provider: "liberica", OS: utils.OSAlpine}); err != nil {
+		t.Fatal(err)
+	}
+	if target, foreign := utils.IsForeignInstall(installDir); !foreign || target != utils.OSAlpine {
+		t.Errorf("IsForeignInstall = %q, %v; want alpine, true", target, foreign)
+	}
+}
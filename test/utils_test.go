@@ -1,11 +1,21 @@
 package test
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
 
+	"jenvy/internal/cmd"
 	"jenvy/internal/utils"
 )
 
@@ -97,7 +107,7 @@ func TestIsValidJDKDirectory(t *testing.T) {
 				os.MkdirAll(filepath.Join(jdkDir, "bin"), 0755)
 				os.MkdirAll(filepath.Join(jdkDir, "lib"), 0755)
 				// Crea java.exe
-				javaExe := filepath.Join(jdkDir, "bin", "java.exe")
+				javaExe := filepath.Join(jdkDir, "bin", utils.JavaExecutableName())
 				file, _ := os.Create(javaExe)
 				file.Close()
 				return jdkDir
@@ -118,7 +128,7 @@ func TestIsValidJDKDirectory(t *testing.T) {
 			setup: func(baseDir string) string {
 				jdkDir := filepath.Join(baseDir, "no-lib")
 				os.MkdirAll(filepath.Join(jdkDir, "bin"), 0755)
-				javaExe := filepath.Join(jdkDir, "bin", "java.exe")
+				javaExe := filepath.Join(jdkDir, "bin", utils.JavaExecutableName())
 				file, _ := os.Create(javaExe)
 				file.Close()
 				return jdkDir
@@ -195,7 +205,7 @@ func BenchmarkIsValidJDKDirectory(b *testing.B) {
 	jdkDir := filepath.Join(tempDir, "benchmark-jdk")
 	os.MkdirAll(filepath.Join(jdkDir, "bin"), 0755)
 	os.MkdirAll(filepath.Join(jdkDir, "lib"), 0755)
-	javaExe := filepath.Join(jdkDir, "bin", "java.exe")
+	javaExe := filepath.Join(jdkDir, "bin", utils.JavaExecutableName())
 	file, _ := os.Create(javaExe)
 	file.Close()
 
@@ -219,3 +229,1478 @@ func TestFindSingleJDKInstallation(t *testing.T) {
 		t.Error("FindSingleJDKInstallation('999') should return error for non-existent version")
 	}
 }
+
+// TestInstallNamingScheme verifica formattazione e parsing dei nomi delle directory di installazione
+func TestInstallNamingScheme(t *testing.T) {
+	tests := []struct {
+		name     string
+		scheme   string
+		provider string
+		version  string
+		expected string
+	}{
+		{"Default scheme", "", "adoptium", "21.0.1", "JDK-21.0.1"},
+		{"Provider scheme", utils.ProviderNamingScheme, "Adoptium", "17.0.9", "adoptium-jdk-17.0.9"},
+		{"Custom scheme", "java-{version}-{provider}", "zulu", "11", "java-11-zulu"},
+		{"Provider missing falls back", utils.ProviderNamingScheme, "", "21", "JDK-21"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dirName := utils.FormatInstallDirName(tt.scheme, tt.provider, tt.version)
+			if dirName != tt.expected {
+				t.Fatalf("FormatInstallDirName() = %q, want %q", dirName, tt.expected)
+			}
+
+			version, _, ok := utils.ParseInstallDirName(dirName, tt.scheme)
+			if !ok || version != tt.version {
+				t.Errorf("ParseInstallDirName(%q) = %q, %v; want %q", dirName, version, ok, tt.version)
+			}
+		})
+	}
+
+	// Le directory create con lo schema predefinito restano riconoscibili dopo un cambio di schema
+	if version, _, ok := utils.ParseInstallDirName("JDK-21.0.1", utils.ProviderNamingScheme); !ok || version != "21.0.1" {
+		t.Errorf("Legacy directory not recognized: %q, %v", version, ok)
+	}
+}
+
+// TestValidateNamingScheme verifica la validazione degli schemi di denominazione
+func TestValidateNamingScheme(t *testing.T) {
+	tests := []struct {
+		scheme string
+		valid  bool
+	}{
+		{"JDK-{version}", true},
+		{"{provider}-jdk-{version}", true},
+		{"jdk-{provider}", false},
+		{"{vendor}-{version}", false},
+		{"../{version}", false},
+		{"jdk/{version}", false},
+	}
+
+	for _, tt := range tests {
+		err := utils.ValidateNamingScheme(tt.scheme)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateNamingScheme(%q) error = %v, want valid=%v", tt.scheme, err, tt.valid)
+		}
+	}
+}
+
+// TestResolveJDKHome verifica la risoluzione della root JDK per installazioni non appiattite
+func TestResolveJDKHome(t *testing.T) {
+	createJDK := func(dir string) {
+		os.MkdirAll(filepath.Join(dir, "bin"), 0755)
+		os.MkdirAll(filepath.Join(dir, "lib"), 0755)
+		file, _ := os.Create(filepath.Join(dir, "bin", utils.JavaExecutableName()))
+		file.Close()
+	}
+
+	t.Run("Flattened installation", func(t *testing.T) {
+		installDir := filepath.Join(t.TempDir(), "JDK-17.0.9")
+		createJDK(installDir)
+		home, ok := utils.ResolveJDKHome(installDir)
+		if !ok || home != installDir {
+			t.Errorf("ResolveJDKHome() = %q, %v; want %q, true", home, ok, installDir)
+		}
+	})
+
+	t.Run("Nested installation", func(t *testing.T) {
+		installDir := filepath.Join(t.TempDir(), "JDK-17.0.9")
+		nested := filepath.Join(installDir, "jdk-17.0.9+9")
+		createJDK(nested)
+		home, ok := utils.ResolveJDKHome(installDir)
+		if !ok || home != nested {
+			t.Errorf("ResolveJDKHome() = %q, %v; want %q, true", home, ok, nested)
+		}
+	})
+
+	t.Run("Ambiguous nested installation", func(t *testing.T) {
+		installDir := filepath.Join(t.TempDir(), "JDK-17.0.9")
+		createJDK(filepath.Join(installDir, "jdk-a"))
+		createJDK(filepath.Join(installDir, "jdk-b"))
+		if _, ok := utils.ResolveJDKHome(installDir); ok {
+			t.Error("Expected ambiguous nested layout to be rejected")
+		}
+	})
+}
+
+// TestRemoveCompletionBlock verifica la rimozione dei blocchi di completamento dai profili shell
+func TestRemoveCompletionBlock(t *testing.T) {
+	userContent := "export EDITOR=vim\nalias ll='ls -l'"
+	legacyEnd := "complete -F _jenvy_completion jenvy"
+
+	tests := []struct {
+		name    string
+		content string
+		removed bool
+	}{
+		{
+			name:    "Sentinel block",
+			content: userContent + "\n\n" + utils.WrapCompletionBlock("_jenvy_completion() {\n    :\n}\n"+legacyEnd),
+			removed: true,
+		},
+		{
+			name:    "Legacy block",
+			content: userContent + "\n\n# Jenvy completion\n_jenvy_completion() {\n    :\n}\n" + legacyEnd + "\n",
+			removed: true,
+		},
+		{
+			name:    "No block",
+			content: userContent,
+			removed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, removed := utils.RemoveCompletionBlock(tt.content, legacyEnd)
+			if removed != tt.removed {
+				t.Fatalf("RemoveCompletionBlock() removed = %v, want %v", removed, tt.removed)
+			}
+			if strings.TrimRight(result, "\n") != userContent {
+				t.Errorf("Unexpected remaining content: %q", result)
+			}
+		})
+	}
+}
+
+// TestUpdateCompletionBlock verifica installazione, aggiornamento e rilevamento dei blocchi già correnti
+func TestUpdateCompletionBlock(t *testing.T) {
+	legacyEnd := "complete -F _jenvy_completion jenvy"
+	script := "_jenvy_completion() {\n    :\n}\n" + legacyEnd
+
+	content, status := utils.UpdateCompletionBlock("export EDITOR=vim", script, legacyEnd)
+	if status != utils.CompletionInstalled || !strings.Contains(content, utils.CompletionBlockBegin) {
+		t.Fatalf("Expected fresh install, got status %q", status)
+	}
+
+	if _, status := utils.UpdateCompletionBlock(content, script, legacyEnd); status != utils.CompletionCurrent {
+		t.Errorf("Expected %q for identical block, got %q", utils.CompletionCurrent, status)
+	}
+
+	legacy := "export EDITOR=vim\n\n# Jenvy completion\n_jenvy_completion() {\n}\n" + legacyEnd + "\n"
+	updated, status := utils.UpdateCompletionBlock(legacy, script, legacyEnd)
+	if status != utils.CompletionUpdated {
+		t.Fatalf("Expected %q for legacy block, got %q", utils.CompletionUpdated, status)
+	}
+	if strings.Count(updated, legacyEnd) != 1 || strings.Contains(updated, "# Jenvy completion") {
+		t.Errorf("Legacy block not replaced cleanly: %q", updated)
+	}
+}
+
+// TestJavaExecutablePath verifica il nome del launcher java in base al sistema operativo
+func TestJavaExecutablePath(t *testing.T) {
+	jdkPath := filepath.Join("versions", "JDK-17")
+	expected := filepath.Join(jdkPath, "bin", "java")
+	if runtime.GOOS == "windows" {
+		expected += ".exe"
+	}
+
+	if result := utils.JavaExecutablePath(jdkPath); result != expected {
+		t.Errorf("JavaExecutablePath(%q) = %q, want %q", jdkPath, result, expected)
+	}
+}
+
+// TestForEachParallel verifica che ogni indice venga elaborato una sola volta mantenendo l'ordine dei risultati
+func TestForEachParallel(t *testing.T) {
+	for _, workers := range []int{0, 1, 3, 50} {
+		results := make([]int, 100)
+		utils.ForEachParallel(len(results), workers, func(i int) {
+			results[i] += i * 2
+		})
+		for i, value := range results {
+			if value != i*2 {
+				t.Fatalf("workers=%d: results[%d] = %d, want %d", workers, i, value, i*2)
+			}
+		}
+	}
+
+	// Nessun elemento: non deve bloccarsi
+	utils.ForEachParallel(0, 4, func(i int) { t.Error("fn should not be called") })
+}
+
+// TestParseSinceFilter verifica il parsing di date assolute e durate relative per --since
+func TestParseSinceFilter(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		input    string
+		expected time.Time
+		wantErr  bool
+	}{
+		{"2024-01-31", time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), false},
+		{"90d", now.AddDate(0, 0, -90), false},
+		{"2w", now.AddDate(0, 0, -14), false},
+		{"6m", now.AddDate(0, -6, 0), false},
+		{"1y", now.AddDate(-1, 0, 0), false},
+		{"48h", now.Add(-48 * time.Hour), false},
+		{"yesterday", time.Time{}, true},
+		{"", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := utils.ParseSinceFilter(tt.input, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSinceFilter(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && !result.Equal(tt.expected) {
+				t.Errorf("ParseSinceFilter(%q) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if !utils.ReleasedSince("2024-03-01T10:00:00Z", since) {
+		t.Error("Recent release should pass the filter")
+	}
+	if utils.ReleasedSince("2023-12-01", since) {
+		t.Error("Old release should be filtered out")
+	}
+	if utils.ReleasedSince("", since) {
+		t.Error("Release without date should be filtered out")
+	}
+}
+
+// TestFormatArchiveName verifica i nomi archivio personalizzati di --archive-name
+func TestFormatArchiveName(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		original string
+		expected string
+		wantErr  bool
+	}{
+		{"Placeholders with extension added", "temurin-{version}-{os}-{arch}", "OpenJDK17U-jdk_x64.zip", "temurin-17.0.9-windows-x64.zip", false},
+		{"Double extension preserved", "{provider}-{version}", "jdk.tar.gz", "adoptium-17.0.9.tar.gz", false},
+		{"Extension already present", "jdk-{version}.zip", "OpenJDK.zip", "jdk-17.0.9.zip", false},
+		{"Unknown placeholder", "jdk-{vendor}", "jdk.zip", "", true},
+		{"Path separator", "../{version}", "jdk.zip", "", true},
+		{"Empty pattern", "", "jdk.zip", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := utils.FormatArchiveName(tt.pattern, "Adoptium", "17.0.9", "windows", "x64", tt.original)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FormatArchiveName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if result != tt.expected {
+				t.Errorf("FormatArchiveName() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestInstallMetadataRoundTrip verifica salvataggio e lettura di .jenvy-meta.json
+func TestInstallMetadataRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := utils.LoadInstallMetadata(dir); !os.IsNotExist(err) {
+		t.Fatalf("Expected not-exist error for missing metadata, got %v", err)
+	}
+
+	meta := &utils.InstallMetadata{Provider: "adoptium", Version: "17.0.9", Checksum: "0260f3", ChecksumAlgorithm: "sha256", ImageType: "jdk", ManifestHash: "abc"}
+	if err := utils.SaveInstallMetadata(dir, meta); err != nil {
+		t.Fatalf("SaveInstallMetadata() error = %v", err)
+	}
+	loaded, err := utils.LoadInstallMetadata(dir)
+	if err != nil {
+		t.Fatalf("LoadInstallMetadata() error = %v", err)
+	}
+	if *loaded != *meta {
+		t.Errorf("LoadInstallMetadata() = %+v, want %+v", loaded, meta)
+	}
+}
+
+// TestComputeManifestHash verifica che l'hash rilevi modifiche ai file del JDK
+func TestComputeManifestHash(t *testing.T) {
+	dir := t.TempDir()
+	javaPath := filepath.Join(dir, "bin", "java")
+	os.MkdirAll(filepath.Dir(javaPath), 0755)
+	os.WriteFile(javaPath, []byte("original"), 0755)
+
+	base, err := utils.ComputeManifestHash(dir)
+	if err != nil {
+		t.Fatalf("ComputeManifestHash() error = %v", err)
+	}
+
+	// Metadati e archivi nella root non fanno parte del manifest
+	utils.SaveInstallMetadata(dir, &utils.InstallMetadata{ManifestHash: base})
+	os.WriteFile(filepath.Join(dir, "OpenJDK17.zip"), []byte("archive"), 0644)
+	if hash, _ := utils.ComputeManifestHash(dir); hash != base {
+		t.Error("Metadata file and root archives should not change the manifest hash")
+	}
+
+	os.WriteFile(javaPath, []byte("tampered"), 0755)
+	if hash, _ := utils.ComputeManifestHash(dir); hash == base {
+		t.Error("Modified file should change the manifest hash")
+	}
+}
+
+// TestExtractedFileMatches verifica il riconoscimento dei file già estratti per il resume
+func TestExtractedFileMatches(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "release")
+	modTime := time.Date(2024, 1, 16, 10, 30, 0, 0, time.UTC)
+
+	if utils.ExtractedFileMatches(path, 5, modTime) {
+		t.Error("Missing file should not match")
+	}
+
+	os.WriteFile(path, []byte("hello"), 0644)
+	if utils.ExtractedFileMatches(path, 5, modTime) {
+		t.Error("File without archive timestamp should not match")
+	}
+
+	os.Chtimes(path, modTime, modTime)
+	if !utils.ExtractedFileMatches(path, 5, modTime) {
+		t.Error("File with same size and timestamp should match")
+	}
+	if utils.ExtractedFileMatches(path, 10, modTime) {
+		t.Error("Truncated file should not match")
+	}
+}
+
+// TestStagingDir verifica la directory temporanea delle estrazioni, sullo stesso volume della destinazione
+func TestStagingDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(utils.JenvyHomeEnv, home)
+
+	dest := filepath.Join(home, "versions", "JDK-21.0.2")
+	staging, err := utils.StagingDir(dest)
+	if err != nil {
+		t.Fatalf("StagingDir failed: %v", err)
+	}
+	if filepath.Dir(staging) != filepath.Join(home, "tmp") || !strings.HasPrefix(filepath.Base(staging), "JDK-21.0.2-") {
+		t.Errorf("Staging for %s should be in %s/tmp, got %s", dest, home, staging)
+	}
+	if again, _ := utils.StagingDir(dest); again != staging {
+		t.Errorf("Staging should be stable across runs: %s vs %s", staging, again)
+	}
+	if other, _ := utils.StagingDir(filepath.Join(home, "versions", "JDK-17.0.9")); other == staging {
+		t.Error("Different destinations should not share a staging directory")
+	}
+
+	external := filepath.Join(t.TempDir(), "jdks", "JDK-21.0.2")
+	staging, _ = utils.StagingDir(external)
+	if filepath.Dir(staging) != filepath.Dir(external) {
+		t.Errorf("Staging for %s should be next to it, got %s", external, staging)
+	}
+}
+
+// TestFindJavaOnPath verifica l'elenco ordinato delle directory del PATH che contengono java
+func TestFindJavaOnPath(t *testing.T) {
+	root := t.TempDir()
+	external := filepath.Join(root, "external")
+	empty := filepath.Join(root, "empty")
+	managed := filepath.Join(root, "jdk", "bin")
+	for _, dir := range []string{external, empty, managed} {
+		os.MkdirAll(dir, 0755)
+	}
+	os.WriteFile(filepath.Join(external, utils.JavaExecutableName()), []byte{}, 0755)
+	os.WriteFile(filepath.Join(managed, utils.JavaExecutableName()), []byte{}, 0755)
+
+	pathValue := strings.Join([]string{empty, external, "", managed}, string(os.PathListSeparator))
+	entries := utils.FindJavaOnPath(pathValue)
+
+	if len(entries) != 2 {
+		t.Fatalf("FindJavaOnPath() returned %d entries, want 2", len(entries))
+	}
+	if entries[0].Dir != external || entries[0].Position != 2 {
+		t.Errorf("First entry = %+v, want %s at position 2", entries[0], external)
+	}
+	if entries[1].Dir != managed || entries[1].Position != 4 {
+		t.Errorf("Second entry = %+v, want %s at position 4", entries[1], managed)
+	}
+}
+
+// TestVerifyFileChecksum verifica SHA-256, SHA-512, rilevamento automatico e mismatch
+func TestVerifyFileChecksum(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jdk.zip")
+	if err := os.WriteFile(path, []byte("jenvy"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	sha256sum, err := utils.FileChecksum(path, "sha256")
+	if err != nil {
+		t.Fatalf("FileChecksum(sha256) failed: %v", err)
+	}
+	sha512sum, err := utils.FileChecksum(path, "SHA-512")
+	if err != nil {
+		t.Fatalf("FileChecksum(SHA-512) failed: %v", err)
+	}
+
+	if got := utils.DetectChecksumAlgorithm(sha256sum); got != "sha256" {
+		t.Errorf("DetectChecksumAlgorithm(sha256) = %q", got)
+	}
+	if got := utils.DetectChecksumAlgorithm(sha512sum); got != "sha512" {
+		t.Errorf("DetectChecksumAlgorithm(sha512) = %q", got)
+	}
+
+	for _, tc := range []struct{ algorithm, checksum string }{
+		{"sha256", sha256sum},
+		{"sha512", sha512sum},
+		{"", strings.ToUpper(sha512sum)},
+	} {
+		if err := utils.VerifyFileChecksum(path, tc.algorithm, tc.checksum); err != nil {
+			t.Errorf("VerifyFileChecksum(%q) failed: %v", tc.algorithm, err)
+		}
+	}
+
+	if err := utils.VerifyFileChecksum(path, "sha512", sha256sum); err == nil {
+		t.Error("Expected mismatch when verifying a SHA-256 checksum as SHA-512")
+	}
+	if _, err := utils.NewChecksumHash("md5"); err == nil {
+		t.Error("Expected md5 to be rejected")
+	}
+}
+
+// TestJenvyHome verifica che la directory Jenvy derivi dalla home risolta
+func TestJenvyHome(t *testing.T) {
+	home, err := utils.UserHomeDir()
+	if err != nil {
+		t.Fatalf("UserHomeDir failed: %v", err)
+	}
+
+	jenvyDir, err := utils.JenvyHome()
+	if err != nil {
+		t.Fatalf("JenvyHome failed: %v", err)
+	}
+	if jenvyDir != filepath.Join(home, ".jenvy") {
+		t.Errorf("JenvyHome() = %q, want %q", jenvyDir, filepath.Join(home, ".jenvy"))
+	}
+
+	versionsDir, err := utils.GetJenvyVersionsDirectory()
+	if err != nil {
+		t.Fatalf("GetJenvyVersionsDirectory failed: %v", err)
+	}
+	if versionsDir != filepath.Join(jenvyDir, "versions") {
+		t.Errorf("GetJenvyVersionsDirectory() = %q, want it under %q", versionsDir, jenvyDir)
+	}
+}
+
+// TestExpandWindowsEnv verifica l'espansione dei riferimenti %NOME% di un PATH dal registro
+func TestExpandWindowsEnv(t *testing.T) {
+	env := map[string]string{
+		"JAVA_HOME":  `C:\jdk-17`,
+		"SystemRoot": `C:\Windows`,
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`%JAVA_HOME%\bin;%SystemRoot%\system32`, `C:\jdk-17\bin;C:\Windows\system32`},
+		{`%UNKNOWN%\bin;%JAVA_HOME%\bin`, `%UNKNOWN%\bin;C:\jdk-17\bin`},
+		{`100%;%JAVA_HOME%`, `100%;C:\jdk-17`},
+		{`C:\tools;%%`, `C:\tools;%%`},
+		{`no references`, `no references`},
+	}
+
+	for _, tc := range tests {
+		if got := utils.ExpandWindowsEnv(tc.input, lookup); got != tc.expected {
+			t.Errorf("ExpandWindowsEnv(%q) = %q, want %q", tc.input, got, tc.expected)
+		}
+	}
+}
+
+// TestShellFromProcessName verifica il riconoscimento delle shell dal nome del processo
+func TestShellFromProcessName(t *testing.T) {
+	tests := map[string]string{
+		`C:\Program Files\Git\usr\bin\bash.exe`: "bash",
+		"pwsh.exe":                              "pwsh",
+		"PowerShell.EXE":                        "powershell",
+		"cmd.exe":                               "cmd",
+		"-zsh":                                  "zsh",
+		"/usr/bin/fish":                         "fish",
+		"explorer.exe":                          "",
+		"":                                      "",
+	}
+
+	for name, expected := range tests {
+		if got := utils.ShellFromProcessName(name); got != expected {
+			t.Errorf("ShellFromProcessName(%q) = %q, want %q", name, got, expected)
+		}
+	}
+}
+
+// TestSelectColumns verifica selezione, ordinamento e validazione dei campi di remote-list --fields
+func TestSelectColumns(t *testing.T) {
+	headers := []string{"Version", "OS", "Arch", "LTS", "Download"}
+	rows := [][]string{{"17.0.12+7", "windows", "x64", "YES", "https://example.com/17.zip"}}
+
+	projected, selected, err := utils.SelectColumns(rows, headers, []string{"lts", " Version"})
+	if err != nil {
+		t.Fatalf("SelectColumns() failed: %v", err)
+	}
+	if strings.Join(selected, ",") != "LTS,Version" {
+		t.Errorf("Headers = %v, want [LTS Version]", selected)
+	}
+	if strings.Join(projected[0], ",") != "YES,17.0.12+7" {
+		t.Errorf("Row = %v, want [YES 17.0.12+7]", projected[0])
+	}
+
+	if all, allHeaders, err := utils.SelectColumns(rows, headers, nil); err != nil || len(allHeaders) != 5 || len(all[0]) != 5 {
+		t.Errorf("Empty field list should keep every column, got %v (err: %v)", allHeaders, err)
+	}
+
+	_, _, err = utils.SelectColumns(rows, headers, []string{"version", "vendor"})
+	if err == nil || !strings.Contains(err.Error(), "version, os, arch, lts, download") {
+		t.Errorf("Expected unknown field error listing available fields, got %v", err)
+	}
+}
+
+// TestIsTerminal verifica che file regolari non vengano scambiati per un terminale
+func TestIsTerminal(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "log")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer f.Close()
+	if utils.IsTerminal(f) {
+		t.Error("A regular file must not be reported as a terminal")
+	}
+}
+
+// TestEffectiveScope verifica la precedenza project > user > system
+func TestEffectiveScope(t *testing.T) {
+	system := utils.ScopeValue{Scope: utils.ScopeSystem, Value: `C:\jdk-17`}
+	user := utils.ScopeValue{Scope: utils.ScopeUser, Value: `C:\jdk-21`}
+	project := utils.ScopeValue{Scope: utils.ScopeProject, Value: "11"}
+
+	if got, _ := utils.EffectiveScope(system, user, project); got.Scope != utils.ScopeProject {
+		t.Errorf("Project file should win, got %s", got.Scope)
+	}
+	if got, _ := utils.EffectiveScope(system, user); got.Scope != utils.ScopeUser {
+		t.Errorf("User scope should override system, got %s", got.Scope)
+	}
+	if got, _ := utils.EffectiveScope(system, utils.ScopeValue{Scope: utils.ScopeUser}); got.Scope != utils.ScopeSystem {
+		t.Errorf("Empty user value should be ignored, got %s", got.Scope)
+	}
+	if _, ok := utils.EffectiveScope(); ok {
+		t.Error("No values should report no effective scope")
+	}
+}
+
+// TestFindProjectVersion verifica la ricerca di .jenvy-version nelle directory superiori
+func TestFindProjectVersion(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "module", "src")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatalf("Failed to create directories: %v", err)
+	}
+	if _, ok := utils.FindProjectVersion(nested); ok {
+		t.Fatal("No project file should be found yet")
+	}
+
+	if err := os.WriteFile(filepath.Join(root, utils.ProjectVersionFile), []byte("# pinned\n\n17.0.12\n"), 0644); err != nil {
+		t.Fatalf("Failed to write project file: %v", err)
+	}
+	found, ok := utils.FindProjectVersion(nested)
+	if !ok || found.Value != "17.0.12" || found.Scope != utils.ScopeProject {
+		t.Errorf("FindProjectVersion() = %+v, %v; want 17.0.12 from project scope", found, ok)
+	}
+
+	path, err := utils.WriteProjectVersionFile(nested, "21")
+	if err != nil {
+		t.Fatalf("WriteProjectVersionFile() failed: %v", err)
+	}
+	if found, _ := utils.FindProjectVersion(nested); found.Value != "21" || found.Source != path {
+		t.Errorf("Closest project file should win, got %+v", found)
+	}
+}
+
+// TestFindJavaVersionFile verifica il ripiego su .java-version e la precedenza di .jenvy-version
+func TestFindJavaVersionFile(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "app")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatalf("Failed to create directories: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, utils.JavaVersionFile), []byte("11\n"), 0644); err != nil {
+		t.Fatalf("Failed to write .java-version: %v", err)
+	}
+	if found, ok := utils.FindProjectVersion(nested); !ok || found.Value != "11" {
+		t.Errorf("FindProjectVersion() = %+v, %v; want 11 from .java-version", found, ok)
+	}
+
+	path, err := utils.WriteProjectVersionFile(root, "17")
+	if err != nil {
+		t.Fatalf("WriteProjectVersionFile() failed: %v", err)
+	}
+	if found, _ := utils.FindProjectVersion(nested); found.Value != "17" || found.Source != path {
+		t.Errorf(".jenvy-version should win in the same directory, got %+v", found)
+	}
+}
+
+// TestSessionEnvStatements verifica i comandi stampati da 'jenvy use --session' per ogni shell
+func TestSessionEnvStatements(t *testing.T) {
+	home := `C:\Users\me\.jenvy\versions\JDK-21`
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{"cmd", []string{`set "JAVA_HOME=` + home + `"`, `set "PATH=` + home + `\bin;C:\Windows"`}},
+		{"pwsh", []string{"$env:JAVA_HOME = '" + home + "'", "$env:Path = '" + home + `\bin;' + $env:Path`}},
+		{"bash", []string{"export JAVA_HOME='" + home + "'", `export PATH='/c/Users/me/.jenvy/versions/JDK-21/bin':"$PATH"`}},
+	}
+	for _, tt := range tests {
+		got, err := utils.SessionEnvStatements(tt.shell, home, `C:\Windows`)
+		if err != nil {
+			t.Fatalf("SessionEnvStatements(%q) failed: %v", tt.shell, err)
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("SessionEnvStatements(%q) = %q, want %q", tt.shell, got, tt.want)
+		}
+	}
+	if _, err := utils.SessionEnvStatements("tcsh", home, ""); err == nil {
+		t.Error("Unsupported shells should be rejected")
+	}
+}
+
+// TestJavaPathOrigin verifica il riconoscimento delle installazioni Java esterne usato da 'jenvy doctor'
+func TestJavaPathOrigin(t *testing.T) {
+	tests := map[string]string{
+		`C:\Program Files\Common Files\Oracle\Java\javapath`:         "Oracle installer",
+		`C:\Users\me\scoop\apps\temurin17-jdk\current\bin`:           "Scoop",
+		`C:\ProgramData\chocolatey\bin`:                              "Chocolatey",
+		`C:\Program Files\Eclipse Adoptium\jdk-17.0.9.9-hotspot\bin`: "MSI installer",
+		`C:\Users\me\.jenvy\versions\JDK-21\bin`:                     "",
+	}
+	for dir, want := range tests {
+		if got := utils.JavaPathOrigin(dir); got != want {
+			t.Errorf("JavaPathOrigin(%q) = %q, want %q", dir, got, want)
+		}
+	}
+}
+
+// TestCompareJavaVersions verifica l'ordinamento delle versioni usato da 'jenvy upgrade'
+func TestCompareJavaVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"17.0.12+7", "17.0.13+11", -1},
+		{"17.0.12+9", "17.0.12+7", 1},
+		{"21.0.5", "21.0.5", 0},
+		{"1.8.0_452-b09", "8u442", 1},
+		{"11.0.25+9", "17.0.1+12", -1},
+	}
+	for _, tt := range tests {
+		if got := utils.CompareJavaVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareJavaVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// TestDetectBuildRequirement verifica la lettura della versione Java da pom.xml e build.gradle
+func TestDetectBuildRequirement(t *testing.T) {
+	tests := []struct {
+		name, file, content string
+		want                int
+	}{
+		{"pom release", "pom.xml", "<properties><maven.compiler.source>11</maven.compiler.source><maven.compiler.release>17</maven.compiler.release></properties>", 17},
+		{"pom legacy", "pom.xml", "<properties><maven.compiler.target>1.8</maven.compiler.target></properties>", 8},
+		{"pom property reference", "pom.xml", "<properties><java.version>21</java.version></properties><configuration><release>${java.version}</release></configuration>", 21},
+		{"gradle toolchain", "build.gradle.kts", "java { toolchain { languageVersion.set(JavaLanguageVersion.of(21)) } }", 21},
+		{"gradle enum", "build.gradle", "sourceCompatibility = JavaVersion.VERSION_1_8", 8},
+		{"gradle string", "build.gradle", "sourceCompatibility = '17'", 17},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, tt.file), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", tt.file, err)
+			}
+			req, ok := utils.DetectBuildRequirement(dir)
+			if !ok || req.Major != tt.want {
+				t.Errorf("DetectBuildRequirement() = %+v, %v; want major %d", req, ok, tt.want)
+			}
+		})
+	}
+
+	if _, ok := utils.DetectBuildRequirement(t.TempDir()); ok {
+		t.Error("A directory without build files should report no requirement")
+	}
+}
+
+// TestStripInsecureFlag verifica che l'opzione globale venga rimossa prima del dispatch
+func TestStripInsecureFlag(t *testing.T) {
+	args, found := utils.StripInsecureFlag([]string{"jenvy", "download", "17", "--insecure-skip-verify", "--yes"})
+	if !found || strings.Join(args, " ") != "jenvy download 17 --yes" {
+		t.Errorf("StripInsecureFlag() = %v, %v", args, found)
+	}
+	if _, found := utils.StripInsecureFlag([]string{"jenvy", "list"}); found {
+		t.Error("Flag should not be reported when absent")
+	}
+	if _, found := utils.StripInsecureFlag([]string{"jenvy", "rl", "--no-verify-tls"}); !found {
+		t.Error("--no-verify-tls alias should be recognized")
+	}
+}
+
+// TestExitCode verifica la conversione degli errori dei comandi in codici di uscita
+func TestExitCode(t *testing.T) {
+	if code := utils.ExitCode(nil); code != utils.ExitOK {
+		t.Errorf("ExitCode(nil) = %d, want %d", code, utils.ExitOK)
+	}
+	if code := utils.ExitCode(errors.New("boom")); code != utils.ExitGeneric {
+		t.Errorf("Plain error should map to %d, got %d", utils.ExitGeneric, code)
+	}
+	wrapped := fmt.Errorf("download: %w", utils.ExitWith(utils.ExitNetwork, errors.New("timeout")))
+	if code := utils.ExitCode(wrapped); code != utils.ExitNetwork {
+		t.Errorf("Wrapped CommandError should map to %d, got %d", utils.ExitNetwork, code)
+	}
+	if code := utils.ExitCode(utils.ExitWith(utils.ExitPermission, nil)); code != utils.ExitPermission {
+		t.Errorf("CommandError without cause should map to %d, got %d", utils.ExitPermission, code)
+	}
+}
+
+// TestLoadJDKManifest verifica lo schema del file usato da 'download --manifest'
+func TestLoadJDKManifest(t *testing.T) {
+	dir := t.TempDir()
+	sha := strings.Repeat("ab", 32)
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	valid := write("valid.json", `[{"provider": "Adoptium", "version": "21.0.5+11", "sha256": "`+strings.ToUpper(sha)+`"}]`)
+	entries, err := utils.LoadJDKManifest(valid)
+	if err != nil {
+		t.Fatalf("LoadJDKManifest() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Provider != "adoptium" || entries[0].SHA256 != sha {
+		t.Errorf("LoadJDKManifest() = %+v, want normalized provider and checksum", entries)
+	}
+
+	invalid := map[string]string{
+		"empty.json":      `[]`,
+		"no-version.json": `[{"provider": "azul", "sha256": "` + sha + `"}]`,
+		"short-sha.json":  `[{"provider": "azul", "version": "17.0.13", "sha256": "abc"}]`,
+		"not-hex.json":    `[{"provider": "azul", "version": "17.0.13", "sha256": "` + strings.Repeat("zz", 32) + `"}]`,
+		"duplicate.json":  `[{"provider": "azul", "version": "17.0.13", "sha256": "` + sha + `"}, {"provider": "azul", "version": "17.0.13", "sha256": "` + sha + `"}]`,
+	}
+	for name, content := range invalid {
+		if _, err := utils.LoadJDKManifest(write(name, content)); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+}
+
+// TestVersionMatchesPin verifica il confronto tra versione fissata e versione risolta
+func TestVersionMatchesPin(t *testing.T) {
+	tests := []struct {
+		pinned, resolved string
+		want             bool
+	}{
+		{"21.0.5+11", "21.0.5+11", true},
+		{"21.0.5+11", "21.0.5+12", false},
+		{"21.0.5", "21.0.5+11", true},
+		{"21.0.5", "21.0.6+7", false},
+	}
+	for _, tt := range tests {
+		if got := utils.VersionMatchesPin(tt.pinned, tt.resolved); got != tt.want {
+			t.Errorf("VersionMatchesPin(%q, %q) = %v, want %v", tt.pinned, tt.resolved, got, tt.want)
+		}
+	}
+}
+
+// TestReleaseVersionMatches verifica il matching sulle versioni già scomposte dai provider
+func TestReleaseVersionMatches(t *testing.T) {
+	tests := []struct {
+		release, target string
+		want            bool
+	}{
+		{"21.0.5+11", "21.0.5", true},
+		{"21.0.5+11", "21.0.4", false},
+		{"17.0.13+11", "21.0.5", false},
+		{"1.8.0_452-b09", "8.0.452", true},
+	}
+	for _, tt := range tests {
+		major, minor, patch := utils.ParseVersionNumber(tt.target)
+		if got := utils.NewReleaseVersion(tt.release).Matches(major, minor, patch); got != tt.want {
+			t.Errorf("NewReleaseVersion(%q).Matches(%q) = %v, want %v", tt.release, tt.target, got, tt.want)
+		}
+	}
+
+	// Con minor e patch a -1 basta la major
+	if !utils.NewReleaseVersion("17.0.13").Matches(17, -1, -1) {
+		t.Error("Matches(17, -1, -1) should accept any 17.x.y release")
+	}
+}
+
+// TestNormalizeVersionInput verifica la validazione delle versioni digitate dall'utente
+func TestNormalizeVersionInput(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"17", "17", false},
+		{" 21.0.5+11 ", "21.0.5+11", false},
+		{"jdk17", "17", false},
+		{"JDK-17.0.8+7", "17.0.8+7", false},
+		{"1.8", "8", false},
+		{"1.8.0", "8", false},
+		{"1.8.0_452-b09", "1.8.0_452-b09", false},
+		{"8u352", "8u352", false},
+		{"17.", "", true},
+		{"jdk", "", true},
+		{"abc", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		got, err := utils.NormalizeVersionInput(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("NormalizeVersionInput(%q) = %q, %v; want %q (error: %v)", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// TestJenvyHomeOverride verifica che JENVY_HOME sposti configurazione e versioni
+func TestJenvyHomeOverride(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(utils.JenvyHomeEnv, dir)
+
+	home, err := utils.JenvyHome()
+	if err != nil || home != dir {
+		t.Fatalf("JenvyHome() = %q, %v; want %q", home, err, dir)
+	}
+	versionsDir, err := utils.GetJenvyVersionsDirectory()
+	if err != nil || versionsDir != filepath.Join(dir, "versions") {
+		t.Errorf("GetJenvyVersionsDirectory() = %q, %v; want it under %q", versionsDir, err, dir)
+	}
+	if utils.ConfigPath() != filepath.Join(dir, "config.json") {
+		t.Errorf("ConfigPath() = %q, want it under %q", utils.ConfigPath(), dir)
+	}
+}
+
+// TestApplyNetworkFlags verifica il parsing delle opzioni globali --timeout e --retries
+func TestApplyNetworkFlags(t *testing.T) {
+	t.Setenv(utils.JenvyHomeEnv, t.TempDir())
+	t.Setenv(utils.TimeoutEnv, "")
+	t.Setenv(utils.RetriesEnv, "4")
+	t.Cleanup(func() { utils.ApplyNetworkFlags([]string{"jenvy", "--retries=0", "--timeout=2m"}) })
+
+	args, err := utils.ApplyNetworkFlags([]string{"jenvy", "rl", "--timeout", "90", "--all"})
+	if err != nil {
+		t.Fatalf("ApplyNetworkFlags() error: %v", err)
+	}
+	if strings.Join(args, " ") != "jenvy rl --all" {
+		t.Errorf("Network flags should be stripped, got %v", args)
+	}
+	if utils.NetworkTimeout() != 90*time.Second || utils.NetworkRetries() != 4 {
+		t.Errorf("Got timeout %s, retries %d; want 90s from the flag and 4 from %s", utils.NetworkTimeout(), utils.NetworkRetries(), utils.RetriesEnv)
+	}
+
+	if _, err := utils.ApplyNetworkFlags([]string{"jenvy", "dl", "17", "--retries=1", "--timeout=2m"}); err != nil {
+		t.Fatalf("ApplyNetworkFlags() error: %v", err)
+	}
+	if utils.NetworkTimeout() != 2*time.Minute || utils.NetworkRetries() != 1 {
+		t.Errorf("Flags should override the environment, got %s, %d", utils.NetworkTimeout(), utils.NetworkRetries())
+	}
+
+	for _, bad := range [][]string{{"jenvy", "rl", "--timeout=0"}, {"jenvy", "rl", "--retries=-1"}, {"jenvy", "rl", "--timeout"}} {
+		if _, err := utils.ApplyNetworkFlags(bad); err == nil {
+			t.Errorf("ApplyNetworkFlags(%v) should fail", bad)
+		}
+	}
+}
+
+// TestVersionRequestBuild verifica la scelta esatta tra due build della stessa patch
+func TestVersionRequestBuild(t *testing.T) {
+	releases := []utils.ReleaseVersion{
+		utils.NewReleaseVersion("17.0.9+9"),
+		utils.NewReleaseVersion("17.0.9+11"),
+	}
+	selected := func(version string) []int {
+		var builds []int
+		target := utils.ParseVersionRequest(version)
+		for _, release := range releases {
+			if release.Satisfies(target) {
+				builds = append(builds, release.Build)
+			}
+		}
+		return builds
+	}
+
+	if got := selected("17.0.9+11"); len(got) != 1 || got[0] != 11 {
+		t.Errorf("17.0.9+11 should select only build 11, got %v", got)
+	}
+	if got := selected("17.0.9+9"); len(got) != 1 || got[0] != 9 {
+		t.Errorf("17.0.9+9 should select only build 9, got %v", got)
+	}
+	if got := selected("17.0.9"); len(got) != 2 {
+		t.Errorf("17.0.9 without a build should match both respins, got %v", got)
+	}
+	if got := selected("17.0.9+10"); len(got) != 0 {
+		t.Errorf("An unpublished build should match nothing, got %v", got)
+	}
+}
+
+// TestVersionKey verifica la chiave usata per riconoscere la stessa versione tra provider
+func TestVersionKey(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+		ok      bool
+	}{
+		{"17.0.9+9", "17.0.9", true},
+		{"17.0.9", "17.0.9", true},
+		{"1.8.0_452-b09", "8.0.452", true},
+		{"8u452", "8.0.452", true},
+		{"latest", "", false},
+	}
+	for _, tt := range tests {
+		if got, ok := utils.VersionKey(tt.version); got != tt.want || ok != tt.ok {
+			t.Errorf("VersionKey(%q) = %q, %v; want %q, %v", tt.version, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// TestJDKHomeVersion verifica le fonti della versione usata da 'current --version-only'
+func TestJDKHomeVersion(t *testing.T) {
+	dir := t.TempDir()
+
+	withRelease := filepath.Join(dir, "JDK-17.0.9+9")
+	os.MkdirAll(withRelease, 0755)
+	os.WriteFile(filepath.Join(withRelease, "release"), []byte("IMPLEMENTOR=\"Eclipse Adoptium\"\nJAVA_VERSION=\"17.0.9\"\n"), 0644)
+	if version, ok := utils.JDKHomeVersion(withRelease); !ok || version != "17.0.9" {
+		t.Errorf("release file: got %q, %v; want 17.0.9", version, ok)
+	}
+
+	fromName := filepath.Join(dir, "adoptium-jdk-21.0.5+11")
+	os.MkdirAll(fromName, 0755)
+	if version, ok := utils.JDKHomeVersion(fromName); !ok || version != "21.0.5" {
+		t.Errorf("directory name: got %q, %v; want 21.0.5", version, ok)
+	}
+
+	if _, ok := utils.JDKHomeVersion(filepath.Join(dir, "custom")); ok {
+		t.Error("A directory without release file, metadata or versioned name should not report a version")
+	}
+}
+
+// TestParseArch verifica la normalizzazione di --arch e la compatibilità con il sistema nativo
+func TestParseArch(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"x64", utils.ArchX64, false},
+		{"AMD64", utils.ArchX64, false},
+		{"x86", utils.ArchX32, false},
+		{"386", utils.ArchX32, false},
+		{"arm64", utils.ArchAArch64, false},
+		{"sparc", "", true},
+	}
+	for _, tt := range tests {
+		got, err := utils.ParseArch(tt.value)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseArch(%q) = %q, %v; want %q, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	if !utils.ArchRunsOn(utils.ArchX32, utils.ArchX64) {
+		t.Error("a x32 JDK should run on x64 Windows")
+	}
+	if utils.ArchRunsOn(utils.ArchX64, utils.ArchX32) {
+		t.Error("a x64 JDK should not run on x32 Windows")
+	}
+}
+
+// TestDetectNativeImage verifica il riconoscimento dei layout GraalVM (native-image incluso o via gu)
+func TestDetectNativeImage(t *testing.T) {
+	newHome := func(release string, launchers ...string) string {
+		home := t.TempDir()
+		os.MkdirAll(filepath.Join(home, "bin"), 0755)
+		os.WriteFile(filepath.Join(home, "release"), []byte(release), 0644)
+		for _, launcher := range launchers {
+			os.WriteFile(filepath.Join(home, "bin", launcher), []byte("@echo off\n"), 0644)
+		}
+		return home
+	}
+
+	bundled := utils.DetectNativeImage(newHome("JAVA_VERSION=\"21.0.2\"\nGRAALVM_VERSION=\"23.1.2\"\n", "native-image.cmd"))
+	if !bundled.GraalVM || !bundled.Bundled {
+		t.Errorf("GraalVM for JDK 21: got %+v, want GraalVM with bundled native-image", bundled)
+	}
+	legacy := utils.DetectNativeImage(newHome("JAVA_VERSION=\"17.0.8\"\nGRAALVM_VERSION=\"22.3.3\"\n", "gu.cmd"))
+	if !legacy.GraalVM || legacy.Bundled || !legacy.HasGu {
+		t.Errorf("GraalVM CE 22.3: got %+v, want gu without native-image", legacy)
+	}
+	if plain := utils.DetectNativeImage(newHome("JAVA_VERSION=\"21.0.2\"\n")); plain.GraalVM {
+		t.Errorf("Temurin 21: got %+v, want not GraalVM", plain)
+	}
+
+	cfg := &utils.Config{}
+	if err := utils.SetConfigValue(cfg, "graalvm-edition", "enterprise"); err == nil {
+		t.Error("Expected error for unknown GraalVM edition")
+	}
+	if err := utils.SetConfigValue(cfg, "graalvm-edition", "Oracle"); err != nil || cfg.GraalVMEdition != utils.GraalVMEditionOracle {
+		t.Errorf("SetConfigValue(Oracle) = %v, GraalVMEdition = %q", err, cfg.GraalVMEdition)
+	}
+}
+
+// TestWriteRecords verifica l'output JSON e CSV di 'list' e 'remote-list' (--output)
+func TestWriteRecords(t *testing.T) {
+	headers := []string{"Version", "Install Date"}
+	rows := [][]string{{"21.0.5+11", "2025-01-31 10:00"}}
+
+	var jsonOut bytes.Buffer
+	if err := utils.WriteRecords(&jsonOut, utils.OutputJSON, headers, rows); err != nil {
+		t.Fatalf("WriteRecords(json) failed: %v", err)
+	}
+	var records []map[string]string
+	if err := json.Unmarshal(jsonOut.Bytes(), &records); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", jsonOut.String(), err)
+	}
+	if len(records) != 1 || records[0]["version"] != "21.0.5+11" || records[0]["install_date"] != "2025-01-31 10:00" {
+		t.Errorf("Unexpected JSON records: %v", records)
+	}
+
+	var empty bytes.Buffer
+	utils.WriteRecords(&empty, utils.OutputJSON, headers, nil)
+	if strings.TrimSpace(empty.String()) != "[]" {
+		t.Errorf("No rows should produce an empty array, got %q", empty.String())
+	}
+
+	var csvOut bytes.Buffer
+	if err := utils.WriteRecords(&csvOut, utils.OutputCSV, headers, rows); err != nil {
+		t.Fatalf("WriteRecords(csv) failed: %v", err)
+	}
+	if want := "version,install_date\n21.0.5+11,2025-01-31 10:00\n"; csvOut.String() != want {
+		t.Errorf("CSV = %q, want %q", csvOut.String(), want)
+	}
+
+	if _, err := utils.ParseOutputFormat("xml"); err == nil {
+		t.Error("ParseOutputFormat should reject unknown formats")
+	}
+}
+
+// TestProxySettings verifica la scelta del proxy da configurazione, credenziali e no-proxy
+func TestProxySettings(t *testing.T) {
+	cfg := &utils.Config{Proxy: "proxy.corp:8080", NoProxy: "localhost,.corp.local,10.0.0.0/8", ProxyUser: `CORP\jdoe`, ProxyPassword: "s3cret"}
+	settings := utils.LoadProxySettings(cfg)
+	if settings.Auth != utils.ProxyAuthBasic {
+		t.Errorf("Default proxy auth = %q, want basic", settings.Auth)
+	}
+
+	proxyURL, err := settings.ProxyFor(&url.URL{Scheme: "https", Host: "api.adoptium.net"})
+	if err != nil || proxyURL == nil {
+		t.Fatalf("ProxyFor() = %v, %v; want the configured proxy", proxyURL, err)
+	}
+	if proxyURL.Host != "proxy.corp:8080" || proxyURL.Scheme != "http" {
+		t.Errorf("Proxy = %s, want http://proxy.corp:8080", proxyURL.Redacted())
+	}
+	if password, _ := proxyURL.User.Password(); proxyURL.User.Username() != `CORP\jdoe` || password != "s3cret" {
+		t.Errorf("Proxy credentials not applied: %s", proxyURL.Redacted())
+	}
+
+	for _, host := range []string{"repo.corp.local", "localhost:8080", "10.1.2.3:443"} {
+		if proxyURL, _ := settings.ProxyFor(&url.URL{Scheme: "https", Host: host}); proxyURL != nil {
+			t.Errorf("%s should bypass the proxy, got %s", host, proxyURL.Redacted())
+		}
+	}
+
+	bypass := []struct {
+		host, noProxy string
+		want          bool
+	}{
+		{"example.com:443", "*", true},
+		{"corp.localhost", "corp.local", false},
+		{"nexus:8081", "nexus:8080", false},
+		{"nexus:8080", "nexus:8080", true},
+		{"192.168.1.5", "10.0.0.0/8", false},
+	}
+	for _, tc := range bypass {
+		if got := utils.ProxyBypassed(tc.host, tc.noProxy); got != tc.want {
+			t.Errorf("ProxyBypassed(%q, %q) = %v, want %v", tc.host, tc.noProxy, got, tc.want)
+		}
+	}
+
+	for _, bad := range []string{"ftp://proxy:21", "http://"} {
+		if _, err := utils.ParseProxyURL(bad); err == nil {
+			t.Errorf("ParseProxyURL(%q) should fail", bad)
+		}
+	}
+	if err := utils.ValidateProxyAuth("kerberos"); err == nil {
+		t.Error("ValidateProxyAuth should reject unsupported schemes")
+	}
+}
+
+// TestProxySettingsFromEnvironment verifica che no-proxy escluda anche il proxy preso da HTTPS_PROXY
+//
+// net/http legge le variabili del proxy una sola volta per processo: il controllo
+// viene eseguito in un processo di test separato, con HTTPS_PROXY già impostata.
+func TestProxySettingsFromEnvironment(t *testing.T) {
+	if os.Getenv("JENVY_TEST_ENV_PROXY") == "" {
+		child := exec.Command(os.Args[0], "-test.run=^TestProxySettingsFromEnvironment$")
+		child.Env = append(os.Environ(), "JENVY_TEST_ENV_PROXY=1", "HTTPS_PROXY=http://env-proxy.corp:3128", "https_proxy=", "NO_PROXY=", "no_proxy=")
+		if output, err := child.CombinedOutput(); err != nil {
+			t.Fatalf("%v\n%s", err, output)
+		}
+		return
+	}
+
+	settings := utils.LoadProxySettings(&utils.Config{NoProxy: ".corp.local", ProxyUser: "jdoe"})
+	proxyURL, err := settings.ProxyFor(&url.URL{Scheme: "https", Host: "api.adoptium.net"})
+	if err != nil || proxyURL == nil {
+		t.Fatalf("ProxyFor() = %v, %v; want the HTTPS_PROXY proxy", proxyURL, err)
+	}
+	if proxyURL.Host != "env-proxy.corp:3128" || proxyURL.User.Username() != "jdoe" {
+		t.Errorf("Proxy = %s, want http://jdoe@env-proxy.corp:3128", proxyURL.Redacted())
+	}
+	if proxyURL, _ := settings.ProxyFor(&url.URL{Scheme: "https", Host: "repo.corp.local"}); proxyURL != nil {
+		t.Errorf("no-proxy should apply to the environment proxy, got %s", proxyURL.Redacted())
+	}
+}
+
+// TestMirrorURL verifica la riscrittura degli URL dei provider verso i mirror configurati
+func TestMirrorURL(t *testing.T) {
+	t.Setenv(utils.JenvyHomeEnv, t.TempDir())
+	cfg := &utils.Config{}
+	for key, value := range map[string]string{
+		"adoptium-api-mirror":      "https://artifactory.corp/api/adoptium/",
+		"adoptium-download-mirror": "https://artifactory.corp/github/adoptium",
+	} {
+		if err := utils.SetConfigValue(cfg, key, value); err != nil {
+			t.Fatalf("SetConfigValue(%s) error: %v", key, err)
+		}
+	}
+	if err := utils.SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"https://api.adoptium.net/v3/info/available_releases":                    "https://artifactory.corp/api/adoptium/v3/info/available_releases",
+		"https://github.com/adoptium/temurin17-binaries/releases/download/x.zip": "https://artifactory.corp/github/adoptium/temurin17-binaries/releases/download/x.zip",
+		"https://github.com/adoptiumx/other.zip":                                 "https://github.com/adoptiumx/other.zip",
+		"https://api.azul.com/metadata/v1/zulu/packages":                         "https://api.azul.com/metadata/v1/zulu/packages",
+	}
+	for in, want := range tests {
+		if got := utils.MirrorURL(in); got != want {
+			t.Errorf("MirrorURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	if err := utils.SetConfigValue(cfg, "azul-api-mirror", "artifactory.corp"); err == nil {
+		t.Error("Mirror without scheme should be rejected")
+	}
+}
+
+// TestContentRangeStart verifica la lettura del primo byte di una risposta 206
+func TestContentRangeStart(t *testing.T) {
+	tests := []struct {
+		header string
+		start  int64
+		ok     bool
+	}{
+		{"bytes 100-199/200", 100, true},
+		{"bytes 0-99/*", 0, true},
+		{"bytes */200", 0, false},
+		{"items 100-199/200", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		if start, ok := utils.ContentRangeStart(tt.header); start != tt.start || ok != tt.ok {
+			t.Errorf("ContentRangeStart(%q) = %d, %v; want %d, %v", tt.header, start, ok, tt.start, tt.ok)
+		}
+	}
+}
+
+// TestMajorSupportEnd verifica la fine del supporto delle major LTS e non LTS
+func TestMajorSupportEnd(t *testing.T) {
+	date := func(year int, month time.Month) time.Time {
+		return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		major int
+		end   time.Time
+	}{
+		{9, date(2018, time.March)},
+		{19, date(2023, time.March)},
+		{22, date(2024, time.September)},
+		{21, date(2029, time.December)},
+		{8, date(2030, time.December)},
+	}
+	for _, tt := range tests {
+		end, ok := utils.MajorSupportEnd(tt.major)
+		if !ok || !end.Equal(tt.end) {
+			t.Errorf("MajorSupportEnd(%d) = %v, %v; want %v", tt.major, end, ok, tt.end)
+		}
+	}
+	if _, ok := utils.MajorSupportEnd(29); ok {
+		t.Error("MajorSupportEnd(29) should be unknown for a future LTS")
+	}
+
+	now := date(2026, time.October)
+	for major, eol := range map[int]bool{6: true, 17: false, 21: false, 24: true, 25: false, 26: true, 27: false, 29: false} {
+		if got := utils.IsEOLMajor(major, now); got != eol {
+			t.Errorf("IsEOLMajor(%d) = %v, want %v", major, got, eol)
+		}
+	}
+}
+
+// TestNormalizeArgs verifica la forma canonica degli argomenti dei sottocomandi
+func TestNormalizeArgs(t *testing.T) {
+	spec := utils.FlagSpec{Values: []string{"--provider"}, Bools: []string{"--all"}, Shorts: map[byte]string{'a': "--all"}}
+	tests := []struct {
+		args []string
+		want []string
+		help bool
+	}{
+		{[]string{"--provider", "azul", "17"}, []string{"17", "--provider=azul"}, false},
+		{[]string{"--provider=azul", "17", "--timeout", "90s"}, []string{"17", "--provider=azul", "--timeout=90s"}, false},
+		{[]string{"-ay"}, []string{"--all", "--yes"}, false},
+		{[]string{"17", "-h"}, []string{"17"}, true},
+		{[]string{"--help", "--all"}, []string{"--all"}, true},
+		{[]string{"--yes", "--", "17", "-"}, []string{"17", "-", "--yes"}, false},
+		{[]string{"-", "-y", "--", "--all", "-y"}, []string{"-", "--yes", "--", "--all", "-y"}, false},
+	}
+	for _, tt := range tests {
+		got, help, err := utils.NormalizeArgs(tt.args, spec)
+		if err != nil || help != tt.help || strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("NormalizeArgs(%q) = %q, %v, %v; want %q, %v", tt.args, got, help, err, tt.want, tt.help)
+		}
+	}
+
+	for _, args := range [][]string{{"17", "--provider"}, {"--bogus", "17"}, {"--bogus=1"}, {"-ax"}} {
+		if _, _, err := utils.NormalizeArgs(args, spec); err == nil {
+			t.Errorf("NormalizeArgs(%q) should fail", args)
+		}
+	}
+}
+
+// TestNormalizeCommandLineUnknownOption verifica che un'opzione sconosciuta fermi il comando
+func TestNormalizeCommandLineUnknownOption(t *testing.T) {
+	args, _, err := cmd.NormalizeCommandLine([]string{"jenvy", "download", "--bogus", "17"})
+	if err == nil || len(args) != 2 || args[1] != "download" {
+		t.Errorf("NormalizeCommandLine = %q, %v; want an error for download", args, err)
+	}
+	args, _, err = cmd.NormalizeCommandLine([]string{"jenvy", "--yes", "alias", "x", "--", "--bogus"})
+	if err != nil || strings.Join(args, " ") != "jenvy alias x --yes -- --bogus" {
+		t.Errorf("NormalizeCommandLine = %q, %v", args, err)
+	}
+}
+
+// TestSplitArgs verifica che gli argomenti dopo "--" restino posizionali fino al comando
+func TestSplitArgs(t *testing.T) {
+	positional, flags := utils.SplitArgs([]string{"set", "-", "--yes", "--", "-1", "--all"})
+	if strings.Join(positional, " ") != "set - -1 --all" || strings.Join(flags, " ") != "--yes" {
+		t.Errorf("SplitArgs = %q, %q", positional, flags)
+	}
+
+	// Le opzioni globali dopo "--" non vengono tolte
+	args, yes := utils.StripYesFlag([]string{"jenvy", "alias", "--", "--yes"})
+	if yes || strings.Join(args, " ") != "jenvy alias -- --yes" {
+		t.Errorf("StripYesFlag = %q, %v", args, yes)
+	}
+}
+
+// TestValidateAliasName verifica i nomi accettati da 'jenvy alias'
+func TestValidateAliasName(t *testing.T) {
+	for name, valid := range map[string]bool{
+		"default": true, "proj-x": true, "team_1.2": true,
+		"21": false, "jdk17": false, "list": false, "-x": false, "": false, "my alias": false,
+	} {
+		if err := utils.ValidateAliasName(name); (err == nil) != valid {
+			t.Errorf("ValidateAliasName(%q) = %v, want valid=%v", name, err, valid)
+		}
+	}
+}
+
+// TestProgressFormatting verifica unità adattive, tempo stimato e modalità di progresso
+func TestProgressFormatting(t *testing.T) {
+	for bytes, want := range map[int64]string{0: "0 B", 512: "512 B", 1536: "1.5 KB", 277 * 1024 * 1024: "277.0 MB", 3 << 30: "3.0 GB"} {
+		if got := utils.FormatBytes(bytes); got != want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", bytes, got, want)
+		}
+	}
+	for d, want := range map[time.Duration]string{42 * time.Second: "0:42", 125 * time.Second: "2:05", time.Hour + 5*time.Minute + 9*time.Second: "1:05:09"} {
+		if got := utils.FormatETA(d); got != want {
+			t.Errorf("FormatETA(%v) = %q, want %q", d, got, want)
+		}
+	}
+
+	if mode, err := utils.ResolveProgressMode(utils.ProgressLines); err != nil || mode != utils.ProgressLines {
+		t.Errorf("ResolveProgressMode(lines) = %q, %v", mode, err)
+	}
+	if _, err := utils.ResolveProgressMode("dots"); err == nil {
+		t.Error("ResolveProgressMode should reject an unknown mode")
+	}
+}
+
+// TestAcquireLock verifica il lock di ~/.jenvy tra esecuzioni concorrenti e l'attesa configurabile
+func TestAcquireLock(t *testing.T) {
+	t.Setenv(utils.JenvyHomeEnv, t.TempDir())
+	t.Setenv(utils.LockTimeoutEnv, "")
+	t.Cleanup(func() { utils.SetNoWait(false) })
+
+	lock, err := utils.AcquireLock("download 21")
+	if err != nil {
+		t.Fatalf("AcquireLock() error: %v", err)
+	}
+	utils.SetNoWait(true)
+	if _, err := utils.AcquireLock("remove 21"); err == nil || !strings.Contains(err.Error(), "download 21") {
+		t.Errorf("A held lock should fail at once with --no-wait and name its holder, got %v", err)
+	}
+	lock.Release()
+	again, err := utils.AcquireLock("remove 21")
+	if err != nil {
+		t.Fatalf("AcquireLock() after Release error: %v", err)
+	}
+	again.Release()
+	home, _ := utils.JenvyHome()
+	if holder, _ := os.ReadFile(filepath.Join(home, utils.LockFileName)); len(holder) != 0 {
+		t.Errorf("A released lock should not name a holder, got %q", holder)
+	}
+
+	utils.SetNoWait(false)
+	t.Setenv(utils.LockTimeoutEnv, "90s")
+	if timeout, err := utils.LockTimeout(); err != nil || timeout != 90*time.Second {
+		t.Errorf("LockTimeout() = %s, %v; want 90s from %s", timeout, err, utils.LockTimeoutEnv)
+	}
+	if timeout, err := utils.ParseLockTimeout("0"); err != nil || timeout != 0 {
+		t.Errorf("ParseLockTimeout(0) = %s, %v; want no wait", timeout, err)
+	}
+	if _, err := utils.ParseLockTimeout("soon"); err == nil {
+		t.Error("ParseLockTimeout should reject an invalid duration")
+	}
+	if args, found := utils.StripNoWaitFlag([]string{"jenvy", "dl", "21", "--no-wait"}); !found || strings.Join(args, " ") != "jenvy dl 21" {
+		t.Errorf("StripNoWaitFlag() = %v, %v", args, found)
+	}
+}
+
+// TestLongPath verifica la forma estesa \\?\ dei percorsi su Windows, invariati altrove
+func TestLongPath(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		if got := utils.LongPath("/tmp/jdk/legal"); got != "/tmp/jdk/legal" {
+			t.Errorf("LongPath() = %q, want the path unchanged", got)
+		}
+		return
+	}
+
+	for path, want := range map[string]string{
+		`C:\Users\me\.jenvy\versions\JDK-21\..\JDK-17\bin`: `\\?\C:\Users\me\.jenvy\versions\JDK-17\bin`,
+		`\\server\share\jdk`:      `\\?\UNC\server\share\jdk`,
+		`\\?\C:\already\extended`: `\\?\C:\already\extended`,
+	} {
+		if got := utils.LongPath(path); got != want {
+			t.Errorf("LongPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+// TestComputeManifestHashSymlinks verifica che i link simbolici contino per la destinazione, anche se non risolvibile
+func TestComputeManifestHashSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "lib", "server"), 0755)
+	os.WriteFile(filepath.Join(dir, "lib", "server", "libjvm.so"), []byte("jvm"), 0755)
+	if err := os.Symlink(filepath.Join("lib", "server"), filepath.Join(dir, "jvm")); err != nil {
+		t.Skipf("Symbolic links not supported: %v", err)
+	}
+
+	base, err := utils.ComputeManifestHash(dir)
+	if err != nil {
+		t.Fatalf("ComputeManifestHash() with a directory symlink error = %v", err)
+	}
+	os.Remove(filepath.Join(dir, "jvm"))
+	os.Symlink("missing", filepath.Join(dir, "jvm"))
+	if hash, err := utils.ComputeManifestHash(dir); err != nil || hash == base {
+		t.Errorf("A retargeted symlink should change the manifest hash (got %v)", err)
+	}
+}
+
+// TestArchiveGuard verifica i limiti di estrazione: voci, dimensione totale e rapporto di compressione
+func TestArchiveGuard(t *testing.T) {
+	for value, want := range map[string]int64{"512MB": 512 << 20, "8gb": 8 << 30, "1.5 KB": 1536, "4096": 4096} {
+		if got, err := utils.ParseByteSize(value); err != nil || got != want {
+			t.Errorf("ParseByteSize(%q) = %d, %v; want %d", value, got, err, want)
+		}
+	}
+	if _, err := utils.ParseByteSize("lots"); err == nil {
+		t.Error("ParseByteSize should reject an invalid size")
+	}
+
+	limits := utils.ArchiveExtractLimits(&utils.Config{ExtractMaxSize: "1GB", ExtractMaxFiles: 2})
+	if limits.MaxSize != 1<<30 || limits.MaxFiles != 2 || limits.MaxRatio != utils.DefaultExtractMaxRatio {
+		t.Errorf("ArchiveExtractLimits() = %+v, want configured size and files with the default ratio", limits)
+	}
+
+	guard := utils.NewArchiveGuard(limits)
+	if guard.Entry() != nil || guard.Entry() != nil {
+		t.Fatal("Entries within the limit should be accepted")
+	}
+	if err := guard.Entry(); !errors.Is(err, utils.ErrArchiveLimit) {
+		t.Errorf("A third entry should exceed extract-max-files, got %v", err)
+	}
+
+	guard = utils.NewArchiveGuard(limits)
+	guard.Compressed(1 << 20)
+	if _, err := io.Copy(guard, io.LimitReader(zeroReader{}, 100<<20)); err != nil {
+		t.Errorf("100 MB from 1 MB (100:1) should be accepted, got %v", err)
+	}
+	if _, err := io.Copy(guard, io.LimitReader(zeroReader{}, 2<<20)); !errors.Is(err, utils.ErrArchiveLimit) {
+		t.Errorf("A ratio above 100:1 should be rejected, got %v", err)
+	}
+
+	guard = utils.NewArchiveGuard(limits)
+	guard.Compressed(1 << 30)
+	if err := guard.Extracted(1<<30 + 1); !errors.Is(err, utils.ErrArchiveLimit) {
+		t.Errorf("More than extract-max-size should be rejected, got %v", err)
+	}
+}
+
+// zeroReader produce zeri all'infinito, come il contenuto di una zip bomb.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestParseJavaVersionOutput(t *testing.T) {
+	tests := map[string]string{
+		"openjdk version \"21.0.5\" 2024-10-15\nOpenJDK Runtime Environment Temurin-21.0.5+11 (build 21.0.5+11-LTS)": "21.0.5",
+		"java version \"1.8.0_432\"\nJava(TM) SE Runtime Environment (build 1.8.0_432-b06)":                          "1.8.0_432",
+		"Picked up JAVA_TOOL_OPTIONS: -Xmx1g\nopenjdk version \"17\" 2021-09-14":                                     "17",
+	}
+	for output, expected := range tests {
+		if version, _, ok := utils.ParseJavaVersionOutput(output); !ok || version != expected {
+			t.Errorf("ParseJavaVersionOutput(%q) = %q, %v; expected %q", output, version, ok, expected)
+		}
+	}
+	if _, _, ok := utils.ParseJavaVersionOutput("Error: could not find libjvm.so"); ok {
+		t.Error("Output without a version should not be parsed")
+	}
+}
+
+func TestJavaVersionMatches(t *testing.T) {
+	tests := []struct {
+		reported, expected string
+		match              bool
+	}{
+		{"17.0.9", "17.0.9+9", true},
+		{"17.0.9", "17", true},
+		{"17.0.8", "17.0.9+9", false},
+		{"21.0.5", "17.0.9+9", false},
+		{"1.8.0_432", "8u432", true},
+		{"1.8.0_432", "8.0.432+6", true},
+		{"1.8.0_432", "1.8.0_422-b05", false},
+		{"21", "21.0.0", true},
+		{"21.0.5", "latest", true},
+	}
+	for _, tt := range tests {
+		if got := utils.JavaVersionMatches(tt.reported, tt.expected); got != tt.match {
+			t.Errorf("JavaVersionMatches(%q, %q) = %v, expected %v", tt.reported, tt.expected, got, tt.match)
+		}
+	}
+}